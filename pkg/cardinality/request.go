@@ -168,6 +168,54 @@ func DecodeLabelValuesRequestFromValues(values url.Values) (*LabelValuesRequest,
 	return parsed, nil
 }
 
+type ActiveSeriesRequest struct {
+	Matchers []*labels.Matcher
+}
+
+// String returns a full representation of the request. The returned string can be
+// used to uniquely identify the request.
+func (r *ActiveSeriesRequest) String() string {
+	b := strings.Builder{}
+
+	// Add matchers.
+	for idx, matcher := range r.Matchers {
+		if idx > 0 {
+			b.WriteRune(stringValueSeparator)
+		}
+		b.WriteString(matcher.String())
+	}
+
+	return b.String()
+}
+
+// DecodeActiveSeriesRequest decodes the input http.Request into an ActiveSeriesRequest.
+// The input http.Request can either be a GET or POST with URL-encoded parameters.
+func DecodeActiveSeriesRequest(r *http.Request) (*ActiveSeriesRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return DecodeActiveSeriesRequestFromValues(r.Form)
+}
+
+// DecodeActiveSeriesRequestFromValues is like DecodeActiveSeriesRequest but takes url.Values in input.
+func DecodeActiveSeriesRequestFromValues(values url.Values) (*ActiveSeriesRequest, error) {
+	var (
+		parsed = &ActiveSeriesRequest{}
+		err    error
+	)
+
+	parsed.Matchers, err = extractSelector(values)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.Matchers) == 0 {
+		return nil, fmt.Errorf("'selector' param is required")
+	}
+
+	return parsed, nil
+}
+
 // extractSelector parses and gets selector query parameter containing a single matcher
 func extractSelector(values url.Values) (matchers []*labels.Matcher, err error) {
 	selectorParams := values["selector"]
@@ -133,3 +133,55 @@ func TestLabelValuesRequest_String(t *testing.T) {
 
 	assert.Equal(t, "foo\x01bar\x00first=\"1\"\x01second!=\"2\"\x00active\x00100", req.String())
 }
+
+func TestDecodeActiveSeriesRequest(t *testing.T) {
+	var (
+		params = url.Values{
+			"selector": []string{`{second!="2",first="1"}`},
+		}
+
+		expected = &ActiveSeriesRequest{
+			Matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchEqual, "first", "1"),
+				labels.MustNewMatcher(labels.MatchNotEqual, "second", "2"),
+			},
+		}
+	)
+
+	t.Run("DecodeActiveSeriesRequest() with GET request", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "http://localhost?"+params.Encode(), nil)
+		require.NoError(t, err)
+
+		actual, err := DecodeActiveSeriesRequest(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("DecodeActiveSeriesRequest() with POST request", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "http://localhost/", strings.NewReader(params.Encode()))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		actual, err := DecodeActiveSeriesRequest(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("DecodeActiveSeriesRequestFromValues() without selector", func(t *testing.T) {
+		_, err := DecodeActiveSeriesRequestFromValues(url.Values{})
+		require.EqualError(t, err, "'selector' param is required")
+	})
+}
+
+func TestActiveSeriesRequest_String(t *testing.T) {
+	req := &ActiveSeriesRequest{
+		Matchers: []*labels.Matcher{
+			labels.MustNewMatcher(labels.MatchEqual, "first", "1"),
+			labels.MustNewMatcher(labels.MatchNotEqual, "second", "2"),
+		},
+	}
+
+	assert.Equal(t, "first=\"1\"\x01second!=\"2\"", req.String())
+}
@@ -8,6 +8,7 @@ package compactor
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -28,6 +29,7 @@ import (
 	"github.com/thanos-io/objstore"
 	"go.uber.org/atomic"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/storage/sharding"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
@@ -368,7 +370,19 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 		c.metrics.compactionBlocksVerificationFailed.Inc()
 	}
 
+	sourceMetadata := readBlocksMetadata(blocksToCompactDirs, jobLogger)
+
 	blocksToUpload := convertCompactionResultToForEachJobs(compIDs, job.UseSplitting(), jobLogger)
+
+	// The exemplars of the source blocks are merged and split across the compacted blocks.
+	exemplarsShardCount := uint64(1)
+	if job.UseSplitting() {
+		exemplarsShardCount = uint64(job.SplittingShards())
+	}
+	if err := writeCompactedBlocksExemplars(blocksToCompactDirs, subDir, blocksToUpload, exemplarsShardCount, jobLogger); err != nil {
+		return false, nil, errors.Wrap(err, "write exemplars of compacted blocks")
+	}
+
 	err = concurrency.ForEachJob(ctx, len(blocksToUpload), c.blockSyncConcurrency, func(ctx context.Context, idx int) error {
 		blockToUpload := blocksToUpload[idx]
 
@@ -401,6 +415,19 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 			return errors.Wrapf(err, "invalid result block %s", bdir)
 		}
 
+		if len(sourceMetadata) > 0 {
+			shardIndex, shardCount := uint64(0), uint64(1)
+			if job.UseSplitting() {
+				shardIndex, shardCount = uint64(blockToUpload.shardIndex), uint64(job.SplittingShards())
+			}
+
+			if blockMetadata := compactedBlockMetadata(sourceMetadata, newMeta, shardIndex, shardCount); len(blockMetadata) > 0 {
+				if err := block.WriteMetadataFile(bdir, blockMetadata); err != nil {
+					return errors.Wrapf(err, "write metadata of block %s", bdir)
+				}
+			}
+		}
+
 		begin := time.Now()
 		if err := block.Upload(ctx, jobLogger, c.bkt, bdir, nil); err != nil {
 			return errors.Wrapf(err, "upload of %s failed", blockToUpload.ulid)
@@ -429,6 +456,221 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 	return true, compIDs, nil
 }
 
+// writeCompactedBlocksExemplars merges the exemplars of the source blocks and writes them to the compacted
+// blocks in subDir: each compacted block gets the exemplars within its time range, of the series belonging
+// to its shard. Series are split across the shards the same way the compactor splits them. The source
+// exemplars files are sorted by series, so they're streamed and merged one series at a time.
+func writeCompactedBlocksExemplars(sourceDirs []string, subDir string, compactedBlocks []ulidWithShardIndex, shardCount uint64, logger log.Logger) (err error) {
+	type target struct {
+		dir              string
+		minTime, maxTime int64
+		shardIndex       uint64
+		writer           *block.ExemplarsWriter
+	}
+
+	targets := make([]*target, 0, len(compactedBlocks))
+	for _, b := range compactedBlocks {
+		dir := filepath.Join(subDir, b.ulid.String())
+		meta, err := block.ReadMetaFromDir(dir)
+		if err != nil {
+			return errors.Wrapf(err, "read meta of block %s", dir)
+		}
+		targets = append(targets, &target{dir: dir, minTime: meta.MinTime, maxTime: meta.MaxTime, shardIndex: uint64(b.shardIndex)})
+	}
+
+	defer func() {
+		for _, t := range targets {
+			if t.writer == nil {
+				continue
+			}
+			if err != nil {
+				t.writer.Discard()
+			} else if closeErr := t.writer.Close(); closeErr != nil {
+				err = errors.Wrapf(closeErr, "write exemplars of block %s", t.dir)
+			}
+		}
+	}()
+
+	return mergeBlocksExemplars(sourceDirs, logger, func(series mimirpb.TimeSeries) error {
+		shardIndex := uint64(0)
+		if shardCount > 1 {
+			shardIndex = labels.StableHash(mimirpb.FromLabelAdaptersToLabels(series.Labels)) % shardCount
+		}
+
+		for _, t := range targets {
+			if shardCount > 1 && t.shardIndex != shardIndex {
+				continue
+			}
+
+			// The block max time is exclusive.
+			filtered := block.FilterExemplars([]mimirpb.TimeSeries{series}, t.minTime, t.maxTime-1)
+			if len(filtered) == 0 {
+				continue
+			}
+
+			if t.writer == nil {
+				w, err := block.NewExemplarsWriter(t.dir)
+				if err != nil {
+					return errors.Wrapf(err, "write exemplars of block %s", t.dir)
+				}
+				t.writer = w
+			}
+			if err := t.writer.Append(filtered[0]); err != nil {
+				return errors.Wrapf(err, "write exemplars of block %s", t.dir)
+			}
+		}
+		return nil
+	})
+}
+
+// mergeBlocksExemplars merges the exemplars files of the input blocks, calling fn for each series in labels
+// order. Only the current series of each file is kept in memory. The exemplars of a block which can't be
+// read are skipped from the point of the failure, because exemplars are best-effort and shouldn't prevent
+// compaction.
+func mergeBlocksExemplars(blockDirs []string, logger log.Logger, fn func(series mimirpb.TimeSeries) error) error {
+	var sources []*exemplarsSource
+	defer func() {
+		for _, s := range sources {
+			s.close()
+		}
+	}()
+
+	for _, dir := range blockDirs {
+		s, err := openExemplarsSource(dir)
+		if err != nil {
+			level.Warn(logger).Log("msg", "failed to read block exemplars, skipping them", "block", dir, "err", err)
+			continue
+		}
+		if s == nil {
+			continue
+		}
+		if s.next(logger) {
+			sources = append(sources, s)
+		} else {
+			s.close()
+		}
+	}
+
+	for len(sources) > 0 {
+		lowest := sources[0].lset
+		for _, s := range sources[1:] {
+			if labels.Compare(s.lset, lowest) < 0 {
+				lowest = s.lset
+			}
+		}
+
+		var merged mimirpb.TimeSeries
+		for i := 0; i < len(sources); {
+			s := sources[i]
+			if labels.Compare(s.lset, lowest) != 0 {
+				i++
+				continue
+			}
+
+			if merged.Labels == nil {
+				merged.Labels = s.cur.Labels
+			}
+			merged.Exemplars = append(merged.Exemplars, s.cur.Exemplars...)
+
+			if !s.next(logger) {
+				s.close()
+				sources = append(sources[:i], sources[i+1:]...)
+				continue
+			}
+			i++
+		}
+
+		merged.Exemplars = block.DedupeExemplars(merged.Exemplars)
+		if err := fn(merged); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exemplarsSource reads the exemplars file of a source block one series at a time.
+type exemplarsSource struct {
+	dir  string
+	f    *os.File
+	r    *block.ExemplarsReader
+	cur  mimirpb.TimeSeries
+	lset labels.Labels
+}
+
+// openExemplarsSource opens the exemplars file of the block in dir. If the block has no exemplars file,
+// no source and no error are returned.
+func openExemplarsSource(dir string) (*exemplarsSource, error) {
+	f, err := os.Open(filepath.Join(dir, block.ExemplarsFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := block.NewExemplarsReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &exemplarsSource{dir: dir, f: f, r: r}, nil
+}
+
+// next reads the next series, and returns false once the file has been fully read or can't be read anymore.
+func (s *exemplarsSource) next(logger log.Logger) bool {
+	series, err := s.r.Next()
+	if errors.Is(err, io.EOF) {
+		return false
+	}
+	if err != nil {
+		level.Warn(logger).Log("msg", "failed to read block exemplars, skipping the remaining ones", "block", s.dir, "err", err)
+		return false
+	}
+
+	s.cur = series
+	s.lset = mimirpb.FromLabelAdaptersToLabels(series.Labels)
+	return true
+}
+
+func (s *exemplarsSource) close() {
+	_ = s.f.Close()
+}
+
+// readBlocksMetadata reads and merges the metric metadata of the input blocks. The metadata of a block which
+// can't be read is skipped, because metadata is best-effort and shouldn't prevent compaction.
+func readBlocksMetadata(blockDirs []string, logger log.Logger) []block.MetricMetadata {
+	var sets [][]block.MetricMetadata
+	for _, dir := range blockDirs {
+		metadata, err := block.ReadMetadataFile(dir)
+		if err != nil {
+			level.Warn(logger).Log("msg", "failed to read block metadata, skipping it", "block", dir, "err", err)
+			continue
+		}
+		if len(metadata) > 0 {
+			sets = append(sets, metadata)
+		}
+	}
+
+	return block.MergeMetadata(sets...)
+}
+
+// compactedBlockMetadata returns the metric metadata belonging to a compacted block: the metadata observed
+// within the block time range, of the metrics belonging to the block shard. The series of a metric are split
+// across all the shards, so the metadata is split by metric name instead, to not store it in every shard.
+func compactedBlockMetadata(metadata []block.MetricMetadata, meta *block.Meta, shardIndex, shardCount uint64) []block.MetricMetadata {
+	var result []block.MetricMetadata
+	for _, m := range metadata {
+		if shardCount > 1 && labels.StableHash(labels.FromStrings(labels.MetricName, m.Metric))%shardCount != shardIndex {
+			continue
+		}
+		result = append(result, m)
+	}
+
+	// The block max time is exclusive.
+	return block.FilterMetadata(result, meta.MinTime, meta.MaxTime-1, "")
+}
+
 // verifyCompactedBlocksTimeRanges does a full run over the compacted blocks
 // and verifies that they satisfy the min/maxTime from the source blocks
 func verifyCompactedBlocksTimeRanges(compIDs []ulid.ULID, sourceBlocksMinTime, sourceBlocksMaxTime int64, subDir string) error {
@@ -8,6 +8,9 @@ package compactor
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -23,6 +26,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/thanos-io/objstore"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/util/extprom"
 )
@@ -353,3 +357,148 @@ func TestCompactedBlocksTimeRangeVerification(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteCompactedBlocksExemplars(t *testing.T) {
+	series := func(name string, timestamps ...int64) mimirpb.TimeSeries {
+		s := mimirpb.TimeSeries{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings(labels.MetricName, name))}
+		for _, ts := range timestamps {
+			s.Exemplars = append(s.Exemplars, mimirpb.Exemplar{Value: 1, TimestampMs: ts})
+		}
+		return s
+	}
+
+	// createCompactedBlocks creates a compacted block for each shard, spanning the [0, 200) time range.
+	createCompactedBlocks := func(t *testing.T, shardCount int) (string, []ulidWithShardIndex) {
+		subDir := t.TempDir()
+
+		var blocks []ulidWithShardIndex
+		for shardIndex := 0; shardIndex < shardCount; shardIndex++ {
+			id := ulid.MustNew(uint64(shardIndex), nil)
+			dir := filepath.Join(subDir, id.String())
+			require.NoError(t, os.Mkdir(dir, 0777))
+
+			meta := block.Meta{BlockMeta: tsdb.BlockMeta{ULID: id, MinTime: 0, MaxTime: 200, Version: block.TSDBVersion1}}
+			require.NoError(t, meta.WriteToDir(log.NewNopLogger(), dir))
+
+			blocks = append(blocks, ulidWithShardIndex{ulid: id, shardIndex: shardIndex})
+		}
+		return subDir, blocks
+	}
+
+	t.Run("should merge the exemplars of the source blocks", func(t *testing.T) {
+		first, second, withoutExemplars, corrupted, truncated := t.TempDir(), t.TempDir(), t.TempDir(), t.TempDir(), t.TempDir()
+		require.NoError(t, block.WriteExemplarsFile(first, []mimirpb.TimeSeries{series("a", 10, 20), series("b", 10), series("d", 10)}))
+		require.NoError(t, block.WriteExemplarsFile(second, []mimirpb.TimeSeries{series("a", 20, 30), series("c", 10)}))
+		require.NoError(t, os.WriteFile(filepath.Join(corrupted, block.ExemplarsFilename), []byte("corrupted"), 0666))
+
+		// The exemplars read before the truncation are kept.
+		require.NoError(t, block.WriteExemplarsFile(truncated, []mimirpb.TimeSeries{series("a", 40), series("e", 10)}))
+		content, err := os.ReadFile(filepath.Join(truncated, block.ExemplarsFilename))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(truncated, block.ExemplarsFilename), content[:len(content)-1], 0666))
+
+		subDir, blocks := createCompactedBlocks(t, 1)
+		require.NoError(t, writeCompactedBlocksExemplars([]string{first, second, withoutExemplars, corrupted, truncated}, subDir, blocks, 1, log.NewNopLogger()))
+
+		actual, err := block.ReadExemplarsFile(filepath.Join(subDir, blocks[0].ulid.String()))
+		require.NoError(t, err)
+		assert.Equal(t, []mimirpb.TimeSeries{series("a", 10, 20, 30, 40), series("b", 10), series("c", 10), series("d", 10)}, actual)
+	})
+
+	t.Run("should keep only the exemplars within the block time range", func(t *testing.T) {
+		source := t.TempDir()
+		require.NoError(t, block.WriteExemplarsFile(source, []mimirpb.TimeSeries{series("a", 100, 200), series("b", 200)}))
+
+		subDir, blocks := createCompactedBlocks(t, 1)
+		require.NoError(t, writeCompactedBlocksExemplars([]string{source}, subDir, blocks, 1, log.NewNopLogger()))
+
+		// The exemplars at the block max time belong to the next block.
+		actual, err := block.ReadExemplarsFile(filepath.Join(subDir, blocks[0].ulid.String()))
+		require.NoError(t, err)
+		assert.Equal(t, []mimirpb.TimeSeries{series("a", 100)}, actual)
+	})
+
+	t.Run("should split the exemplars across the shards", func(t *testing.T) {
+		const shardCount = 3
+
+		var sourceSeries []mimirpb.TimeSeries
+		for i := 0; i < 10; i++ {
+			sourceSeries = append(sourceSeries, series("metric_"+strconv.Itoa(i), 100))
+		}
+		source := t.TempDir()
+		require.NoError(t, block.WriteExemplarsFile(source, sourceSeries))
+
+		subDir, blocks := createCompactedBlocks(t, shardCount)
+		require.NoError(t, writeCompactedBlocksExemplars([]string{source}, subDir, blocks, shardCount, log.NewNopLogger()))
+
+		total := 0
+		for _, b := range blocks {
+			actual, err := block.ReadExemplarsFile(filepath.Join(subDir, b.ulid.String()))
+			require.NoError(t, err)
+			for _, s := range actual {
+				assert.Equal(t, uint64(b.shardIndex), labels.StableHash(mimirpb.FromLabelAdaptersToLabels(s.Labels))%shardCount)
+				total++
+			}
+		}
+		assert.Equal(t, len(sourceSeries), total)
+	})
+
+	t.Run("should not write an exemplars file to blocks without exemplars", func(t *testing.T) {
+		subDir, blocks := createCompactedBlocks(t, 1)
+		require.NoError(t, writeCompactedBlocksExemplars([]string{t.TempDir()}, subDir, blocks, 1, log.NewNopLogger()))
+
+		_, err := os.Stat(filepath.Join(subDir, blocks[0].ulid.String(), block.ExemplarsFilename))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestReadBlocksMetadata(t *testing.T) {
+	first, second, withoutMetadata, corrupted := t.TempDir(), t.TempDir(), t.TempDir(), t.TempDir()
+	require.NoError(t, block.WriteMetadataFile(first, []block.MetricMetadata{
+		{Metric: "a", Type: "counter", Help: "a help", MinTime: 10, MaxTime: 20},
+		{Metric: "b", Type: "gauge", Help: "b help", MinTime: 10, MaxTime: 20},
+	}))
+	require.NoError(t, block.WriteMetadataFile(second, []block.MetricMetadata{
+		{Metric: "a", Type: "counter", Help: "a help", MinTime: 20, MaxTime: 25},
+		{Metric: "a", Type: "counter", Help: "a new help", MinTime: 25, MaxTime: 30},
+	}))
+	require.NoError(t, os.WriteFile(filepath.Join(corrupted, block.MetadataFilename), []byte("corrupted"), 0666))
+
+	actual := readBlocksMetadata([]string{first, second, withoutMetadata, corrupted}, log.NewNopLogger())
+	assert.Equal(t, []block.MetricMetadata{
+		{Metric: "a", Type: "counter", Help: "a help", MinTime: 10, MaxTime: 25},
+		{Metric: "a", Type: "counter", Help: "a new help", MinTime: 25, MaxTime: 30},
+		{Metric: "b", Type: "gauge", Help: "b help", MinTime: 10, MaxTime: 20},
+	}, actual)
+}
+
+func TestCompactedBlockMetadata(t *testing.T) {
+	var metadata []block.MetricMetadata
+	for i := 0; i < 10; i++ {
+		metadata = append(metadata, block.MetricMetadata{Metric: "metric_" + strconv.Itoa(i), Type: "counter", MinTime: 100, MaxTime: 300})
+	}
+	meta := &block.Meta{BlockMeta: tsdb.BlockMeta{MinTime: 0, MaxTime: 200}}
+
+	t.Run("no splitting", func(t *testing.T) {
+		actual := compactedBlockMetadata(metadata, meta, 0, 1)
+		require.Len(t, actual, len(metadata))
+		for _, m := range actual {
+			// The observation time range is clamped to the block time range, whose max time is exclusive.
+			assert.Equal(t, int64(100), m.MinTime)
+			assert.Equal(t, int64(199), m.MaxTime)
+		}
+	})
+
+	t.Run("splitting", func(t *testing.T) {
+		const shardCount = 3
+
+		total := 0
+		for shardIndex := uint64(0); shardIndex < shardCount; shardIndex++ {
+			for _, m := range compactedBlockMetadata(metadata, meta, shardIndex, shardCount) {
+				assert.Equal(t, shardIndex, labels.StableHash(labels.FromStrings(labels.MetricName, m.Metric))%shardCount)
+				total++
+			}
+		}
+		assert.Equal(t, len(metadata), total)
+	})
+}
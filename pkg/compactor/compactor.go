@@ -35,6 +35,7 @@ import (
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/util"
 	util_log "github.com/grafana/mimir/pkg/util/log"
+	"github.com/grafana/mimir/pkg/util/validation"
 )
 
 const (
@@ -44,6 +45,8 @@ const (
 	// ringAutoForgetUnhealthyPeriods is how many consecutive timeout periods an unhealthy instance
 	// in the ring will be automatically removed after.
 	ringAutoForgetUnhealthyPeriods = 10
+
+	deleteRequestsGracePeriodFlag = "compactor.delete-requests-grace-period"
 )
 
 const (
@@ -58,6 +61,7 @@ var (
 	errInvalidMaxClosingBlocksConcurrency         = fmt.Errorf("invalid max-closing-blocks-concurrency value, must be positive")
 	errInvalidSymbolFlushersConcurrency           = fmt.Errorf("invalid symbols-flushers-concurrency value, must be positive")
 	errInvalidMaxBlockUploadValidationConcurrency = fmt.Errorf("invalid max-block-upload-validation-concurrency value, can't be negative")
+	errInvalidDeleteRequestsGracePeriod           = fmt.Errorf("the -%s setting must be greater than -%s otherwise queries might return the samples deleted from the blocks but still stored in the ingesters", deleteRequestsGracePeriodFlag, validation.QueryIngestersWithinFlag)
 	RingOp                                        = ring.NewOp([]ring.InstanceState{ring.ACTIVE}, nil)
 )
 
@@ -95,6 +99,7 @@ type Config struct {
 	TenantCleanupDelay         time.Duration           `yaml:"tenant_cleanup_delay" category:"advanced"`
 	MaxCompactionTime          time.Duration           `yaml:"max_compaction_time" category:"advanced"`
 	NoBlocksFileCleanupEnabled bool                    `yaml:"no_blocks_file_cleanup_enabled" category:"experimental"`
+	DeleteRequestsGracePeriod  time.Duration           `yaml:"delete_requests_grace_period" category:"experimental"`
 
 	// Compactor concurrency options
 	MaxOpeningBlocksConcurrency         int `yaml:"max_opening_blocks_concurrency" category:"advanced"`          // Number of goroutines opening blocks before compaction.
@@ -146,6 +151,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 		"If 0, blocks will be deleted straight away. Note that deleting blocks immediately can cause query failures.")
 	f.DurationVar(&cfg.TenantCleanupDelay, "compactor.tenant-cleanup-delay", 6*time.Hour, "For tenants marked for deletion, this is time between deleting of last block, and doing final cleanup (marker files, debug files) of the tenant.")
 	f.BoolVar(&cfg.NoBlocksFileCleanupEnabled, "compactor.no-blocks-file-cleanup-enabled", false, "If enabled, will delete the bucket-index, markers and debug files in the tenant bucket when there are no blocks left in the index.")
+	f.DurationVar(&cfg.DeleteRequestsGracePeriod, deleteRequestsGracePeriodFlag, 24*time.Hour, fmt.Sprintf("Minimum time since the creation of a delete request before it's marked as processed and the deleted samples are not filtered out by the queriers anymore. Ingesters don't apply the delete requests, so it must be greater than -%s. Processed delete requests are still applied to the blocks uploaded late, and are removed once they have been processed for the same period.", validation.QueryIngestersWithinFlag))
 	// compactor concurrency options
 	f.IntVar(&cfg.MaxOpeningBlocksConcurrency, "compactor.max-opening-blocks-concurrency", 1, "Number of goroutines opening blocks before compaction.")
 	f.IntVar(&cfg.MaxClosingBlocksConcurrency, "compactor.max-closing-blocks-concurrency", 1, "Max number of blocks that can be closed concurrently during split compaction. Note that closing of newly compacted block uses a lot of memory for writing index.")
@@ -183,6 +189,17 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+// ValidateLimits validates the runtime limits that can be set for each tenant against the compactor config.
+func (cfg *Config) ValidateLimits(limits validation.Limits) error {
+	// Once a delete request is processed, the queriers don't filter out the deleted samples anymore,
+	// so they must not query them from the ingesters, which never apply the delete requests.
+	if limits.QueryIngestersWithin != 0 && cfg.DeleteRequestsGracePeriod <= time.Duration(limits.QueryIngestersWithin) {
+		return errInvalidDeleteRequestsGracePeriod
+	}
+
+	return nil
+}
+
 // ConfigProvider defines the per-tenant config provider for the MultitenantCompactor.
 type ConfigProvider interface {
 	bucket.TenantConfigProvider
@@ -266,6 +283,8 @@ type MultitenantCompactor struct {
 	compactionRunFailedTenants     prometheus.Gauge
 	compactionRunInterval          prometheus.Gauge
 	blocksMarkedForDeletion        prometheus.Counter
+	deleteRequestsBlocksRewritten  prometheus.Counter
+	deleteRequestsNoCompactMarks   prometheus.Counter
 
 	// Metrics shared across all BucketCompactor instances.
 	bucketCompactorMetrics *BucketCompactorMetrics
@@ -371,6 +390,15 @@ func newMultitenantCompactor(
 			Help:        blocksMarkedForDeletionHelp,
 			ConstLabels: prometheus.Labels{"reason": "compaction"},
 		}),
+		deleteRequestsBlocksRewritten: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_delete_requests_blocks_rewritten_total",
+			Help: "Total number of blocks rewritten by the compactor to remove the series matching tenants delete requests.",
+		}),
+		deleteRequestsNoCompactMarks: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Name:        "cortex_compactor_blocks_marked_for_no_compaction_total",
+			Help:        "Total number of blocks that were marked for no-compaction.",
+			ConstLabels: prometheus.Labels{"reason": block.DeleteRequestRewriteNoCompactReason},
+		}),
 		blockUploadBlocks: promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
 			Name: "cortex_block_upload_api_blocks_total",
 			Help: "Total number of blocks successfully uploaded and validated using the block upload API.",
@@ -716,6 +744,18 @@ func (c *MultitenantCompactor) compactUser(ctx context.Context, userID string) e
 
 	userLogger := util_log.WithUserID(userID, c.logger)
 
+	// Physically remove the series deleted by the tenant before compacting blocks. All compactors of
+	// the tenant's shard plan compaction jobs, but only the one running the blocks cleaner for the tenant
+	// applies the delete requests, so that blocks and delete requests are never updated concurrently.
+	// Failing to apply delete requests doesn't prevent compaction: they will be retried at the next run.
+	if owned, err := c.shardingStrategy.blocksCleanerOwnUser(userID); err != nil {
+		level.Warn(userLogger).Log("msg", "unable to check if delete requests are owned by this compactor", "err", err)
+	} else if owned {
+		if err := c.applyDeleteRequests(ctx, userID, userBucket, userLogger); err != nil {
+			level.Error(userLogger).Log("msg", "failed to apply delete requests", "err", err)
+		}
+	}
+
 	// Filters out duplicate blocks that can be formed from two or more overlapping
 	// blocks that fully submatches the source blocks of the older blocks.
 	deduplicateBlocksFilter := NewShardAwareDeduplicateFilter()
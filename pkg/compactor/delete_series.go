@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/thanos-io/objstore"
+
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+// applyDeleteRequests rewrites the tenant's blocks containing series matching the delete requests, and
+// removes the delete requests processed for longer than the grace period. The processed delete requests
+// are applied until they're removed, so that the blocks uploaded late (e.g. out-of-order blocks, or blocks
+// shipped by ingesters which have been unavailable for a while) are rewritten as well.
+func (c *MultitenantCompactor) applyDeleteRequests(ctx context.Context, userID string, userBucket objstore.InstrumentedBucket, userLogger log.Logger) error {
+	requests, err := mimir_tsdb.ReadDeleteRequests(ctx, userBucket)
+	if err != nil {
+		return err
+	}
+
+	var active []*mimir_tsdb.DeleteRequest
+	for _, req := range requests {
+		if req.State == mimir_tsdb.DeleteRequestProcessed && time.Since(time.Unix(req.ProcessedTime, 0)) >= c.compactorCfg.DeleteRequestsGracePeriod {
+			if err := mimir_tsdb.DeleteDeleteRequest(ctx, userBucket, req.RequestID); err != nil {
+				return errors.Wrapf(err, "remove processed delete request %s", req.RequestID)
+			}
+			level.Info(userLogger).Log("msg", "removed processed delete request", "request_id", req.RequestID)
+			continue
+		}
+
+		active = append(active, req)
+	}
+	if len(active) == 0 {
+		return nil
+	}
+
+	fetcher, err := block.NewMetaFetcher(userLogger, c.compactorCfg.MetaSyncConcurrency, userBucket, c.metaSyncDirForUser(userID), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, req := range active {
+		if err := c.applyDeleteRequest(ctx, userBucket, fetcher, req, log.With(userLogger, "request_id", req.RequestID)); err != nil {
+			return errors.Wrapf(err, "apply delete request %s", req.RequestID)
+		}
+	}
+
+	return nil
+}
+
+func (c *MultitenantCompactor) applyDeleteRequest(ctx context.Context, userBucket objstore.InstrumentedBucket, fetcher *block.MetaFetcher, req *mimir_tsdb.DeleteRequest, logger log.Logger) error {
+	// Fetch the metas for each request, because blocks are replaced while applying the previous requests.
+	metas, _, err := fetcher.FetchWithoutMarkedForDeletion(ctx)
+	if err != nil {
+		return errors.Wrap(err, "fetch blocks metas")
+	}
+
+	updated := false
+	for _, meta := range metas {
+		// Blocks time range is half-open, while the delete request time range is closed.
+		if meta.MinTime > req.EndTime || meta.MaxTime <= req.StartTime {
+			continue
+		}
+		if req.IsBlockProcessed(meta.ULID) {
+			continue
+		}
+
+		newID, err := c.rewriteBlock(ctx, userBucket, meta, req, logger)
+		if err != nil {
+			return errors.Wrapf(err, "rewrite block %s", meta.ULID)
+		}
+
+		// Only the block resulting from the rewrite is processed. The blocks compacted from the original
+		// block while it was rewritten, or later from the rewritten block, have a different ID, so they're
+		// checked again at the next run.
+		if newID != (ulid.ULID{}) {
+			req.ProcessedBlocks = append(req.ProcessedBlocks, newID)
+		} else {
+			req.ProcessedBlocks = append(req.ProcessedBlocks, meta.ULID)
+		}
+		updated = true
+
+		// Persist the progress after each block, so that a failure doesn't cause blocks to be rewritten again.
+		if err := mimir_tsdb.WriteDeleteRequest(ctx, userBucket, req); err != nil {
+			return err
+		}
+	}
+
+	if req.State == mimir_tsdb.DeleteRequestProcessed {
+		if updated {
+			level.Warn(logger).Log("msg", "rewritten blocks uploaded after the delete request was processed", "processed_blocks", len(req.ProcessedBlocks))
+		}
+		return nil
+	}
+
+	// The grace period gives ingesters enough time to upload the blocks containing samples ingested
+	// before the request was created, which have to be rewritten as well, and guarantees that the queriers
+	// don't query the deleted samples from the ingesters anymore once the request is processed.
+	if time.Since(time.Unix(req.CreationTime, 0)) < c.compactorCfg.DeleteRequestsGracePeriod {
+		return nil
+	}
+
+	if !updated {
+		req.State = mimir_tsdb.DeleteRequestProcessed
+		req.ProcessedTime = time.Now().Unix()
+		level.Info(logger).Log("msg", "delete request processed", "processed_blocks", len(req.ProcessedBlocks))
+		return mimir_tsdb.WriteDeleteRequest(ctx, userBucket, req)
+	}
+
+	return nil
+}
+
+// rewriteBlock downloads the block, removes the series matching the delete request and uploads the result.
+// The original block is marked for deletion. Returns the ID of the new block, or an empty ID if the block
+// didn't contain any matching series or all its series have been removed.
+//
+// The block is marked for no-compaction while it's rewritten, so that the compaction jobs planned in the
+// meantime don't compact it. The mark is kept if the original block is marked for deletion.
+func (c *MultitenantCompactor) rewriteBlock(ctx context.Context, userBucket objstore.InstrumentedBucket, meta *block.Meta, req *mimir_tsdb.DeleteRequest, logger log.Logger) (ulid.ULID, error) {
+	matchers, err := req.Matchers()
+	if err != nil {
+		return ulid.ULID{}, err
+	}
+
+	unmark, err := c.markNoCompactForRewrite(ctx, userBucket, meta.ULID, logger)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrapf(err, "mark block %s for no-compaction", meta.ULID)
+	}
+	markedForDeletion := false
+	defer func() {
+		if !markedForDeletion {
+			unmark()
+		}
+	}()
+
+	// Each block is rewritten in its own working directory, because the blocks of different
+	// tenants may be rewritten concurrently.
+	baseDir := filepath.Join(c.compactorCfg.DataDir, "delete-requests")
+	if err := os.MkdirAll(baseDir, 0o750); err != nil {
+		return ulid.ULID{}, err
+	}
+	dir, err := os.MkdirTemp(baseDir, meta.ULID.String()+"-")
+	if err != nil {
+		return ulid.ULID{}, err
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			level.Warn(logger).Log("msg", "failed to remove delete requests working directory", "dir", dir, "err", err)
+		}
+	}()
+
+	bdir := filepath.Join(dir, meta.ULID.String())
+	if err := block.Download(ctx, logger, userBucket, meta.ULID, bdir); err != nil {
+		return ulid.ULID{}, errors.Wrapf(err, "download block %s", meta.ULID)
+	}
+
+	b, err := tsdb.OpenBlock(logger, bdir, nil)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrapf(err, "open block %s", meta.ULID)
+	}
+	defer func() {
+		if err := b.Close(); err != nil {
+			level.Warn(logger).Log("msg", "failed to close block", "block", meta.ULID, "err", err)
+		}
+	}()
+
+	for _, ms := range matchers {
+		if err := b.Delete(ctx, req.StartTime, req.EndTime, ms...); err != nil {
+			return ulid.ULID{}, errors.Wrapf(err, "delete series from block %s", meta.ULID)
+		}
+	}
+
+	if b.Meta().Stats.NumTombstones == 0 {
+		level.Debug(logger).Log("msg", "block doesn't contain series matching the delete request", "block", meta.ULID)
+		return ulid.ULID{}, nil
+	}
+
+	compactor, err := tsdb.NewLeveledCompactor(ctx, nil, logger, c.compactorCfg.BlockRanges.ToMilliseconds(), nil, nil, true)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrap(err, "create compactor")
+	}
+
+	newID, err := compactor.Write(dir, b, meta.MinTime, meta.MaxTime, &meta.BlockMeta)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrapf(err, "write block %s", meta.ULID)
+	}
+
+	if newID != (ulid.ULID{}) {
+		newDir := filepath.Join(dir, newID.String())
+
+		// Keep the original compaction and Thanos metadata, so that the rewritten block gets
+		// compacted exactly like the original one would have been.
+		newMeta, err := block.InjectThanosMeta(logger, newDir, block.ThanosMeta{
+			Labels:       meta.Thanos.Labels,
+			Downsample:   meta.Thanos.Downsample,
+			Source:       block.CompactorSource,
+			SegmentFiles: block.GetSegmentFiles(newDir),
+		}, &meta.BlockMeta)
+		if err != nil {
+			return ulid.ULID{}, errors.Wrapf(err, "failed to finalize the block %s", newDir)
+		}
+
+		if err = os.Remove(filepath.Join(newDir, "tombstones")); err != nil && !os.IsNotExist(err) {
+			return ulid.ULID{}, errors.Wrap(err, "remove tombstones")
+		}
+
+		if err := block.VerifyBlock(ctx, logger, newDir, newMeta.MinTime, newMeta.MaxTime, false); err != nil {
+			return ulid.ULID{}, errors.Wrapf(err, "invalid rewritten block %s", newDir)
+		}
+
+		if err := block.Upload(ctx, logger, userBucket, newDir, nil); err != nil {
+			return ulid.ULID{}, errors.Wrapf(err, "upload of %s failed", newID)
+		}
+	}
+
+	level.Info(logger).Log("msg", "rewritten block to apply delete request", "old_block", meta.ULID, "new_block", newID)
+	c.deleteRequestsBlocksRewritten.Inc()
+
+	// Spawn a new context so we always mark a block for deletion in full on shutdown.
+	delCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := block.MarkForDeletion(delCtx, logger, userBucket, meta.ULID, "source of rewritten block for delete request", c.blocksMarkedForDeletion); err != nil {
+		return ulid.ULID{}, errors.Wrapf(err, "marking old block %s for deletion has failed", meta.ULID)
+	}
+	markedForDeletion = true
+
+	return newID, nil
+}
+
+// markNoCompactForRewrite marks the block for no-compaction with the DeleteRequestRewriteNoCompactReason,
+// and returns a function removing the mark. If the block is already marked for no-compaction for another
+// reason, the mark is left untouched.
+func (c *MultitenantCompactor) markNoCompactForRewrite(ctx context.Context, userBucket objstore.InstrumentedBucket, id ulid.ULID, logger log.Logger) (func(), error) {
+	var mark block.NoCompactMark
+	err := block.ReadMarker(ctx, logger, userBucket, id.String(), &mark)
+	switch {
+	case err == nil && mark.Reason != block.DeleteRequestRewriteNoCompactReason:
+		return func() {}, nil
+	case err == nil:
+		// The mark has been left by a previous rewrite which didn't complete.
+	case errors.Is(err, block.ErrorMarkerNotFound):
+		if err := block.MarkForNoCompact(ctx, logger, userBucket, id, block.DeleteRequestRewriteNoCompactReason, "rewriting block for delete request", c.deleteRequestsNoCompactMarks); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	return func() {
+		// Spawn a new context so we always remove the mark on shutdown.
+		delCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := userBucket.Delete(delCtx, path.Join(id.String(), block.NoCompactMarkFilename)); err != nil {
+			level.Warn(logger).Log("msg", "failed to remove no-compaction mark of rewritten block", "block", id, "err", err)
+		}
+	}, nil
+}
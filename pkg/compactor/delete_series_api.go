@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+// AddDeleteRequest is a Prometheus-compatible API to delete the series matching the match[] selectors
+// within the start and end time range. The request is persisted in the bucket: the matching series are
+// filtered out at query time and physically removed from blocks by the compactor.
+func (c *MultitenantCompactor) AddDeleteRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	startTime, endTime := int64(0), now.UnixMilli()
+	if v := r.FormValue("start"); v != "" {
+		if startTime, err = util.ParseTime(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := r.FormValue("end"); v != "" {
+		if endTime, err = util.ParseTime(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	req, err := mimir_tsdb.NewDeleteRequest(r.Form["match[]"], startTime, endTime, now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := mimir_tsdb.WriteDeleteRequest(ctx, bucket.NewUserBucketClient(userID, c.bucketClient, c.cfgProvider), req); err != nil {
+		level.Error(c.logger).Log("msg", "failed to write delete request", "user", userID, "err", err)
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	level.Info(c.logger).Log("msg", "delete request created", "user", userID, "request_id", req.RequestID, "selectors", fmt.Sprint(req.Selectors), "start", req.StartTime, "end", req.EndTime)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeleteRequests returns all the delete requests of the tenant.
+func (c *MultitenantCompactor) GetDeleteRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := mimir_tsdb.ReadDeleteRequests(ctx, bucket.NewUserBucketClient(userID, c.bucketClient, c.cfgProvider))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if requests == nil {
+		requests = []*mimir_tsdb.DeleteRequest{}
+	}
+
+	util.WriteJSONResponse(w, requests)
+}
+
+// CancelDeleteRequest removes the delete request with the given request_id. A delete request can be canceled
+// only until the compactor has started rewriting the blocks, because deleted data can't be recovered.
+func (c *MultitenantCompactor) CancelDeleteRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	requestID := r.FormValue("request_id")
+	if requestID == "" {
+		http.Error(w, "missing request_id parameter", http.StatusBadRequest)
+		return
+	}
+
+	userBucket := bucket.NewUserBucketClient(userID, c.bucketClient, c.cfgProvider)
+	req, err := mimir_tsdb.ReadDeleteRequest(ctx, userBucket, requestID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if req == nil {
+		http.Error(w, "delete request not found", http.StatusNotFound)
+		return
+	}
+	if req.State != mimir_tsdb.DeleteRequestPending || len(req.ProcessedBlocks) > 0 {
+		http.Error(w, "the delete request can't be canceled because the compactor has already started processing it", http.StatusBadRequest)
+		return
+	}
+
+	if err := mimir_tsdb.DeleteDeleteRequest(ctx, userBucket, requestID); err != nil {
+		level.Error(c.logger).Log("msg", "failed to cancel delete request", "user", userID, "request_id", requestID, "err", err)
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	level.Info(c.logger).Log("msg", "delete request canceled", "user", userID, "request_id", requestID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
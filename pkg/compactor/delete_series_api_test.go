@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/oklog/ulid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+)
+
+func TestAddDeleteRequest(t *testing.T) {
+	bkt := objstore.NewInMemBucket()
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), c))
+	t.Cleanup(stopServiceFn(t, c))
+
+	ctx := user.InjectOrgID(context.Background(), "user")
+
+	{
+		resp := httptest.NewRecorder()
+		c.AddDeleteRequest(resp, httptest.NewRequest(http.MethodPost, "/", nil))
+		require.Equal(t, http.StatusUnauthorized, resp.Code)
+	}
+
+	for name, tc := range map[string]struct {
+		form         url.Values
+		expectedCode int
+	}{
+		"missing selectors": {
+			form:         url.Values{},
+			expectedCode: http.StatusBadRequest,
+		},
+		"invalid selector": {
+			form:         url.Values{"match[]": {`{job=}`}},
+			expectedCode: http.StatusBadRequest,
+		},
+		"invalid start time": {
+			form:         url.Values{"match[]": {`up`}, "start": {"invalid"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		"start time after end time": {
+			form:         url.Values{"match[]": {`up`}, "start": {"20"}, "end": {"10"}},
+			expectedCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			c.AddDeleteRequest(resp, newDeleteSeriesRequest(ctx, tc.form))
+			require.Equal(t, tc.expectedCode, resp.Code)
+		})
+	}
+
+	resp := httptest.NewRecorder()
+	c.AddDeleteRequest(resp, newDeleteSeriesRequest(ctx, url.Values{"match[]": {`{job="test"}`, `up`}, "start": {"10"}, "end": {"20"}}))
+	require.Equal(t, http.StatusNoContent, resp.Code)
+
+	requests, err := mimir_tsdb.ReadDeleteRequests(ctx, bucket.NewUserBucketClient("user", bkt, nil))
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, []string{`{job="test"}`, `up`}, requests[0].Selectors)
+	assert.Equal(t, int64(10000), requests[0].StartTime)
+	assert.Equal(t, int64(20000), requests[0].EndTime)
+	assert.Equal(t, mimir_tsdb.DeleteRequestPending, requests[0].State)
+
+	// Requests are stored per tenant.
+	requests, err = mimir_tsdb.ReadDeleteRequests(ctx, bucket.NewUserBucketClient("another-user", bkt, nil))
+	require.NoError(t, err)
+	require.Empty(t, requests)
+}
+
+func TestGetDeleteRequests(t *testing.T) {
+	bkt := objstore.NewInMemBucket()
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), c))
+	t.Cleanup(stopServiceFn(t, c))
+
+	ctx := user.InjectOrgID(context.Background(), "user")
+
+	{
+		resp := httptest.NewRecorder()
+		c.GetDeleteRequests(resp, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.JSONEq(t, `[]`, resp.Body.String())
+	}
+
+	req, err := mimir_tsdb.NewDeleteRequest([]string{`up`}, 0, 10, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, bucket.NewUserBucketClient("user", bkt, nil), req))
+
+	resp := httptest.NewRecorder()
+	c.GetDeleteRequests(resp, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var actual []*mimir_tsdb.DeleteRequest
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &actual))
+	require.Equal(t, []*mimir_tsdb.DeleteRequest{req}, actual)
+}
+
+func TestCancelDeleteRequest(t *testing.T) {
+	bkt := objstore.NewInMemBucket()
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), c))
+	t.Cleanup(stopServiceFn(t, c))
+
+	ctx := user.InjectOrgID(context.Background(), "user")
+
+	pending, err := mimir_tsdb.NewDeleteRequest([]string{`up`}, 0, 10, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, bucket.NewUserBucketClient("user", bkt, nil), pending))
+
+	started, err := mimir_tsdb.NewDeleteRequest([]string{`up`}, 0, 10, time.Now())
+	require.NoError(t, err)
+	started.ProcessedBlocks = []ulid.ULID{ulid.MustNew(1, nil)}
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, bucket.NewUserBucketClient("user", bkt, nil), started))
+
+	for name, tc := range map[string]struct {
+		requestID    string
+		expectedCode int
+	}{
+		"missing request ID": {
+			expectedCode: http.StatusBadRequest,
+		},
+		"unknown request ID": {
+			requestID:    "unknown",
+			expectedCode: http.StatusNotFound,
+		},
+		"request already being processed": {
+			requestID:    started.RequestID,
+			expectedCode: http.StatusBadRequest,
+		},
+		"pending request": {
+			requestID:    pending.RequestID,
+			expectedCode: http.StatusNoContent,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			c.CancelDeleteRequest(resp, newDeleteSeriesRequest(ctx, url.Values{"request_id": {tc.requestID}}))
+			require.Equal(t, tc.expectedCode, resp.Code)
+		})
+	}
+
+	requests, err := mimir_tsdb.ReadDeleteRequests(ctx, bucket.NewUserBucketClient("user", bkt, nil))
+	require.NoError(t, err)
+	require.Equal(t, []*mimir_tsdb.DeleteRequest{started}, requests)
+}
+
+func newDeleteSeriesRequest(ctx context.Context, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req.WithContext(ctx)
+}
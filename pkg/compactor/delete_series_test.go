@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+func TestMultitenantCompactor_applyDeleteRequests(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	bkt := block.BucketWithGlobalMarkers(objstore.NewInMemBucket())
+	userBucket := bucket.NewUserBucketClient(userID, bkt, nil)
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+	c.bucketClient = bkt
+
+	// Create a block with 5 series, and another one not overlapping the delete request time range.
+	blockID := createTSDBBlock(t, bkt, userID, 0, 2*time.Hour.Milliseconds(), 5, nil)
+	otherBlockID := createTSDBBlock(t, bkt, userID, 4*time.Hour.Milliseconds(), 6*time.Hour.Milliseconds(), 5, nil)
+
+	// The request is older than the grace period, so it can be marked as processed.
+	req, err := mimir_tsdb.NewDeleteRequest([]string{`{series_id="1"}`}, 0, time.Hour.Milliseconds(), time.Now().Add(-2*cfg.DeleteRequestsGracePeriod))
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBucket, req))
+
+	require.NoError(t, c.applyDeleteRequests(ctx, userID, userBucket, log.NewNopLogger()))
+
+	// The working directory of the rewritten block has been removed.
+	entries, err := os.ReadDir(filepath.Join(c.compactorCfg.DataDir, "delete-requests"))
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// The original block has been marked for deletion, and replaced by a new block.
+	exists, err := bkt.Exists(ctx, path.Join(userID, blockID.String(), block.DeletionMarkFilename))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = bkt.Exists(ctx, path.Join(userID, otherBlockID.String(), block.DeletionMarkFilename))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	stored, err := mimir_tsdb.ReadDeleteRequest(ctx, userBucket, req.RequestID)
+	require.NoError(t, err)
+	require.Equal(t, mimir_tsdb.DeleteRequestPending, stored.State)
+	require.Len(t, stored.ProcessedBlocks, 1)
+	newBlockID := stored.ProcessedBlocks[0]
+	require.NotEqual(t, blockID, newBlockID)
+
+	meta, err := block.DownloadMeta(ctx, log.NewNopLogger(), userBucket, newBlockID)
+	require.NoError(t, err)
+	assert.Equal(t, []ulid.ULID{blockID}, meta.Compaction.Sources)
+	assert.Equal(t, block.CompactorSource, meta.Thanos.Source)
+	assert.Equal(t, uint64(4), meta.Stats.NumSeries)
+
+	bdir := filepath.Join(t.TempDir(), newBlockID.String())
+	require.NoError(t, block.Download(ctx, log.NewNopLogger(), userBucket, newBlockID, bdir))
+	b, err := tsdb.OpenBlock(log.NewNopLogger(), bdir, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, b.Close()) })
+
+	q, err := tsdb.NewBlockQuerier(b, meta.MinTime, meta.MaxTime)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, q.Close()) })
+
+	set := q.Select(ctx, false, nil, labels.MustNewMatcher(labels.MatchEqual, "series_id", "1"))
+	require.False(t, set.Next())
+	require.NoError(t, set.Err())
+
+	// The next run doesn't find any block left to rewrite, so the request is marked as processed.
+	require.NoError(t, c.applyDeleteRequests(ctx, userID, userBucket, log.NewNopLogger()))
+
+	stored, err = mimir_tsdb.ReadDeleteRequest(ctx, userBucket, req.RequestID)
+	require.NoError(t, err)
+	require.Equal(t, mimir_tsdb.DeleteRequestProcessed, stored.State)
+	require.NotZero(t, stored.ProcessedTime)
+}
+
+func TestMultitenantCompactor_applyDeleteRequests_ShouldRemoveProcessedDeleteRequestsAfterGracePeriod(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	bkt := block.BucketWithGlobalMarkers(objstore.NewInMemBucket())
+	userBucket := bucket.NewUserBucketClient(userID, bkt, nil)
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+	c.bucketClient = bkt
+
+	recent, err := mimir_tsdb.NewDeleteRequest([]string{`{series_id="1"}`}, 0, time.Hour.Milliseconds(), time.Now().Add(-2*cfg.DeleteRequestsGracePeriod))
+	require.NoError(t, err)
+	recent.State = mimir_tsdb.DeleteRequestProcessed
+	recent.ProcessedTime = time.Now().Add(-cfg.DeleteRequestsGracePeriod / 2).Unix()
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBucket, recent))
+
+	old, err := mimir_tsdb.NewDeleteRequest([]string{`{series_id="2"}`}, 0, time.Hour.Milliseconds(), time.Now().Add(-3*cfg.DeleteRequestsGracePeriod))
+	require.NoError(t, err)
+	old.State = mimir_tsdb.DeleteRequestProcessed
+	old.ProcessedTime = time.Now().Add(-2 * cfg.DeleteRequestsGracePeriod).Unix()
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBucket, old))
+
+	require.NoError(t, c.applyDeleteRequests(ctx, userID, userBucket, log.NewNopLogger()))
+
+	requests, err := mimir_tsdb.ReadDeleteRequests(ctx, userBucket)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, recent.RequestID, requests[0].RequestID)
+}
+
+func TestMultitenantCompactor_applyDeleteRequests_ShouldRewriteBlocksUploadedAfterTheDeleteRequestIsProcessed(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	bkt := block.BucketWithGlobalMarkers(objstore.NewInMemBucket())
+	userBucket := bucket.NewUserBucketClient(userID, bkt, nil)
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+	c.bucketClient = bkt
+
+	req, err := mimir_tsdb.NewDeleteRequest([]string{`{series_id="1"}`}, 0, time.Hour.Milliseconds(), time.Now().Add(-2*cfg.DeleteRequestsGracePeriod))
+	require.NoError(t, err)
+	req.State = mimir_tsdb.DeleteRequestProcessed
+	req.ProcessedTime = time.Now().Add(-time.Hour).Unix()
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBucket, req))
+
+	// A block containing matching series is uploaded after the delete request has been processed.
+	lateBlockID := createTSDBBlock(t, bkt, userID, 0, 2*time.Hour.Milliseconds(), 5, nil)
+
+	require.NoError(t, c.applyDeleteRequests(ctx, userID, userBucket, log.NewNopLogger()))
+
+	exists, err := bkt.Exists(ctx, path.Join(userID, lateBlockID.String(), block.DeletionMarkFilename))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	stored, err := mimir_tsdb.ReadDeleteRequest(ctx, userBucket, req.RequestID)
+	require.NoError(t, err)
+	require.Equal(t, mimir_tsdb.DeleteRequestProcessed, stored.State)
+	require.Equal(t, req.ProcessedTime, stored.ProcessedTime)
+	require.Len(t, stored.ProcessedBlocks, 1)
+	require.NotEqual(t, lateBlockID, stored.ProcessedBlocks[0])
+}
+
+func TestMultitenantCompactor_applyDeleteRequests_ShouldRewriteBlocksCompactedFromTheOriginalBlock(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	bkt := block.BucketWithGlobalMarkers(objstore.NewInMemBucket())
+	userBucket := bucket.NewUserBucketClient(userID, bkt, nil)
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+	c.bucketClient = bkt
+
+	blockID := createTSDBBlock(t, bkt, userID, 0, 2*time.Hour.Milliseconds(), 5, nil)
+
+	req, err := mimir_tsdb.NewDeleteRequest([]string{`{series_id="1"}`}, 0, time.Hour.Milliseconds(), time.Now())
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBucket, req))
+
+	require.NoError(t, c.applyDeleteRequests(ctx, userID, userBucket, log.NewNopLogger()))
+
+	// The original block keeps the no-compaction mark until it's deleted.
+	exists, err := bkt.Exists(ctx, path.Join(userID, blockID.String(), block.NoCompactMarkFilename))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// A compaction job compacted the original block while it was rewritten: the resulting block has the same
+	// sources of the rewritten block, but still contains the deleted series, so it's rewritten as well.
+	compactedID := createTSDBBlock(t, bkt, userID, 0, 2*time.Hour.Milliseconds(), 5, nil)
+	require.NoError(t, c.applyDeleteRequests(ctx, userID, userBucket, log.NewNopLogger()))
+
+	exists, err = bkt.Exists(ctx, path.Join(userID, compactedID.String(), block.DeletionMarkFilename))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	stored, err := mimir_tsdb.ReadDeleteRequest(ctx, userBucket, req.RequestID)
+	require.NoError(t, err)
+	require.Len(t, stored.ProcessedBlocks, 2)
+	require.NotContains(t, stored.ProcessedBlocks, blockID)
+	require.NotContains(t, stored.ProcessedBlocks, compactedID)
+}
+
+func TestMultitenantCompactor_applyDeleteRequests_ShouldRemoveTheNoCompactionMarkOfBlocksWithoutMatchingSeries(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	bkt := block.BucketWithGlobalMarkers(objstore.NewInMemBucket())
+	userBucket := bucket.NewUserBucketClient(userID, bkt, nil)
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+	c.bucketClient = bkt
+
+	blockID := createTSDBBlock(t, bkt, userID, 0, 2*time.Hour.Milliseconds(), 5, nil)
+
+	req, err := mimir_tsdb.NewDeleteRequest([]string{`{series_id="100"}`}, 0, time.Hour.Milliseconds(), time.Now())
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBucket, req))
+
+	require.NoError(t, c.applyDeleteRequests(ctx, userID, userBucket, log.NewNopLogger()))
+
+	for _, marker := range []string{block.NoCompactMarkFilename, block.DeletionMarkFilename} {
+		exists, err := bkt.Exists(ctx, path.Join(userID, blockID.String(), marker))
+		require.NoError(t, err)
+		require.False(t, exists, marker)
+	}
+
+	stored, err := mimir_tsdb.ReadDeleteRequest(ctx, userBucket, req.RequestID)
+	require.NoError(t, err)
+	require.Equal(t, []ulid.ULID{blockID}, stored.ProcessedBlocks)
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package costattribution
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Limits are the per-tenant limits configuring cost attribution.
+type Limits interface {
+	CostAttributionLabels(userID string) []string
+	MaxCostAttributionCardinalityPerUser(userID string) int
+}
+
+// Manager holds the cost attribution trackers of all tenants, periodically purges the attributions
+// which have not been updated recently, and exports the trackers' metrics. A nil Manager is valid,
+// and returns no tracker.
+type Manager struct {
+	services.Service
+
+	limits          Limits
+	inactiveTimeout time.Duration
+
+	mtx      sync.RWMutex
+	trackers map[string]*Tracker
+}
+
+// NewManager creates a new Manager. Attributions which have not been updated for longer than
+// inactiveTimeout, and have no active series, are purged every cleanupInterval.
+func NewManager(cleanupInterval, inactiveTimeout time.Duration, limits Limits, reg prometheus.Registerer) *Manager {
+	m := &Manager{
+		limits:          limits,
+		inactiveTimeout: inactiveTimeout,
+		trackers:        map[string]*Tracker{},
+	}
+	m.Service = services.NewTimerService(cleanupInterval, nil, m.iteration, nil).WithName("cost attribution cleanup")
+
+	if reg != nil {
+		reg.MustRegister(m)
+	}
+	return m
+}
+
+func (m *Manager) iteration(_ context.Context) error {
+	m.PurgeInactiveAttributions(time.Now().Add(-m.inactiveTimeout))
+	return nil
+}
+
+// Tracker returns the tracker of the tenant, or nil if cost attribution is disabled for the tenant.
+// If the tenant's configuration changed since the tracker was created, a new tracker is returned.
+func (m *Manager) Tracker(userID string) *Tracker {
+	if m == nil {
+		return nil
+	}
+
+	attributionLabels := m.limits.CostAttributionLabels(userID)
+	maxCardinality := m.limits.MaxCostAttributionCardinalityPerUser(userID)
+
+	m.mtx.RLock()
+	t := m.trackers[userID]
+	m.mtx.RUnlock()
+
+	if len(attributionLabels) == 0 {
+		if t != nil {
+			m.RemoveTracker(userID)
+		}
+		return nil
+	}
+	if t != nil && t.hasConfig(attributionLabels, maxCardinality) {
+		return t
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	// Check again, now that we hold the write lock.
+	if t = m.trackers[userID]; t != nil && t.hasConfig(attributionLabels, maxCardinality) {
+		return t
+	}
+	t = newTracker(userID, attributionLabels, maxCardinality)
+	m.trackers[userID] = t
+	return t
+}
+
+// RemoveTracker removes the tracker of the tenant, and its metrics.
+func (m *Manager) RemoveTracker(userID string) {
+	if m == nil {
+		return
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	delete(m.trackers, userID)
+}
+
+// PurgeInactiveAttributions removes the attributions which have not been updated since the deadline,
+// and have no active series.
+func (m *Manager) PurgeInactiveAttributions(deadline time.Time) {
+	m.mtx.RLock()
+	trackers := make([]*Tracker, 0, len(m.trackers))
+	for _, t := range m.trackers {
+		trackers = append(trackers, t)
+	}
+	m.mtx.RUnlock()
+
+	for _, t := range trackers {
+		t.purge(deadline)
+	}
+}
+
+// Describe implements prometheus.Collector. No descriptor is sent, making the Manager an unchecked
+// collector, because the labels of the exported metrics depend on each tenant's configuration.
+func (m *Manager) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (m *Manager) Collect(out chan<- prometheus.Metric) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	for _, t := range m.trackers {
+		t.collect(out)
+	}
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package costattribution
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+type mockLimits struct {
+	labels         map[string][]string
+	maxCardinality int
+}
+
+func (l *mockLimits) CostAttributionLabels(userID string) []string {
+	return l.labels[userID]
+}
+
+func (l *mockLimits) MaxCostAttributionCardinalityPerUser(string) int {
+	return l.maxCardinality
+}
+
+func TestManager_Tracker(t *testing.T) {
+	limits := &mockLimits{labels: map[string][]string{"user-1": {"team"}}, maxCardinality: 10}
+	m := NewManager(time.Minute, time.Minute, limits, nil)
+
+	assert.Nil(t, m.Tracker("user-2"))
+
+	tracker := m.Tracker("user-1")
+	require.NotNil(t, tracker)
+	assert.Equal(t, []string{"team"}, tracker.Labels())
+	assert.Same(t, tracker, m.Tracker("user-1"))
+
+	// A new tracker is returned when the configuration changes.
+	limits.labels["user-1"] = []string{"team", "env"}
+	updated := m.Tracker("user-1")
+	assert.NotSame(t, tracker, updated)
+	assert.Equal(t, []string{"team", "env"}, updated.Labels())
+
+	limits.maxCardinality = 20
+	assert.NotSame(t, updated, m.Tracker("user-1"))
+
+	// The tracker is removed when cost attribution is disabled.
+	delete(limits.labels, "user-1")
+	assert.Nil(t, m.Tracker("user-1"))
+	assert.Empty(t, m.trackers)
+
+	// A nil manager returns no tracker.
+	var nilManager *Manager
+	assert.Nil(t, nilManager.Tracker("user-1"))
+	nilManager.RemoveTracker("user-1")
+}
+
+func TestManager_Collect(t *testing.T) {
+	limits := &mockLimits{labels: map[string][]string{"user-1": {"team"}, "user-2": {"team", "env"}}, maxCardinality: 2}
+	reg := prometheus.NewPedanticRegistry()
+	m := NewManager(time.Minute, time.Minute, limits, reg)
+	now := time.Now()
+
+	series := func(lbls ...string) []mimirpb.LabelAdapter {
+		return mimirpb.FromLabelsToLabelAdapters(labels.FromStrings(lbls...))
+	}
+
+	user1 := m.Tracker("user-1")
+	user1.IncrementReceivedSamples(series("__name__", "metric", "team", "a"), 2, now)
+	user1.IncrementReceivedSamples(series("__name__", "metric", "team", "b"), 3, now)
+	user1.IncrementReceivedSamples(series("__name__", "metric"), 1, now)
+	user1.IncrementDiscardedSamples(series("__name__", "metric", "team", "a"), 1, "rate_limited", now)
+	user1.IncrementDiscardedSamples(series("__name__", "metric", "team", "c"), 1, "rate_limited", now)
+
+	user2 := m.Tracker("user-2")
+	user2.IncrementReceivedSamples(series("__name__", "metric", "team", "a", "env", "prod"), 1, now)
+	user2.SetActiveSeries(map[string]int{
+		user2.Attribute(labels.FromStrings("__name__", "metric", "team", "a", "env", "prod"), now): 5,
+		user2.Attribute(labels.FromStrings("__name__", "metric", "team", "b"), now):                3,
+		user2.Attribute(labels.FromStrings("__name__", "metric", "team", "c", "env", "prod"), now): 1,
+	}, now)
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_discarded_attributed_samples_total The total number of samples discarded per tenant, broken down by reason and by the tenant's cost attribution labels.
+		# TYPE cortex_discarded_attributed_samples_total counter
+		cortex_discarded_attributed_samples_total{reason="rate_limited",team="__overflow__",user="user-1"} 1
+		cortex_discarded_attributed_samples_total{reason="rate_limited",team="a",user="user-1"} 1
+
+		# HELP cortex_distributor_received_attributed_samples_total The total number of samples received per tenant, broken down by the tenant's cost attribution labels.
+		# TYPE cortex_distributor_received_attributed_samples_total counter
+		cortex_distributor_received_attributed_samples_total{team="a",user="user-1"} 2
+		cortex_distributor_received_attributed_samples_total{team="b",user="user-1"} 3
+		cortex_distributor_received_attributed_samples_total{team="__overflow__",user="user-1"} 1
+		cortex_distributor_received_attributed_samples_total{env="prod",team="a",user="user-2"} 1
+
+		# HELP cortex_ingester_attributed_active_series The number of currently active series per tenant, broken down by the tenant's cost attribution labels.
+		# TYPE cortex_ingester_attributed_active_series gauge
+		cortex_ingester_attributed_active_series{env="prod",team="a",user="user-2"} 5
+		cortex_ingester_attributed_active_series{env="",team="b",user="user-2"} 3
+		cortex_ingester_attributed_active_series{env="__overflow__",team="__overflow__",user="user-2"} 1
+	`)))
+
+	m.RemoveTracker("user-1")
+	m.RemoveTracker("user-2")
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader("")))
+}
+
+func TestManager_PurgeInactiveAttributions(t *testing.T) {
+	limits := &mockLimits{labels: map[string][]string{"user-1": {"team"}}, maxCardinality: 3}
+	m := NewManager(time.Minute, time.Minute, limits, nil)
+	now := time.Now()
+
+	tracker := m.Tracker("user-1")
+	tracker.IncrementReceivedSamples([]mimirpb.LabelAdapter{{Name: "team", Value: "a"}}, 1, now.Add(-2*time.Minute))
+	tracker.IncrementReceivedSamples([]mimirpb.LabelAdapter{{Name: "team", Value: "b"}}, 1, now)
+	tracker.SetActiveSeries(map[string]int{tracker.Attribute(labels.FromStrings("team", "c"), now.Add(-2*time.Minute)): 1}, now.Add(-2*time.Minute))
+
+	// The max cardinality has been reached.
+	assert.Equal(t, OverflowValue, tracker.Attribute(labels.FromStrings("team", "d"), now))
+
+	m.PurgeInactiveAttributions(now.Add(-time.Minute))
+
+	// The attribution "a" has been purged, while "c" is kept because it still has active series.
+	assert.ElementsMatch(t, []string{"b", "c", OverflowValue}, attributionKeys(tracker))
+
+	// Purging "a" made room for a new attribution.
+	assert.Equal(t, "d", tracker.Attribute(labels.FromStrings("team", "d"), now))
+	assert.Equal(t, OverflowValue, tracker.Attribute(labels.FromStrings("team", "e"), now))
+}
+
+func attributionKeys(t *Tracker) []string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	keys := make([]string, 0, len(t.attributions))
+	for key := range t.attributions {
+		keys = append(keys, key)
+	}
+	return keys
+}
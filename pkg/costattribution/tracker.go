@@ -0,0 +1,302 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package costattribution
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+const (
+	// OverflowValue is the value of the cost attribution labels of the series accounted to the overflow
+	// attribution, once the max cardinality of a tenant has been reached.
+	OverflowValue = "__overflow__"
+
+	// keySeparator separates the label values in an attribution key. It's not a valid UTF-8 character,
+	// so it can't be part of a label value.
+	keySeparator = "\xff"
+)
+
+// Tracker tracks the samples and active series of a single tenant, broken down by the values of the
+// tenant's cost attribution labels. The number of distinct attributions is capped: once the cap is
+// reached, new attributions are accounted to the overflow attribution, whose labels are all set to
+// OverflowValue. A nil Tracker is valid, and tracks nothing.
+type Tracker struct {
+	userID         string
+	labels         []string
+	maxCardinality int
+	overflowKey    string
+
+	receivedSamplesDesc  *prometheus.Desc
+	discardedSamplesDesc *prometheus.Desc
+	activeSeriesDesc     *prometheus.Desc
+
+	mtx          sync.RWMutex
+	attributions map[string]*attribution // by attribution key
+}
+
+// attribution holds the stats of a single combination of cost attribution label values. The samples are
+// counted with atomics, so that they can be incremented with the tracker's read lock held.
+type attribution struct {
+	key              string
+	values           []string
+	lastUpdate       atomic.Int64 // Unix timestamp in nanoseconds.
+	receivedSamples  atomic.Float64
+	discardedSamples map[string]*atomic.Float64 // by reason, added with the tracker's lock held
+	activeSeries     int
+}
+
+func newTracker(userID string, attributionLabels []string, maxCardinality int) *Tracker {
+	overflowValues := make([]string, len(attributionLabels))
+	for i := range overflowValues {
+		overflowValues[i] = OverflowValue
+	}
+
+	variableLabels := append([]string{"user"}, attributionLabels...)
+	return &Tracker{
+		userID:         userID,
+		labels:         append([]string(nil), attributionLabels...),
+		maxCardinality: maxCardinality,
+		overflowKey:    strings.Join(overflowValues, keySeparator),
+		receivedSamplesDesc: prometheus.NewDesc(
+			"cortex_distributor_received_attributed_samples_total",
+			"The total number of samples received per tenant, broken down by the tenant's cost attribution labels.",
+			variableLabels, nil),
+		discardedSamplesDesc: prometheus.NewDesc(
+			"cortex_discarded_attributed_samples_total",
+			"The total number of samples discarded per tenant, broken down by reason and by the tenant's cost attribution labels.",
+			append(variableLabels, "reason"), nil),
+		activeSeriesDesc: prometheus.NewDesc(
+			"cortex_ingester_attributed_active_series",
+			"The number of currently active series per tenant, broken down by the tenant's cost attribution labels.",
+			variableLabels, nil),
+		attributions: map[string]*attribution{},
+	}
+}
+
+// Labels returns the cost attribution labels of the tracker.
+func (t *Tracker) Labels() []string {
+	if t == nil {
+		return nil
+	}
+	return t.labels
+}
+
+func (t *Tracker) hasConfig(attributionLabels []string, maxCardinality int) bool {
+	if t.maxCardinality != maxCardinality || len(t.labels) != len(attributionLabels) {
+		return false
+	}
+	for i, l := range t.labels {
+		if attributionLabels[i] != l {
+			return false
+		}
+	}
+	return true
+}
+
+// IncrementReceivedSamples accounts the received samples of the series to the series' attribution.
+func (t *Tracker) IncrementReceivedSamples(series []mimirpb.LabelAdapter, samples int, now time.Time) {
+	if t == nil {
+		return
+	}
+
+	_, a := t.lookupAttribution(t.adaptersKey(series), now)
+	a.receivedSamples.Add(float64(samples))
+}
+
+// IncrementDiscardedSamples accounts the samples of the series discarded for the given reason to the series' attribution.
+func (t *Tracker) IncrementDiscardedSamples(series []mimirpb.LabelAdapter, samples int, reason string, now time.Time) {
+	if t == nil {
+		return
+	}
+
+	_, a := t.lookupAttribution(t.adaptersKey(series), now)
+
+	t.mtx.RLock()
+	discarded, ok := a.discardedSamples[reason]
+	t.mtx.RUnlock()
+
+	if !ok {
+		t.mtx.Lock()
+		if discarded, ok = a.discardedSamples[reason]; !ok {
+			if a.discardedSamples == nil {
+				a.discardedSamples = map[string]*atomic.Float64{}
+			}
+			discarded = atomic.NewFloat64(0)
+			a.discardedSamples[reason] = discarded
+		}
+		t.mtx.Unlock()
+	}
+	discarded.Add(float64(samples))
+}
+
+// Attribute returns the key of the attribution of the series, which is the overflow attribution if the
+// max cardinality has been reached. The returned key can be used to report the active series with SetActiveSeries.
+// The tracker is shared by all the active series stripes of the tenant, so it must not be called with a stripe
+// lock held.
+func (t *Tracker) Attribute(series labels.Labels, now time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	key, _ := t.lookupAttribution(t.labelsKey(series), now)
+	return key
+}
+
+// SetActiveSeries sets the number of active series of each attribution, by attribution key as returned
+// by Attribute. The attributions not included in active have no active series.
+func (t *Tracker) SetActiveSeries(active map[string]int, now time.Time) {
+	if t == nil {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for _, a := range t.attributions {
+		a.activeSeries = 0
+	}
+	for key, count := range active {
+		a, ok := t.attributions[key]
+		if !ok {
+			// The attribution has been purged while the series were being tracked, so we recreate it
+			// regardless of the max cardinality, in order to not lose track of the active series.
+			a = newAttribution(strings.Clone(key))
+			t.attributions[a.key] = a
+		}
+		a.activeSeries = count
+		a.lastUpdate.Store(now.UnixNano())
+	}
+}
+
+// purge removes the attributions which have not been updated since the deadline and have no active series.
+func (t *Tracker) purge(deadline time.Time) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	deadlineNanos := deadline.UnixNano()
+	for key, a := range t.attributions {
+		if a.lastUpdate.Load() < deadlineNanos && a.activeSeries == 0 {
+			delete(t.attributions, key)
+		}
+	}
+}
+
+// lookupAttribution is like getOrCreateAttribution, but the attributions which already exist are looked up with
+// the read lock only, so that the series of the tenant's concurrent requests don't contend for the lock.
+func (t *Tracker) lookupAttribution(key string, now time.Time) (string, *attribution) {
+	t.mtx.RLock()
+	a, ok := t.attributions[key]
+	if ok {
+		// The last update is stored with the lock held, so that the attribution can't be purged meanwhile.
+		a.lastUpdate.Store(now.UnixNano())
+	}
+	t.mtx.RUnlock()
+
+	if ok {
+		return a.key, a
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.getOrCreateAttribution(key, now)
+}
+
+// getOrCreateAttribution returns the attribution with the given key, or the overflow attribution if the
+// attribution doesn't exist and the max cardinality has been reached, along with the key of the returned
+// attribution. Must be called with the lock held.
+func (t *Tracker) getOrCreateAttribution(key string, now time.Time) (string, *attribution) {
+	a, ok := t.attributions[key]
+	if !ok {
+		if t.maxCardinality > 0 && t.cardinality() >= t.maxCardinality {
+			key = t.overflowKey
+			a, ok = t.attributions[key]
+		}
+		if !ok {
+			// The key may reference the request buffer, so we copy it before retaining it.
+			a = newAttribution(strings.Clone(key))
+			t.attributions[a.key] = a
+		}
+	}
+
+	a.lastUpdate.Store(now.UnixNano())
+	return a.key, a
+}
+
+func newAttribution(key string) *attribution {
+	return &attribution{key: key, values: strings.Split(key, keySeparator)}
+}
+
+// cardinality returns the number of attributions, excluding the overflow one. Must be called with the lock held.
+func (t *Tracker) cardinality() int {
+	if _, ok := t.attributions[t.overflowKey]; ok {
+		return len(t.attributions) - 1
+	}
+	return len(t.attributions)
+}
+
+func (t *Tracker) adaptersKey(series []mimirpb.LabelAdapter) string {
+	if len(t.labels) == 1 {
+		return adapterValue(series, t.labels[0])
+	}
+
+	sb := strings.Builder{}
+	for i, l := range t.labels {
+		if i > 0 {
+			sb.WriteString(keySeparator)
+		}
+		sb.WriteString(adapterValue(series, l))
+	}
+	return sb.String()
+}
+
+func (t *Tracker) labelsKey(series labels.Labels) string {
+	if len(t.labels) == 1 {
+		return series.Get(t.labels[0])
+	}
+
+	sb := strings.Builder{}
+	for i, l := range t.labels {
+		if i > 0 {
+			sb.WriteString(keySeparator)
+		}
+		sb.WriteString(series.Get(l))
+	}
+	return sb.String()
+}
+
+func adapterValue(series []mimirpb.LabelAdapter, name string) string {
+	for _, l := range series {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+func (t *Tracker) collect(out chan<- prometheus.Metric) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	for _, a := range t.attributions {
+		labelValues := append([]string{t.userID}, a.values...)
+
+		if received := a.receivedSamples.Load(); received > 0 {
+			out <- prometheus.MustNewConstMetric(t.receivedSamplesDesc, prometheus.CounterValue, received, labelValues...)
+		}
+		for reason, discarded := range a.discardedSamples {
+			out <- prometheus.MustNewConstMetric(t.discardedSamplesDesc, prometheus.CounterValue, discarded.Load(), append(labelValues, reason)...)
+		}
+		if a.activeSeries > 0 {
+			out <- prometheus.MustNewConstMetric(t.activeSeriesDesc, prometheus.GaugeValue, float64(a.activeSeries), labelValues...)
+		}
+	}
+}
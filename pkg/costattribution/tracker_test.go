@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package costattribution
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+func TestTracker_ConcurrentIncrements(t *testing.T) {
+	const (
+		concurrency = 10
+		iterations  = 1000
+	)
+
+	limits := &mockLimits{labels: map[string][]string{"user-1": {"team"}}, maxCardinality: 10}
+	reg := prometheus.NewPedanticRegistry()
+	m := NewManager(time.Minute, time.Minute, limits, reg)
+	tracker := m.Tracker("user-1")
+	now := time.Now()
+
+	wg := sync.WaitGroup{}
+	wg.Add(concurrency)
+	for c := 0; c < concurrency; c++ {
+		go func(c int) {
+			defer wg.Done()
+
+			series := mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", "metric", "team", fmt.Sprintf("%d", c%2)))
+			for i := 0; i < iterations; i++ {
+				tracker.IncrementReceivedSamples(series, 2, now)
+				tracker.IncrementDiscardedSamples(series, 1, fmt.Sprintf("reason-%d", i%2), now)
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_discarded_attributed_samples_total The total number of samples discarded per tenant, broken down by reason and by the tenant's cost attribution labels.
+		# TYPE cortex_discarded_attributed_samples_total counter
+		cortex_discarded_attributed_samples_total{reason="reason-0",team="0",user="user-1"} 2500
+		cortex_discarded_attributed_samples_total{reason="reason-1",team="0",user="user-1"} 2500
+		cortex_discarded_attributed_samples_total{reason="reason-0",team="1",user="user-1"} 2500
+		cortex_discarded_attributed_samples_total{reason="reason-1",team="1",user="user-1"} 2500
+
+		# HELP cortex_distributor_received_attributed_samples_total The total number of samples received per tenant, broken down by the tenant's cost attribution labels.
+		# TYPE cortex_distributor_received_attributed_samples_total counter
+		cortex_distributor_received_attributed_samples_total{team="0",user="user-1"} 10000
+		cortex_distributor_received_attributed_samples_total{team="1",user="user-1"} 10000
+	`)))
+}
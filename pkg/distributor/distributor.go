@@ -33,15 +33,16 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
-	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/prometheus/prometheus/scrape"
 	"go.uber.org/atomic"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/grafana/mimir/pkg/cardinality"
+	"github.com/grafana/mimir/pkg/costattribution"
 	ingester_client "github.com/grafana/mimir/pkg/ingester/client"
 	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/streamaggr"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/globalerror"
 	util_log "github.com/grafana/mimir/pkg/util/log"
@@ -73,6 +74,10 @@ const (
 
 	instanceIngestionRateTickInterval = time.Second
 
+	// costAttributionInactiveTimeout is how long a cost attribution is kept after the last sample accounted to it.
+	costAttributionInactiveTimeout = 15 * time.Minute
+	costAttributionCleanupInterval = 3 * time.Minute
+
 	// Size of "slab" when using pooled buffers for marshaling write requests. When handling single Push request
 	// buffers for multiple write requests sent to ingesters will be allocated from single "slab", if there is enough space.
 	writeRequestSlabPoolSize = 512 * 1024
@@ -97,10 +102,16 @@ type Distributor struct {
 	// For handling HA replicas.
 	HATracker *haTracker
 
+	// Metrics of the push handlers, shared by all of them.
+	PushMetrics *PushMetrics
+
 	// Per-user rate limiters.
 	requestRateLimiter   *limiter.RateLimiter
 	ingestionRateLimiter *limiter.RateLimiter
 
+	// Per-user rate limiter of the samples forwarded from the non-elected HA replicas.
+	haSampleLevelDedupRateLimiter *limiter.RateLimiter
+
 	// Manager for subservices (HA Tracker, distributor ring and client pool)
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
@@ -108,6 +119,12 @@ type Distributor struct {
 	activeUsers  *util.ActiveUsersCleanupService
 	activeGroups *util.ActiveGroupsCleanupService
 
+	// Per-tenant received and discarded samples, broken down by the tenant's cost attribution labels.
+	costAttribution *costattribution.Manager
+
+	// Routes the series matching the tenants' streaming aggregation rules to the ingesters aggregating them.
+	streamAggregationRules *streamaggr.Rules
+
 	ingestionRate             *util_math.EwmaRate
 	inflightPushRequests      atomic.Int64
 	inflightPushRequestsBytes atomic.Int64
@@ -124,6 +141,11 @@ type Distributor struct {
 	incomingMetadata                 *prometheus.CounterVec
 	nonHASamples                     *prometheus.CounterVec
 	dedupedSamples                   *prometheus.CounterVec
+	nonElectedReplicaSamples         *prometheus.CounterVec
+	relabelSeriesChanged             *prometheus.CounterVec
+	relabelSeriesDropped             *prometheus.CounterVec
+	streamAggregationSamples         *prometheus.CounterVec
+	streamAggregationDroppedSeries   *prometheus.CounterVec
 	labelsHistogram                  prometheus.Histogram
 	sampleDelayHistogram             prometheus.Histogram
 	replicationFactor                prometheus.Gauge
@@ -180,6 +202,10 @@ type Config struct {
 	DefaultLimits    InstanceLimits         `yaml:"instance_limits"`
 	InstanceLimitsFn func() *InstanceLimits `yaml:"-"`
 
+	// ReadOnlyIngesters tells which ingesters are in read-only mode. The read-only ingesters are LEAVING, and
+	// are queried until they leave the ring, while the other LEAVING ingesters aren't queried. Optional.
+	ReadOnlyIngesters ReadOnlyIngesters `yaml:"-"`
+
 	// This allows downstream projects to wrap the distributor push function
 	// and access the deserialized write requests before/after they are pushed.
 	// These functions will only receive samples that don't get dropped by HA deduplication.
@@ -188,6 +214,12 @@ type Config struct {
 	WriteRequestsBufferPoolingEnabled bool `yaml:"write_requests_buffer_pooling_enabled" category:"experimental"`
 }
 
+// ReadOnlyIngesters tells which ingesters are in read-only mode.
+type ReadOnlyIngesters interface {
+	// IsReadOnly returns whether the instance is in read-only mode, and since when.
+	IsReadOnly(instanceID string) (time.Time, bool)
+}
+
 // PushWrapper wraps around a push. It is similar to middleware.Interface.
 type PushWrapper func(next PushFunc) PushFunc
 
@@ -246,6 +278,7 @@ func New(cfg Config, clientConfig ingester_client.Config, limits *validation.Ove
 		healthyInstancesCount: atomic.NewUint32(0),
 		limits:                limits,
 		HATracker:             haTracker,
+		PushMetrics:           newPushMetrics(reg),
 		ingestionRate:         util_math.NewEWMARate(0.2, instanceIngestionRateTickInterval),
 
 		queryDuration: instrument.NewHistogramCollector(promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
@@ -293,6 +326,26 @@ func New(cfg Config, clientConfig ingester_client.Config, limits *validation.Ove
 			Name: "cortex_distributor_deduped_samples_total",
 			Help: "The total number of deduplicated samples.",
 		}, []string{"user", "cluster"}),
+		nonElectedReplicaSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_non_elected_replica_samples_forwarded_total",
+			Help: "The total number of samples received from a non-elected HA replica and forwarded to the ingesters, which only append the samples of the series the elected replica stopped sending.",
+		}, []string{"user", "cluster"}),
+		streamAggregationSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_streaming_aggregation_forwarded_samples_total",
+			Help: "The total number of samples forwarded to the ingesters aggregating the output series of the streaming aggregation rules.",
+		}, []string{"user"}),
+		streamAggregationDroppedSeries: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_streaming_aggregation_dropped_input_series_total",
+			Help: "The total number of input series dropped after being forwarded to the streaming aggregation rules.",
+		}, []string{"user"}),
+		relabelSeriesChanged: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_relabel_series_changed_total",
+			Help: "The total number of series whose labels have been changed by a per-tenant relabel rule.",
+		}, []string{"user", "rule"}),
+		relabelSeriesDropped: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_relabel_series_dropped_total",
+			Help: "The total number of series dropped by a per-tenant relabel rule.",
+		}, []string{"user", "rule"}),
 		labelsHistogram: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
 			Name:    "cortex_labels_per_sample",
 			Help:    "Number of labels per sample.",
@@ -393,13 +446,14 @@ func New(cfg Config, clientConfig ingester_client.Config, limits *validation.Ove
 	// Create the configured ingestion rate limit strategy (local or global). In case
 	// it's an internal dependency and we can't join the distributors ring, we skip rate
 	// limiting.
-	var ingestionRateStrategy, requestRateStrategy limiter.RateLimiterStrategy
+	var ingestionRateStrategy, requestRateStrategy, haSampleLevelDedupRateStrategy limiter.RateLimiterStrategy
 	var distributorsLifecycler *ring.BasicLifecycler
 	var distributorsRing *ring.Ring
 
 	if !canJoinDistributorsRing {
 		requestRateStrategy = newInfiniteRateStrategy()
 		ingestionRateStrategy = newInfiniteRateStrategy()
+		haSampleLevelDedupRateStrategy = newHASampleLevelDedupRateStrategy(limits)
 	} else {
 		distributorsRing, distributorsLifecycler, err = newRingAndLifecycler(cfg.DistributorRing, d.healthyInstancesCount, log, reg)
 		if err != nil {
@@ -409,20 +463,24 @@ func New(cfg Config, clientConfig ingester_client.Config, limits *validation.Ove
 		subservices = append(subservices, distributorsLifecycler, distributorsRing)
 		requestRateStrategy = newGlobalRateStrategy(newRequestRateStrategy(limits), d)
 		ingestionRateStrategy = newGlobalRateStrategy(newIngestionRateStrategy(limits), d)
+		haSampleLevelDedupRateStrategy = newGlobalRateStrategy(newHASampleLevelDedupRateStrategy(limits), d)
 	}
 
 	d.requestRateLimiter = limiter.NewRateLimiter(requestRateStrategy, 10*time.Second)
 	d.ingestionRateLimiter = limiter.NewRateLimiter(ingestionRateStrategy, 10*time.Second)
+	d.haSampleLevelDedupRateLimiter = limiter.NewRateLimiter(haSampleLevelDedupRateStrategy, 10*time.Second)
 	d.distributorsLifecycler = distributorsLifecycler
 	d.distributorsRing = distributorsRing
 
 	d.replicationFactor.Set(float64(ingestersRing.ReplicationFactor()))
 	d.activeUsers = util.NewActiveUsersCleanupWithDefaultValues(d.cleanupInactiveUser)
 	d.activeGroups = activeGroupsCleanupService
+	d.costAttribution = costattribution.NewManager(costAttributionCleanupInterval, costAttributionInactiveTimeout, limits, reg)
+	d.streamAggregationRules = streamaggr.NewRules(limits)
 
 	d.PushWithMiddlewares = d.wrapPushWithMiddlewares(d.push)
 
-	subservices = append(subservices, d.ingesterPool, d.activeUsers)
+	subservices = append(subservices, d.ingesterPool, d.activeUsers, d.costAttribution)
 	d.subservices, err = services.NewManager(subservices...)
 	if err != nil {
 		return nil, err
@@ -520,6 +578,9 @@ func (d *Distributor) cleanupInactiveUser(userID string) {
 
 	filter := prometheus.Labels{"user": userID}
 	d.dedupedSamples.DeletePartialMatch(filter)
+	d.nonElectedReplicaSamples.DeletePartialMatch(filter)
+	d.relabelSeriesChanged.DeletePartialMatch(filter)
+	d.relabelSeriesDropped.DeletePartialMatch(filter)
 	d.discardedSamplesTooManyHaClusters.DeletePartialMatch(filter)
 	d.discardedSamplesRateLimited.DeletePartialMatch(filter)
 	d.discardedRequestsRateLimited.DeleteLabelValues(userID)
@@ -529,6 +590,10 @@ func (d *Distributor) cleanupInactiveUser(userID string) {
 	d.sampleValidationMetrics.deleteUserMetrics(userID)
 	d.exemplarValidationMetrics.deleteUserMetrics(userID)
 	d.metadataValidationMetrics.deleteUserMetrics(userID)
+
+	d.costAttribution.RemoveTracker(userID)
+	d.streamAggregationSamples.DeleteLabelValues(userID)
+	d.streamAggregationDroppedSeries.DeleteLabelValues(userID)
 }
 
 func (d *Distributor) RemoveGroupMetricsForUser(userID, group string) {
@@ -605,8 +670,8 @@ func (d *Distributor) checkSample(ctx context.Context, userID, cluster, replica
 // May alter timeseries data in-place.
 // The returned error may retain the series labels.
 // It uses the passed nowt time to observe the delay of sample timestamps.
-func (d *Distributor) validateSeries(nowt time.Time, ts *mimirpb.PreallocTimeseries, userID, group string, skipLabelNameValidation bool, minExemplarTS, maxExemplarTS int64) error {
-	if err := validateLabels(d.sampleValidationMetrics, d.limits, userID, group, ts.Labels, skipLabelNameValidation); err != nil {
+func (d *Distributor) validateSeries(nowt time.Time, ts *mimirpb.PreallocTimeseries, userID, group string, cat *costattribution.Tracker, skipLabelNameValidation bool, minExemplarTS, maxExemplarTS int64) error {
+	if err := validateLabels(d.sampleValidationMetrics, d.limits, userID, group, ts.Labels, skipLabelNameValidation, cat, nowt); err != nil {
 		return err
 	}
 
@@ -619,18 +684,19 @@ func (d *Distributor) validateSeries(nowt time.Time, ts *mimirpb.PreallocTimeser
 			d.sampleDelayHistogram.Observe(float64(delta) / 1000)
 		}
 
-		if err := validateSample(d.sampleValidationMetrics, now, d.limits, userID, group, ts.Labels, s); err != nil {
+		if err := validateSample(d.sampleValidationMetrics, now, d.limits, userID, group, ts.Labels, s, cat); err != nil {
 			return err
 		}
 	}
 
-	for _, h := range ts.Histograms {
+	for i := range ts.Histograms {
+		h := &ts.Histograms[i]
 		delta := now - model.Time(h.Timestamp)
 		if delta > 0 {
 			d.sampleDelayHistogram.Observe(float64(delta) / 1000)
 		}
 
-		if err := validateSampleHistogram(d.sampleValidationMetrics, now, d.limits, userID, group, ts.Labels, h); err != nil {
+		if err := validateSampleHistogram(d.sampleValidationMetrics, now, d.limits, userID, group, ts.Labels, h, cat); err != nil {
 			return err
 		}
 	}
@@ -672,6 +738,7 @@ func (d *Distributor) wrapPushWithMiddlewares(next PushFunc) PushFunc {
 	middlewares = append(middlewares, d.prePushHaDedupeMiddleware)
 	middlewares = append(middlewares, d.prePushRelabelMiddleware)
 	middlewares = append(middlewares, d.prePushValidationMiddleware)
+	middlewares = append(middlewares, d.prePushStreamingAggregationMiddleware)
 	middlewares = append(middlewares, d.cfg.PushWrappers...)
 
 	for ix := len(middlewares) - 1; ix >= 0; ix-- {
@@ -725,6 +792,18 @@ func (d *Distributor) prePushHaDedupeMiddleware(next PushFunc) PushFunc {
 		removeReplica, err := d.checkSample(ctx, userID, cluster, replica)
 		if err != nil {
 			if errors.As(err, &replicasDidNotMatchError{}) {
+				if window := d.limits.HASampleLevelDedupWindow(userID); window > 0 && d.haSampleLevelDedupRateLimiter.AllowN(time.Now(), userID, numSamples) {
+					// The ingesters only append the samples of the series that the elected replica stopped sending.
+					for ix := range req.Timeseries {
+						req.Timeseries[ix].RemoveLabel(haReplicaLabel)
+					}
+					req.NonElectedReplicaWindowMs = window.Milliseconds()
+
+					d.nonElectedReplicaSamples.WithLabelValues(userID, cluster).Add(float64(numSamples))
+					cleanupInDefer = false
+					return next(ctx, pushReq)
+				}
+
 				// These samples have been deduped.
 				d.dedupedSamples.WithLabelValues(userID, cluster).Add(float64(numSamples))
 			}
@@ -772,31 +851,20 @@ func (d *Distributor) prePushRelabelMiddleware(next PushFunc) PushFunc {
 			return err
 		}
 
+		mrc := d.limits.MetricRelabelConfigs(userID)
+		dropLabels := d.limits.DropLabels(userID)
+		onRule := func(rule string, dropped bool) {
+			if dropped {
+				d.relabelSeriesDropped.WithLabelValues(userID, rule).Inc()
+			} else {
+				d.relabelSeriesChanged.WithLabelValues(userID, rule).Inc()
+			}
+		}
+
 		var removeTsIndexes []int
 		lb := labels.NewBuilder(labels.EmptyLabels())
 		for tsIdx := 0; tsIdx < len(req.Timeseries); tsIdx++ {
-			ts := req.Timeseries[tsIdx]
-
-			if mrc := d.limits.MetricRelabelConfigs(userID); len(mrc) > 0 {
-				mimirpb.FromLabelAdaptersToBuilder(ts.Labels, lb)
-				lb.Set(metaLabelTenantID, userID)
-				keep := relabel.ProcessBuilder(lb, mrc...)
-				if !keep {
-					removeTsIndexes = append(removeTsIndexes, tsIdx)
-					continue
-				}
-				lb.Del(metaLabelTenantID)
-				req.Timeseries[tsIdx].SetLabels(mimirpb.FromBuilderToLabelAdapters(lb, ts.Labels))
-			}
-
-			for _, labelName := range d.limits.DropLabels(userID) {
-				req.Timeseries[tsIdx].RemoveLabel(labelName)
-			}
-
-			// Prometheus strips empty values before storing; drop them now, before sharding to ingesters.
-			req.Timeseries[tsIdx].RemoveEmptyLabelValues()
-
-			if len(ts.Labels) == 0 {
+			if !relabelTimeseries(&req.Timeseries[tsIdx], lb, userID, mrc, dropLabels, onRule) {
 				removeTsIndexes = append(removeTsIndexes, tsIdx)
 				continue
 			}
@@ -846,6 +914,7 @@ func (d *Distributor) prePushValidationMiddleware(next PushFunc) PushFunc {
 		d.activeUsers.UpdateUserTimestamp(userID, now)
 
 		group := d.activeGroups.UpdateActiveGroupTimestamp(userID, validation.GroupLabel(d.limits, userID, req.Timeseries), now)
+		cat := d.costAttribution.Tracker(userID)
 
 		// A WriteRequest can only contain series or metadata but not both. This might change in the future.
 		validatedMetadata := 0
@@ -892,7 +961,7 @@ func (d *Distributor) prePushValidationMiddleware(next PushFunc) PushFunc {
 
 			skipLabelNameValidation := d.cfg.SkipLabelNameValidation || req.GetSkipLabelNameValidation()
 			// Note that validateSeries may drop some data in ts.
-			validationErr := d.validateSeries(now, &req.Timeseries[tsIdx], userID, group, skipLabelNameValidation, minExemplarTS, maxExemplarTS)
+			validationErr := d.validateSeries(now, &req.Timeseries[tsIdx], userID, group, cat, skipLabelNameValidation, minExemplarTS, maxExemplarTS)
 
 			// Errors in validation are considered non-fatal, as one series in a request may contain
 			// invalid data but all the remaining series could be perfectly valid.
@@ -940,6 +1009,9 @@ func (d *Distributor) prePushValidationMiddleware(next PushFunc) PushFunc {
 		totalN := validatedSamples + validatedExemplars + validatedMetadata
 		if !d.ingestionRateLimiter.AllowN(now, userID, totalN) {
 			d.discardedSamplesRateLimited.WithLabelValues(userID, group).Add(float64(validatedSamples))
+			for _, ts := range req.Timeseries {
+				cat.IncrementDiscardedSamples(ts.Labels, len(ts.Samples)+len(ts.Histograms), reasonRateLimited, now)
+			}
 			d.discardedExemplarsRateLimited.WithLabelValues(userID).Add(float64(validatedExemplars))
 			d.discardedMetadataRateLimited.WithLabelValues(userID).Add(float64(validatedMetadata))
 			return newIngestionRateLimitedError(d.limits.IngestionRate(userID), d.limits.IngestionBurstSize(userID))
@@ -1159,6 +1231,9 @@ func (d *Distributor) push(ctx context.Context, pushReq *Request) error {
 	copy(keys, seriesKeys)
 	copy(keys[initialMetadataIndex:], metadataKeys)
 
+	// The request can't be read once DoBatch returns, because it may be cleaned up in the meanwhile.
+	written := remoteWriteV2WrittenStats(ctx, req)
+
 	// we must not re-use buffers now until all DoBatch goroutines have finished,
 	// so set this flag false and pass cleanup() to DoBatch.
 	cleanupInDefer = false
@@ -1189,12 +1264,15 @@ func (d *Distributor) push(ctx context.Context, pushReq *Request) error {
 			}
 		}
 
-		err := d.send(localCtx, ingester, timeseries, metadata, req.Source)
+		err := d.send(localCtx, ingester, timeseries, metadata, req.Source, req.NonElectedReplicaWindowMs)
 		if errors.Is(err, context.DeadlineExceeded) {
 			return httpgrpc.Errorf(500, "exceeded configured distributor remote timeout: %s", err.Error())
 		}
 		return err
 	}, func() { pushReq.CleanUp(); cancel() })
+	if err == nil {
+		recordRemoteWriteV2Written(ctx, written)
+	}
 
 	return err
 }
@@ -1220,9 +1298,12 @@ func (d *Distributor) getTokensForSeries(userID string, series []mimirpb.Preallo
 
 func (d *Distributor) updateReceivedMetrics(req *mimirpb.WriteRequest, userID string) {
 	var receivedSamples, receivedExemplars, receivedMetadata int
+	cat := d.costAttribution.Tracker(userID)
+	now := mtime.Now()
 	for _, ts := range req.Timeseries {
 		receivedSamples += len(ts.TimeSeries.Samples) + len(ts.TimeSeries.Histograms)
 		receivedExemplars += len(ts.TimeSeries.Exemplars)
+		cat.IncrementReceivedSamples(ts.Labels, len(ts.TimeSeries.Samples)+len(ts.TimeSeries.Histograms), now)
 	}
 	receivedMetadata = len(req.Metadata)
 
@@ -1235,7 +1316,7 @@ func copyString(s string) string {
 	return string([]byte(s))
 }
 
-func (d *Distributor) send(ctx context.Context, ingester ring.InstanceDesc, timeseries []mimirpb.PreallocTimeseries, metadata []*mimirpb.MetricMetadata, source mimirpb.WriteRequest_SourceEnum) error {
+func (d *Distributor) send(ctx context.Context, ingester ring.InstanceDesc, timeseries []mimirpb.PreallocTimeseries, metadata []*mimirpb.MetricMetadata, source mimirpb.WriteRequest_SourceEnum, nonElectedReplicaWindowMs int64) error {
 	h, err := d.ingesterPool.GetClientForInstance(ingester)
 	if err != nil {
 		return err
@@ -1246,6 +1327,8 @@ func (d *Distributor) send(ctx context.Context, ingester ring.InstanceDesc, time
 		Timeseries: timeseries,
 		Metadata:   metadata,
 		Source:     source,
+
+		NonElectedReplicaWindowMs: nonElectedReplicaWindowMs,
 	}
 
 	_, err = c.Push(ctx, &req)
@@ -1725,6 +1808,58 @@ func (d *Distributor) MetricsForLabelMatchers(ctx context.Context, from, through
 	return result, nil
 }
 
+// ActiveSeries queries the ingesters for the series matching the matchers and tracked as
+// active, and returns them deduplicated across the replicas.
+func (d *Distributor) ActiveSeries(ctx context.Context, matchers []*labels.Matcher) ([]labels.Labels, error) {
+	replicationSet, err := d.GetIngesters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := ingester_client.ToActiveSeriesRequest(matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	resps, err := forReplicationSet(ctx, d, replicationSet, func(ctx context.Context, client ingester_client.IngesterClient) ([]labels.Labels, error) {
+		stream, err := client.ActiveSeries(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		defer stream.CloseSend() //nolint:errcheck
+
+		var series []labels.Labels
+		for {
+			msg, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			for _, m := range msg.Metric {
+				series = append(series, mimirpb.FromLabelAdaptersToLabels(m.Labels))
+			}
+		}
+		return series, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := map[uint64]labels.Labels{}
+	for _, resp := range resps {
+		for _, m := range resp {
+			metrics[labels.StableHash(m)] = m
+		}
+	}
+
+	result := make([]labels.Labels, 0, len(metrics))
+	for _, m := range metrics {
+		result = append(result, m)
+	}
+	return result, nil
+}
+
 // MetricsMetadata returns all metric metadata of a user.
 func (d *Distributor) MetricsMetadata(ctx context.Context, req *ingester_client.MetricsMetadataRequest) ([]scrape.MetricMetadata, error) {
 	replicationSet, err := d.GetIngesters(ctx)
@@ -1843,11 +1978,14 @@ func (d *Distributor) AllUserStats(ctx context.Context) ([]UserIDStats, error) {
 	req := &ingester_client.UserStatsRequest{}
 	ctx = user.InjectOrgID(ctx, "1") // fake: ingester insists on having an org ID
 	// Not using d.forReplicationSet(), so we can fail after first error.
-	replicationSet, err := d.ingestersRing.GetAllHealthy(readNoExtend)
+	replicationSet, err := d.ingestersRing.GetAllHealthy(d.readOperation())
 	if err != nil {
 		return nil, err
 	}
 	for _, ingester := range replicationSet.Instances {
+		if !d.isQueryable(ingester) {
+			continue
+		}
 		client, err := d.ingesterPool.GetClientForInstance(ingester)
 		if err != nil {
 			return nil, err
@@ -821,6 +821,68 @@ func TestDistributor_PushInstanceLimits(t *testing.T) {
 	}
 }
 
+func TestDistributor_PushHAInstances_SampleLevelDedup(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "user")
+
+	var limits validation.Limits
+	flagext.DefaultValues(&limits)
+	limits.AcceptHASamples = true
+	limits.HASampleLevelDedupWindow = model.Duration(time.Minute)
+	// The burst is 5 samples, and each request has 4 samples, since each generated series has a float and a histogram sample.
+	limits.HASampleLevelDedupMaxSamplesPerSecond = 0.5
+
+	ds, _, regs := prepare(t, prepConfig{
+		numIngesters:    3,
+		happyIngesters:  3,
+		numDistributors: 1,
+		limits:          &limits,
+		enableTracker:   true,
+	})
+	d := ds[0]
+
+	require.NoError(t, d.HATracker.checkReplica(ctx, "user", "cluster0", "instance0", time.Now()))
+
+	var forwarded *mimirpb.WriteRequest
+	next := func(_ context.Context, pushReq *Request) error {
+		req, err := pushReq.WriteRequest()
+		require.NoError(t, err)
+		forwarded = req
+		return nil
+	}
+	push := d.prePushHaDedupeMiddleware(next)
+
+	// The series of the non-elected replica are forwarded to the ingesters, which only append the samples of
+	// the series that the elected replica stopped sending.
+	require.NoError(t, push(ctx, NewParsedRequest(makeWriteRequestForGenerators(2, labelSetGenWithReplicaAndCluster("instance1", "cluster0"), nil, nil))))
+	require.NotNil(t, forwarded)
+	assert.Equal(t, time.Minute.Milliseconds(), forwarded.NonElectedReplicaWindowMs)
+	for _, s := range forwarded.Timeseries {
+		lbls := mimirpb.FromLabelAdaptersToLabels(s.Labels)
+		assert.False(t, lbls.Has("__replica__"), lbls.String())
+	}
+
+	// The samples of the non-elected replica exceeding the rate limit are deduplicated.
+	forwarded = nil
+	err := push(ctx, NewParsedRequest(makeWriteRequestForGenerators(2, labelSetGenWithReplicaAndCluster("instance1", "cluster0"), nil, nil)))
+	assert.ErrorAs(t, err, &replicasDidNotMatchError{})
+	assert.Nil(t, forwarded)
+
+	// The series of the elected replica are forwarded as usual.
+	require.NoError(t, push(ctx, NewParsedRequest(makeWriteRequestForGenerators(2, labelSetGenWithReplicaAndCluster("instance0", "cluster0"), nil, nil))))
+	require.NotNil(t, forwarded)
+	assert.Zero(t, forwarded.NonElectedReplicaWindowMs)
+
+	assert.NoError(t, testutil.GatherAndCompare(regs[0], strings.NewReader(`
+		# HELP cortex_distributor_deduped_samples_total The total number of deduplicated samples.
+		# TYPE cortex_distributor_deduped_samples_total counter
+		cortex_distributor_deduped_samples_total{cluster="cluster0",user="user"} 4
+
+		# HELP cortex_distributor_non_elected_replica_samples_forwarded_total The total number of samples received from a non-elected HA replica and forwarded to the ingesters, which only append the samples of the series the elected replica stopped sending.
+		# TYPE cortex_distributor_non_elected_replica_samples_forwarded_total counter
+		cortex_distributor_non_elected_replica_samples_forwarded_total{cluster="cluster0",user="user"} 4
+	`), "cortex_distributor_deduped_samples_total", "cortex_distributor_non_elected_replica_samples_forwarded_total"))
+}
+
 func TestDistributor_PushHAInstances(t *testing.T) {
 	ctx := user.InjectOrgID(context.Background(), "user")
 
@@ -1264,6 +1326,26 @@ func TestDistributor_Push_LabelNameValidation(t *testing.T) {
 	}
 }
 
+func TestDistributor_Push_RemoteWriteV2WrittenStats(t *testing.T) {
+	ds, _, _ := prepare(t, prepConfig{
+		numIngesters:    2,
+		happyIngesters:  2,
+		numDistributors: 1,
+	})
+
+	req := makeWriteRequest(100000, 2, 0, true, false)
+	invalid := mockWriteRequest(labels.FromStrings(model.MetricNameLabel, "foo", "999.illegal", "baz"), 42, 100000)
+	req.Timeseries = append(req.Timeseries, invalid.Timeseries...)
+
+	// The invalid series is discarded, as well as the exemplars because they are disabled by default,
+	// and so they aren't counted as written.
+	stats := &remoteWriteV2Stats{}
+	ctx := contextWithRemoteWriteV2Stats(user.InjectOrgID(context.Background(), "user"), stats)
+	_, err := ds[0].Push(ctx, req)
+	require.Error(t, err)
+	assert.Equal(t, remoteWriteV2Stats{samples: 2}, *stats)
+}
+
 func TestDistributor_Push_ExemplarValidation(t *testing.T) {
 	ctx := user.InjectOrgID(context.Background(), "user")
 	manyLabels := []string{model.MetricNameLabel, "test"}
@@ -1343,10 +1425,11 @@ func TestDistributor_Push_HistogramValidation(t *testing.T) {
 	require.Equal(t, 8, len(testHistogram.PositiveBuckets)+len(testHistogram.NegativeBuckets), "selftest, check generator drift")
 
 	tests := map[string]struct {
-		req         *mimirpb.WriteRequest
-		errMsg      string
-		errID       globalerror.ID
-		bucketLimit int
+		req                  *mimirpb.WriteRequest
+		errMsg               string
+		errID                globalerror.ID
+		bucketLimit          int
+		reduceOverMaxBuckets bool
 	}{
 		"valid histogram": {
 			req: makeWriteRequestHistogram([]string{model.MetricNameLabel, "test"}, 1000, generateTestHistogram(0)),
@@ -1374,6 +1457,11 @@ func TestDistributor_Push_HistogramValidation(t *testing.T) {
 			errMsg:      "received a native histogram sample with too many buckets, timestamp",
 			errID:       globalerror.MaxNativeHistogramBuckets,
 		},
+		"buckets over limit with resolution reduction": {
+			req:                  makeWriteRequestFloatHistogram([]string{model.MetricNameLabel, "test"}, 1000, testHistogram),
+			bucketLimit:          7,
+			reduceOverMaxBuckets: true,
+		},
 	}
 
 	for testName, tc := range tests {
@@ -1382,6 +1470,7 @@ func TestDistributor_Push_HistogramValidation(t *testing.T) {
 			flagext.DefaultValues(limits)
 			limits.CreationGracePeriod = model.Duration(time.Minute)
 			limits.MaxNativeHistogramBuckets = tc.bucketLimit
+			limits.ReduceNativeHistogramOverMaxBuckets = tc.reduceOverMaxBuckets
 
 			ds, _, _ := prepare(t, prepConfig{
 				numIngesters:     2,
@@ -1587,7 +1676,7 @@ func TestDistributor_ExemplarValidation(t *testing.T) {
 			require.Len(t, regs, 1)
 
 			for _, ts := range tc.req.Timeseries {
-				err := ds[0].validateSeries(now, &ts, "user", "test-group", false, tc.minExemplarTS, tc.maxExemplarTS)
+				err := ds[0].validateSeries(now, &ts, "user", "test-group", nil, false, tc.minExemplarTS, tc.maxExemplarTS)
 				assert.NoError(t, err)
 			}
 
@@ -1989,6 +2078,87 @@ func TestDistributor_MetricsForLabelMatchers(t *testing.T) {
 	}
 }
 
+func TestDistributor_ActiveSeries(t *testing.T) {
+	const numIngesters = 5
+
+	fixtures := []struct {
+		lbls      labels.Labels
+		value     float64
+		timestamp int64
+	}{
+		{labels.FromStrings(labels.MetricName, "test_1", "status", "200"), 1, 100000},
+		{labels.FromStrings(labels.MetricName, "test_1", "status", "500"), 1, 110000},
+		{labels.FromStrings(labels.MetricName, "test_2"), 2, 200000},
+	}
+
+	tests := map[string]struct {
+		shuffleShardSize  int
+		matchers          []*labels.Matcher
+		expectedResult    []labels.Labels
+		expectedIngesters int
+	}{
+		"should return an empty response if no metric match": {
+			matchers: []*labels.Matcher{
+				mustNewMatcher(labels.MatchEqual, model.MetricNameLabel, "unknown"),
+			},
+			expectedResult:    []labels.Labels{},
+			expectedIngesters: numIngesters,
+		},
+		"should return the matching series deduplicated across replicas": {
+			matchers: []*labels.Matcher{
+				mustNewMatcher(labels.MatchEqual, model.MetricNameLabel, "test_1"),
+			},
+			expectedResult: []labels.Labels{
+				fixtures[0].lbls,
+				fixtures[1].lbls,
+			},
+			expectedIngesters: numIngesters,
+		},
+		"should query only ingesters belonging to tenant's subring if shuffle shard size is set": {
+			shuffleShardSize: 3,
+			matchers: []*labels.Matcher{
+				mustNewMatcher(labels.MatchRegexp, model.MetricNameLabel, "test_.*"),
+			},
+			expectedResult: []labels.Labels{
+				fixtures[0].lbls,
+				fixtures[1].lbls,
+				fixtures[2].lbls,
+			},
+			expectedIngesters: 3,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			// Create distributor
+			ds, ingesters, _ := prepare(t, prepConfig{
+				numIngesters:     numIngesters,
+				happyIngesters:   numIngesters,
+				numDistributors:  1,
+				shuffleShardSize: testData.shuffleShardSize,
+			})
+
+			// Push fixtures
+			ctx := user.InjectOrgID(context.Background(), "test")
+
+			for _, series := range fixtures {
+				req := mockWriteRequest(series.lbls, series.value, series.timestamp)
+				_, err := ds[0].Push(ctx, req)
+				require.NoError(t, err)
+			}
+
+			series, err := ds[0].ActiveSeries(ctx, testData.matchers)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, testData.expectedResult, series)
+
+			// Due to the quorum the distributor could cancel the last request towards ingesters
+			// if all other ones are successful, so we're good either has been queried X or X-1
+			// ingesters.
+			assert.Contains(t, []int{testData.expectedIngesters, testData.expectedIngesters - 1}, countMockIngestersCalls(ingesters, "ActiveSeries"))
+		})
+	}
+}
+
 func TestDistributor_LabelNames(t *testing.T) {
 	const numIngesters = 5
 
@@ -3465,6 +3635,20 @@ type mockIngester struct {
 	labelNamesStreamResponseDelay time.Duration
 	timeOut                       bool
 	tokens                        []uint32
+
+	// The input series of the streaming aggregation rules, by rule key.
+	streamingAggregationInputs map[string][]string
+}
+
+func (i *mockIngester) streamingAggregationInputsByRule() map[string][]string {
+	i.Lock()
+	defer i.Unlock()
+
+	result := map[string][]string{}
+	for k, v := range i.streamingAggregationInputs {
+		result[k] = append([]string(nil), v...)
+	}
+	return result
 }
 
 func (i *mockIngester) series() map[uint32]*mimirpb.PreallocTimeseries {
@@ -3507,6 +3691,16 @@ func (i *mockIngester) Push(ctx context.Context, req *mimirpb.WriteRequest, _ ..
 		return nil, context.DeadlineExceeded
 	}
 
+	if req.StreamingAggregationRule != "" {
+		if i.streamingAggregationInputs == nil {
+			i.streamingAggregationInputs = map[string][]string{}
+		}
+		for _, series := range req.Timeseries {
+			i.streamingAggregationInputs[req.StreamingAggregationRule] = append(i.streamingAggregationInputs[req.StreamingAggregationRule], mimirpb.FromLabelAdaptersToLabels(series.Labels).String())
+		}
+		return &mimirpb.WriteResponse{}, nil
+	}
+
 	if len(req.Timeseries) > 0 && i.timeseries == nil {
 		i.timeseries = map[uint32]*mimirpb.PreallocTimeseries{}
 	}
@@ -3963,6 +4157,50 @@ func (s *labelValuesCardinalityStream) Recv() (*client.LabelValuesCardinalityRes
 	return result, nil
 }
 
+func (i *mockIngester) ActiveSeries(_ context.Context, req *client.ActiveSeriesRequest, _ ...grpc.CallOption) (client.Ingester_ActiveSeriesClient, error) {
+	i.Lock()
+	defer i.Unlock()
+
+	i.trackCall("ActiveSeries")
+
+	if !i.happy {
+		return nil, errFail
+	}
+
+	matchers, err := client.FromLabelMatchers(req.GetMatchers())
+	if err != nil {
+		return nil, err
+	}
+
+	// Send each series in its own message.
+	var results []*client.ActiveSeriesResponse
+	for _, ts := range i.timeseries {
+		if match(ts.Labels, matchers) {
+			results = append(results, &client.ActiveSeriesResponse{Metric: []*mimirpb.Metric{{Labels: ts.Labels}}})
+		}
+	}
+	return &activeSeriesStream{results: results}, nil
+}
+
+type activeSeriesStream struct {
+	grpc.ClientStream
+	i       int
+	results []*client.ActiveSeriesResponse
+}
+
+func (*activeSeriesStream) CloseSend() error {
+	return nil
+}
+
+func (s *activeSeriesStream) Recv() (*client.ActiveSeriesResponse, error) {
+	if s.i >= len(s.results) {
+		return nil, io.EOF
+	}
+	result := s.results[s.i]
+	s.i++
+	return result, nil
+}
+
 func (i *mockIngester) trackCall(name string) {
 	if i.calls == nil {
 		i.calls = map[string]int{}
@@ -4197,6 +4435,52 @@ func TestDistributorValidation(t *testing.T) {
 	}
 }
 
+func TestDistributor_Push_CostAttribution(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "user")
+
+	var limits validation.Limits
+	flagext.DefaultValues(&limits)
+	limits.MaxLabelNamesPerSeries = 3
+	limits.CostAttributionLabels = []string{"team"}
+	limits.MaxCostAttributionCardinalityPerUser = 2
+
+	ds, _, regs := prepare(t, prepConfig{
+		numIngesters:    3,
+		happyIngesters:  3,
+		numDistributors: 1,
+		limits:          &limits,
+	})
+
+	now := time.Now().UnixMilli()
+	req := &mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "foo"}, {Name: "team", Value: "a"}}, now, 1),
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "bar"}, {Name: "team", Value: "a"}}, now, 1),
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "foo"}, {Name: "team", Value: "b"}}, now, 1),
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "foo"}, {Name: "team", Value: "c"}}, now, 1),
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "foo"}, {Name: "extra1", Value: "x"}, {Name: "extra2", Value: "x"}, {Name: "team", Value: "a"}}, now, 1),
+	}}
+
+	_, err := ds[0].Push(ctx, req)
+	require.Error(t, err)
+
+	metrics := []string{"cortex_distributor_received_attributed_samples_total", "cortex_discarded_attributed_samples_total"}
+	assert.NoError(t, testutil.GatherAndCompare(regs[0], strings.NewReader(`
+		# HELP cortex_discarded_attributed_samples_total The total number of samples discarded per tenant, broken down by reason and by the tenant's cost attribution labels.
+		# TYPE cortex_discarded_attributed_samples_total counter
+		cortex_discarded_attributed_samples_total{reason="max_label_names_per_series",team="a",user="user"} 1
+
+		# HELP cortex_distributor_received_attributed_samples_total The total number of samples received per tenant, broken down by the tenant's cost attribution labels.
+		# TYPE cortex_distributor_received_attributed_samples_total counter
+		cortex_distributor_received_attributed_samples_total{team="a",user="user"} 2
+		cortex_distributor_received_attributed_samples_total{team="b",user="user"} 1
+		cortex_distributor_received_attributed_samples_total{team="__overflow__",user="user"} 1
+	`), metrics...))
+
+	ds[0].cleanupInactiveUser("user")
+
+	assert.NoError(t, testutil.GatherAndCompare(regs[0], strings.NewReader(""), metrics...))
+}
+
 // This is not great, but we deal with unsorted labels in prePushRelabelMiddleware.
 func TestShardByAllLabelsReturnsWrongResultsForUnsortedLabels(t *testing.T) {
 	val1 := shardByAllLabels("test", []mimirpb.LabelAdapter{
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package graphite parses samples in the Graphite plaintext protocol, and maps Graphite
+// metric paths to metric names and labels via templates.
+package graphite
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Tag is a tag of a Graphite 1.1 tagged series, or a label extracted from a metric path by a template.
+type Tag struct {
+	Key, Value string
+}
+
+// Point is a single line of the plaintext protocol.
+type Point struct {
+	Path string
+	// Tags holds the tags of a tagged series, in the "path;tag1=value1;tag2=value2" format.
+	Tags  []Tag
+	Value float64
+	// TimestampMs is only valid if HasTimestamp is true.
+	TimestampMs  int64
+	HasTimestamp bool
+}
+
+// Parse parses the plaintext protocol body, calling fn for each point. Empty lines are skipped.
+func Parse(body []byte, fn func(Point) error) error {
+	for lineNum := 1; len(body) > 0; lineNum++ {
+		var line []byte
+		if idx := bytes.IndexByte(body, '\n'); idx >= 0 {
+			line, body = body[:idx], body[idx+1:]
+		} else {
+			line, body = body, nil
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		p, err := ParseLine(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseLine parses a single "<path> <value> [<timestamp>]" line. The timestamp is in seconds, and
+// a missing or negative timestamp means the time the sample has been received. The returned point
+// doesn't reference line.
+func ParseLine(line []byte) (Point, error) {
+	var p Point
+
+	parts := strings.Fields(string(line))
+	if len(parts) != 2 && len(parts) != 3 {
+		return p, fmt.Errorf("invalid line %q: expected \"<path> <value> [<timestamp>]\"", line)
+	}
+
+	path, tags, _ := strings.Cut(parts[0], ";")
+	if path == "" {
+		return p, errors.New("missing metric path")
+	}
+	p.Path = path
+
+	for tags != "" {
+		var tag string
+		tag, tags, _ = strings.Cut(tags, ";")
+
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" || value == "" {
+			return p, fmt.Errorf("invalid tag %q of metric %q", tag, path)
+		}
+		p.Tags = append(p.Tags, Tag{Key: key, Value: value})
+	}
+
+	var err error
+	if p.Value, err = strconv.ParseFloat(parts[1], 64); err != nil {
+		return p, fmt.Errorf("invalid value %q of metric %q", parts[1], path)
+	}
+
+	if len(parts) == 3 {
+		ts, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || math.IsNaN(ts) || math.IsInf(ts, 0) {
+			return p, fmt.Errorf("invalid timestamp %q of metric %q", parts[2], path)
+		}
+		if ts >= 0 {
+			p.TimestampMs = int64(ts * 1000)
+			p.HasTimestamp = true
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package graphite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := map[string]struct {
+		line          string
+		expected      Point
+		expectedError string
+	}{
+		"without timestamp": {
+			line:     "servers.host-1.cpu 1.5",
+			expected: Point{Path: "servers.host-1.cpu", Value: 1.5},
+		},
+		"with timestamp": {
+			line:     "servers.host-1.cpu  1.5\t1700000000",
+			expected: Point{Path: "servers.host-1.cpu", Value: 1.5, TimestampMs: 1700000000000, HasTimestamp: true},
+		},
+		"with fractional timestamp": {
+			line:     "servers.host-1.cpu 1.5 1700000000.25",
+			expected: Point{Path: "servers.host-1.cpu", Value: 1.5, TimestampMs: 1700000000250, HasTimestamp: true},
+		},
+		"with negative timestamp": {
+			line:     "servers.host-1.cpu 1.5 -1",
+			expected: Point{Path: "servers.host-1.cpu", Value: 1.5},
+		},
+		"tagged series": {
+			line:     "cpu;host=host-1;dc=eu 2 1700000000",
+			expected: Point{Path: "cpu", Tags: []Tag{{Key: "host", Value: "host-1"}, {Key: "dc", Value: "eu"}}, Value: 2, TimestampMs: 1700000000000, HasTimestamp: true},
+		},
+		"missing value": {
+			line:          "servers.host-1.cpu",
+			expectedError: "invalid line",
+		},
+		"too many fields": {
+			line:          "servers.host-1.cpu 1 2 3",
+			expectedError: "invalid line",
+		},
+		"invalid value": {
+			line:          "servers.host-1.cpu abc",
+			expectedError: `invalid value "abc"`,
+		},
+		"invalid timestamp": {
+			line:          "servers.host-1.cpu 1 abc",
+			expectedError: `invalid timestamp "abc"`,
+		},
+		"invalid tag": {
+			line:          "cpu;host 1",
+			expectedError: `invalid tag "host"`,
+		},
+		"missing path": {
+			line:          ";host=a 1",
+			expectedError: "missing metric path",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParseLine([]byte(tc.line))
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	var paths []string
+	require.NoError(t, Parse([]byte("cpu 1\r\n\n  mem 2\n"), func(p Point) error {
+		paths = append(paths, p.Path)
+		return nil
+	}))
+	assert.Equal(t, []string{"cpu", "mem"}, paths)
+
+	err := Parse([]byte("cpu 1\nmem\n"), func(Point) error { return nil })
+	assert.EqualError(t, err, `line 2: invalid line "mem": expected "<path> <value> [<timestamp>]"`)
+}
@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package graphite
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	measurementNode         = "measurement"
+	measurementWildcardNode = "measurement*"
+	nameSeparator           = "_"
+)
+
+// Template maps the nodes of the metric paths matching its filter to a metric name and labels.
+//
+// A template rule has the format "[<filter>] <template> [<tags>]", for example
+// "servers.* .host.measurement* region=eu,env=prod":
+//   - The optional filter is a dot-separated pattern of the paths the template applies to. Each node
+//     of the filter is matched against the corresponding node of the path, and supports "*" wildcards.
+//     A path matches the filter if its leading nodes match all the nodes of the filter.
+//   - The template is a dot-separated list of node names. The nodes named "measurement" are joined with
+//     underscores to build the metric name, and "measurement*" joins the node and all the following ones.
+//     The nodes with an empty name are skipped, and any other node is added as a label with the given name.
+//   - The optional tags are a comma-separated list of "name=value" labels added to all the series
+//     matching the template.
+type Template struct {
+	filter []string
+	nodes  []string
+	tags   []Tag
+}
+
+// ParseTemplate parses a template rule.
+func ParseTemplate(rule string) (Template, error) {
+	var t Template
+
+	parts := strings.Fields(rule)
+	switch {
+	case len(parts) == 1:
+		t.nodes = strings.Split(parts[0], ".")
+	case len(parts) == 2 && strings.Contains(parts[1], "="):
+		t.nodes = strings.Split(parts[0], ".")
+		if err := t.parseTags(parts[1]); err != nil {
+			return t, fmt.Errorf("invalid template %q: %w", rule, err)
+		}
+	case len(parts) == 2:
+		t.filter = strings.Split(parts[0], ".")
+		t.nodes = strings.Split(parts[1], ".")
+	case len(parts) == 3:
+		t.filter = strings.Split(parts[0], ".")
+		t.nodes = strings.Split(parts[1], ".")
+		if err := t.parseTags(parts[2]); err != nil {
+			return t, fmt.Errorf("invalid template %q: %w", rule, err)
+		}
+	default:
+		return t, fmt.Errorf("invalid template %q: expected \"[<filter>] <template> [<tags>]\"", rule)
+	}
+
+	for _, f := range t.filter {
+		if _, err := path.Match(f, ""); f == "" || err != nil {
+			return t, fmt.Errorf("invalid template %q: invalid filter node %q", rule, f)
+		}
+	}
+
+	hasMeasurement := false
+	for i, n := range t.nodes {
+		switch n {
+		case "":
+		case measurementNode:
+			hasMeasurement = true
+		case measurementWildcardNode:
+			if i != len(t.nodes)-1 {
+				return t, fmt.Errorf("invalid template %q: %q must be the last node", rule, measurementWildcardNode)
+			}
+			hasMeasurement = true
+		default:
+			if !model.LabelName(n).IsValid() || n == model.MetricNameLabel {
+				return t, fmt.Errorf("invalid template %q: %q is not a valid label name", rule, n)
+			}
+		}
+	}
+	if !hasMeasurement {
+		return t, fmt.Errorf("invalid template %q: at least one %q node is required", rule, measurementNode)
+	}
+
+	return t, nil
+}
+
+func (t *Template) parseTags(tags string) error {
+	for _, tag := range strings.Split(tags, ",") {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || value == "" || !model.LabelName(key).IsValid() || key == model.MetricNameLabel {
+			return fmt.Errorf("invalid tag %q", tag)
+		}
+		t.tags = append(t.tags, Tag{Key: key, Value: value})
+	}
+	return nil
+}
+
+// matches returns whether the nodes of the metric path match the template's filter.
+func (t Template) matches(nodes []string) bool {
+	if len(nodes) < len(t.filter) {
+		return false
+	}
+	for i, f := range t.filter {
+		if ok, _ := path.Match(f, nodes[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the metric name and labels of the metric path nodes.
+func (t Template) apply(nodes []string) (string, []Tag) {
+	var (
+		name []string
+		tags = make([]Tag, 0, len(t.nodes)+len(t.tags))
+	)
+
+	for i, n := range t.nodes {
+		if i >= len(nodes) {
+			break
+		}
+
+		switch n {
+		case "":
+		case measurementNode:
+			name = append(name, nodes[i])
+		case measurementWildcardNode:
+			name = append(name, nodes[i:]...)
+		default:
+			tags = append(tags, Tag{Key: n, Value: nodes[i]})
+		}
+	}
+
+	// The labels extracted from the path take precedence over the template's tags.
+	for _, tag := range t.tags {
+		if !hasTag(tags, tag.Key) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return strings.Join(name, nameSeparator), tags
+}
+
+func hasTag(tags []Tag, key string) bool {
+	for _, t := range tags {
+		if t.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Templates is a list of templates, applied in order.
+type Templates []Template
+
+// ParseTemplates parses a list of template rules.
+func ParseTemplates(rules []string) (Templates, error) {
+	templates := make(Templates, 0, len(rules))
+	for _, rule := range rules {
+		t, err := ParseTemplate(rule)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// Apply returns the metric name and labels of the metric path, as mapped by the first template
+// matching the path. If no template matches, the metric name is the path with its nodes joined
+// with underscores, and there are no labels. The metric name may not be a valid Prometheus
+// metric name, and should be sanitized by the caller.
+func (ts Templates) Apply(metricPath string) (string, []Tag) {
+	nodes := strings.Split(metricPath, ".")
+	for _, t := range ts {
+		if t.matches(nodes) {
+			if name, tags := t.apply(nodes); name != "" {
+				return name, tags
+			}
+		}
+	}
+	return strings.Join(nodes, nameSeparator), nil
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package graphite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplate_Errors(t *testing.T) {
+	for rule, expectedError := range map[string]string{
+		"":                                "expected \"[<filter>] <template> [<tags>]\"",
+		"a b c d":                         "expected \"[<filter>] <template> [<tags>]\"",
+		"host.cpu":                        `at least one "measurement" node is required`,
+		"measurement*.host":               `"measurement*" must be the last node`,
+		"host-name.measurement":           `"host-name" is not a valid label name`,
+		"__name__.measurement":            `"__name__" is not a valid label name`,
+		"servers..* host.measurement":     `invalid filter node ""`,
+		"servers.[ host.measurement":      `invalid filter node "["`,
+		"servers.* host.measurement env":  `invalid tag "env"`,
+		"host.measurement region=eu,env=": `invalid tag "env="`,
+	} {
+		_, err := ParseTemplate(rule)
+		require.Error(t, err, rule)
+		assert.Contains(t, err.Error(), expectedError, rule)
+	}
+}
+
+func TestTemplates_Apply(t *testing.T) {
+	templates, err := ParseTemplates([]string{
+		"servers.* .host.measurement* region=eu,host=unknown",
+		"apps.*.http .app..measurement.measurement",
+		"stats.*.* ..measurement",
+		"measurement.job",
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		path         string
+		expectedName string
+		expectedTags []Tag
+	}{
+		"measurement wildcard and template tags": {
+			path:         "servers.host-1.cpu.load.avg",
+			expectedName: "cpu_load_avg",
+			expectedTags: []Tag{{Key: "host", Value: "host-1"}, {Key: "region", Value: "eu"}},
+		},
+		"skipped nodes": {
+			path:         "apps.checkout.http.requests.total",
+			expectedName: "requests_total",
+			expectedTags: []Tag{{Key: "app", Value: "checkout"}},
+		},
+		"extra nodes are ignored": {
+			path:         "stats.a.b.c",
+			expectedName: "b",
+			expectedTags: []Tag{},
+		},
+		"path shorter than the template": {
+			path:         "up",
+			expectedName: "up",
+			expectedTags: []Tag{},
+		},
+		"filter wildcard matches a single node": {
+			path:         "apps.checkout.grpc.requests",
+			expectedName: "apps",
+			expectedTags: []Tag{{Key: "job", Value: "checkout"}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			name, tags := templates.Apply(tc.path)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedTags, tags)
+		})
+	}
+}
+
+func TestTemplates_Apply_NoMatchingTemplate(t *testing.T) {
+	templates, err := ParseTemplates([]string{"servers.* .host.measurement"})
+	require.NoError(t, err)
+
+	name, tags := templates.Apply("apps.checkout.requests")
+	assert.Equal(t, "apps_checkout_requests", name)
+	assert.Empty(t, tags)
+
+	// A template not extracting any metric name from the path is skipped.
+	name, tags = templates.Apply("servers.host-1")
+	assert.Equal(t, "servers_host-1", name)
+	assert.Empty(t, tags)
+}
@@ -136,6 +136,46 @@ type haClusterInfo struct {
 	electedLastSeenTimestamp    int64
 	nonElectedLastSeenReplica   string
 	nonElectedLastSeenTimestamp int64
+
+	// The latest elections of the cluster, oldest first.
+	elections []haElection
+}
+
+// maxElectionsPerCluster is the number of elections kept in the history of each cluster.
+const maxElectionsPerCluster = 10
+
+// haElection is an entry of the elections history of a cluster.
+type haElection struct {
+	Replica   string    `json:"replica"`
+	ElectedAt time.Time `json:"electedAt"`
+
+	// The replica elected before, and when its last sample has been received.
+	// Empty if no replica was elected for the cluster.
+	PreviousReplica    string    `json:"previousReplica,omitempty"`
+	PreviousLastSeenAt time.Time `json:"previousLastSeenAt"`
+
+	// Reason is a human-readable explanation of why the previous replica lost the election.
+	Reason string `json:"reason"`
+}
+
+func newHAElection(desc *ReplicaDesc) haElection {
+	e := haElection{
+		Replica:   desc.Replica,
+		ElectedAt: timestamp.Time(desc.ElectedAt),
+	}
+	if desc.ElectedAt == 0 {
+		// The replica has been elected by a distributor not tracking the election time.
+		e.ElectedAt = timestamp.Time(desc.ReceivedAt)
+	}
+
+	if desc.PreviousReplica == "" {
+		e.Reason = "no replica was elected for the cluster"
+		return e
+	}
+	e.PreviousReplica = desc.PreviousReplica
+	e.PreviousLastSeenAt = timestamp.Time(desc.PreviousReceivedAt)
+	e.Reason = fmt.Sprintf("failover: no samples received from replica %s for %s", desc.PreviousReplica, e.ElectedAt.Sub(e.PreviousLastSeenAt).Truncate(time.Millisecond))
+	return e
 }
 
 // newHATracker returns a new HA cluster tracker using either Consul
@@ -462,6 +502,15 @@ func (h *haTracker) updateCache(userID, cluster string, desc *ReplicaDesc) {
 	}
 	if desc.Replica != entry.elected.Replica {
 		h.electedReplicaChanges.WithLabelValues(userID, cluster).Inc()
+
+		election := newHAElection(desc)
+		if entry.elected.Replica != "" {
+			level.Info(h.logger).Log("msg", "elected replica changed", "user", userID, "cluster", cluster, "replica", election.Replica, "previous_replica", entry.elected.Replica, "reason", election.Reason)
+		}
+		entry.elections = append(entry.elections, election)
+		if len(entry.elections) > maxElectionsPerCluster {
+			entry.elections = entry.elections[len(entry.elections)-maxElectionsPerCluster:]
+		}
 	}
 	entry.elected = *desc
 	h.electedReplicaTimestamp.WithLabelValues(userID, cluster).Set(float64(desc.ReceivedAt / 1000))
@@ -473,7 +522,10 @@ func (h *haTracker) updateKVStore(ctx context.Context, userID, cluster, replica
 	key := fmt.Sprintf("%s/%s", userID, cluster)
 	var desc *ReplicaDesc
 	err := h.client.CAS(ctx, key, func(in interface{}) (out interface{}, retry bool, err error) {
-		var ok bool
+		var (
+			ok   bool
+			prev *ReplicaDesc
+		)
 		if desc, ok = in.(*ReplicaDesc); ok && desc.DeletedAt == 0 {
 			// If the entry in KVStore is up-to-date, just stop the loop.
 			if h.withinUpdateTimeout(now, desc.ReceivedAt) ||
@@ -481,6 +533,7 @@ func (h *haTracker) updateKVStore(ctx context.Context, userID, cluster, replica
 				desc.Replica != replica && now.Sub(timestamp.Time(desc.ReceivedAt)) < h.cfg.FailoverTimeout {
 				return nil, false, nil
 			}
+			prev = desc
 		}
 
 		// Attempt to update KVStore to our timestamp and replica.
@@ -488,6 +541,16 @@ func (h *haTracker) updateKVStore(ctx context.Context, userID, cluster, replica
 			Replica:    replica,
 			ReceivedAt: timestamp.FromTime(now),
 			DeletedAt:  0,
+			ElectedAt:  timestamp.FromTime(now),
+		}
+		if prev != nil && prev.Replica == replica {
+			// The replica is still elected: keep the details of its election.
+			desc.ElectedAt = prev.ElectedAt
+			desc.PreviousReplica = prev.PreviousReplica
+			desc.PreviousReceivedAt = prev.PreviousReceivedAt
+		} else if prev != nil {
+			desc.PreviousReplica = prev.Replica
+			desc.PreviousReceivedAt = prev.ReceivedAt
 		}
 		return desc, true, nil
 	})
@@ -34,6 +34,13 @@ type ReplicaDesc struct {
 	// already remove entry from memory. Actual deletion from KV store does *not* trigger
 	// "watch" notification with a key for all KV stores.
 	DeletedAt int64 `protobuf:"varint,3,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// Unix timestamp in milliseconds when the replica has been elected. Unlike received_at,
+	// it's not updated while the elected replica keeps sending samples.
+	ElectedAt int64 `protobuf:"varint,4,opt,name=elected_at,json=electedAt,proto3" json:"elected_at,omitempty"`
+	// The replica elected before this one, and the Unix timestamp in milliseconds of the
+	// last sample received from it. Empty if no replica was elected for the cluster.
+	PreviousReplica    string `protobuf:"bytes,5,opt,name=previous_replica,json=previousReplica,proto3" json:"previous_replica,omitempty"`
+	PreviousReceivedAt int64  `protobuf:"varint,6,opt,name=previous_received_at,json=previousReceivedAt,proto3" json:"previous_received_at,omitempty"`
 }
 
 func (m *ReplicaDesc) Reset()      { *m = ReplicaDesc{} }
@@ -89,6 +96,27 @@ func (m *ReplicaDesc) GetDeletedAt() int64 {
 	return 0
 }
 
+func (m *ReplicaDesc) GetElectedAt() int64 {
+	if m != nil {
+		return m.ElectedAt
+	}
+	return 0
+}
+
+func (m *ReplicaDesc) GetPreviousReplica() string {
+	if m != nil {
+		return m.PreviousReplica
+	}
+	return ""
+}
+
+func (m *ReplicaDesc) GetPreviousReceivedAt() int64 {
+	if m != nil {
+		return m.PreviousReceivedAt
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*ReplicaDesc)(nil), "distributor.ReplicaDesc")
 }
@@ -96,21 +124,24 @@ func init() {
 func init() { proto.RegisterFile("ha_tracker.proto", fileDescriptor_86f0e7bcf71d860b) }
 
 var fileDescriptor_86f0e7bcf71d860b = []byte{
-	// 224 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xc8, 0x48, 0x8c, 0x2f,
-	0x29, 0x4a, 0x4c, 0xce, 0x4e, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x4e, 0xc9,
-	0x2c, 0x2e, 0x29, 0xca, 0x4c, 0x2a, 0x2d, 0xc9, 0x2f, 0x92, 0xd2, 0x4d, 0xcf, 0x2c, 0xc9, 0x28,
-	0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xcf, 0x4f, 0xcf, 0xd7, 0x07, 0xab, 0x49, 0x2a, 0x4d,
-	0x03, 0xf3, 0xc0, 0x1c, 0x30, 0x0b, 0xa2, 0x57, 0x29, 0x9d, 0x8b, 0x3b, 0x28, 0xb5, 0x20, 0x27,
-	0x33, 0x39, 0xd1, 0x25, 0xb5, 0x38, 0x59, 0x48, 0x82, 0x8b, 0xbd, 0x08, 0xc2, 0x95, 0x60, 0x54,
-	0x60, 0xd4, 0xe0, 0x0c, 0x82, 0x71, 0x85, 0xe4, 0xb9, 0xb8, 0x8b, 0x52, 0x93, 0x53, 0x33, 0xcb,
-	0x52, 0x53, 0xe2, 0x13, 0x4b, 0x24, 0x98, 0x14, 0x18, 0x35, 0x98, 0x83, 0xb8, 0x60, 0x42, 0x8e,
-	0x25, 0x42, 0xb2, 0x5c, 0x5c, 0x29, 0xa9, 0x39, 0xa9, 0x25, 0x10, 0x79, 0x66, 0xb0, 0x3c, 0x27,
-	0x54, 0xc4, 0xb1, 0xc4, 0xc9, 0xe4, 0xc2, 0x43, 0x39, 0x86, 0x1b, 0x0f, 0xe5, 0x18, 0x3e, 0x3c,
-	0x94, 0x63, 0x6c, 0x78, 0x24, 0xc7, 0xb8, 0xe2, 0x91, 0x1c, 0xe3, 0x89, 0x47, 0x72, 0x8c, 0x17,
-	0x1e, 0xc9, 0x31, 0x3e, 0x78, 0x24, 0xc7, 0xf8, 0xe2, 0x91, 0x1c, 0xc3, 0x87, 0x47, 0x72, 0x8c,
-	0x13, 0x1e, 0xcb, 0x31, 0x5c, 0x78, 0x2c, 0xc7, 0x70, 0xe3, 0xb1, 0x1c, 0x43, 0x12, 0x1b, 0xd8,
-	0x95, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0xb3, 0xd1, 0xdd, 0x8d, 0xf5, 0x00, 0x00, 0x00,
+	// 267 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0x31, 0x4e, 0xc3, 0x30,
+	0x14, 0x86, 0xfd, 0x28, 0x14, 0xd5, 0x19, 0xa8, 0x2c, 0x86, 0x08, 0x89, 0x47, 0xc5, 0x54, 0x06,
+	0x5a, 0x24, 0xb8, 0x40, 0x11, 0x27, 0xc8, 0x05, 0xa2, 0xc4, 0x79, 0xa4, 0x16, 0x41, 0x8e, 0x1c,
+	0xa7, 0x33, 0x47, 0xe0, 0x18, 0x1c, 0x85, 0x31, 0x63, 0x47, 0xe2, 0x2c, 0x8c, 0x3d, 0x02, 0x92,
+	0x93, 0xa8, 0x6c, 0xfe, 0xff, 0xef, 0xb7, 0xf5, 0xc9, 0x7c, 0xbe, 0x4d, 0x62, 0x6b, 0x12, 0xf9,
+	0x46, 0x66, 0x55, 0x1a, 0x6d, 0xb5, 0x08, 0x32, 0x55, 0x59, 0xa3, 0xd2, 0xda, 0x6a, 0x73, 0x75,
+	0x9f, 0x2b, 0xbb, 0xad, 0xd3, 0x95, 0xd4, 0xef, 0xeb, 0x5c, 0xe7, 0x7a, 0xed, 0x37, 0x69, 0xfd,
+	0xea, 0x93, 0x0f, 0xfe, 0xd4, 0xdf, 0xbd, 0xed, 0x80, 0x07, 0x11, 0x95, 0x85, 0x92, 0xc9, 0x0b,
+	0x55, 0x52, 0x84, 0xfc, 0xdc, 0xf4, 0x31, 0x84, 0x05, 0x2c, 0x67, 0xd1, 0x18, 0xc5, 0x0d, 0x0f,
+	0x0c, 0x49, 0x52, 0x3b, 0xca, 0xe2, 0xc4, 0x86, 0x27, 0x0b, 0x58, 0x4e, 0x22, 0x3e, 0x56, 0x1b,
+	0x2b, 0xae, 0x39, 0xcf, 0xa8, 0x20, 0xdb, 0xf3, 0x89, 0xe7, 0xb3, 0xa1, 0xe9, 0x31, 0x15, 0x24,
+	0x07, 0x7c, 0xda, 0xe3, 0xa1, 0xd9, 0x58, 0x71, 0xc7, 0xe7, 0xa5, 0xa1, 0x9d, 0xd2, 0x75, 0x15,
+	0x8f, 0x06, 0x67, 0xde, 0xe0, 0x62, 0xec, 0x07, 0x4f, 0xf1, 0xc0, 0x2f, 0xff, 0x4d, 0x8f, 0x4a,
+	0x53, 0xff, 0xa6, 0x38, 0xce, 0x47, 0xb5, 0xe7, 0xa7, 0xa6, 0x45, 0xb6, 0x6f, 0x91, 0x1d, 0x5a,
+	0x84, 0x0f, 0x87, 0xf0, 0xe5, 0x10, 0xbe, 0x1d, 0x42, 0xe3, 0x10, 0x7e, 0x1c, 0xc2, 0xaf, 0x43,
+	0x76, 0x70, 0x08, 0x9f, 0x1d, 0xb2, 0xa6, 0x43, 0xb6, 0xef, 0x90, 0xa5, 0x53, 0xff, 0x45, 0x8f,
+	0x7f, 0x03, 0x00, 0x72, 0x8d, 0xb7, 0x8d, 0x72, 0x01, 0x00, 0x00,
 }
 
 func (this *ReplicaDesc) Equal(that interface{}) bool {
@@ -141,17 +172,29 @@ func (this *ReplicaDesc) Equal(that interface{}) bool {
 	if this.DeletedAt != that1.DeletedAt {
 		return false
 	}
+	if this.ElectedAt != that1.ElectedAt {
+		return false
+	}
+	if this.PreviousReplica != that1.PreviousReplica {
+		return false
+	}
+	if this.PreviousReceivedAt != that1.PreviousReceivedAt {
+		return false
+	}
 	return true
 }
 func (this *ReplicaDesc) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 7)
+	s := make([]string, 0, 10)
 	s = append(s, "&distributor.ReplicaDesc{")
 	s = append(s, "Replica: "+fmt.Sprintf("%#v", this.Replica)+",\n")
 	s = append(s, "ReceivedAt: "+fmt.Sprintf("%#v", this.ReceivedAt)+",\n")
 	s = append(s, "DeletedAt: "+fmt.Sprintf("%#v", this.DeletedAt)+",\n")
+	s = append(s, "ElectedAt: "+fmt.Sprintf("%#v", this.ElectedAt)+",\n")
+	s = append(s, "PreviousReplica: "+fmt.Sprintf("%#v", this.PreviousReplica)+",\n")
+	s = append(s, "PreviousReceivedAt: "+fmt.Sprintf("%#v", this.PreviousReceivedAt)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -183,6 +226,23 @@ func (m *ReplicaDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.PreviousReceivedAt != 0 {
+		i = encodeVarintHaTracker(dAtA, i, uint64(m.PreviousReceivedAt))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.PreviousReplica) > 0 {
+		i -= len(m.PreviousReplica)
+		copy(dAtA[i:], m.PreviousReplica)
+		i = encodeVarintHaTracker(dAtA, i, uint64(len(m.PreviousReplica)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.ElectedAt != 0 {
+		i = encodeVarintHaTracker(dAtA, i, uint64(m.ElectedAt))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.DeletedAt != 0 {
 		i = encodeVarintHaTracker(dAtA, i, uint64(m.DeletedAt))
 		i--
@@ -230,6 +290,16 @@ func (m *ReplicaDesc) Size() (n int) {
 	if m.DeletedAt != 0 {
 		n += 1 + sovHaTracker(uint64(m.DeletedAt))
 	}
+	if m.ElectedAt != 0 {
+		n += 1 + sovHaTracker(uint64(m.ElectedAt))
+	}
+	l = len(m.PreviousReplica)
+	if l > 0 {
+		n += 1 + l + sovHaTracker(uint64(l))
+	}
+	if m.PreviousReceivedAt != 0 {
+		n += 1 + sovHaTracker(uint64(m.PreviousReceivedAt))
+	}
 	return n
 }
 
@@ -247,6 +317,9 @@ func (this *ReplicaDesc) String() string {
 		`Replica:` + fmt.Sprintf("%v", this.Replica) + `,`,
 		`ReceivedAt:` + fmt.Sprintf("%v", this.ReceivedAt) + `,`,
 		`DeletedAt:` + fmt.Sprintf("%v", this.DeletedAt) + `,`,
+		`ElectedAt:` + fmt.Sprintf("%v", this.ElectedAt) + `,`,
+		`PreviousReplica:` + fmt.Sprintf("%v", this.PreviousReplica) + `,`,
+		`PreviousReceivedAt:` + fmt.Sprintf("%v", this.PreviousReceivedAt) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -358,6 +431,76 @@ func (m *ReplicaDesc) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElectedAt", wireType)
+			}
+			m.ElectedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHaTracker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ElectedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreviousReplica", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHaTracker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PreviousReplica = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreviousReceivedAt", wireType)
+			}
+			m.PreviousReceivedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHaTracker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PreviousReceivedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHaTracker(dAtA[iNdEx:])
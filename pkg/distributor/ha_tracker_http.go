@@ -12,6 +12,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/grafana/dskit/tenant"
 	"github.com/prometheus/prometheus/model/timestamp"
 
 	"github.com/grafana/mimir/pkg/util"
@@ -22,8 +23,9 @@ var haTrackerStatusPageHTML string
 var haTrackerStatusPageTemplate = template.Must(template.New("ha-tracker").Parse(haTrackerStatusPageHTML))
 
 type haTrackerStatusPageContents struct {
-	Elected []haTrackerReplica `json:"elected"`
-	Now     time.Time          `json:"now"`
+	Elected   []haTrackerReplica  `json:"elected"`
+	Elections []haTrackerElection `json:"elections"`
+	Now       time.Time           `json:"now"`
 }
 
 type haTrackerReplica struct {
@@ -35,10 +37,31 @@ type haTrackerReplica struct {
 	FailoverTime time.Duration `json:"failoverDuration"`
 }
 
+type haTrackerElection struct {
+	UserID  string `json:"userID"`
+	Cluster string `json:"cluster"`
+	haElection
+}
+
+// haTrackerClustersResponse is the response of the tenant's HA clusters API.
+type haTrackerClustersResponse struct {
+	Clusters []haTrackerCluster `json:"clusters"`
+}
+
+type haTrackerCluster struct {
+	Cluster        string       `json:"cluster"`
+	Replica        string       `json:"replica"`
+	LastReceivedAt time.Time    `json:"lastReceivedAt"`
+	Elections      []haElection `json:"elections"`
+}
+
 func (h *haTracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	h.electedLock.RLock()
 
-	var electedReplicas []haTrackerReplica
+	var (
+		electedReplicas []haTrackerReplica
+		elections       []haTrackerElection
+	)
 	for userID, clusters := range h.clusters {
 		for cluster, entry := range clusters {
 			desc := &entry.elected
@@ -50,6 +73,9 @@ func (h *haTracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				UpdateTime:   time.Until(timestamp.Time(desc.ReceivedAt).Add(h.cfg.UpdateTimeout)),
 				FailoverTime: time.Until(timestamp.Time(desc.ReceivedAt).Add(h.cfg.FailoverTimeout)),
 			})
+			for _, e := range entry.elections {
+				elections = append(elections, haTrackerElection{UserID: userID, Cluster: cluster, haElection: e})
+			}
 		}
 	}
 	h.electedLock.RUnlock()
@@ -64,8 +90,50 @@ func (h *haTracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return first.Cluster < second.Cluster
 	})
 
+	// Show the latest elections first.
+	sort.Slice(elections, func(i, j int) bool {
+		first := elections[i]
+		second := elections[j]
+
+		if !first.ElectedAt.Equal(second.ElectedAt) {
+			return first.ElectedAt.After(second.ElectedAt)
+		}
+		if first.UserID != second.UserID {
+			return first.UserID < second.UserID
+		}
+		return first.Cluster < second.Cluster
+	})
+
 	util.RenderHTTPResponse(w, haTrackerStatusPageContents{
-		Elected: electedReplicas,
-		Now:     time.Now(),
+		Elected:   electedReplicas,
+		Elections: elections,
+		Now:       time.Now(),
 	}, haTrackerStatusPageTemplate, req)
 }
+
+// ClustersHandler returns the HA clusters of the tenant, with the elected replicas and the latest elections.
+func (h *haTracker) ClustersHandler(w http.ResponseWriter, req *http.Request) {
+	userID, err := tenant.TenantID(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.electedLock.RLock()
+	clusters := make([]haTrackerCluster, 0, len(h.clusters[userID]))
+	for cluster, entry := range h.clusters[userID] {
+		clusters = append(clusters, haTrackerCluster{
+			Cluster:        cluster,
+			Replica:        entry.elected.Replica,
+			LastReceivedAt: timestamp.Time(entry.elected.ReceivedAt),
+			Elections:      append([]haElection(nil), entry.elections...),
+		})
+	}
+	h.electedLock.RUnlock()
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Cluster < clusters[j].Cluster
+	})
+
+	util.WriteJSONResponse(w, haTrackerClustersResponse{Clusters: clusters})
+}
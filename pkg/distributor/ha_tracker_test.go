@@ -7,7 +7,10 @@ package distributor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -870,3 +873,127 @@ func getSumOfHistogramSampleCount(families []*dto.MetricFamily, metricName strin
 
 	return sum
 }
+
+func TestHATracker_Elections(t *testing.T) {
+	// The in-memory KV store is shared between the tests.
+	const userID = "elections-user"
+
+	c, err := newHATracker(HATrackerConfig{
+		EnableHATracker:        true,
+		KVStore:                kv.Config{Store: "inmemory"},
+		UpdateTimeout:          100 * time.Millisecond,
+		UpdateTimeoutJitterMax: 0,
+		FailoverTimeout:        time.Second,
+	}, trackerLimits{maxClusters: 100}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), c))
+	defer services.StopAndAwaitTerminated(context.Background(), c) //nolint:errcheck
+
+	start := time.Now().Truncate(time.Millisecond)
+	require.NoError(t, c.checkReplica(context.Background(), userID, "c1", "replica1", start))
+
+	// The elected replica keeps sending samples: the election time is preserved.
+	lastSeen := start.Add(500 * time.Millisecond)
+	require.NoError(t, c.checkReplica(context.Background(), userID, "c1", "replica1", lastSeen))
+	c.updateKVStoreAll(context.Background(), lastSeen)
+	checkReplicaTimestamp(t, time.Second, c, userID, "c1", "replica1", lastSeen)
+
+	// Fail over to the other replica.
+	failover := lastSeen.Add(1500 * time.Millisecond)
+	require.Error(t, c.checkReplica(context.Background(), userID, "c1", "replica2", failover))
+	c.updateKVStoreAll(context.Background(), failover)
+	checkReplicaTimestamp(t, time.Second, c, userID, "c1", "replica2", failover)
+
+	expected := []haElection{
+		{
+			Replica:   "replica1",
+			ElectedAt: start,
+			Reason:    "no replica was elected for the cluster",
+		},
+		{
+			Replica:            "replica2",
+			ElectedAt:          failover,
+			PreviousReplica:    "replica1",
+			PreviousLastSeenAt: lastSeen,
+			Reason:             "failover: no samples received from replica replica1 for 1.5s",
+		},
+	}
+
+	t.Run("tenant API", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/ha_tracker/clusters", nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), userID))
+		resp := httptest.NewRecorder()
+		c.ClustersHandler(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var actual haTrackerClustersResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &actual))
+		require.Len(t, actual.Clusters, 1)
+		assert.Equal(t, "c1", actual.Clusters[0].Cluster)
+		assert.Equal(t, "replica2", actual.Clusters[0].Replica)
+		assertElections(t, expected, actual.Clusters[0].Elections)
+
+		// Other tenants don't see the cluster.
+		req = req.WithContext(user.InjectOrgID(req.Context(), "other"))
+		resp = httptest.NewRecorder()
+		c.ClustersHandler(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"clusters": []}`, resp.Body.String())
+	})
+
+	t.Run("status page", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/distributor/ha_tracker", nil)
+		req.Header.Set("Accept", "application/json")
+		resp := httptest.NewRecorder()
+		c.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var actual haTrackerStatusPageContents
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &actual))
+
+		// The page shows the elections of all the tenants, the latest first.
+		var elections []haElection
+		for _, e := range actual.Elections {
+			if e.UserID == userID && e.Cluster == "c1" {
+				elections = append(elections, e.haElection)
+			}
+		}
+		assertElections(t, []haElection{expected[1], expected[0]}, elections)
+	})
+}
+
+func assertElections(t *testing.T, expected, actual []haElection) {
+	t.Helper()
+
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].Replica, actual[i].Replica)
+		assert.True(t, expected[i].ElectedAt.Equal(actual[i].ElectedAt), "expected %s, got %s", expected[i].ElectedAt, actual[i].ElectedAt)
+		assert.Equal(t, expected[i].PreviousReplica, actual[i].PreviousReplica)
+		assert.Equal(t, expected[i].Reason, actual[i].Reason)
+		if expected[i].PreviousReplica != "" {
+			assert.True(t, expected[i].PreviousLastSeenAt.Equal(actual[i].PreviousLastSeenAt), "expected %s, got %s", expected[i].PreviousLastSeenAt, actual[i].PreviousLastSeenAt)
+		}
+	}
+}
+
+func TestHATracker_ElectionsHistoryIsBounded(t *testing.T) {
+	c, err := newHATracker(HATrackerConfig{EnableHATracker: false}, trackerLimits{}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	now := time.Now()
+	c.electedLock.Lock()
+	for i := 0; i < maxElectionsPerCluster+2; i++ {
+		c.updateCache("user", "c1", &ReplicaDesc{
+			Replica:    fmt.Sprintf("replica%d", i),
+			ReceivedAt: timestamp.FromTime(now),
+			ElectedAt:  timestamp.FromTime(now),
+		})
+	}
+	elections := c.clusters["user"]["c1"].elections
+	c.electedLock.Unlock()
+
+	require.Len(t, elections, maxElectionsPerCluster)
+	assert.Equal(t, "replica2", elections[0].Replica)
+	assert.Equal(t, fmt.Sprintf("replica%d", maxElectionsPerCluster+1), elections[maxElectionsPerCluster-1].Replica)
+}
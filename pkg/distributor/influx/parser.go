@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package influx parses samples in the InfluxDB line protocol.
+package influx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// measurementEscapes are the characters which can be escaped with a backslash in a measurement.
+	measurementEscapes = ", "
+	// keyEscapes are the characters which can be escaped with a backslash in tag keys, tag values and field keys.
+	keyEscapes = ",= "
+)
+
+// Tag is a tag of a point.
+type Tag struct {
+	Key, Value string
+}
+
+// Field is a numeric field of a point. Boolean fields are converted to 1 and 0.
+type Field struct {
+	Key   string
+	Value float64
+}
+
+// Point is a single line of the line protocol.
+type Point struct {
+	Measurement string
+	Tags        []Tag
+	// Fields holds the numeric and boolean fields of the point. String fields are skipped,
+	// because they can't be converted into samples.
+	Fields []Field
+	// Timestamp is in the precision of the request. It's only valid if HasTimestamp is true.
+	Timestamp    int64
+	HasTimestamp bool
+}
+
+// Parse parses the line protocol body, calling fn for each point. Empty lines and comments are skipped.
+func Parse(body []byte, fn func(Point) error) error {
+	for lineNum := 1; len(body) > 0; lineNum++ {
+		var line []byte
+		if idx := bytes.IndexByte(body, '\n'); idx >= 0 {
+			line, body = body[:idx], body[idx+1:]
+		} else {
+			line, body = body, nil
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		p, err := ParseLine(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseLine parses a single line of the line protocol. The returned point doesn't reference line.
+func ParseLine(line []byte) (Point, error) {
+	var (
+		p   Point
+		s   = string(line)
+		err error
+	)
+
+	var i int
+	p.Measurement, i = scanToken(s, 0, measurementEscapes, ", ")
+	if p.Measurement == "" {
+		return p, errors.New("missing measurement")
+	}
+
+	for i < len(s) && s[i] == ',' {
+		var key, value string
+		key, i = scanToken(s, i+1, keyEscapes, ",= ")
+		if i >= len(s) || s[i] != '=' {
+			return p, fmt.Errorf("missing value of tag %q", key)
+		}
+		value, i = scanToken(s, i+1, keyEscapes, ", ")
+		if key == "" || value == "" {
+			return p, fmt.Errorf("invalid tag %q=%q: tag keys and values can't be empty", key, value)
+		}
+		p.Tags = append(p.Tags, Tag{Key: key, Value: value})
+	}
+
+	if i >= len(s) || s[i] != ' ' {
+		return p, errors.New("missing fields")
+	}
+	i = skipSpaces(s, i)
+
+	for {
+		var key string
+		key, i = scanToken(s, i, keyEscapes, ",= ")
+		if key == "" || i >= len(s) || s[i] != '=' {
+			return p, fmt.Errorf("invalid field %q: missing key or value", key)
+		}
+		i++
+
+		if i < len(s) && s[i] == '"' {
+			if i, err = skipString(s, i); err != nil {
+				return p, fmt.Errorf("invalid value of field %q: %w", key, err)
+			}
+		} else {
+			var raw string
+			raw, i = scanToken(s, i, "", ", ")
+
+			var value float64
+			if value, err = parseFieldValue(raw); err != nil {
+				return p, fmt.Errorf("invalid value of field %q: %w", key, err)
+			}
+			p.Fields = append(p.Fields, Field{Key: key, Value: value})
+		}
+
+		if i >= len(s) || s[i] != ',' {
+			break
+		}
+		i++
+	}
+
+	if i = skipSpaces(s, i); i < len(s) {
+		if p.Timestamp, err = strconv.ParseInt(s[i:], 10, 64); err != nil {
+			return p, fmt.Errorf("invalid timestamp %q", s[i:])
+		}
+		p.HasTimestamp = true
+	}
+
+	return p, nil
+}
+
+// scanToken returns the unescaped token starting at start and ending before the first unescaped
+// character in stops, along with the position of the latter.
+func scanToken(s string, start int, escapes, stops string) (string, int) {
+	escaped := false
+
+	i := start
+	for ; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(escapes, s[i+1]) >= 0 {
+			escaped = true
+			i++
+			continue
+		}
+		if strings.IndexByte(stops, s[i]) >= 0 {
+			break
+		}
+	}
+
+	if !escaped {
+		return s[start:i], i
+	}
+	return unescape(s[start:i], escapes), i
+}
+
+func unescape(s, escapes string) string {
+	sb := strings.Builder{}
+	sb.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(escapes, s[i+1]) >= 0 {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && s[i] == ' ' {
+		i++
+	}
+	return i
+}
+
+// skipString returns the position following the double-quoted string starting at start.
+func skipString(s string, start int) (int, error) {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, errors.New("unterminated string")
+}
+
+func parseFieldValue(raw string) (float64, error) {
+	if raw == "" {
+		return 0, errors.New("empty value")
+	}
+
+	switch raw {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, nil
+	}
+
+	switch raw[len(raw)-1] {
+	case 'i':
+		v, err := strconv.ParseInt(raw[:len(raw)-1], 10, 64)
+		return float64(v), err
+	case 'u':
+		v, err := strconv.ParseUint(raw[:len(raw)-1], 10, 64)
+		return float64(v), err
+	}
+	return strconv.ParseFloat(raw, 64)
+}
+
+// ParsePrecision returns the unit of the timestamps for the given precision, as set in the "precision"
+// parameter of write requests. Both the InfluxDB 1.x and 2.x precisions are supported, and the default
+// precision is nanoseconds.
+func ParsePrecision(precision string) (time.Duration, error) {
+	switch precision {
+	case "", "n", "ns":
+		return time.Nanosecond, nil
+	case "u", "us":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	default:
+		return 0, fmt.Errorf("invalid precision %q", precision)
+	}
+}
+
+// ToMilliseconds converts a timestamp in the given unit to milliseconds.
+func ToMilliseconds(ts int64, unit time.Duration) int64 {
+	if unit < time.Millisecond {
+		return ts / int64(time.Millisecond/unit)
+	}
+	return ts * int64(unit/time.Millisecond)
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package influx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := map[string]struct {
+		line          string
+		expected      Point
+		expectedError string
+	}{
+		"measurement and field only": {
+			line:     "cpu value=1",
+			expected: Point{Measurement: "cpu", Fields: []Field{{Key: "value", Value: 1}}},
+		},
+		"tags, fields and timestamp": {
+			line: "cpu,host=server-1,region=eu user=1.5,system=2i,steal=3u,online=true,offline=F 1700000000000000000",
+			expected: Point{
+				Measurement: "cpu",
+				Tags:        []Tag{{Key: "host", Value: "server-1"}, {Key: "region", Value: "eu"}},
+				Fields: []Field{
+					{Key: "user", Value: 1.5},
+					{Key: "system", Value: 2},
+					{Key: "steal", Value: 3},
+					{Key: "online", Value: 1},
+					{Key: "offline", Value: 0},
+				},
+				Timestamp:    1700000000000000000,
+				HasTimestamp: true,
+			},
+		},
+		"escaped characters": {
+			line: `disk\ io\,total,path=/var\ log,k\=v=a\,b reads\ total=1`,
+			expected: Point{
+				Measurement: "disk io,total",
+				Tags:        []Tag{{Key: "path", Value: "/var log"}, {Key: "k=v", Value: "a,b"}},
+				Fields:      []Field{{Key: "reads total", Value: 1}},
+			},
+		},
+		"string fields are skipped": {
+			line: `service,host=a status="up, \"really\" up",code=200i 1`,
+			expected: Point{
+				Measurement:  "service",
+				Tags:         []Tag{{Key: "host", Value: "a"}},
+				Fields:       []Field{{Key: "code", Value: 200}},
+				Timestamp:    1,
+				HasTimestamp: true,
+			},
+		},
+		"negative and exponent values": {
+			line:     "temp value=-1.5e2",
+			expected: Point{Measurement: "temp", Fields: []Field{{Key: "value", Value: -150}}},
+		},
+		"missing measurement": {
+			line:          ",host=a value=1",
+			expectedError: "missing measurement",
+		},
+		"missing fields": {
+			line:          "cpu,host=a",
+			expectedError: "missing fields",
+		},
+		"empty tag value": {
+			line:          "cpu,host= value=1",
+			expectedError: `invalid tag "host"=""`,
+		},
+		"invalid field value": {
+			line:          "cpu value=abc",
+			expectedError: `invalid value of field "value"`,
+		},
+		"missing field value": {
+			line:          "cpu value",
+			expectedError: `invalid field "value"`,
+		},
+		"unterminated string": {
+			line:          `cpu status="up`,
+			expectedError: "unterminated string",
+		},
+		"invalid timestamp": {
+			line:          "cpu value=1 abc",
+			expectedError: `invalid timestamp "abc"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParseLine([]byte(tc.line))
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	body := "# comment\ncpu value=1\r\n\n  mem value=2\n"
+
+	var measurements []string
+	require.NoError(t, Parse([]byte(body), func(p Point) error {
+		measurements = append(measurements, p.Measurement)
+		return nil
+	}))
+	assert.Equal(t, []string{"cpu", "mem"}, measurements)
+
+	err := Parse([]byte("cpu value=1\nmem\n"), func(Point) error { return nil })
+	assert.EqualError(t, err, "line 2: missing fields")
+}
+
+func TestToMilliseconds(t *testing.T) {
+	for _, precision := range []string{"", "ns", "us", "ms", "s", "m", "h"} {
+		unit, err := ParsePrecision(precision)
+		require.NoError(t, err)
+
+		ts := time.UnixMilli(1699999200000)
+		assert.Equal(t, ts.UnixMilli(), ToMilliseconds(ts.UnixNano()/int64(unit), unit), precision)
+	}
+
+	_, err := ParsePrecision("d")
+	assert.Error(t, err)
+}
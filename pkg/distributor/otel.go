@@ -3,11 +3,10 @@
 package distributor
 
 import (
-	"compress/gzip"
 	"context"
 	"errors"
-	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	kitlog "github.com/go-kit/log"
@@ -20,6 +19,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/prompb"
+	otlpnormalize "github.com/prometheus/prometheus/storage/remote/otlptranslator/prometheus"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
@@ -38,6 +38,10 @@ const (
 
 	otelParseError = "otlp_parse_error"
 	maxErrMsgLen   = 1024
+
+	// otelCreatedSuffix is the metric name suffix of the series exported by the OTLP translator for the start
+	// time of cumulative counters, histograms and summaries.
+	otelCreatedSuffix = "_created"
 )
 
 func OTLPHandler(
@@ -47,10 +51,10 @@ func OTLPHandler(
 	enableOtelMetadataStorage bool,
 	limits *validation.Overrides,
 	reg prometheus.Registerer,
+	pushMetrics *PushMetrics,
 	push PushFunc,
 ) http.Handler {
 	discardedDueToOtelParseError := validation.DiscardedSamplesCounter(reg, otelParseError)
-
 	return handler(maxRecvMsgSize, sourceIPs, allowSkipLabelNameValidation, limits, push, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, dst []byte, req *mimirpb.PreallocWriteRequest) ([]byte, error) {
 		var decoderFunc func(buf []byte) (pmetricotlp.ExportRequest, error)
 
@@ -78,33 +82,20 @@ func OTLPHandler(
 			return nil, httpgrpc.Errorf(http.StatusRequestEntityTooLarge, distributorMaxWriteMessageSizeErr{actual: int(r.ContentLength), limit: maxRecvMsgSize}.Error())
 		}
 
-		reader := r.Body
-		// Handle compression.
 		contentEncoding := r.Header.Get("Content-Encoding")
-		switch contentEncoding {
-		case "gzip":
-			gr, err := gzip.NewReader(reader)
-			if err != nil {
-				return nil, err
-			}
-			reader = gr
-
-		case "":
-			// No compression.
-
-		default:
-			return nil, httpgrpc.Errorf(http.StatusUnsupportedMediaType, "unsupported compression: %s. Only \"gzip\" or no compression supported", contentEncoding)
+		compression, err := otlpCompression(contentEncoding)
+		if err != nil {
+			return nil, err
 		}
 
-		// Protect against a large input.
-		reader = http.MaxBytesReader(nil, reader, int64(maxRecvMsgSize))
-
-		body, err := io.ReadAll(reader)
+		// Read and decompress the body, protecting against a large input.
+		reader, compressedSize := newBodySizeReader(r.Body)
+		body, err := util.ReadRequestBody(ctx, reader, int(r.ContentLength), maxRecvMsgSize, dst, compression)
 		if err != nil {
 			r.Body.Close()
 
-			if util.IsRequestBodyTooLarge(err) {
-				return body, httpgrpc.Errorf(http.StatusRequestEntityTooLarge, distributorMaxWriteMessageSizeErr{actual: -1, limit: maxRecvMsgSize}.Error())
+			if errors.Is(err, util.MsgSizeTooLargeErr{}) {
+				return body, httpgrpc.Errorf(http.StatusRequestEntityTooLarge, maxWriteMessageSizeErr(err, r, maxRecvMsgSize).Error())
 			}
 
 			return body, err
@@ -114,6 +105,8 @@ func OTLPHandler(
 			return body, err
 		}
 
+		pushMetrics.observeBody(otlpHandlerName, compression, compressedSize(), len(body))
+
 		log, ctx := spanlogger.NewWithLogger(ctx, logger, "Distributor.OTLPHandler.decodeAndConvert")
 		defer log.Span.Finish()
 
@@ -128,7 +121,21 @@ func OTLPHandler(
 
 		level.Debug(log).Log("msg", "decoding complete, starting conversion")
 
-		metrics, err := otelMetricsToTimeseries(ctx, discardedDueToOtelParseError, logger, otlpReq.Metrics())
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return body, err
+		}
+		addSuffixes := limits.OTelMetricSuffixesEnabled(tenantID)
+
+		promoteResourceAttributes(otlpReq.Metrics(), limits.PromoteOTelResourceAttributes(tenantID))
+		if addSuffixes {
+			normalizeMetricNames(otlpReq.Metrics())
+		}
+
+		metrics, err := otelMetricsToTimeseries(ctx, discardedDueToOtelParseError, logger, otlpReq.Metrics(), prometheusremotewrite.Settings{
+			DisableTargetInfo:   !limits.OTelTargetInfoEnabled(tenantID),
+			ExportCreatedMetric: limits.CreatedTimestampZeroIngestionEnabled(tenantID),
+		})
 		if err != nil {
 			return body, err
 		}
@@ -203,7 +210,7 @@ func otelMetricsToMetadata(md pmetric.Metrics) []*mimirpb.MetricMetadata {
 				metric := scopeMetrics.Metrics().At(k)
 				entry := mimirpb.MetricMetadata{
 					Type:             otelMetricTypeToMimirMetricType(metric),
-					MetricFamilyName: prometheustranslator.BuildCompliantName(metric, "", true), // TODO expose addMetricSuffixes in configuration (https://github.com/grafana/mimir/issues/5967)
+					MetricFamilyName: prometheustranslator.BuildCompliantName(metric, "", false),
 					Help:             metric.Description(),
 					Unit:             metric.Unit(),
 				}
@@ -216,8 +223,8 @@ func otelMetricsToMetadata(md pmetric.Metrics) []*mimirpb.MetricMetadata {
 
 }
 
-func otelMetricsToTimeseries(ctx context.Context, discardedDueToOtelParseError *prometheus.CounterVec, logger kitlog.Logger, md pmetric.Metrics) ([]mimirpb.PreallocTimeseries, error) {
-	tsMap, errs := prometheusremotewrite.FromMetrics(md, prometheusremotewrite.Settings{})
+func otelMetricsToTimeseries(ctx context.Context, discardedDueToOtelParseError *prometheus.CounterVec, logger kitlog.Logger, md pmetric.Metrics, settings prometheusremotewrite.Settings) ([]mimirpb.PreallocTimeseries, error) {
+	tsMap, errs := prometheusremotewrite.FromMetrics(md, settings)
 
 	if errs != nil {
 		userID, err := tenant.TenantID(ctx)
@@ -240,14 +247,175 @@ func otelMetricsToTimeseries(ctx context.Context, discardedDueToOtelParseError *
 		level.Warn(logger).Log("msg", "OTLP parse error", "err", parseErrs)
 	}
 
+	// The start time of the data points is exported as separate series, which are converted
+	// to the created timestamp of the series they refer to.
+	var createdTimestamps map[string]int64
+	if settings.ExportCreatedMetric {
+		createdTimestamps = extractOTelCreatedTimestamps(tsMap)
+	}
+
 	mimirTs := mimirpb.PreallocTimeseriesSliceFromPool()
 	for _, promTs := range tsMap {
-		mimirTs = append(mimirTs, promToMimirTimeseries(promTs))
+		ts := promToMimirTimeseries(promTs)
+		if len(createdTimestamps) > 0 {
+			ts.CreatedTimestamp = otelCreatedTimestampOf(createdTimestamps, promTs.Labels)
+		}
+		mimirTs = append(mimirTs, ts)
 	}
 
 	return mimirTs, nil
 }
 
+// extractOTelCreatedTimestamps removes from tsMap the series exported by the OTLP translator for the start time of
+// cumulative counters, histograms and summaries, and returns the created timestamps, in milliseconds, of the series they
+// refer to. The returned map is keyed by the metric family name and the labels of the series.
+func extractOTelCreatedTimestamps(tsMap map[string]*prompb.TimeSeries) map[string]int64 {
+	var createdTimestamps map[string]int64
+
+	for sig, promTs := range tsMap {
+		name := otelMetricName(promTs.Labels)
+
+		// The translator exports the start time as the value of a single sample with a zero timestamp.
+		if !strings.HasSuffix(name, otelCreatedSuffix) || len(promTs.Samples) != 1 || promTs.Samples[0].Timestamp != 0 || len(promTs.Histograms) > 0 {
+			continue
+		}
+
+		if createdTimestamps == nil {
+			createdTimestamps = map[string]int64{}
+		}
+		createdTimestamps[otelCreatedTimestampKey(strings.TrimSuffix(name, otelCreatedSuffix), promTs.Labels)] = int64(promTs.Samples[0].Value)
+		delete(tsMap, sig)
+	}
+
+	return createdTimestamps
+}
+
+// otelCreatedTimestampOf returns the created timestamp of the series with the input labels, or 0 if unknown.
+// Counters are named after their metric family, while histograms and summaries have series named after their
+// metric family with the _bucket, _count and _sum suffixes.
+func otelCreatedTimestampOf(createdTimestamps map[string]int64, lbls []prompb.Label) int64 {
+	name := otelMetricName(lbls)
+	if ct, ok := createdTimestamps[otelCreatedTimestampKey(name, lbls)]; ok {
+		return ct
+	}
+
+	for _, suffix := range []string{"_bucket", "_count", "_sum"} {
+		if family, ok := strings.CutSuffix(name, suffix); ok {
+			return createdTimestamps[otelCreatedTimestampKey(family, lbls)]
+		}
+	}
+	return 0
+}
+
+// otelCreatedTimestampKey returns the key of the created timestamp of a series of the input metric family.
+// The le and quantile labels are ignored, because the created timestamp is exported once per histogram and summary.
+func otelCreatedTimestampKey(family string, lbls []prompb.Label) string {
+	b := strings.Builder{}
+	b.WriteString(family)
+	for _, l := range lbls {
+		if l.Name == model.MetricNameLabel || l.Name == model.BucketLabel || l.Name == model.QuantileLabel {
+			continue
+		}
+		b.WriteByte(0xff)
+		b.WriteString(l.Name)
+		b.WriteByte(0xff)
+		b.WriteString(l.Value)
+	}
+	return b.String()
+}
+
+func otelMetricName(lbls []prompb.Label) string {
+	for _, l := range lbls {
+		if l.Name == model.MetricNameLabel {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+// normalizeMetricNames renames the metrics following the Prometheus naming conventions, adding the unit and type
+// suffixes. The OTLP translator only normalizes the metric names if a global feature gate is enabled, so the metrics
+// are renamed before the translation instead, and the translator just keeps the normalized names.
+func normalizeMetricNames(md pmetric.Metrics) {
+	resourceMetricsSlice := md.ResourceMetrics()
+	for i := 0; i < resourceMetricsSlice.Len(); i++ {
+		scopeMetricsSlice := resourceMetricsSlice.At(i).ScopeMetrics()
+		for j := 0; j < scopeMetricsSlice.Len(); j++ {
+			metrics := scopeMetricsSlice.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				metric.SetName(otlpnormalize.BuildPromCompliantName(metric, ""))
+			}
+		}
+	}
+}
+
+// promoteResourceAttributes copies the given resource attributes to the attributes of every data point of the
+// resource, so that they're converted to series labels. Data point attributes with the same name take precedence.
+func promoteResourceAttributes(md pmetric.Metrics, promote []string) {
+	if len(promote) == 0 {
+		return
+	}
+
+	resourceMetricsSlice := md.ResourceMetrics()
+	for i := 0; i < resourceMetricsSlice.Len(); i++ {
+		resourceMetrics := resourceMetricsSlice.At(i)
+		resourceAttrs := resourceMetrics.Resource().Attributes()
+
+		promoted := pcommon.NewMap()
+		for _, name := range promote {
+			if value, ok := resourceAttrs.Get(name); ok {
+				value.CopyTo(promoted.PutEmpty(name))
+			}
+		}
+		if promoted.Len() == 0 {
+			continue
+		}
+
+		addAttrs := func(attrs pcommon.Map) {
+			promoted.Range(func(name string, value pcommon.Value) bool {
+				if _, ok := attrs.Get(name); !ok {
+					value.CopyTo(attrs.PutEmpty(name))
+				}
+				return true
+			})
+		}
+
+		scopeMetricsSlice := resourceMetrics.ScopeMetrics()
+		for j := 0; j < scopeMetricsSlice.Len(); j++ {
+			metricSlice := scopeMetricsSlice.At(j).Metrics()
+			for k := 0; k < metricSlice.Len(); k++ {
+				forEachDataPointAttributes(metricSlice.At(k), addAttrs)
+			}
+		}
+	}
+}
+
+func forEachDataPointAttributes(metric pmetric.Metric, fn func(attrs pcommon.Map)) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		for i := 0; i < metric.Gauge().DataPoints().Len(); i++ {
+			fn(metric.Gauge().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		for i := 0; i < metric.Sum().DataPoints().Len(); i++ {
+			fn(metric.Sum().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		for i := 0; i < metric.Histogram().DataPoints().Len(); i++ {
+			fn(metric.Histogram().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		for i := 0; i < metric.ExponentialHistogram().DataPoints().Len(); i++ {
+			fn(metric.ExponentialHistogram().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		for i := 0; i < metric.Summary().DataPoints().Len(); i++ {
+			fn(metric.Summary().DataPoints().At(i).Attributes())
+		}
+	}
+}
+
 func promToMimirTimeseries(promTs *prompb.TimeSeries) mimirpb.PreallocTimeseries {
 	labels := make([]mimirpb.LabelAdapter, 0, len(promTs.Labels))
 	for _, label := range promTs.Labels {
@@ -13,10 +13,12 @@ import (
 	"sync"
 
 	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/proto"
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/middleware"
 	"github.com/grafana/dskit/tenant"
 
+	"github.com/grafana/mimir/pkg/distributor/writev2pb"
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/globalerror"
@@ -44,20 +46,66 @@ const (
 	statusClientClosedRequest     = 499
 )
 
-// Handler is a http.Handler which accepts WriteRequests.
+// Handler is a http.Handler which accepts WriteRequests. Both Prometheus Remote-Write 1.0
+// and 2.0 requests are supported, and the protocol is negotiated via the Content-Type header.
+// The request body can be compressed with snappy (default), gzip or zstd, negotiated via the
+// Content-Encoding header.
 func Handler(
 	maxRecvMsgSize int,
 	sourceIPs *middleware.SourceIPExtractor,
 	allowSkipLabelNameValidation bool,
 	limits *validation.Overrides,
+	pushMetrics *PushMetrics,
 	push PushFunc,
 ) http.Handler {
-	return handler(maxRecvMsgSize, sourceIPs, allowSkipLabelNameValidation, limits, push, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, dst []byte, req *mimirpb.PreallocWriteRequest) ([]byte, error) {
-		res, err := util.ParseProtoReader(ctx, r.Body, int(r.ContentLength), maxRecvMsgSize, dst, req, util.RawSnappy)
-		if errors.Is(err, util.MsgSizeTooLargeErr{}) {
-			err = distributorMaxWriteMessageSizeErr{actual: int(r.ContentLength), limit: maxRecvMsgSize}
+	parseBody := func(ctx context.Context, r *http.Request, maxRecvMsgSize int, dst []byte, req proto.Message) ([]byte, error) {
+		compression, err := remoteWriteCompression(r.Header.Get("Content-Encoding"))
+		if err != nil {
+			return nil, err
 		}
-		return res, err
+
+		reader, compressedSize := newBodySizeReader(r.Body)
+		res, err := util.ParseProtoReader(ctx, reader, int(r.ContentLength), maxRecvMsgSize, dst, req, compression)
+		if err != nil {
+			return res, maxWriteMessageSizeErr(err, r, maxRecvMsgSize)
+		}
+
+		pushMetrics.observeBody(pushHandlerName, compression, compressedSize(), len(res))
+		return res, nil
+	}
+
+	v1Handler := handler(maxRecvMsgSize, sourceIPs, allowSkipLabelNameValidation, limits, push, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, dst []byte, req *mimirpb.PreallocWriteRequest) ([]byte, error) {
+		return parseBody(ctx, r, maxRecvMsgSize, dst, req)
+	})
+
+	v2Handler := handler(maxRecvMsgSize, sourceIPs, allowSkipLabelNameValidation, limits, push, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, dst []byte, req *mimirpb.PreallocWriteRequest) ([]byte, error) {
+		var v2Req writev2pb.Request
+		res, err := parseBody(ctx, r, maxRecvMsgSize, dst, &v2Req)
+		if err != nil {
+			return res, err
+		}
+
+		return res, remoteWriteV2ToWriteRequest(&v2Req, req)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protoMessage, err := remoteWriteProtoMessage(r.Header.Get("Content-Type"))
+		if err != nil {
+			resp, _ := httpgrpc.HTTPResponseFromError(err)
+			http.Error(w, string(resp.Body), int(resp.Code))
+			return
+		}
+
+		if protoMessage != remoteWriteV2ProtoMessage {
+			v1Handler.ServeHTTP(w, r)
+			return
+		}
+
+		// The stats are tracked in the context, and updated once the request has been written to the ingesters.
+		stats := &remoteWriteV2Stats{}
+		rw := &remoteWriteV2ResponseWriter{ResponseWriter: w, stats: stats}
+		v2Handler.ServeHTTP(rw, r.WithContext(contextWithRemoteWriteV2Stats(r.Context(), stats)))
+		rw.setHeaders()
 	})
 }
 
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/mimir/pkg/util"
+)
+
+const (
+	pushHandlerName       = "push"
+	otlpHandlerName       = "otlp"
+	influxHandlerName     = "influx"
+	graphiteHandlerName   = "graphite"
+	expositionHandlerName = "exposition"
+)
+
+// remoteWriteCompression returns the compression of a remote-write request body. Requests without
+// Content-Encoding are snappy compressed, as mandated by the remote-write protocol.
+func remoteWriteCompression(contentEncoding string) (util.CompressionType, error) {
+	switch contentEncoding {
+	case "", "snappy":
+		return util.RawSnappy, nil
+	case "gzip":
+		return util.Gzip, nil
+	case "zstd":
+		return util.Zstd, nil
+	default:
+		return 0, httpgrpc.Errorf(http.StatusUnsupportedMediaType, "unsupported compression: %s. Only \"snappy\", \"gzip\" or \"zstd\" supported", contentEncoding)
+	}
+}
+
+// otlpCompression returns the compression of an OTLP request body.
+func otlpCompression(contentEncoding string) (util.CompressionType, error) {
+	switch contentEncoding {
+	case "":
+		return util.NoCompression, nil
+	case "gzip":
+		return util.Gzip, nil
+	case "zstd":
+		return util.Zstd, nil
+	default:
+		return 0, httpgrpc.Errorf(http.StatusUnsupportedMediaType, "unsupported compression: %s. Only \"gzip\", \"zstd\" or no compression supported", contentEncoding)
+	}
+}
+
+// readTextBody reads the body of a push request in a text format, like the InfluxDB line protocol or the
+// Graphite plaintext protocol. Like OTLP requests, the body is either uncompressed, or compressed with
+// gzip or zstd as negotiated via the Content-Encoding header.
+func readTextBody(ctx context.Context, r *http.Request, maxRecvMsgSize int, dst []byte, pushMetrics *PushMetrics, handlerName string) ([]byte, error) {
+	if r.ContentLength > int64(maxRecvMsgSize) {
+		return nil, httpgrpc.Errorf(http.StatusRequestEntityTooLarge, distributorMaxWriteMessageSizeErr{actual: int(r.ContentLength), limit: maxRecvMsgSize}.Error())
+	}
+
+	compression, err := otlpCompression(r.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+
+	reader, compressedSize := newBodySizeReader(r.Body)
+	body, err := util.ReadRequestBody(ctx, reader, int(r.ContentLength), maxRecvMsgSize, dst, compression)
+	if err != nil {
+		if errors.Is(err, util.MsgSizeTooLargeErr{}) {
+			return body, httpgrpc.Errorf(http.StatusRequestEntityTooLarge, maxWriteMessageSizeErr(err, r, maxRecvMsgSize).Error())
+		}
+		return body, err
+	}
+
+	pushMetrics.observeBody(handlerName, compression, compressedSize(), len(body))
+	return body, nil
+}
+
+// maxWriteMessageSizeErr converts a util.MsgSizeTooLargeErr into a distributorMaxWriteMessageSizeErr.
+// Any other error is returned as is.
+func maxWriteMessageSizeErr(err error, r *http.Request, maxRecvMsgSize int) error {
+	var sizeErr util.MsgSizeTooLargeErr
+	if !errors.As(err, &sizeErr) {
+		return err
+	}
+
+	// The actual size is unknown if the limit has been exceeded while decompressing the body.
+	actual := int(r.ContentLength)
+	if sizeErr.Actual < 0 {
+		actual = -1
+	}
+	return distributorMaxWriteMessageSizeErr{actual: actual, limit: maxRecvMsgSize}
+}
+
+// PushMetrics tracks the number of push requests, and their compressed and decompressed body size,
+// by handler and Content-Encoding. They're created once by the Distributor, and shared by all the
+// push handlers.
+type PushMetrics struct {
+	requests          *prometheus.CounterVec
+	compressedBytes   *prometheus.CounterVec
+	decompressedBytes *prometheus.CounterVec
+}
+
+func newPushMetrics(reg prometheus.Registerer) *PushMetrics {
+	return &PushMetrics{
+		requests: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_push_requests_by_encoding_total",
+			Help: "The total number of push requests successfully decoded, by Content-Encoding.",
+		}, []string{"handler", "encoding"}),
+		compressedBytes: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_push_compressed_bytes_total",
+			Help: "The total size of the push requests body, as received on the wire, by Content-Encoding.",
+		}, []string{"handler", "encoding"}),
+		decompressedBytes: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_push_decompressed_bytes_total",
+			Help: "The total size of the push requests body after decompression, by Content-Encoding.",
+		}, []string{"handler", "encoding"}),
+	}
+}
+
+// observeBody tracks a request body received by the given handler. It's a no-op if m is nil.
+func (m *PushMetrics) observeBody(handler string, compression util.CompressionType, compressedSize, decompressedSize int) {
+	if m == nil {
+		return
+	}
+
+	encoding := compression.String()
+	m.requests.WithLabelValues(handler, encoding).Inc()
+	m.compressedBytes.WithLabelValues(handler, encoding).Add(float64(compressedSize))
+	m.decompressedBytes.WithLabelValues(handler, encoding).Add(float64(decompressedSize))
+}
+
+// newBodySizeReader returns a reader tracking the number of bytes read from r, and a function returning it.
+// If r is backed by a bytes.Buffer (e.g. when the request is received via httpgrpc), r is returned as is,
+// so that the request body can still be decoded without copying it.
+func newBodySizeReader(r io.Reader) (io.Reader, func() int) {
+	if bufReader, ok := r.(interface{ BytesBuffer() *bytes.Buffer }); ok && bufReader != nil {
+		size := bufReader.BytesBuffer().Len()
+		return r, func() int { return size }
+	}
+
+	cr := &countingReader{Reader: r}
+	return cr, func() int { return cr.n }
+}
+
+type countingReader struct {
+	io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += n
+	return n, err
+}
@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/middleware"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	// groupingKeyPathPrefix precedes the grouping key in the URL path of the push requests, as in the
+	// Pushgateway API: "/metrics/job/<job>{/<label>/<value>}".
+	groupingKeyPathPrefix = "/metrics/"
+
+	// base64Suffix is the suffix of the grouping label names whose value is base64 encoded.
+	base64Suffix = "@base64"
+
+	// pushTimeMetricName is the name of the series holding the time of the last push of a group.
+	pushTimeMetricName = "push_time_seconds"
+)
+
+// ExpositionHandler is a http.Handler which accepts metrics in the Prometheus text, OpenMetrics or
+// Prometheus protobuf exposition formats, like the Pushgateway. The metrics are converted into a
+// WriteRequest, which is pushed like a remote-write request. The grouping key in the URL path is
+// added as labels to every series, overriding the labels with the same name in the exposition,
+// and all the samples are assigned the time the request has been received.
+func ExpositionHandler(
+	maxRecvMsgSize int,
+	sourceIPs *middleware.SourceIPExtractor,
+	limits *validation.Overrides,
+	pushMetrics *PushMetrics,
+	push PushFunc,
+) http.Handler {
+	return handler(maxRecvMsgSize, sourceIPs, false, limits, push, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, dst []byte, req *mimirpb.PreallocWriteRequest) ([]byte, error) {
+		grouping, err := parseGroupingKey(r.URL.Path)
+		if err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+
+		body, err := readTextBody(ctx, r, maxRecvMsgSize, dst, pushMetrics, expositionHandlerName)
+		if err != nil {
+			return body, err
+		}
+
+		return body, expositionToWriteRequest(body, r.Header.Get("Content-Type"), grouping, time.Now(), req)
+	})
+}
+
+// parseGroupingKey parses the grouping key following groupingKeyPathPrefix in the URL path. The
+// grouping key starts with the job, and can be followed by any number of label name and value pairs.
+// The values of the label names with the "@base64" suffix are URL-safe base64 encoded, allowing
+// values containing slashes, and empty values.
+func parseGroupingKey(urlPath string) (labels.Labels, error) {
+	idx := strings.Index(urlPath, groupingKeyPathPrefix)
+	if idx < 0 {
+		return labels.EmptyLabels(), fmt.Errorf("missing grouping key in URL path %q", urlPath)
+	}
+
+	parts := strings.Split(strings.TrimSuffix(urlPath[idx+len(groupingKeyPathPrefix):], "/"), "/")
+	if len(parts)%2 != 0 {
+		return labels.EmptyLabels(), fmt.Errorf("invalid grouping key in URL path %q: expected label name and value pairs", urlPath)
+	}
+
+	b := labels.NewScratchBuilder(len(parts) / 2)
+	for i := 0; i < len(parts); i += 2 {
+		name, value := parts[i], parts[i+1]
+
+		if strings.HasSuffix(name, base64Suffix) {
+			name = strings.TrimSuffix(name, base64Suffix)
+
+			decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+			if err != nil {
+				return labels.EmptyLabels(), fmt.Errorf("invalid base64 encoded value of grouping label %q: %w", name, err)
+			}
+			value = string(decoded)
+		}
+
+		if i == 0 && name != model.JobLabel {
+			return labels.EmptyLabels(), fmt.Errorf("invalid grouping key in URL path %q: the grouping key must start with the %q label", urlPath, model.JobLabel)
+		}
+		if !model.LabelName(name).IsValid() || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+			return labels.EmptyLabels(), fmt.Errorf("invalid grouping label name %q", name)
+		}
+		if name == model.JobLabel && value == "" {
+			return labels.EmptyLabels(), errors.New("the job name can't be empty")
+		}
+		b.Add(name, value)
+	}
+
+	b.Sort()
+	grouping := b.Labels()
+	if name, dup := grouping.HasDuplicateLabelNames(); dup {
+		return labels.EmptyLabels(), fmt.Errorf("duplicate grouping label %q", name)
+	}
+	return grouping, nil
+}
+
+// expositionToWriteRequest parses the exposition body into the series and the metadata of the request.
+// Besides the parsed series, a push_time_seconds series holding the push time is added to the group.
+func expositionToWriteRequest(body []byte, contentType string, grouping labels.Labels, now time.Time, req *mimirpb.PreallocWriteRequest) (err error) {
+	parser, err := textparse.New(body, contentType, false)
+	if err != nil {
+		return httpgrpc.Errorf(http.StatusUnsupportedMediaType, "invalid content type %q: %s", contentType, err)
+	}
+
+	nowMs := now.UnixMilli()
+	req.Timeseries = mimirpb.PreallocTimeseriesSliceFromPool()
+	defer func() {
+		if err != nil {
+			mimirpb.ReuseSlice(req.Timeseries)
+			req.Timeseries = nil
+			req.Metadata = nil
+		}
+	}()
+
+	var (
+		lset     labels.Labels
+		ex       exemplar.Exemplar
+		lb       = labels.NewBuilder(labels.EmptyLabels())
+		metadata = map[string]*mimirpb.MetricMetadata{}
+	)
+
+	getMetadata := func(name []byte) *mimirpb.MetricMetadata {
+		md, ok := metadata[string(name)]
+		if !ok {
+			md = &mimirpb.MetricMetadata{MetricFamilyName: string(name)}
+			metadata[md.MetricFamilyName] = md
+			req.Metadata = append(req.Metadata, md)
+		}
+		return md
+	}
+
+	withGroupingLabels := func(lset labels.Labels) []mimirpb.LabelAdapter {
+		lb.Reset(lset)
+		grouping.Range(func(l labels.Label) {
+			lb.Set(l.Name, l.Value)
+		})
+		return mimirpb.FromLabelsToLabelAdapters(lb.Labels())
+	}
+
+	for {
+		var entry textparse.Entry
+		if entry, err = parser.Next(); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		switch entry {
+		case textparse.EntryType:
+			name, typ := parser.Type()
+			getMetadata(name).Type = expositionMetricTypeToMimir(typ)
+
+		case textparse.EntryHelp:
+			name, help := parser.Help()
+			getMetadata(name).Help = string(help)
+
+		case textparse.EntryUnit:
+			name, unit := parser.Unit()
+			getMetadata(name).Unit = string(unit)
+
+		case textparse.EntrySeries, textparse.EntryHistogram:
+			var (
+				series = mimirpb.TimeseriesFromPool()
+				ts     *int64
+			)
+			req.Timeseries = append(req.Timeseries, mimirpb.PreallocTimeseries{TimeSeries: series})
+
+			if entry == textparse.EntrySeries {
+				var value float64
+				_, ts, value = parser.Series()
+				series.Samples = append(series.Samples, mimirpb.Sample{TimestampMs: nowMs, Value: value})
+			} else {
+				var (
+					h  *histogram.Histogram
+					fh *histogram.FloatHistogram
+				)
+				_, ts, h, fh = parser.Histogram()
+				if h != nil {
+					series.Histograms = append(series.Histograms, mimirpb.FromHistogramToHistogramProto(nowMs, h))
+				} else {
+					series.Histograms = append(series.Histograms, mimirpb.FromFloatHistogramToHistogramProto(nowMs, fh))
+				}
+			}
+
+			parser.Metric(&lset)
+			if ts != nil {
+				return fmt.Errorf("pushed metrics must not have timestamps, but series %s has timestamp %d", lset.String(), *ts)
+			}
+			series.Labels = withGroupingLabels(lset)
+
+			for ex = (exemplar.Exemplar{}); parser.Exemplar(&ex); ex = (exemplar.Exemplar{}) {
+				exemplarTs := nowMs
+				if ex.HasTs {
+					exemplarTs = ex.Ts
+				}
+				series.Exemplars = append(series.Exemplars, mimirpb.Exemplar{
+					Labels:      mimirpb.FromLabelsToLabelAdapters(ex.Labels),
+					Value:       ex.Value,
+					TimestampMs: exemplarTs,
+				})
+			}
+		}
+	}
+	err = nil
+
+	pushTime := mimirpb.TimeseriesFromPool()
+	pushTime.Labels = withGroupingLabels(labels.FromStrings(model.MetricNameLabel, pushTimeMetricName))
+	pushTime.Samples = append(pushTime.Samples, mimirpb.Sample{TimestampMs: nowMs, Value: float64(nowMs) / 1000})
+	req.Timeseries = append(req.Timeseries, mimirpb.PreallocTimeseries{TimeSeries: pushTime})
+
+	return nil
+}
+
+func expositionMetricTypeToMimir(t textparse.MetricType) mimirpb.MetricMetadata_MetricType {
+	switch t {
+	case textparse.MetricTypeCounter:
+		return mimirpb.COUNTER
+	case textparse.MetricTypeGauge:
+		return mimirpb.GAUGE
+	case textparse.MetricTypeHistogram:
+		return mimirpb.HISTOGRAM
+	case textparse.MetricTypeGaugeHistogram:
+		return mimirpb.GAUGEHISTOGRAM
+	case textparse.MetricTypeSummary:
+		return mimirpb.SUMMARY
+	case textparse.MetricTypeInfo:
+		return mimirpb.INFO
+	case textparse.MetricTypeStateset:
+		return mimirpb.STATESET
+	}
+	return mimirpb.UNKNOWN
+}
@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestParseGroupingKey(t *testing.T) {
+	tests := map[string]struct {
+		path          string
+		expected      labels.Labels
+		expectedError string
+	}{
+		"job only": {
+			path:     "/api/v1/push/metrics/job/backup",
+			expected: labels.FromStrings("job", "backup"),
+		},
+		"job and labels": {
+			path:     "/api/v1/push/metrics/job/backup/instance/db-1/env/prod/",
+			expected: labels.FromStrings("job", "backup", "instance", "db-1", "env", "prod"),
+		},
+		"base64 encoded values": {
+			path:     "/api/v1/push/metrics/job@base64/YmFja3VwL2RhaWx5/path@base64/L3Zhci9saWI=/empty@base64/=",
+			expected: labels.FromStrings("job", "backup/daily", "path", "/var/lib", "empty", ""),
+		},
+		"missing job": {
+			path:          "/api/v1/push/metrics/instance/db-1",
+			expectedError: `the grouping key must start with the "job" label`,
+		},
+		"empty job": {
+			path:          "/api/v1/push/metrics/job@base64/=",
+			expectedError: "the job name can't be empty",
+		},
+		"missing label value": {
+			path:          "/api/v1/push/metrics/job/backup/instance",
+			expectedError: "expected label name and value pairs",
+		},
+		"invalid label name": {
+			path:          "/api/v1/push/metrics/job/backup/in-stance/db-1",
+			expectedError: `invalid grouping label name "in-stance"`,
+		},
+		"reserved label name": {
+			path:          "/api/v1/push/metrics/job/backup/__name__/up",
+			expectedError: `invalid grouping label name "__name__"`,
+		},
+		"invalid base64 value": {
+			path:          "/api/v1/push/metrics/job/backup/instance@base64/!!",
+			expectedError: `invalid base64 encoded value of grouping label "instance"`,
+		},
+		"duplicate label": {
+			path:          "/api/v1/push/metrics/job/backup/job/restore",
+			expectedError: `duplicate grouping label "job"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseGroupingKey(tc.path)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestExpositionHandler(t *testing.T) {
+	tests := map[string]struct {
+		path             string
+		contentType      string
+		body             string
+		expectedCode     int
+		expectedSeries   []labels.Labels
+		expectedValues   []float64
+		expectedMetadata []*mimirpb.MetricMetadata
+		expectedExemplar *mimirpb.Exemplar
+		expectedErrorMsg string
+	}{
+		"Prometheus text format": {
+			path: "/api/v1/push/metrics/job/backup/instance/db-1",
+			body: `# HELP backup_duration_seconds Duration of the backup.
+# TYPE backup_duration_seconds gauge
+backup_duration_seconds{instance="overridden",stage="dump"} 12.5
+backup_duration_seconds{stage="upload"} 3
+# TYPE backup_runs_total counter
+backup_runs_total 7
+`,
+			expectedCode: http.StatusOK,
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("__name__", "backup_duration_seconds", "instance", "db-1", "job", "backup", "stage", "dump"),
+				labels.FromStrings("__name__", "backup_duration_seconds", "instance", "db-1", "job", "backup", "stage", "upload"),
+				labels.FromStrings("__name__", "backup_runs_total", "instance", "db-1", "job", "backup"),
+				labels.FromStrings("__name__", "push_time_seconds", "instance", "db-1", "job", "backup"),
+			},
+			expectedValues: []float64{12.5, 3, 7},
+			expectedMetadata: []*mimirpb.MetricMetadata{
+				{MetricFamilyName: "backup_duration_seconds", Type: mimirpb.GAUGE, Help: "Duration of the backup."},
+				{MetricFamilyName: "backup_runs_total", Type: mimirpb.COUNTER},
+			},
+		},
+		"OpenMetrics format with exemplars": {
+			path:        "/api/v1/push/metrics/job/ci",
+			contentType: "application/openmetrics-text; version=1.0.0",
+			body: `# TYPE ci_builds counter
+# UNIT ci_builds builds
+ci_builds_total 4 # {build_id="42"} 1 1700000000.000
+# EOF
+`,
+			expectedCode: http.StatusOK,
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("__name__", "ci_builds_total", "job", "ci"),
+				labels.FromStrings("__name__", "push_time_seconds", "job", "ci"),
+			},
+			expectedValues: []float64{4},
+			expectedMetadata: []*mimirpb.MetricMetadata{
+				{MetricFamilyName: "ci_builds", Type: mimirpb.COUNTER, Unit: "builds"},
+			},
+			expectedExemplar: &mimirpb.Exemplar{
+				Labels:      []mimirpb.LabelAdapter{{Name: "build_id", Value: "42"}},
+				Value:       1,
+				TimestampMs: 1700000000000,
+			},
+		},
+		"samples with timestamps are rejected": {
+			path:             "/api/v1/push/metrics/job/backup",
+			body:             "backup_runs_total 7 1700000000000\n",
+			expectedCode:     http.StatusBadRequest,
+			expectedErrorMsg: "pushed metrics must not have timestamps",
+		},
+		"invalid exposition": {
+			path:         "/api/v1/push/metrics/job/backup",
+			body:         "backup_runs_total{\n",
+			expectedCode: http.StatusBadRequest,
+		},
+		"invalid grouping key": {
+			path:             "/api/v1/push/metrics/job/backup/instance",
+			body:             "backup_runs_total 7\n",
+			expectedCode:     http.StatusBadRequest,
+			expectedErrorMsg: "expected label name and value pairs",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var limits validation.Limits
+			flagext.DefaultValues(&limits)
+			overrides, err := validation.NewOverrides(limits, nil)
+			require.NoError(t, err)
+
+			var pushed *mimirpb.WriteRequest
+			push := func(_ context.Context, req *Request) error {
+				pushed, err = req.WriteRequest()
+				return err
+			}
+			handler := ExpositionHandler(100000, nil, overrides, nil, push)
+
+			req, err := http.NewRequestWithContext(user.InjectOrgID(context.Background(), "user"), "POST", "http://localhost"+tc.path, bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", tc.contentType)
+
+			resp := httptest.NewRecorder()
+			before := time.Now().UnixMilli()
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code, resp.Body.String())
+			if tc.expectedCode != http.StatusOK {
+				assert.Contains(t, resp.Body.String(), tc.expectedErrorMsg)
+				return
+			}
+
+			require.Len(t, pushed.Timeseries, len(tc.expectedSeries))
+			for i, ts := range pushed.Timeseries {
+				assert.Equal(t, tc.expectedSeries[i], mimirpb.FromLabelAdaptersToLabels(ts.Labels))
+				require.Len(t, ts.Samples, 1)
+
+				// All the samples are assigned the time the request has been received.
+				assert.GreaterOrEqual(t, ts.Samples[0].TimestampMs, before)
+				assert.Equal(t, pushed.Timeseries[0].Samples[0].TimestampMs, ts.Samples[0].TimestampMs)
+
+				if i < len(tc.expectedValues) {
+					assert.Equal(t, tc.expectedValues[i], ts.Samples[0].Value)
+				} else {
+					// The push time series holds the time of the push in seconds.
+					assert.Equal(t, float64(ts.Samples[0].TimestampMs)/1000, ts.Samples[0].Value)
+				}
+			}
+
+			assert.Equal(t, tc.expectedMetadata, pushed.Metadata)
+			if tc.expectedExemplar != nil {
+				assert.Equal(t, []mimirpb.Exemplar{*tc.expectedExemplar}, pushed.Timeseries[0].Exemplars)
+			}
+		})
+	}
+}
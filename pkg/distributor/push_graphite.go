@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/mimir/pkg/distributor/graphite"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+// GraphiteHandler is a http.Handler which accepts samples in the Graphite plaintext protocol, one
+// "<path> <value> [<timestamp>]" sample per line. The samples are converted into a WriteRequest,
+// which is pushed like a remote-write request. The metric paths are mapped to metric names and labels
+// with the tenant's Graphite templates, and the tags of Graphite tagged series are added as labels.
+func GraphiteHandler(
+	maxRecvMsgSize int,
+	sourceIPs *middleware.SourceIPExtractor,
+	limits *validation.Overrides,
+	pushMetrics *PushMetrics,
+	push PushFunc,
+) http.Handler {
+	return handler(maxRecvMsgSize, sourceIPs, false, limits, push, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, dst []byte, req *mimirpb.PreallocWriteRequest) ([]byte, error) {
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// The templates have been validated when loading the limits.
+		templates, err := graphite.ParseTemplates(limits.GraphiteTemplates(tenantID))
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := readTextBody(ctx, r, maxRecvMsgSize, dst, pushMetrics, graphiteHandlerName)
+		if err != nil {
+			return body, err
+		}
+
+		req.Timeseries, err = graphiteToTimeseries(body, templates, time.Now().UnixMilli())
+		return body, err
+	})
+}
+
+// graphiteToTimeseries converts the plaintext protocol body into series. The samples without a
+// timestamp are assigned nowMs.
+func graphiteToTimeseries(body []byte, templates graphite.Templates, nowMs int64) ([]mimirpb.PreallocTimeseries, error) {
+	timeseries := mimirpb.PreallocTimeseriesSliceFromPool()
+
+	err := graphite.Parse(body, func(p graphite.Point) error {
+		ts := nowMs
+		if p.HasTimestamp {
+			ts = p.TimestampMs
+		}
+
+		name, tags := templates.Apply(p.Path)
+
+		series := mimirpb.TimeseriesFromPool()
+		series.Labels = append(series.Labels, mimirpb.LabelAdapter{Name: model.MetricNameLabel, Value: sanitizeMetricName(name)})
+
+		// The tags of a tagged series take precedence over the labels extracted by the templates.
+		for _, t := range p.Tags {
+			series.Labels = append(series.Labels, mimirpb.LabelAdapter{Name: sanitizeLabelName(t.Key), Value: t.Value})
+		}
+		for _, t := range tags {
+			if !hasLabel(series.Labels, t.Key) {
+				series.Labels = append(series.Labels, mimirpb.LabelAdapter{Name: t.Key, Value: t.Value})
+			}
+		}
+		series.Samples = append(series.Samples, mimirpb.Sample{TimestampMs: ts, Value: p.Value})
+
+		timeseries = append(timeseries, mimirpb.PreallocTimeseries{TimeSeries: series})
+		return nil
+	})
+	if err != nil {
+		mimirpb.ReuseSlice(timeseries)
+		return nil, err
+	}
+
+	return timeseries, nil
+}
+
+func hasLabel(lbls []mimirpb.LabelAdapter, name string) bool {
+	for _, l := range lbls {
+		if l.Name == name {
+			return true
+		}
+	}
+	return false
+}
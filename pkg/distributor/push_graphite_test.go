@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestGraphiteHandler(t *testing.T) {
+	tests := map[string]struct {
+		templates        []string
+		encoding         string
+		body             string
+		expectedCode     int
+		expectedSeries   []labels.Labels
+		expectedSamples  []mimirpb.Sample
+		expectedErrorMsg string
+	}{
+		"no templates": {
+			body:         "servers.host-1.cpu.load 1.5 1700000000\nservers.host-1.cpu.load;env=prod;data.center=eu 2 1700000000.5\n",
+			expectedCode: http.StatusOK,
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("__name__", "servers_host_1_cpu_load"),
+				labels.FromStrings("__name__", "servers_host_1_cpu_load", "env", "prod", "data_center", "eu"),
+			},
+			expectedSamples: []mimirpb.Sample{
+				{TimestampMs: 1700000000000, Value: 1.5},
+				{TimestampMs: 1700000000500, Value: 2},
+			},
+		},
+		"templates": {
+			templates: []string{
+				"servers.* .host.measurement* region=eu",
+				"measurement.measurement.job",
+			},
+			body:         "servers.host-1.cpu.load 1.5 1700000000\nservers.host-1.cpu.load;region=us 2 1700000000\napp.requests.api 3\n",
+			expectedCode: http.StatusOK,
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("__name__", "cpu_load", "host", "host-1", "region", "eu"),
+				labels.FromStrings("__name__", "cpu_load", "host", "host-1", "region", "us"),
+				labels.FromStrings("__name__", "app_requests", "job", "api"),
+			},
+			expectedSamples: []mimirpb.Sample{
+				{TimestampMs: 1700000000000, Value: 1.5},
+				{TimestampMs: 1700000000000, Value: 2},
+				{TimestampMs: 0, Value: 3}, // The sample has no timestamp.
+			},
+		},
+		"zstd compressed body": {
+			encoding:        "zstd",
+			body:            "cpu.load 1 1700000000",
+			expectedCode:    http.StatusOK,
+			expectedSeries:  []labels.Labels{labels.FromStrings("__name__", "cpu_load")},
+			expectedSamples: []mimirpb.Sample{{TimestampMs: 1700000000000, Value: 1}},
+		},
+		"invalid line": {
+			body:             "cpu.load 1 1700000000\ncpu.load",
+			expectedCode:     http.StatusBadRequest,
+			expectedErrorMsg: "line 2: invalid line",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var limits validation.Limits
+			flagext.DefaultValues(&limits)
+			limits.GraphiteTemplates = tc.templates
+			overrides, err := validation.NewOverrides(limits, nil)
+			require.NoError(t, err)
+
+			push, pushed := capturePushFunc()
+			handler := GraphiteHandler(100000, nil, overrides, nil, push)
+
+			reqBody := []byte(tc.body)
+			if tc.encoding != "" {
+				reqBody = compressBody(t, tc.encoding, reqBody)
+			}
+			req, err := http.NewRequestWithContext(user.InjectOrgID(context.Background(), "user"), "POST", "http://localhost/api/v1/push/graphite", bytes.NewReader(reqBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Encoding", tc.encoding)
+
+			resp := httptest.NewRecorder()
+			before := time.Now().UnixMilli()
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code, resp.Body.String())
+			if tc.expectedErrorMsg != "" {
+				assert.Contains(t, resp.Body.String(), tc.expectedErrorMsg)
+				return
+			}
+
+			series, samples := pushed()
+			for i := range tc.expectedSamples {
+				// The samples without a timestamp are assigned the time the request has been received.
+				if tc.expectedSamples[i].TimestampMs == 0 && i < len(samples) {
+					assert.GreaterOrEqual(t, samples[i].TimestampMs, before)
+					tc.expectedSamples[i].TimestampMs = samples[i].TimestampMs
+				}
+			}
+			assert.Equal(t, tc.expectedSeries, series)
+			assert.Equal(t, tc.expectedSamples, samples)
+		})
+	}
+}
+
+func TestGraphiteHandler_ShouldApplyLimitsAndRelabeling(t *testing.T) {
+	var limits validation.Limits
+	flagext.DefaultValues(&limits)
+	limits.GraphiteTemplates = []string{".host.measurement*"}
+	limits.DropLabels = []string{"host"}
+	limits.MaxLabelValueLength = 10
+
+	ds, ingesters, _ := prepare(t, prepConfig{
+		numIngesters:      1,
+		happyIngesters:    1,
+		numDistributors:   1,
+		replicationFactor: 1,
+		limits:            &limits,
+	})
+
+	handler := GraphiteHandler(100000, nil, ds[0].limits, nil, ds[0].PushWithMiddlewares)
+	now := time.Now().Unix()
+
+	push := func(body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequestWithContext(user.InjectOrgID(context.Background(), "user"), "POST", "http://localhost/api/v1/push/graphite", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := push("servers.host-1.cpu.load 1 " + strconv.FormatInt(now, 10) + "\n")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	series := ingesters[0].series()
+	require.Len(t, series, 1)
+	for _, ts := range series {
+		assert.Equal(t, labels.FromStrings("__name__", "cpu_load"), mimirpb.FromLabelAdaptersToLabels(ts.Labels))
+	}
+
+	// The label values length limit applies to the converted series.
+	resp = push("servers.host-1.cpu.load;env=a-very-long-value 1\n")
+	assert.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/mimir/pkg/distributor/influx"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+// influxValueField is the name of the field whose samples are named after the measurement only.
+const influxValueField = "value"
+
+// InfluxHandler is a http.Handler which accepts samples in the InfluxDB line protocol, as sent to the
+// InfluxDB 1.x write API. The samples are converted into a WriteRequest, which is pushed like a
+// remote-write request: each numeric or boolean field of a point becomes a series, labelled with the
+// point's tags, and string fields are skipped.
+func InfluxHandler(
+	maxRecvMsgSize int,
+	sourceIPs *middleware.SourceIPExtractor,
+	limits *validation.Overrides,
+	pushMetrics *PushMetrics,
+	push PushFunc,
+) http.Handler {
+	return handler(maxRecvMsgSize, sourceIPs, false, limits, push, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, dst []byte, req *mimirpb.PreallocWriteRequest) ([]byte, error) {
+		unit, err := influx.ParsePrecision(r.URL.Query().Get("precision"))
+		if err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := readTextBody(ctx, r, maxRecvMsgSize, dst, pushMetrics, influxHandlerName)
+		if err != nil {
+			return body, err
+		}
+
+		req.Timeseries, err = influxToTimeseries(body, unit, limits.InfluxFieldLabel(tenantID), limits.InfluxTagLabelMapping(tenantID), time.Now().UnixMilli())
+		return body, err
+	})
+}
+
+// influxToTimeseries converts the line protocol body into series. Timestamps are in the given unit,
+// and the points without a timestamp are assigned nowMs.
+func influxToTimeseries(body []byte, unit time.Duration, fieldLabel string, tagMapping map[string]string, nowMs int64) ([]mimirpb.PreallocTimeseries, error) {
+	timeseries := mimirpb.PreallocTimeseriesSliceFromPool()
+
+	err := influx.Parse(body, func(p influx.Point) error {
+		ts := nowMs
+		if p.HasTimestamp {
+			ts = influx.ToMilliseconds(p.Timestamp, unit)
+		}
+
+		tags := make([]mimirpb.LabelAdapter, 0, len(p.Tags))
+		for _, t := range p.Tags {
+			name, ok := tagMapping[t.Key]
+			if !ok {
+				name = sanitizeLabelName(t.Key)
+			}
+			tags = append(tags, mimirpb.LabelAdapter{Name: name, Value: t.Value})
+		}
+
+		for _, f := range p.Fields {
+			metricName := p.Measurement
+			if fieldLabel == "" && f.Key != influxValueField {
+				metricName += "_" + f.Key
+			}
+
+			series := mimirpb.TimeseriesFromPool()
+			series.Labels = append(series.Labels, mimirpb.LabelAdapter{Name: model.MetricNameLabel, Value: sanitizeMetricName(metricName)})
+			series.Labels = append(series.Labels, tags...)
+			if fieldLabel != "" {
+				series.Labels = append(series.Labels, mimirpb.LabelAdapter{Name: fieldLabel, Value: f.Key})
+			}
+			series.Samples = append(series.Samples, mimirpb.Sample{TimestampMs: ts, Value: f.Value})
+
+			timeseries = append(timeseries, mimirpb.PreallocTimeseries{TimeSeries: series})
+		}
+		return nil
+	})
+	if err != nil {
+		mimirpb.ReuseSlice(timeseries)
+		return nil, err
+	}
+
+	return timeseries, nil
+}
+
+// sanitizeMetricName replaces the characters not allowed in metric names with underscores.
+func sanitizeMetricName(name string) string {
+	return sanitizeName(name, true)
+}
+
+// sanitizeLabelName replaces the characters not allowed in label names with underscores.
+func sanitizeLabelName(name string) string {
+	return sanitizeName(name, false)
+}
+
+func sanitizeName(name string, allowColons bool) string {
+	isValid := func(r rune, first bool) bool {
+		return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (!first && r >= '0' && r <= '9') || (allowColons && r == ':')
+	}
+
+	valid := name != ""
+	for i, r := range name {
+		if !isValid(r, i == 0) {
+			valid = false
+			break
+		}
+	}
+	if valid {
+		return name
+	}
+
+	sb := strings.Builder{}
+	sb.Grow(len(name) + 1)
+
+	// Names can't start with a digit, so we prefix them with an underscore.
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		sb.WriteByte('_')
+	}
+	for _, r := range name {
+		if r < utf8.RuneSelf && isValid(r, false) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
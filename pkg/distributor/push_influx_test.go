@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestInfluxHandler(t *testing.T) {
+	const body = `# comment
+cpu,host=server-1,data.center=eu usage_user=1.5,usage_system=2i,online=true,name="server" 1700000000000000000
+mem,host=server-1 value=10u
+
+disk\ io,host=server\,2 reads=3 1700000000000000000
+`
+
+	tests := map[string]struct {
+		fieldLabel       string
+		tagMapping       map[string]string
+		precision        string
+		encoding         string
+		body             string
+		expectedCode     int
+		expectedSeries   []labels.Labels
+		expectedSamples  []mimirpb.Sample
+		expectedErrorMsg string
+	}{
+		"default conversion": {
+			body:         body,
+			expectedCode: http.StatusOK,
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("__name__", "cpu_usage_user", "host", "server-1", "data_center", "eu"),
+				labels.FromStrings("__name__", "cpu_usage_system", "host", "server-1", "data_center", "eu"),
+				labels.FromStrings("__name__", "cpu_online", "host", "server-1", "data_center", "eu"),
+				labels.FromStrings("__name__", "mem", "host", "server-1"),
+				labels.FromStrings("__name__", "disk_io_reads", "host", "server,2"),
+			},
+			expectedSamples: []mimirpb.Sample{
+				{TimestampMs: 1700000000000, Value: 1.5},
+				{TimestampMs: 1700000000000, Value: 2},
+				{TimestampMs: 1700000000000, Value: 1},
+				{TimestampMs: 0, Value: 10}, // The point has no timestamp.
+				{TimestampMs: 1700000000000, Value: 3},
+			},
+		},
+		"field label and tag mapping": {
+			fieldLabel:   "field",
+			tagMapping:   map[string]string{"host": "instance"},
+			body:         "cpu,host=server-1 usage_user=1.5,value=2 1700000000000000000",
+			expectedCode: http.StatusOK,
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("__name__", "cpu", "instance", "server-1", "field", "usage_user"),
+				labels.FromStrings("__name__", "cpu", "instance", "server-1", "field", "value"),
+			},
+			expectedSamples: []mimirpb.Sample{
+				{TimestampMs: 1700000000000, Value: 1.5},
+				{TimestampMs: 1700000000000, Value: 2},
+			},
+		},
+		"precision": {
+			precision:       "s",
+			body:            "cpu value=1 1700000000",
+			expectedCode:    http.StatusOK,
+			expectedSeries:  []labels.Labels{labels.FromStrings("__name__", "cpu")},
+			expectedSamples: []mimirpb.Sample{{TimestampMs: 1700000000000, Value: 1}},
+		},
+		"gzip compressed body": {
+			encoding:        "gzip",
+			body:            "cpu value=1 1700000000000000000",
+			expectedCode:    http.StatusOK,
+			expectedSeries:  []labels.Labels{labels.FromStrings("__name__", "cpu")},
+			expectedSamples: []mimirpb.Sample{{TimestampMs: 1700000000000, Value: 1}},
+		},
+		"invalid precision": {
+			precision:        "d",
+			body:             "cpu value=1",
+			expectedCode:     http.StatusBadRequest,
+			expectedErrorMsg: `invalid precision "d"`,
+		},
+		"invalid line": {
+			body:             "cpu value=1\ncpu,host value=1",
+			expectedCode:     http.StatusBadRequest,
+			expectedErrorMsg: "line 2: missing value of tag \"host\"",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var limits validation.Limits
+			flagext.DefaultValues(&limits)
+			limits.InfluxFieldLabel = tc.fieldLabel
+			limits.InfluxTagLabelMapping = tc.tagMapping
+			overrides, err := validation.NewOverrides(limits, nil)
+			require.NoError(t, err)
+
+			push, pushed := capturePushFunc()
+			handler := InfluxHandler(100000, nil, overrides, nil, push)
+
+			reqBody := []byte(tc.body)
+			if tc.encoding != "" {
+				reqBody = compressBody(t, tc.encoding, reqBody)
+			}
+			req, err := http.NewRequestWithContext(user.InjectOrgID(context.Background(), "user"), "POST", "http://localhost/api/v1/push/influx/write?precision="+tc.precision, bytes.NewReader(reqBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Encoding", tc.encoding)
+
+			resp := httptest.NewRecorder()
+			before := time.Now().UnixMilli()
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code, resp.Body.String())
+			if tc.expectedErrorMsg != "" {
+				assert.Contains(t, resp.Body.String(), tc.expectedErrorMsg)
+				return
+			}
+
+			series, samples := pushed()
+			for i := range tc.expectedSamples {
+				// The points without a timestamp are assigned the time the request has been received.
+				if tc.expectedSamples[i].TimestampMs == 0 && i < len(samples) {
+					assert.GreaterOrEqual(t, samples[i].TimestampMs, before)
+					tc.expectedSamples[i].TimestampMs = samples[i].TimestampMs
+				}
+			}
+			assert.Equal(t, tc.expectedSeries, series)
+			assert.Equal(t, tc.expectedSamples, samples)
+		})
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	for input, expected := range map[string][2]string{
+		"metric_name:total": {"metric_name:total", "metric_name_total"},
+		"data.center":       {"data_center", "data_center"},
+		"1xx":               {"_1xx", "_1xx"},
+		"température":       {"temp_rature", "temp_rature"},
+		"":                  {"_", "_"},
+	} {
+		assert.Equal(t, expected[0], sanitizeMetricName(input), input)
+		assert.Equal(t, expected[1], sanitizeLabelName(input), input)
+	}
+}
+
+// capturePushFunc returns a PushFunc recording the pushed series, and a function returning
+// the labels and the samples of the pushed series.
+func capturePushFunc() (PushFunc, func() ([]labels.Labels, []mimirpb.Sample)) {
+	var (
+		series  []labels.Labels
+		samples []mimirpb.Sample
+	)
+
+	push := func(_ context.Context, req *Request) error {
+		defer req.CleanUp()
+
+		writeReq, err := req.WriteRequest()
+		if err != nil {
+			return err
+		}
+		for _, ts := range writeReq.Timeseries {
+			b := labels.NewScratchBuilder(len(ts.Labels))
+			for _, l := range ts.Labels {
+				b.Add(l.Name, l.Value)
+			}
+			b.Sort()
+			series = append(series, b.Labels())
+			samples = append(samples, ts.Samples...)
+		}
+		return nil
+	}
+
+	return push, func() ([]labels.Labels, []mimirpb.Sample) {
+		return series, samples
+	}
+}
@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/mimir/pkg/distributor/writev2pb"
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+const (
+	// remoteWriteV1ProtoMessage and remoteWriteV2ProtoMessage are the values of the "proto"
+	// Content-Type parameter used by clients to negotiate the remote-write protocol version.
+	remoteWriteV1ProtoMessage = "prometheus.WriteRequest"
+	remoteWriteV2ProtoMessage = "io.prometheus.write.v2.Request"
+
+	writtenSamplesHeader    = "X-Prometheus-Remote-Write-Samples-Written"
+	writtenHistogramsHeader = "X-Prometheus-Remote-Write-Histograms-Written"
+	writtenExemplarsHeader  = "X-Prometheus-Remote-Write-Exemplars-Written"
+)
+
+// remoteWriteProtoMessage returns the remote-write protobuf message negotiated by the client
+// via the Content-Type header. Requests without an explicit "proto" parameter are considered
+// Remote-Write 1.0 requests, to keep backward compatibility with existing clients.
+func remoteWriteProtoMessage(contentType string) (string, error) {
+	if contentType == "" {
+		return remoteWriteV1ProtoMessage, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != pbContentType {
+		return remoteWriteV1ProtoMessage, nil
+	}
+
+	switch msg := params["proto"]; msg {
+	case "", remoteWriteV1ProtoMessage:
+		return remoteWriteV1ProtoMessage, nil
+	case remoteWriteV2ProtoMessage:
+		return remoteWriteV2ProtoMessage, nil
+	default:
+		return "", httpgrpc.Errorf(http.StatusUnsupportedMediaType, "unsupported remote-write protobuf message: %s, supported: [%s, %s]", msg, remoteWriteV1ProtoMessage, remoteWriteV2ProtoMessage)
+	}
+}
+
+type remoteWriteV2StatsContextKey int
+
+const remoteWriteV2StatsKey remoteWriteV2StatsContextKey = 0
+
+// remoteWriteV2Stats holds the number of samples, histograms and exemplars of a Remote-Write 2.0
+// request written to the ingesters, which are reported back to the client through response headers.
+// The series and exemplars discarded by the distributor, for example because they are invalid, are
+// not forwarded to the ingesters, and so they are not counted.
+type remoteWriteV2Stats struct {
+	samples, histograms, exemplars int
+}
+
+func (s *remoteWriteV2Stats) setHeaders(h http.Header) {
+	h.Set(writtenSamplesHeader, strconv.Itoa(s.samples))
+	h.Set(writtenHistogramsHeader, strconv.Itoa(s.histograms))
+	h.Set(writtenExemplarsHeader, strconv.Itoa(s.exemplars))
+}
+
+func contextWithRemoteWriteV2Stats(ctx context.Context, stats *remoteWriteV2Stats) context.Context {
+	return context.WithValue(ctx, remoteWriteV2StatsKey, stats)
+}
+
+// remoteWriteV2WrittenStats returns the number of samples, histograms and exemplars of the request,
+// if the stats of the request written to the ingesters are tracked in the context, or nil otherwise.
+func remoteWriteV2WrittenStats(ctx context.Context, req *mimirpb.WriteRequest) *remoteWriteV2Stats {
+	if _, ok := ctx.Value(remoteWriteV2StatsKey).(*remoteWriteV2Stats); !ok {
+		return nil
+	}
+
+	written := &remoteWriteV2Stats{}
+	for _, ts := range req.Timeseries {
+		written.samples += len(ts.Samples)
+		written.histograms += len(ts.Histograms)
+		written.exemplars += len(ts.Exemplars)
+	}
+	return written
+}
+
+// recordRemoteWriteV2Written adds the written stats to the ones tracked in the context.
+func recordRemoteWriteV2Written(ctx context.Context, written *remoteWriteV2Stats) {
+	stats, ok := ctx.Value(remoteWriteV2StatsKey).(*remoteWriteV2Stats)
+	if !ok || written == nil {
+		return
+	}
+	stats.samples += written.samples
+	stats.histograms += written.histograms
+	stats.exemplars += written.exemplars
+}
+
+// remoteWriteV2ResponseWriter sets the written stats headers right before the response
+// is written, once the request has been pushed, whether it succeeded or not.
+type remoteWriteV2ResponseWriter struct {
+	http.ResponseWriter
+	stats      *remoteWriteV2Stats
+	headersSet bool
+}
+
+func (w *remoteWriteV2ResponseWriter) setHeaders() {
+	if !w.headersSet {
+		w.headersSet = true
+		w.stats.setHeaders(w.Header())
+	}
+}
+
+func (w *remoteWriteV2ResponseWriter) WriteHeader(statusCode int) {
+	w.setHeaders()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *remoteWriteV2ResponseWriter) Write(b []byte) (int, error) {
+	w.setHeaders()
+	return w.ResponseWriter.Write(b)
+}
+
+// remoteWriteV2ToWriteRequest converts a Remote-Write 2.0 request into dst, resolving all
+// symbol references. Metadata is de-duplicated by metric family name. The created timestamp
+// of series is kept, unless the series is a gauge, so that ingesters can inject a zero sample
+// at the created timestamp.
+func remoteWriteV2ToWriteRequest(src *writev2pb.Request, dst *mimirpb.PreallocWriteRequest) error {
+	var metadata map[string]*mimirpb.MetricMetadata
+
+	symbol := func(ref uint32) (string, error) {
+		if int(ref) >= len(src.Symbols) {
+			return "", fmt.Errorf("symbol reference %d is out of range, the request has %d symbols", ref, len(src.Symbols))
+		}
+		return src.Symbols[ref], nil
+	}
+
+	labelsFromRefs := func(refs []uint32) ([]mimirpb.LabelAdapter, error) {
+		if len(refs)%2 != 0 {
+			return nil, fmt.Errorf("invalid number of label references: %d, it must be even", len(refs))
+		}
+		lbls := make([]mimirpb.LabelAdapter, 0, len(refs)/2)
+		for i := 0; i < len(refs); i += 2 {
+			name, err := symbol(refs[i])
+			if err != nil {
+				return nil, err
+			}
+			value, err := symbol(refs[i+1])
+			if err != nil {
+				return nil, err
+			}
+			lbls = append(lbls, mimirpb.LabelAdapter{Name: name, Value: value})
+		}
+		return lbls, nil
+	}
+
+	dst.Timeseries = mimirpb.PreallocTimeseriesSliceFromPool()
+	for _, series := range src.Timeseries {
+		lbls, err := labelsFromRefs(series.LabelsRefs)
+		if err != nil {
+			return err
+		}
+
+		exemplars := make([]mimirpb.Exemplar, 0, len(series.Exemplars))
+		for _, e := range series.Exemplars {
+			exemplarLabels, err := labelsFromRefs(e.LabelsRefs)
+			if err != nil {
+				return err
+			}
+			exemplars = append(exemplars, mimirpb.Exemplar{Labels: exemplarLabels, Value: e.Value, TimestampMs: e.Timestamp})
+		}
+
+		ts := mimirpb.TimeseriesFromPool()
+		ts.Labels = lbls
+		ts.Samples = series.Samples
+		ts.Histograms = series.Histograms
+		ts.Exemplars = exemplars
+		if !isGaugeMetricType(series.Metadata.Type) {
+			ts.CreatedTimestamp = series.CreatedTimestamp
+		}
+		dst.Timeseries = append(dst.Timeseries, mimirpb.PreallocTimeseries{TimeSeries: ts})
+
+		if md := series.Metadata; md.Type != writev2pb.METRIC_TYPE_UNSPECIFIED || md.HelpRef != 0 || md.UnitRef != 0 {
+			help, err := symbol(md.HelpRef)
+			if err != nil {
+				return err
+			}
+			unit, err := symbol(md.UnitRef)
+			if err != nil {
+				return err
+			}
+
+			name := metricFamilyName(metricNameFromLabelAdapters(lbls), md.Type)
+			if metadata == nil {
+				metadata = map[string]*mimirpb.MetricMetadata{}
+			}
+			if _, ok := metadata[name]; !ok {
+				m := &mimirpb.MetricMetadata{
+					Type:             remoteWriteV2MetricTypeToMimir(md.Type),
+					MetricFamilyName: name,
+					Help:             help,
+					Unit:             unit,
+				}
+				metadata[name] = m
+				dst.Metadata = append(dst.Metadata, m)
+			}
+		}
+	}
+
+	return nil
+}
+
+// metricFamilyName returns the name of the metric family of the series with the input metric name.
+// The series of classic histograms and summaries are named after their metric family with the
+// _bucket, _count and _sum suffixes.
+func metricFamilyName(name string, t writev2pb.Metadata_MetricType) string {
+	var suffixes []string
+	switch t {
+	case writev2pb.METRIC_TYPE_HISTOGRAM, writev2pb.METRIC_TYPE_GAUGEHISTOGRAM:
+		suffixes = []string{"_bucket", "_count", "_sum"}
+	case writev2pb.METRIC_TYPE_SUMMARY:
+		suffixes = []string{"_count", "_sum"}
+	}
+
+	for _, suffix := range suffixes {
+		if family, ok := strings.CutSuffix(name, suffix); ok {
+			return family
+		}
+	}
+	return name
+}
+
+func metricNameFromLabelAdapters(lbls []mimirpb.LabelAdapter) string {
+	for _, l := range lbls {
+		if l.Name == model.MetricNameLabel {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+func isGaugeMetricType(t writev2pb.Metadata_MetricType) bool {
+	return t == writev2pb.METRIC_TYPE_GAUGE || t == writev2pb.METRIC_TYPE_GAUGEHISTOGRAM
+}
+
+func remoteWriteV2MetricTypeToMimir(t writev2pb.Metadata_MetricType) mimirpb.MetricMetadata_MetricType {
+	switch t {
+	case writev2pb.METRIC_TYPE_COUNTER:
+		return mimirpb.COUNTER
+	case writev2pb.METRIC_TYPE_GAUGE:
+		return mimirpb.GAUGE
+	case writev2pb.METRIC_TYPE_HISTOGRAM:
+		return mimirpb.HISTOGRAM
+	case writev2pb.METRIC_TYPE_GAUGEHISTOGRAM:
+		return mimirpb.GAUGEHISTOGRAM
+	case writev2pb.METRIC_TYPE_SUMMARY:
+		return mimirpb.SUMMARY
+	case writev2pb.METRIC_TYPE_INFO:
+		return mimirpb.INFO
+	case writev2pb.METRIC_TYPE_STATESET:
+		return mimirpb.STATESET
+	}
+	return mimirpb.UNKNOWN
+}
@@ -14,15 +14,21 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/snappy"
+	"github.com/grafana/dskit/flagext"
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/middleware"
 	"github.com/grafana/dskit/tenant"
 	"github.com/grafana/dskit/user"
+	"github.com/klauspost/compress/zstd"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/stretchr/testify/assert"
@@ -31,6 +37,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 
+	"github.com/grafana/mimir/pkg/distributor/writev2pb"
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/util/log"
 	"github.com/grafana/mimir/pkg/util/test"
@@ -40,11 +47,170 @@ import (
 func TestHandler_remoteWrite(t *testing.T) {
 	req := createRequest(t, createPrometheusRemoteWriteProtobuf(t))
 	resp := httptest.NewRecorder()
-	handler := Handler(100000, nil, false, nil, verifyWritePushFunc(t, mimirpb.API))
+	handler := Handler(100000, nil, false, nil, nil, verifyWritePushFunc(t, mimirpb.API))
 	handler.ServeHTTP(resp, req)
 	assert.Equal(t, 200, resp.Code)
 }
 
+func TestHandler_remoteWriteCompression(t *testing.T) {
+	protobuf := createPrometheusRemoteWriteProtobuf(t)
+
+	for _, encoding := range []string{"", "snappy", "gzip", "zstd"} {
+		t.Run(fmt.Sprintf("encoding=%q", encoding), func(t *testing.T) {
+			body := compressBody(t, encoding, protobuf)
+			req, err := http.NewRequest("POST", "http://localhost/", bytes.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-protobuf")
+			if encoding != "" {
+				req.Header.Set("Content-Encoding", encoding)
+			}
+
+			reg := prometheus.NewPedanticRegistry()
+			resp := httptest.NewRecorder()
+			handler := Handler(100000, nil, false, nil, newPushMetrics(reg), verifyWritePushFunc(t, mimirpb.API))
+			handler.ServeHTTP(resp, req)
+			assert.Equal(t, 200, resp.Code)
+
+			expectedEncoding := encoding
+			if expectedEncoding == "" {
+				expectedEncoding = "snappy"
+			}
+			assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(fmt.Sprintf(`
+				# HELP cortex_distributor_push_compressed_bytes_total The total size of the push requests body, as received on the wire, by Content-Encoding.
+				# TYPE cortex_distributor_push_compressed_bytes_total counter
+				cortex_distributor_push_compressed_bytes_total{encoding="%[1]s",handler="push"} %[2]d
+				# HELP cortex_distributor_push_decompressed_bytes_total The total size of the push requests body after decompression, by Content-Encoding.
+				# TYPE cortex_distributor_push_decompressed_bytes_total counter
+				cortex_distributor_push_decompressed_bytes_total{encoding="%[1]s",handler="push"} %[3]d
+				# HELP cortex_distributor_push_requests_by_encoding_total The total number of push requests successfully decoded, by Content-Encoding.
+				# TYPE cortex_distributor_push_requests_by_encoding_total counter
+				cortex_distributor_push_requests_by_encoding_total{encoding="%[1]s",handler="push"} 1
+			`, expectedEncoding, len(body), len(protobuf)))))
+		})
+	}
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "http://localhost/", bytes.NewReader(protobuf))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.Header.Set("Content-Encoding", "br")
+
+		resp := httptest.NewRecorder()
+		handler := Handler(100000, nil, false, nil, nil, readBodyPushFunc(t))
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+	})
+
+	for _, encoding := range []string{"gzip", "zstd"} {
+		t.Run(fmt.Sprintf("decompressed body exceeding the limit with encoding=%s", encoding), func(t *testing.T) {
+			body := compressBody(t, encoding, make([]byte, 100000))
+			require.Less(t, len(body), 1000)
+
+			req, err := http.NewRequest("POST", "http://localhost/", bytes.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-protobuf")
+			req.Header.Set("Content-Encoding", encoding)
+
+			resp := httptest.NewRecorder()
+			handler := Handler(1000, nil, false, nil, nil, readBodyPushFunc(t))
+			handler.ServeHTTP(resp, req)
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			assert.Contains(t, resp.Body.String(), "the incoming push request has been rejected because its message size is larger than the allowed limit of 1000 bytes")
+		})
+	}
+}
+
+func TestHandler_sharedPushMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	pushMetrics := newPushMetrics(reg)
+
+	// Building multiple handlers with the same metrics must not register them again.
+	for i := 0; i < 2; i++ {
+		req := createRequest(t, createPrometheusRemoteWriteProtobuf(t))
+		resp := httptest.NewRecorder()
+		handler := Handler(100000, nil, false, nil, pushMetrics, verifyWritePushFunc(t, mimirpb.API))
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, 200, resp.Code)
+	}
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_distributor_push_requests_by_encoding_total The total number of push requests successfully decoded, by Content-Encoding.
+		# TYPE cortex_distributor_push_requests_by_encoding_total counter
+		cortex_distributor_push_requests_by_encoding_total{encoding="snappy",handler="push"} 2
+	`), "cortex_distributor_push_requests_by_encoding_total"))
+}
+
+func TestHandler_otlpCompression(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("foo")
+	datapoint := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	datapoint.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	datapoint.SetDoubleValue(1)
+
+	protobuf, err := pmetricotlp.NewExportRequestFromMetrics(md).MarshalProto()
+	require.NoError(t, err)
+
+	for _, encoding := range []string{"", "gzip", "zstd"} {
+		t.Run(fmt.Sprintf("encoding=%q", encoding), func(t *testing.T) {
+			req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
+			body := protobuf
+			if encoding != "" {
+				body = compressBody(t, encoding, protobuf)
+				req.Header.Set("Content-Encoding", encoding)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			req.ContentLength = int64(len(body))
+
+			reg := prometheus.NewPedanticRegistry()
+			resp := httptest.NewRecorder()
+			handler := OTLPHandler(100000, nil, false, true, validation.MockDefaultOverrides(), reg, newPushMetrics(reg), func(ctx context.Context, pushReq *Request) error {
+				request, err := pushReq.WriteRequest()
+				require.NoError(t, err)
+				require.Len(t, request.Timeseries, 1)
+				pushReq.CleanUp()
+				return nil
+			})
+			handler.ServeHTTP(resp, req)
+			assert.Equal(t, 200, resp.Code)
+
+			expectedEncoding := encoding
+			if expectedEncoding == "" {
+				expectedEncoding = "none"
+			}
+			assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(fmt.Sprintf(`
+				# HELP cortex_distributor_push_requests_by_encoding_total The total number of push requests successfully decoded, by Content-Encoding.
+				# TYPE cortex_distributor_push_requests_by_encoding_total counter
+				cortex_distributor_push_requests_by_encoding_total{encoding="%s",handler="otlp"} 1
+			`, expectedEncoding)), "cortex_distributor_push_requests_by_encoding_total"))
+		})
+	}
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
+		req.Header.Set("Content-Encoding", "br")
+
+		resp := httptest.NewRecorder()
+		handler := OTLPHandler(100000, nil, false, true, validation.MockDefaultOverrides(), nil, nil, readBodyPushFunc(t))
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+	})
+
+	t.Run("decompressed body exceeding the limit", func(t *testing.T) {
+		req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
+		body := compressBody(t, "zstd", make([]byte, 100000))
+		req.Header.Set("Content-Encoding", "zstd")
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+
+		resp := httptest.NewRecorder()
+		handler := OTLPHandler(1000, nil, false, true, validation.MockDefaultOverrides(), nil, nil, readBodyPushFunc(t))
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+		assert.Contains(t, resp.Body.String(), "the incoming push request has been rejected because its message size is larger than the allowed limit of 1000 bytes")
+	})
+}
+
 func TestOtelMetricsToMetadata(t *testing.T) {
 	otelMetrics := pmetric.NewMetrics()
 	rs := otelMetrics.ResourceMetrics().AppendEmpty()
@@ -81,6 +247,14 @@ func TestOtelMetricsToMetadata(t *testing.T) {
 
 	res := otelMetricsToMetadata(otelMetrics)
 	assert.Equal(t, sampleMetadata, res)
+
+	// The unit suffix is added to the metric family name when metric suffixes are enabled.
+	sampleMetadata[0].MetricFamilyName = "name_Count"
+	sampleMetadata[1].MetricFamilyName = "test_Count"
+
+	normalizeMetricNames(otelMetrics)
+	res = otelMetricsToMetadata(otelMetrics)
+	assert.Equal(t, sampleMetadata, res)
 }
 
 func TestHandlerOTLPPush(t *testing.T) {
@@ -211,7 +385,7 @@ func TestHandlerOTLPPush(t *testing.T) {
 				return err
 			},
 			responseCode: http.StatusUnsupportedMediaType,
-			errMessage:   "Only \"gzip\" or no compression supported",
+			errMessage:   "Only \"gzip\", \"zstd\" or no compression supported",
 		},
 		{
 			name:       "Write histograms",
@@ -264,7 +438,7 @@ func TestHandlerOTLPPush(t *testing.T) {
 				req.Header.Set("Content-Encoding", tt.encoding)
 			}
 
-			handler := OTLPHandler(tt.maxMsgSize, nil, false, tt.enableOtelMetadataStorage, nil, nil, tt.verifyFunc)
+			handler := OTLPHandler(tt.maxMsgSize, nil, false, tt.enableOtelMetadataStorage, validation.MockDefaultOverrides(), nil, nil, tt.verifyFunc)
 
 			resp := httptest.NewRecorder()
 			handler.ServeHTTP(resp, req)
@@ -319,7 +493,7 @@ func TestHandler_otlpDroppedMetricsPanic(t *testing.T) {
 
 	req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
 	resp := httptest.NewRecorder()
-	handler := OTLPHandler(100000, nil, false, true, nil, nil, func(ctx context.Context, pushReq *Request) error {
+	handler := OTLPHandler(100000, nil, false, true, validation.MockDefaultOverrides(), nil, nil, func(ctx context.Context, pushReq *Request) error {
 		request, err := pushReq.WriteRequest()
 		assert.NoError(t, err)
 		assert.Len(t, request.Timeseries, 3)
@@ -359,7 +533,7 @@ func TestHandler_otlpDroppedMetricsPanic2(t *testing.T) {
 
 	req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
 	resp := httptest.NewRecorder()
-	handler := OTLPHandler(100000, nil, false, true, nil, nil, func(ctx context.Context, pushReq *Request) error {
+	handler := OTLPHandler(100000, nil, false, true, validation.MockDefaultOverrides(), nil, nil, func(ctx context.Context, pushReq *Request) error {
 		request, err := pushReq.WriteRequest()
 		assert.NoError(t, err)
 		assert.Len(t, request.Timeseries, 2)
@@ -385,7 +559,7 @@ func TestHandler_otlpDroppedMetricsPanic2(t *testing.T) {
 
 	req = createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
 	resp = httptest.NewRecorder()
-	handler = OTLPHandler(100000, nil, false, true, nil, nil, func(ctx context.Context, pushReq *Request) error {
+	handler = OTLPHandler(100000, nil, false, true, validation.MockDefaultOverrides(), nil, nil, func(ctx context.Context, pushReq *Request) error {
 		request, err := pushReq.WriteRequest()
 		assert.NoError(t, err)
 		assert.Len(t, request.Timeseries, 10) // 6 buckets (including +Inf) + 2 sum/count + 2 from the first case
@@ -397,6 +571,249 @@ func TestHandler_otlpDroppedMetricsPanic2(t *testing.T) {
 	assert.Equal(t, 200, resp.Code)
 }
 
+func TestHandler_otlpTranslationSettings(t *testing.T) {
+	md := pmetric.NewMetrics()
+	resource := md.ResourceMetrics().AppendEmpty()
+	resource.Resource().Attributes().PutStr("service.name", "service")
+	resource.Resource().Attributes().PutStr("k8s.namespace.name", "namespace")
+	resource.Resource().Attributes().PutStr("k8s.pod.name", "pod")
+
+	metric := resource.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("requests")
+	metric.SetUnit("s")
+	metric.SetEmptySum().SetIsMonotonic(true)
+	metric.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	datapoint := metric.Sum().DataPoints().AppendEmpty()
+	datapoint.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	datapoint.SetDoubleValue(1)
+	datapoint.Attributes().PutStr("k8s.pod.name", "overridden")
+
+	tests := map[string]struct {
+		limits         func(*validation.Limits)
+		expectedSeries []labels.Labels
+	}{
+		"default settings": {
+			limits: func(*validation.Limits) {},
+			expectedSeries: []labels.Labels{
+				labels.FromStrings(model.MetricNameLabel, "requests", "job", "service", "k8s_pod_name", "overridden"),
+				labels.FromStrings(model.MetricNameLabel, "target_info", "job", "service", "k8s_namespace_name", "namespace", "k8s_pod_name", "pod"),
+			},
+		},
+		"target_info disabled": {
+			limits: func(l *validation.Limits) {
+				l.OTelTargetInfoEnabled = false
+			},
+			expectedSeries: []labels.Labels{
+				labels.FromStrings(model.MetricNameLabel, "requests", "job", "service", "k8s_pod_name", "overridden"),
+			},
+		},
+		"metric suffixes enabled": {
+			limits: func(l *validation.Limits) {
+				l.OTelTargetInfoEnabled = false
+				l.OTelMetricSuffixesEnabled = true
+			},
+			expectedSeries: []labels.Labels{
+				labels.FromStrings(model.MetricNameLabel, "requests_seconds_total", "job", "service", "k8s_pod_name", "overridden"),
+			},
+		},
+		"resource attributes promoted": {
+			limits: func(l *validation.Limits) {
+				l.OTelTargetInfoEnabled = false
+				l.PromoteOTelResourceAttributes = []string{"k8s.namespace.name", "k8s.pod.name", "missing"}
+			},
+			expectedSeries: []labels.Labels{
+				labels.FromStrings(model.MetricNameLabel, "requests", "job", "service", "k8s_namespace_name", "namespace", "k8s_pod_name", "overridden"),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			limits := validation.MockOverrides(func(defaults *validation.Limits, _ map[string]*validation.Limits) {
+				tc.limits(defaults)
+			})
+
+			var actualSeries []labels.Labels
+			handler := OTLPHandler(100000, nil, false, false, limits, nil, nil, func(ctx context.Context, pushReq *Request) error {
+				request, err := pushReq.WriteRequest()
+				require.NoError(t, err)
+				for _, ts := range request.Timeseries {
+					actualSeries = append(actualSeries, mimirpb.FromLabelAdaptersToLabelsWithCopy(ts.Labels))
+				}
+				pushReq.CleanUp()
+				return nil
+			})
+
+			// The request is decoded from scratch each time, so the promoted attributes don't leak across test cases.
+			req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Code)
+			assert.ElementsMatch(t, tc.expectedSeries, actualSeries)
+		})
+	}
+}
+
+func TestHandler_otlpCreatedTimestamp(t *testing.T) {
+	start := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	now := start.Add(time.Minute)
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+
+	counter := metrics.AppendEmpty()
+	counter.SetName("requests")
+	counter.SetEmptySum().SetIsMonotonic(true)
+	counter.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	datapoint := counter.Sum().DataPoints().AppendEmpty()
+	datapoint.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	datapoint.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	datapoint.SetDoubleValue(1)
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("temperature")
+	datapoint = gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	datapoint.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	datapoint.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	datapoint.SetDoubleValue(10)
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	histogram.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	histogramDatapoint := histogram.Histogram().DataPoints().AppendEmpty()
+	histogramDatapoint.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	histogramDatapoint.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	histogramDatapoint.SetCount(1)
+	histogramDatapoint.SetSum(0.5)
+	histogramDatapoint.ExplicitBounds().FromRaw([]float64{1})
+	histogramDatapoint.BucketCounts().FromRaw([]uint64{1, 0})
+
+	tests := map[string]struct {
+		enabled                   bool
+		expectedCreatedTimestamps map[string]int64
+	}{
+		"created timestamp zero ingestion disabled": {
+			enabled: false,
+			expectedCreatedTimestamps: map[string]int64{
+				`{__name__="requests"}`:                  0,
+				`{__name__="temperature"}`:               0,
+				`{__name__="latency_bucket", le="1"}`:    0,
+				`{__name__="latency_bucket", le="+Inf"}`: 0,
+				`{__name__="latency_count"}`:             0,
+				`{__name__="latency_sum"}`:               0,
+			},
+		},
+		"created timestamp zero ingestion enabled": {
+			enabled: true,
+			expectedCreatedTimestamps: map[string]int64{
+				`{__name__="requests"}`:                  start.UnixMilli(),
+				`{__name__="temperature"}`:               0,
+				`{__name__="latency_bucket", le="1"}`:    start.UnixMilli(),
+				`{__name__="latency_bucket", le="+Inf"}`: start.UnixMilli(),
+				`{__name__="latency_count"}`:             start.UnixMilli(),
+				`{__name__="latency_sum"}`:               start.UnixMilli(),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			limits := validation.MockOverrides(func(defaults *validation.Limits, _ map[string]*validation.Limits) {
+				defaults.OTelTargetInfoEnabled = false
+				defaults.CreatedTimestampZeroIngestionEnabled = tc.enabled
+			})
+
+			actualCreatedTimestamps := map[string]int64{}
+			handler := OTLPHandler(100000, nil, false, false, limits, nil, nil, func(ctx context.Context, pushReq *Request) error {
+				request, err := pushReq.WriteRequest()
+				require.NoError(t, err)
+				for _, ts := range request.Timeseries {
+					actualCreatedTimestamps[mimirpb.FromLabelAdaptersToLabels(ts.Labels).String()] = ts.CreatedTimestamp
+				}
+				pushReq.CleanUp()
+				return nil
+			})
+
+			req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, tc.expectedCreatedTimestamps, actualCreatedTimestamps)
+		})
+	}
+}
+
+func TestHandler_otlpExponentialHistogramOverMaxBuckets(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("latency")
+	metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	datapoint := metric.ExponentialHistogram().DataPoints().AppendEmpty()
+	datapoint.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	datapoint.SetScale(8)
+	datapoint.SetCount(40)
+	datapoint.SetSum(100)
+	datapoint.Positive().BucketCounts().FromRaw([]uint64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
+
+	tests := map[string]struct {
+		reduceOverMaxBuckets bool
+		expectedCode         int
+		expectedHistogram    *mimirpb.Histogram
+	}{
+		"resolution reduction enabled": {
+			reduceOverMaxBuckets: true,
+			expectedCode:         http.StatusOK,
+			expectedHistogram: &mimirpb.Histogram{
+				Schema:         6,
+				PositiveSpans:  []mimirpb.BucketSpan{{Offset: 1, Length: 10}},
+				PositiveDeltas: []int64{4, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			},
+		},
+		"resolution reduction disabled": {
+			reduceOverMaxBuckets: false,
+			expectedCode:         http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var limits validation.Limits
+			flagext.DefaultValues(&limits)
+			limits.MaxNativeHistogramBuckets = 10
+			limits.ReduceNativeHistogramOverMaxBuckets = tc.reduceOverMaxBuckets
+
+			ds, ingesters, _ := prepare(t, prepConfig{
+				numIngesters:      1,
+				happyIngesters:    1,
+				numDistributors:   1,
+				replicationFactor: 1,
+				limits:            &limits,
+			})
+
+			handler := OTLPHandler(100000, nil, false, false, ds[0].limits, nil, nil, ds[0].PushWithMiddlewares)
+			req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code, resp.Body.String())
+
+			series := ingesters[0].series()
+			if tc.expectedHistogram == nil {
+				require.Empty(t, series)
+				return
+			}
+
+			require.Len(t, series, 1)
+			for _, ts := range series {
+				require.Len(t, ts.Histograms, 1)
+				h := ts.Histograms[0]
+				assert.Equal(t, tc.expectedHistogram.Schema, h.Schema)
+				assert.Equal(t, tc.expectedHistogram.PositiveSpans, h.PositiveSpans)
+				assert.Equal(t, tc.expectedHistogram.PositiveDeltas, h.PositiveDeltas)
+			}
+		})
+	}
+}
+
 func TestHandler_otlpWriteRequestTooBigWithCompression(t *testing.T) {
 
 	// createOTLPRequest will create a request which is BIGGER with compression (37 vs 58 bytes).
@@ -414,7 +831,7 @@ func TestHandler_otlpWriteRequestTooBigWithCompression(t *testing.T) {
 
 	resp := httptest.NewRecorder()
 
-	handler := OTLPHandler(140, nil, false, true, nil, nil, readBodyPushFunc(t))
+	handler := OTLPHandler(140, nil, false, true, validation.MockDefaultOverrides(), nil, nil, readBodyPushFunc(t))
 	handler.ServeHTTP(resp, req)
 	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
 	body, err := io.ReadAll(resp.Body)
@@ -426,16 +843,231 @@ func TestHandler_mimirWriteRequest(t *testing.T) {
 	req := createRequest(t, createMimirWriteRequestProtobuf(t, false))
 	resp := httptest.NewRecorder()
 	sourceIPs, _ := middleware.NewSourceIPs("SomeField", "(.*)")
-	handler := Handler(100000, sourceIPs, false, nil, verifyWritePushFunc(t, mimirpb.RULE))
+	handler := Handler(100000, sourceIPs, false, nil, nil, verifyWritePushFunc(t, mimirpb.RULE))
 	handler.ServeHTTP(resp, req)
 	assert.Equal(t, 200, resp.Code)
 }
 
+func TestHandler_remoteWriteV2(t *testing.T) {
+	const ct = 1000
+
+	input := &writev2pb.Request{
+		Symbols: []string{"", "__name__", "foo_total", "job", "test", "trace_id", "1234", "Help text.", "seconds"},
+		Timeseries: []writev2pb.TimeSeries{
+			{
+				LabelsRefs:       []uint32{1, 2, 3, 4},
+				Samples:          []mimirpb.Sample{{TimestampMs: 2000, Value: 1}, {TimestampMs: 3000, Value: 2}},
+				Exemplars:        []writev2pb.Exemplar{{LabelsRefs: []uint32{5, 6}, Value: 2, Timestamp: 3000}},
+				Metadata:         writev2pb.Metadata{Type: writev2pb.METRIC_TYPE_COUNTER, HelpRef: 7, UnitRef: 8},
+				CreatedTimestamp: ct,
+			},
+		},
+	}
+
+	req := createRemoteWriteV2Request(t, input)
+	resp := httptest.NewRecorder()
+	handler := Handler(100000, nil, false, nil, nil, func(ctx context.Context, pushReq *Request) error {
+		defer pushReq.CleanUp()
+
+		request, err := pushReq.WriteRequest()
+		require.NoError(t, err)
+		require.Len(t, request.Timeseries, 1)
+
+		ts := request.Timeseries[0]
+		assert.Equal(t, []mimirpb.LabelAdapter{{Name: "__name__", Value: "foo_total"}, {Name: "job", Value: "test"}}, ts.Labels)
+		assert.Equal(t, []mimirpb.Sample{{TimestampMs: 2000, Value: 1}, {TimestampMs: 3000, Value: 2}}, ts.Samples)
+		assert.Equal(t, int64(ct), ts.CreatedTimestamp)
+		assert.Equal(t, []mimirpb.Exemplar{{Labels: []mimirpb.LabelAdapter{{Name: "trace_id", Value: "1234"}}, Value: 2, TimestampMs: 3000}}, ts.Exemplars)
+		assert.Equal(t, []*mimirpb.MetricMetadata{{Type: mimirpb.COUNTER, MetricFamilyName: "foo_total", Help: "Help text.", Unit: "seconds"}}, request.Metadata)
+
+		recordRemoteWriteV2Written(ctx, remoteWriteV2WrittenStats(ctx, request))
+		return nil
+	})
+	handler.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get(writtenSamplesHeader))
+	assert.Equal(t, "0", resp.Header().Get(writtenHistogramsHeader))
+	assert.Equal(t, "1", resp.Header().Get(writtenExemplarsHeader))
+}
+
+func TestHandler_remoteWriteV2Errors(t *testing.T) {
+	tests := map[string]struct {
+		input              *writev2pb.Request
+		contentType        string
+		pushErr            error
+		expectedStatusCode int
+	}{
+		"unsupported proto message": {
+			input:              &writev2pb.Request{},
+			contentType:        "application/x-protobuf;proto=io.prometheus.write.v3.Request",
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+		},
+		"symbol reference out of range": {
+			input: &writev2pb.Request{
+				Symbols:    []string{"", "__name__", "foo"},
+				Timeseries: []writev2pb.TimeSeries{{LabelsRefs: []uint32{1, 3}, Samples: []mimirpb.Sample{{TimestampMs: 1, Value: 1}}}},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"odd number of label references": {
+			input: &writev2pb.Request{
+				Symbols:    []string{"", "__name__", "foo"},
+				Timeseries: []writev2pb.TimeSeries{{LabelsRefs: []uint32{1, 2, 1}, Samples: []mimirpb.Sample{{TimestampMs: 1, Value: 1}}}},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"push error": {
+			input: &writev2pb.Request{
+				Symbols:    []string{"", "__name__", "foo"},
+				Timeseries: []writev2pb.TimeSeries{{LabelsRefs: []uint32{1, 2}, Samples: []mimirpb.Sample{{TimestampMs: 1, Value: 1}}}},
+			},
+			pushErr:            newIngestionRateLimitedError(10, 10),
+			expectedStatusCode: http.StatusTooManyRequests,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := createRemoteWriteV2Request(t, tc.input)
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			resp := httptest.NewRecorder()
+			handler := Handler(100000, nil, false, nil, nil, func(ctx context.Context, pushReq *Request) error {
+				defer pushReq.CleanUp()
+				if _, err := pushReq.WriteRequest(); err != nil {
+					return err
+				}
+				return tc.pushErr
+			})
+			handler.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			if tc.expectedStatusCode != http.StatusUnsupportedMediaType {
+				assert.Equal(t, "0", resp.Header().Get(writtenSamplesHeader))
+			}
+		})
+	}
+}
+
+func TestHandler_remoteWriteV2PartiallyWritten(t *testing.T) {
+	input := &writev2pb.Request{
+		Symbols: []string{"", "__name__", "foo", "bar"},
+		Timeseries: []writev2pb.TimeSeries{
+			{LabelsRefs: []uint32{1, 2}, Samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 1}, {TimestampMs: 2000, Value: 2}}},
+			{LabelsRefs: []uint32{1, 3}, Samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 1}}},
+		},
+	}
+
+	req := createRemoteWriteV2Request(t, input)
+	resp := httptest.NewRecorder()
+	handler := Handler(100000, nil, false, nil, nil, func(ctx context.Context, pushReq *Request) error {
+		defer pushReq.CleanUp()
+
+		request, err := pushReq.WriteRequest()
+		require.NoError(t, err)
+
+		// Discard the last series, like the distributor does with invalid series.
+		request.Timeseries = request.Timeseries[:1]
+		recordRemoteWriteV2Written(ctx, remoteWriteV2WrittenStats(ctx, request))
+		return newValidationError(errors.New("invalid series"))
+	})
+	handler.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get(writtenSamplesHeader))
+	assert.Equal(t, "0", resp.Header().Get(writtenHistogramsHeader))
+	assert.Equal(t, "0", resp.Header().Get(writtenExemplarsHeader))
+}
+
+func TestRemoteWriteV2ToWriteRequest_Metadata(t *testing.T) {
+	series := func(nameRef uint32, t writev2pb.Metadata_MetricType) writev2pb.TimeSeries {
+		return writev2pb.TimeSeries{
+			LabelsRefs: []uint32{1, nameRef},
+			Samples:    []mimirpb.Sample{{TimestampMs: 1000, Value: 1}},
+			Metadata:   writev2pb.Metadata{Type: t, HelpRef: 9},
+		}
+	}
+
+	var req mimirpb.PreallocWriteRequest
+	err := remoteWriteV2ToWriteRequest(&writev2pb.Request{
+		Symbols: []string{"", "__name__", "foo_bucket", "foo_sum", "foo_count", "bar_sum", "bar_count", "baz_total", "baz_count", "Help text."},
+		Timeseries: []writev2pb.TimeSeries{
+			series(2, writev2pb.METRIC_TYPE_HISTOGRAM),
+			series(3, writev2pb.METRIC_TYPE_HISTOGRAM),
+			series(4, writev2pb.METRIC_TYPE_HISTOGRAM),
+			series(5, writev2pb.METRIC_TYPE_SUMMARY),
+			series(6, writev2pb.METRIC_TYPE_SUMMARY),
+			series(7, writev2pb.METRIC_TYPE_COUNTER),
+			series(8, writev2pb.METRIC_TYPE_COUNTER),
+		},
+	}, &req)
+	require.NoError(t, err)
+
+	// The metadata is de-duplicated by metric family, only the suffixes of classic histograms and summaries are trimmed.
+	assert.Equal(t, []*mimirpb.MetricMetadata{
+		{Type: mimirpb.HISTOGRAM, MetricFamilyName: "foo", Help: "Help text."},
+		{Type: mimirpb.SUMMARY, MetricFamilyName: "bar", Help: "Help text."},
+		{Type: mimirpb.COUNTER, MetricFamilyName: "baz_total", Help: "Help text."},
+		{Type: mimirpb.COUNTER, MetricFamilyName: "baz_count", Help: "Help text."},
+	}, req.Metadata)
+}
+
+func TestRemoteWriteV2ToWriteRequest_CreatedTimestamp(t *testing.T) {
+	promHistogram := remote.HistogramToHistogramProto(2000, test.GenerateTestHistogram(1))
+	h := promToMimirHistogram(&promHistogram)
+
+	tests := map[string]struct {
+		series                   writev2pb.TimeSeries
+		expectedCreatedTimestamp int64
+	}{
+		"counter": {
+			series:                   writev2pb.TimeSeries{LabelsRefs: []uint32{1, 2}, Samples: []mimirpb.Sample{{TimestampMs: 2000, Value: 5}}, CreatedTimestamp: 1000},
+			expectedCreatedTimestamp: 1000,
+		},
+		"no created timestamp": {
+			series:                   writev2pb.TimeSeries{LabelsRefs: []uint32{1, 2}, Samples: []mimirpb.Sample{{TimestampMs: 2000, Value: 5}}},
+			expectedCreatedTimestamp: 0,
+		},
+		"gauge": {
+			series: writev2pb.TimeSeries{
+				LabelsRefs:       []uint32{1, 2},
+				Samples:          []mimirpb.Sample{{TimestampMs: 2000, Value: 5}},
+				Metadata:         writev2pb.Metadata{Type: writev2pb.METRIC_TYPE_GAUGE},
+				CreatedTimestamp: 1000,
+			},
+			expectedCreatedTimestamp: 0,
+		},
+		"histogram": {
+			series:                   writev2pb.TimeSeries{LabelsRefs: []uint32{1, 2}, Histograms: []mimirpb.Histogram{h}, CreatedTimestamp: 1000},
+			expectedCreatedTimestamp: 1000,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var req mimirpb.PreallocWriteRequest
+			err := remoteWriteV2ToWriteRequest(&writev2pb.Request{
+				Symbols:    []string{"", "__name__", "foo"},
+				Timeseries: []writev2pb.TimeSeries{tc.series},
+			}, &req)
+			require.NoError(t, err)
+			require.Len(t, req.Timeseries, 1)
+
+			// The samples are not changed, the zero sample is injected by ingesters.
+			assert.Equal(t, tc.series.Samples, req.Timeseries[0].Samples)
+			assert.Equal(t, tc.series.Histograms, req.Timeseries[0].Histograms)
+			assert.Equal(t, tc.expectedCreatedTimestamp, req.Timeseries[0].CreatedTimestamp)
+		})
+	}
+}
+
 func TestHandler_contextCanceledRequest(t *testing.T) {
 	req := createRequest(t, createMimirWriteRequestProtobuf(t, false))
 	resp := httptest.NewRecorder()
 	sourceIPs, _ := middleware.NewSourceIPs("SomeField", "(.*)")
-	handler := Handler(100000, sourceIPs, false, nil, func(_ context.Context, req *Request) error {
+	handler := Handler(100000, sourceIPs, false, nil, nil, func(_ context.Context, req *Request) error {
 		defer req.CleanUp()
 		return fmt.Errorf("the request failed: %w", context.Canceled)
 	})
@@ -544,7 +1176,7 @@ func TestHandler_EnsureSkipLabelNameValidationBehaviour(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			resp := httptest.NewRecorder()
-			handler := Handler(100000, nil, tc.allowSkipLabelNameValidation, nil, tc.verifyReqHandler)
+			handler := Handler(100000, nil, tc.allowSkipLabelNameValidation, nil, nil, tc.verifyReqHandler)
 			if !tc.includeAllowSkiplabelNameValidationHeader {
 				tc.req.Header.Set(SkipLabelNameValidationHeader, "true")
 			}
@@ -588,6 +1220,41 @@ func createRequest(t testing.TB, protobuf []byte) *http.Request {
 	return req
 }
 
+func compressBody(t testing.TB, encoding string, data []byte) []byte {
+	t.Helper()
+
+	switch encoding {
+	case "", "snappy":
+		return snappy.Encode(nil, data)
+	case "gzip":
+		var b bytes.Buffer
+		gz := gzip.NewWriter(&b)
+		_, err := gz.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		return b.Bytes()
+	case "zstd":
+		enc, err := zstd.NewWriter(nil)
+		require.NoError(t, err)
+		defer enc.Close()
+		return enc.EncodeAll(data, nil)
+	}
+
+	require.FailNow(t, "unsupported encoding", encoding)
+	return nil
+}
+
+func createRemoteWriteV2Request(t testing.TB, input *writev2pb.Request) *http.Request {
+	t.Helper()
+	protobuf, err := input.Marshal()
+	require.NoError(t, err)
+
+	req := createRequest(t, protobuf)
+	req.Header.Set("Content-Type", "application/x-protobuf;proto=io.prometheus.write.v2.Request")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "2.0.0")
+	return req
+}
+
 func createOTLPRequest(t testing.TB, metricRequest pmetricotlp.ExportRequest, compress bool) *http.Request {
 	t.Helper()
 
@@ -701,7 +1368,7 @@ func BenchmarkPushHandler(b *testing.B) {
 		pushReq.CleanUp()
 		return nil
 	}
-	handler := Handler(100000, nil, false, nil, pushFunc)
+	handler := Handler(100000, nil, false, nil, nil, pushFunc)
 	b.ResetTimer()
 	for iter := 0; iter < b.N; iter++ {
 		req.Body = bufCloser{Buffer: buf} // reset Body so it can be read each time round the loop
@@ -33,6 +33,11 @@ var (
 	// readNoExtend is a ring.Operation that only selects instances marked as ring.ACTIVE.
 	// This should mirror the operation used when choosing ingesters to write series to (ring.WriteNoExtend).
 	readNoExtend = ring.NewOp([]ring.InstanceState{ring.ACTIVE}, nil)
+
+	// readNoExtendWithLeaving is a ring.Operation that selects instances marked as ring.ACTIVE or ring.LEAVING.
+	// It's used to query the read-only ingesters, which are LEAVING: the LEAVING instances which aren't
+	// read-only are then excluded from the replication set.
+	readNoExtendWithLeaving = ring.NewOp([]ring.InstanceState{ring.ACTIVE, ring.LEAVING}, nil)
 )
 
 func (d *Distributor) QueryExemplars(ctx context.Context, from, to model.Time, matchers ...[]*labels.Matcher) (*ingester_client.ExemplarQueryResponse, error) {
@@ -110,11 +115,79 @@ func (d *Distributor) GetIngesters(ctx context.Context) (ring.ReplicationSet, er
 	shardSize := d.limits.IngestionTenantShardSize(userID)
 	lookbackPeriod := d.cfg.ShuffleShardingLookbackPeriod
 
+	var replicationSet ring.ReplicationSet
 	if shardSize > 0 && lookbackPeriod > 0 {
-		return d.ingestersRing.ShuffleShardWithLookback(userID, shardSize, lookbackPeriod, time.Now()).GetReplicationSetForOperation(readNoExtend)
+		replicationSet, err = d.ingestersRing.ShuffleShardWithLookback(userID, shardSize, lookbackPeriod, time.Now()).GetReplicationSetForOperation(d.readOperation())
+	} else {
+		replicationSet, err = d.ingestersRing.GetReplicationSetForOperation(d.readOperation())
+	}
+	if err != nil {
+		return ring.ReplicationSet{}, err
+	}
+
+	return d.excludeNotQueryable(replicationSet)
+}
+
+// readOperation returns the ring.Operation selecting the ingesters to query. The LEAVING ingesters are
+// selected only if the read-only ingesters are known, so that the read-only ones can be queried.
+func (d *Distributor) readOperation() ring.Operation {
+	if d.cfg.ReadOnlyIngesters == nil {
+		return readNoExtend
+	}
+	return readNoExtendWithLeaving
+}
+
+// isQueryable returns whether the ingester selected by readOperation is queried: the LEAVING ingesters are
+// only queried if they're read-only, because the other ones are shutting down.
+func (d *Distributor) isQueryable(instance ring.InstanceDesc) bool {
+	if instance.State != ring.LEAVING {
+		return true
+	}
+	_, readOnly := d.cfg.ReadOnlyIngesters.IsReadOnly(instance.Id)
+	return readOnly
+}
+
+// excludeNotQueryable removes the ingesters which aren't queried from the replication set. They're handled
+// like the unhealthy instances excluded by the ring: with zone-awareness, the whole zone of an excluded
+// ingester is excluded, otherwise the number of tolerated errors is reduced.
+func (d *Distributor) excludeNotQueryable(replicationSet ring.ReplicationSet) (ring.ReplicationSet, error) {
+	excluded := 0
+	excludedZones := map[string]struct{}{}
+	for _, instance := range replicationSet.Instances {
+		if !d.isQueryable(instance) {
+			excluded++
+			excludedZones[instance.Zone] = struct{}{}
+		}
+	}
+	if excluded == 0 {
+		return replicationSet, nil
+	}
+
+	instances := make([]ring.InstanceDesc, 0, len(replicationSet.Instances)-excluded)
+	if replicationSet.MaxUnavailableZones > 0 {
+		for _, instance := range replicationSet.Instances {
+			if _, ok := excludedZones[instance.Zone]; !ok {
+				instances = append(instances, instance)
+			}
+		}
+		replicationSet.MaxUnavailableZones -= len(excludedZones)
+		if replicationSet.MaxUnavailableZones < 0 {
+			return ring.ReplicationSet{}, ring.ErrTooManyUnhealthyInstances
+		}
+	} else {
+		for _, instance := range replicationSet.Instances {
+			if d.isQueryable(instance) {
+				instances = append(instances, instance)
+			}
+		}
+		replicationSet.MaxErrors -= excluded
+		if replicationSet.MaxErrors < 0 {
+			return ring.ReplicationSet{}, ring.ErrTooManyUnhealthyInstances
+		}
 	}
 
-	return d.ingestersRing.GetReplicationSetForOperation(readNoExtend)
+	replicationSet.Instances = instances
+	return replicationSet, nil
 }
 
 // mergeExemplarSets merges and dedupes two sets of already sorted exemplar pairs.
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/ring"
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
@@ -238,6 +239,112 @@ func TestDistributor_QueryStream_ShouldReturnErrorIfMaxChunkBytesPerQueryLimitIs
 	assert.ErrorContains(t, err, fmt.Sprintf(limiter.MaxChunkBytesHitMsgFormat, maxBytesLimit))
 }
 
+func TestDistributor_GetIngesters_ShouldOnlyQueryLeavingIngestersInReadOnlyMode(t *testing.T) {
+	since := time.Now()
+	instance := func(id, zone string, state ring.InstanceState) ring.InstanceDesc {
+		return ring.InstanceDesc{Id: id, Addr: id, Zone: zone, State: state}
+	}
+
+	tests := map[string]struct {
+		readOnlyIngesters ReadOnlyIngesters
+		replicationSet    ring.ReplicationSet
+		expectedInstances []string
+		expectedMaxErrors int
+		expectedMaxZones  int
+		expectedErr       error
+	}{
+		"should keep all ingesters if none is LEAVING": {
+			readOnlyIngesters: readOnlyInstances{},
+			replicationSet: ring.ReplicationSet{
+				Instances: []ring.InstanceDesc{instance("ingester-1", "", ring.ACTIVE), instance("ingester-2", "", ring.ACTIVE), instance("ingester-3", "", ring.ACTIVE)},
+				MaxErrors: 1,
+			},
+			expectedInstances: []string{"ingester-1", "ingester-2", "ingester-3"},
+			expectedMaxErrors: 1,
+		},
+		"should keep the LEAVING ingester in read-only mode": {
+			readOnlyIngesters: readOnlyInstances{"ingester-2": since},
+			replicationSet: ring.ReplicationSet{
+				Instances: []ring.InstanceDesc{instance("ingester-1", "", ring.ACTIVE), instance("ingester-2", "", ring.LEAVING), instance("ingester-3", "", ring.ACTIVE)},
+				MaxErrors: 1,
+			},
+			expectedInstances: []string{"ingester-1", "ingester-2", "ingester-3"},
+			expectedMaxErrors: 1,
+		},
+		"should exclude the LEAVING ingester not in read-only mode": {
+			readOnlyIngesters: readOnlyInstances{},
+			replicationSet: ring.ReplicationSet{
+				Instances: []ring.InstanceDesc{instance("ingester-1", "", ring.ACTIVE), instance("ingester-2", "", ring.LEAVING), instance("ingester-3", "", ring.ACTIVE)},
+				MaxErrors: 1,
+			},
+			expectedInstances: []string{"ingester-1", "ingester-3"},
+			expectedMaxErrors: 0,
+		},
+		"should fail if too many LEAVING ingesters aren't in read-only mode": {
+			readOnlyIngesters: readOnlyInstances{},
+			replicationSet: ring.ReplicationSet{
+				Instances: []ring.InstanceDesc{instance("ingester-1", "", ring.LEAVING), instance("ingester-2", "", ring.LEAVING), instance("ingester-3", "", ring.ACTIVE)},
+				MaxErrors: 1,
+			},
+			expectedErr: ring.ErrTooManyUnhealthyInstances,
+		},
+		"should exclude the zone of the LEAVING ingester not in read-only mode with zone-awareness": {
+			readOnlyIngesters: readOnlyInstances{"ingester-zone-b-1": since},
+			replicationSet: ring.ReplicationSet{
+				Instances: []ring.InstanceDesc{
+					instance("ingester-zone-a-1", "zone-a", ring.ACTIVE), instance("ingester-zone-a-2", "zone-a", ring.LEAVING),
+					instance("ingester-zone-b-1", "zone-b", ring.LEAVING), instance("ingester-zone-b-2", "zone-b", ring.ACTIVE),
+					instance("ingester-zone-c-1", "zone-c", ring.ACTIVE), instance("ingester-zone-c-2", "zone-c", ring.ACTIVE),
+				},
+				MaxUnavailableZones: 1,
+			},
+			expectedInstances: []string{"ingester-zone-b-1", "ingester-zone-b-2", "ingester-zone-c-1", "ingester-zone-c-2"},
+			expectedMaxZones:  0,
+		},
+		"should fail if LEAVING ingesters not in read-only mode are in too many zones": {
+			readOnlyIngesters: readOnlyInstances{},
+			replicationSet: ring.ReplicationSet{
+				Instances: []ring.InstanceDesc{
+					instance("ingester-zone-a-1", "zone-a", ring.LEAVING),
+					instance("ingester-zone-b-1", "zone-b", ring.LEAVING),
+					instance("ingester-zone-c-1", "zone-c", ring.ACTIVE),
+				},
+				MaxUnavailableZones: 1,
+			},
+			expectedErr: ring.ErrTooManyUnhealthyInstances,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			d := &Distributor{cfg: Config{ReadOnlyIngesters: testData.readOnlyIngesters}}
+			assert.Equal(t, readNoExtendWithLeaving, d.readOperation())
+
+			actual, err := d.excludeNotQueryable(testData.replicationSet)
+			if testData.expectedErr != nil {
+				require.ErrorIs(t, err, testData.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			actualInstances := make([]string, 0, len(actual.Instances))
+			for _, instance := range actual.Instances {
+				actualInstances = append(actualInstances, instance.Id)
+			}
+			assert.Equal(t, testData.expectedInstances, actualInstances)
+			assert.Equal(t, testData.expectedMaxErrors, actual.MaxErrors)
+			assert.Equal(t, testData.expectedMaxZones, actual.MaxUnavailableZones)
+		})
+	}
+}
+
+type readOnlyInstances map[string]time.Time
+
+func (r readOnlyInstances) IsReadOnly(instanceID string) (time.Time, bool) {
+	since, ok := r[instanceID]
+	return since, ok
+}
+
 func TestMergeSamplesIntoFirstDuplicates(t *testing.T) {
 	a := []mimirpb.Sample{
 		{Value: 1.084537996, TimestampMs: 1583946732744},
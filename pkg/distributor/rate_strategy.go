@@ -94,6 +94,28 @@ func (s *ingestionRateStrategy) Burst(tenantID string) int {
 	return s.limits.IngestionBurstSize(tenantID)
 }
 
+type haSampleLevelDedupRateStrategy struct {
+	limits *validation.Overrides
+}
+
+func newHASampleLevelDedupRateStrategy(limits *validation.Overrides) limiter.RateLimiterStrategy {
+	return &haSampleLevelDedupRateStrategy{
+		limits: limits,
+	}
+}
+
+func (s *haSampleLevelDedupRateStrategy) Limit(tenantID string) float64 {
+	if lm := s.limits.HASampleLevelDedupMaxSamplesPerSecond(tenantID); lm > 0 {
+		return lm
+	}
+	return float64(rate.Inf)
+}
+
+func (s *haSampleLevelDedupRateStrategy) Burst(tenantID string) int {
+	// The burst allows to forward a push request which contains up to 10 seconds of samples.
+	return int(10 * s.limits.HASampleLevelDedupMaxSamplesPerSecond(tenantID))
+}
+
 type infiniteStrategy struct{}
 
 func newInfiniteRateStrategy() limiter.RateLimiterStrategy {
@@ -0,0 +1,288 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"gopkg.in/yaml.v3"
+
+	ingester_client "github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+// dropLabelsRuleName is the name of the relabel rule removing the labels configured in drop_labels.
+// The rules configured in metric_relabel_configs are named after their index.
+const dropLabelsRuleName = "drop_labels"
+
+func relabelRuleName(idx int) string {
+	return strconv.Itoa(idx)
+}
+
+// relabelTimeseries applies the metric_relabel_configs and drop_labels rules to the series labels, and
+// removes labels with an empty value. onRule is called with the name of each rule which changed or dropped
+// the series. Returns false if the series has been dropped.
+func relabelTimeseries(ts *mimirpb.PreallocTimeseries, lb *labels.Builder, userID string, mrc []*relabel.Config, dropLabels []string, onRule func(rule string, dropped bool)) bool {
+	if len(mrc) > 0 {
+		mimirpb.FromLabelAdaptersToBuilder(ts.Labels, lb)
+		lb.Set(metaLabelTenantID, userID)
+
+		// Rules are applied one by one, in order to track which ones changed the series.
+		for idx, cfg := range mrc {
+			before := relabelSnapshotOf(lb, cfg)
+			if !relabel.ProcessBuilder(lb, cfg) {
+				onRule(relabelRuleName(idx), true)
+				return false
+			}
+			if relabelSnapshotOf(lb, cfg) != before {
+				onRule(relabelRuleName(idx), false)
+			}
+		}
+
+		lb.Del(metaLabelTenantID)
+		ts.SetLabels(mimirpb.FromBuilderToLabelAdapters(lb, ts.Labels))
+	}
+
+	if len(dropLabels) > 0 {
+		numLabels := len(ts.Labels)
+		for _, labelName := range dropLabels {
+			ts.RemoveLabel(labelName)
+		}
+		if len(ts.Labels) != numLabels {
+			onRule(dropLabelsRuleName, len(ts.Labels) == 0)
+		}
+	}
+
+	// Prometheus strips empty values before storing; drop them now, before sharding to ingesters.
+	ts.RemoveEmptyLabelValues()
+
+	return len(ts.Labels) > 0
+}
+
+// relabelSnapshot is the part of the series labels a relabel rule can change. It's used to detect whether
+// a rule changed the series without materialising the labels, because the rules are applied on the write path.
+type relabelSnapshot struct {
+	// value of the target label, for the rules changing a single label known in advance.
+	value string
+
+	// Order-independent fingerprint of all the labels, for the other rules which can change the labels.
+	fingerprint uint64
+	numLabels   int
+}
+
+func relabelSnapshotOf(lb *labels.Builder, cfg *relabel.Config) relabelSnapshot {
+	switch cfg.Action {
+	case relabel.Keep, relabel.Drop, relabel.KeepEqual, relabel.DropEqual:
+		// These rules can only drop the series.
+		return relabelSnapshot{}
+	case relabel.HashMod, relabel.Lowercase, relabel.Uppercase:
+		return relabelSnapshot{value: lb.Get(cfg.TargetLabel)}
+	case relabel.Replace:
+		// The target label of the replace rules can be a template referencing the regex capture groups.
+		if !strings.Contains(cfg.TargetLabel, "$") {
+			return relabelSnapshot{value: lb.Get(cfg.TargetLabel)}
+		}
+	}
+
+	var snapshot relabelSnapshot
+	lb.Range(func(l labels.Label) {
+		h := ingester_client.HashAdd32a(ingester_client.HashNew32a(), l.Name)
+		h = ingester_client.HashAddByte32a(h, model.SeparatorByte)
+		h = ingester_client.HashAdd32a(h, l.Value)
+		snapshot.fingerprint += uint64(h)
+		snapshot.numLabels++
+	})
+	return snapshot
+}
+
+type relabelDryRunResponse struct {
+	Series []relabelDryRunSeries `json:"series"`
+	Rules  []relabelDryRunRule   `json:"rules"`
+}
+
+type relabelDryRunSeries struct {
+	Before  labels.Labels `json:"before"`
+	After   labels.Labels `json:"after"`
+	Dropped bool          `json:"dropped"`
+	// Rules which changed or dropped the series.
+	Rules []string `json:"rules"`
+}
+
+type relabelDryRunRule struct {
+	Rule          string `json:"rule"`
+	Action        string `json:"action"`
+	SeriesChanged int    `json:"series_changed"`
+	SeriesDropped int    `json:"series_dropped"`
+}
+
+// RelabelDryRunHandler applies the tenant's relabel rules to the series of the remote-write request received
+// in the body, and returns the series labels before and after relabeling. The series are not ingested.
+// The tenant's metric_relabel_configs and drop_labels can be overridden via the request parameters, in order
+// to preview the effect of a change before rolling it out.
+func (d *Distributor) RelabelDryRunHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	mrc := d.limits.MetricRelabelConfigs(userID)
+	dropLabels := d.limits.DropLabels(userID)
+
+	query := r.URL.Query()
+	if query.Has("metric_relabel_configs") {
+		mrc, err = parseRelabelConfigs(query.Get("metric_relabel_configs"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if query.Has("drop_labels") {
+		dropLabels = query["drop_labels"]
+	}
+
+	var timeseries []mimirpb.PreallocTimeseries
+	if isTrafficCaptureContentType(r.Header.Get("Content-Type")) {
+		timeseries, err = parseTrafficCapture(w, r, userID, d.cfg.MaxRecvMsgSize)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.As(err, new(*http.MaxBytesError)) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+	} else {
+		compression, err := remoteWriteCompression(r.Header.Get("Content-Encoding"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+			return
+		}
+
+		var req mimirpb.PreallocWriteRequest
+		if _, err := util.ParseProtoReader(r.Context(), r.Body, int(r.ContentLength), d.cfg.MaxRecvMsgSize, nil, &req, compression); err != nil {
+			err = maxWriteMessageSizeErr(err, r, d.cfg.MaxRecvMsgSize)
+			status := http.StatusBadRequest
+			if errors.As(err, new(distributorMaxWriteMessageSizeErr)) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		defer mimirpb.ReuseSlice(req.Timeseries)
+		timeseries = req.Timeseries
+	}
+
+	resp := relabelDryRunResponse{
+		Series: make([]relabelDryRunSeries, 0, len(timeseries)),
+		Rules:  make([]relabelDryRunRule, 0, len(mrc)+1),
+	}
+	rules := map[string]*relabelDryRunRule{}
+	for idx, cfg := range mrc {
+		resp.Rules = append(resp.Rules, relabelDryRunRule{Rule: relabelRuleName(idx), Action: string(cfg.Action)})
+	}
+	if len(dropLabels) > 0 {
+		resp.Rules = append(resp.Rules, relabelDryRunRule{Rule: dropLabelsRuleName, Action: string(relabel.LabelDrop)})
+	}
+	for idx := range resp.Rules {
+		rules[resp.Rules[idx].Rule] = &resp.Rules[idx]
+	}
+
+	lb := labels.NewBuilder(labels.EmptyLabels())
+	for idx := range timeseries {
+		ts := &timeseries[idx]
+		series := relabelDryRunSeries{
+			Before: mimirpb.FromLabelAdaptersToLabelsWithCopy(ts.Labels),
+			After:  labels.EmptyLabels(),
+			Rules:  []string{},
+		}
+
+		kept := relabelTimeseries(ts, lb, userID, mrc, dropLabels, func(rule string, dropped bool) {
+			series.Rules = append(series.Rules, rule)
+			if dropped {
+				rules[rule].SeriesDropped++
+			} else {
+				rules[rule].SeriesChanged++
+			}
+		})
+		if kept {
+			series.After = mimirpb.FromLabelAdaptersToLabelsWithCopy(ts.Labels)
+		} else {
+			series.Dropped = true
+		}
+
+		resp.Series = append(resp.Series, series)
+	}
+
+	util.WriteJSONResponse(w, resp)
+}
+
+// isTrafficCaptureContentType returns whether the content type is the one of the traffic captured by the trafficdump tool.
+func isTrafficCaptureContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/x-ndjson" || mediaType == "application/json"
+}
+
+// capturedRequest is the subset of a request captured by the trafficdump tool used by the relabeling dry-run.
+type capturedRequest struct {
+	Request *struct {
+		Tenant string `json:"tenant"`
+		Push   *struct {
+			Timeseries []struct {
+				Metric map[string]string `json:"metric"`
+			} `json:"timeseries"`
+		} `json:"push"`
+	} `json:"request"`
+}
+
+// parseTrafficCapture returns the series of the push requests captured by the trafficdump tool, one JSON object
+// per line. The requests captured for other tenants, and the ones which aren't push requests, are skipped.
+func parseTrafficCapture(w http.ResponseWriter, r *http.Request, userID string, maxSize int) ([]mimirpb.PreallocTimeseries, error) {
+	var timeseries []mimirpb.PreallocTimeseries
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, int64(maxSize)))
+	for {
+		var captured capturedRequest
+		if err := dec.Decode(&captured); err != nil {
+			if errors.Is(err, io.EOF) {
+				return timeseries, nil
+			}
+			return nil, errors.Wrap(err, "invalid traffic capture")
+		}
+
+		req := captured.Request
+		if req == nil || req.Push == nil || (req.Tenant != "" && req.Tenant != userID) {
+			continue
+		}
+		for _, ts := range req.Push.Timeseries {
+			timeseries = append(timeseries, mimirpb.PreallocTimeseries{TimeSeries: &mimirpb.TimeSeries{
+				Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromMap(ts.Metric)),
+			}})
+		}
+	}
+}
+
+// parseRelabelConfigs parses a YAML list of relabel configs. The configs are validated while being unmarshalled.
+func parseRelabelConfigs(cfg string) ([]*relabel.Config, error) {
+	var mrc []*relabel.Config
+	if err := yaml.Unmarshal([]byte(cfg), &mrc); err != nil {
+		return nil, errors.Wrap(err, "invalid metric_relabel_configs")
+	}
+	for _, c := range mrc {
+		if c == nil {
+			return nil, errors.New("invalid metric_relabel_configs: empty relabel config")
+		}
+	}
+	return mrc, nil
+}
@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestRelabelMiddleware_ShouldTrackSeriesChangedAndDroppedPerRule(t *testing.T) {
+	var limits validation.Limits
+	flagext.DefaultValues(&limits)
+	limits.MetricRelabelConfigs = []*relabel.Config{
+		{
+			SourceLabels: []model.LabelName{"label1"},
+			Action:       relabel.Drop,
+			Regex:        relabel.MustNewRegexp("drop"),
+		},
+		{
+			SourceLabels: []model.LabelName{"label1"},
+			Action:       relabel.Replace,
+			Regex:        relabel.MustNewRegexp("(rename)"),
+			TargetLabel:  "target",
+			Replacement:  "$1",
+		},
+	}
+	limits.DropLabels = []string{"label2"}
+
+	ds, _, regs := prepare(t, prepConfig{
+		numDistributors: 1,
+		limits:          &limits,
+	})
+
+	next := func(_ context.Context, pushReq *Request) error {
+		pushReq.CleanUp()
+		return nil
+	}
+
+	req := makeWriteRequestForGenerators(3, labelSetGenForStringPairs(t, "__name__", "metric", "label1", "keep_%d"), nil, nil)
+	req.Timeseries = append(req.Timeseries,
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "metric"}, {Name: "label1", Value: "drop"}}, 1, 1),
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "metric"}, {Name: "label1", Value: "rename"}}, 1, 1),
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "metric"}, {Name: "label1", Value: "rename"}, {Name: "label2", Value: "value"}}, 1, 1),
+	)
+
+	require.NoError(t, ds[0].prePushRelabelMiddleware(next)(user.InjectOrgID(context.Background(), "user"), NewParsedRequest(req)))
+
+	assert.NoError(t, testutil.GatherAndCompare(regs[0], strings.NewReader(`
+		# HELP cortex_distributor_relabel_series_changed_total The total number of series whose labels have been changed by a per-tenant relabel rule.
+		# TYPE cortex_distributor_relabel_series_changed_total counter
+		cortex_distributor_relabel_series_changed_total{rule="1",user="user"} 2
+		cortex_distributor_relabel_series_changed_total{rule="drop_labels",user="user"} 1
+
+		# HELP cortex_distributor_relabel_series_dropped_total The total number of series dropped by a per-tenant relabel rule.
+		# TYPE cortex_distributor_relabel_series_dropped_total counter
+		cortex_distributor_relabel_series_dropped_total{rule="0",user="user"} 1
+	`), "cortex_distributor_relabel_series_changed_total", "cortex_distributor_relabel_series_dropped_total"))
+
+	ds[0].cleanupInactiveUser("user")
+
+	assert.NoError(t, testutil.GatherAndCompare(regs[0], strings.NewReader(""), "cortex_distributor_relabel_series_changed_total", "cortex_distributor_relabel_series_dropped_total"))
+}
+
+func TestDistributor_RelabelDryRunHandler(t *testing.T) {
+	var limits validation.Limits
+	flagext.DefaultValues(&limits)
+	limits.MetricRelabelConfigs = []*relabel.Config{
+		{
+			SourceLabels: []model.LabelName{"__name__"},
+			Action:       relabel.Drop,
+			Regex:        relabel.MustNewRegexp("bar"),
+		},
+	}
+
+	ds, _, regs := prepare(t, prepConfig{
+		numDistributors: 1,
+		limits:          &limits,
+	})
+
+	body, err := (&mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "foo"}, {Name: "pod", Value: "pod-1"}}, 1, 1),
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "bar"}, {Name: "pod", Value: "pod-1"}}, 1, 1),
+	}}).Marshal()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		query            url.Values
+		noOrgID          bool
+		expectedCode     int
+		expectedResponse relabelDryRunResponse
+	}{
+		"no tenant ID": {
+			noOrgID:      true,
+			expectedCode: http.StatusUnauthorized,
+		},
+		"tenant's relabel rules": {
+			expectedCode: http.StatusOK,
+			expectedResponse: relabelDryRunResponse{
+				Series: []relabelDryRunSeries{
+					{Before: labels.FromStrings("__name__", "foo", "pod", "pod-1"), After: labels.FromStrings("__name__", "foo", "pod", "pod-1"), Rules: []string{}},
+					{Before: labels.FromStrings("__name__", "bar", "pod", "pod-1"), After: labels.EmptyLabels(), Dropped: true, Rules: []string{"0"}},
+				},
+				Rules: []relabelDryRunRule{{Rule: "0", Action: "drop", SeriesDropped: 1}},
+			},
+		},
+		"overridden relabel rules": {
+			query: url.Values{
+				"metric_relabel_configs": {`[{source_labels: [pod], regex: "pod-(.*)", target_label: shard, action: hashmod, modulus: 1}]`},
+				"drop_labels":            {"pod"},
+			},
+			expectedCode: http.StatusOK,
+			expectedResponse: relabelDryRunResponse{
+				Series: []relabelDryRunSeries{
+					{Before: labels.FromStrings("__name__", "foo", "pod", "pod-1"), After: labels.FromStrings("__name__", "foo", "shard", "0"), Rules: []string{"0", "drop_labels"}},
+					{Before: labels.FromStrings("__name__", "bar", "pod", "pod-1"), After: labels.FromStrings("__name__", "bar", "shard", "0"), Rules: []string{"0", "drop_labels"}},
+				},
+				Rules: []relabelDryRunRule{
+					{Rule: "0", Action: "hashmod", SeriesChanged: 2},
+					{Rule: "drop_labels", Action: "labeldrop", SeriesChanged: 2},
+				},
+			},
+		},
+		"invalid relabel rules": {
+			query:        url.Values{"metric_relabel_configs": {`[{source_labels: [pod], regex: "(", action: drop}]`}},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := createRequest(t, body)
+			req.URL.RawQuery = tc.query.Encode()
+			if !tc.noOrgID {
+				req = req.WithContext(user.InjectOrgID(req.Context(), "user"))
+			}
+
+			resp := httptest.NewRecorder()
+			ds[0].RelabelDryRunHandler(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code, resp.Body.String())
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+
+			var actual relabelDryRunResponse
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &actual))
+			assert.Equal(t, tc.expectedResponse, actual)
+		})
+	}
+
+	// Dry-run requests are not tracked by the relabel stats.
+	assert.NoError(t, testutil.GatherAndCompare(regs[0], strings.NewReader(""), "cortex_distributor_relabel_series_changed_total", "cortex_distributor_relabel_series_dropped_total"))
+}
+
+func TestDistributor_RelabelDryRunHandler_TrafficCapture(t *testing.T) {
+	var limits validation.Limits
+	flagext.DefaultValues(&limits)
+	limits.MetricRelabelConfigs = []*relabel.Config{
+		{
+			SourceLabels: []model.LabelName{"__name__"},
+			Action:       relabel.Drop,
+			Regex:        relabel.MustNewRegexp("bar"),
+		},
+	}
+
+	ds, _, _ := prepare(t, prepConfig{
+		numDistributors: 1,
+		limits:          &limits,
+	})
+
+	// The requests captured for other tenants and the ones which aren't push requests are skipped.
+	capture := strings.Join([]string{
+		`{"request":{"method":"POST","url":{"path":"/api/v1/push"},"tenant":"user","push":{"version":"1","timeseries":[{"metric":{"__name__":"foo","pod":"pod-1"},"samples":1},{"metric":{"__name__":"bar","pod":"pod-1"},"samples":1}]}}}`,
+		`{"request":{"method":"POST","url":{"path":"/api/v1/push"},"tenant":"another-user","push":{"version":"1","timeseries":[{"metric":{"__name__":"foo","pod":"pod-2"},"samples":1}]}}}`,
+		`{"request":{"method":"GET","url":{"path":"/api/v1/query"},"tenant":"user"}}`,
+	}, "\n")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/relabel/dry-run", strings.NewReader(capture))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req = req.WithContext(user.InjectOrgID(req.Context(), "user"))
+
+	resp := httptest.NewRecorder()
+	ds[0].RelabelDryRunHandler(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var actual relabelDryRunResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &actual))
+	assert.Equal(t, relabelDryRunResponse{
+		Series: []relabelDryRunSeries{
+			{Before: labels.FromStrings("__name__", "foo", "pod", "pod-1"), After: labels.FromStrings("__name__", "foo", "pod", "pod-1"), Rules: []string{}},
+			{Before: labels.FromStrings("__name__", "bar", "pod", "pod-1"), After: labels.EmptyLabels(), Dropped: true, Rules: []string{"0"}},
+		},
+		Rules: []relabelDryRunRule{{Rule: "0", Action: "drop", SeriesDropped: 1}},
+	}, actual)
+
+	// An invalid capture is rejected.
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/relabel/dry-run", strings.NewReader(`{"request":`))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req = req.WithContext(user.InjectOrgID(req.Context(), "user"))
+
+	resp = httptest.NewRecorder()
+	ds[0].RelabelDryRunHandler(resp, req)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+}
+
+func TestRelabelSnapshotOf(t *testing.T) {
+	series := labels.FromStrings("__name__", "metric", "pod", "pod-1", "namespace", "ns")
+
+	tests := map[string]struct {
+		config          string
+		expectedChanged bool
+	}{
+		"replace not matching": {
+			config: `{source_labels: [pod], regex: "other", target_label: shard, action: replace}`,
+		},
+		"replace setting the same value": {
+			config: `{source_labels: [pod], regex: "(.*)", target_label: pod, action: replace}`,
+		},
+		"replace": {
+			config:          `{source_labels: [pod], regex: "pod-(.*)", target_label: shard, action: replace}`,
+			expectedChanged: true,
+		},
+		"replace with templated target label": {
+			config:          `{source_labels: [pod], regex: "(pod)-(.*)", target_label: "${1}_id", replacement: "$2", action: replace}`,
+			expectedChanged: true,
+		},
+		"uppercase": {
+			config:          `{source_labels: [pod], target_label: pod, action: uppercase}`,
+			expectedChanged: true,
+		},
+		"labeldrop not matching": {
+			config: `{regex: "other", action: labeldrop}`,
+		},
+		"labeldrop": {
+			config:          `{regex: "namespace", action: labeldrop}`,
+			expectedChanged: true,
+		},
+		"labelmap swapping values": {
+			config:          `{regex: "(pod)", replacement: "namespace", action: labelmap}`,
+			expectedChanged: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfgs, err := parseRelabelConfigs("[" + tc.config + "]")
+			require.NoError(t, err)
+
+			lb := labels.NewBuilder(series)
+			before := relabelSnapshotOf(lb, cfgs[0])
+			require.True(t, relabel.ProcessBuilder(lb, cfgs[0]))
+
+			assert.Equal(t, tc.expectedChanged, relabelSnapshotOf(lb, cfgs[0]) != before)
+			assert.Equal(t, tc.expectedChanged, !labels.Equal(series, lb.Labels()))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+
+	ingester_client "github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+// streamingAggregationInput is an input series of a streaming aggregation rule, routed by its output series.
+type streamingAggregationInput struct {
+	ruleKey string
+	series  mimirpb.PreallocTimeseries
+}
+
+// prePushStreamingAggregationMiddleware removes the series whose streaming aggregation rules drop their input series
+// and, once the request has been pushed, forwards the validated series matching the tenant's rules to the ingesters
+// owning the output series, which aggregate them.
+func (d *Distributor) prePushStreamingAggregationMiddleware(next PushFunc) PushFunc {
+	return func(ctx context.Context, pushReq *Request) error {
+		cleanupInDefer := true
+		defer func() {
+			if cleanupInDefer {
+				pushReq.CleanUp()
+			}
+		}()
+
+		req, err := pushReq.WriteRequest()
+		if err != nil {
+			return err
+		}
+
+		userID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(req.Timeseries) == 0 || len(d.limits.StreamingAggregationRules(userID)) == 0 {
+			cleanupInDefer = false
+			return next(ctx, pushReq)
+		}
+
+		var (
+			inputs        []streamingAggregationInput
+			keys          []uint32
+			removeIndexes []int
+			samples       int
+		)
+		for tsIdx, ts := range req.Timeseries {
+			if len(ts.Samples) == 0 {
+				continue
+			}
+
+			var input mimirpb.PreallocTimeseries
+			drop := d.streamAggregationRules.Route(userID, mimirpb.FromLabelAdaptersToLabels(ts.Labels), func(ruleKey string, output labels.Labels) {
+				if input.TimeSeries == nil {
+					// The series is backed by the request buffer, which is reused once the request is ingested.
+					input.TimeSeries = &mimirpb.TimeSeries{
+						Labels:  mimirpb.FromLabelsToLabelAdapters(mimirpb.FromLabelAdaptersToLabelsWithCopy(ts.Labels)),
+						Samples: append([]mimirpb.Sample(nil), ts.Samples...),
+					}
+				}
+				inputs = append(inputs, streamingAggregationInput{ruleKey: ruleKey, series: input})
+				keys = append(keys, d.tokenForLabels(userID, mimirpb.FromLabelsToLabelAdapters(output)))
+				samples += len(ts.Samples)
+			})
+			if drop {
+				removeIndexes = append(removeIndexes, tsIdx)
+			}
+		}
+
+		if len(removeIndexes) > 0 {
+			d.streamAggregationDroppedSeries.WithLabelValues(userID).Add(float64(len(removeIndexes)))
+			for _, removeIndex := range removeIndexes {
+				mimirpb.ReusePreallocTimeseries(&req.Timeseries[removeIndex])
+			}
+			req.Timeseries = util.RemoveSliceIndexes(req.Timeseries, removeIndexes)
+		}
+
+		cleanupInDefer = false
+		if err := next(ctx, pushReq); err != nil {
+			// The inputs aren't aggregated when the series failed to be pushed, because the client retries the
+			// whole request.
+			return err
+		}
+
+		if len(inputs) > 0 {
+			if err := d.sendStreamingAggregationInputs(ctx, userID, inputs, keys); err != nil {
+				return err
+			}
+			d.streamAggregationSamples.WithLabelValues(userID).Add(float64(samples))
+		}
+		return nil
+	}
+}
+
+// sendStreamingAggregationInputs sends the input series of the streaming aggregation rules to the ingesters owning
+// their output series, as identified by keys, so that the replicas of each output series receive all its samples.
+func (d *Distributor) sendStreamingAggregationInputs(ctx context.Context, userID string, inputs []streamingAggregationInput, keys []uint32) error {
+	subRing := d.ingestersRing.ShuffleShard(userID, d.limits.IngestionTenantShardSize(userID))
+
+	// Use a background context to make sure all ingesters get the series even if we return early.
+	localCtx, cancel := context.WithTimeout(context.Background(), d.cfg.RemoteTimeout)
+	localCtx = user.InjectOrgID(localCtx, userID)
+
+	return ring.DoBatch(ctx, ring.WriteNoExtend, subRing, keys, func(ingester ring.InstanceDesc, indexes []int) error {
+		// The ingesters aggregate all the series of a request into the same rule.
+		byRule := map[string][]mimirpb.PreallocTimeseries{}
+		for _, i := range indexes {
+			byRule[inputs[i].ruleKey] = append(byRule[inputs[i].ruleKey], inputs[i].series)
+		}
+
+		h, err := d.ingesterPool.GetClientForInstance(ingester)
+		if err != nil {
+			return err
+		}
+		c := h.(ingester_client.IngesterClient)
+
+		for ruleKey, series := range byRule {
+			_, err := c.Push(localCtx, &mimirpb.WriteRequest{
+				Timeseries:               series,
+				Source:                   mimirpb.API,
+				StreamingAggregationRule: ruleKey,
+			})
+			if err = handleIngesterPushError(err); err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					return httpgrpc.Errorf(500, "exceeded configured distributor remote timeout: %s", err.Error())
+				}
+				return err
+			}
+		}
+		return nil
+	}, cancel)
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/test"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/streamaggr"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestDistributor_Push_StreamingAggregation(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now()
+
+	var limits validation.Limits
+	flagext.DefaultValues(&limits)
+	limits.StreamingAggregationRules = []*streamaggr.Rule{
+		{Match: `{job="api"}`, By: []string{"job"}, Output: streamaggr.OutputSum, Interval: model.Duration(time.Minute), Suffix: ":sum", DropInput: true},
+	}
+
+	ds, ingesters, _ := prepare(t, prepConfig{
+		numIngesters:      6,
+		happyIngesters:    6,
+		numDistributors:   1,
+		replicationFactor: 3,
+		limits:            &limits,
+	})
+
+	apiSeries := [][]mimirpb.LabelAdapter{
+		{{Name: "__name__", Value: "requests_total"}, {Name: "job", Value: "api"}, {Name: "pod", Value: "1"}},
+		{{Name: "__name__", Value: "requests_total"}, {Name: "job", Value: "api"}, {Name: "pod", Value: "2"}},
+	}
+	expectedInputs := []string{
+		mimirpb.FromLabelAdaptersToLabels(apiSeries[0]).String(),
+		mimirpb.FromLabelAdaptersToLabels(apiSeries[1]).String(),
+	}
+
+	req := &mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{
+		makeWriteRequestTimeseries(apiSeries[0], now.UnixMilli(), 10),
+		makeWriteRequestTimeseries(apiSeries[1], now.UnixMilli(), 20),
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "requests_total"}, {Name: "job", Value: "db"}, {Name: "pod", Value: "1"}}, now.UnixMilli(), 30),
+	}}
+	_, err := ds[0].Push(ctx, req)
+	require.NoError(t, err)
+
+	// The input series are routed by output series, so that the replicas of the output series receive all of them.
+	test.Poll(t, time.Second, 3, func() interface{} {
+		aggregating := 0
+		for idx := range ingesters {
+			ing := &ingesters[idx]
+			for _, inputs := range ing.streamingAggregationInputsByRule() {
+				sort.Strings(inputs)
+				require.Equal(t, expectedInputs, inputs)
+				aggregating++
+			}
+		}
+		return aggregating
+	})
+
+	// The input series of the rule have been dropped.
+	ingestedSeries := map[string]struct{}{}
+	test.Poll(t, time.Second, 3, func() interface{} {
+		replicas := 0
+		for idx := range ingesters {
+			ing := &ingesters[idx]
+			for _, ts := range ing.series() {
+				ingestedSeries[mimirpb.FromLabelAdaptersToLabels(ts.Labels).String()] = struct{}{}
+				replicas++
+			}
+		}
+		return replicas
+	})
+	assert.Equal(t, map[string]struct{}{
+		labels.FromStrings("__name__", "requests_total", "job", "db", "pod", "1").String(): {},
+	}, ingestedSeries)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(ds[0].streamAggregationSamples.WithLabelValues("user")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(ds[0].streamAggregationDroppedSeries.WithLabelValues("user")))
+}
+
+func TestDistributor_StreamingAggregation_ShouldNotForwardInputsIfThePushFails(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now()
+
+	var limits validation.Limits
+	flagext.DefaultValues(&limits)
+	limits.StreamingAggregationRules = []*streamaggr.Rule{
+		{Match: `{job="api"}`, By: []string{"job"}, Output: streamaggr.OutputSum, Interval: model.Duration(time.Minute), Suffix: ":sum"},
+	}
+
+	ds, ingesters, _ := prepare(t, prepConfig{
+		numIngesters:      3,
+		happyIngesters:    3,
+		numDistributors:   1,
+		replicationFactor: 3,
+		limits:            &limits,
+	})
+
+	req := &mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{
+		makeWriteRequestTimeseries([]mimirpb.LabelAdapter{{Name: "__name__", Value: "requests_total"}, {Name: "job", Value: "api"}, {Name: "pod", Value: "1"}}, now.UnixMilli(), 10),
+	}}
+	pushErr := errors.New("push failed")
+	push := ds[0].prePushStreamingAggregationMiddleware(func(context.Context, *Request) error {
+		return pushErr
+	})
+	require.ErrorIs(t, push(ctx, NewParsedRequest(req)), pushErr)
+
+	// The client retries the request, so the inputs aren't aggregated twice.
+	for idx := range ingesters {
+		assert.Empty(t, ingesters[idx].streamingAggregationInputsByRule())
+	}
+	assert.Equal(t, float64(0), testutil.ToFloat64(ds[0].streamAggregationSamples.WithLabelValues("user")))
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 
+	"github.com/grafana/mimir/pkg/costattribution"
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/util/extract"
 	"github.com/grafana/mimir/pkg/util/globalerror"
@@ -25,6 +26,9 @@ const (
 	// The combined length of the label names and values of an Exemplar's LabelSet MUST NOT exceed 128 UTF-8 characters
 	// https://github.com/OpenObservability/OpenMetrics/blob/main/specification/OpenMetrics.md#exemplars
 	ExemplarMaxLabelSetLength = 128
+
+	// minNativeHistogramSchema is the lowest resolution schema supported by native histograms.
+	minNativeHistogramSchema = -4
 )
 
 var (
@@ -107,6 +111,7 @@ var (
 type sampleValidationConfig interface {
 	CreationGracePeriod(userID string) time.Duration
 	MaxNativeHistogramBuckets(userID string) int
+	ReduceNativeHistogramOverMaxBuckets(userID string) bool
 }
 
 // sampleValidationMetrics is a collection of metrics used during sample validation.
@@ -194,9 +199,10 @@ func newExemplarValidationMetrics(r prometheus.Registerer) *exemplarValidationMe
 // validateSample returns an err if the sample is invalid.
 // The returned error may retain the provided series labels.
 // It uses the passed 'now' time to measure the relative time of the sample.
-func validateSample(m *sampleValidationMetrics, now model.Time, cfg sampleValidationConfig, userID, group string, ls []mimirpb.LabelAdapter, s mimirpb.Sample) error {
+func validateSample(m *sampleValidationMetrics, now model.Time, cfg sampleValidationConfig, userID, group string, ls []mimirpb.LabelAdapter, s mimirpb.Sample, cat *costattribution.Tracker) error {
 	if model.Time(s.TimestampMs) > now.Add(cfg.CreationGracePeriod(userID)) {
 		m.tooFarInFuture.WithLabelValues(userID, group).Inc()
+		cat.IncrementDiscardedSamples(ls, 1, reasonTooFarInFuture, now.Time())
 		unsafeMetricName, _ := extract.UnsafeMetricNameFromLabelAdapters(ls)
 		return fmt.Errorf(sampleTimestampTooNewMsgFormat, s.TimestampMs, unsafeMetricName)
 	}
@@ -205,24 +211,29 @@ func validateSample(m *sampleValidationMetrics, now model.Time, cfg sampleValida
 }
 
 // validateSampleHistogram returns an err if the sample is invalid.
+// If the sample has more buckets than the limit and the tenant is configured to reduce such histograms,
+// the resolution of the sample is reduced in place until the buckets fit the limit.
 // The returned error may retain the provided series labels.
 // It uses the passed 'now' time to measure the relative time of the sample.
-func validateSampleHistogram(m *sampleValidationMetrics, now model.Time, cfg sampleValidationConfig, userID, group string, ls []mimirpb.LabelAdapter, s mimirpb.Histogram) error {
+func validateSampleHistogram(m *sampleValidationMetrics, now model.Time, cfg sampleValidationConfig, userID, group string, ls []mimirpb.LabelAdapter, s *mimirpb.Histogram, cat *costattribution.Tracker) error {
 	if model.Time(s.Timestamp) > now.Add(cfg.CreationGracePeriod(userID)) {
 		m.tooFarInFuture.WithLabelValues(userID, group).Inc()
+		cat.IncrementDiscardedSamples(ls, 1, reasonTooFarInFuture, now.Time())
 		unsafeMetricName, _ := extract.UnsafeMetricNameFromLabelAdapters(ls)
 		return fmt.Errorf(sampleTimestampTooNewMsgFormat, s.Timestamp, unsafeMetricName)
 	}
 
 	if bucketLimit := cfg.MaxNativeHistogramBuckets(userID); bucketLimit > 0 {
-		var bucketCount int
-		if s.IsFloatHistogram() {
-			bucketCount = len(s.GetNegativeCounts()) + len(s.GetPositiveCounts())
-		} else {
-			bucketCount = len(s.GetNegativeDeltas()) + len(s.GetPositiveDeltas())
+		bucketCount := s.BucketCount()
+		if bucketCount > bucketLimit && cfg.ReduceNativeHistogramOverMaxBuckets(userID) {
+			for bucketCount > bucketLimit && s.Schema > minNativeHistogramSchema {
+				s.ReduceResolution(s.Schema - 1)
+				bucketCount = s.BucketCount()
+			}
 		}
 		if bucketCount > bucketLimit {
 			m.maxNativeHistogramBuckets.WithLabelValues(userID, group).Inc()
+			cat.IncrementDiscardedSamples(ls, 1, reasonMaxNativeHistogramBuckets, now.Time())
 			return fmt.Errorf(maxNativeHistogramBucketsMsgFormat, s.Timestamp, mimirpb.FromLabelAdaptersToLabels(ls).String(), bucketCount, bucketLimit)
 		}
 	}
@@ -296,21 +307,24 @@ type labelValidationConfig interface {
 
 // validateLabels returns an err if the labels are invalid.
 // The returned error may retain the provided series labels.
-func validateLabels(m *sampleValidationMetrics, cfg labelValidationConfig, userID, group string, ls []mimirpb.LabelAdapter, skipLabelNameValidation bool) error {
+func validateLabels(m *sampleValidationMetrics, cfg labelValidationConfig, userID, group string, ls []mimirpb.LabelAdapter, skipLabelNameValidation bool, cat *costattribution.Tracker, now time.Time) error {
 	unsafeMetricName, err := extract.UnsafeMetricNameFromLabelAdapters(ls)
 	if err != nil {
 		m.missingMetricName.WithLabelValues(userID, group).Inc()
+		cat.IncrementDiscardedSamples(ls, 1, reasonMissingMetricName, now)
 		return errors.New(noMetricNameMsgFormat)
 	}
 
 	if !model.IsValidMetricName(model.LabelValue(unsafeMetricName)) {
 		m.invalidMetricName.WithLabelValues(userID, group).Inc()
+		cat.IncrementDiscardedSamples(ls, 1, reasonInvalidMetricName, now)
 		return fmt.Errorf(invalidMetricNameMsgFormat, unsafeMetricName)
 	}
 
 	numLabelNames := len(ls)
 	if numLabelNames > cfg.MaxLabelNamesPerSeries(userID) {
 		m.maxLabelNamesPerSeries.WithLabelValues(userID, group).Inc()
+		cat.IncrementDiscardedSamples(ls, 1, reasonMaxLabelNamesPerSeries, now)
 		metric, ellipsis := getMetricAndEllipsis(ls)
 		return fmt.Errorf(tooManyLabelsMsgFormat, len(ls), cfg.MaxLabelNamesPerSeries(userID), metric, ellipsis)
 	}
@@ -321,15 +335,19 @@ func validateLabels(m *sampleValidationMetrics, cfg labelValidationConfig, userI
 	for _, l := range ls {
 		if !skipLabelNameValidation && !model.LabelName(l.Name).IsValid() {
 			m.invalidLabel.WithLabelValues(userID, group).Inc()
+			cat.IncrementDiscardedSamples(ls, 1, reasonInvalidLabel, now)
 			return fmt.Errorf(invalidLabelMsgFormat, l.Name, formatLabelSet(ls))
 		} else if len(l.Name) > maxLabelNameLength {
 			m.labelNameTooLong.WithLabelValues(userID, group).Inc()
+			cat.IncrementDiscardedSamples(ls, 1, reasonLabelNameTooLong, now)
 			return fmt.Errorf(labelNameTooLongMsgFormat, l.Name, formatLabelSet(ls))
 		} else if len(l.Value) > maxLabelValueLength {
 			m.labelValueTooLong.WithLabelValues(userID, group).Inc()
+			cat.IncrementDiscardedSamples(ls, 1, reasonLabelValueTooLong, now)
 			return fmt.Errorf(labelValueTooLongMsgFormat, l.Value, formatLabelSet(ls))
 		} else if lastLabelName == l.Name {
 			m.duplicateLabelNames.WithLabelValues(userID, group).Inc()
+			cat.IncrementDiscardedSamples(ls, 1, reasonDuplicateLabelNames, now)
 			return fmt.Errorf(duplicateLabelMsgFormat, l.Name, formatLabelSet(ls))
 		}
 
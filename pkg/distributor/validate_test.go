@@ -148,7 +148,7 @@ func TestValidateLabels(t *testing.T) {
 			nil,
 		},
 	} {
-		err := validateLabels(s, cfg, userID, "custom label", mimirpb.FromMetricsToLabelAdapters(c.metric), c.skipLabelNameValidation)
+		err := validateLabels(s, cfg, userID, "custom label", mimirpb.FromMetricsToLabelAdapters(c.metric), c.skipLabelNameValidation, nil, time.Now())
 		assert.Equal(t, c.err, err, "wrong error")
 	}
 
@@ -351,7 +351,7 @@ func TestValidateLabelDuplication(t *testing.T) {
 	actual := validateLabels(newSampleValidationMetrics(nil), cfg, userID, "", []mimirpb.LabelAdapter{
 		{Name: model.MetricNameLabel, Value: "a"},
 		{Name: model.MetricNameLabel, Value: "b"},
-	}, false)
+	}, false, nil, time.Now())
 	expected := fmt.Errorf(
 		duplicateLabelMsgFormat,
 		model.MetricNameLabel,
@@ -368,7 +368,7 @@ func TestValidateLabelDuplication(t *testing.T) {
 		{Name: model.MetricNameLabel, Value: "a"},
 		{Name: "a", Value: "a"},
 		{Name: "a", Value: "a"},
-	}, false)
+	}, false, nil, time.Now())
 	expected = fmt.Errorf(
 		duplicateLabelMsgFormat,
 		"a",
@@ -384,7 +384,8 @@ func TestValidateLabelDuplication(t *testing.T) {
 }
 
 type sampleValidationCfg struct {
-	maxNativeHistogramBuckets int
+	maxNativeHistogramBuckets           int
+	reduceNativeHistogramOverMaxBuckets bool
 }
 
 func (c sampleValidationCfg) CreationGracePeriod(_ string) time.Duration {
@@ -395,6 +396,10 @@ func (c sampleValidationCfg) MaxNativeHistogramBuckets(_ string) int {
 	return c.maxNativeHistogramBuckets
 }
 
+func (c sampleValidationCfg) ReduceNativeHistogramOverMaxBuckets(_ string) bool {
+	return c.reduceNativeHistogramOverMaxBuckets
+}
+
 func TestMaxNativeHistorgramBuckets(t *testing.T) {
 	// All will have 2 buckets, one negative and one positive
 	testCases := map[string]mimirpb.Histogram{
@@ -515,7 +520,7 @@ func TestMaxNativeHistorgramBuckets(t *testing.T) {
 
 				err := validateSampleHistogram(metrics, model.Now(), cfg, "user-1", "group-1", []mimirpb.LabelAdapter{
 					{Name: model.MetricNameLabel, Value: "a"},
-					{Name: "a", Value: "a"}}, h)
+					{Name: "a", Value: "a"}}, &h, nil)
 
 				if limit == 1 {
 					require.Error(t, err)
@@ -533,6 +538,99 @@ func TestMaxNativeHistorgramBuckets(t *testing.T) {
 	`), "cortex_discarded_samples_total"))
 }
 
+func TestMaxNativeHistogramBuckets_ReduceResolution(t *testing.T) {
+	testCases := map[string]struct {
+		histogram   mimirpb.Histogram
+		limit       int
+		expected    mimirpb.Histogram
+		expectedErr bool
+	}{
+		"integer histogram within the limit is not reduced": {
+			histogram: mimirpb.Histogram{
+				Count:          &mimirpb.Histogram_CountInt{CountInt: 8},
+				Schema:         3,
+				PositiveSpans:  []mimirpb.BucketSpan{{Offset: 1, Length: 8}},
+				PositiveDeltas: []int64{1, 0, 0, 0, 0, 0, 0, 0},
+			},
+			limit: 8,
+			expected: mimirpb.Histogram{
+				Count:          &mimirpb.Histogram_CountInt{CountInt: 8},
+				Schema:         3,
+				PositiveSpans:  []mimirpb.BucketSpan{{Offset: 1, Length: 8}},
+				PositiveDeltas: []int64{1, 0, 0, 0, 0, 0, 0, 0},
+			},
+		},
+		"integer histogram over the limit is reduced": {
+			histogram: mimirpb.Histogram{
+				Count:          &mimirpb.Histogram_CountInt{CountInt: 8},
+				Schema:         3,
+				PositiveSpans:  []mimirpb.BucketSpan{{Offset: 1, Length: 8}},
+				PositiveDeltas: []int64{1, 0, 0, 0, 0, 0, 0, 0},
+			},
+			limit: 2,
+			expected: mimirpb.Histogram{
+				Count:          &mimirpb.Histogram_CountInt{CountInt: 8},
+				Schema:         1,
+				PositiveSpans:  []mimirpb.BucketSpan{{Offset: 1, Length: 2}},
+				PositiveDeltas: []int64{4, 0},
+			},
+		},
+		"float histogram over the limit is reduced": {
+			histogram: mimirpb.Histogram{
+				Count:          &mimirpb.Histogram_CountFloat{CountFloat: 8},
+				Schema:         3,
+				NegativeSpans:  []mimirpb.BucketSpan{{Offset: 1, Length: 8}},
+				NegativeCounts: []float64{1, 1, 1, 1, 1, 1, 1, 1},
+			},
+			limit: 3,
+			expected: mimirpb.Histogram{
+				Count:          &mimirpb.Histogram_CountFloat{CountFloat: 8},
+				Schema:         1,
+				NegativeSpans:  []mimirpb.BucketSpan{{Offset: 1, Length: 2}},
+				NegativeCounts: []float64{4, 4},
+			},
+		},
+		"histogram which can't be reduced to fit the limit is rejected": {
+			histogram: mimirpb.Histogram{
+				Count:          &mimirpb.Histogram_CountInt{CountInt: 2},
+				Schema:         3,
+				PositiveSpans:  []mimirpb.BucketSpan{{Offset: 0, Length: 2}},
+				PositiveDeltas: []int64{1, 0},
+			},
+			limit:       1,
+			expectedErr: true,
+		},
+	}
+
+	registry := prometheus.NewRegistry()
+	metrics := newSampleValidationMetrics(registry)
+	cfg := sampleValidationCfg{reduceNativeHistogramOverMaxBuckets: true}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg.maxNativeHistogramBuckets = tc.limit
+
+			h := tc.histogram
+			err := validateSampleHistogram(metrics, model.Now(), cfg, "user-1", "group-1", []mimirpb.LabelAdapter{
+				{Name: model.MetricNameLabel, Value: "a"},
+				{Name: "a", Value: "a"}}, &h, nil)
+
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, h)
+		})
+	}
+
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+			# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+			# TYPE cortex_discarded_samples_total counter
+			cortex_discarded_samples_total{group="group-1",reason="max_native_histogram_buckets",user="user-1"} 1
+	`), "cortex_discarded_samples_total"))
+}
+
 func tooManyLabelsArgs(series []mimirpb.LabelAdapter, limit int) []any {
 	metric := mimirpb.FromLabelAdaptersToMetric(series).String()
 	ellipsis := ""
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package activequeries
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/mimir/pkg/util"
+	util_log "github.com/grafana/mimir/pkg/util/log"
+)
+
+// Peers lists and cancels the active queries in the other query-frontends of the cluster. The errors they return
+// describe the query-frontends which couldn't be reached, while the results of the other ones are still returned.
+type Peers interface {
+	// ActiveQueries returns the active queries of the tenant in the context.
+	ActiveQueries(ctx context.Context) ([]Info, error)
+
+	// CancelActiveQuery cancels the query of the tenant in the context, and returns whether it was found.
+	CancelActiveQuery(ctx context.Context, id string) (bool, error)
+}
+
+type handler struct {
+	tracker *Tracker
+	peers   Peers
+	logger  log.Logger
+}
+
+// NewHandler returns the handler of the active queries API, listing the active queries of the tenant
+// on GET requests, and cancelling the query whose ID is in the path on DELETE requests.
+// The peers are optional: without them, only the queries of this query-frontend are listed and cancelled.
+func NewHandler(tracker *Tracker, peers Peers, logger log.Logger) http.Handler {
+	return &handler{
+		tracker: tracker,
+		peers:   peers,
+		logger:  logger,
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantIDs, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID := tenant.JoinTenantIDs(tenantIDs)
+
+	switch r.Method {
+	case http.MethodGet:
+		h.list(w, r, userID)
+	case http.MethodDelete:
+		h.cancel(w, r, userID)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported method %s", r.Method), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request, userID string) {
+	queries := h.tracker.List(userID)
+
+	// The queries of the query-frontends which can't be reached are missing, which is reported in the warnings.
+	var warnings []string
+	if h.peers != nil {
+		peerQueries, err := h.peers.ActiveQueries(r.Context())
+		if err != nil {
+			level.Warn(util_log.WithContext(r.Context(), h.logger)).Log("msg", "failed to list the active queries of some query-frontends", "err", err)
+			warnings = append(warnings, fmt.Sprintf("the active queries of some query-frontends couldn't be listed: %s", err))
+		}
+		queries = append(queries, peerQueries...)
+		sortInfos(queries)
+	}
+
+	util.WriteJSONResponse(w, struct {
+		Status   string   `json:"status"`
+		Data     []Info   `json:"data"`
+		Warnings []string `json:"warnings,omitempty"`
+	}{
+		Status:   "success",
+		Data:     queries,
+		Warnings: warnings,
+	})
+}
+
+func (h *handler) cancel(w http.ResponseWriter, r *http.Request, userID string) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		http.Error(w, "missing query ID", http.StatusBadRequest)
+		return
+	}
+
+	cancelled := h.tracker.Cancel(userID, id)
+	if !cancelled && h.peers != nil {
+		var err error
+		cancelled, err = h.peers.CancelActiveQuery(r.Context(), id)
+		if !cancelled && err != nil {
+			// The query may be running in a query-frontend which can't be reached.
+			level.Warn(util_log.WithContext(r.Context(), h.logger)).Log("msg", "failed to cancel the query in some query-frontends", "id", id, "err", err)
+			http.Error(w, fmt.Sprintf("query %s not found in the query-frontends which could be reached: %s", id, err), http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	if !cancelled {
+		http.Error(w, fmt.Sprintf("query %s not found", id), http.StatusNotFound)
+		return
+	}
+
+	level.Info(util_log.WithContext(r.Context(), h.logger)).Log("msg", "query cancelled through the active queries API", "id", id)
+	util.WriteJSONResponse(w, struct {
+		Status string `json:"status"`
+	}{
+		Status: "success",
+	})
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package activequeries
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type peersMock struct {
+	queries   []Info
+	cancelled map[string]bool
+	err       error
+}
+
+func (p *peersMock) ActiveQueries(context.Context) ([]Info, error) {
+	return p.queries, p.err
+}
+
+func (p *peersMock) CancelActiveQuery(_ context.Context, id string) (bool, error) {
+	if _, ok := p.cancelled[id]; !ok {
+		return false, p.err
+	}
+	p.cancelled[id] = true
+	return true, p.err
+}
+
+func TestHandler(t *testing.T) {
+	tracker := NewTracker()
+	q, ctx := tracker.Track(user.InjectOrgID(context.Background(), "user-1"), http.MethodGet, "/api/v1/query", nil)
+	defer tracker.Done(q)
+
+	peers := &peersMock{
+		queries:   []Info{{ID: "peer-query", UserID: "user-1", StartTime: q.startTime.Add(-time.Minute)}},
+		cancelled: map[string]bool{"peer-query": false},
+	}
+
+	router := mux.NewRouter()
+	h := NewHandler(tracker, peers, log.NewNopLogger())
+	router.Path("/api/v1/active_queries").Methods(http.MethodGet).Handler(h)
+	router.Path("/api/v1/active_queries/{id}").Methods(http.MethodDelete).Handler(h)
+
+	do := func(method, path, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if userID != "" {
+			req = req.WithContext(user.InjectOrgID(req.Context(), userID))
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("list", func(t *testing.T) {
+		resp := do(http.MethodGet, "/api/v1/active_queries", "user-1")
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var res struct {
+			Status string `json:"status"`
+			Data   []Info `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+		require.Equal(t, "success", res.Status)
+
+		// The queries are sorted by start time.
+		require.Len(t, res.Data, 2)
+		assert.Equal(t, "peer-query", res.Data[0].ID)
+		assert.Equal(t, q.id, res.Data[1].ID)
+		assert.Equal(t, "/api/v1/query", res.Data[1].Path)
+	})
+
+	t.Run("list without tenant", func(t *testing.T) {
+		resp := do(http.MethodGet, "/api/v1/active_queries", "")
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("cancel an unknown query", func(t *testing.T) {
+		resp := do(http.MethodDelete, "/api/v1/active_queries/unknown", "user-1")
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("cancel a query of another tenant", func(t *testing.T) {
+		resp := do(http.MethodDelete, "/api/v1/active_queries/"+q.id, "user-2")
+		require.Equal(t, http.StatusNotFound, resp.Code)
+		require.NoError(t, ctx.Err())
+	})
+
+	t.Run("cancel a query of another query-frontend", func(t *testing.T) {
+		resp := do(http.MethodDelete, "/api/v1/active_queries/peer-query", "user-1")
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.True(t, peers.cancelled["peer-query"])
+	})
+
+	t.Run("cancel a query", func(t *testing.T) {
+		resp := do(http.MethodDelete, "/api/v1/active_queries/"+q.id, "user-1")
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.ErrorIs(t, context.Cause(ctx), ErrQueryCancelled)
+	})
+
+	t.Run("partially failing peers", func(t *testing.T) {
+		peers.err = errors.New("query-frontend 10.0.0.1:9095: unavailable")
+		peers.cancelled["other-peer-query"] = false
+		defer func() { peers.err = nil }()
+
+		// The queries of the query-frontends which could be reached are listed, with a warning.
+		resp := do(http.MethodGet, "/api/v1/active_queries", "user-1")
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var res struct {
+			Status   string   `json:"status"`
+			Data     []Info   `json:"data"`
+			Warnings []string `json:"warnings"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+		require.Equal(t, "success", res.Status)
+		require.Len(t, res.Data, 2)
+		require.Len(t, res.Warnings, 1)
+		assert.Contains(t, res.Warnings[0], "10.0.0.1:9095")
+
+		// A query which isn't found may be running in a query-frontend which couldn't be reached.
+		resp = do(http.MethodDelete, "/api/v1/active_queries/another", "user-1")
+		require.Equal(t, http.StatusServiceUnavailable, resp.Code)
+		assert.Contains(t, resp.Body.String(), "10.0.0.1:9095")
+
+		// A query found in a query-frontend which could be reached is cancelled.
+		resp = do(http.MethodDelete, "/api/v1/active_queries/other-peer-query", "user-1")
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.True(t, peers.cancelled["other-peer-query"])
+	})
+}
+
+func TestHandler_WithoutPeers(t *testing.T) {
+	tracker := NewTracker()
+	q, ctx := tracker.Track(user.InjectOrgID(context.Background(), "user-1"), http.MethodGet, "/api/v1/query", nil)
+	defer tracker.Done(q)
+
+	h := NewHandler(tracker, nil, log.NewNopLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/active_queries", nil).WithContext(user.InjectOrgID(context.Background(), "user-1"))
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), q.id)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/active_queries/"+q.id, nil).WithContext(user.InjectOrgID(context.Background(), "user-1"))
+	req = mux.SetURLVars(req, map[string]string{"id": q.id})
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.ErrorIs(t, context.Cause(ctx), ErrQueryCancelled)
+}
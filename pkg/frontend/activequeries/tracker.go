@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package activequeries
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/frontend/v2/frontendv2pb"
+)
+
+// ErrQueryCancelled is the cause of the cancellation of the queries cancelled through the active queries API.
+var ErrQueryCancelled = errors.New("the query has been cancelled through the active queries API")
+
+type contextKey int
+
+const activeQueryContextKey contextKey = 0
+
+// Info describes an active query.
+type Info struct {
+	ID                    string            `json:"id"`
+	UserID                string            `json:"user_id"`
+	Method                string            `json:"method"`
+	Path                  string            `json:"path"`
+	Params                map[string]string `json:"params"`
+	StartTime             time.Time         `json:"start_time"`
+	OutstandingSubqueries int               `json:"outstanding_subqueries"`
+	Queriers              []string          `json:"queriers"`
+}
+
+// Query is a query in progress in the query-frontend. A query is executed as one or more subqueries
+// sent to the queriers, after being split and sharded.
+type Query struct {
+	id        string
+	userID    string
+	method    string
+	path      string
+	params    map[string]string
+	startTime time.Time
+	cancel    context.CancelCauseFunc
+
+	mtx sync.Mutex
+	// IDs of the subqueries in progress.
+	subqueries map[uint64]struct{}
+	// IDs of the queriers which have executed subqueries of the query.
+	queriers map[string]struct{}
+}
+
+// SubqueryEnqueued records that a subquery of the query is waiting to be executed.
+func (q *Query) SubqueryEnqueued(subqueryID uint64) {
+	if q == nil {
+		return
+	}
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.subqueries[subqueryID] = struct{}{}
+}
+
+// SubqueryExecuted records that a subquery of the query has been executed by the querier, which is reported
+// with the result of the subquery.
+func (q *Query) SubqueryExecuted(querierID string) {
+	if q == nil || querierID == "" {
+		return
+	}
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.queriers[querierID] = struct{}{}
+}
+
+// SubqueryDone records that a subquery of the query has completed.
+func (q *Query) SubqueryDone(subqueryID uint64) {
+	if q == nil {
+		return
+	}
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	delete(q.subqueries, subqueryID)
+}
+
+func (q *Query) info() Info {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	queriers := make([]string, 0, len(q.queriers))
+	for querierID := range q.queriers {
+		queriers = append(queriers, querierID)
+	}
+	sort.Strings(queriers)
+
+	return Info{
+		ID:                    q.id,
+		UserID:                q.userID,
+		Method:                q.method,
+		Path:                  q.path,
+		Params:                q.params,
+		StartTime:             q.startTime,
+		OutstandingSubqueries: len(q.subqueries),
+		Queriers:              queriers,
+	}
+}
+
+// FromContext returns the query tracked in the context, or nil if there is none.
+func FromContext(ctx context.Context) *Query {
+	q, _ := ctx.Value(activeQueryContextKey).(*Query)
+	return q
+}
+
+// Tracker tracks the queries in progress in a query-frontend, so that they can be listed and cancelled.
+// It implements the frontendv2pb.FrontendForFrontendServer interface, to let the other query-frontends
+// list and cancel them.
+type Tracker struct {
+	lastID atomic.Uint64
+
+	mtx     sync.Mutex
+	queries map[string]*Query
+}
+
+func NewTracker() *Tracker {
+	t := &Tracker{
+		queries: map[string]*Query{},
+	}
+	// Randomize to make the IDs of the queries unique across the query-frontends.
+	t.lastID.Store(rand.Uint64())
+	return t
+}
+
+// Track starts tracking the query of the request. It returns the query, and a context which is cancelled when
+// the query is cancelled. The returned query is nil if the request has no tenant, in which case it isn't tracked.
+func (t *Tracker) Track(ctx context.Context, method, path string, params url.Values) (*Query, context.Context) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, ctx
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	q := &Query{
+		id:         strconv.FormatUint(t.lastID.Inc(), 10),
+		userID:     tenant.JoinTenantIDs(tenantIDs),
+		method:     method,
+		path:       path,
+		params:     formatParams(params),
+		startTime:  time.Now(),
+		cancel:     cancel,
+		subqueries: map[uint64]struct{}{},
+		queriers:   map[string]struct{}{},
+	}
+
+	t.mtx.Lock()
+	t.queries[q.id] = q
+	t.mtx.Unlock()
+
+	return q, context.WithValue(ctx, activeQueryContextKey, q)
+}
+
+// Done stops tracking the query. It's safe to call with a nil query.
+func (t *Tracker) Done(q *Query) {
+	if q == nil {
+		return
+	}
+
+	t.mtx.Lock()
+	delete(t.queries, q.id)
+	t.mtx.Unlock()
+
+	q.cancel(context.Canceled)
+}
+
+// List returns the queries in progress of the user, sorted by start time.
+func (t *Tracker) List(userID string) []Info {
+	t.mtx.Lock()
+	queries := make([]*Query, 0, len(t.queries))
+	for _, q := range t.queries {
+		if q.userID == userID {
+			queries = append(queries, q)
+		}
+	}
+	t.mtx.Unlock()
+
+	infos := make([]Info, 0, len(queries))
+	for _, q := range queries {
+		infos = append(infos, q.info())
+	}
+	sortInfos(infos)
+	return infos
+}
+
+// Cancel cancels the query of the user, and returns whether it was found.
+func (t *Tracker) Cancel(userID, id string) bool {
+	t.mtx.Lock()
+	q, ok := t.queries[id]
+	t.mtx.Unlock()
+
+	if !ok || q.userID != userID {
+		return false
+	}
+	q.cancel(ErrQueryCancelled)
+	return true
+}
+
+// ActiveQueries implements frontendv2pb.FrontendForFrontendServer.
+func (t *Tracker) ActiveQueries(ctx context.Context, _ *frontendv2pb.ActiveQueriesRequest) (*frontendv2pb.ActiveQueriesResponse, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := t.List(tenant.JoinTenantIDs(tenantIDs))
+	resp := &frontendv2pb.ActiveQueriesResponse{Queries: make([]frontendv2pb.ActiveQuery, 0, len(infos))}
+	for _, info := range infos {
+		resp.Queries = append(resp.Queries, frontendv2pb.ActiveQuery{
+			Id:                    info.ID,
+			UserID:                info.UserID,
+			Method:                info.Method,
+			Path:                  info.Path,
+			Params:                info.Params,
+			StartTimestampMs:      info.StartTime.UnixMilli(),
+			OutstandingSubqueries: uint32(info.OutstandingSubqueries),
+			Queriers:              info.Queriers,
+		})
+	}
+	return resp, nil
+}
+
+// CancelActiveQuery implements frontendv2pb.FrontendForFrontendServer.
+func (t *Tracker) CancelActiveQuery(ctx context.Context, req *frontendv2pb.CancelActiveQueryRequest) (*frontendv2pb.CancelActiveQueryResponse, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &frontendv2pb.CancelActiveQueryResponse{
+		Cancelled: t.Cancel(tenant.JoinTenantIDs(tenantIDs), req.Id),
+	}, nil
+}
+
+// InfosFromProto returns the descriptions of the active queries returned by another query-frontend.
+func InfosFromProto(resp *frontendv2pb.ActiveQueriesResponse) []Info {
+	infos := make([]Info, 0, len(resp.Queries))
+	for _, q := range resp.Queries {
+		if q.Params == nil {
+			q.Params = map[string]string{}
+		}
+		if q.Queriers == nil {
+			q.Queriers = []string{}
+		}
+		infos = append(infos, Info{
+			ID:                    q.Id,
+			UserID:                q.UserID,
+			Method:                q.Method,
+			Path:                  q.Path,
+			Params:                q.Params,
+			StartTime:             time.UnixMilli(q.StartTimestampMs).UTC(),
+			OutstandingSubqueries: int(q.OutstandingSubqueries),
+			Queriers:              q.Queriers,
+		})
+	}
+	return infos
+}
+
+func sortInfos(infos []Info) {
+	sort.Slice(infos, func(i, j int) bool {
+		if !infos[i].StartTime.Equal(infos[j].StartTime) {
+			return infos[i].StartTime.Before(infos[j].StartTime)
+		}
+		return infos[i].ID < infos[j].ID
+	})
+}
+
+// formatParams returns the request params, the values of the same param being joined by commas.
+func formatParams(params url.Values) map[string]string {
+	formatted := make(map[string]string, len(params))
+	for k, v := range params {
+		formatted[k] = strings.Join(v, ",")
+	}
+	return formatted
+}
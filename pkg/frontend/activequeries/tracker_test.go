@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package activequeries
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/frontend/v2/frontendv2pb"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+
+	// The requests without tenant aren't tracked.
+	q, ctx := tracker.Track(context.Background(), http.MethodGet, "/api/v1/query", nil)
+	require.Nil(t, q)
+	require.Equal(t, context.Background(), ctx)
+	tracker.Done(q)
+
+	q1, ctx1 := tracker.Track(user.InjectOrgID(context.Background(), "user-1"), http.MethodGet, "/api/v1/query", url.Values{"query": []string{"up"}})
+	q2, ctx2 := tracker.Track(user.InjectOrgID(context.Background(), "user-1"), http.MethodPost, "/api/v1/query_range", nil)
+	q3, _ := tracker.Track(user.InjectOrgID(context.Background(), "user-2"), http.MethodGet, "/api/v1/labels", nil)
+	defer tracker.Done(q3)
+	require.Same(t, q1, FromContext(ctx1))
+
+	q1.SubqueryEnqueued(1)
+	q1.SubqueryEnqueued(2)
+	q1.SubqueryEnqueued(3)
+	q1.SubqueryEnqueued(4)
+	q1.SubqueryExecuted("querier-2")
+	q1.SubqueryDone(3)
+	q1.SubqueryExecuted("querier-1")
+	q1.SubqueryDone(4)
+	// The results without querier ID, like the ones of the queriers not reporting it yet, are ignored.
+	q1.SubqueryExecuted("")
+
+	infos := tracker.List("user-1")
+	require.Len(t, infos, 2)
+	assert.Equal(t, q1.id, infos[0].ID)
+	assert.Equal(t, "user-1", infos[0].UserID)
+	assert.Equal(t, http.MethodGet, infos[0].Method)
+	assert.Equal(t, "/api/v1/query", infos[0].Path)
+	assert.Equal(t, map[string]string{"query": "up"}, infos[0].Params)
+	assert.Equal(t, 2, infos[0].OutstandingSubqueries)
+	assert.Equal(t, []string{"querier-1", "querier-2"}, infos[0].Queriers)
+	assert.Equal(t, q2.id, infos[1].ID)
+	assert.Equal(t, 0, infos[1].OutstandingSubqueries)
+	assert.Empty(t, infos[1].Queriers)
+
+	// The queries can only be cancelled by their tenant.
+	require.False(t, tracker.Cancel("user-2", q1.id))
+	require.False(t, tracker.Cancel("user-1", "unknown"))
+	require.NoError(t, ctx1.Err())
+
+	require.True(t, tracker.Cancel("user-1", q1.id))
+	require.ErrorIs(t, context.Cause(ctx1), ErrQueryCancelled)
+	require.NoError(t, ctx2.Err())
+
+	// The queries are not tracked anymore once done.
+	tracker.Done(q1)
+	tracker.Done(q2)
+	require.Empty(t, tracker.List("user-1"))
+	require.Len(t, tracker.List("user-2"), 1)
+	require.ErrorIs(t, context.Cause(ctx2), context.Canceled)
+}
+
+func TestTracker_FrontendForFrontendServer(t *testing.T) {
+	tracker := NewTracker()
+	q, ctx := tracker.Track(user.InjectOrgID(context.Background(), "user-1"), http.MethodGet, "/api/v1/query", url.Values{"query": []string{"up"}})
+	defer tracker.Done(q)
+	q.SubqueryEnqueued(1)
+	q.SubqueryEnqueued(2)
+	q.SubqueryExecuted("querier-1")
+	q.SubqueryDone(2)
+
+	_, err := tracker.ActiveQueries(context.Background(), &frontendv2pb.ActiveQueriesRequest{})
+	require.Error(t, err)
+
+	resp, err := tracker.ActiveQueries(user.InjectOrgID(context.Background(), "user-1"), &frontendv2pb.ActiveQueriesRequest{})
+	require.NoError(t, err)
+	infos := InfosFromProto(resp)
+	require.Len(t, infos, 1)
+	assert.Equal(t, q.id, infos[0].ID)
+	assert.Equal(t, map[string]string{"query": "up"}, infos[0].Params)
+	assert.Equal(t, q.startTime.UnixMilli(), infos[0].StartTime.UnixMilli())
+	assert.Equal(t, 1, infos[0].OutstandingSubqueries)
+	assert.Equal(t, []string{"querier-1"}, infos[0].Queriers)
+
+	cancelResp, err := tracker.CancelActiveQuery(user.InjectOrgID(context.Background(), "user-2"), &frontendv2pb.CancelActiveQueryRequest{Id: q.id})
+	require.NoError(t, err)
+	require.False(t, cancelResp.Cancelled)
+	require.NoError(t, ctx.Err())
+
+	cancelResp, err = tracker.CancelActiveQuery(user.InjectOrgID(context.Background(), "user-1"), &frontendv2pb.CancelActiveQueryRequest{Id: q.id})
+	require.NoError(t, err)
+	require.True(t, cancelResp.Cancelled)
+	require.ErrorIs(t, context.Cause(ctx), ErrQueryCancelled)
+}
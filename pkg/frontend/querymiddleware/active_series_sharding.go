@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/prometheus/model/labels"
+
+	apierror "github.com/grafana/mimir/pkg/api/error"
+	"github.com/grafana/mimir/pkg/cardinality"
+	"github.com/grafana/mimir/pkg/storage/sharding"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+// shardActiveSeriesRoundTripper splits an active series request into a request per query shard,
+// each one selecting a shard of the series with the __query_shard__ label matcher, and merges
+// the responses. The series of different shards are disjoint, so no deduplication is needed.
+type shardActiveSeriesRoundTripper struct {
+	limits Limits
+	next   http.RoundTripper
+	logger log.Logger
+}
+
+func newShardActiveSeriesRoundTripper(limits Limits, next http.RoundTripper, logger log.Logger) http.RoundTripper {
+	return &shardActiveSeriesRoundTripper{
+		limits: limits,
+		next:   next,
+		logger: logger,
+	}
+}
+
+var errActiveSeriesShardFailed = errors.New("active series request failed for a shard")
+
+type activeSeriesResponse struct {
+	Data []labels.Labels `json:"data"`
+}
+
+func (s *shardActiveSeriesRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	spanLog, ctx := spanlogger.NewWithLogger(r.Context(), s.logger, "shardActiveSeries.RoundTrip")
+	defer spanLog.Finish()
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, apierror.New(apierror.TypeBadData, err.Error())
+	}
+
+	shardCount := validation.SmallestPositiveIntPerTenant(tenantIDs, s.limits.QueryShardingTotalShards)
+	if shardCount <= 1 {
+		return s.next.RoundTrip(r)
+	}
+
+	reqValues, err := util.ParseRequestFormWithoutConsumingBody(r)
+	if err != nil {
+		return nil, apierror.New(apierror.TypeBadData, err.Error())
+	}
+
+	activeSeriesReq, err := cardinality.DecodeActiveSeriesRequestFromValues(reqValues)
+	if err != nil {
+		// Let the downstream respond to invalid requests.
+		level.Debug(spanLog).Log("msg", "skipped active series request sharding because failed to parse the request", "err", err)
+		return s.next.RoundTrip(r)
+	}
+	if shard, _, err := sharding.RemoveShardFromMatchers(activeSeriesReq.Matchers); err != nil || shard != nil {
+		// The request is already sharded, or has an invalid shard selector.
+		return s.next.RoundTrip(r)
+	}
+
+	var (
+		parallelism = validation.SmallestPositiveIntPerTenant(tenantIDs, s.limits.MaxQueryParallelism)
+		responses   = make([]*activeSeriesResponse, shardCount)
+
+		// failed is the first unsuccessful response received for a shard, which is returned as is.
+		failedMx sync.Mutex
+		failed   *http.Response
+	)
+
+	err = concurrency.ForEachJob(ctx, shardCount, parallelism, func(ctx context.Context, idx int) error {
+		shardReq := shardedActiveSeriesRequest(ctx, r, reqValues, activeSeriesReq.Matchers, sharding.ShardSelector{
+			ShardIndex: uint64(idx),
+			ShardCount: uint64(shardCount),
+		})
+
+		res, err := s.next.RoundTrip(shardReq)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = res.Body.Close() }()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		if res.StatusCode != http.StatusOK {
+			failedMx.Lock()
+			if failed == nil {
+				failed = res
+				failed.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			failedMx.Unlock()
+			return errActiveSeriesShardFailed
+		}
+
+		responses[idx] = &activeSeriesResponse{}
+		if err := json.Unmarshal(body, responses[idx]); err != nil {
+			return apierror.New(apierror.TypeInternal, fmt.Sprintf("failed to decode active series response: %s", err))
+		}
+		return nil
+	})
+	if failed != nil {
+		return failed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &activeSeriesResponse{Data: []labels.Labels{}}
+	for _, res := range responses {
+		merged.Data = append(merged.Data, res.Data...)
+	}
+	sort.Slice(merged.Data, func(i, j int) bool {
+		return labels.Compare(merged.Data[i], merged.Data[j]) < 0
+	})
+
+	body, err := json.Marshal(merged)
+	if err != nil {
+		return nil, apierror.New(apierror.TypeInternal, err.Error())
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}, nil
+}
+
+// shardedActiveSeriesRequest returns a GET request selecting the series of the input shard.
+func shardedActiveSeriesRequest(ctx context.Context, r *http.Request, values url.Values, matchers []*labels.Matcher, shard sharding.ShardSelector) *http.Request {
+	selector := make([]string, 0, len(matchers)+1)
+	for _, m := range matchers {
+		selector = append(selector, m.String())
+	}
+	selector = append(selector, shard.Matcher().String())
+
+	query := make(url.Values, len(values))
+	for name, vals := range values {
+		query[name] = vals
+	}
+	query.Set("selector", "{"+strings.Join(selector, ",")+"}")
+
+	shardReq := r.Clone(ctx)
+	shardReq.Method = http.MethodGet
+	shardReq.Body = http.NoBody
+	shardReq.ContentLength = 0
+	shardReq.Header.Del("Content-Type")
+	shardReq.Header.Del("Content-Length")
+	shardReq.Form, shardReq.PostForm = nil, nil
+	shardReq.URL.RawQuery = query.Encode()
+	shardReq.RequestURI = ""
+	return shardReq
+}
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/storage/sharding"
+)
+
+func TestShardActiveSeriesRoundTripper(t *testing.T) {
+	series := []labels.Labels{
+		labels.FromStrings(labels.MetricName, "metric", "pod", "a"),
+		labels.FromStrings(labels.MetricName, "metric", "pod", "b"),
+		labels.FromStrings(labels.MetricName, "metric", "pod", "c"),
+		labels.FromStrings(labels.MetricName, "metric", "pod", "d"),
+		labels.FromStrings(labels.MetricName, "metric", "pod", "e"),
+	}
+
+	// The downstream returns the series of the shard requested with the __query_shard__ matcher.
+	var (
+		receivedMx sync.Mutex
+		received   []string
+	)
+	downstream := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, http.MethodGet, req.Method)
+
+		selector := req.URL.Query().Get("selector")
+		receivedMx.Lock()
+		received = append(received, selector)
+		receivedMx.Unlock()
+
+		matchers, err := parser.ParseMetricSelector(selector)
+		require.NoError(t, err)
+		shard, matchers, err := sharding.RemoveShardFromMatchers(matchers)
+		require.NoError(t, err)
+
+		var data []string
+		for _, s := range series {
+			if shard != nil && labels.StableHash(s)%shard.ShardCount != shard.ShardIndex {
+				continue
+			}
+			data = append(data, fmt.Sprintf(`{"__name__":%q,"pod":%q}`, s.Get(labels.MetricName), s.Get("pod")))
+		}
+		require.Len(t, matchers, 1)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"data":[` + strings.Join(data, ",") + `]}`)),
+		}, nil
+	})
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		t.Run(method, func(t *testing.T) {
+			received = nil
+			rt := newShardActiveSeriesRoundTripper(mockLimits{totalShards: 3, maxQueryParallelism: 2}, downstream, log.NewNopLogger())
+
+			params := url.Values{"selector": []string{"metric"}}
+			var req *http.Request
+			var err error
+			if method == http.MethodGet {
+				req, err = http.NewRequest(method, "/api/v1/cardinality/active_series?"+params.Encode(), nil)
+			} else {
+				req, err = http.NewRequest(method, "/api/v1/cardinality/active_series", strings.NewReader(params.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			require.NoError(t, err)
+			req = req.WithContext(user.InjectOrgID(context.Background(), "test"))
+
+			res, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"data":[
+				{"__name__":"metric","pod":"a"},
+				{"__name__":"metric","pod":"b"},
+				{"__name__":"metric","pod":"c"},
+				{"__name__":"metric","pod":"d"},
+				{"__name__":"metric","pod":"e"}
+			]}`, string(body))
+
+			assert.ElementsMatch(t, []string{
+				`{__name__="metric",__query_shard__="1_of_3"}`,
+				`{__name__="metric",__query_shard__="2_of_3"}`,
+				`{__name__="metric",__query_shard__="3_of_3"}`,
+			}, received)
+		})
+	}
+}
+
+func TestShardActiveSeriesRoundTripper_ShouldReturnTheFirstFailedResponse(t *testing.T) {
+	downstream := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.Query().Get("selector"), "2_of_2") {
+			return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader("cardinality analysis is disabled"))}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"data":[]}`))}, nil
+	})
+
+	rt := newShardActiveSeriesRoundTripper(mockLimits{totalShards: 2}, downstream, log.NewNopLogger())
+	req, err := http.NewRequestWithContext(user.InjectOrgID(context.Background(), "test"), http.MethodGet, "/api/v1/cardinality/active_series?selector=metric", nil)
+	require.NoError(t, err)
+
+	res, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "cardinality analysis is disabled", string(body))
+}
+
+func TestShardActiveSeriesRoundTripper_ShouldNotShard(t *testing.T) {
+	tests := map[string]struct {
+		totalShards int
+		query       string
+	}{
+		"sharding disabled for the tenant": {
+			totalShards: 0,
+			query:       "selector=metric",
+		},
+		"missing selector": {
+			totalShards: 4,
+			query:       "",
+		},
+		"already sharded request": {
+			totalShards: 4,
+			query:       url.Values{"selector": []string{`{__name__="metric",__query_shard__="1_of_2"}`}}.Encode(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			downstream := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				assert.Equal(t, tc.query, req.URL.RawQuery)
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"data":[]}`))}, nil
+			})
+
+			rt := newShardActiveSeriesRoundTripper(mockLimits{totalShards: tc.totalShards}, downstream, log.NewNopLogger())
+			req, err := http.NewRequestWithContext(user.InjectOrgID(context.Background(), "test"), http.MethodGet, "/api/v1/cardinality/active_series?"+tc.query, nil)
+			require.NoError(t, err)
+
+			_, err = rt.RoundTrip(req)
+			require.NoError(t, err)
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
@@ -13,13 +13,19 @@ import (
 	"github.com/prometheus/prometheus/promql/parser"
 )
 
+// summableAggregates are the aggregations which can be computed by re-aggregating the results
+// of the same (or an equivalent) aggregation over each shard. The aggregations which need all
+// the values of a group at once, like quantile() or stddev(), are not summable.
 var summableAggregates = map[parser.ItemType]struct{}{
-	parser.GROUP: {},
-	parser.SUM:   {},
-	parser.MIN:   {},
-	parser.MAX:   {},
-	parser.COUNT: {},
-	parser.AVG:   {},
+	parser.GROUP:        {},
+	parser.SUM:          {},
+	parser.MIN:          {},
+	parser.MAX:          {},
+	parser.COUNT:        {},
+	parser.AVG:          {},
+	parser.TOPK:         {},
+	parser.BOTTOMK:      {},
+	parser.COUNT_VALUES: {},
 }
 
 // NonParallelFuncs is the list of functions that shouldn't be parallelized.
@@ -61,6 +67,11 @@ func CanParallelize(expr parser.Expr, logger log.Logger) bool {
 			return false
 		}
 
+		// The k of topk() and bottomk() must be the same for each shard.
+		if (e.Op == parser.TOPK || e.Op == parser.BOTTOMK) && !isConstantScalar(e.Param) {
+			return false
+		}
+
 		// Ensure there are no nested aggregations
 		nestedAggrs, err := anyNode(e.Expr, isAggregateExpr)
 
@@ -125,6 +125,26 @@ func TestCanParallel_String(t *testing.T) {
 			`sum by (foo) (histogram_quantile(0.9, rate(http_request_duration_seconds_bucket[10m])))`,
 			false,
 		},
+		{
+			`topk by (foo) (10, rate(bar1[1m]))`,
+			true,
+		},
+		{
+			`bottomk(10, bar1)`,
+			true,
+		},
+		{
+			`topk(scalar(bar2), bar1)`,
+			false,
+		},
+		{
+			`count_values("value", bar1)`,
+			true,
+		},
+		{
+			`quantile(0.9, bar1)`,
+			false,
+		},
 		{
 			`sum by (foo) (
 			  quantile_over_time(0.9, http_request_duration_seconds_bucket[10m])
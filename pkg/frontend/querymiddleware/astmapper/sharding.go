@@ -8,6 +8,7 @@ package astmapper
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/go-kit/log"
 	"github.com/pkg/errors"
@@ -217,6 +218,7 @@ func (summer *shardSummer) shardAndSquashFuncCall(expr *parser.Call) (mapped par
 		To find the most outer matrix/vector selector, we need to traverse the AST by using each function arguments.
 	*/
 
+	original := expr.String()
 	children := make([]parser.Expr, 0, summer.shards)
 
 	// Create sub-query for each shard.
@@ -253,6 +255,7 @@ func (summer *shardSummer) shardAndSquashFuncCall(expr *parser.Call) (mapped par
 
 	// Update stats.
 	summer.stats.AddShardedQueries(summer.shards)
+	summer.stats.AddShardedExpression(original)
 	squashed, err := summer.squash(children...)
 	if err != nil {
 		return nil, true, err
@@ -262,42 +265,39 @@ func (summer *shardSummer) shardAndSquashFuncCall(expr *parser.Call) (mapped par
 
 // shardAggregate attempts to shard the given aggregation expression.
 func (summer *shardSummer) shardAggregate(expr *parser.AggregateExpr) (mapped parser.Expr, finished bool, err error) {
+	original := expr.String()
+
 	switch expr.Op {
 	case parser.GROUP:
 		mapped, err = summer.shardGroup(expr)
-		if err != nil {
-			return nil, false, err
-		}
-		return mapped, true, nil
 	case parser.SUM:
 		mapped, err = summer.shardSum(expr)
-		if err != nil {
-			return nil, false, err
-		}
-		return mapped, true, nil
 	case parser.COUNT:
 		mapped, err = summer.shardCount(expr)
-		if err != nil {
-			return nil, false, err
-		}
-		return mapped, true, nil
 	case parser.MAX, parser.MIN:
 		mapped, err = summer.shardMinMax(expr)
-		if err != nil {
-			return nil, false, err
-		}
-		return mapped, true, nil
 	case parser.AVG:
 		mapped, err = summer.shardAvg(expr)
-		if err != nil {
-			return nil, false, err
+	case parser.TOPK, parser.BOTTOMK:
+		mapped, err = summer.shardTopkBottomk(expr)
+	case parser.COUNT_VALUES:
+		// The per-shard counts are summed by the value label, so it must be known upfront.
+		valueLabel, ok := expr.Param.(*parser.StringLiteral)
+		if !ok {
+			return expr, false, nil
 		}
-		return mapped, true, nil
+		mapped, err = summer.shardCountValues(expr, valueLabel.Val)
+	default:
+		// If the aggregation operation is not shardable, we have to return the input
+		// expr as is.
+		return expr, false, nil
+	}
+	if err != nil {
+		return nil, false, err
 	}
 
-	// If the aggregation operation is not shardable, we have to return the input
-	// expr as is.
-	return expr, false, nil
+	summer.stats.AddShardedExpression(original)
+	return mapped, true, nil
 }
 
 // shardGroup attempts to shard the given GROUP aggregation expression.
@@ -439,6 +439,77 @@ func (summer *shardSummer) shardAvg(expr *parser.AggregateExpr) (result parser.E
 	}, nil
 }
 
+// shardTopkBottomk attempts to shard the given TOPK/BOTTOMK aggregation expression.
+func (summer *shardSummer) shardTopkBottomk(expr *parser.AggregateExpr) (result parser.Expr, err error) {
+	// We expect the given aggregation is either a TOPK or BOTTOMK.
+	if expr.Op != parser.TOPK && expr.Op != parser.BOTTOMK {
+		return nil, errors.Errorf("expected TOPK or BOTTOMK aggregation while got %s", expr.Op.String())
+	}
+
+	/*
+		parallelizing a topk using by(foo) is representable as
+		topk by(foo) (10,
+		  topk by(foo) (10, rate(bar1{__query_shard__="0_of_2",baz="blip"}[1m])) or
+		  topk by(foo) (10, rate(bar1{__query_shard__="1_of_2",baz="blip"}[1m]))
+		)
+
+		The top k series of each group are within the top k series of the same group of their shard,
+		and the series returned by topk() keep their labels, so re-applying the aggregation over the
+		per-shard results selects the same series.
+	*/
+
+	// Create a TOPK/BOTTOMK sub-query for each shard and squash it into a CONCAT expression.
+	sharded, err := summer.shardAndSquashAggregateExpr(expr, expr.Op)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parser.AggregateExpr{
+		Op:       expr.Op,
+		Expr:     sharded,
+		Param:    expr.Param,
+		Grouping: expr.Grouping,
+		Without:  expr.Without,
+	}, nil
+}
+
+// shardCountValues attempts to shard the given COUNT_VALUES aggregation expression, whose
+// values are stored in the valueLabel label.
+func (summer *shardSummer) shardCountValues(expr *parser.AggregateExpr, valueLabel string) (result parser.Expr, err error) {
+	/*
+		parallelizing a count_values using by(foo) is representable as
+		sum by(foo, value) (
+		  count_values by(foo) ("value", bar1{__query_shard__="0_of_2",baz="blip"}) or
+		  count_values by(foo) ("value", bar1{__query_shard__="1_of_2",baz="blip"})
+		)
+
+		parallelizing a count_values using without(foo) is representable as
+		sum without(foo) (
+		  count_values without(foo) ("value", bar1{__query_shard__="0_of_2",baz="blip"}) or
+		  count_values without(foo) ("value", bar1{__query_shard__="1_of_2",baz="blip"})
+		)
+	*/
+	// Create a COUNT_VALUES sub-query for each shard and squash it into a CONCAT expression.
+	sharded, err := summer.shardAndSquashAggregateExpr(expr, parser.COUNT_VALUES)
+	if err != nil {
+		return nil, err
+	}
+
+	// The per-shard counts of each value are summed. When grouping by labels, the label holding
+	// the value must be kept too, while it's kept already when grouping without labels.
+	grouping := expr.Grouping
+	if !expr.Without && !slices.Contains(grouping, valueLabel) {
+		grouping = append(slices.Clone(grouping), valueLabel)
+	}
+
+	return &parser.AggregateExpr{
+		Op:       parser.SUM,
+		Expr:     sharded,
+		Grouping: grouping,
+		Without:  expr.Without,
+	}, nil
+}
+
 // shardAndSquashAggregateExpr returns a squashed CONCAT expression including N embedded
 // queries, where N is the number of shards and each sub-query queries a different shard
 // with the given "op" aggregation operation.
@@ -454,13 +525,17 @@ func (summer *shardSummer) shardAndSquashAggregateExpr(expr *parser.AggregateExp
 
 		// Create the child expression, which runs the given aggregation operation
 		// on a single shard. We need to preserve the grouping as it was
-		// in the original one.
-		children = append(children, &parser.AggregateExpr{
+		// in the original one, and the parameter of the aggregations which have one.
+		child := &parser.AggregateExpr{
 			Op:       op,
 			Expr:     sharded,
 			Grouping: expr.Grouping,
 			Without:  expr.Without,
-		})
+		}
+		if op.IsAggregatorWithParam() {
+			child.Param = expr.Param
+		}
+		children = append(children, child)
 	}
 
 	// Update stats.
@@ -476,10 +551,12 @@ func (summer *shardSummer) shardBinOp(expr *parser.BinaryExpr) (mapped parser.Ex
 		parser.GTE,
 		parser.LSS,
 		parser.LTE:
+		original := expr.String()
 		mapped, err = summer.shardAndSquashBinOp(expr)
 		if err != nil {
 			return nil, false, err
 		}
+		summer.stats.AddShardedExpression(original)
 		return mapped, true, nil
 	default:
 		return expr, false, nil
@@ -518,6 +518,63 @@ func TestShardSummer(t *testing.T) {
 			out:                    `count(group without() (` + concatShards(3, `group without() ({namespace="foo",__query_shard__="x_of_y"})`) + `))`,
 			expectedShardedQueries: 3,
 		},
+		{
+			in:                     `topk(10, rate(metric[1m]))`,
+			out:                    `topk(10, ` + concatShards(3, `topk(10, rate(metric{__query_shard__="x_of_y"}[1m]))`) + `)`,
+			expectedShardedQueries: 3,
+		},
+		{
+			in:                     `bottomk by (a) (5, metric)`,
+			out:                    `bottomk by (a) (5, ` + concatShards(3, `bottomk by (a) (5, metric{__query_shard__="x_of_y"})`) + `)`,
+			expectedShardedQueries: 3,
+		},
+		{
+			in:                     `sum(topk without (a) (5, metric))`,
+			out:                    `sum(topk without (a) (5, ` + concatShards(3, `topk without (a) (5, metric{__query_shard__="x_of_y"})`) + `))`,
+			expectedShardedQueries: 3,
+		},
+		{
+			// The k must be the same for each shard.
+			in:                     `topk(scalar(foo), metric)`,
+			out:                    concat(`topk(scalar(foo), metric)`),
+			expectedShardedQueries: 0,
+		},
+		{
+			in:                     `count_values("value", metric)`,
+			out:                    `sum by (value) (` + concatShards(3, `count_values("value", metric{__query_shard__="x_of_y"})`) + `)`,
+			expectedShardedQueries: 3,
+		},
+		{
+			in:                     `count_values by (a) ("value", metric)`,
+			out:                    `sum by (a, value) (` + concatShards(3, `count_values by (a) ("value", metric{__query_shard__="x_of_y"})`) + `)`,
+			expectedShardedQueries: 3,
+		},
+		{
+			in:                     `count_values by (value) ("value", metric)`,
+			out:                    `sum by (value) (` + concatShards(3, `count_values by (value) ("value", metric{__query_shard__="x_of_y"})`) + `)`,
+			expectedShardedQueries: 3,
+		},
+		{
+			in:                     `count_values without (a) ("value", metric)`,
+			out:                    `sum without (a) (` + concatShards(3, `count_values without (a) ("value", metric{__query_shard__="x_of_y"})`) + `)`,
+			expectedShardedQueries: 3,
+		},
+		{
+			// The value label must be a string literal.
+			in:                     `count_values(("value"), metric)`,
+			out:                    concat(`count_values(("value"), metric)`),
+			expectedShardedQueries: 0,
+		},
+		{
+			in:                     `histogram_quantile(0.9, sum by (le) (rate(metric_bucket[1m])))`,
+			out:                    `histogram_quantile(0.9, sum by (le) (` + concatShards(3, `sum by (le) (rate(metric_bucket{__query_shard__="x_of_y"}[1m]))`) + `))`,
+			expectedShardedQueries: 3,
+		},
+		{
+			in:                     `histogram_quantile(0.9, sum(rate(native_histogram[1m])))`,
+			out:                    `histogram_quantile(0.9, sum(` + concatShards(3, `sum(rate(native_histogram{__query_shard__="x_of_y"}[1m]))`) + `))`,
+			expectedShardedQueries: 3,
+		},
 	} {
 		tt := tt
 
@@ -538,6 +595,50 @@ func TestShardSummer(t *testing.T) {
 	}
 }
 
+func TestShardSummer_ShardedExpressions(t *testing.T) {
+	for _, tt := range []struct {
+		in       string
+		expected []string
+	}{
+		{
+			in:       `quantile(0.9, metric)`,
+			expected: nil,
+		},
+		{
+			in:       `sum by (a) (rate(metric[1m]))`,
+			expected: []string{`sum by (a) (rate(metric[1m]))`},
+		},
+		{
+			in:       `topk(10, avg by (a) (metric))`,
+			expected: []string{`avg by (a) (metric)`},
+		},
+		{
+			in:       `histogram_quantile(0.9, sum by (le) (rate(metric_bucket[1m]))) > 1 or count_values("value", metric)`,
+			expected: []string{`sum by (le) (rate(metric_bucket[1m]))`, `count_values("value", metric)`},
+		},
+		{
+			in:       `rate(metric[1m]) > 1`,
+			expected: []string{`rate(metric[1m]) > 1`},
+		},
+		{
+			in:       `max_over_time(rate(metric[1m])[5m:1m])`,
+			expected: []string{`max_over_time(rate(metric[1m])[5m:1m])`},
+		},
+	} {
+		t.Run(tt.in, func(t *testing.T) {
+			stats := NewMapperStats()
+			mapper, err := NewSharding(context.Background(), 3, log.NewNopLogger(), stats)
+			require.NoError(t, err)
+			expr, err := parser.ParseExpr(tt.in)
+			require.NoError(t, err)
+
+			_, err = mapper.Map(expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stats.GetShardedExpressions())
+		})
+	}
+}
+
 func concatShards(shards int, queryTemplate string) string {
 	queries := make([]string, shards)
 	for shard := range queries {
@@ -3,7 +3,8 @@
 package astmapper
 
 type MapperStats struct {
-	shardedQueries int
+	shardedQueries     int
+	shardedExpressions []string
 }
 
 func NewMapperStats() *MapperStats {
@@ -19,3 +20,14 @@ func (s *MapperStats) AddShardedQueries(num int) {
 func (s *MapperStats) GetShardedQueries() int {
 	return s.shardedQueries
 }
+
+// AddShardedExpression records an expression of the original query which has been sharded.
+func (s *MapperStats) AddShardedExpression(expr string) {
+	s.shardedExpressions = append(s.shardedExpressions, expr)
+}
+
+// GetShardedExpressions returns the expressions of the original query which have been sharded,
+// in the order they've been mapped.
+func (s *MapperStats) GetShardedExpressions() []string {
+	return s.shardedExpressions
+}
@@ -52,6 +52,12 @@ func (qb *queryBlockerMiddleware) Do(ctx context.Context, req Request) (Response
 	for _, tenant := range tenants {
 		isBlocked := qb.isBlocked(tenant, req)
 		if isBlocked {
+			// The plan of a blocked query is returned, so that it shows the query is blocked.
+			if plan := queryPlanFromContext(ctx); plan != nil {
+				plan.setBlocked()
+				return newEmptyPrometheusResponse(), nil
+			}
+
 			qb.blockedQueriesCounter.WithLabelValues(tenant, "blocked").Inc()
 			return nil, apierror.New(apierror.TypeBadData, validation.NewQueryBlockedError().Error())
 		}
@@ -90,6 +90,11 @@ func (c *cardinalityEstimation) Do(ctx context.Context, request Request) (Respon
 		return nil, err
 	}
 
+	// The cardinality of the queries being explained is unknown, since they're not executed.
+	if queryPlanFromContext(ctx) != nil {
+		return res, nil
+	}
+
 	statistics := stats.FromContext(ctx)
 	actualCardinality := statistics.GetFetchedSeriesCount()
 	spanLog.LogFields(otlog.Uint64("actual cardinality", actualCardinality))
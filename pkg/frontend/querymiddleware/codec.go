@@ -33,6 +33,7 @@ import (
 
 	apierror "github.com/grafana/mimir/pkg/api/error"
 	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/spanlogger"
 )
@@ -433,7 +434,13 @@ func (c prometheusCodec) EncodeResponse(ctx context.Context, req *http.Request,
 	}
 
 	start := time.Now()
-	b, err := formatter.EncodeResponse(a)
+	var b []byte
+	var err error
+	if queryStats := queryStatsToEncode(ctx, req, a); queryStats != nil && formatter == jsonFormatterInstance {
+		b, err = jsonFormatterInstance.encodeResponseWithStats(a, queryStats)
+	} else {
+		b, err = formatter.EncodeResponse(a)
+	}
 	if err != nil {
 		return nil, apierror.Newf(apierror.TypeInternal, "error encoding response: %v", err)
 	}
@@ -453,6 +460,20 @@ func (c prometheusCodec) EncodeResponse(ctx context.Context, req *http.Request,
 	return &resp, nil
 }
 
+// queryStatsToEncode returns the query stats to add to the response, if the stats are requested with the stats param,
+// like the Prometheus API, and tracked. The stats are only added to the successful JSON responses.
+func queryStatsToEncode(ctx context.Context, req *http.Request, res *PrometheusResponse) *QueryStats {
+	if req.FormValue("stats") == "" || res.Status != statusSuccess || res.Data == nil {
+		return nil
+	}
+	s := stats.FromContext(ctx)
+	if s == nil {
+		return nil
+	}
+	queryStats := NewQueryStats(s)
+	return &queryStats
+}
+
 func (prometheusCodec) negotiateContentType(acceptHeader string) (string, formatter) {
 	if acceptHeader == "" {
 		return jsonMimeType, jsonFormatterInstance
@@ -15,6 +15,21 @@ func (j jsonFormatter) EncodeResponse(resp *PrometheusResponse) ([]byte, error)
 	return json.Marshal(resp)
 }
 
+// encodeResponseWithStats encodes the response like EncodeResponse, adding the query stats to the data of the response.
+func (j jsonFormatter) encodeResponseWithStats(resp *PrometheusResponse, queryStats *QueryStats) ([]byte, error) {
+	return json.Marshal(struct {
+		Status    string                  `json:"status"`
+		Data      prometheusDataWithStats `json:"data"`
+		ErrorType string                  `json:"errorType,omitempty"`
+		Error     string                  `json:"error,omitempty"`
+	}{
+		Status:    resp.Status,
+		Data:      prometheusDataWithStats{data: resp.Data, stats: queryStats},
+		ErrorType: resp.ErrorType,
+		Error:     resp.Error,
+	})
+}
+
 func (j jsonFormatter) DecodeResponse(buf []byte) (*PrometheusResponse, error) {
 	var resp PrometheusResponse
 
@@ -30,6 +30,7 @@ import (
 
 	apierror "github.com/grafana/mimir/pkg/api/error"
 	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/querier/stats"
 )
 
 var (
@@ -205,6 +206,118 @@ func TestPrometheusCodec_EncodeResponse_ContentNegotiation(t *testing.T) {
 	}
 }
 
+func TestPrometheusCodec_EncodeResponse_QueryStats(t *testing.T) {
+	const expectedStats = `{"wall_time_seconds":0,"fetched_series_count":3,"fetched_chunk_bytes":1024,"fetched_chunks_count":0,"fetched_index_bytes":0,"sharded_queries":16,"split_queries":0,"estimated_series_count":0}`
+
+	vectorResponse := &PrometheusResponse{
+		Status: statusSuccess,
+		Data: &PrometheusData{
+			ResultType: model.ValVector.String(),
+			Result: []SampleStream{
+				{Labels: []mimirpb.LabelAdapter{{Name: "foo", Value: "bar"}}, Samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 1}}},
+			},
+		},
+	}
+	matrixResponse := &PrometheusResponse{
+		Status: statusSuccess,
+		Data: &PrometheusData{
+			ResultType: model.ValMatrix.String(),
+			Result: []SampleStream{
+				{Labels: []mimirpb.LabelAdapter{{Name: "foo", Value: "bar"}}, Samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 1}}},
+			},
+		},
+	}
+	errorResponse := &PrometheusResponse{
+		Status:    statusError,
+		ErrorType: string(v1.ErrExec),
+		Error:     "something went wrong",
+	}
+
+	for name, tc := range map[string]struct {
+		path          string
+		acceptHeader  string
+		statsEnabled  bool
+		response      *PrometheusResponse
+		expectedStats bool
+	}{
+		"stats requested for an instant query": {
+			path:          "/api/v1/query?query=up&stats=all",
+			statsEnabled:  true,
+			response:      vectorResponse,
+			expectedStats: true,
+		},
+		"stats requested for a range query": {
+			path:          "/api/v1/query_range?query=up&start=0&end=60&step=15&stats=all",
+			statsEnabled:  true,
+			response:      matrixResponse,
+			expectedStats: true,
+		},
+		"stats not requested": {
+			path:         "/api/v1/query?query=up",
+			statsEnabled: true,
+			response:     vectorResponse,
+		},
+		"stats requested but not tracked": {
+			path:     "/api/v1/query?query=up&stats=all",
+			response: vectorResponse,
+		},
+		"stats requested for a failed query": {
+			path:         "/api/v1/query?query=up&stats=all",
+			statsEnabled: true,
+			response:     errorResponse,
+		},
+		"stats requested for a protobuf response": {
+			path:         "/api/v1/query?query=up&stats=all",
+			acceptHeader: mimirpb.QueryResponseMimeType,
+			statsEnabled: true,
+			response:     vectorResponse,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.statsEnabled {
+				var queryStats *stats.Stats
+				queryStats, ctx = stats.ContextWithEmptyStats(ctx)
+				queryStats.AddFetchedSeries(3)
+				queryStats.AddFetchedChunkBytes(1024)
+				queryStats.AddShardedQueries(16)
+			}
+
+			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", tc.acceptHeader)
+
+			codec := newTestPrometheusCodec()
+			encoded, err := codec.EncodeResponse(ctx, req, tc.response)
+			require.NoError(t, err)
+			body, err := io.ReadAll(encoded.Body)
+			require.NoError(t, err)
+
+			// The response is decoded the same way, whether the stats are added or not.
+			decoded, err := codec.DecodeResponse(ctx, &http.Response{StatusCode: http.StatusOK, Header: encoded.Header, Body: io.NopCloser(bytes.NewReader(body))}, nil, log.NewNopLogger())
+			if tc.response.Status == statusSuccess {
+				require.NoError(t, err)
+				require.Equal(t, tc.response.Data, decoded.(*PrometheusResponse).Data)
+			}
+
+			if tc.acceptHeader != "" {
+				return
+			}
+			var res struct {
+				Data struct {
+					Stats jsoniter.RawMessage `json:"stats"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(body, &res))
+			if !tc.expectedStats {
+				require.Empty(t, res.Data.Stats)
+				return
+			}
+			require.JSONEq(t, expectedStats, string(res.Data.Stats))
+		})
+	}
+}
+
 type prometheusAPIResponse struct {
 	Status    string       `json:"status"`
 	Data      interface{}  `json:"data,omitempty"`
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+
+	apierror "github.com/grafana/mimir/pkg/api/error"
+)
+
+type deleteRequestsContextKey int
+
+const deleteRequestsGenerationKey deleteRequestsContextKey = 0
+
+// DeleteRequestsLoader returns the generation of the pending delete requests of a tenant, which changes
+// whenever a delete request is created, canceled or processed, and whether the queriers have loaded it.
+type DeleteRequestsLoader interface {
+	ResultsCacheGeneration(ctx context.Context, userID string) (generation string, propagated bool, err error)
+}
+
+// newDeleteRequestsTripperware returns a tripperware adding the generation of the tenants' delete requests
+// to the results cache keys, so that the results cached before a delete request is created, canceled or
+// processed aren't used anymore. The results cache is bypassed while the queriers may still be running
+// the queries with the previous delete requests.
+func newDeleteRequestsTripperware(loader DeleteRequestsLoader, logger log.Logger) Tripperware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(r *http.Request) (*http.Response, error) {
+			ctx := r.Context()
+			tenantIDs, err := tenant.TenantIDs(ctx)
+			if err != nil {
+				return nil, apierror.New(apierror.TypeBadData, err.Error())
+			}
+
+			bypassCache := false
+			generations := make([]string, 0, len(tenantIDs))
+			for _, userID := range tenantIDs {
+				generation, propagated, err := loader.ResultsCacheGeneration(ctx, userID)
+				if err != nil {
+					level.Warn(logger).Log("msg", "failed to load the delete requests, bypassing the results cache", "user", userID, "err", err)
+					bypassCache = true
+					break
+				}
+				bypassCache = bypassCache || !propagated
+				generations = append(generations, generation)
+			}
+
+			if bypassCache {
+				r = r.Clone(ctx)
+				r.Header.Add(cacheControlHeader, noStoreValue)
+			} else if generation := strings.Join(generations, ","); strings.Trim(generation, ",") != "" {
+				r = r.WithContext(context.WithValue(ctx, deleteRequestsGenerationKey, generation))
+			}
+
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+// withDeleteRequestsGeneration returns the cache key with the generation of the delete requests, if any.
+func withDeleteRequestsGeneration(ctx context.Context, key string) string {
+	if generation, ok := ctx.Value(deleteRequestsGenerationKey).(string); ok {
+		return key + ":" + generation
+	}
+	return key
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deleteRequestsLoaderMock map[string]struct {
+	generation string
+	propagated bool
+	err        error
+}
+
+func (m deleteRequestsLoaderMock) ResultsCacheGeneration(_ context.Context, userID string) (string, bool, error) {
+	res, ok := m[userID]
+	if !ok {
+		return "", true, nil
+	}
+	return res.generation, res.propagated, res.err
+}
+
+func TestDeleteRequestsTripperware(t *testing.T) {
+	// Enable the multi-tenant queries, and restore the default resolver once done.
+	tenant.WithDefaultResolver(tenant.NewMultiResolver())
+	t.Cleanup(func() {
+		tenant.WithDefaultResolver(tenant.NewSingleResolver())
+	})
+
+	loader := deleteRequestsLoaderMock{
+		"with-deletes":        {generation: "1", propagated: true},
+		"with-other-deletes":  {generation: "2", propagated: true},
+		"with-recent-deletes": {generation: "3", propagated: false},
+		"failing":             {err: errors.New("failed")},
+	}
+
+	tests := map[string]struct {
+		orgID               string
+		expectedKey         string
+		expectedCacheBypass bool
+	}{
+		"tenant without delete requests": {
+			orgID:       "without-deletes",
+			expectedKey: "key",
+		},
+		"tenant with delete requests": {
+			orgID:       "with-deletes",
+			expectedKey: "key:1",
+		},
+		"tenants with delete requests": {
+			orgID:       "with-deletes|without-deletes|with-other-deletes",
+			expectedKey: "key:1,2,",
+		},
+		"tenant with delete requests not loaded by the queriers yet": {
+			orgID:               "with-recent-deletes",
+			expectedKey:         "key",
+			expectedCacheBypass: true,
+		},
+		"tenant whose delete requests can't be loaded": {
+			orgID:               "without-deletes|failing",
+			expectedKey:         "key",
+			expectedCacheBypass: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				key         string
+				cacheBypass bool
+			)
+			downstream := RoundTripFunc(func(r *http.Request) (*http.Response, error) {
+				key = withDeleteRequestsGeneration(r.Context(), "key")
+				cacheBypass = decodeCacheDisabledOption(r)
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			})
+
+			ctx := user.InjectOrgID(context.Background(), tc.orgID)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/api/v1/query_range", http.NoBody)
+			require.NoError(t, err)
+
+			_, err = newDeleteRequestsTripperware(loader, log.NewNopLogger())(downstream).RoundTrip(req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedKey, key)
+			assert.Equal(t, tc.expectedCacheBypass, cacheBypass)
+			assert.False(t, decodeCacheDisabledOption(req), "the original request must not be modified")
+		})
+	}
+}
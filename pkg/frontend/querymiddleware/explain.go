@@ -0,0 +1,406 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/timestamp"
+
+	apierror "github.com/grafana/mimir/pkg/api/error"
+	"github.com/grafana/mimir/pkg/frontend/querymiddleware/astmapper"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	queryExplainPathSuffix = "/api/v1/query_explain"
+
+	// explainParam is the request parameter to explain a query sent to the instant or range query API.
+	explainParam = "explain"
+)
+
+type queryPlanContextKey int
+
+const queryPlanCtxKey = queryPlanContextKey(0)
+
+// queryPlan describes how the query-frontend executes a query: the middlewares record in it what they
+// would do with the query, and the queries are not sent to the queriers.
+type queryPlan struct {
+	mtx sync.Mutex
+
+	Query   string            `json:"query"`
+	Start   time.Time         `json:"start"`
+	End     time.Time         `json:"end"`
+	Step    string            `json:"step,omitempty"`
+	Limits  map[string]string `json:"limits"`
+	Blocked bool              `json:"blocked"`
+
+	// Notes describe the changes the query-frontend made to the query, for example to its time range.
+	Notes []string `json:"notes,omitempty"`
+
+	// Splits are the queries the query has been split into by time interval, and what the results cache holds for them.
+	Splits []explainedSplit `json:"splits,omitempty"`
+
+	// InstantSplits are the instant queries which have been split by time interval.
+	InstantSplits []explainedInstantSplit `json:"instant_splits,omitempty"`
+
+	// Sharding are the queries which have been considered for sharding.
+	Sharding []explainedSharding `json:"sharding,omitempty"`
+
+	// DownstreamQueries are the queries which would be sent to the queriers.
+	DownstreamQueries []explainedQuery `json:"downstream_queries"`
+}
+
+type explainedQuery struct {
+	Query string    `json:"query"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Step  string    `json:"step,omitempty"`
+}
+
+type explainedTimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type explainedSplit struct {
+	explainedQuery
+
+	CacheKey          string `json:"cache_key,omitempty"`
+	NotCachableReason string `json:"not_cachable_reason,omitempty"`
+
+	// CachedExtents are the time ranges served from the results cache, and DownstreamTimeRanges the ones which
+	// are not and have to be executed.
+	CachedExtents        []explainedTimeRange `json:"cached_extents,omitempty"`
+	DownstreamTimeRanges []explainedTimeRange `json:"downstream_time_ranges,omitempty"`
+}
+
+type explainedInstantSplit struct {
+	explainedQuery
+
+	RewrittenQuery string `json:"rewritten_query"`
+	SplitQueries   int    `json:"split_queries"`
+}
+
+type explainedSharding struct {
+	explainedQuery
+
+	// EstimatedSeriesCount is the number of series estimated by the cardinality-based query sharding, if any.
+	EstimatedSeriesCount *uint64 `json:"estimated_series_count,omitempty"`
+	TotalShards          int     `json:"total_shards"`
+
+	ShardedQueries     int      `json:"sharded_queries"`
+	ShardedExpressions []string `json:"sharded_expressions,omitempty"`
+	RewrittenQuery     string   `json:"rewritten_query,omitempty"`
+}
+
+// contextWithQueryPlan returns a context which makes the middlewares record what they do in the returned plan,
+// rather than executing the query.
+func contextWithQueryPlan(ctx context.Context) (*queryPlan, context.Context) {
+	plan := &queryPlan{DownstreamQueries: []explainedQuery{}}
+	return plan, context.WithValue(ctx, queryPlanCtxKey, plan)
+}
+
+// queryPlanFromContext returns the plan of the query being explained, or nil if the query is executed.
+func queryPlanFromContext(ctx context.Context) *queryPlan {
+	plan, _ := ctx.Value(queryPlanCtxKey).(*queryPlan)
+	return plan
+}
+
+func newExplainedQuery(r Request) explainedQuery {
+	q := explainedQuery{
+		Query: r.GetQuery(),
+		Start: timestamp.Time(r.GetStart()),
+		End:   timestamp.Time(r.GetEnd()),
+	}
+	if step := r.GetStep(); step > 0 {
+		q.Step = (time.Duration(step) * time.Millisecond).String()
+	}
+	return q
+}
+
+func newExplainedTimeRange(start, end int64) explainedTimeRange {
+	return explainedTimeRange{Start: timestamp.Time(start), End: timestamp.Time(end)}
+}
+
+// setRequest records the query as received by the middlewares.
+func (p *queryPlan) setRequest(r Request) {
+	if p == nil {
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	q := newExplainedQuery(r)
+	p.Query, p.Start, p.End, p.Step = q.Query, q.Start, q.End, q.Step
+}
+
+func (p *queryPlan) addNote(format string, args ...any) {
+	if p == nil {
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.Notes = append(p.Notes, fmt.Sprintf(format, args...))
+}
+
+func (p *queryPlan) setBlocked() {
+	if p == nil {
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.Blocked = true
+}
+
+// addSplits records the queries split by time interval once the results cache has been looked up.
+func (p *queryPlan) addSplits(splitReqs splitRequests) {
+	if p == nil {
+		return
+	}
+
+	splits := make([]explainedSplit, 0, len(splitReqs))
+	for _, splitReq := range splitReqs {
+		split := explainedSplit{
+			explainedQuery:    newExplainedQuery(splitReq.orig),
+			CacheKey:          splitReq.cacheKey,
+			NotCachableReason: splitReq.notCachableReason,
+		}
+		for _, extent := range splitReq.cachedExtents {
+			split.CachedExtents = append(split.CachedExtents, newExplainedTimeRange(extent.Start, extent.End))
+		}
+		for _, downstreamReq := range splitReq.downstreamRequests {
+			split.DownstreamTimeRanges = append(split.DownstreamTimeRanges, newExplainedTimeRange(downstreamReq.GetStart(), downstreamReq.GetEnd()))
+		}
+		splits = append(splits, split)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.Splits = append(p.Splits, splits...)
+}
+
+func (p *queryPlan) addInstantSplit(r Request, rewrittenQuery string, splitQueries int) {
+	if p == nil {
+		return
+	}
+
+	split := explainedInstantSplit{
+		explainedQuery: newExplainedQuery(r),
+		RewrittenQuery: rewrittenQuery,
+		SplitQueries:   splitQueries,
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.InstantSplits = append(p.InstantSplits, split)
+}
+
+// addSharding records the sharding of a query. The mapper stats are nil if the query has not been rewritten.
+func (p *queryPlan) addSharding(r Request, totalShards int, stats *astmapper.MapperStats, rewrittenQuery string) {
+	if p == nil {
+		return
+	}
+
+	sharding := explainedSharding{
+		explainedQuery: newExplainedQuery(r),
+		TotalShards:    totalShards,
+		RewrittenQuery: rewrittenQuery,
+	}
+	if v, ok := r.GetHints().GetCardinalityEstimate().(*Hints_EstimatedSeriesCount); ok {
+		sharding.EstimatedSeriesCount = &v.EstimatedSeriesCount
+	}
+	if stats != nil {
+		sharding.ShardedQueries = stats.GetShardedQueries()
+		sharding.ShardedExpressions = stats.GetShardedExpressions()
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.Sharding = append(p.Sharding, sharding)
+}
+
+func (p *queryPlan) addDownstreamQuery(r Request) {
+	if p == nil {
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.DownstreamQueries = append(p.DownstreamQueries, newExplainedQuery(r))
+}
+
+// sort sorts the queries recorded concurrently, so that explaining the same query twice returns the same plan.
+func (p *queryPlan) sort() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	less := func(a, b explainedQuery) bool {
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		if !a.End.Equal(b.End) {
+			return a.End.Before(b.End)
+		}
+		return a.Query < b.Query
+	}
+
+	sort.SliceStable(p.InstantSplits, func(i, j int) bool {
+		return less(p.InstantSplits[i].explainedQuery, p.InstantSplits[j].explainedQuery)
+	})
+	sort.SliceStable(p.Sharding, func(i, j int) bool {
+		return less(p.Sharding[i].explainedQuery, p.Sharding[j].explainedQuery)
+	})
+	sort.SliceStable(p.DownstreamQueries, func(i, j int) bool {
+		return less(p.DownstreamQueries[i], p.DownstreamQueries[j])
+	})
+}
+
+// queryExplainRoundTripper explains the queries sent to the query explain API, or to the instant and range query
+// APIs with the explain parameter, by running them through the query middlewares without executing them.
+type queryExplainRoundTripper struct {
+	cfg    Config
+	limits Limits
+
+	// The round trippers of the range and instant queries.
+	queryRange http.RoundTripper
+	instant    http.RoundTripper
+}
+
+func newQueryExplainRoundTripper(cfg Config, limits Limits, queryRange, instant http.RoundTripper) http.RoundTripper {
+	return &queryExplainRoundTripper{
+		cfg:        cfg,
+		limits:     limits,
+		queryRange: queryRange,
+		instant:    instant,
+	}
+}
+
+func (rt *queryExplainRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	tenantIDs, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		return nil, apierror.New(apierror.TypeBadData, err.Error())
+	}
+
+	params, err := parseRequestParams(r)
+	if err != nil {
+		return nil, apierror.New(apierror.TypeBadData, err.Error())
+	}
+
+	// The query explain API explains range queries if they have a step, and instant queries otherwise.
+	next, pathSuffix := rt.instant, instantQueryPathSuffix
+	if isRangeQuery(r.URL.Path) || (isQueryExplain(r.URL.Path) && params.Has("step")) {
+		next, pathSuffix = rt.queryRange, queryRangePathSuffix
+	}
+
+	prefix := r.URL.Path
+	for _, suffix := range []string{queryExplainPathSuffix, queryRangePathSuffix, instantQueryPathSuffix} {
+		prefix = strings.TrimSuffix(prefix, suffix)
+	}
+
+	plan, ctx := contextWithQueryPlan(r.Context())
+	req := r.Clone(ctx)
+	req.URL.Path = prefix + pathSuffix
+
+	res, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	_ = res.Body.Close()
+
+	plan.sort()
+	plan.Limits = rt.explainLimits(tenantIDs)
+
+	body, err := json.Marshal(struct {
+		Status string     `json:"status"`
+		Data   *queryPlan `json:"data"`
+	}{
+		Status: statusSuccess,
+		Data:   plan,
+	})
+	if err != nil {
+		return nil, apierror.New(apierror.TypeInternal, err.Error())
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}, nil
+}
+
+// explainLimits returns the limits applying to the queries of the tenants, as the middlewares compute them.
+func (rt *queryExplainRoundTripper) explainLimits(tenantIDs []string) map[string]string {
+	duration := func(d time.Duration) string {
+		return model.Duration(d).String()
+	}
+
+	return map[string]string{
+		"split_queries_by_interval":              duration(rt.cfg.SplitQueriesByInterval),
+		"align_queries_with_step":                strconv.FormatBool(rt.cfg.AlignQueriesWithStep),
+		"cache_results":                          strconv.FormatBool(rt.cfg.CacheResults),
+		"parallelize_shardable_queries":          strconv.FormatBool(rt.cfg.ShardedQueries),
+		"query_sharding_target_series_per_shard": strconv.FormatUint(rt.cfg.TargetSeriesPerShard, 10),
+
+		"max_query_lookback":                             duration(validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, rt.limits.MaxQueryLookback)),
+		"compactor_blocks_retention_period":              duration(validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, rt.limits.CompactorBlocksRetentionPeriod)),
+		"creation_grace_period":                          duration(validation.LargestPositiveNonZeroDurationPerTenant(tenantIDs, rt.limits.CreationGracePeriod)),
+		"max_total_query_length":                         duration(validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, rt.limits.MaxTotalQueryLength)),
+		"max_query_expression_size_bytes":                strconv.Itoa(validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, rt.limits.MaxQueryExpressionSizeBytes)),
+		"max_query_parallelism":                          strconv.Itoa(validation.SmallestPositiveIntPerTenant(tenantIDs, rt.limits.MaxQueryParallelism)),
+		"max_cache_freshness":                            duration(validation.MaxDurationPerTenant(tenantIDs, rt.limits.MaxCacheFreshness)),
+		"cache_unaligned_requests":                       strconv.FormatBool(validation.AllTrueBooleansPerTenant(tenantIDs, rt.limits.ResultsCacheForUnalignedQueryEnabled)),
+		"results_cache_ttl":                              duration(validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, rt.limits.ResultsCacheTTL)),
+		"results_cache_ttl_for_out_of_order_time_window": duration(validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, rt.limits.ResultsCacheTTLForOutOfOrderTimeWindow)),
+		"out_of_order_time_window":                       duration(validation.MaxDurationPerTenant(tenantIDs, rt.limits.OutOfOrderTimeWindow)),
+		"query_sharding_total_shards":                    strconv.Itoa(validation.SmallestPositiveIntPerTenant(tenantIDs, rt.limits.QueryShardingTotalShards)),
+		"query_sharding_max_sharded_queries":             strconv.Itoa(validation.SmallestPositiveIntPerTenant(tenantIDs, rt.limits.QueryShardingMaxShardedQueries)),
+		"query_sharding_max_regexp_size_bytes":           strconv.Itoa(validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, rt.limits.QueryShardingMaxRegexpSizeBytes)),
+		"split_instant_queries_by_interval":              duration(validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, rt.limits.SplitInstantQueriesByInterval)),
+	}
+}
+
+func isQueryExplain(path string) bool {
+	return strings.HasSuffix(path, queryExplainPathSuffix)
+}
+
+// isExplainRequested returns whether the instant or range query has the explain parameter set to true.
+func isExplainRequested(r *http.Request) bool {
+	if !isRangeQuery(r.URL.Path) && !isInstantQuery(r.URL.Path) {
+		return false
+	}
+	params, err := parseRequestParams(r)
+	if err != nil {
+		return false
+	}
+	explain, _ := strconv.ParseBool(params.Get(explainParam))
+	return explain
+}
+
+// parseRequestParams returns the params of the request, without consuming its body. The form is used as is
+// if it has already been parsed, since the body has been consumed then.
+func parseRequestParams(r *http.Request) (url.Values, error) {
+	if r.Form != nil {
+		return r.Form, nil
+	}
+	return util.ParseRequestFormWithoutConsumingBody(r)
+}
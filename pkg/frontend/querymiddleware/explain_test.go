@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/cache"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestQueryExplainRoundTripper(t *testing.T) {
+	var (
+		start = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		end   = start.Add(36 * time.Hour)
+	)
+
+	for name, tc := range map[string]struct {
+		path   string
+		params url.Values
+		limits mockLimits
+
+		expectedQuery             string
+		expectedStart             time.Time
+		expectedEnd               time.Time
+		expectedStep              string
+		expectedBlocked           bool
+		expectedSplits            int
+		expectedSharding          []int
+		expectedShardedExprs      [][]string
+		expectedDownstreamQueries []explainedQuery
+	}{
+		"range query sent to the query explain API": {
+			path: "/prometheus/api/v1/query_explain",
+			params: url.Values{
+				"query": []string{`sum(rate(metric[1m]))`},
+				"start": []string{formatTime(start)},
+				"end":   []string{formatTime(end)},
+				"step":  []string{"60"},
+			},
+			limits:           mockLimits{totalShards: 2},
+			expectedQuery:    `sum(rate(metric[1m]))`,
+			expectedStart:    start,
+			expectedEnd:      end,
+			expectedStep:     "1m0s",
+			expectedSplits:   2,
+			expectedSharding: []int{2, 2},
+			expectedDownstreamQueries: []explainedQuery{
+				{Query: `sum(rate(metric{__query_shard__="1_of_2"}[1m]))`, Start: start, End: start.Add(24*time.Hour - time.Minute), Step: "1m0s"},
+				{Query: `sum(rate(metric{__query_shard__="2_of_2"}[1m]))`, Start: start, End: start.Add(24*time.Hour - time.Minute), Step: "1m0s"},
+				{Query: `sum(rate(metric{__query_shard__="1_of_2"}[1m]))`, Start: start.Add(24 * time.Hour), End: end, Step: "1m0s"},
+				{Query: `sum(rate(metric{__query_shard__="2_of_2"}[1m]))`, Start: start.Add(24 * time.Hour), End: end, Step: "1m0s"},
+			},
+		},
+		"instant query sent to the query explain API": {
+			path: "/prometheus/api/v1/query_explain",
+			params: url.Values{
+				"query": []string{`count(metric)`},
+				"time":  []string{formatTime(end)},
+			},
+			limits:           mockLimits{totalShards: 2},
+			expectedQuery:    `count(metric)`,
+			expectedStart:    end,
+			expectedEnd:      end,
+			expectedSharding: []int{2},
+			expectedDownstreamQueries: []explainedQuery{
+				{Query: `count(metric{__query_shard__="1_of_2"})`, Start: end, End: end},
+				{Query: `count(metric{__query_shard__="2_of_2"})`, Start: end, End: end},
+			},
+		},
+		"instant query with a sharded topk sent to the query explain API": {
+			path: "/prometheus/api/v1/query_explain",
+			params: url.Values{
+				"query": []string{`topk(5, rate(metric[1m]))`},
+				"time":  []string{formatTime(end)},
+			},
+			limits:               mockLimits{totalShards: 2},
+			expectedQuery:        `topk(5, rate(metric[1m]))`,
+			expectedStart:        end,
+			expectedEnd:          end,
+			expectedSharding:     []int{2},
+			expectedShardedExprs: [][]string{{`topk(5, rate(metric[1m]))`}},
+			expectedDownstreamQueries: []explainedQuery{
+				{Query: `topk(5, rate(metric{__query_shard__="1_of_2"}[1m]))`, Start: end, End: end},
+				{Query: `topk(5, rate(metric{__query_shard__="2_of_2"}[1m]))`, Start: end, End: end},
+			},
+		},
+		"instant query with a quantile sent to the query explain API": {
+			path: "/prometheus/api/v1/query_explain",
+			params: url.Values{
+				"query": []string{`quantile(0.9, rate(metric[1m]))`},
+				"time":  []string{formatTime(end)},
+			},
+			limits:               mockLimits{totalShards: 2},
+			expectedQuery:        `quantile(0.9, rate(metric[1m]))`,
+			expectedStart:        end,
+			expectedEnd:          end,
+			expectedSharding:     []int{2},
+			expectedShardedExprs: [][]string{nil},
+			expectedDownstreamQueries: []explainedQuery{
+				{Query: `quantile(0.9, rate(metric[1m]))`, Start: end, End: end},
+			},
+		},
+		"instant query with the explain parameter": {
+			path: "/prometheus/api/v1/query",
+			params: url.Values{
+				"query":   []string{`metric`},
+				"time":    []string{formatTime(end)},
+				"explain": []string{"true"},
+			},
+			limits:           mockLimits{totalShards: 2},
+			expectedQuery:    `metric`,
+			expectedStart:    end,
+			expectedEnd:      end,
+			expectedSharding: []int{2},
+			expectedDownstreamQueries: []explainedQuery{
+				{Query: `metric`, Start: end, End: end},
+			},
+		},
+		"blocked range query with the explain parameter": {
+			path: "/prometheus/api/v1/query_range",
+			params: url.Values{
+				"query":   []string{`metric`},
+				"start":   []string{formatTime(start)},
+				"end":     []string{formatTime(end)},
+				"step":    []string{"60"},
+				"explain": []string{"true"},
+			},
+			limits:                    mockLimits{blockedQueries: []*validation.BlockedQuery{{Pattern: "metric"}}},
+			expectedQuery:             `metric`,
+			expectedStart:             start,
+			expectedEnd:               end,
+			expectedStep:              "1m0s",
+			expectedBlocked:           true,
+			expectedDownstreamQueries: []explainedQuery{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tw, err := NewTripperware(
+				Config{
+					SplitQueriesByInterval: 24 * time.Hour,
+					ShardedQueries:         true,
+				},
+				log.NewNopLogger(),
+				tc.limits,
+				newTestPrometheusCodec(),
+				nil,
+				promql.EngineOpts{
+					Logger:     log.NewNopLogger(),
+					MaxSamples: 1000,
+					Timeout:    time.Minute,
+				},
+				nil,
+				nil,
+			)
+			require.NoError(t, err)
+
+			downstream := RoundTripFunc(func(r *http.Request) (*http.Response, error) {
+				require.FailNow(t, "the query being explained must not be executed", r.URL.String())
+				return nil, nil
+			})
+
+			ctx := user.InjectOrgID(context.Background(), "user-1")
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, tc.path+"?"+tc.params.Encode(), http.NoBody)
+			require.NoError(t, err)
+			require.NoError(t, user.InjectOrgIDIntoHTTPRequest(ctx, req))
+
+			res, err := tw(downstream).RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			var plan struct {
+				Status string    `json:"status"`
+				Data   queryPlan `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(body, &plan))
+			assert.Equal(t, statusSuccess, plan.Status)
+			assert.Equal(t, tc.expectedQuery, plan.Data.Query)
+			assert.True(t, tc.expectedStart.Equal(plan.Data.Start))
+			assert.True(t, tc.expectedEnd.Equal(plan.Data.End))
+			assert.Equal(t, tc.expectedStep, plan.Data.Step)
+			assert.Equal(t, tc.expectedBlocked, plan.Data.Blocked)
+			assert.Len(t, plan.Data.Splits, tc.expectedSplits)
+			assert.Equal(t, "1d", plan.Data.Limits["split_queries_by_interval"])
+			assert.Equal(t, "true", plan.Data.Limits["parallelize_shardable_queries"])
+
+			var shards []int
+			for _, sharding := range plan.Data.Sharding {
+				shards = append(shards, sharding.TotalShards)
+			}
+			assert.Equal(t, tc.expectedSharding, shards)
+
+			if tc.expectedShardedExprs != nil {
+				var exprs [][]string
+				for _, sharding := range plan.Data.Sharding {
+					exprs = append(exprs, sharding.ShardedExpressions)
+				}
+				assert.Equal(t, tc.expectedShardedExprs, exprs)
+			}
+
+			require.Len(t, plan.Data.DownstreamQueries, len(tc.expectedDownstreamQueries))
+			for i, expected := range tc.expectedDownstreamQueries {
+				actual := plan.Data.DownstreamQueries[i]
+				assert.Equal(t, expected.Query, actual.Query)
+				assert.True(t, expected.Start.Equal(actual.Start), "expected start %s, got %s", expected.Start, actual.Start)
+				assert.True(t, expected.End.Equal(actual.End), "expected end %s, got %s", expected.End, actual.End)
+				assert.Equal(t, expected.Step, actual.Step)
+			}
+		})
+	}
+}
+
+func TestSplitAndCacheMiddleware_Explain(t *testing.T) {
+	cacheBackend := cache.NewInstrumentedMockCache()
+	mw := newSplitAndCacheMiddleware(
+		true,
+		true,
+		24*time.Hour,
+		mockLimits{resultsCacheTTL: time.Hour},
+		newTestPrometheusCodec(),
+		cacheBackend,
+		ConstSplitter(day),
+		PrometheusResponseExtractor{},
+		resultsCacheAlwaysEnabled,
+		log.NewNopLogger(),
+		prometheus.NewPedanticRegistry(),
+	)
+
+	var (
+		start = time.Now().Add(-72 * time.Hour).Truncate(24 * time.Hour)
+		end   = start.Add(24*time.Hour + 12*time.Hour)
+		step  = time.Minute.Milliseconds()
+	)
+
+	req := &PrometheusRangeQueryRequest{
+		Path:  "/api/v1/query_range",
+		Start: start.UnixMilli(),
+		End:   end.UnixMilli(),
+		Step:  step,
+		Query: `sum(metric)`,
+	}
+
+	downstreamRequests := atomic.NewInt64(0)
+	handler := mw.Wrap(HandlerFunc(func(context.Context, Request) (Response, error) {
+		downstreamRequests.Inc()
+		return newEmptyPrometheusResponse(), nil
+	}))
+
+	// Explaining the query before it's cached shows the whole time range would be executed.
+	ctx := user.InjectOrgID(context.Background(), "user-1")
+	plan, explainCtx := contextWithQueryPlan(ctx)
+	_, err := handler.Do(explainCtx, req)
+	require.NoError(t, err)
+	require.Len(t, plan.Splits, 2)
+	for _, split := range plan.Splits {
+		assert.NotEmpty(t, split.CacheKey)
+		assert.Empty(t, split.CachedExtents)
+		assert.Equal(t, []explainedTimeRange{{Start: split.Start, End: split.End}}, split.DownstreamTimeRanges)
+	}
+
+	// The empty responses of the explained queries are not cached.
+	assert.Equal(t, 0, cacheBackend.CountStoreCalls())
+
+	// Execute the query to cache its results, then explain it again.
+	_, err = handler.Do(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, 2, cacheBackend.CountStoreCalls())
+
+	plan, explainCtx = contextWithQueryPlan(ctx)
+	downstreamRequests.Store(0)
+	_, err = handler.Do(explainCtx, req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), downstreamRequests.Load())
+	require.Len(t, plan.Splits, 2)
+	for _, split := range plan.Splits {
+		assert.Equal(t, []explainedTimeRange{{Start: split.Start, End: split.End}}, split.CachedExtents)
+		assert.Empty(t, split.DownstreamTimeRanges)
+	}
+}
+
+func TestIsExplainRequested(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"/api/v1/query?query=up&explain=true":            true,
+		"/api/v1/query_range?query=up&explain=1":         true,
+		"/api/v1/query?query=up&explain=false":           false,
+		"/api/v1/query?query=up":                         false,
+		"/api/v1/labels?explain=true":                    false,
+		"/prometheus/api/v1/query_range?explain=invalid": false,
+	} {
+		t.Run(path, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, path, http.NoBody)
+			require.NoError(t, err)
+			assert.Equal(t, expected, isExplainRequested(req))
+		})
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
@@ -102,6 +102,7 @@ func (c *genericQueryCache) RoundTrip(req *http.Request) (*http.Response, error)
 
 	// Lookup the cache.
 	c.metrics.cacheRequests.Inc()
+	queryReq.cacheKey = withDeleteRequestsGeneration(ctx, queryReq.cacheKey)
 	cacheKey, hashedCacheKey := generateGenericQueryRequestCacheKey(tenantIDs, queryReq)
 	res := c.fetchCachedResponse(ctx, cacheKey, hashedCacheKey)
 	if res != nil {
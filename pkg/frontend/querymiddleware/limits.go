@@ -141,6 +141,7 @@ func (l limitsMiddleware) Do(ctx context.Context, r Request) (Response, error) {
 				"redEnd", util.FormatTimeMillis(r.GetEnd()),
 				"maxQueryLookback", maxQueryLookback,
 				"blocksRetentionPeriod", blocksRetentionPeriod)
+			queryPlanFromContext(ctx).addNote("the query is not executed because its time range is before the max query lookback (%s) or the blocks retention period (%s)", maxQueryLookback, blocksRetentionPeriod)
 
 			return newEmptyPrometheusResponse(), nil
 		}
@@ -153,6 +154,8 @@ func (l limitsMiddleware) Do(ctx context.Context, r Request) (Response, error) {
 				"updated", util.FormatTimeMillis(minStartTime),
 				"maxQueryLookback", maxQueryLookback,
 				"blocksRetentionPeriod", blocksRetentionPeriod)
+			queryPlanFromContext(ctx).addNote("the start time of the query has been changed from %s to %s because of the max query lookback (%s) or the blocks retention period (%s)",
+				util.FormatTimeMillis(r.GetStart()), util.FormatTimeMillis(minStartTime), maxQueryLookback, blocksRetentionPeriod)
 
 			r = r.WithStartEnd(minStartTime, r.GetEnd())
 		}
@@ -168,6 +171,8 @@ func (l limitsMiddleware) Do(ctx context.Context, r Request) (Response, error) {
 			"original", util.FormatTimeMillis(r.GetEnd()),
 			"updated", util.FormatTimeMillis(maxEndTime),
 			"creationGracePeriod", creationGracePeriod)
+		queryPlanFromContext(ctx).addNote("the end time of the query has been changed from %s to %s because of the creation grace period (%s)",
+			util.FormatTimeMillis(r.GetEnd()), util.FormatTimeMillis(maxEndTime), creationGracePeriod)
 
 		r = r.WithStartEnd(r.GetStart(), maxEndTime)
 	}
@@ -224,6 +229,8 @@ func (rt limitedParallelismRoundTripper) RoundTrip(r *http.Request) (*http.Respo
 	if span := opentracing.SpanFromContext(ctx); span != nil {
 		request.LogToSpan(span)
 	}
+	plan := queryPlanFromContext(ctx)
+	plan.setRequest(request)
 	tenantIDs, err := tenant.TenantIDs(ctx)
 	if err != nil {
 		return nil, apierror.New(apierror.TypeBadData, err.Error())
@@ -243,6 +250,12 @@ func (rt limitedParallelismRoundTripper) RoundTrip(r *http.Request) (*http.Respo
 			}
 			defer sem.Release(1)
 
+			// The queries being explained are not executed.
+			if plan != nil {
+				plan.addDownstreamQuery(r)
+				return newEmptyPrometheusResponse(), nil
+			}
+
 			return rt.downstream.Do(ctx, r)
 		})).Do(ctx, request)
 	if err != nil {
@@ -21,6 +21,7 @@ import (
 	"github.com/prometheus/prometheus/model/timestamp"
 
 	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/querier/stats"
 )
 
 var (
@@ -208,6 +209,12 @@ func (d *PrometheusData) UnmarshalJSON(b []byte) error {
 }
 
 func (d *PrometheusData) MarshalJSON() ([]byte, error) {
+	return d.marshalJSON(nil)
+}
+
+// marshalJSON encodes the data with the query stats, if any, in its stats field, where the Prometheus API
+// returns the stats of a query when they're requested.
+func (d *PrometheusData) marshalJSON(queryStats *QueryStats) ([]byte, error) {
 	if d == nil {
 		return []byte("null"), nil
 	}
@@ -217,38 +224,87 @@ func (d *PrometheusData) MarshalJSON() ([]byte, error) {
 		return json.Marshal(struct {
 			Type   model.ValueType     `json:"resultType"`
 			Result stringSampleStreams `json:"result"`
+			Stats  *QueryStats         `json:"stats,omitempty"`
 		}{
 			Type:   model.ValString,
 			Result: d.Result,
+			Stats:  queryStats,
 		})
 
 	case model.ValScalar.String():
 		return json.Marshal(struct {
 			Type   model.ValueType     `json:"resultType"`
 			Result scalarSampleStreams `json:"result"`
+			Stats  *QueryStats         `json:"stats,omitempty"`
 		}{
 			Type:   model.ValScalar,
 			Result: d.Result,
+			Stats:  queryStats,
 		})
 
 	case model.ValVector.String():
 		return json.Marshal(struct {
 			Type   model.ValueType      `json:"resultType"`
 			Result []vectorSampleStream `json:"result"`
+			Stats  *QueryStats          `json:"stats,omitempty"`
 		}{
 			Type:   model.ValVector,
 			Result: asVectorSampleStreams(d.Result),
+			Stats:  queryStats,
 		})
 
 	case model.ValMatrix.String():
-		type plain *PrometheusData
-		return json.Marshal(plain(d))
+		return json.Marshal(struct {
+			Type   model.ValueType `json:"resultType"`
+			Result []SampleStream  `json:"result"`
+			Stats  *QueryStats     `json:"stats,omitempty"`
+		}{
+			Type:   model.ValMatrix,
+			Result: d.Result,
+			Stats:  queryStats,
+		})
 
 	default:
 		return nil, fmt.Errorf("can't marshal prometheus result type %q", d.ResultType)
 	}
 }
 
+// prometheusDataWithStats encodes the data of a response with the query stats.
+type prometheusDataWithStats struct {
+	data  *PrometheusData
+	stats *QueryStats
+}
+
+func (d prometheusDataWithStats) MarshalJSON() ([]byte, error) {
+	return d.data.marshalJSON(d.stats)
+}
+
+// QueryStats are the statistics of a query, as returned to the clients.
+type QueryStats struct {
+	WallTimeSeconds      float64 `json:"wall_time_seconds"`
+	FetchedSeriesCount   uint64  `json:"fetched_series_count"`
+	FetchedChunkBytes    uint64  `json:"fetched_chunk_bytes"`
+	FetchedChunksCount   uint64  `json:"fetched_chunks_count"`
+	FetchedIndexBytes    uint64  `json:"fetched_index_bytes"`
+	ShardedQueries       uint32  `json:"sharded_queries"`
+	SplitQueries         uint32  `json:"split_queries"`
+	EstimatedSeriesCount uint64  `json:"estimated_series_count"`
+}
+
+// NewQueryStats returns the statistics of a query returned to the clients.
+func NewQueryStats(s *stats.Stats) QueryStats {
+	return QueryStats{
+		WallTimeSeconds:      s.LoadWallTime().Seconds(),
+		FetchedSeriesCount:   s.LoadFetchedSeries(),
+		FetchedChunkBytes:    s.LoadFetchedChunkBytes(),
+		FetchedChunksCount:   s.LoadFetchedChunks(),
+		FetchedIndexBytes:    s.LoadFetchedIndexBytes(),
+		ShardedQueries:       s.LoadShardedQueries(),
+		SplitQueries:         s.LoadSplitQueries(),
+		EstimatedSeriesCount: s.GetEstimatedSeriesCount(),
+	}
+}
+
 type stringSampleStreams []SampleStream
 
 func (sss stringSampleStreams) MarshalJSON() ([]byte, error) {
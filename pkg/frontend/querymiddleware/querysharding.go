@@ -111,9 +111,11 @@ func (s *querySharding) Do(ctx context.Context, r Request) (Response, error) {
 		return nil, apierror.New(apierror.TypeBadData, decorateWithParamName(err, "query").Error())
 	}
 
+	plan := queryPlanFromContext(ctx)
 	totalShards := s.getShardsForQuery(ctx, tenantIDs, r, queryExpr, log)
 	if totalShards <= 1 {
 		level.Debug(log).Log("msg", "query sharding is disabled for this query or tenant")
+		plan.addSharding(r, totalShards, nil, "")
 		return s.next.Do(ctx, r)
 	}
 
@@ -131,10 +133,12 @@ func (s *querySharding) Do(ctx context.Context, r Request) (Response, error) {
 			level.Debug(log).Log("msg", "query is not supported for being rewritten into a shardable query", "query", r.GetQuery())
 		}
 
+		plan.addSharding(r, totalShards, nil, "")
 		return s.next.Do(ctx, r)
 	}
 
-	level.Debug(log).Log("msg", "query has been rewritten into a shardable query", "original", r.GetQuery(), "rewritten", shardedQuery, "sharded_queries", shardingStats.GetShardedQueries())
+	level.Debug(log).Log("msg", "query has been rewritten into a shardable query", "original", r.GetQuery(), "rewritten", shardedQuery, "sharded_queries", shardingStats.GetShardedQueries(), "sharded_expressions", fmt.Sprintf("%q", shardingStats.GetShardedExpressions()))
+	plan.addSharding(r, totalShards, shardingStats, shardedQuery)
 
 	// Update metrics.
 	s.shardingSuccesses.Inc()
@@ -454,10 +454,50 @@ func TestQuerySharding_Correctness(t *testing.T) {
 		},
 		"topk()": {
 			query:                  `topk(2, metric_counter{const="fixed"})`,
+			expectedShardedQueries: 1,
+		},
+		"topk() grouping 'by'": {
+			query:                  `topk by (group_1) (2, rate(metric_counter[1m]))`,
+			expectedShardedQueries: 1,
+		},
+		"topk() grouping 'without'": {
+			query:                  `topk without (unique) (3, metric_counter)`,
+			expectedShardedQueries: 1,
+		},
+		"topk() with non constant k": {
+			query:                  `topk(scalar(count(metric_counter{group_1="0"})), metric_counter)`,
 			expectedShardedQueries: 0,
 		},
 		"bottomk()": {
 			query:                  `bottomk(2, metric_counter{const="fixed"})`,
+			expectedShardedQueries: 1,
+		},
+		"bottomk() grouping 'by'": {
+			query:                  `bottomk by (group_2) (2, rate(metric_counter[1m]))`,
+			expectedShardedQueries: 1,
+		},
+		"sum(topk())": {
+			query:                  `sum by (group_1) (topk by (group_1) (2, metric_counter))`,
+			expectedShardedQueries: 1,
+		},
+		"count_values()": {
+			query:                  `count_values("value", metric_counter{group_1="0"})`,
+			expectedShardedQueries: 1,
+		},
+		"count_values() grouping 'by'": {
+			query:                  `count_values by (group_1) ("value", floor(metric_counter))`,
+			expectedShardedQueries: 1,
+		},
+		"count_values() grouping 'by' the value label": {
+			query:                  `count_values by (group_1, value) ("value", floor(metric_counter))`,
+			expectedShardedQueries: 1,
+		},
+		"count_values() grouping 'without'": {
+			query:                  `count_values without (unique) ("value", floor(metric_counter))`,
+			expectedShardedQueries: 1,
+		},
+		"quantile()": {
+			query:                  `quantile(0.9, metric_counter)`,
 			expectedShardedQueries: 0,
 		},
 		"vector()": {
@@ -28,12 +28,13 @@ import (
 )
 
 const (
-	day                              = 24 * time.Hour
-	queryRangePathSuffix             = "/api/v1/query_range"
-	instantQueryPathSuffix           = "/api/v1/query"
-	cardinalityLabelNamesPathSuffix  = "/api/v1/cardinality/label_names"
-	cardinalityLabelValuesPathSuffix = "/api/v1/cardinality/label_values"
-	labelNamesPathSuffix             = "/api/v1/labels"
+	day                               = 24 * time.Hour
+	queryRangePathSuffix              = "/api/v1/query_range"
+	instantQueryPathSuffix            = "/api/v1/query"
+	cardinalityLabelNamesPathSuffix   = "/api/v1/cardinality/label_names"
+	cardinalityLabelValuesPathSuffix  = "/api/v1/cardinality/label_values"
+	cardinalityActiveSeriesPathSuffix = "/api/v1/cardinality/active_series"
+	labelNamesPathSuffix              = "/api/v1/labels"
 
 	// DefaultDeprecatedCacheUnalignedRequests is the default value for the deprecated querier frontend config DeprecatedCacheUnalignedRequests
 	// which has been moved to a per-tenant limit; TODO remove in Mimir 2.12
@@ -165,6 +166,8 @@ func (f RoundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
 }
 
 // NewTripperware returns a Tripperware configured with middlewares to limit, align, split, retry and cache requests.
+// The delete requests loader is optional: when set, the cached results are invalidated when the tenant's delete
+// requests change.
 func NewTripperware(
 	cfg Config,
 	log log.Logger,
@@ -172,16 +175,19 @@ func NewTripperware(
 	codec Codec,
 	cacheExtractor Extractor,
 	engineOpts promql.EngineOpts,
+	deleteRequestsLoader DeleteRequestsLoader,
 	registerer prometheus.Registerer,
 ) (Tripperware, error) {
 	queryRangeTripperware, err := newQueryTripperware(cfg, log, limits, codec, cacheExtractor, engineOpts, registerer)
 	if err != nil {
 		return nil, err
 	}
-	return MergeTripperwares(
-		newActiveUsersTripperware(registerer),
-		queryRangeTripperware,
-	), err
+
+	tripperwares := []Tripperware{newActiveUsersTripperware(registerer)}
+	if deleteRequestsLoader != nil && cfg.CacheResults {
+		tripperwares = append(tripperwares, newDeleteRequestsTripperware(deleteRequestsLoader, log))
+	}
+	return MergeTripperwares(append(tripperwares, queryRangeTripperware)...), err
 }
 
 func newQueryTripperware(
@@ -314,8 +320,19 @@ func newQueryTripperware(
 			labels = newLabelsQueryCacheRoundTripper(c, limits, next, log, registerer)
 		}
 
+		// Inject the active series sharding roundtripper only if query sharding is enabled.
+		activeSeries := next
+		if cfg.ShardedQueries {
+			activeSeries = newShardActiveSeriesRoundTripper(limits, next, log)
+		}
+
+		// The queries to explain run through the same middlewares, which don't execute them.
+		explain := newQueryExplainRoundTripper(cfg, limits, queryrange, instant)
+
 		return RoundTripFunc(func(r *http.Request) (*http.Response, error) {
 			switch {
+			case isQueryExplain(r.URL.Path) || isExplainRequested(r):
+				return explain.RoundTrip(r)
 			case isRangeQuery(r.URL.Path):
 				return queryrange.RoundTrip(r)
 			case isInstantQuery(r.URL.Path):
@@ -324,6 +341,8 @@ func newQueryTripperware(
 				return cardinality.RoundTrip(r)
 			case isLabelsQuery(r.URL.Path):
 				return labels.RoundTrip(r)
+			case isActiveSeriesQuery(r.URL.Path):
+				return activeSeries.RoundTrip(r)
 			default:
 				return next.RoundTrip(r)
 			}
@@ -377,6 +396,10 @@ func isCardinalityQuery(path string) bool {
 	return strings.HasSuffix(path, cardinalityLabelNamesPathSuffix) || strings.HasSuffix(path, cardinalityLabelValuesPathSuffix)
 }
 
+func isActiveSeriesQuery(path string) bool {
+	return strings.HasSuffix(path, cardinalityActiveSeriesPathSuffix)
+}
+
 func isLabelsQuery(path string) bool {
 	return strings.HasSuffix(path, labelNamesPathSuffix) || labelValuesPathSuffix.MatchString(path)
 }
@@ -77,6 +77,7 @@ func TestRangeTripperware(t *testing.T) {
 			Timeout:    time.Minute,
 		},
 		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -128,6 +129,7 @@ func TestInstantTripperware(t *testing.T) {
 			Timeout:    time.Minute,
 		},
 		nil,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -300,6 +302,7 @@ func TestTripperware_Metrics(t *testing.T) {
 					MaxSamples: 1000,
 					Timeout:    time.Minute,
 				},
+				nil,
 				reg,
 			)
 			require.NoError(t, err)
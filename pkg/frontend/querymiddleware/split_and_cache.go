@@ -164,11 +164,12 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req Request) (Response
 			// Do not try to pick response from cache at all if the request is not cachable.
 			if cachable, reason := isRequestCachable(splitReq.orig, maxCacheTime, cacheUnalignedRequests, s.logger); !cachable {
 				splitReq.downstreamRequests = []Request{splitReq.orig}
+				splitReq.notCachableReason = reason
 				s.metrics.queryResultCacheSkippedCount.WithLabelValues(reason).Inc()
 				continue
 			}
 
-			splitReq.cacheKey = s.splitter.GenerateCacheKey(ctx, tenant.JoinTenantIDs(tenantIDs), splitReq.orig)
+			splitReq.cacheKey = withDeleteRequestsGeneration(ctx, s.splitter.GenerateCacheKey(ctx, tenant.JoinTenantIDs(tenantIDs), splitReq.orig))
 			lookupKeys = append(lookupKeys, splitReq.cacheKey)
 			lookupReqs = append(lookupReqs, splitReq)
 		}
@@ -198,6 +199,7 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req Request) (Response
 				}
 
 				lookupReqs[lookupIdx].cachedResponses = []Response{response}
+				lookupReqs[lookupIdx].cachedExtents = extents
 				continue
 			}
 
@@ -212,6 +214,9 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req Request) (Response
 		}
 	}
 
+	plan := queryPlanFromContext(ctx)
+	plan.addSplits(splitReqs)
+
 	// Prepare and execute the downstream requests.
 	execReqs := splitReqs.prepareDownstreamRequests()
 
@@ -234,8 +239,8 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req Request) (Response
 		}
 	}
 
-	// Store the updated response in the results cache.
-	if isCacheEnabled && len(execReqs) > 0 {
+	// Store the updated response in the results cache. The empty responses of the queries being explained are not stored.
+	if isCacheEnabled && len(execReqs) > 0 && plan == nil {
 		for _, splitReq := range splitReqs {
 			// If there are no downstream requests it means the response was entirely picked up from the cache
 			// so there's no need to store it again in the cache (because nothing has changed).
@@ -451,6 +456,9 @@ type splitRequest struct {
 	// The cache key for the request.
 	cacheKey string
 
+	// The reason why the request is not cachable, if it's not.
+	notCachableReason string
+
 	// The extents picked up from the cache.
 	cachedExtents []Extent
 
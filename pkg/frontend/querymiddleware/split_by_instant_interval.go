@@ -161,6 +161,8 @@ func (s *splitInstantQueryByIntervalMiddleware) Do(ctx context.Context, req Requ
 
 	level.Debug(spanLog).Log("msg", "instant query has been split by interval", "rewritten", instantSplitQuery, "split_queries", mapperStats.GetSplitQueries())
 
+	queryPlanFromContext(ctx).addInstantSplit(req, instantSplitQuery.String(), mapperStats.GetSplitQueries())
+
 	// Update query stats.
 	queryStats := stats.FromContext(ctx)
 	queryStats.AddSplitQueries(uint32(mapperStats.GetSplitQueries()))
@@ -28,6 +28,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	apierror "github.com/grafana/mimir/pkg/api/error"
+	"github.com/grafana/mimir/pkg/frontend/activequeries"
+	"github.com/grafana/mimir/pkg/frontend/querymiddleware"
 	querier_stats "github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/activitytracker"
@@ -44,6 +46,8 @@ var (
 	errCanceled              = httpgrpc.Errorf(StatusClientClosedRequest, context.Canceled.Error())
 	errDeadlineExceeded      = httpgrpc.Errorf(http.StatusGatewayTimeout, context.DeadlineExceeded.Error())
 	errRequestEntityTooLarge = httpgrpc.Errorf(http.StatusRequestEntityTooLarge, "http: request body too large")
+
+	errInvalidSlowQueryLogRetentionPeriod = errors.New("the slow query log retention period must be greater than 0 when the slow query log is enabled")
 )
 
 // Config for a Handler.
@@ -52,13 +56,27 @@ type HandlerConfig struct {
 	LogQueryRequestHeaders flagext.StringSliceCSV `yaml:"log_query_request_headers" category:"advanced"`
 	MaxBodySize            int64                  `yaml:"max_body_size" category:"advanced"`
 	QueryStatsEnabled      bool                   `yaml:"query_stats_enabled" category:"advanced"`
+
+	SlowQueryLogSize            int           `yaml:"slow_query_log_size" category:"experimental"`
+	SlowQueryLogMinResponseTime time.Duration `yaml:"slow_query_log_min_response_time" category:"experimental"`
+	SlowQueryLogRetentionPeriod time.Duration `yaml:"slow_query_log_retention_period" category:"experimental"`
 }
 
 func (cfg *HandlerConfig) RegisterFlags(f *flag.FlagSet) {
 	f.DurationVar(&cfg.LogQueriesLongerThan, "query-frontend.log-queries-longer-than", 0, "Log queries that are slower than the specified duration. Set to 0 to disable. Set to < 0 to enable on all queries.")
 	f.Var(&cfg.LogQueryRequestHeaders, "query-frontend.log-query-request-headers", "Comma-separated list of request header names to include in query logs. Applies to both query stats and slow queries logs.")
 	f.Int64Var(&cfg.MaxBodySize, "query-frontend.max-body-size", 10*1024*1024, "Max body size for downstream prometheus.")
-	f.BoolVar(&cfg.QueryStatsEnabled, "query-frontend.query-stats-enabled", true, "False to disable query statistics tracking. When enabled, a message with some statistics is logged for every query.")
+	f.BoolVar(&cfg.QueryStatsEnabled, "query-frontend.query-stats-enabled", true, "False to disable query statistics tracking. When enabled, a message with some statistics is logged for every query, and the statistics are returned in the response of the queries with the request param stats.")
+	f.IntVar(&cfg.SlowQueryLogSize, "query-frontend.slow-query-log-size", 0, "Number of queries kept per tenant in the slow query log for each response time or query statistic the tenants can sort the queries by, which are the queries with the highest value. 0 to disable the slow query log.")
+	f.DurationVar(&cfg.SlowQueryLogMinResponseTime, "query-frontend.slow-query-log-min-response-time", 0, "Minimum response time of the queries kept in the slow query log. 0 to keep all queries.")
+	f.DurationVar(&cfg.SlowQueryLogRetentionPeriod, "query-frontend.slow-query-log-retention-period", 24*time.Hour, "How long the queries are kept in the slow query log.")
+}
+
+func (cfg *HandlerConfig) Validate() error {
+	if cfg.SlowQueryLogSize > 0 && cfg.SlowQueryLogRetentionPeriod <= 0 {
+		return errInvalidSlowQueryLogRetentionPeriod
+	}
+	return nil
 }
 
 // Handler accepts queries and forwards them to RoundTripper. It can wait on in-flight requests and log slow queries,
@@ -68,6 +86,10 @@ type Handler struct {
 	log          log.Logger
 	roundTripper http.RoundTripper
 	at           *activitytracker.ActivityTracker
+	slowQueries  *slowQueryLog
+
+	// Tracks the queries in progress, so that they can be listed and cancelled.
+	activeQueries *activequeries.Tracker
 
 	// Metrics.
 	querySeconds    *prometheus.CounterVec
@@ -90,9 +112,15 @@ func NewHandler(cfg HandlerConfig, roundTripper http.RoundTripper, log log.Logge
 		log:          log,
 		roundTripper: roundTripper,
 		at:           at,
+
+		activeQueries: activequeries.NewTracker(),
 	}
 	h.cond = sync.NewCond(&h.mtx)
 
+	if cfg.SlowQueryLogSize > 0 {
+		h.slowQueries = newSlowQueryLog(cfg.SlowQueryLogSize, cfg.SlowQueryLogMinResponseTime, cfg.SlowQueryLogRetentionPeriod)
+	}
+
 	if cfg.QueryStatsEnabled {
 		h.querySeconds = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
 			Name: "cortex_query_seconds_total",
@@ -189,13 +217,21 @@ func (f *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	activityIndex := f.at.Insert(func() string { return httpRequestActivity(r, params) })
 	defer f.at.Delete(activityIndex)
 
+	activeQuery, ctx := f.activeQueries.Track(r.Context(), r.Method, r.URL.Path, params)
+	defer f.activeQueries.Done(activeQuery)
+	r = r.WithContext(ctx)
+
 	startTime := time.Now()
 	resp, err := f.roundTripper.RoundTrip(r)
 	queryResponseTime := time.Since(startTime)
 
 	if err != nil {
+		if errors.Is(context.Cause(ctx), activequeries.ErrQueryCancelled) {
+			err = apierror.New(apierror.TypeCanceled, activequeries.ErrQueryCancelled.Error())
+		}
 		writeError(w, err)
 		f.reportQueryStats(r, params, queryResponseTime, 0, stats, err)
+		f.recordSlowQuery(r, params, startTime, queryResponseTime, 0, stats, err)
 		return
 	}
 
@@ -218,6 +254,20 @@ func (f *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if f.cfg.QueryStatsEnabled {
 		f.reportQueryStats(r, params, queryResponseTime, queryResponseSize, stats, nil)
 	}
+	f.recordSlowQuery(r, params, startTime, queryResponseTime, queryResponseSize, stats, nil)
+}
+
+// SlowQueryLogHandler returns the handler listing the slow queries of the tenant, or nil if the slow query log is disabled.
+func (f *Handler) SlowQueryLogHandler() http.Handler {
+	if f.slowQueries == nil {
+		return nil
+	}
+	return f.slowQueries
+}
+
+// ActiveQueries returns the tracker of the queries in progress.
+func (f *Handler) ActiveQueries() *activequeries.Tracker {
+	return f.activeQueries
 }
 
 // reportSlowQuery reports slow queries.
@@ -284,24 +334,57 @@ func (f *Handler) reportQueryStats(r *http.Request, queryString url.Values, quer
 	}
 
 	if queryErr != nil {
-		logStatus := "failed"
-		if errors.Is(queryErr, context.Canceled) {
-			logStatus = "canceled"
-		} else if errors.Is(queryErr, context.DeadlineExceeded) {
-			logStatus = "timeout"
-		}
-
 		logMessage = append(logMessage,
-			"status", logStatus,
+			"status", queryStatus(queryErr),
 			"err", queryErr)
 	} else {
 		logMessage = append(logMessage,
-			"status", "success")
+			"status", queryStatus(nil))
 	}
 
 	level.Info(util_log.WithContext(r.Context(), f.log)).Log(logMessage...)
 }
 
+// recordSlowQuery records the query in the slow query log, if it's enabled.
+func (f *Handler) recordSlowQuery(r *http.Request, queryString url.Values, startTime time.Time, queryResponseTime time.Duration, queryResponseSizeBytes int64, stats *querier_stats.Stats, queryErr error) {
+	if f.slowQueries == nil {
+		return
+	}
+	tenantIDs, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		return
+	}
+
+	q := slowQuery{
+		Timestamp:           startTime,
+		Method:              r.Method,
+		Path:                r.URL.Path,
+		Params:              formatParams(queryString),
+		Status:              queryStatus(queryErr),
+		ResponseTimeSeconds: queryResponseTime.Seconds(),
+		ResponseSizeBytes:   queryResponseSizeBytes,
+		Stats:               querymiddleware.NewQueryStats(stats),
+	}
+	if queryErr != nil {
+		q.Error = queryErr.Error()
+	}
+	f.slowQueries.record(tenant.JoinTenantIDs(tenantIDs), q)
+}
+
+// queryStatus returns the status of the query, as logged and recorded in the slow query log.
+func queryStatus(queryErr error) string {
+	switch {
+	case queryErr == nil:
+		return "success"
+	case errors.Is(queryErr, context.Canceled):
+		return "canceled"
+	case errors.Is(queryErr, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "failed"
+	}
+}
+
 func formatQueryString(queryString url.Values) (fields []interface{}) {
 	for k, v := range queryString {
 		fields = append(fields, fmt.Sprintf("param_%s", k), strings.Join(v, ","))
@@ -31,6 +31,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 
+	"github.com/grafana/mimir/pkg/frontend/activequeries"
 	"github.com/grafana/mimir/pkg/util/activitytracker"
 )
 
@@ -357,6 +358,39 @@ func TestHandler_Stop(t *testing.T) {
 	})
 }
 
+func TestHandler_ActiveQueries(t *testing.T) {
+	started := make(chan struct{})
+	roundTripper := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	handler := NewHandler(HandlerConfig{MaxBodySize: 1024}, roundTripper, log.NewNopLogger(), prometheus.NewPedanticRegistry(), nil)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/query?query=up", nil).WithContext(user.InjectOrgID(context.Background(), "12345"))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		done <- resp
+	}()
+	<-started
+
+	queries := handler.ActiveQueries().List("12345")
+	require.Len(t, queries, 1)
+	assert.Equal(t, "/api/v1/query", queries[0].Path)
+	assert.Equal(t, map[string]string{"query": "up"}, queries[0].Params)
+
+	require.True(t, handler.ActiveQueries().Cancel("12345", queries[0].ID))
+	resp := <-done
+	require.Equal(t, 499, resp.Code)
+	require.Contains(t, resp.Body.String(), activequeries.ErrQueryCancelled.Error())
+
+	// The query isn't active anymore.
+	require.Empty(t, handler.ActiveQueries().List("12345"))
+}
+
 type testLogger struct {
 	logMessages []map[string]interface{}
 }
@@ -394,3 +428,14 @@ func TestFormatRequestHeaders(t *testing.T) {
 
 	assert.Equal(t, expected, fields)
 }
+
+func TestHandlerConfig_Validate(t *testing.T) {
+	cfg := HandlerConfig{}
+	require.NoError(t, cfg.Validate())
+
+	cfg.SlowQueryLogSize = 10
+	require.ErrorIs(t, cfg.Validate(), errInvalidSlowQueryLogRetentionPeriod)
+
+	cfg.SlowQueryLogRetentionPeriod = time.Hour
+	require.NoError(t, cfg.Validate())
+}
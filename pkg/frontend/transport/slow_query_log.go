@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/mimir/pkg/frontend/querymiddleware"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+const slowQueryLogDefaultSort = "response_time"
+
+// slowQuery is a query recorded in the slow query log.
+type slowQuery struct {
+	Timestamp           time.Time                  `json:"timestamp"`
+	Method              string                     `json:"method"`
+	Path                string                     `json:"path"`
+	Params              map[string]string          `json:"params"`
+	Status              string                     `json:"status"`
+	Error               string                     `json:"error,omitempty"`
+	ResponseTimeSeconds float64                    `json:"response_time_seconds"`
+	ResponseSizeBytes   int64                      `json:"response_size_bytes"`
+	Stats               querymiddleware.QueryStats `json:"stats"`
+}
+
+// slowQuerySortFields are the fields the slow queries can be sorted by, in descending order.
+var slowQuerySortFields = map[string]func(q *slowQuery) float64{
+	"response_time":        func(q *slowQuery) float64 { return q.ResponseTimeSeconds },
+	"response_size_bytes":  func(q *slowQuery) float64 { return float64(q.ResponseSizeBytes) },
+	"wall_time":            func(q *slowQuery) float64 { return q.Stats.WallTimeSeconds },
+	"fetched_series_count": func(q *slowQuery) float64 { return float64(q.Stats.FetchedSeriesCount) },
+	"fetched_chunk_bytes":  func(q *slowQuery) float64 { return float64(q.Stats.FetchedChunkBytes) },
+	"fetched_chunks_count": func(q *slowQuery) float64 { return float64(q.Stats.FetchedChunksCount) },
+	"fetched_index_bytes":  func(q *slowQuery) float64 { return float64(q.Stats.FetchedIndexBytes) },
+}
+
+// slowQueryLog keeps, for each tenant and each field the queries can be sorted by, the queries with the highest value
+// of the field among the queries slower than a minimum response time, during a retention period, so that the tenants
+// can find out which of their queries are the slowest or the most expensive.
+type slowQueryLog struct {
+	size            int
+	minResponseTime time.Duration
+	retentionPeriod time.Duration
+	activeUsers     *util.ActiveUsersCleanupService
+
+	mtx sync.Mutex
+	// tenants are the slow queries of each tenant, by sort field. The queries are shared between the sort fields.
+	tenants map[string]map[string][]*slowQuery
+}
+
+func newSlowQueryLog(size int, minResponseTime, retentionPeriod time.Duration) *slowQueryLog {
+	l := &slowQueryLog{
+		size:            size,
+		minResponseTime: minResponseTime,
+		retentionPeriod: retentionPeriod,
+		tenants:         map[string]map[string][]*slowQuery{},
+	}
+	l.activeUsers = util.NewActiveUsersCleanupService(time.Minute, retentionPeriod, l.deleteTenant)
+
+	// If cleaner stops or fail, we will simply not clean the queries of inactive tenants.
+	_ = l.activeUsers.StartAsync(context.Background())
+	return l
+}
+
+// record adds the query to the log of the tenant, if it's slower than the minimum response time, for each sort field
+// it has one of the highest values of.
+func (l *slowQueryLog) record(tenantID string, q slowQuery) {
+	if q.ResponseTimeSeconds < l.minResponseTime.Seconds() {
+		return
+	}
+	l.activeUsers.UpdateUserTimestamp(tenantID, q.Timestamp)
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	queries, ok := l.tenants[tenantID]
+	if !ok {
+		queries = make(map[string][]*slowQuery, len(slowQuerySortFields))
+		l.tenants[tenantID] = queries
+	}
+
+	minTimestamp := time.Now().Add(-l.retentionPeriod)
+	for name, sortField := range slowQuerySortFields {
+		queries[name] = addSlowQuery(removeExpiredSlowQueries(queries[name], minTimestamp), &q, sortField, l.size)
+	}
+}
+
+// addSlowQuery adds q to the queries if there are less than size queries, or if it has a higher or equal value
+// of the sort field than the query with the lowest one, which it replaces.
+func addSlowQuery(queries []*slowQuery, q *slowQuery, sortField func(q *slowQuery) float64, size int) []*slowQuery {
+	if len(queries) < size {
+		return append(queries, q)
+	}
+
+	lowest := 0
+	for i := range queries {
+		if sortField(queries[i]) < sortField(queries[lowest]) {
+			lowest = i
+		}
+	}
+	if sortField(q) >= sortField(queries[lowest]) {
+		queries[lowest] = q
+	}
+	return queries
+}
+
+func removeExpiredSlowQueries(queries []*slowQuery, minTimestamp time.Time) []*slowQuery {
+	kept := queries[:0]
+	for _, q := range queries {
+		if !q.Timestamp.Before(minTimestamp) {
+			kept = append(kept, q)
+		}
+	}
+	// Don't keep references to the removed queries.
+	clear(queries[len(kept):])
+	return kept
+}
+
+// queries returns a copy of the queries of the tenant with the highest values of the sort field, which haven't expired.
+func (l *slowQueryLog) queries(tenantID, sortBy string) []slowQuery {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	minTimestamp := time.Now().Add(-l.retentionPeriod)
+	queries := []slowQuery{}
+	for _, q := range l.tenants[tenantID][sortBy] {
+		if !q.Timestamp.Before(minTimestamp) {
+			queries = append(queries, *q)
+		}
+	}
+	return queries
+}
+
+func (l *slowQueryLog) deleteTenant(tenantID string) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	delete(l.tenants, tenantID)
+}
+
+// ServeHTTP returns the slow queries of the tenant, sorted by the request param "sort" in descending order,
+// and limited to the request param "limit" if any.
+func (l *slowQueryLog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantIDs, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sortBy := r.FormValue("sort")
+	if sortBy == "" {
+		sortBy = slowQueryLogDefaultSort
+	}
+	sortField, ok := slowQuerySortFields[sortBy]
+	if !ok {
+		http.Error(w, fmt.Sprintf("invalid sort %q, supported values are: %s", sortBy, strings.Join(slowQuerySortFieldNames(), ", ")), http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if v := r.FormValue("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			http.Error(w, fmt.Sprintf("invalid limit %q", v), http.StatusBadRequest)
+			return
+		}
+	}
+
+	queries := l.queries(tenant.JoinTenantIDs(tenantIDs), sortBy)
+	sort.SliceStable(queries, func(i, j int) bool {
+		return sortField(&queries[i]) > sortField(&queries[j])
+	})
+	if limit > 0 && len(queries) > limit {
+		queries = queries[:limit]
+	}
+
+	util.WriteJSONResponse(w, struct {
+		Status string      `json:"status"`
+		Data   []slowQuery `json:"data"`
+	}{
+		Status: "success",
+		Data:   queries,
+	})
+}
+
+func slowQuerySortFieldNames() []string {
+	names := make([]string, 0, len(slowQuerySortFields))
+	for name := range slowQuerySortFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatParams returns the request params, the values of the same param being joined by commas.
+func formatParams(params url.Values) map[string]string {
+	formatted := make(map[string]string, len(params))
+	for k, v := range params {
+		formatted[k] = strings.Join(v, ",")
+	}
+	return formatted
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/frontend/querymiddleware"
+	querier_stats "github.com/grafana/mimir/pkg/querier/stats"
+)
+
+func TestSlowQueryLog_Record(t *testing.T) {
+	l := newSlowQueryLog(3, time.Second, time.Hour)
+	now := time.Now()
+
+	// The expired queries are removed when a query is recorded.
+	l.record("user-1", slowQuery{Path: "expired", Timestamp: now.Add(-2 * time.Hour), ResponseTimeSeconds: 100, Stats: querymiddleware.QueryStats{FetchedSeriesCount: 100000}})
+
+	for i, q := range []slowQuery{
+		{ResponseTimeSeconds: 4, Stats: querymiddleware.QueryStats{FetchedSeriesCount: 1}},
+		{ResponseTimeSeconds: 0.5, Stats: querymiddleware.QueryStats{FetchedSeriesCount: 1000}},
+		{ResponseTimeSeconds: 1, Stats: querymiddleware.QueryStats{FetchedSeriesCount: 10}},
+		{ResponseTimeSeconds: 3, Stats: querymiddleware.QueryStats{FetchedSeriesCount: 20}},
+		{ResponseTimeSeconds: 2, Stats: querymiddleware.QueryStats{FetchedSeriesCount: 30}},
+		{ResponseTimeSeconds: 1.5, Stats: querymiddleware.QueryStats{FetchedSeriesCount: 5}},
+	} {
+		q.Path = string(rune('a' + i))
+		q.Timestamp = now
+		l.record("user-1", q)
+	}
+	l.record("user-2", slowQuery{Path: "z", Timestamp: now, ResponseTimeSeconds: 10})
+
+	paths := func(tenantID, sortBy string) []string {
+		var paths []string
+		for _, q := range l.queries(tenantID, sortBy) {
+			paths = append(paths, q.Path)
+		}
+		return paths
+	}
+
+	// The queries faster than the minimum response time aren't recorded, and the queries with the highest
+	// values of each sort field are kept, regardless of when they've been recorded.
+	assert.ElementsMatch(t, []string{"a", "d", "e"}, paths("user-1", "response_time"))
+	assert.ElementsMatch(t, []string{"c", "d", "e"}, paths("user-1", "fetched_series_count"))
+
+	assert.Len(t, l.queries("user-2", "response_time"), 1)
+	assert.Empty(t, l.queries("user-3", "response_time"))
+
+	l.deleteTenant("user-2")
+	assert.Empty(t, l.queries("user-2", "response_time"))
+}
+
+func TestSlowQueryLog_ShouldNotReturnExpiredQueries(t *testing.T) {
+	l := newSlowQueryLog(3, 0, time.Hour)
+	now := time.Now()
+
+	l.record("user-1", slowQuery{Path: "recent", Timestamp: now, ResponseTimeSeconds: 1})
+	l.record("user-1", slowQuery{Path: "expired", Timestamp: now.Add(-2 * time.Hour), ResponseTimeSeconds: 10})
+
+	queries := l.queries("user-1", "response_time")
+	require.Len(t, queries, 1)
+	assert.Equal(t, "recent", queries[0].Path)
+}
+
+func TestSlowQueryLog_ServeHTTP(t *testing.T) {
+	l := newSlowQueryLog(10, 0, time.Hour)
+	now := time.Now()
+	l.record("user-1", slowQuery{Path: "slow", Timestamp: now, ResponseTimeSeconds: 10, Stats: querymiddleware.QueryStats{FetchedChunkBytes: 1}})
+	l.record("user-1", slowQuery{Path: "expensive", Timestamp: now, ResponseTimeSeconds: 1, Stats: querymiddleware.QueryStats{FetchedChunkBytes: 1000}})
+	l.record("user-1", slowQuery{Path: "cheap", Timestamp: now, ResponseTimeSeconds: 2, Stats: querymiddleware.QueryStats{FetchedChunkBytes: 10}})
+	l.record("user-2", slowQuery{Path: "other tenant", Timestamp: now, ResponseTimeSeconds: 100})
+
+	for name, tc := range map[string]struct {
+		params         string
+		expectedStatus int
+		expectedPaths  []string
+	}{
+		"default sort": {
+			expectedStatus: http.StatusOK,
+			expectedPaths:  []string{"slow", "cheap", "expensive"},
+		},
+		"sorted by fetched chunk bytes": {
+			params:         "?sort=fetched_chunk_bytes",
+			expectedStatus: http.StatusOK,
+			expectedPaths:  []string{"expensive", "cheap", "slow"},
+		},
+		"limited": {
+			params:         "?sort=response_time&limit=1",
+			expectedStatus: http.StatusOK,
+			expectedPaths:  []string{"slow"},
+		},
+		"invalid sort": {
+			params:         "?sort=unknown",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"invalid limit": {
+			params:         "?limit=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/slow_queries"+tc.params, nil).WithContext(user.InjectOrgID(context.Background(), "user-1"))
+			resp := httptest.NewRecorder()
+			l.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedStatus, resp.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var res struct {
+				Status string      `json:"status"`
+				Data   []slowQuery `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+			require.Equal(t, "success", res.Status)
+
+			paths := make([]string, 0, len(res.Data))
+			for _, q := range res.Data {
+				paths = append(paths, q.Path)
+			}
+			require.Equal(t, tc.expectedPaths, paths)
+		})
+	}
+}
+
+func TestHandler_SlowQueryLog(t *testing.T) {
+	roundTripper := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		querier_stats.FromContext(req.Context()).AddFetchedSeries(5)
+
+		if req.URL.Query().Get("query") == "failing" {
+			return nil, errors.New("query failed")
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("{}")),
+		}, nil
+	})
+
+	cfg := HandlerConfig{QueryStatsEnabled: true, SlowQueryLogSize: 10, SlowQueryLogRetentionPeriod: time.Hour}
+	handler := NewHandler(cfg, roundTripper, log.NewNopLogger(), prometheus.NewPedanticRegistry(), nil)
+	require.NotNil(t, handler.SlowQueryLogHandler())
+
+	for _, query := range []string{"up", "failing"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/query?query="+query, nil).WithContext(user.InjectOrgID(context.Background(), "12345"))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	queries := handler.slowQueries.queries("12345", "response_time")
+	require.Len(t, queries, 2)
+
+	assert.Equal(t, http.MethodGet, queries[0].Method)
+	assert.Equal(t, "/api/v1/query", queries[0].Path)
+	assert.Equal(t, map[string]string{"query": "up"}, queries[0].Params)
+	assert.Equal(t, "success", queries[0].Status)
+	assert.Empty(t, queries[0].Error)
+	assert.Equal(t, int64(2), queries[0].ResponseSizeBytes)
+	assert.Equal(t, uint64(5), queries[0].Stats.FetchedSeriesCount)
+
+	assert.Equal(t, "failed", queries[1].Status)
+	assert.Equal(t, "query failed", queries[1].Error)
+
+	// The slow query log is disabled by default.
+	handler = NewHandler(HandlerConfig{QueryStatsEnabled: true}, roundTripper, log.NewNopLogger(), prometheus.NewPedanticRegistry(), nil)
+	require.Nil(t, handler.SlowQueryLogHandler())
+}
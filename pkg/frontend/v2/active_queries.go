@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/multierror"
+	"github.com/grafana/dskit/ring/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/grafana/mimir/pkg/frontend/activequeries"
+	"github.com/grafana/mimir/pkg/frontend/v2/frontendv2pb"
+)
+
+// activeQueriesPeersConcurrency is the max number of query-frontends called concurrently by the active queries API.
+const activeQueriesPeersConcurrency = 16
+
+// ActiveQueries implements activequeries.Peers. It returns the active queries of the tenant in the other
+// query-frontends connected to the query-schedulers.
+func (f *Frontend) ActiveQueries(ctx context.Context) ([]activequeries.Info, error) {
+	var (
+		mtx     sync.Mutex
+		queries []activequeries.Info
+	)
+
+	err := f.forEachPeer(ctx, func(ctx context.Context, client frontendv2pb.FrontendForFrontendClient) error {
+		resp, err := client.ActiveQueries(ctx, &frontendv2pb.ActiveQueriesRequest{})
+		if err != nil {
+			return err
+		}
+
+		mtx.Lock()
+		queries = append(queries, activequeries.InfosFromProto(resp)...)
+		mtx.Unlock()
+		return nil
+	})
+
+	return queries, err
+}
+
+// CancelActiveQuery implements activequeries.Peers. It cancels the query of the tenant in the other
+// query-frontends connected to the query-schedulers, and returns whether it was found.
+func (f *Frontend) CancelActiveQuery(ctx context.Context, id string) (bool, error) {
+	cancelled := atomic.NewBool(false)
+
+	err := f.forEachPeer(ctx, func(ctx context.Context, client frontendv2pb.FrontendForFrontendClient) error {
+		resp, err := client.CancelActiveQuery(ctx, &frontendv2pb.CancelActiveQueryRequest{Id: id})
+		if err != nil {
+			return err
+		}
+
+		if resp.Cancelled {
+			cancelled.Store(true)
+		}
+		return nil
+	})
+
+	return cancelled.Load(), err
+}
+
+// forEachPeer calls fn for each of the other query-frontends connected to the query-schedulers. It returns
+// an error listing the query-schedulers and query-frontends which couldn't be reached, after fn has been
+// called for all the other query-frontends.
+func (f *Frontend) forEachPeer(ctx context.Context, fn func(context.Context, frontendv2pb.FrontendForFrontendClient) error) error {
+	var (
+		mtx  sync.Mutex
+		errs multierror.MultiError
+	)
+
+	addresses, err := f.schedulerWorkers.getConnectedFrontends(ctx)
+	if err != nil {
+		errs.Add(err)
+	}
+
+	_ = concurrency.ForEachJob(ctx, len(addresses), activeQueriesPeersConcurrency, func(ctx context.Context, idx int) error {
+		addr := addresses[idx]
+
+		c, err := f.peersPool.GetClientFor(addr)
+		if err == nil {
+			if err = fn(ctx, c.(frontendv2pb.FrontendForFrontendClient)); err != nil {
+				// The connection may be broken, so the next calls get a new one.
+				f.peersPool.RemoveClientFor(addr)
+			}
+		}
+		if err != nil {
+			mtx.Lock()
+			errs.Add(fmt.Errorf("query-frontend %s: %w", addr, err))
+			mtx.Unlock()
+		}
+		return nil
+	})
+
+	return errs.Err()
+}
+
+func newPeersPool(cfg Config, log log.Logger, reg prometheus.Registerer) *client.Pool {
+	clientsGauge := promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+		Name: "cortex_query_frontend_peer_clients",
+		Help: "The current number of clients connected to the other query-frontends.",
+	})
+
+	poolConfig := client.PoolConfig{
+		CheckInterval:      10 * time.Second,
+		HealthCheckEnabled: true,
+		HealthCheckTimeout: 1 * time.Second,
+	}
+
+	return client.NewPool("query-frontend", poolConfig, nil, client.PoolAddrFunc(func(addr string) (client.PoolClient, error) {
+		opts, err := cfg.GRPCClientConfig.DialOption([]grpc.UnaryClientInterceptor{middleware.ClientUserHeaderInterceptor}, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := grpc.Dial(addr, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &peerClient{
+			FrontendForFrontendClient: frontendv2pb.NewFrontendForFrontendClient(conn),
+			HealthClient:              grpc_health_v1.NewHealthClient(conn),
+			conn:                      conn,
+		}, nil
+	}), clientsGauge, log)
+}
+
+type peerClient struct {
+	frontendv2pb.FrontendForFrontendClient
+	grpc_health_v1.HealthClient
+	conn *grpc.ClientConn
+}
+
+func (c *peerClient) Close() error {
+	return c.conn.Close()
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/dskit/grpcclient"
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/netutil"
+	"github.com/grafana/dskit/ring/client"
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/tenant"
 	"github.com/opentracing/opentracing-go"
@@ -30,6 +31,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/atomic"
 
+	"github.com/grafana/mimir/pkg/frontend/activequeries"
 	"github.com/grafana/mimir/pkg/frontend/v2/frontendv2pb"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/scheduler/schedulerdiscovery"
@@ -42,7 +44,7 @@ type Config struct {
 	SchedulerAddress  string            `yaml:"scheduler_address"`
 	DNSLookupPeriod   time.Duration     `yaml:"scheduler_dns_lookup_period" category:"advanced"`
 	WorkerConcurrency int               `yaml:"scheduler_worker_concurrency" category:"advanced"`
-	GRPCClientConfig  grpcclient.Config `yaml:"grpc_client_config" doc:"description=Configures the gRPC client used to communicate between the query-frontends and the query-schedulers."`
+	GRPCClientConfig  grpcclient.Config `yaml:"grpc_client_config" doc:"description=Configures the gRPC client used to communicate between the query-frontends and the query-schedulers, and between the query-frontends."`
 
 	// Used to find local IP address, that is sent to scheduler and querier-worker.
 	InfNames   []string `yaml:"instance_interface_names" category:"advanced" doc:"default=[<private network interfaces>]"`
@@ -91,9 +93,11 @@ type Frontend struct {
 	// frontend workers will read from this channel, and send request to scheduler.
 	requestsCh chan *frontendRequest
 
-	schedulerWorkers        *frontendSchedulerWorkers
-	schedulerWorkersWatcher *services.FailureWatcher
-	requests                *requestsInProgress
+	schedulerWorkers   *frontendSchedulerWorkers
+	peersPool          *client.Pool
+	subservices        *services.Manager
+	subservicesWatcher *services.FailureWatcher
+	requests           *requestsInProgress
 }
 
 type frontendRequest struct {
@@ -102,6 +106,9 @@ type frontendRequest struct {
 	userID       string
 	statsEnabled bool
 
+	// The query in progress in the frontend this request is a subquery of, if tracked.
+	activeQuery *activequeries.Query
+
 	ctx    context.Context
 	cancel context.CancelFunc
 
@@ -135,12 +142,18 @@ func NewFrontend(cfg Config, log log.Logger, reg prometheus.Registerer) (*Fronte
 	}
 
 	f := &Frontend{
-		cfg:                     cfg,
-		log:                     log,
-		requestsCh:              requestsCh,
-		schedulerWorkers:        schedulerWorkers,
-		schedulerWorkersWatcher: services.NewFailureWatcher(),
-		requests:                newRequestsInProgress(),
+		cfg:                cfg,
+		log:                log,
+		requestsCh:         requestsCh,
+		schedulerWorkers:   schedulerWorkers,
+		peersPool:          newPeersPool(cfg, log, reg),
+		subservicesWatcher: services.NewFailureWatcher(),
+		requests:           newRequestsInProgress(),
+	}
+
+	f.subservices, err = services.NewManager(f.schedulerWorkers, f.peersPool)
+	if err != nil {
+		return nil, err
 	}
 	// Randomize to avoid getting responses from queries sent before restart, which could lead to mixing results
 	// between different queries. Note that frontend verifies the user, so it cannot leak results between tenants.
@@ -166,22 +179,22 @@ func NewFrontend(cfg Config, log log.Logger, reg prometheus.Registerer) (*Fronte
 }
 
 func (f *Frontend) starting(ctx context.Context) error {
-	f.schedulerWorkersWatcher.WatchService(f.schedulerWorkers)
+	f.subservicesWatcher.WatchManager(f.subservices)
 
-	return errors.Wrap(services.StartAndAwaitRunning(ctx, f.schedulerWorkers), "failed to start frontend scheduler workers")
+	return errors.Wrap(services.StartManagerAndAwaitHealthy(ctx, f.subservices), "failed to start frontend subservices")
 }
 
 func (f *Frontend) running(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
 		return nil
-	case err := <-f.schedulerWorkersWatcher.Chan():
+	case err := <-f.subservicesWatcher.Chan():
 		return errors.Wrap(err, "query-frontend subservice failed")
 	}
 }
 
 func (f *Frontend) stopping(_ error) error {
-	return errors.Wrap(services.StopAndAwaitTerminated(context.Background(), f.schedulerWorkers), "failed to stop frontend scheduler workers")
+	return errors.Wrap(services.StopManagerAndAwaitStopped(context.Background(), f.subservices), "failed to stop frontend subservices")
 }
 
 // RoundTripGRPC round trips a proto (instead of an HTTP request).
@@ -214,6 +227,7 @@ func (f *Frontend) RoundTripGRPC(ctx context.Context, req *httpgrpc.HTTPRequest)
 		request:      req,
 		userID:       userID,
 		statsEnabled: stats.IsEnabled(ctx),
+		activeQuery:  activequeries.FromContext(ctx),
 
 		ctx:    ctx,
 		cancel: cancel,
@@ -227,6 +241,9 @@ func (f *Frontend) RoundTripGRPC(ctx context.Context, req *httpgrpc.HTTPRequest)
 	f.requests.put(freq)
 	defer f.requests.delete(freq.queryID)
 
+	freq.activeQuery.SubqueryEnqueued(freq.queryID)
+	defer freq.activeQuery.SubqueryDone(freq.queryID)
+
 	retries := f.cfg.WorkerConcurrency + 1 // To make sure we hit at least two different schedulers.
 
 enqueueAgain:
@@ -276,6 +293,7 @@ enqueueAgain:
 
 	case resp := <-freq.response:
 		level.Debug(spanLogger).Log("msg", "received response")
+		freq.activeQuery.SubqueryExecuted(resp.QuerierID)
 
 		if stats.ShouldTrackHTTPGRPCResponse(resp.HttpResponse) {
 			stats := stats.FromContext(ctx)
@@ -7,7 +7,9 @@ package v2
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/backoff"
 	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/multierror"
 	"github.com/grafana/dskit/services"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -194,6 +197,40 @@ func (f *frontendSchedulerWorkers) getWorkersCount() int {
 	return len(f.workers)
 }
 
+// getConnectedFrontends returns the addresses of the other query-frontends connected to the query-schedulers.
+// It returns an error listing the query-schedulers which couldn't be reached, along with the query-frontends
+// connected to the other ones.
+func (f *frontendSchedulerWorkers) getConnectedFrontends(ctx context.Context) ([]string, error) {
+	f.mu.Lock()
+	workers := make([]*frontendSchedulerWorker, 0, len(f.workers))
+	for _, w := range f.workers {
+		workers = append(workers, w)
+	}
+	f.mu.Unlock()
+
+	var errs multierror.MultiError
+	unique := map[string]struct{}{}
+	for _, w := range workers {
+		resp, err := schedulerpb.NewSchedulerForFrontendClient(w.conn).ConnectedFrontends(ctx, &schedulerpb.ConnectedFrontendsRequest{})
+		if err != nil {
+			errs.Add(fmt.Errorf("query-scheduler %s: %w", w.schedulerAddr, err))
+			continue
+		}
+		for _, addr := range resp.Addresses {
+			if addr != f.frontendAddress {
+				unique[addr] = struct{}{}
+			}
+		}
+	}
+
+	addresses := make([]string, 0, len(unique))
+	for addr := range unique {
+		addresses = append(addresses, addr)
+	}
+	sort.Strings(addresses)
+	return addresses, errs.Err()
+}
+
 func (f *frontendSchedulerWorkers) connectToScheduler(ctx context.Context, address string) (*grpc.ClientConn, error) {
 	// Because we only use single long-running method, it doesn't make sense to inject user ID, send over tracing or add metrics.
 	opts, err := f.cfg.GRPCClientConfig.DialOption(nil, nil)
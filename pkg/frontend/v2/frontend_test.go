@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/dskit/flagext"
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/metrics"
+	"github.com/grafana/dskit/middleware"
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/test"
 	"github.com/grafana/dskit/user"
@@ -33,6 +34,7 @@ import (
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/grafana/mimir/pkg/frontend/activequeries"
 	"github.com/grafana/mimir/pkg/frontend/v2/frontendv2pb"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/scheduler/schedulerdiscovery"
@@ -135,6 +137,97 @@ func TestFrontendBasicWorkflow(t *testing.T) {
 	require.Equal(t, []byte(body), resp.Body)
 }
 
+func TestFrontend_ActiveQuerySubqueries(t *testing.T) {
+	const userID = "test"
+
+	tracker := activequeries.NewTracker()
+	var infos []activequeries.Info
+
+	f, _ := setupFrontend(t, nil, func(f *Frontend, msg *schedulerpb.FrontendToScheduler) *schedulerpb.SchedulerToFrontend {
+		infos = tracker.List(userID)
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = f.QueryResult(user.InjectOrgID(context.Background(), userID), &frontendv2pb.QueryResultRequest{
+				QueryID:      msg.QueryID,
+				HttpResponse: &httpgrpc.HTTPResponse{Code: 200},
+				Stats:        &stats.Stats{},
+				QuerierID:    "querier-1",
+			})
+		}()
+		return &schedulerpb.SchedulerToFrontend{Status: schedulerpb.OK}
+	})
+
+	_, ctx := tracker.Track(user.InjectOrgID(context.Background(), userID), http.MethodGet, "/api/v1/query", nil)
+	resp, err := f.RoundTripGRPC(ctx, &httpgrpc.HTTPRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(200), resp.Code)
+
+	// While the subquery was running, the active query reported it as outstanding.
+	require.Len(t, infos, 1)
+	assert.Equal(t, 1, infos[0].OutstandingSubqueries)
+	assert.Empty(t, infos[0].Queriers)
+
+	// The querier which executed the subquery is reported with its result.
+	infos = tracker.List(userID)
+	require.Len(t, infos, 1)
+	assert.Equal(t, 0, infos[0].OutstandingSubqueries)
+	assert.Equal(t, []string{"querier-1"}, infos[0].Queriers)
+}
+
+func TestFrontend_ActiveQueriesPeers(t *testing.T) {
+	f, ms := setupFrontend(t, nil, nil)
+
+	// Run another frontend, with an active query of the tenant.
+	peerTracker := activequeries.NewTracker()
+	peerQuery, peerCtx := peerTracker.Track(user.InjectOrgID(context.Background(), "user-1"), http.MethodGet, "/api/v1/query_range", nil)
+	defer peerTracker.Done(peerQuery)
+
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.UnaryInterceptor(middleware.ServerUserHeaderInterceptor))
+	frontendv2pb.RegisterFrontendForFrontendServer(server, peerTracker)
+	go func() {
+		_ = server.Serve(l)
+	}()
+	t.Cleanup(server.GracefulStop)
+
+	// The queries of the reachable frontends are returned, along with an error for the unreachable ones.
+	ms.mu.Lock()
+	ms.peers = []string{l.Addr().String(), "localhost:1"}
+	ms.mu.Unlock()
+
+	queries, err := f.ActiveQueries(user.InjectOrgID(context.Background(), "user-1"))
+	require.ErrorContains(t, err, "query-frontend localhost:1")
+	require.Len(t, queries, 1)
+	assert.Equal(t, "/api/v1/query_range", queries[0].Path)
+
+	id := queries[0].ID
+
+	ms.mu.Lock()
+	ms.peers = []string{l.Addr().String()}
+	ms.mu.Unlock()
+
+	queries, err = f.ActiveQueries(user.InjectOrgID(context.Background(), "user-2"))
+	require.NoError(t, err)
+	require.Empty(t, queries)
+
+	// The query can't be cancelled by another tenant.
+	cancelled, err := f.CancelActiveQuery(user.InjectOrgID(context.Background(), "user-2"), id)
+	require.NoError(t, err)
+	require.False(t, cancelled)
+	require.NoError(t, peerCtx.Err())
+
+	cancelled, err = f.CancelActiveQuery(user.InjectOrgID(context.Background(), "user-1"), id)
+	require.NoError(t, err)
+	require.True(t, cancelled)
+	require.ErrorIs(t, context.Cause(peerCtx), activequeries.ErrQueryCancelled)
+
+	// The client of the reachable frontend is reused across the calls, while the one of the unreachable frontend
+	// has been removed from the pool.
+	assert.Equal(t, []string{l.Addr().String()}, f.peersPool.RegisteredAddresses())
+}
+
 func TestFrontend_ShouldTrackPerRequestMetrics(t *testing.T) {
 	const (
 		body   = "all fine here"
@@ -350,6 +443,9 @@ type mockScheduler struct {
 	mu           sync.Mutex
 	frontendAddr map[string]int
 	msgs         []*schedulerpb.FrontendToScheduler
+
+	// Addresses of other frontends returned by ConnectedFrontends.
+	peers []string
 }
 
 func newMockScheduler(t *testing.T, f *Frontend, replyFunc func(f *Frontend, msg *schedulerpb.FrontendToScheduler) *schedulerpb.SchedulerToFrontend) *mockScheduler {
@@ -399,6 +495,17 @@ func (m *mockScheduler) FrontendLoop(frontend schedulerpb.SchedulerForFrontend_F
 	}
 }
 
+func (m *mockScheduler) ConnectedFrontends(_ context.Context, _ *schedulerpb.ConnectedFrontendsRequest) (*schedulerpb.ConnectedFrontendsResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	resp := &schedulerpb.ConnectedFrontendsResponse{Addresses: append([]string(nil), m.peers...)}
+	for addr := range m.frontendAddr {
+		resp.Addresses = append(resp.Addresses, addr)
+	}
+	return resp, nil
+}
+
 func TestConfig_Validate(t *testing.T) {
 	tests := map[string]struct {
 		setup       func(cfg *Config)
@@ -8,6 +8,7 @@ import (
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
 	httpgrpc "github.com/grafana/dskit/httpgrpc"
 	stats "github.com/grafana/mimir/pkg/querier/stats"
 	grpc "google.golang.org/grpc"
@@ -35,6 +36,8 @@ type QueryResultRequest struct {
 	QueryID      uint64                 `protobuf:"varint,1,opt,name=queryID,proto3" json:"queryID,omitempty"`
 	HttpResponse *httpgrpc.HTTPResponse `protobuf:"bytes,2,opt,name=httpResponse,proto3" json:"httpResponse,omitempty"`
 	Stats        *stats.Stats           `protobuf:"bytes,3,opt,name=stats,proto3" json:"stats,omitempty"`
+	// The ID of the querier which executed the query.
+	QuerierID string `protobuf:"bytes,4,opt,name=querierID,proto3" json:"querierID,omitempty"`
 }
 
 func (m *QueryResultRequest) Reset()      { *m = QueryResultRequest{} }
@@ -90,6 +93,13 @@ func (m *QueryResultRequest) GetStats() *stats.Stats {
 	return nil
 }
 
+func (m *QueryResultRequest) GetQuerierID() string {
+	if m != nil {
+		return m.QuerierID
+	}
+	return ""
+}
+
 type QueryResultResponse struct {
 }
 
@@ -125,36 +135,326 @@ func (m *QueryResultResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_QueryResultResponse proto.InternalMessageInfo
 
+type ActiveQueriesRequest struct {
+}
+
+func (m *ActiveQueriesRequest) Reset()      { *m = ActiveQueriesRequest{} }
+func (*ActiveQueriesRequest) ProtoMessage() {}
+func (*ActiveQueriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_eca3873955a29cfe, []int{2}
+}
+func (m *ActiveQueriesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ActiveQueriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ActiveQueriesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ActiveQueriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActiveQueriesRequest.Merge(m, src)
+}
+func (m *ActiveQueriesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ActiveQueriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActiveQueriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActiveQueriesRequest proto.InternalMessageInfo
+
+type ActiveQueriesResponse struct {
+	Queries []ActiveQuery `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries"`
+}
+
+func (m *ActiveQueriesResponse) Reset()      { *m = ActiveQueriesResponse{} }
+func (*ActiveQueriesResponse) ProtoMessage() {}
+func (*ActiveQueriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_eca3873955a29cfe, []int{3}
+}
+func (m *ActiveQueriesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ActiveQueriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ActiveQueriesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ActiveQueriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActiveQueriesResponse.Merge(m, src)
+}
+func (m *ActiveQueriesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ActiveQueriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActiveQueriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActiveQueriesResponse proto.InternalMessageInfo
+
+func (m *ActiveQueriesResponse) GetQueries() []ActiveQuery {
+	if m != nil {
+		return m.Queries
+	}
+	return nil
+}
+
+type ActiveQuery struct {
+	Id                    string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserID                string            `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	Method                string            `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Path                  string            `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	Params                map[string]string `protobuf:"bytes,5,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	StartTimestampMs      int64             `protobuf:"varint,6,opt,name=startTimestampMs,proto3" json:"startTimestampMs,omitempty"`
+	OutstandingSubqueries uint32            `protobuf:"varint,7,opt,name=outstandingSubqueries,proto3" json:"outstandingSubqueries,omitempty"`
+	Queriers              []string          `protobuf:"bytes,8,rep,name=queriers,proto3" json:"queriers,omitempty"`
+}
+
+func (m *ActiveQuery) Reset()      { *m = ActiveQuery{} }
+func (*ActiveQuery) ProtoMessage() {}
+func (*ActiveQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_eca3873955a29cfe, []int{4}
+}
+func (m *ActiveQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ActiveQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ActiveQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ActiveQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActiveQuery.Merge(m, src)
+}
+func (m *ActiveQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *ActiveQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActiveQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActiveQuery proto.InternalMessageInfo
+
+func (m *ActiveQuery) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ActiveQuery) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *ActiveQuery) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *ActiveQuery) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ActiveQuery) GetParams() map[string]string {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *ActiveQuery) GetStartTimestampMs() int64 {
+	if m != nil {
+		return m.StartTimestampMs
+	}
+	return 0
+}
+
+func (m *ActiveQuery) GetOutstandingSubqueries() uint32 {
+	if m != nil {
+		return m.OutstandingSubqueries
+	}
+	return 0
+}
+
+func (m *ActiveQuery) GetQueriers() []string {
+	if m != nil {
+		return m.Queriers
+	}
+	return nil
+}
+
+type CancelActiveQueryRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *CancelActiveQueryRequest) Reset()      { *m = CancelActiveQueryRequest{} }
+func (*CancelActiveQueryRequest) ProtoMessage() {}
+func (*CancelActiveQueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_eca3873955a29cfe, []int{5}
+}
+func (m *CancelActiveQueryRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CancelActiveQueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CancelActiveQueryRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CancelActiveQueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelActiveQueryRequest.Merge(m, src)
+}
+func (m *CancelActiveQueryRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CancelActiveQueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelActiveQueryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelActiveQueryRequest proto.InternalMessageInfo
+
+func (m *CancelActiveQueryRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type CancelActiveQueryResponse struct {
+	// Whether the query was found and cancelled.
+	Cancelled bool `protobuf:"varint,1,opt,name=cancelled,proto3" json:"cancelled,omitempty"`
+}
+
+func (m *CancelActiveQueryResponse) Reset()      { *m = CancelActiveQueryResponse{} }
+func (*CancelActiveQueryResponse) ProtoMessage() {}
+func (*CancelActiveQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_eca3873955a29cfe, []int{6}
+}
+func (m *CancelActiveQueryResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CancelActiveQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CancelActiveQueryResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CancelActiveQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelActiveQueryResponse.Merge(m, src)
+}
+func (m *CancelActiveQueryResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CancelActiveQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelActiveQueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelActiveQueryResponse proto.InternalMessageInfo
+
+func (m *CancelActiveQueryResponse) GetCancelled() bool {
+	if m != nil {
+		return m.Cancelled
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*QueryResultRequest)(nil), "frontendv2pb.QueryResultRequest")
 	proto.RegisterType((*QueryResultResponse)(nil), "frontendv2pb.QueryResultResponse")
+	proto.RegisterType((*ActiveQueriesRequest)(nil), "frontendv2pb.ActiveQueriesRequest")
+	proto.RegisterType((*ActiveQueriesResponse)(nil), "frontendv2pb.ActiveQueriesResponse")
+	proto.RegisterType((*ActiveQuery)(nil), "frontendv2pb.ActiveQuery")
+	proto.RegisterMapType((map[string]string)(nil), "frontendv2pb.ActiveQuery.ParamsEntry")
+	proto.RegisterType((*CancelActiveQueryRequest)(nil), "frontendv2pb.CancelActiveQueryRequest")
+	proto.RegisterType((*CancelActiveQueryResponse)(nil), "frontendv2pb.CancelActiveQueryResponse")
 }
 
 func init() { proto.RegisterFile("frontend.proto", fileDescriptor_eca3873955a29cfe) }
 
 var fileDescriptor_eca3873955a29cfe = []byte{
-	// 333 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0xbf, 0x4e, 0x02, 0x41,
-	0x10, 0xc6, 0x77, 0xfd, 0x9b, 0x2c, 0xc4, 0x62, 0xfd, 0x93, 0x0b, 0xc5, 0x04, 0xaf, 0xa2, 0xba,
-	0x35, 0x98, 0x58, 0x58, 0x12, 0x43, 0xb4, 0x93, 0x93, 0xca, 0xee, 0x80, 0xe5, 0x38, 0x91, 0xdb,
-	0x63, 0x77, 0xcf, 0x84, 0xce, 0x27, 0x30, 0x3e, 0x86, 0x8f, 0x62, 0x49, 0x49, 0x29, 0x4b, 0x63,
-	0xc9, 0x23, 0x98, 0xbb, 0x05, 0xc2, 0x45, 0x63, 0xb3, 0x99, 0xc9, 0x7c, 0xbf, 0xcc, 0x37, 0xdf,
-	0x92, 0xa3, 0xbe, 0x14, 0xb1, 0xe6, 0x71, 0xcf, 0x4b, 0xa4, 0xd0, 0x82, 0x96, 0xd7, 0xfd, 0x4b,
-	0x3d, 0xe9, 0x54, 0x4e, 0x42, 0x11, 0x8a, 0x7c, 0xc0, 0xb2, 0xca, 0x6a, 0x2a, 0x17, 0x61, 0xa4,
-	0x07, 0x69, 0xc7, 0xeb, 0x8a, 0x11, 0x0b, 0x65, 0xd0, 0x0f, 0xe2, 0x80, 0xf5, 0xd4, 0x30, 0xd2,
-	0x6c, 0xa0, 0x75, 0x12, 0xca, 0xa4, 0xbb, 0x29, 0x56, 0xc4, 0xd5, 0x1f, 0xc4, 0x28, 0x1a, 0x45,
-	0x92, 0x25, 0xc3, 0x90, 0x8d, 0x53, 0x2e, 0x23, 0x2e, 0x99, 0xd2, 0x81, 0x56, 0xf6, 0xb5, 0x9c,
-	0xfb, 0x86, 0x09, 0x6d, 0xa5, 0x5c, 0x4e, 0x7c, 0xae, 0xd2, 0x67, 0xed, 0xf3, 0x71, 0xca, 0x95,
-	0xa6, 0x0e, 0x39, 0xcc, 0x98, 0xc9, 0xdd, 0x8d, 0x83, 0xab, 0xb8, 0xb6, 0xe7, 0xaf, 0x5b, 0x7a,
-	0x4d, 0xca, 0xd9, 0x6a, 0x9f, 0xab, 0x44, 0xc4, 0x8a, 0x3b, 0x3b, 0x55, 0x5c, 0x2b, 0xd5, 0xcf,
-	0xbc, 0x8d, 0x9f, 0xdb, 0x76, 0xfb, 0x7e, 0x3d, 0xf5, 0x0b, 0x5a, 0xea, 0x92, 0xfd, 0x7c, 0xb7,
-	0xb3, 0x9b, 0x43, 0x65, 0xcf, 0x3a, 0x79, 0xc8, 0x5e, 0xdf, 0x8e, 0xdc, 0x53, 0x72, 0x5c, 0xf0,
-	0x63, 0xd1, 0xfa, 0x13, 0xa1, 0xcd, 0x55, 0x6e, 0x4d, 0x21, 0x5b, 0xf6, 0x1e, 0xda, 0x26, 0xa5,
-	0x2d, 0x31, 0xad, 0x7a, 0xdb, 0xd9, 0x7a, 0xbf, 0xef, 0xaa, 0x9c, 0xff, 0xa3, 0xb0, 0x9b, 0x5c,
-	0xd4, 0x68, 0x4c, 0xe7, 0x80, 0x66, 0x73, 0x40, 0xcb, 0x39, 0xe0, 0x57, 0x03, 0xf8, 0xc3, 0x00,
-	0xfe, 0x34, 0x80, 0xa7, 0x06, 0xf0, 0x97, 0x01, 0xfc, 0x6d, 0x00, 0x2d, 0x0d, 0xe0, 0xf7, 0x05,
-	0xa0, 0xe9, 0x02, 0xd0, 0x6c, 0x01, 0xe8, 0xb1, 0xf0, 0xaf, 0x9d, 0x83, 0x3c, 0xde, 0xcb, 0x9f,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xef, 0x5d, 0x91, 0xa7, 0xfe, 0x01, 0x00, 0x00,
+	// 644 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0xcb, 0x4e, 0xdb, 0x40,
+	0x14, 0xf5, 0x24, 0x21, 0x90, 0x1b, 0x40, 0x74, 0x78, 0xc8, 0x58, 0xc8, 0x75, 0x5d, 0xb5, 0xb5,
+	0x58, 0xd8, 0x55, 0x5a, 0x55, 0x05, 0xa9, 0x8b, 0x52, 0x8a, 0xca, 0xa2, 0x12, 0x0c, 0x59, 0xb1,
+	0x73, 0x92, 0xc1, 0x71, 0x89, 0x1f, 0xcc, 0x8c, 0x91, 0xb2, 0xeb, 0x27, 0xf4, 0x33, 0xba, 0xe8,
+	0x87, 0xb0, 0x64, 0x09, 0x9b, 0xaa, 0x98, 0x4d, 0x97, 0x7c, 0x42, 0x15, 0x7b, 0x4c, 0x12, 0x20,
+	0x74, 0x63, 0xdd, 0xc7, 0x39, 0xb9, 0xe7, 0x1e, 0xdf, 0x18, 0xe6, 0x8f, 0x58, 0x14, 0x0a, 0x1a,
+	0x76, 0xec, 0x98, 0x45, 0x22, 0xc2, 0xb3, 0x45, 0x7e, 0xda, 0x88, 0x5b, 0xda, 0x92, 0x17, 0x79,
+	0x51, 0xd6, 0x70, 0x06, 0x51, 0x8e, 0xd1, 0x5e, 0x7b, 0xbe, 0xe8, 0x26, 0x2d, 0xbb, 0x1d, 0x05,
+	0x8e, 0xc7, 0xdc, 0x23, 0x37, 0x74, 0x9d, 0x0e, 0x3f, 0xf6, 0x85, 0xd3, 0x15, 0x22, 0xf6, 0x58,
+	0xdc, 0xbe, 0x0d, 0x24, 0xe3, 0xdd, 0x03, 0x8c, 0xc0, 0x0f, 0x7c, 0xe6, 0xc4, 0xc7, 0x9e, 0x73,
+	0x92, 0x50, 0xe6, 0x53, 0xe6, 0x70, 0xe1, 0x0a, 0x9e, 0x3f, 0x73, 0x9e, 0xf9, 0x0b, 0x01, 0xde,
+	0x4f, 0x28, 0xeb, 0x13, 0xca, 0x93, 0x9e, 0x20, 0xf4, 0x24, 0xa1, 0x5c, 0x60, 0x15, 0xa6, 0x07,
+	0x9c, 0xfe, 0xee, 0xb6, 0x8a, 0x0c, 0x64, 0x55, 0x48, 0x91, 0xe2, 0x4d, 0x98, 0x1d, 0x8c, 0x26,
+	0x94, 0xc7, 0x51, 0xc8, 0xa9, 0x5a, 0x32, 0x90, 0x55, 0x6f, 0xac, 0xd8, 0xb7, 0x7a, 0xbe, 0x34,
+	0x9b, 0x7b, 0x45, 0x97, 0x8c, 0x61, 0xb1, 0x09, 0x53, 0xd9, 0x6c, 0xb5, 0x9c, 0x91, 0x66, 0xed,
+	0x5c, 0xc9, 0xc1, 0xe0, 0x49, 0xf2, 0x16, 0x5e, 0x83, 0x9a, 0x54, 0xbb, 0xbb, 0xad, 0x56, 0x0c,
+	0x64, 0xd5, 0xc8, 0xb0, 0x60, 0x2e, 0xc3, 0xe2, 0x98, 0xda, 0xfc, 0x87, 0xcd, 0x15, 0x58, 0xfa,
+	0xd8, 0x16, 0xfe, 0x29, 0xdd, 0xcf, 0x90, 0x5c, 0xae, 0x61, 0x12, 0x58, 0xbe, 0x53, 0x97, 0x4a,
+	0x36, 0xf2, 0xfd, 0x7c, 0xca, 0x55, 0x64, 0x94, 0xad, 0x7a, 0x63, 0xd5, 0x1e, 0x7d, 0x2d, 0xf6,
+	0x90, 0xd5, 0xdf, 0xaa, 0x9c, 0xfd, 0x7e, 0xaa, 0x90, 0x02, 0x6f, 0x5e, 0x96, 0xa0, 0x3e, 0xd2,
+	0xc6, 0xf3, 0x50, 0xf2, 0x3b, 0x99, 0x4b, 0x35, 0x52, 0xf2, 0x3b, 0x78, 0x05, 0xaa, 0x09, 0xcf,
+	0xd4, 0x97, 0xb2, 0x9a, 0xcc, 0x06, 0xf5, 0x80, 0x8a, 0x6e, 0xd4, 0xc9, 0xb6, 0xaf, 0x11, 0x99,
+	0x61, 0x0c, 0x95, 0xd8, 0x15, 0x5d, 0xb9, 0x6b, 0x16, 0xe3, 0x0f, 0x50, 0x8d, 0x5d, 0xe6, 0x06,
+	0x5c, 0x9d, 0xca, 0xd4, 0xbd, 0x98, 0xa8, 0xce, 0xde, 0xcb, 0x70, 0x9f, 0x43, 0xc1, 0xfa, 0x44,
+	0x92, 0xf0, 0x3a, 0x2c, 0x70, 0xe1, 0x32, 0xd1, 0xf4, 0x03, 0xca, 0x85, 0x1b, 0xc4, 0x5f, 0xb9,
+	0x5a, 0x35, 0x90, 0x55, 0x26, 0xf7, 0xea, 0xf8, 0x2d, 0x2c, 0x47, 0x89, 0xe0, 0xc2, 0x0d, 0x3b,
+	0x7e, 0xe8, 0x1d, 0x24, 0xad, 0xc2, 0x97, 0x69, 0x03, 0x59, 0x73, 0xe4, 0xe1, 0x26, 0xd6, 0x60,
+	0x26, 0x0f, 0x19, 0x57, 0x67, 0x8c, 0xb2, 0x55, 0x23, 0xb7, 0xb9, 0xb6, 0x01, 0xf5, 0x11, 0x51,
+	0x78, 0x01, 0xca, 0xc7, 0xb4, 0x2f, 0x0d, 0x1a, 0x84, 0x78, 0x09, 0xa6, 0x4e, 0xdd, 0x5e, 0x42,
+	0xa5, 0x41, 0x79, 0xb2, 0x59, 0x7a, 0x8f, 0xcc, 0x75, 0x50, 0x3f, 0xb9, 0x61, 0x9b, 0xf6, 0x46,
+	0x36, 0x2c, 0x4e, 0xf2, 0x8e, 0xcf, 0xe6, 0x06, 0xac, 0x3e, 0x80, 0x95, 0xef, 0x77, 0x0d, 0x6a,
+	0xed, 0xac, 0xd9, 0xa3, 0x39, 0x67, 0x86, 0x0c, 0x0b, 0x8d, 0x6f, 0x80, 0x77, 0xa4, 0x9f, 0x3b,
+	0x11, 0xcb, 0x6f, 0x83, 0xe1, 0x26, 0xd4, 0x47, 0x6e, 0x0b, 0x1b, 0xe3, 0x9e, 0xdf, 0xff, 0x93,
+	0x68, 0xcf, 0x1e, 0x41, 0xc8, 0xc3, 0x54, 0x1a, 0x97, 0x08, 0x16, 0x47, 0x86, 0x15, 0x21, 0x3e,
+	0x84, 0xb9, 0xb1, 0xd3, 0xc4, 0xe6, 0xa4, 0x77, 0x3c, 0xbc, 0x67, 0xed, 0xf9, 0xa3, 0x98, 0x62,
+	0x26, 0x3e, 0x82, 0x27, 0xf7, 0xac, 0xc1, 0x2f, 0xc7, 0xb9, 0x93, 0x7c, 0xd6, 0x5e, 0xfd, 0x17,
+	0x57, 0xcc, 0xd9, 0xda, 0x3a, 0xbf, 0xd2, 0x95, 0x8b, 0x2b, 0x5d, 0xb9, 0xb9, 0xd2, 0xd1, 0xf7,
+	0x54, 0x47, 0x3f, 0x53, 0x1d, 0x9d, 0xa5, 0x3a, 0x3a, 0x4f, 0x75, 0xf4, 0x27, 0xd5, 0xd1, 0xdf,
+	0x54, 0x57, 0x6e, 0x52, 0x1d, 0xfd, 0xb8, 0xd6, 0x95, 0xf3, 0x6b, 0x5d, 0xb9, 0xb8, 0xd6, 0x95,
+	0xc3, 0xb1, 0x0f, 0x60, 0xab, 0x9a, 0x7d, 0x87, 0xde, 0xfc, 0x1b, 0x00, 0x80, 0x1b, 0x3f, 0x27,
+	0x27, 0x05, 0x00, 0x00,
 }
 
 func (this *QueryResultRequest) Equal(that interface{}) bool {
@@ -185,6 +485,9 @@ func (this *QueryResultRequest) Equal(that interface{}) bool {
 	if !this.Stats.Equal(that1.Stats) {
 		return false
 	}
+	if this.QuerierID != that1.QuerierID {
+		return false
+	}
 	return true
 }
 func (this *QueryResultResponse) Equal(that interface{}) bool {
@@ -208,76 +511,304 @@ func (this *QueryResultResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
-func (this *QueryResultRequest) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 7)
-	s = append(s, "&frontendv2pb.QueryResultRequest{")
-	s = append(s, "QueryID: "+fmt.Sprintf("%#v", this.QueryID)+",\n")
-	if this.HttpResponse != nil {
-		s = append(s, "HttpResponse: "+fmt.Sprintf("%#v", this.HttpResponse)+",\n")
+func (this *ActiveQueriesRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if this.Stats != nil {
-		s = append(s, "Stats: "+fmt.Sprintf("%#v", this.Stats)+",\n")
+
+	that1, ok := that.(*ActiveQueriesRequest)
+	if !ok {
+		that2, ok := that.(ActiveQueriesRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *QueryResultResponse) GoString() string {
-	if this == nil {
-		return "nil"
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s := make([]string, 0, 4)
-	s = append(s, "&frontendv2pb.QueryResultResponse{")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return true
 }
-func valueToGoStringFrontend(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+func (this *ActiveQueriesResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
-}
-
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// FrontendForQuerierClient is the client API for FrontendForQuerier service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type FrontendForQuerierClient interface {
-	QueryResult(ctx context.Context, in *QueryResultRequest, opts ...grpc.CallOption) (*QueryResultResponse, error)
-}
-
-type frontendForQuerierClient struct {
-	cc *grpc.ClientConn
-}
-
-func NewFrontendForQuerierClient(cc *grpc.ClientConn) FrontendForQuerierClient {
-	return &frontendForQuerierClient{cc}
-}
 
-func (c *frontendForQuerierClient) QueryResult(ctx context.Context, in *QueryResultRequest, opts ...grpc.CallOption) (*QueryResultResponse, error) {
-	out := new(QueryResultResponse)
-	err := c.cc.Invoke(ctx, "/frontendv2pb.FrontendForQuerier/QueryResult", in, out, opts...)
-	if err != nil {
-		return nil, err
+	that1, ok := that.(*ActiveQueriesResponse)
+	if !ok {
+		that2, ok := that.(ActiveQueriesResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return out, nil
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Queries) != len(that1.Queries) {
+		return false
+	}
+	for i := range this.Queries {
+		if !this.Queries[i].Equal(&that1.Queries[i]) {
+			return false
+		}
+	}
+	return true
 }
+func (this *ActiveQuery) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-// FrontendForQuerierServer is the server API for FrontendForQuerier service.
-type FrontendForQuerierServer interface {
-	QueryResult(context.Context, *QueryResultRequest) (*QueryResultResponse, error)
-}
+	that1, ok := that.(*ActiveQuery)
+	if !ok {
+		that2, ok := that.(ActiveQuery)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if this.UserID != that1.UserID {
+		return false
+	}
+	if this.Method != that1.Method {
+		return false
+	}
+	if this.Path != that1.Path {
+		return false
+	}
+	if len(this.Params) != len(that1.Params) {
+		return false
+	}
+	for i := range this.Params {
+		if this.Params[i] != that1.Params[i] {
+			return false
+		}
+	}
+	if this.StartTimestampMs != that1.StartTimestampMs {
+		return false
+	}
+	if this.OutstandingSubqueries != that1.OutstandingSubqueries {
+		return false
+	}
+	if len(this.Queriers) != len(that1.Queriers) {
+		return false
+	}
+	for i := range this.Queriers {
+		if this.Queriers[i] != that1.Queriers[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *CancelActiveQueryRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CancelActiveQueryRequest)
+	if !ok {
+		that2, ok := that.(CancelActiveQueryRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	return true
+}
+func (this *CancelActiveQueryResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CancelActiveQueryResponse)
+	if !ok {
+		that2, ok := that.(CancelActiveQueryResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Cancelled != that1.Cancelled {
+		return false
+	}
+	return true
+}
+func (this *QueryResultRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&frontendv2pb.QueryResultRequest{")
+	s = append(s, "QueryID: "+fmt.Sprintf("%#v", this.QueryID)+",\n")
+	if this.HttpResponse != nil {
+		s = append(s, "HttpResponse: "+fmt.Sprintf("%#v", this.HttpResponse)+",\n")
+	}
+	if this.Stats != nil {
+		s = append(s, "Stats: "+fmt.Sprintf("%#v", this.Stats)+",\n")
+	}
+	s = append(s, "QuerierID: "+fmt.Sprintf("%#v", this.QuerierID)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *QueryResultResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&frontendv2pb.QueryResultResponse{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ActiveQueriesRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&frontendv2pb.ActiveQueriesRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ActiveQueriesResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&frontendv2pb.ActiveQueriesResponse{")
+	if this.Queries != nil {
+		vs := make([]*ActiveQuery, len(this.Queries))
+		for i := range vs {
+			vs[i] = &this.Queries[i]
+		}
+		s = append(s, "Queries: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ActiveQuery) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 12)
+	s = append(s, "&frontendv2pb.ActiveQuery{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "UserID: "+fmt.Sprintf("%#v", this.UserID)+",\n")
+	s = append(s, "Method: "+fmt.Sprintf("%#v", this.Method)+",\n")
+	s = append(s, "Path: "+fmt.Sprintf("%#v", this.Path)+",\n")
+	keysForParams := make([]string, 0, len(this.Params))
+	for k, _ := range this.Params {
+		keysForParams = append(keysForParams, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForParams)
+	mapStringForParams := "map[string]string{"
+	for _, k := range keysForParams {
+		mapStringForParams += fmt.Sprintf("%#v: %#v,", k, this.Params[k])
+	}
+	mapStringForParams += "}"
+	if this.Params != nil {
+		s = append(s, "Params: "+mapStringForParams+",\n")
+	}
+	s = append(s, "StartTimestampMs: "+fmt.Sprintf("%#v", this.StartTimestampMs)+",\n")
+	s = append(s, "OutstandingSubqueries: "+fmt.Sprintf("%#v", this.OutstandingSubqueries)+",\n")
+	s = append(s, "Queriers: "+fmt.Sprintf("%#v", this.Queriers)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CancelActiveQueryRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&frontendv2pb.CancelActiveQueryRequest{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CancelActiveQueryResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&frontendv2pb.CancelActiveQueryResponse{")
+	s = append(s, "Cancelled: "+fmt.Sprintf("%#v", this.Cancelled)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringFrontend(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// FrontendForQuerierClient is the client API for FrontendForQuerier service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FrontendForQuerierClient interface {
+	QueryResult(ctx context.Context, in *QueryResultRequest, opts ...grpc.CallOption) (*QueryResultResponse, error)
+}
+
+type frontendForQuerierClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFrontendForQuerierClient(cc *grpc.ClientConn) FrontendForQuerierClient {
+	return &frontendForQuerierClient{cc}
+}
+
+func (c *frontendForQuerierClient) QueryResult(ctx context.Context, in *QueryResultRequest, opts ...grpc.CallOption) (*QueryResultResponse, error) {
+	out := new(QueryResultResponse)
+	err := c.cc.Invoke(ctx, "/frontendv2pb.FrontendForQuerier/QueryResult", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FrontendForQuerierServer is the server API for FrontendForQuerier service.
+type FrontendForQuerierServer interface {
+	QueryResult(context.Context, *QueryResultRequest) (*QueryResultResponse, error)
+}
 
 // UnimplementedFrontendForQuerierServer can be embedded to have forward compatible implementations.
 type UnimplementedFrontendForQuerierServer struct {
@@ -322,6 +853,114 @@ var _FrontendForQuerier_serviceDesc = grpc.ServiceDesc{
 	Metadata: "frontend.proto",
 }
 
+// FrontendForFrontendClient is the client API for FrontendForFrontend service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FrontendForFrontendClient interface {
+	ActiveQueries(ctx context.Context, in *ActiveQueriesRequest, opts ...grpc.CallOption) (*ActiveQueriesResponse, error)
+	CancelActiveQuery(ctx context.Context, in *CancelActiveQueryRequest, opts ...grpc.CallOption) (*CancelActiveQueryResponse, error)
+}
+
+type frontendForFrontendClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFrontendForFrontendClient(cc *grpc.ClientConn) FrontendForFrontendClient {
+	return &frontendForFrontendClient{cc}
+}
+
+func (c *frontendForFrontendClient) ActiveQueries(ctx context.Context, in *ActiveQueriesRequest, opts ...grpc.CallOption) (*ActiveQueriesResponse, error) {
+	out := new(ActiveQueriesResponse)
+	err := c.cc.Invoke(ctx, "/frontendv2pb.FrontendForFrontend/ActiveQueries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *frontendForFrontendClient) CancelActiveQuery(ctx context.Context, in *CancelActiveQueryRequest, opts ...grpc.CallOption) (*CancelActiveQueryResponse, error) {
+	out := new(CancelActiveQueryResponse)
+	err := c.cc.Invoke(ctx, "/frontendv2pb.FrontendForFrontend/CancelActiveQuery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FrontendForFrontendServer is the server API for FrontendForFrontend service.
+type FrontendForFrontendServer interface {
+	ActiveQueries(context.Context, *ActiveQueriesRequest) (*ActiveQueriesResponse, error)
+	CancelActiveQuery(context.Context, *CancelActiveQueryRequest) (*CancelActiveQueryResponse, error)
+}
+
+// UnimplementedFrontendForFrontendServer can be embedded to have forward compatible implementations.
+type UnimplementedFrontendForFrontendServer struct {
+}
+
+func (*UnimplementedFrontendForFrontendServer) ActiveQueries(ctx context.Context, req *ActiveQueriesRequest) (*ActiveQueriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActiveQueries not implemented")
+}
+func (*UnimplementedFrontendForFrontendServer) CancelActiveQuery(ctx context.Context, req *CancelActiveQueryRequest) (*CancelActiveQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelActiveQuery not implemented")
+}
+
+func RegisterFrontendForFrontendServer(s *grpc.Server, srv FrontendForFrontendServer) {
+	s.RegisterService(&_FrontendForFrontend_serviceDesc, srv)
+}
+
+func _FrontendForFrontend_ActiveQueries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActiveQueriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendForFrontendServer).ActiveQueries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/frontendv2pb.FrontendForFrontend/ActiveQueries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendForFrontendServer).ActiveQueries(ctx, req.(*ActiveQueriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FrontendForFrontend_CancelActiveQuery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelActiveQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendForFrontendServer).CancelActiveQuery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/frontendv2pb.FrontendForFrontend/CancelActiveQuery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendForFrontendServer).CancelActiveQuery(ctx, req.(*CancelActiveQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _FrontendForFrontend_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "frontendv2pb.FrontendForFrontend",
+	HandlerType: (*FrontendForFrontendServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ActiveQueries",
+			Handler:    _FrontendForFrontend_ActiveQueries_Handler,
+		},
+		{
+			MethodName: "CancelActiveQuery",
+			Handler:    _FrontendForFrontend_CancelActiveQuery_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "frontend.proto",
+}
+
 func (m *QueryResultRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -342,6 +981,13 @@ func (m *QueryResultRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.QuerierID) > 0 {
+		i -= len(m.QuerierID)
+		copy(dAtA[i:], m.QuerierID)
+		i = encodeVarintFrontend(dAtA, i, uint64(len(m.QuerierID)))
+		i--
+		dAtA[i] = 0x22
+	}
 	if m.Stats != nil {
 		{
 			size, err := m.Stats.MarshalToSizedBuffer(dAtA[:i])
@@ -397,13 +1043,225 @@ func (m *QueryResultResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintFrontend(dAtA []byte, offset int, v uint64) int {
-	offset -= sovFrontend(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ActiveQueriesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ActiveQueriesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ActiveQueriesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *ActiveQueriesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ActiveQueriesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ActiveQueriesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Queries) > 0 {
+		for iNdEx := len(m.Queries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Queries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFrontend(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ActiveQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ActiveQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ActiveQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Queriers) > 0 {
+		for iNdEx := len(m.Queriers) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Queriers[iNdEx])
+			copy(dAtA[i:], m.Queriers[iNdEx])
+			i = encodeVarintFrontend(dAtA, i, uint64(len(m.Queriers[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.OutstandingSubqueries != 0 {
+		i = encodeVarintFrontend(dAtA, i, uint64(m.OutstandingSubqueries))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.StartTimestampMs != 0 {
+		i = encodeVarintFrontend(dAtA, i, uint64(m.StartTimestampMs))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Params) > 0 {
+		for k := range m.Params {
+			v := m.Params[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintFrontend(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintFrontend(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintFrontend(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintFrontend(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Method) > 0 {
+		i -= len(m.Method)
+		copy(dAtA[i:], m.Method)
+		i = encodeVarintFrontend(dAtA, i, uint64(len(m.Method)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.UserID) > 0 {
+		i -= len(m.UserID)
+		copy(dAtA[i:], m.UserID)
+		i = encodeVarintFrontend(dAtA, i, uint64(len(m.UserID)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintFrontend(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CancelActiveQueryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CancelActiveQueryRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CancelActiveQueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintFrontend(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CancelActiveQueryResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CancelActiveQueryResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CancelActiveQueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Cancelled {
+		i--
+		if m.Cancelled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintFrontend(dAtA []byte, offset int, v uint64) int {
+	offset -= sovFrontend(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
 	}
 	dAtA[offset] = uint8(v)
 	return base
@@ -425,6 +1283,10 @@ func (m *QueryResultRequest) Size() (n int) {
 		l = m.Stats.Size()
 		n += 1 + l + sovFrontend(uint64(l))
 	}
+	l = len(m.QuerierID)
+	if l > 0 {
+		n += 1 + l + sovFrontend(uint64(l))
+	}
 	return n
 }
 
@@ -437,6 +1299,100 @@ func (m *QueryResultResponse) Size() (n int) {
 	return n
 }
 
+func (m *ActiveQueriesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *ActiveQueriesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Queries) > 0 {
+		for _, e := range m.Queries {
+			l = e.Size()
+			n += 1 + l + sovFrontend(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ActiveQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovFrontend(uint64(l))
+	}
+	l = len(m.UserID)
+	if l > 0 {
+		n += 1 + l + sovFrontend(uint64(l))
+	}
+	l = len(m.Method)
+	if l > 0 {
+		n += 1 + l + sovFrontend(uint64(l))
+	}
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovFrontend(uint64(l))
+	}
+	if len(m.Params) > 0 {
+		for k, v := range m.Params {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovFrontend(uint64(len(k))) + 1 + len(v) + sovFrontend(uint64(len(v)))
+			n += mapEntrySize + 1 + sovFrontend(uint64(mapEntrySize))
+		}
+	}
+	if m.StartTimestampMs != 0 {
+		n += 1 + sovFrontend(uint64(m.StartTimestampMs))
+	}
+	if m.OutstandingSubqueries != 0 {
+		n += 1 + sovFrontend(uint64(m.OutstandingSubqueries))
+	}
+	if len(m.Queriers) > 0 {
+		for _, s := range m.Queriers {
+			l = len(s)
+			n += 1 + l + sovFrontend(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *CancelActiveQueryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovFrontend(uint64(l))
+	}
+	return n
+}
+
+func (m *CancelActiveQueryResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Cancelled {
+		n += 2
+	}
+	return n
+}
+
 func sovFrontend(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -451,6 +1407,7 @@ func (this *QueryResultRequest) String() string {
 		`QueryID:` + fmt.Sprintf("%v", this.QueryID) + `,`,
 		`HttpResponse:` + strings.Replace(fmt.Sprintf("%v", this.HttpResponse), "HTTPResponse", "httpgrpc.HTTPResponse", 1) + `,`,
 		`Stats:` + strings.Replace(fmt.Sprintf("%v", this.Stats), "Stats", "stats.Stats", 1) + `,`,
+		`QuerierID:` + fmt.Sprintf("%v", this.QuerierID) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -464,15 +1421,455 @@ func (this *QueryResultResponse) String() string {
 	}, "")
 	return s
 }
+func (this *ActiveQueriesRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ActiveQueriesRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ActiveQueriesResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForQueries := "[]ActiveQuery{"
+	for _, f := range this.Queries {
+		repeatedStringForQueries += strings.Replace(strings.Replace(f.String(), "ActiveQuery", "ActiveQuery", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForQueries += "}"
+	s := strings.Join([]string{`&ActiveQueriesResponse{`,
+		`Queries:` + repeatedStringForQueries + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ActiveQuery) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForParams := make([]string, 0, len(this.Params))
+	for k, _ := range this.Params {
+		keysForParams = append(keysForParams, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForParams)
+	mapStringForParams := "map[string]string{"
+	for _, k := range keysForParams {
+		mapStringForParams += fmt.Sprintf("%v: %v,", k, this.Params[k])
+	}
+	mapStringForParams += "}"
+	s := strings.Join([]string{`&ActiveQuery{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`UserID:` + fmt.Sprintf("%v", this.UserID) + `,`,
+		`Method:` + fmt.Sprintf("%v", this.Method) + `,`,
+		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
+		`Params:` + mapStringForParams + `,`,
+		`StartTimestampMs:` + fmt.Sprintf("%v", this.StartTimestampMs) + `,`,
+		`OutstandingSubqueries:` + fmt.Sprintf("%v", this.OutstandingSubqueries) + `,`,
+		`Queriers:` + fmt.Sprintf("%v", this.Queriers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CancelActiveQueryRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CancelActiveQueryRequest{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CancelActiveQueryResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CancelActiveQueryResponse{`,
+		`Cancelled:` + fmt.Sprintf("%v", this.Cancelled) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringFrontend(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
 		return "nil"
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *QueryResultRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFrontend
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryResultRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryResultRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryID", wireType)
+			}
+			m.QueryID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.QueryID |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HttpResponse", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.HttpResponse == nil {
+				m.HttpResponse = &httpgrpc.HTTPResponse{}
+			}
+			if err := m.HttpResponse.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Stats == nil {
+				m.Stats = &stats.Stats{}
+			}
+			if err := m.Stats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuerierID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QuerierID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFrontend(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryResultResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFrontend
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryResultResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryResultResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFrontend(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ActiveQueriesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFrontend
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ActiveQueriesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ActiveQueriesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFrontend(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ActiveQueriesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFrontend
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ActiveQueriesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ActiveQueriesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Queries", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Queries = append(m.Queries, ActiveQuery{})
+			if err := m.Queries[len(m.Queries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFrontend(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *QueryResultRequest) Unmarshal(dAtA []byte) error {
+func (m *ActiveQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -495,17 +1892,17 @@ func (m *QueryResultRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryResultRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ActiveQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryResultRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ActiveQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QueryID", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
-			m.QueryID = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFrontend
@@ -515,16 +1912,29 @@ func (m *QueryResultRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.QueryID |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field HttpResponse", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field UserID", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFrontend
@@ -534,31 +1944,91 @@ func (m *QueryResultRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthFrontend
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthFrontend
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.HttpResponse == nil {
-				m.HttpResponse = &httpgrpc.HTTPResponse{}
+			m.UserID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Method", wireType)
 			}
-			if err := m.HttpResponse.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFrontend
 			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Method = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Stats", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Params", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -585,12 +2055,258 @@ func (m *QueryResultRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Stats == nil {
-				m.Stats = &stats.Stats{}
+			if m.Params == nil {
+				m.Params = make(map[string]string)
 			}
-			if err := m.Stats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFrontend
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowFrontend
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthFrontend
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthFrontend
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowFrontend
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthFrontend
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthFrontend
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipFrontend(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthFrontend
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Params[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimestampMs", wireType)
+			}
+			m.StartTimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OutstandingSubqueries", wireType)
+			}
+			m.OutstandingSubqueries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.OutstandingSubqueries |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Queriers", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Queriers = append(m.Queriers, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFrontend(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
+			if skippy < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CancelActiveQueryRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFrontend
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CancelActiveQueryRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CancelActiveQueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFrontend
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -616,7 +2332,7 @@ func (m *QueryResultRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryResultResponse) Unmarshal(dAtA []byte) error {
+func (m *CancelActiveQueryResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -639,12 +2355,32 @@ func (m *QueryResultResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryResultResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: CancelActiveQueryResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryResultResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CancelActiveQueryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cancelled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Cancelled = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFrontend(dAtA[iNdEx:])
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+
+	"github.com/grafana/mimir/pkg/ingester/activeseries"
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/sharding"
+)
+
+// activeSeriesTargetSizeBytes is the target size in bytes of each message of the active series response.
+const activeSeriesTargetSizeBytes = 1 * 1024 * 1024
+
+// listActiveSeries streams the label sets of the active series matching the `matchers` param. If the shard
+// is not nil, only the series belonging to the shard are listed. Messages are immediately sent as soon they
+// reach message size threshold defined in `messageSizeThreshold` param.
+func listActiveSeries(
+	idx tsdb.IndexReader,
+	active *activeseries.ActiveSeries,
+	matchers []*labels.Matcher,
+	shard *sharding.ShardSelector,
+	messageSizeThreshold int,
+	stream client.Ingester_ActiveSeriesServer,
+) error {
+	ctx := stream.Context()
+
+	postings, err := tsdb.PostingsForMatchers(ctx, idx, matchers...)
+	if err != nil {
+		return err
+	}
+	if shard != nil {
+		postings = idx.ShardedPostings(postings, shard.ShardIndex, shard.ShardCount)
+	}
+	postings = activeseries.NewPostings(active, postings)
+
+	var (
+		response     client.ActiveSeriesResponse
+		responseSize int
+		builder      labels.ScratchBuilder
+		count        int
+	)
+	for postings.Next() {
+		count++
+		if count%checkContextErrorSeriesCount == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		if err := idx.Series(postings.At(), &builder, nil); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				// The series has been removed from the head after the postings have been read.
+				continue
+			}
+			return err
+		}
+
+		m := &mimirpb.Metric{Labels: mimirpb.FromLabelsToLabelAdapters(builder.Labels())}
+		response.Metric = append(response.Metric, m)
+		responseSize += m.Size()
+		if responseSize >= messageSizeThreshold {
+			if err := client.SendActiveSeriesResponse(stream, &response); err != nil {
+				return err
+			}
+			response.Metric = response.Metric[:0]
+			responseSize = 0
+		}
+	}
+	if err := postings.Err(); err != nil {
+		return err
+	}
+
+	// Send response in case there are any pending series.
+	if len(response.Metric) > 0 {
+		return client.SendActiveSeriesResponse(stream, &response)
+	}
+	return nil
+}
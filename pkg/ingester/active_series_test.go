@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/sharding"
+)
+
+func TestIngester_ActiveSeries(t *testing.T) {
+	const numSeries = 50
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.ActiveSeriesMetrics.Enabled = true
+	i := requireActiveIngesterWithBlocksStorage(t, cfg, prometheus.NewRegistry())
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	now := time.Now()
+
+	// Push series which are no longer active, and purge them from the active series.
+	require.NoError(t, pushSeriesToIngester(ctx, t, i, []series{
+		{lbls: labels.FromStrings(labels.MetricName, "inactive"), value: 1, timestamp: now.UnixMilli()},
+		{lbls: labels.FromStrings(labels.MetricName, "metric", "pod", "inactive"), value: 1, timestamp: now.UnixMilli()},
+	}))
+	i.getTSDB("test").activeSeries.Purge(time.Now().Add(cfg.ActiveSeriesMetrics.IdleTimeout + time.Minute))
+
+	var expected []labels.Labels
+	for s := 0; s < numSeries; s++ {
+		lbls := labels.FromStrings(labels.MetricName, "metric", "pod", fmt.Sprintf("pod-%d", s))
+		expected = append(expected, lbls)
+		require.NoError(t, pushSeriesToIngester(ctx, t, i, []series{{lbls: lbls, value: 1, timestamp: now.UnixMilli()}}))
+	}
+	require.NoError(t, pushSeriesToIngester(ctx, t, i, []series{
+		{lbls: labels.FromStrings(labels.MetricName, "other"), value: 1, timestamp: now.UnixMilli()},
+	}))
+
+	t.Run("should return the active series matching the matchers", func(t *testing.T) {
+		actual := activeSeriesFromIngester(t, ctx, i, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "metric"))
+		assert.ElementsMatch(t, expected, actual)
+	})
+
+	t.Run("should return the active series of each shard", func(t *testing.T) {
+		const numShards = 4
+
+		var actual []labels.Labels
+		for shardIndex := uint64(0); shardIndex < numShards; shardIndex++ {
+			shard := sharding.ShardSelector{ShardIndex: shardIndex, ShardCount: numShards}
+			shardSeries := activeSeriesFromIngester(t, ctx, i, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "metric"), shard.Matcher())
+			for _, s := range shardSeries {
+				assert.Equal(t, shardIndex, labels.StableHash(s)%numShards)
+			}
+			actual = append(actual, shardSeries...)
+		}
+		assert.ElementsMatch(t, expected, actual)
+	})
+
+	t.Run("should not return anything if no active series match the matchers", func(t *testing.T) {
+		assert.Empty(t, activeSeriesFromIngester(t, ctx, i, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "inactive")))
+	})
+
+	t.Run("should not return anything for a tenant without TSDB", func(t *testing.T) {
+		assert.Empty(t, activeSeriesFromIngester(t, user.InjectOrgID(context.Background(), "unknown"), i, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "metric")))
+	})
+}
+
+func TestListActiveSeries_SentInBatches(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.ActiveSeriesMetrics.Enabled = true
+	i := requireActiveIngesterWithBlocksStorage(t, cfg, prometheus.NewRegistry())
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	for s := 0; s < 10; s++ {
+		lbls := labels.FromStrings(labels.MetricName, "metric", "pod", fmt.Sprintf("pod-%d", s))
+		require.NoError(t, pushSeriesToIngester(ctx, t, i, []series{{lbls: lbls, value: 1, timestamp: time.Now().UnixMilli()}}))
+	}
+
+	db := i.getTSDB("test")
+	idx, err := db.Head().Index()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, idx.Close()) })
+
+	// Each series is bigger than the message size threshold, so each one is sent in its own message.
+	server := &mockActiveSeriesServer{context: ctx}
+	require.NoError(t, listActiveSeries(idx, db.activeSeries, []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "metric")}, nil, 1, server))
+	require.Len(t, server.SentResponses, 10)
+	for _, resp := range server.SentResponses {
+		require.Len(t, resp.Metric, 1)
+	}
+}
+
+func activeSeriesFromIngester(t *testing.T, ctx context.Context, i *Ingester, matchers ...*labels.Matcher) []labels.Labels {
+	req, err := client.ToActiveSeriesRequest(matchers)
+	require.NoError(t, err)
+
+	server := &mockActiveSeriesServer{context: ctx}
+	require.NoError(t, i.ActiveSeries(req, server))
+
+	var series []labels.Labels
+	for _, resp := range server.SentResponses {
+		for _, m := range resp.Metric {
+			series = append(series, mimirpb.FromLabelAdaptersToLabels(m.Labels))
+		}
+	}
+	return series
+}
+
+type mockActiveSeriesServer struct {
+	client.Ingester_ActiveSeriesServer
+	SentResponses []client.ActiveSeriesResponse
+	context       context.Context
+}
+
+func (m *mockActiveSeriesServer) Send(resp *client.ActiveSeriesResponse) error {
+	// Copy the response, because the sender reuses it.
+	sent := client.ActiveSeriesResponse{Metric: make([]*mimirpb.Metric, 0, len(resp.Metric))}
+	for _, m := range resp.Metric {
+		sent.Metric = append(sent.Metric, &mimirpb.Metric{Labels: mimirpb.FromLabelsToLabelAdapters(mimirpb.FromLabelAdaptersToLabels(m.Labels).Copy())})
+	}
+	m.SentResponses = append(m.SentResponses, sent)
+	return nil
+}
+
+func (m *mockActiveSeriesServer) Context() context.Context {
+	return m.context
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/prometheus/prometheus/tsdb/chunks"
 	"github.com/prometheus/prometheus/util/zeropool"
 	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/costattribution"
 )
 
 const (
@@ -42,11 +44,15 @@ type ActiveSeries struct {
 	stripes [numStripes]seriesStripe
 	deleted deletedSeries
 
-	// matchersMutex protects matchers and lastMatchersUpdate.
+	// matchersMutex protects matchers, costAttribution, lastMatchersUpdate and trackingSince.
 	matchersMutex      sync.RWMutex
 	matchers           *Matchers
+	costAttribution    *costattribution.Tracker
 	lastMatchersUpdate time.Time
 
+	// The time since when all the updated series are tracked: the creation time, or the time of the last reload.
+	trackingSince time.Time
+
 	// The duration after which series become inactive.
 	// Also used to determine if enough time has passed since configuration reload for valid results.
 	timeout time.Duration
@@ -54,7 +60,8 @@ type ActiveSeries struct {
 
 // seriesStripe holds a subset of the series timestamps for a single tenant.
 type seriesStripe struct {
-	matchers *Matchers
+	matchers        *Matchers
+	costAttribution *costattribution.Tracker
 
 	deleted *deletedSeries
 
@@ -71,6 +78,9 @@ type seriesStripe struct {
 	activeMatchingNativeHistograms       []uint32 // Number of active entries (only native histograms) in this stripe matching each matcher of the configured Matchers.
 	activeNativeHistogramBuckets         uint32   // Number of buckets in active native histogram entries in this stripe. Only decreased during purge or clear.
 	activeMatchingNativeHistogramBuckets []uint32 // Number of buckets in active native histogram entries in this stripe matching each matcher of the configured Matchers.
+
+	// Number of active entries in this stripe by cost attribution key. Nil if cost attribution is disabled.
+	activeAttributed map[string]uint32
 }
 
 // seriesEntry holds a timestamp for single series.
@@ -78,16 +88,19 @@ type seriesEntry struct {
 	nanos                     *atomic.Int64        // Unix timestamp in nanoseconds. Needs to be a pointer because we don't store pointers to entries in the stripe.
 	matches                   preAllocDynamicSlice //  Index of the matcher matching
 	numNativeHistogramBuckets int                  // Number of buckets in native histogram series, -1 if not a native histogram.
+	attribution               string               // Cost attribution key of the series, empty if cost attribution is disabled.
 
 	deleted bool // This series was marked as deleted, so before purging we need to remove the refence to it from the deletedSeries.
 }
 
-func NewActiveSeries(asm *Matchers, timeout time.Duration) *ActiveSeries {
-	c := &ActiveSeries{matchers: asm, timeout: timeout}
+// NewActiveSeries creates a new ActiveSeries. The cost attribution tracker is optional: if not nil,
+// the active series are also tracked by cost attribution.
+func NewActiveSeries(asm *Matchers, cat *costattribution.Tracker, timeout time.Duration) *ActiveSeries {
+	c := &ActiveSeries{matchers: asm, costAttribution: cat, timeout: timeout, trackingSince: time.Now()}
 
 	// Stripes are pre-allocated so that we only read on them and no lock is required.
 	for i := 0; i < numStripes; i++ {
-		c.stripes[i].reinitialize(asm, &c.deleted)
+		c.stripes[i].reinitialize(asm, cat, &c.deleted)
 	}
 
 	return c
@@ -104,10 +117,31 @@ func (c *ActiveSeries) ReloadMatchers(asm *Matchers, now time.Time) {
 	defer c.matchersMutex.Unlock()
 
 	for i := 0; i < numStripes; i++ {
-		c.stripes[i].reinitialize(asm, &c.deleted)
+		c.stripes[i].reinitialize(asm, c.costAttribution, &c.deleted)
 	}
 	c.matchers = asm
 	c.lastMatchersUpdate = now
+	c.trackingSince = now
+}
+
+func (c *ActiveSeries) CurrentCostAttribution() *costattribution.Tracker {
+	c.matchersMutex.RLock()
+	defer c.matchersMutex.RUnlock()
+	return c.costAttribution
+}
+
+// ReloadCostAttribution replaces the cost attribution tracker. Like ReloadMatchers, it resets the
+// tracked series, so the active series are not valid until the timeout has passed.
+func (c *ActiveSeries) ReloadCostAttribution(cat *costattribution.Tracker, now time.Time) {
+	c.matchersMutex.Lock()
+	defer c.matchersMutex.Unlock()
+
+	for i := 0; i < numStripes; i++ {
+		c.stripes[i].reinitialize(c.matchers, cat, &c.deleted)
+	}
+	c.costAttribution = cat
+	c.lastMatchersUpdate = now
+	c.trackingSince = now
 }
 
 func (c *ActiveSeries) CurrentConfig() CustomTrackersConfig {
@@ -168,6 +202,28 @@ func (c *ActiveSeries) ContainsRef(ref storage.SeriesRef) bool {
 	return c.stripes[stripeID].containsRef(ref)
 }
 
+// LastUpdate returns the time when the series has been last updated, and false if the series isn't active.
+func (c *ActiveSeries) LastUpdate(ref storage.SeriesRef) (time.Time, bool) {
+	stripeID := ref % numStripes
+	return c.stripes[stripeID].lastUpdate(ref)
+}
+
+// IsStale returns whether the series hasn't been updated since staleBefore. A series which isn't tracked either
+// hasn't been updated since the tracking started, or has been purged because it hasn't been updated for the timeout,
+// so it's only considered stale if both the tracking start and the purge time are not after staleBefore. For example,
+// the series aren't tracked right after the creation or a reload, so they're not stale until the timeout has passed.
+func (c *ActiveSeries) IsStale(ref storage.SeriesRef, staleBefore, now time.Time) bool {
+	if lastUpdate, ok := c.LastUpdate(ref); ok {
+		return lastUpdate.Before(staleBefore)
+	}
+
+	c.matchersMutex.RLock()
+	trackingSince := c.trackingSince
+	c.matchersMutex.RUnlock()
+
+	return !trackingSince.After(staleBefore) && !now.Add(-c.timeout).After(staleBefore)
+}
+
 // Active returns the total numbers of active series, active native
 // histogram series, and buckets of those native histogram series.
 // This method does not purge expired entries, so Purge should be
@@ -182,6 +238,17 @@ func (c *ActiveSeries) Active() (total, totalNativeHistograms, totalNativeHistog
 	return
 }
 
+// ActiveSince returns the number of series which have been updated since the given time, excluding the series
+// which have been deleted from the head. Unlike Active, it iterates over all the tracked series, and it's only
+// accurate if the time is within the timeout.
+func (c *ActiveSeries) ActiveSince(since time.Time) int {
+	total := 0
+	for s := 0; s < numStripes; s++ {
+		total += c.stripes[s].activeSince(since.UnixNano())
+	}
+	return total
+}
+
 // ActiveWithMatchers returns the total number of active series, as well as a
 // slice of active series matching each one of the custom trackers provided (in
 // the same order as custom trackers are defined), and then the same thing for
@@ -205,6 +272,37 @@ func (c *ActiveSeries) ActiveWithMatchers() (total int, totalMatching []int, tot
 	return
 }
 
+// ActiveByAttribution returns the number of active series by cost attribution key, or nil if
+// cost attribution is disabled. This method does not purge expired entries, so Purge should be
+// called periodically.
+func (c *ActiveSeries) ActiveByAttribution() map[string]int {
+	c.matchersMutex.RLock()
+	defer c.matchersMutex.RUnlock()
+
+	if c.costAttribution == nil {
+		return nil
+	}
+
+	active := map[string]int{}
+	for s := 0; s < numStripes; s++ {
+		c.stripes[s].updateActiveByAttribution(active)
+	}
+	return active
+}
+
+func (s *seriesStripe) activeSince(sinceNanos int64) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	total := 0
+	for _, entry := range s.refs {
+		if !entry.deleted && entry.nanos.Load() >= sinceNanos {
+			total++
+		}
+	}
+	return total
+}
+
 func (s *seriesStripe) containsRef(ref storage.SeriesRef) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -213,6 +311,17 @@ func (s *seriesStripe) containsRef(ref storage.SeriesRef) bool {
 	return ok
 }
 
+func (s *seriesStripe) lastUpdate(ref storage.SeriesRef) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.refs[ref]
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(0, entry.nanos.Load()), true
+}
+
 func (s *seriesStripe) markDeleted(ref storage.SeriesRef, lbls labels.Labels) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -259,13 +368,38 @@ func (s *seriesStripe) getTotalAndUpdateMatching(matching []int, matchingNativeH
 	return s.active, s.activeNativeHistograms, s.activeNativeHistogramBuckets
 }
 
+// updateActiveByAttribution adds the number of active series in the stripe by cost attribution key to active.
+func (s *seriesStripe) updateActiveByAttribution(active map[string]int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for key, a := range s.activeAttributed {
+		active[key] += int(a)
+	}
+}
+
 func (s *seriesStripe) updateSeriesTimestamp(now time.Time, series labels.Labels, ref storage.SeriesRef, numNativeHistogramBuckets int) bool {
 	nowNanos := now.UnixNano()
 
-	e, needsUpdating := s.findEntryForSeries(ref, numNativeHistogramBuckets)
+	e, needsUpdating, cat := s.findEntryForSeries(ref, numNativeHistogramBuckets)
 	created := false
 	if e == nil || needsUpdating {
-		e, created = s.findAndUpdateOrCreateEntryForSeries(ref, series, nowNanos, numNativeHistogramBuckets)
+		// The cost attribution tracker is shared by all the stripes, so the attribution of a new series is
+		// resolved before taking the stripe lock, and resolved again if the series has to be created with
+		// another tracker (e.g. it has been reloaded meanwhile).
+		attribution, attributed := "", false
+		if e == nil {
+			attribution, attributed = cat.Attribute(series, now), true
+		}
+		for {
+			var current *costattribution.Tracker
+			e, created, current = s.findAndUpdateOrCreateEntryForSeries(ref, series, nowNanos, numNativeHistogramBuckets, cat, attribution, attributed)
+			if e != nil {
+				break
+			}
+			cat = current
+			attribution, attributed = cat.Attribute(series, now), true
+		}
 	}
 
 	entryTimeSet := created
@@ -288,14 +422,20 @@ func (s *seriesStripe) updateSeriesTimestamp(now time.Time, series labels.Labels
 	return created
 }
 
-func (s *seriesStripe) findEntryForSeries(ref storage.SeriesRef, numNativeHistogramBuckets int) (*atomic.Int64, bool) {
+// findEntryForSeries returns the entry of the series, if any, whether it needs to be updated, and the
+// cost attribution tracker of the stripe.
+func (s *seriesStripe) findEntryForSeries(ref storage.SeriesRef, numNativeHistogramBuckets int) (*atomic.Int64, bool, *costattribution.Tracker) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	entry := s.refs[ref]
-	return entry.nanos, entry.numNativeHistogramBuckets != numNativeHistogramBuckets
+	return entry.nanos, entry.numNativeHistogramBuckets != numNativeHistogramBuckets, s.costAttribution
 }
 
-func (s *seriesStripe) findAndUpdateOrCreateEntryForSeries(ref storage.SeriesRef, series labels.Labels, nowNanos int64, numNativeHistogramBuckets int) (entryTime *atomic.Int64, created bool) {
+// findAndUpdateOrCreateEntryForSeries updates the entry of the series, or creates it with the given attribution,
+// which must have been resolved by the cat tracker. If the entry has to be created, but the attribution hasn't
+// been resolved or cat is not the tracker of the stripe anymore, it returns a nil entry and the tracker of the
+// stripe, which must be used to resolve the attribution before calling it again.
+func (s *seriesStripe) findAndUpdateOrCreateEntryForSeries(ref storage.SeriesRef, series labels.Labels, nowNanos int64, numNativeHistogramBuckets int, cat *costattribution.Tracker, attribution string, attributed bool) (entryTime *atomic.Int64, created bool, current *costattribution.Tracker) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -335,12 +475,20 @@ func (s *seriesStripe) findAndUpdateOrCreateEntryForSeries(ref storage.SeriesRef
 			entry.numNativeHistogramBuckets = numNativeHistogramBuckets
 			s.refs[ref] = entry
 		}
-		return entry.nanos, false
+		return entry.nanos, false, nil
+	}
+
+	if !attributed || cat != s.costAttribution {
+		return nil, false, s.costAttribution
 	}
 
 	matches := s.matchers.matches(series)
 	matchesLen := matches.len()
 
+	if s.costAttribution != nil {
+		s.activeAttributed[attribution]++
+	}
+
 	s.active++
 	if numNativeHistogramBuckets >= 0 {
 		s.activeNativeHistograms++
@@ -359,10 +507,11 @@ func (s *seriesStripe) findAndUpdateOrCreateEntryForSeries(ref storage.SeriesRef
 		nanos:                     atomic.NewInt64(nowNanos),
 		matches:                   matches,
 		numNativeHistogramBuckets: numNativeHistogramBuckets,
+		attribution:               attribution,
 	}
 
 	s.refs[ref] = e
-	return e.nanos, true
+	return e.nanos, true, nil
 }
 
 // nolint // Linter reports that this method is unused, but it is.
@@ -375,6 +524,7 @@ func (s *seriesStripe) clear() {
 	s.active = 0
 	s.activeNativeHistograms = 0
 	s.activeNativeHistogramBuckets = 0
+	clear(s.activeAttributed)
 	for i := range s.activeMatching {
 		s.activeMatching[i] = 0
 		s.activeMatchingNativeHistograms[i] = 0
@@ -382,8 +532,8 @@ func (s *seriesStripe) clear() {
 	}
 }
 
-// Reinitialize assigns new matchers and corresponding size activeMatching slices.
-func (s *seriesStripe) reinitialize(asm *Matchers, deleted *deletedSeries) {
+// Reinitialize assigns new matchers and corresponding size activeMatching slices, and the cost attribution tracker.
+func (s *seriesStripe) reinitialize(asm *Matchers, cat *costattribution.Tracker, deleted *deletedSeries) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -394,6 +544,11 @@ func (s *seriesStripe) reinitialize(asm *Matchers, deleted *deletedSeries) {
 	s.activeNativeHistograms = 0
 	s.activeNativeHistogramBuckets = 0
 	s.matchers = asm
+	s.costAttribution = cat
+	s.activeAttributed = nil
+	if cat != nil {
+		s.activeAttributed = map[string]uint32{}
+	}
 	s.activeMatching = resizeAndClear(len(asm.MatcherNames()), s.activeMatching)
 	s.activeMatchingNativeHistograms = resizeAndClear(len(asm.MatcherNames()), s.activeMatchingNativeHistograms)
 	s.activeMatchingNativeHistogramBuckets = resizeAndClear(len(asm.MatcherNames()), s.activeMatchingNativeHistogramBuckets)
@@ -415,6 +570,7 @@ func (s *seriesStripe) purge(keepUntil time.Time) {
 	s.activeMatching = resizeAndClear(len(s.activeMatching), s.activeMatching)
 	s.activeMatchingNativeHistograms = resizeAndClear(len(s.activeMatchingNativeHistograms), s.activeMatchingNativeHistograms)
 	s.activeMatchingNativeHistogramBuckets = resizeAndClear(len(s.activeMatchingNativeHistogramBuckets), s.activeMatchingNativeHistogramBuckets)
+	clear(s.activeAttributed)
 
 	oldest := int64(math.MaxInt64)
 	for ref, entry := range s.refs {
@@ -432,6 +588,9 @@ func (s *seriesStripe) purge(keepUntil time.Time) {
 			s.activeNativeHistograms++
 			s.activeNativeHistogramBuckets += uint32(entry.numNativeHistogramBuckets)
 		}
+		if s.activeAttributed != nil {
+			s.activeAttributed[entry.attribution]++
+		}
 		ml := entry.matches.len()
 		for i := 0; i < ml; i++ {
 			match := entry.matches.get(i)
@@ -474,6 +633,11 @@ func (s *seriesStripe) remove(ref storage.SeriesRef) {
 		s.activeNativeHistograms--
 		s.activeNativeHistogramBuckets -= uint32(entry.numNativeHistogramBuckets)
 	}
+	if s.activeAttributed != nil {
+		if s.activeAttributed[entry.attribution]--; s.activeAttributed[entry.attribution] == 0 {
+			delete(s.activeAttributed, entry.attribution)
+		}
+	}
 	ml := entry.matches.len()
 	for i := 0; i < ml; i++ {
 		match := entry.matches.get(i)
@@ -17,7 +17,10 @@ import (
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb/chunks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/costattribution"
 )
 
 const DefaultTimeout = 5 * time.Minute
@@ -29,7 +32,7 @@ func TestActiveSeries_UpdateSeries_NoMatchers(t *testing.T) {
 	ref4, ls4 := storage.SeriesRef(4), labels.FromStrings("a", "4")
 	ref5 := storage.SeriesRef(5) // will be used for ls1 again.
 
-	c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 	valid := c.Purge(time.Now())
 	assert.True(t, valid)
 	allActive, activeMatching, allActiveHistograms, activeMatchingHistograms, allActiveBuckets, activeMatchingBuckets := c.ActiveWithMatchers()
@@ -194,7 +197,7 @@ func TestActiveSeries_ContainsRef(t *testing.T) {
 	for ttl := 1; ttl <= len(series); ttl++ {
 		t.Run(fmt.Sprintf("ttl: %d", ttl), func(t *testing.T) {
 			mockedTime := time.Unix(int64(ttl), 0)
-			c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+			c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 
 			// Update each series with a different timestamp according to each index
 			for i := 0; i < len(series); i++ {
@@ -219,6 +222,52 @@ func TestActiveSeries_ContainsRef(t *testing.T) {
 	}
 }
 
+func TestActiveSeries_LastUpdate(t *testing.T) {
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
+
+	_, ok := c.LastUpdate(1)
+	assert.False(t, ok)
+
+	c.UpdateSeries(labels.FromStrings("a", "1"), 1, time.Unix(10, 0), -1)
+	lastUpdate, ok := c.LastUpdate(1)
+	assert.True(t, ok)
+	assert.Equal(t, time.Unix(10, 0), lastUpdate)
+
+	c.UpdateSeries(labels.FromStrings("a", "1"), 1, time.Unix(20, 0), -1)
+	lastUpdate, ok = c.LastUpdate(1)
+	assert.True(t, ok)
+	assert.Equal(t, time.Unix(20, 0), lastUpdate)
+
+	c.purge(time.Unix(30, 0))
+	_, ok = c.LastUpdate(1)
+	assert.False(t, ok)
+}
+
+func TestActiveSeries_IsStale(t *testing.T) {
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
+	trackingSince := time.Unix(1000, 0)
+	c.ReloadMatchers(&Matchers{}, trackingSince)
+
+	c.UpdateSeries(labels.FromStrings("a", "1"), 1, trackingSince.Add(time.Minute), -1)
+
+	// The tracked series are stale if they haven't been updated since the input time.
+	assert.False(t, c.IsStale(1, trackingSince.Add(time.Minute), trackingSince.Add(2*time.Minute)))
+	assert.True(t, c.IsStale(1, trackingSince.Add(2*time.Minute), trackingSince.Add(2*time.Minute)))
+
+	// The untracked series may have been updated before the tracking started, but not after.
+	assert.False(t, c.IsStale(2, trackingSince.Add(-time.Minute), trackingSince.Add(2*time.Minute)))
+	assert.True(t, c.IsStale(2, trackingSince.Add(time.Minute), trackingSince.Add(2*time.Minute)))
+
+	// Once the timeout has passed since the tracking started, the untracked series haven't been updated for the timeout.
+	now := trackingSince.Add(DefaultTimeout + 2*time.Minute)
+	assert.True(t, c.IsStale(2, now.Add(-DefaultTimeout), now))
+	assert.False(t, c.IsStale(2, now.Add(-DefaultTimeout-time.Minute), now))
+
+	// A reload resets the tracked series.
+	c.ReloadMatchers(&Matchers{}, now)
+	assert.False(t, c.IsStale(1, now.Add(-DefaultTimeout), now))
+}
+
 func TestActiveSeries_UpdateSeries_WithMatchers(t *testing.T) {
 	ref1, ls1 := storage.SeriesRef(1), labels.FromStrings("a", "1")
 	ref2, ls2 := storage.SeriesRef(2), labels.FromStrings("a", "2")
@@ -229,7 +278,7 @@ func TestActiveSeries_UpdateSeries_WithMatchers(t *testing.T) {
 
 	asm := NewMatchers(mustNewCustomTrackersConfigFromMap(t, map[string]string{"foo": `{a=~"2|3|4"}`}))
 
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 	valid := c.Purge(time.Now())
 	assert.True(t, valid)
 	allActive, activeMatching, allActiveHistograms, activeMatchingHistograms, allActiveBuckets, activeMatchingBuckets := c.ActiveWithMatchers()
@@ -455,7 +504,7 @@ func TestActiveSeries_ShouldCorrectlyHandleHashCollisions(t *testing.T) {
 	ls1, ls2 := labelsWithHashCollision()
 	ref1, ref2 := storage.SeriesRef(1), storage.SeriesRef(2)
 
-	c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 	c.UpdateSeries(ls1, ref1, time.Now(), -1)
 	c.UpdateSeries(ls2, ref2, time.Now(), -1)
 
@@ -483,7 +532,7 @@ func TestActiveSeries_Purge_NoMatchers(t *testing.T) {
 	for ttl := 1; ttl <= len(series); ttl++ {
 		t.Run(fmt.Sprintf("ttl: %d", ttl), func(t *testing.T) {
 			mockedTime := time.Unix(int64(ttl), 0)
-			c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+			c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 
 			for i := 0; i < len(series); i++ {
 				c.UpdateSeries(series[i], refs[i], time.Unix(int64(i), 0), -1)
@@ -529,7 +578,7 @@ func TestActiveSeries_Purge_WithMatchers(t *testing.T) {
 		t.Run(fmt.Sprintf("ttl=%d", ttl), func(t *testing.T) {
 			mockedTime := time.Unix(int64(ttl), 0)
 
-			c := NewActiveSeries(asm, 5*time.Minute)
+			c := NewActiveSeries(asm, nil, 5*time.Minute)
 
 			exp := len(series) - ttl
 			expMatchingSeries := 0
@@ -556,12 +605,131 @@ func TestActiveSeries_Purge_WithMatchers(t *testing.T) {
 	}
 }
 
+type costAttributionLimits struct{}
+
+func (costAttributionLimits) CostAttributionLabels(string) []string { return []string{"team"} }
+
+func (costAttributionLimits) MaxCostAttributionCardinalityPerUser(string) int { return 2 }
+
+func TestActiveSeries_ActiveByAttribution(t *testing.T) {
+	cat := costattribution.NewManager(time.Minute, time.Minute, costAttributionLimits{}, nil).Tracker("user")
+	series := []labels.Labels{
+		labels.FromStrings("a", "1", "team", "foo"),
+		labels.FromStrings("a", "2", "team", "foo"),
+		labels.FromStrings("a", "3", "team", "bar"),
+		labels.FromStrings("a", "4", "team", "baz"),
+		labels.FromStrings("a", "5"),
+	}
+
+	c := NewActiveSeries(&Matchers{}, cat, DefaultTimeout)
+	assert.Empty(t, c.ActiveByAttribution())
+
+	for i, s := range series {
+		c.UpdateSeries(s, storage.SeriesRef(i+1), time.Unix(int64(i), 0), -1)
+	}
+	assert.Equal(t, map[string]int{"foo": 2, "bar": 1, costattribution.OverflowValue: 2}, c.ActiveByAttribution())
+
+	// Purge the first two series.
+	c.purge(time.Unix(2, 0))
+	assert.Equal(t, map[string]int{"bar": 1, costattribution.OverflowValue: 2}, c.ActiveByAttribution())
+
+	// Reloading the tracker resets the active series.
+	c.ReloadCostAttribution(nil, time.Unix(5, 0))
+	assert.Nil(t, c.ActiveByAttribution())
+	c.UpdateSeries(series[0], 1, time.Unix(5, 0), -1)
+	assert.Nil(t, c.ActiveByAttribution())
+}
+
+func TestActiveSeries_ActiveByAttribution_ShouldResolveTheAttributionWithTheCurrentTracker(t *testing.T) {
+	manager := costattribution.NewManager(time.Minute, time.Minute, costAttributionLimits{}, nil)
+	oldTracker, newTracker := manager.Tracker("user-1"), manager.Tracker("user-2")
+	series := labels.FromStrings("a", "1", "team", "foo")
+
+	c := NewActiveSeries(&Matchers{}, newTracker, DefaultTimeout)
+	stripe := &c.stripes[0]
+
+	// The attribution resolved by a tracker which isn't the stripe's one anymore is not used.
+	e, created, current := stripe.findAndUpdateOrCreateEntryForSeries(1, series, 0, -1, oldTracker, "foo", true)
+	require.Nil(t, e)
+	require.False(t, created)
+	require.Same(t, newTracker, current)
+
+	// The attribution must be resolved to create the series.
+	e, _, current = stripe.findAndUpdateOrCreateEntryForSeries(1, series, 0, -1, newTracker, "", false)
+	require.Nil(t, e)
+	require.Same(t, newTracker, current)
+
+	e, created, _ = stripe.findAndUpdateOrCreateEntryForSeries(1, series, 0, -1, newTracker, "foo", true)
+	require.NotNil(t, e)
+	require.True(t, created)
+
+	// The attribution is not needed to update an existing series.
+	e, created, _ = stripe.findAndUpdateOrCreateEntryForSeries(1, series, 0, -1, nil, "", false)
+	require.NotNil(t, e)
+	require.False(t, created)
+}
+
+func TestActiveSeries_ActiveByAttribution_Concurrency(t *testing.T) {
+	manager := costattribution.NewManager(time.Minute, time.Minute, costAttributionLimits{}, nil)
+	c := NewActiveSeries(&Matchers{}, manager.Tracker("user"), DefaultTimeout)
+
+	const numSeries = 1000
+	wg := sync.WaitGroup{}
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < numSeries; i++ {
+				c.UpdateSeries(labels.FromStrings("a", strconv.Itoa(i), "team", strconv.Itoa(i%3)), storage.SeriesRef(i+1), time.Unix(int64(i), 0), -1)
+				if w == 0 && i%100 == 0 {
+					c.ReloadCostAttribution(manager.Tracker("user"), time.Unix(int64(i), 0))
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	total := 0
+	for _, count := range c.ActiveByAttribution() {
+		total += count
+	}
+	allActive, _, _, _, _, _ := c.ActiveWithMatchers()
+	assert.Equal(t, allActive, total)
+}
+
+func TestActiveSeries_ActiveSince(t *testing.T) {
+	series := []labels.Labels{
+		labels.FromStrings("a", "1"),
+		labels.FromStrings("a", "2"),
+		labels.FromStrings("a", "3"),
+		labels.FromStrings("a", "4"),
+	}
+
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
+	for i, s := range series {
+		c.UpdateSeries(s, storage.SeriesRef(i+1), time.Unix(int64(i), 0), -1)
+	}
+
+	assert.Equal(t, 4, c.ActiveSince(time.Unix(0, 0)))
+	assert.Equal(t, 2, c.ActiveSince(time.Unix(2, 0)))
+	assert.Equal(t, 0, c.ActiveSince(time.Unix(4, 0)))
+
+	// Updating a series makes it active again.
+	c.UpdateSeries(series[0], 1, time.Unix(5, 0), -1)
+	assert.Equal(t, 1, c.ActiveSince(time.Unix(4, 0)))
+
+	// The series deleted from the head are not counted.
+	c.PostDeletion(map[chunks.HeadSeriesRef]labels.Labels{1: series[0]})
+	assert.Equal(t, 0, c.ActiveSince(time.Unix(4, 0)))
+	assert.Equal(t, 2, c.ActiveSince(time.Unix(2, 0)))
+}
+
 func TestActiveSeries_PurgeOpt(t *testing.T) {
 	ls1, ls2 := labelsWithHashCollision()
 	ref1, ref2 := storage.SeriesRef(1), storage.SeriesRef(2)
 
 	currentTime := time.Now()
-	c := NewActiveSeries(&Matchers{}, 59*time.Second)
+	c := NewActiveSeries(&Matchers{}, nil, 59*time.Second)
 
 	c.UpdateSeries(ls1, ref1, currentTime.Add(-2*time.Minute), -1)
 	c.UpdateSeries(ls2, ref2, currentTime, -1)
@@ -597,7 +765,7 @@ func TestActiveSeries_ReloadSeriesMatchers(t *testing.T) {
 	asm := NewMatchers(mustNewCustomTrackersConfigFromMap(t, map[string]string{"foo": `{a=~.*}`}))
 
 	currentTime := time.Now()
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 
 	valid := c.Purge(currentTime)
 	assert.True(t, valid)
@@ -663,7 +831,7 @@ func TestActiveSeries_ReloadSeriesMatchers_LessMatchers(t *testing.T) {
 	}))
 
 	currentTime := time.Now()
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 	valid := c.Purge(currentTime)
 	assert.True(t, valid)
 	allActive, activeMatching, _, _, _, _ := c.ActiveWithMatchers()
@@ -702,7 +870,7 @@ func TestActiveSeries_ReloadSeriesMatchers_SameSizeNewLabels(t *testing.T) {
 
 	currentTime := time.Now()
 
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 	valid := c.Purge(currentTime)
 	assert.True(t, valid)
 	allActive, activeMatching, _, _, _, _ := c.ActiveWithMatchers()
@@ -755,7 +923,7 @@ func benchmarkActiveSeriesUpdateSeriesConcurrency(b *testing.B, numSeries, numGo
 	var (
 		// Run the active series tracker with an active timeout = 0 so that the Purge() will always
 		// purge the series.
-		c           = NewActiveSeries(&Matchers{}, 0)
+		c           = NewActiveSeries(&Matchers{}, nil, 0)
 		updateGroup = &sync.WaitGroup{}
 		purgeGroup  = &sync.WaitGroup{}
 		start       = make(chan struct{})
@@ -893,7 +1061,7 @@ func BenchmarkActiveSeries_UpdateSeries(b *testing.B) {
 
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				c := NewActiveSeries(asm, DefaultTimeout)
+				c := NewActiveSeries(asm, nil, DefaultTimeout)
 				for round := 0; round <= tt.nRounds; round++ {
 					for ix := 0; ix < tt.nSeries; ix++ {
 						c.UpdateSeries(series[ix], refs[ix], time.Unix(0, now), -1)
@@ -918,7 +1086,7 @@ func benchmarkPurge(b *testing.B, twice bool) {
 	const numExpiresSeries = numSeries / 25
 
 	currentTime := time.Now()
-	c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 
 	series := [numSeries]labels.Labels{}
 	refs := [numSeries]storage.SeriesRef{}
@@ -36,6 +36,7 @@ var (
 		"/cortex.Ingester/MetricsMetadata":         {},
 		"/cortex.Ingester/LabelNamesAndValues":     {},
 		"/cortex.Ingester/LabelValuesCardinality":  {},
+		"/cortex.Ingester/ActiveSeries":            {},
 	}
 )
 
@@ -107,6 +107,16 @@ func FromMetricsForLabelMatchersResponse(resp *MetricsForLabelMatchersResponse)
 	return metrics
 }
 
+// ToActiveSeriesRequest builds an ActiveSeriesRequest proto.
+func ToActiveSeriesRequest(matchers []*labels.Matcher) (*ActiveSeriesRequest, error) {
+	ms, err := ToLabelMatchers(matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ActiveSeriesRequest{Matchers: ms}, nil
+}
+
 // ToLabelValuesRequest builds a LabelValuesRequest proto
 func ToLabelValuesRequest(labelName model.LabelName, from, to model.Time, matchers []*labels.Matcher) (*LabelValuesRequest, error) {
 	ms, err := ToLabelMatchers(matchers)
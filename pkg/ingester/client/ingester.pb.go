@@ -103,7 +103,7 @@ var ReadRequest_ResponseType_value = map[string]int32{
 }
 
 func (ReadRequest_ResponseType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{6, 0}
+	return fileDescriptor_60f6df4f3586b478, []int{8, 0}
 }
 
 type StreamChunk_Encoding int32
@@ -130,7 +130,7 @@ var StreamChunk_Encoding_value = map[string]int32{
 }
 
 func (StreamChunk_Encoding) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{10, 0}
+	return fileDescriptor_60f6df4f3586b478, []int{12, 0}
 }
 
 type LabelNamesAndValuesRequest struct {
@@ -423,6 +423,92 @@ func (m *LabelValueSeriesCount) GetLabelValueSeries() map[string]uint64 {
 	return nil
 }
 
+type ActiveSeriesRequest struct {
+	Matchers []*LabelMatcher `protobuf:"bytes,1,rep,name=matchers,proto3" json:"matchers,omitempty"`
+}
+
+func (m *ActiveSeriesRequest) Reset()      { *m = ActiveSeriesRequest{} }
+func (*ActiveSeriesRequest) ProtoMessage() {}
+func (*ActiveSeriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{6}
+}
+func (m *ActiveSeriesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ActiveSeriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ActiveSeriesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ActiveSeriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActiveSeriesRequest.Merge(m, src)
+}
+func (m *ActiveSeriesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ActiveSeriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActiveSeriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActiveSeriesRequest proto.InternalMessageInfo
+
+func (m *ActiveSeriesRequest) GetMatchers() []*LabelMatcher {
+	if m != nil {
+		return m.Matchers
+	}
+	return nil
+}
+
+type ActiveSeriesResponse struct {
+	Metric []*mimirpb.Metric `protobuf:"bytes,1,rep,name=metric,proto3" json:"metric,omitempty"`
+}
+
+func (m *ActiveSeriesResponse) Reset()      { *m = ActiveSeriesResponse{} }
+func (*ActiveSeriesResponse) ProtoMessage() {}
+func (*ActiveSeriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{7}
+}
+func (m *ActiveSeriesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ActiveSeriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ActiveSeriesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ActiveSeriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActiveSeriesResponse.Merge(m, src)
+}
+func (m *ActiveSeriesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ActiveSeriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActiveSeriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActiveSeriesResponse proto.InternalMessageInfo
+
+func (m *ActiveSeriesResponse) GetMetric() []*mimirpb.Metric {
+	if m != nil {
+		return m.Metric
+	}
+	return nil
+}
+
 type ReadRequest struct {
 	Queries               []*QueryRequest            `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries,omitempty"`
 	AcceptedResponseTypes []ReadRequest_ResponseType `protobuf:"varint,2,rep,packed,name=accepted_response_types,json=acceptedResponseTypes,proto3,enum=cortex.ReadRequest_ResponseType" json:"accepted_response_types,omitempty"`
@@ -431,7 +517,7 @@ type ReadRequest struct {
 func (m *ReadRequest) Reset()      { *m = ReadRequest{} }
 func (*ReadRequest) ProtoMessage() {}
 func (*ReadRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{6}
+	return fileDescriptor_60f6df4f3586b478, []int{8}
 }
 func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -481,7 +567,7 @@ type ReadResponse struct {
 func (m *ReadResponse) Reset()      { *m = ReadResponse{} }
 func (*ReadResponse) ProtoMessage() {}
 func (*ReadResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{7}
+	return fileDescriptor_60f6df4f3586b478, []int{9}
 }
 func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -525,7 +611,7 @@ type StreamReadResponse struct {
 func (m *StreamReadResponse) Reset()      { *m = StreamReadResponse{} }
 func (*StreamReadResponse) ProtoMessage() {}
 func (*StreamReadResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{8}
+	return fileDescriptor_60f6df4f3586b478, []int{10}
 }
 func (m *StreamReadResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -576,7 +662,7 @@ type StreamChunkedSeries struct {
 func (m *StreamChunkedSeries) Reset()      { *m = StreamChunkedSeries{} }
 func (*StreamChunkedSeries) ProtoMessage() {}
 func (*StreamChunkedSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{9}
+	return fileDescriptor_60f6df4f3586b478, []int{11}
 }
 func (m *StreamChunkedSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -622,7 +708,7 @@ type StreamChunk struct {
 func (m *StreamChunk) Reset()      { *m = StreamChunk{} }
 func (*StreamChunk) ProtoMessage() {}
 func (*StreamChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{10}
+	return fileDescriptor_60f6df4f3586b478, []int{12}
 }
 func (m *StreamChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -683,7 +769,7 @@ type QueryRequest struct {
 func (m *QueryRequest) Reset()      { *m = QueryRequest{} }
 func (*QueryRequest) ProtoMessage() {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{11}
+	return fileDescriptor_60f6df4f3586b478, []int{13}
 }
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -749,7 +835,7 @@ type ExemplarQueryRequest struct {
 func (m *ExemplarQueryRequest) Reset()      { *m = ExemplarQueryRequest{} }
 func (*ExemplarQueryRequest) ProtoMessage() {}
 func (*ExemplarQueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{12}
+	return fileDescriptor_60f6df4f3586b478, []int{14}
 }
 func (m *ExemplarQueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -806,7 +892,7 @@ type QueryResponse struct {
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
 func (*QueryResponse) ProtoMessage() {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{13}
+	return fileDescriptor_60f6df4f3586b478, []int{15}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -861,7 +947,7 @@ type QueryStreamResponse struct {
 func (m *QueryStreamResponse) Reset()      { *m = QueryStreamResponse{} }
 func (*QueryStreamResponse) ProtoMessage() {}
 func (*QueryStreamResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{14}
+	return fileDescriptor_60f6df4f3586b478, []int{16}
 }
 func (m *QueryStreamResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -933,7 +1019,7 @@ type QueryStreamSeries struct {
 func (m *QueryStreamSeries) Reset()      { *m = QueryStreamSeries{} }
 func (*QueryStreamSeries) ProtoMessage() {}
 func (*QueryStreamSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{15}
+	return fileDescriptor_60f6df4f3586b478, []int{17}
 }
 func (m *QueryStreamSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -977,7 +1063,7 @@ type QueryStreamSeriesChunks struct {
 func (m *QueryStreamSeriesChunks) Reset()      { *m = QueryStreamSeriesChunks{} }
 func (*QueryStreamSeriesChunks) ProtoMessage() {}
 func (*QueryStreamSeriesChunks) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{16}
+	return fileDescriptor_60f6df4f3586b478, []int{18}
 }
 func (m *QueryStreamSeriesChunks) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1027,7 +1113,7 @@ type ExemplarQueryResponse struct {
 func (m *ExemplarQueryResponse) Reset()      { *m = ExemplarQueryResponse{} }
 func (*ExemplarQueryResponse) ProtoMessage() {}
 func (*ExemplarQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{17}
+	return fileDescriptor_60f6df4f3586b478, []int{19}
 }
 func (m *ExemplarQueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1073,7 +1159,7 @@ type LabelValuesRequest struct {
 func (m *LabelValuesRequest) Reset()      { *m = LabelValuesRequest{} }
 func (*LabelValuesRequest) ProtoMessage() {}
 func (*LabelValuesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{18}
+	return fileDescriptor_60f6df4f3586b478, []int{20}
 }
 func (m *LabelValuesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1137,7 +1223,7 @@ type LabelValuesResponse struct {
 func (m *LabelValuesResponse) Reset()      { *m = LabelValuesResponse{} }
 func (*LabelValuesResponse) ProtoMessage() {}
 func (*LabelValuesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{19}
+	return fileDescriptor_60f6df4f3586b478, []int{21}
 }
 func (m *LabelValuesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1182,7 +1268,7 @@ type LabelNamesRequest struct {
 func (m *LabelNamesRequest) Reset()      { *m = LabelNamesRequest{} }
 func (*LabelNamesRequest) ProtoMessage() {}
 func (*LabelNamesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{20}
+	return fileDescriptor_60f6df4f3586b478, []int{22}
 }
 func (m *LabelNamesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1239,7 +1325,7 @@ type LabelNamesResponse struct {
 func (m *LabelNamesResponse) Reset()      { *m = LabelNamesResponse{} }
 func (*LabelNamesResponse) ProtoMessage() {}
 func (*LabelNamesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{21}
+	return fileDescriptor_60f6df4f3586b478, []int{23}
 }
 func (m *LabelNamesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1282,7 +1368,7 @@ type UserStatsRequest struct {
 func (m *UserStatsRequest) Reset()      { *m = UserStatsRequest{} }
 func (*UserStatsRequest) ProtoMessage() {}
 func (*UserStatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{22}
+	return fileDescriptor_60f6df4f3586b478, []int{24}
 }
 func (m *UserStatsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1328,7 +1414,7 @@ type UserStatsResponse struct {
 func (m *UserStatsResponse) Reset()      { *m = UserStatsResponse{} }
 func (*UserStatsResponse) ProtoMessage() {}
 func (*UserStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{23}
+	return fileDescriptor_60f6df4f3586b478, []int{25}
 }
 func (m *UserStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1393,7 +1479,7 @@ type UserIDStatsResponse struct {
 func (m *UserIDStatsResponse) Reset()      { *m = UserIDStatsResponse{} }
 func (*UserIDStatsResponse) ProtoMessage() {}
 func (*UserIDStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{24}
+	return fileDescriptor_60f6df4f3586b478, []int{26}
 }
 func (m *UserIDStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1443,7 +1529,7 @@ type UsersStatsResponse struct {
 func (m *UsersStatsResponse) Reset()      { *m = UsersStatsResponse{} }
 func (*UsersStatsResponse) ProtoMessage() {}
 func (*UsersStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{25}
+	return fileDescriptor_60f6df4f3586b478, []int{27}
 }
 func (m *UsersStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1488,7 +1574,7 @@ type MetricsForLabelMatchersRequest struct {
 func (m *MetricsForLabelMatchersRequest) Reset()      { *m = MetricsForLabelMatchersRequest{} }
 func (*MetricsForLabelMatchersRequest) ProtoMessage() {}
 func (*MetricsForLabelMatchersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{26}
+	return fileDescriptor_60f6df4f3586b478, []int{28}
 }
 func (m *MetricsForLabelMatchersRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1545,7 +1631,7 @@ type MetricsForLabelMatchersResponse struct {
 func (m *MetricsForLabelMatchersResponse) Reset()      { *m = MetricsForLabelMatchersResponse{} }
 func (*MetricsForLabelMatchersResponse) ProtoMessage() {}
 func (*MetricsForLabelMatchersResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{27}
+	return fileDescriptor_60f6df4f3586b478, []int{29}
 }
 func (m *MetricsForLabelMatchersResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1590,7 +1676,7 @@ type MetricsMetadataRequest struct {
 func (m *MetricsMetadataRequest) Reset()      { *m = MetricsMetadataRequest{} }
 func (*MetricsMetadataRequest) ProtoMessage() {}
 func (*MetricsMetadataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{28}
+	return fileDescriptor_60f6df4f3586b478, []int{30}
 }
 func (m *MetricsMetadataRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1647,7 +1733,7 @@ type MetricsMetadataResponse struct {
 func (m *MetricsMetadataResponse) Reset()      { *m = MetricsMetadataResponse{} }
 func (*MetricsMetadataResponse) ProtoMessage() {}
 func (*MetricsMetadataResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{29}
+	return fileDescriptor_60f6df4f3586b478, []int{31}
 }
 func (m *MetricsMetadataResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1693,7 +1779,7 @@ type TimeSeriesChunk struct {
 func (m *TimeSeriesChunk) Reset()      { *m = TimeSeriesChunk{} }
 func (*TimeSeriesChunk) ProtoMessage() {}
 func (*TimeSeriesChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{30}
+	return fileDescriptor_60f6df4f3586b478, []int{32}
 }
 func (m *TimeSeriesChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1753,7 +1839,7 @@ type Chunk struct {
 func (m *Chunk) Reset()      { *m = Chunk{} }
 func (*Chunk) ProtoMessage() {}
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{31}
+	return fileDescriptor_60f6df4f3586b478, []int{33}
 }
 func (m *Chunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1810,7 +1896,7 @@ type LabelMatchers struct {
 func (m *LabelMatchers) Reset()      { *m = LabelMatchers{} }
 func (*LabelMatchers) ProtoMessage() {}
 func (*LabelMatchers) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{32}
+	return fileDescriptor_60f6df4f3586b478, []int{34}
 }
 func (m *LabelMatchers) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1855,7 +1941,7 @@ type LabelMatcher struct {
 func (m *LabelMatcher) Reset()      { *m = LabelMatcher{} }
 func (*LabelMatcher) ProtoMessage() {}
 func (*LabelMatcher) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{33}
+	return fileDescriptor_60f6df4f3586b478, []int{35}
 }
 func (m *LabelMatcher) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1910,12 +1996,14 @@ type TimeSeriesFile struct {
 	UserId         string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Filename       string `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
 	Data           []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// Offset of data in the file.
+	Offset int64 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
 }
 
 func (m *TimeSeriesFile) Reset()      { *m = TimeSeriesFile{} }
 func (*TimeSeriesFile) ProtoMessage() {}
 func (*TimeSeriesFile) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{34}
+	return fileDescriptor_60f6df4f3586b478, []int{36}
 }
 func (m *TimeSeriesFile) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1972,6 +2060,196 @@ func (m *TimeSeriesFile) GetData() []byte {
 	return nil
 }
 
+func (m *TimeSeriesFile) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type TransferTSDBRequest struct {
+	FromIngesterId string `protobuf:"bytes,1,opt,name=from_ingester_id,json=fromIngesterId,proto3" json:"from_ingester_id,omitempty"`
+	// Files to transfer. Only set in the first request.
+	Files []*TransferTSDBFile `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty"`
+	// Content of a file, starting at the offset.
+	Chunk *TimeSeriesFile `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	// Set in the last request, once all the files have been sent.
+	Commit bool `protobuf:"varint,4,opt,name=commit,proto3" json:"commit,omitempty"`
+}
+
+func (m *TransferTSDBRequest) Reset()      { *m = TransferTSDBRequest{} }
+func (*TransferTSDBRequest) ProtoMessage() {}
+func (*TransferTSDBRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{37}
+}
+func (m *TransferTSDBRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransferTSDBRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransferTSDBRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TransferTSDBRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferTSDBRequest.Merge(m, src)
+}
+func (m *TransferTSDBRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransferTSDBRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferTSDBRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferTSDBRequest proto.InternalMessageInfo
+
+func (m *TransferTSDBRequest) GetFromIngesterId() string {
+	if m != nil {
+		return m.FromIngesterId
+	}
+	return ""
+}
+
+func (m *TransferTSDBRequest) GetFiles() []*TransferTSDBFile {
+	if m != nil {
+		return m.Files
+	}
+	return nil
+}
+
+func (m *TransferTSDBRequest) GetChunk() *TimeSeriesFile {
+	if m != nil {
+		return m.Chunk
+	}
+	return nil
+}
+
+func (m *TransferTSDBRequest) GetCommit() bool {
+	if m != nil {
+		return m.Commit
+	}
+	return false
+}
+
+type TransferTSDBFile struct {
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Path of the file, relative to the TSDB directory of the tenant.
+	Filename  string `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	SizeBytes int64  `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	// CRC32 checksum, using the Castagnoli table, of the file content.
+	Checksum uint32 `protobuf:"varint,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+}
+
+func (m *TransferTSDBFile) Reset()      { *m = TransferTSDBFile{} }
+func (*TransferTSDBFile) ProtoMessage() {}
+func (*TransferTSDBFile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{38}
+}
+func (m *TransferTSDBFile) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransferTSDBFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransferTSDBFile.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TransferTSDBFile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferTSDBFile.Merge(m, src)
+}
+func (m *TransferTSDBFile) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransferTSDBFile) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferTSDBFile.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferTSDBFile proto.InternalMessageInfo
+
+func (m *TransferTSDBFile) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *TransferTSDBFile) GetFilename() string {
+	if m != nil {
+		return m.Filename
+	}
+	return ""
+}
+
+func (m *TransferTSDBFile) GetSizeBytes() int64 {
+	if m != nil {
+		return m.SizeBytes
+	}
+	return 0
+}
+
+func (m *TransferTSDBFile) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
+	}
+	return 0
+}
+
+type TransferTSDBResponse struct {
+	// Number of bytes already received for each file of the first request, in the same order.
+	ReceivedBytes []int64 `protobuf:"varint,1,rep,packed,name=received_bytes,json=receivedBytes,proto3" json:"received_bytes,omitempty"`
+}
+
+func (m *TransferTSDBResponse) Reset()      { *m = TransferTSDBResponse{} }
+func (*TransferTSDBResponse) ProtoMessage() {}
+func (*TransferTSDBResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{39}
+}
+func (m *TransferTSDBResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransferTSDBResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransferTSDBResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TransferTSDBResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferTSDBResponse.Merge(m, src)
+}
+func (m *TransferTSDBResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransferTSDBResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferTSDBResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferTSDBResponse proto.InternalMessageInfo
+
+func (m *TransferTSDBResponse) GetReceivedBytes() []int64 {
+	if m != nil {
+		return m.ReceivedBytes
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("cortex.CountMethod", CountMethod_name, CountMethod_value)
 	proto.RegisterEnum("cortex.MatchType", MatchType_name, MatchType_value)
@@ -1984,6 +2262,8 @@ func init() {
 	proto.RegisterType((*LabelValuesCardinalityResponse)(nil), "cortex.LabelValuesCardinalityResponse")
 	proto.RegisterType((*LabelValueSeriesCount)(nil), "cortex.LabelValueSeriesCount")
 	proto.RegisterMapType((map[string]uint64)(nil), "cortex.LabelValueSeriesCount.LabelValueSeriesEntry")
+	proto.RegisterType((*ActiveSeriesRequest)(nil), "cortex.ActiveSeriesRequest")
+	proto.RegisterType((*ActiveSeriesResponse)(nil), "cortex.ActiveSeriesResponse")
 	proto.RegisterType((*ReadRequest)(nil), "cortex.ReadRequest")
 	proto.RegisterType((*ReadResponse)(nil), "cortex.ReadResponse")
 	proto.RegisterType((*StreamReadResponse)(nil), "cortex.StreamReadResponse")
@@ -2013,135 +2293,150 @@ func init() {
 	proto.RegisterType((*LabelMatchers)(nil), "cortex.LabelMatchers")
 	proto.RegisterType((*LabelMatcher)(nil), "cortex.LabelMatcher")
 	proto.RegisterType((*TimeSeriesFile)(nil), "cortex.TimeSeriesFile")
+	proto.RegisterType((*TransferTSDBRequest)(nil), "cortex.TransferTSDBRequest")
+	proto.RegisterType((*TransferTSDBFile)(nil), "cortex.TransferTSDBFile")
+	proto.RegisterType((*TransferTSDBResponse)(nil), "cortex.TransferTSDBResponse")
 }
 
 func init() { proto.RegisterFile("ingester.proto", fileDescriptor_60f6df4f3586b478) }
 
 var fileDescriptor_60f6df4f3586b478 = []byte{
-	// 1960 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xcf, 0x6f, 0x1b, 0xc7,
-	0xf5, 0xe7, 0xf0, 0x97, 0xc4, 0x47, 0x8a, 0x5a, 0x0d, 0x2d, 0x93, 0xa1, 0xbf, 0xa6, 0x94, 0xfd,
-	0xc2, 0x29, 0x9b, 0x26, 0x94, 0x7f, 0xb5, 0x70, 0x82, 0x14, 0x01, 0x25, 0xd1, 0x16, 0x6d, 0x93,
-	0x54, 0x96, 0x54, 0xe2, 0x16, 0x08, 0x16, 0x4b, 0x72, 0x24, 0x2d, 0xcc, 0x5d, 0x32, 0xbb, 0xc3,
-	0x42, 0xca, 0xa9, 0x40, 0xef, 0x45, 0x6f, 0xbd, 0x14, 0x05, 0x7a, 0x2b, 0x7a, 0x2a, 0x7a, 0xe9,
-	0xad, 0xe7, 0x5c, 0x02, 0xf8, 0x18, 0xf4, 0x60, 0xd4, 0x72, 0x0f, 0xed, 0x2d, 0x40, 0xff, 0x81,
-	0x62, 0x67, 0x66, 0x7f, 0x72, 0x65, 0x29, 0x45, 0xec, 0x13, 0x39, 0xef, 0xbd, 0xf9, 0xcc, 0x7b,
-	0x6f, 0xde, 0xaf, 0x1d, 0x28, 0xea, 0xe6, 0x11, 0xb1, 0x29, 0xb1, 0x1a, 0x33, 0x6b, 0x4a, 0xa7,
-	0x38, 0x3b, 0x9a, 0x5a, 0x94, 0x9c, 0x54, 0xdf, 0x3f, 0xd2, 0xe9, 0xf1, 0x7c, 0xd8, 0x18, 0x4d,
-	0x8d, 0xad, 0xa3, 0xe9, 0xd1, 0x74, 0x8b, 0xb1, 0x87, 0xf3, 0x43, 0xb6, 0x62, 0x0b, 0xf6, 0x8f,
-	0x6f, 0xab, 0xde, 0x0c, 0x8a, 0x5b, 0xda, 0xa1, 0x66, 0x6a, 0x5b, 0x86, 0x6e, 0xe8, 0xd6, 0xd6,
-	0xec, 0xe9, 0x11, 0xff, 0x37, 0x1b, 0xf2, 0x5f, 0xbe, 0x43, 0xee, 0x42, 0xf5, 0xb1, 0x36, 0x24,
-	0x93, 0xae, 0x66, 0x10, 0xbb, 0x69, 0x8e, 0x3f, 0xd5, 0x26, 0x73, 0x62, 0x2b, 0xe4, 0x8b, 0x39,
-	0xb1, 0x29, 0xbe, 0x09, 0xcb, 0x86, 0x46, 0x47, 0xc7, 0xc4, 0xb2, 0x2b, 0x68, 0x33, 0x55, 0xcf,
-	0xdf, 0xbe, 0xd2, 0xe0, 0x9a, 0x35, 0xd8, 0xae, 0x0e, 0x67, 0x2a, 0x9e, 0x94, 0xbc, 0x07, 0xd7,
-	0x62, 0xf1, 0xec, 0xd9, 0xd4, 0xb4, 0x09, 0xfe, 0x21, 0x64, 0x74, 0x4a, 0x0c, 0x17, 0xad, 0x14,
-	0x42, 0x13, 0xb2, 0x5c, 0x42, 0xde, 0x85, 0x7c, 0x80, 0x8a, 0xaf, 0x03, 0x4c, 0x9c, 0xa5, 0x6a,
-	0x6a, 0x06, 0xa9, 0xa0, 0x4d, 0x54, 0xcf, 0x29, 0xb9, 0x89, 0x7b, 0x14, 0xbe, 0x0a, 0xd9, 0x5f,
-	0x30, 0xc1, 0x4a, 0x72, 0x33, 0x55, 0xcf, 0x29, 0x62, 0x25, 0xff, 0x09, 0xc1, 0xf5, 0x00, 0xcc,
-	0x8e, 0x66, 0x8d, 0x75, 0x53, 0x9b, 0xe8, 0xf4, 0xd4, 0xb5, 0x71, 0x03, 0xf2, 0x3e, 0x30, 0x57,
-	0x2c, 0xa7, 0x80, 0x87, 0x6c, 0x87, 0x9c, 0x90, 0xbc, 0x8c, 0x13, 0xf0, 0x4f, 0xa0, 0x30, 0x9a,
-	0xce, 0x4d, 0xaa, 0x1a, 0x84, 0x1e, 0x4f, 0xc7, 0x95, 0xd4, 0x26, 0xaa, 0x17, 0x7d, 0x63, 0x77,
-	0x1c, 0x5e, 0x87, 0xb1, 0x94, 0xfc, 0xc8, 0x5f, 0xc8, 0x07, 0x50, 0x3b, 0x4f, 0x57, 0xe1, 0xbf,
-	0x3b, 0x61, 0xff, 0x5d, 0x5f, 0xf4, 0x5f, 0x9f, 0x58, 0x3a, 0xb1, 0xd9, 0x11, 0xae, 0x27, 0x9f,
-	0x23, 0x58, 0x8f, 0x15, 0xb8, 0xc8, 0xa9, 0x1a, 0x60, 0xce, 0x66, 0xce, 0x54, 0x6d, 0xb6, 0x53,
-	0xf8, 0xe0, 0xce, 0x2b, 0x8f, 0x5e, 0xa0, 0xb6, 0x4c, 0x6a, 0x9d, 0x2a, 0xd2, 0x24, 0x42, 0xae,
-	0xee, 0x2c, 0xaa, 0xc6, 0x44, 0xb1, 0x04, 0xa9, 0xa7, 0xe4, 0x54, 0xe8, 0xe4, 0xfc, 0xc5, 0x57,
-	0x20, 0xc3, 0xf4, 0xa8, 0x24, 0x37, 0x51, 0x3d, 0xad, 0xf0, 0xc5, 0x87, 0xc9, 0x7b, 0x48, 0xfe,
-	0x1a, 0x41, 0x5e, 0x21, 0xda, 0xd8, 0xbd, 0xd2, 0x06, 0x2c, 0x7d, 0x31, 0xe7, 0xca, 0x46, 0xa2,
-	0xf6, 0x93, 0x39, 0xb1, 0xdc, 0x9b, 0x57, 0x5c, 0x21, 0xfc, 0x04, 0xca, 0xda, 0x68, 0x44, 0x66,
-	0x94, 0x8c, 0x55, 0x4b, 0xb8, 0x5a, 0xa5, 0xa7, 0x33, 0x61, 0x6c, 0xf1, 0xf6, 0xa6, 0xbb, 0x3f,
-	0x70, 0x4a, 0xc3, 0xbd, 0x94, 0xc1, 0xe9, 0x8c, 0x28, 0xeb, 0x2e, 0x40, 0x90, 0x6a, 0xcb, 0x77,
-	0xa1, 0x10, 0x24, 0xe0, 0x3c, 0x2c, 0xf5, 0x9b, 0x9d, 0xfd, 0xc7, 0xad, 0xbe, 0x94, 0xc0, 0x65,
-	0x28, 0xf5, 0x07, 0x4a, 0xab, 0xd9, 0x69, 0xed, 0xaa, 0x4f, 0x7a, 0x8a, 0xba, 0xb3, 0x77, 0xd0,
-	0x7d, 0xd4, 0x97, 0x90, 0xfc, 0xb1, 0xb3, 0x4b, 0xf3, 0xa0, 0xf0, 0x16, 0x2c, 0x59, 0xc4, 0x9e,
-	0x4f, 0xa8, 0x6b, 0xcf, 0x7a, 0xc4, 0x1e, 0x2e, 0xa7, 0xb8, 0x52, 0xf2, 0x29, 0xe0, 0x3e, 0xb5,
-	0x88, 0x66, 0x84, 0x60, 0xb6, 0xa1, 0x38, 0x3a, 0x9e, 0x9b, 0x4f, 0xc9, 0xd8, 0xbd, 0x4a, 0x8e,
-	0x76, 0xcd, 0x45, 0xe3, 0x7b, 0x76, 0xb8, 0x0c, 0xbf, 0x0c, 0x65, 0x65, 0x14, 0x5c, 0x3a, 0xd9,
-	0xe2, 0x78, 0xed, 0x54, 0xd5, 0xcd, 0x31, 0x39, 0x61, 0x57, 0x91, 0x52, 0x80, 0x91, 0xda, 0x0e,
-	0x45, 0xfe, 0x33, 0x82, 0x52, 0x0c, 0x0e, 0x3e, 0x84, 0x2c, 0xbb, 0xfc, 0x68, 0xea, 0xcf, 0x86,
-	0x3c, 0x56, 0xf6, 0x35, 0xdd, 0xda, 0xfe, 0xe0, 0xab, 0xe7, 0x1b, 0x89, 0xbf, 0x3f, 0xdf, 0xb8,
-	0x75, 0x99, 0x3a, 0xc6, 0xf7, 0x35, 0xc7, 0xda, 0x8c, 0x12, 0x4b, 0x11, 0xe8, 0xf8, 0x16, 0x64,
-	0x99, 0xc6, 0x6e, 0x9c, 0x96, 0x62, 0x8c, 0xdb, 0x4e, 0x3b, 0xe7, 0x28, 0x42, 0x50, 0xfe, 0x6d,
-	0x12, 0xf2, 0x01, 0x2e, 0xae, 0x41, 0xde, 0xd0, 0x4d, 0x95, 0xea, 0x06, 0x51, 0x59, 0xaa, 0x39,
-	0x36, 0xe6, 0x0c, 0xdd, 0x1c, 0xe8, 0x06, 0xe9, 0xd8, 0x8c, 0xaf, 0x9d, 0x78, 0xfc, 0xa4, 0xe0,
-	0x6b, 0x27, 0x82, 0x7f, 0x13, 0xd2, 0x4e, 0xf0, 0x88, 0xb4, 0xff, 0xbf, 0x18, 0x05, 0x1a, 0x2d,
-	0x73, 0x34, 0x1d, 0xeb, 0xe6, 0x91, 0xc2, 0x24, 0xf1, 0x3e, 0xa4, 0xc7, 0x1a, 0xd5, 0x2a, 0xe9,
-	0x4d, 0x54, 0x2f, 0x6c, 0x7f, 0x24, 0xbc, 0x70, 0xf7, 0x52, 0x5e, 0x38, 0x30, 0x6d, 0xed, 0x90,
-	0x6c, 0x9f, 0x52, 0xd2, 0x9f, 0xe8, 0x23, 0xa2, 0x30, 0x24, 0x79, 0x17, 0x96, 0xdd, 0x33, 0x9c,
-	0xa0, 0x3b, 0xe8, 0x3e, 0xea, 0xf6, 0x3e, 0xeb, 0x4a, 0x09, 0xbc, 0x04, 0xa9, 0x27, 0x3d, 0x45,
-	0x42, 0x78, 0x05, 0x72, 0x7b, 0xed, 0xfe, 0xa0, 0xf7, 0x40, 0x69, 0x76, 0xa4, 0x24, 0x2e, 0xc1,
-	0xea, 0xfd, 0xc7, 0xbd, 0xe6, 0x40, 0xf5, 0x89, 0x29, 0xf9, 0x9f, 0x08, 0x0a, 0xc1, 0x94, 0xc1,
-	0xef, 0x01, 0xb6, 0xa9, 0x66, 0x51, 0x66, 0xbc, 0x4d, 0x35, 0x63, 0xe6, 0x7b, 0x48, 0x62, 0x9c,
-	0x81, 0xcb, 0xe8, 0xd8, 0xb8, 0x0e, 0x12, 0x31, 0xc7, 0x61, 0x59, 0xee, 0xad, 0x22, 0x31, 0xc7,
-	0x41, 0xc9, 0x60, 0x8d, 0x4d, 0x5d, 0xaa, 0xc6, 0xfe, 0x14, 0xae, 0xd9, 0xcc, 0xa1, 0xba, 0x79,
-	0xa4, 0xf2, 0x8b, 0x54, 0x87, 0x0e, 0x53, 0xb5, 0xf5, 0x2f, 0x49, 0x65, 0xcc, 0x6a, 0x44, 0xc5,
-	0x13, 0x61, 0x6e, 0xb7, 0xb7, 0x1d, 0x81, 0xbe, 0xfe, 0x25, 0x79, 0x98, 0x5e, 0x4e, 0x4b, 0x19,
-	0x25, 0x73, 0xac, 0x9b, 0xd4, 0x96, 0xff, 0x80, 0xe0, 0x4a, 0xeb, 0x84, 0x18, 0xb3, 0x89, 0x66,
-	0xbd, 0x11, 0x73, 0x6f, 0x2d, 0x98, 0xbb, 0x1e, 0x67, 0xae, 0x1d, 0x68, 0xac, 0x8f, 0x60, 0x25,
-	0x94, 0xec, 0xf8, 0x43, 0x00, 0x76, 0x52, 0x5c, 0x9d, 0x9b, 0x0d, 0x1b, 0xce, 0x71, 0x3c, 0xf5,
-	0x44, 0xb4, 0x07, 0xa4, 0xe5, 0xff, 0x24, 0xa1, 0xc4, 0xd0, 0xdc, 0x2a, 0x21, 0x30, 0x3f, 0x86,
-	0x3c, 0x77, 0x65, 0x10, 0xb4, 0xec, 0xaa, 0xe6, 0x43, 0x06, 0xb3, 0x28, 0xb8, 0x23, 0xa2, 0x54,
-	0xf2, 0xbb, 0x28, 0x85, 0x1f, 0x82, 0xe4, 0xdf, 0xa8, 0x40, 0xe0, 0xce, 0x79, 0x2b, 0x54, 0xee,
-	0xb8, 0xce, 0x21, 0x98, 0x55, 0x6f, 0xa3, 0xa8, 0x36, 0x77, 0xa1, 0xac, 0xdb, 0xaa, 0x73, 0x1b,
-	0xd3, 0x43, 0x81, 0xa5, 0x72, 0x19, 0x96, 0x63, 0xcb, 0x4a, 0x49, 0xb7, 0x5b, 0xe6, 0xb8, 0x77,
-	0xc8, 0xe5, 0x39, 0x24, 0xfe, 0x1c, 0xca, 0x51, 0x0d, 0x44, 0x68, 0x55, 0x32, 0x4c, 0x91, 0x8d,
-	0x73, 0x15, 0x11, 0xf1, 0xc5, 0xd5, 0x59, 0x8f, 0xa8, 0xc3, 0x99, 0xf2, 0xef, 0x10, 0xac, 0x2d,
-	0x6c, 0x7c, 0x63, 0x85, 0x71, 0x43, 0xdc, 0xad, 0xca, 0x26, 0x0e, 0xb7, 0x72, 0x33, 0x12, 0x6b,
-	0xd9, 0xb2, 0x0e, 0xe5, 0x73, 0xcc, 0xc2, 0x6f, 0x43, 0x41, 0xb8, 0x83, 0x97, 0x7d, 0xc4, 0xb2,
-	0x2b, 0xcf, 0x69, 0xac, 0xee, 0xe3, 0x1f, 0x45, 0xea, 0xee, 0x8a, 0x37, 0xed, 0xc4, 0x54, 0xdc,
-	0x3e, 0xac, 0x47, 0xf2, 0xed, 0x7b, 0x08, 0xea, 0xbf, 0x21, 0xc0, 0xc1, 0x39, 0x52, 0xe4, 0xf0,
-	0x05, 0x33, 0x4e, 0x7c, 0x8a, 0x27, 0xbf, 0x43, 0x8a, 0xa7, 0x2e, 0x4c, 0x71, 0x27, 0xe4, 0x2e,
-	0x91, 0xe2, 0xf7, 0xa0, 0x14, 0xd2, 0x5f, 0xf8, 0xe4, 0x6d, 0x28, 0x04, 0xa6, 0x30, 0x77, 0x42,
-	0xcd, 0xfb, 0xa3, 0x94, 0x2d, 0xff, 0x1e, 0xc1, 0x9a, 0x3f, 0x76, 0xbf, 0xd9, 0xea, 0x75, 0x29,
-	0xd3, 0x7e, 0x2c, 0xae, 0x46, 0xe8, 0x27, 0x2c, 0xbb, 0x68, 0xf4, 0x96, 0x1f, 0x82, 0x74, 0x60,
-	0x13, 0xab, 0x4f, 0x35, 0xea, 0x59, 0x15, 0x1d, 0xae, 0xd1, 0x25, 0x87, 0xeb, 0xbf, 0x22, 0x58,
-	0x0b, 0x80, 0x09, 0x15, 0x6e, 0xb8, 0x9f, 0x5e, 0xfa, 0xd4, 0x54, 0x2d, 0x8d, 0xf2, 0x08, 0x41,
-	0xca, 0x8a, 0x47, 0x55, 0x34, 0x4a, 0x9c, 0x20, 0x32, 0xe7, 0x86, 0x3f, 0x01, 0x3b, 0xe1, 0x9f,
-	0x33, 0xe7, 0x6e, 0x0e, 0xbf, 0x07, 0x58, 0x9b, 0xe9, 0x6a, 0x04, 0x29, 0xc5, 0x90, 0x24, 0x6d,
-	0xa6, 0xb7, 0x43, 0x60, 0x0d, 0x28, 0x59, 0xf3, 0x09, 0x89, 0x8a, 0xa7, 0x99, 0xf8, 0x9a, 0xc3,
-	0x0a, 0xc9, 0xcb, 0x9f, 0x43, 0xc9, 0x51, 0xbc, 0xbd, 0x1b, 0x56, 0xbd, 0x0c, 0x4b, 0x73, 0x9b,
-	0x58, 0xaa, 0x3e, 0x16, 0x51, 0x9d, 0x75, 0x96, 0xed, 0x31, 0x7e, 0x5f, 0x4c, 0x13, 0x49, 0x76,
-	0x37, 0x5e, 0xf1, 0x5c, 0x30, 0x5e, 0x8c, 0x0a, 0x0f, 0x00, 0x3b, 0x2c, 0x3b, 0x8c, 0x7e, 0x0b,
-	0x32, 0xb6, 0x43, 0x88, 0xce, 0x88, 0x31, 0x9a, 0x28, 0x5c, 0x52, 0xfe, 0x0b, 0x82, 0x5a, 0x87,
-	0x50, 0x4b, 0x1f, 0xd9, 0xf7, 0xa7, 0x56, 0x38, 0x14, 0x5e, 0x73, 0x48, 0xde, 0x83, 0x82, 0x1b,
-	0x6b, 0xaa, 0x4d, 0xe8, 0xab, 0x9b, 0x6a, 0xde, 0x15, 0xed, 0x13, 0x2a, 0x3f, 0x82, 0x8d, 0x73,
-	0x75, 0x16, 0xae, 0xa8, 0x43, 0xd6, 0x60, 0x22, 0xc2, 0x17, 0x92, 0x5f, 0x90, 0xf8, 0x56, 0x45,
-	0xf0, 0xe5, 0x19, 0x5c, 0x15, 0x60, 0x1d, 0x42, 0x35, 0xc7, 0xbb, 0xae, 0xe1, 0x57, 0x20, 0x33,
-	0xd1, 0x0d, 0x9d, 0x32, 0x5b, 0xd7, 0x14, 0xbe, 0x70, 0x0c, 0x64, 0x7f, 0xd4, 0x19, 0xb1, 0x54,
-	0x71, 0x46, 0x92, 0x09, 0x14, 0x19, 0x7d, 0x9f, 0x58, 0x1c, 0xcf, 0xf9, 0xbe, 0x15, 0xfc, 0x14,
-	0xbf, 0x6b, 0x71, 0x62, 0x0f, 0xca, 0x0b, 0x27, 0x0a, 0xb5, 0xef, 0xc2, 0xb2, 0x21, 0x68, 0x42,
-	0xf1, 0x4a, 0x54, 0x71, 0x6f, 0x8f, 0x27, 0x29, 0xff, 0x1b, 0xc1, 0x6a, 0xa4, 0xd1, 0x3b, 0x6a,
-	0x1e, 0x5a, 0x53, 0x43, 0x75, 0x1f, 0x29, 0xfc, 0x90, 0x2b, 0x3a, 0xf4, 0xb6, 0x20, 0xb7, 0xc7,
-	0xc1, 0x98, 0x4c, 0x86, 0x62, 0xd2, 0xef, 0x72, 0xa9, 0xd7, 0xda, 0xe5, 0xfc, 0x36, 0x94, 0xbe,
-	0xb8, 0x0d, 0x7d, 0x8d, 0x20, 0xc3, 0x2d, 0x7c, 0x5d, 0x71, 0x59, 0x85, 0x65, 0x22, 0xc6, 0x70,
-	0x76, 0x71, 0x19, 0xc5, 0x5b, 0xbf, 0x86, 0xa1, 0xbf, 0x09, 0x2b, 0xa1, 0x08, 0xfe, 0x1f, 0xde,
-	0x6f, 0x54, 0x28, 0x04, 0x39, 0xf8, 0x86, 0xf8, 0x96, 0xe1, 0x55, 0x76, 0xcd, 0xdd, 0xcd, 0xd8,
-	0xec, 0xc3, 0x97, 0x7f, 0xc0, 0x60, 0x48, 0xb3, 0xf6, 0xca, 0x2f, 0x9d, 0xfd, 0xf7, 0xbf, 0xd7,
-	0x79, 0xc4, 0xf2, 0x85, 0xfc, 0x2b, 0x04, 0x45, 0x3f, 0xbe, 0xee, 0xeb, 0x13, 0xf2, 0x7d, 0x84,
-	0x57, 0x15, 0x96, 0x0f, 0xf5, 0x09, 0x61, 0x3a, 0xf0, 0xe3, 0xbc, 0xb5, 0xa3, 0x9b, 0xef, 0x67,
-	0xee, 0xa9, 0x77, 0xeb, 0x90, 0x0f, 0x34, 0x0a, 0xe7, 0x5b, 0xa8, 0xdd, 0x55, 0x3b, 0xad, 0x4e,
-	0x4f, 0xf9, 0x99, 0x94, 0xc0, 0x00, 0xd9, 0xe6, 0xce, 0xa0, 0xfd, 0x69, 0x4b, 0x42, 0xef, 0x3e,
-	0x84, 0x9c, 0x67, 0x2c, 0xce, 0x41, 0xa6, 0xf5, 0xc9, 0x41, 0xf3, 0xb1, 0x94, 0x70, 0xb6, 0x74,
-	0x7b, 0x03, 0x95, 0x2f, 0x11, 0x5e, 0x85, 0xbc, 0xd2, 0x7a, 0xd0, 0x7a, 0xa2, 0x76, 0x9a, 0x83,
-	0x9d, 0x3d, 0x29, 0x89, 0x31, 0x14, 0x39, 0xa1, 0xdb, 0x13, 0xb4, 0xd4, 0xed, 0x5f, 0x2f, 0xc1,
-	0xb2, 0x6b, 0x0d, 0xfe, 0x00, 0xd2, 0xfb, 0x73, 0xfb, 0x18, 0x5f, 0xf5, 0x33, 0xe1, 0x33, 0x4b,
-	0xa7, 0x44, 0x54, 0x8c, 0x6a, 0x79, 0x81, 0xce, 0xf3, 0x5a, 0x4e, 0xe0, 0x5d, 0xc8, 0x07, 0x26,
-	0x35, 0x1c, 0xfb, 0xba, 0x51, 0xbd, 0x16, 0x33, 0xab, 0xfa, 0x18, 0x37, 0x11, 0xee, 0x41, 0x91,
-	0xb1, 0xdc, 0x49, 0xcc, 0xc6, 0xde, 0xa7, 0x6a, 0xdc, 0xc7, 0x50, 0xf5, 0xfa, 0x39, 0x5c, 0x4f,
-	0xad, 0xbd, 0xf0, 0x8b, 0x5d, 0x35, 0xee, 0x71, 0x2f, 0xaa, 0x5c, 0xcc, 0xc0, 0x23, 0x27, 0x70,
-	0x0b, 0xc0, 0x1f, 0x17, 0xf0, 0x5b, 0x21, 0xe1, 0xe0, 0x88, 0x53, 0xad, 0xc6, 0xb1, 0x3c, 0x98,
-	0x6d, 0xc8, 0x79, 0x4d, 0x0f, 0x57, 0x62, 0xfa, 0x20, 0x07, 0x39, 0xbf, 0x43, 0xca, 0x09, 0x7c,
-	0x1f, 0x0a, 0xcd, 0xc9, 0xe4, 0x32, 0x30, 0xd5, 0x20, 0xc7, 0x8e, 0xe2, 0x4c, 0xbc, 0x42, 0x1d,
-	0xed, 0x33, 0xf8, 0x1d, 0x2f, 0xab, 0x5e, 0xd9, 0x3c, 0xab, 0x3f, 0xb8, 0x50, 0xce, 0x3b, 0x6d,
-	0x00, 0xab, 0x91, 0xb6, 0x80, 0x6b, 0x91, 0xdd, 0x91, 0x0e, 0x55, 0xdd, 0x38, 0x97, 0xef, 0xa1,
-	0x0e, 0xc5, 0x80, 0x1a, 0x7e, 0xdc, 0xc5, 0xf2, 0xe2, 0x25, 0x44, 0x5f, 0x92, 0xab, 0xff, 0xff,
-	0x4a, 0x99, 0x40, 0x54, 0x3e, 0x85, 0xab, 0xf1, 0x6f, 0xa0, 0xf8, 0x46, 0x4c, 0xcc, 0x2c, 0xbe,
-	0xe7, 0x56, 0xdf, 0xb9, 0x48, 0xcc, 0x3f, 0x6c, 0xfb, 0xa3, 0x67, 0x2f, 0x6a, 0x89, 0x6f, 0x5e,
-	0xd4, 0x12, 0xdf, 0xbe, 0xa8, 0xa1, 0x5f, 0x9e, 0xd5, 0xd0, 0x1f, 0xcf, 0x6a, 0xe8, 0xab, 0xb3,
-	0x1a, 0x7a, 0x76, 0x56, 0x43, 0xff, 0x38, 0xab, 0xa1, 0x7f, 0x9d, 0xd5, 0x12, 0xdf, 0x9e, 0xd5,
-	0xd0, 0x6f, 0x5e, 0xd6, 0x12, 0xcf, 0x5e, 0xd6, 0x12, 0xdf, 0xbc, 0xac, 0x25, 0x7e, 0x9e, 0x1d,
-	0x4d, 0x74, 0x62, 0xd2, 0x61, 0x96, 0x3d, 0xa1, 0xdf, 0xf9, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0xae, 0xae, 0xb4, 0x3c, 0xbd, 0x17, 0x00, 0x00,
+	// 2155 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x59, 0xcd, 0x6f, 0x1b, 0xc7,
+	0x15, 0xe7, 0xf0, 0x43, 0x16, 0x1f, 0x29, 0x8a, 0x1a, 0x4a, 0x22, 0x43, 0x5b, 0x94, 0xb2, 0x85,
+	0x53, 0x36, 0x75, 0x28, 0xf9, 0xa3, 0x85, 0x13, 0x24, 0x48, 0x29, 0x89, 0xb6, 0x64, 0x9b, 0xa2,
+	0xb2, 0xa4, 0x12, 0xb7, 0x40, 0xb0, 0x58, 0x92, 0x43, 0x69, 0x21, 0xee, 0x92, 0xd9, 0x5d, 0x1a,
+	0x52, 0x4e, 0x45, 0xff, 0x82, 0xde, 0x7a, 0x68, 0x51, 0xa0, 0xb7, 0xa2, 0x87, 0xa2, 0x28, 0x50,
+	0xf4, 0xd6, 0x73, 0x2e, 0x01, 0x7c, 0x0c, 0x7a, 0x30, 0x6a, 0xb9, 0x87, 0xf6, 0x16, 0xa0, 0xff,
+	0x40, 0x31, 0x1f, 0xfb, 0xc9, 0x95, 0x25, 0x07, 0xb1, 0x4f, 0xdc, 0x79, 0xef, 0xcd, 0x6f, 0xde,
+	0x7b, 0xf3, 0xde, 0x9b, 0x37, 0x43, 0xc8, 0x69, 0xc6, 0x21, 0xb1, 0x6c, 0x62, 0xd6, 0xc6, 0xe6,
+	0xc8, 0x1e, 0xe1, 0x99, 0xde, 0xc8, 0xb4, 0xc9, 0x49, 0xf9, 0xbd, 0x43, 0xcd, 0x3e, 0x9a, 0x74,
+	0x6b, 0xbd, 0x91, 0xbe, 0x7e, 0x38, 0x3a, 0x1c, 0xad, 0x33, 0x76, 0x77, 0x32, 0x60, 0x23, 0x36,
+	0x60, 0x5f, 0x7c, 0x5a, 0x79, 0xc3, 0x2f, 0x6e, 0xaa, 0x03, 0xd5, 0x50, 0xd7, 0x75, 0x4d, 0xd7,
+	0xcc, 0xf5, 0xf1, 0xf1, 0x21, 0xff, 0x1a, 0x77, 0xf9, 0x2f, 0x9f, 0x21, 0xed, 0x41, 0xf9, 0x91,
+	0xda, 0x25, 0xc3, 0x3d, 0x55, 0x27, 0x56, 0xdd, 0xe8, 0x7f, 0xaa, 0x0e, 0x27, 0xc4, 0x92, 0xc9,
+	0x17, 0x13, 0x62, 0xd9, 0x78, 0x03, 0x66, 0x75, 0xd5, 0xee, 0x1d, 0x11, 0xd3, 0x2a, 0xa1, 0xb5,
+	0x44, 0x35, 0x73, 0x6b, 0xb1, 0xc6, 0x35, 0xab, 0xb1, 0x59, 0x4d, 0xce, 0x94, 0x5d, 0x29, 0x69,
+	0x07, 0xae, 0x46, 0xe2, 0x59, 0xe3, 0x91, 0x61, 0x11, 0xfc, 0x23, 0x48, 0x69, 0x36, 0xd1, 0x1d,
+	0xb4, 0x42, 0x00, 0x4d, 0xc8, 0x72, 0x09, 0x69, 0x1b, 0x32, 0x3e, 0x2a, 0x5e, 0x01, 0x18, 0xd2,
+	0xa1, 0x62, 0xa8, 0x3a, 0x29, 0xa1, 0x35, 0x54, 0x4d, 0xcb, 0xe9, 0xa1, 0xb3, 0x14, 0x5e, 0x86,
+	0x99, 0x27, 0x4c, 0xb0, 0x14, 0x5f, 0x4b, 0x54, 0xd3, 0xb2, 0x18, 0x49, 0x7f, 0x42, 0xb0, 0xe2,
+	0x83, 0xd9, 0x52, 0xcd, 0xbe, 0x66, 0xa8, 0x43, 0xcd, 0x3e, 0x75, 0x6c, 0x5c, 0x85, 0x8c, 0x07,
+	0xcc, 0x15, 0x4b, 0xcb, 0xe0, 0x22, 0x5b, 0x01, 0x27, 0xc4, 0x2f, 0xe3, 0x04, 0xfc, 0x53, 0xc8,
+	0xf6, 0x46, 0x13, 0xc3, 0x56, 0x74, 0x62, 0x1f, 0x8d, 0xfa, 0xa5, 0xc4, 0x1a, 0xaa, 0xe6, 0x3c,
+	0x63, 0xb7, 0x28, 0xaf, 0xc9, 0x58, 0x72, 0xa6, 0xe7, 0x0d, 0xa4, 0x03, 0xa8, 0x9c, 0xa7, 0xab,
+	0xf0, 0xdf, 0xed, 0xa0, 0xff, 0x56, 0xa6, 0xfd, 0xd7, 0x26, 0xa6, 0x46, 0x2c, 0xb6, 0x84, 0xe3,
+	0xc9, 0x67, 0x08, 0x96, 0x22, 0x05, 0x2e, 0x72, 0xaa, 0x0a, 0x98, 0xb3, 0x99, 0x33, 0x15, 0x8b,
+	0xcd, 0x14, 0x3e, 0xb8, 0xfd, 0xd2, 0xa5, 0xa7, 0xa8, 0x0d, 0xc3, 0x36, 0x4f, 0xe5, 0xfc, 0x30,
+	0x44, 0x2e, 0x6f, 0xc1, 0x52, 0xa4, 0x28, 0xce, 0x43, 0xe2, 0x98, 0x9c, 0x0a, 0x9d, 0xe8, 0x27,
+	0x5e, 0x84, 0x14, 0xd3, 0xa3, 0x14, 0x5f, 0x43, 0xd5, 0xa4, 0xcc, 0x07, 0x1f, 0xc4, 0xef, 0x22,
+	0xe9, 0x3e, 0x14, 0xea, 0x3d, 0x5b, 0x7b, 0x22, 0x00, 0xbe, 0x7b, 0xf4, 0xfe, 0x0c, 0x16, 0x83,
+	0x40, 0xc2, 0xed, 0x55, 0x98, 0xd1, 0x89, 0x6d, 0x6a, 0x3d, 0x81, 0x93, 0x17, 0x38, 0xe3, 0x6e,
+	0xad, 0xc9, 0xe8, 0xb2, 0xe0, 0x4b, 0x5f, 0x23, 0xc8, 0xc8, 0x44, 0xed, 0x3b, 0x3a, 0xd4, 0xe0,
+	0xca, 0x17, 0x13, 0xee, 0xb7, 0x90, 0x0a, 0x9f, 0x4c, 0x88, 0xe9, 0x04, 0xa1, 0xec, 0x08, 0xe1,
+	0xc7, 0x50, 0x54, 0x7b, 0x3d, 0x32, 0xb6, 0x49, 0x5f, 0x31, 0xc5, 0xf2, 0x8a, 0x7d, 0x3a, 0x16,
+	0x7e, 0xcf, 0xdd, 0x5a, 0x73, 0xe6, 0xfb, 0x56, 0xa9, 0x39, 0x8a, 0x76, 0x4e, 0xc7, 0x44, 0x5e,
+	0x72, 0x00, 0xfc, 0x54, 0x4b, 0xba, 0x03, 0x59, 0x3f, 0x01, 0x67, 0xe0, 0x4a, 0xbb, 0xde, 0xdc,
+	0x7f, 0xd4, 0x68, 0xe7, 0x63, 0xb8, 0x08, 0x85, 0x76, 0x47, 0x6e, 0xd4, 0x9b, 0x8d, 0x6d, 0xe5,
+	0x71, 0x4b, 0x56, 0xb6, 0x76, 0x0e, 0xf6, 0x1e, 0xb6, 0xf3, 0x48, 0xfa, 0x18, 0xb2, 0x7c, 0x21,
+	0xe1, 0x89, 0x75, 0xb8, 0x62, 0x12, 0x6b, 0x32, 0xb4, 0x1d, 0x7b, 0x96, 0x42, 0xf6, 0x70, 0x39,
+	0xd9, 0x91, 0x92, 0x4e, 0x01, 0xb7, 0x6d, 0x93, 0xa8, 0x7a, 0x00, 0x66, 0x13, 0x72, 0xbd, 0xa3,
+	0x89, 0x71, 0x4c, 0xfa, 0x4e, 0x54, 0x71, 0xb4, 0xab, 0x0e, 0x1a, 0x9f, 0xb3, 0xc5, 0x65, 0xc4,
+	0x6e, 0xcc, 0xf5, 0xfc, 0x43, 0x9a, 0xb8, 0xd4, 0x6b, 0xa7, 0x8a, 0x66, 0xf4, 0xc9, 0x09, 0x8b,
+	0x8a, 0x84, 0x0c, 0x8c, 0xb4, 0x4b, 0x29, 0xd2, 0x5f, 0x10, 0x14, 0x22, 0x70, 0xf0, 0x00, 0x66,
+	0x58, 0x1c, 0x86, 0xab, 0xd0, 0xb8, 0xcb, 0xe3, 0x62, 0x5f, 0xd5, 0xcc, 0xcd, 0xf7, 0xbf, 0x7a,
+	0xb6, 0x1a, 0xfb, 0xe7, 0xb3, 0xd5, 0x9b, 0x97, 0x29, 0xa9, 0x7c, 0x5e, 0xbd, 0xaf, 0x8e, 0x6d,
+	0x62, 0xca, 0x02, 0x1d, 0xdf, 0x84, 0x19, 0xa6, 0xb1, 0x93, 0x32, 0x85, 0x08, 0xe3, 0x36, 0x93,
+	0x74, 0x1d, 0x59, 0x08, 0x4a, 0xbf, 0x89, 0x43, 0xc6, 0xc7, 0xc5, 0x15, 0xc8, 0xe8, 0x9a, 0xa1,
+	0xd8, 0x9a, 0x4e, 0x14, 0x96, 0xf5, 0xd4, 0xc6, 0xb4, 0xae, 0x19, 0x1d, 0x4d, 0x27, 0x4d, 0x8b,
+	0xf1, 0xd5, 0x13, 0x97, 0x1f, 0x17, 0x7c, 0xf5, 0x44, 0xf0, 0x37, 0x20, 0x49, 0x83, 0x47, 0x54,
+	0xa0, 0x6b, 0x11, 0x0a, 0xd4, 0x1a, 0x46, 0x6f, 0xd4, 0xd7, 0x8c, 0x43, 0x99, 0x49, 0xe2, 0x7d,
+	0x48, 0xf6, 0x55, 0x5b, 0x2d, 0x25, 0xd7, 0x50, 0x35, 0xbb, 0xf9, 0xa1, 0xf0, 0xc2, 0x9d, 0x4b,
+	0x79, 0xe1, 0xc0, 0xb0, 0xd4, 0x01, 0xd9, 0x3c, 0xb5, 0x49, 0x7b, 0xa8, 0xf5, 0x88, 0xcc, 0x90,
+	0xa4, 0x6d, 0x98, 0x75, 0xd6, 0xa0, 0x41, 0x77, 0xb0, 0xf7, 0x70, 0xaf, 0xf5, 0xd9, 0x5e, 0x3e,
+	0x86, 0xaf, 0x40, 0xe2, 0x71, 0x4b, 0xce, 0x23, 0x3c, 0x07, 0xe9, 0x9d, 0xdd, 0x76, 0xa7, 0x75,
+	0x5f, 0xae, 0x37, 0xf3, 0x71, 0x5c, 0x80, 0xf9, 0x7b, 0x8f, 0x5a, 0xf5, 0x8e, 0xe2, 0x11, 0x13,
+	0xd2, 0xbf, 0x11, 0x64, 0xfd, 0x29, 0x83, 0x6f, 0x00, 0xb6, 0x6c, 0xd5, 0xb4, 0x99, 0xf1, 0x96,
+	0xad, 0xea, 0x63, 0xcf, 0x43, 0x79, 0xc6, 0xe9, 0x38, 0x8c, 0xa6, 0x85, 0xab, 0x90, 0x27, 0x46,
+	0x3f, 0x28, 0xcb, 0xbd, 0x95, 0x23, 0x46, 0xdf, 0x2f, 0xe9, 0xaf, 0x1a, 0x89, 0x4b, 0x95, 0xfb,
+	0x8f, 0xe0, 0xaa, 0xc5, 0x1c, 0xaa, 0x19, 0x87, 0x0a, 0xdf, 0x48, 0xa5, 0x4b, 0x99, 0x8a, 0xa5,
+	0x7d, 0x49, 0x4a, 0x7d, 0x56, 0xae, 0x4a, 0xae, 0x08, 0x73, 0xbb, 0xb5, 0x49, 0x05, 0xda, 0xda,
+	0x97, 0xe4, 0x41, 0x72, 0x36, 0x99, 0x4f, 0xc9, 0xa9, 0x23, 0xcd, 0xb0, 0x2d, 0xe9, 0x0f, 0x08,
+	0x16, 0x1b, 0x27, 0x44, 0x1f, 0x0f, 0x55, 0xf3, 0x8d, 0x98, 0x7b, 0x73, 0xca, 0xdc, 0xa5, 0x28,
+	0x73, 0x2d, 0x5f, 0x95, 0x7c, 0x08, 0x73, 0x81, 0x64, 0xc7, 0x1f, 0x00, 0xb0, 0x95, 0xa2, 0xea,
+	0xdc, 0xb8, 0x5b, 0xa3, 0xcb, 0xf1, 0xd4, 0x13, 0xd1, 0xee, 0x93, 0x96, 0xfe, 0x17, 0x87, 0x02,
+	0x43, 0x73, 0xaa, 0x84, 0xc0, 0xfc, 0x18, 0x32, 0xdc, 0x95, 0x7e, 0xd0, 0xa2, 0xa3, 0x9a, 0x07,
+	0xe9, 0xcf, 0x22, 0xff, 0x8c, 0x90, 0x52, 0xf1, 0x57, 0x51, 0x0a, 0x3f, 0x80, 0xbc, 0xb7, 0xa3,
+	0x02, 0x81, 0x3b, 0xe7, 0xad, 0x40, 0xb9, 0xe3, 0x3a, 0x07, 0x60, 0xe6, 0xdd, 0x89, 0x9c, 0x8c,
+	0xef, 0x40, 0x51, 0xb3, 0x14, 0xba, 0x1b, 0xa3, 0x81, 0xc0, 0x52, 0xb8, 0x0c, 0xcb, 0xb1, 0x59,
+	0xb9, 0xa0, 0x59, 0x0d, 0xa3, 0xdf, 0x1a, 0x70, 0x79, 0x0e, 0x89, 0x3f, 0x87, 0x62, 0x58, 0x03,
+	0x11, 0x5a, 0xa5, 0x14, 0x53, 0x64, 0xf5, 0x5c, 0x45, 0x44, 0x7c, 0x71, 0x75, 0x96, 0x42, 0xea,
+	0x70, 0xa6, 0xf4, 0x3b, 0x04, 0x0b, 0x53, 0x13, 0xdf, 0x58, 0x61, 0x5c, 0x15, 0x7b, 0xab, 0xb0,
+	0xe6, 0xc7, 0xa9, 0xdc, 0x8c, 0xc4, 0xba, 0x07, 0x49, 0x83, 0xe2, 0x39, 0x66, 0xe1, 0xb7, 0x21,
+	0x2b, 0xdc, 0xc1, 0xcb, 0x3e, 0x62, 0xd9, 0x95, 0xe1, 0x34, 0x56, 0xf7, 0xf1, 0x8f, 0x43, 0x75,
+	0x77, 0xce, 0x6d, 0xbc, 0x22, 0x2a, 0x6e, 0x1b, 0x96, 0x42, 0xf9, 0xf6, 0x3d, 0x04, 0xf5, 0x3f,
+	0x10, 0x60, 0x7f, 0x4b, 0x2b, 0x72, 0xf8, 0x82, 0x76, 0x2b, 0x3a, 0xc5, 0xe3, 0xaf, 0x90, 0xe2,
+	0x89, 0x0b, 0x53, 0x9c, 0x86, 0xdc, 0x25, 0x52, 0xfc, 0x2e, 0x14, 0x02, 0xfa, 0x0b, 0x9f, 0xbc,
+	0x0d, 0x59, 0x5f, 0x43, 0xe8, 0x34, 0xcb, 0x19, 0xaf, 0xab, 0xb3, 0xa4, 0xdf, 0x23, 0x58, 0xf0,
+	0x6e, 0x00, 0x6f, 0xb6, 0x7a, 0x5d, 0xca, 0xb4, 0x9f, 0x00, 0xf6, 0xeb, 0x27, 0x2c, 0xbb, 0xe8,
+	0x16, 0x20, 0x3d, 0x80, 0xfc, 0x81, 0x45, 0xcc, 0xb6, 0xad, 0xda, 0xae, 0x55, 0xe1, 0x3e, 0x1f,
+	0x5d, 0xb2, 0xcf, 0xff, 0x3b, 0x82, 0x05, 0x1f, 0x98, 0x50, 0xe1, 0xba, 0x73, 0x0b, 0xd4, 0x46,
+	0x86, 0x62, 0xaa, 0x36, 0x8f, 0x10, 0x24, 0xcf, 0xb9, 0x54, 0x59, 0xb5, 0x09, 0x0d, 0x22, 0x63,
+	0xa2, 0x7b, 0xcd, 0x38, 0x0d, 0xff, 0xb4, 0x31, 0x71, 0x72, 0xf8, 0x06, 0x60, 0x75, 0xac, 0x29,
+	0x21, 0xa4, 0x04, 0x43, 0xca, 0xab, 0x63, 0x6d, 0x37, 0x00, 0x56, 0x83, 0x82, 0x39, 0x19, 0x92,
+	0xb0, 0x78, 0x92, 0x89, 0x2f, 0x50, 0x56, 0x40, 0x5e, 0xfa, 0x1c, 0x0a, 0x54, 0xf1, 0xdd, 0xed,
+	0xa0, 0xea, 0x45, 0xb8, 0x32, 0xb1, 0x88, 0xa9, 0x68, 0x7d, 0x11, 0xd5, 0x33, 0x74, 0xb8, 0xdb,
+	0xc7, 0xef, 0x89, 0x6e, 0x22, 0xbe, 0x86, 0xfc, 0xc5, 0x73, 0xca, 0x78, 0xd1, 0x2a, 0xdc, 0x07,
+	0x4c, 0x59, 0x56, 0x10, 0xfd, 0x26, 0xa4, 0x2c, 0x4a, 0x08, 0xf7, 0x88, 0x11, 0x9a, 0xc8, 0x5c,
+	0x52, 0xfa, 0x2b, 0x82, 0x0a, 0xef, 0xcc, 0xad, 0x7b, 0x23, 0x33, 0x18, 0x0a, 0xaf, 0x39, 0x24,
+	0xef, 0x42, 0xd6, 0x89, 0x35, 0xc5, 0x22, 0xf6, 0xcb, 0x0f, 0xd5, 0x8c, 0x23, 0xda, 0x26, 0xb6,
+	0xf4, 0x10, 0x56, 0xcf, 0xd5, 0xf9, 0x95, 0x2f, 0x22, 0x63, 0x58, 0x16, 0x60, 0x4d, 0x62, 0xab,
+	0xd4, 0xbb, 0x8e, 0xe1, 0x8b, 0x90, 0x1a, 0x6a, 0xba, 0x66, 0x33, 0x5b, 0x17, 0x64, 0x3e, 0xa0,
+	0x06, 0xb2, 0x0f, 0x65, 0x4c, 0x4c, 0x45, 0xac, 0x11, 0x67, 0x02, 0x39, 0x46, 0xdf, 0x27, 0x26,
+	0xc7, 0xa3, 0x57, 0x6d, 0xc1, 0x4f, 0xf0, 0xbd, 0x16, 0x2b, 0xb6, 0xa0, 0x38, 0xb5, 0xa2, 0x50,
+	0xfb, 0x0e, 0xcc, 0xea, 0x82, 0x26, 0x14, 0x2f, 0x85, 0x15, 0x77, 0xe7, 0xb8, 0x92, 0xd2, 0x7f,
+	0x11, 0xcc, 0x87, 0x0e, 0x7a, 0xaa, 0xe6, 0xc0, 0x1c, 0xe9, 0x8a, 0xf3, 0x5e, 0xe2, 0x85, 0x5c,
+	0x8e, 0xd2, 0x77, 0x05, 0x79, 0xb7, 0xef, 0x8f, 0xc9, 0x78, 0x20, 0x26, 0xbd, 0x53, 0x2e, 0xf1,
+	0x5a, 0x4f, 0x39, 0xef, 0x18, 0x4a, 0x5e, 0x7c, 0x0c, 0x7d, 0x8d, 0x20, 0xc5, 0x2d, 0x7c, 0x5d,
+	0x71, 0x59, 0x86, 0x59, 0x22, 0xda, 0x70, 0xb6, 0x71, 0x29, 0xd9, 0x1d, 0xbf, 0x86, 0xa6, 0xbf,
+	0x0e, 0x73, 0x81, 0x08, 0xfe, 0x0e, 0x97, 0x71, 0x05, 0xb2, 0x7e, 0x0e, 0xbe, 0x2e, 0xee, 0x32,
+	0xbc, 0xca, 0x2e, 0x38, 0xb3, 0x19, 0x9b, 0x5d, 0x7c, 0x19, 0x1b, 0x63, 0x48, 0xb2, 0xe3, 0x95,
+	0x6f, 0x3a, 0xfb, 0xf6, 0x9e, 0x0e, 0x78, 0xc4, 0xf2, 0x81, 0xf4, 0x5b, 0x04, 0x39, 0x2f, 0xbe,
+	0xee, 0x69, 0x43, 0xf2, 0x7d, 0x84, 0x57, 0x19, 0x66, 0x07, 0xda, 0x90, 0x30, 0x1d, 0xf8, 0x72,
+	0xee, 0x98, 0xea, 0xe6, 0xf9, 0x99, 0x7b, 0x8a, 0xa6, 0xd3, 0x68, 0x30, 0xa0, 0x95, 0x22, 0xc5,
+	0xf6, 0x4d, 0x8c, 0xa4, 0xbf, 0x21, 0x28, 0x74, 0x4c, 0xd5, 0xb0, 0x06, 0xc4, 0xec, 0xb4, 0xb7,
+	0x37, 0x9d, 0xf4, 0xbd, 0xbc, 0x8a, 0x35, 0x48, 0xd1, 0x95, 0x9d, 0x36, 0xa8, 0xe4, 0x36, 0xcf,
+	0x3e, 0x54, 0x6a, 0xb5, 0xcc, 0xc5, 0xf0, 0x0d, 0x48, 0xb1, 0x68, 0x14, 0x27, 0xe9, 0xf2, 0x74,
+	0xb3, 0xcd, 0xa5, 0x99, 0x10, 0xd5, 0xbb, 0x37, 0xd2, 0x69, 0x1d, 0xe1, 0x6d, 0xac, 0x18, 0x49,
+	0xbf, 0x42, 0x90, 0x0f, 0xaf, 0x70, 0xfe, 0x01, 0xe1, 0xf7, 0x56, 0x3c, 0xe4, 0xad, 0x15, 0x00,
+	0x7a, 0x81, 0x52, 0xba, 0xa7, 0x36, 0x71, 0x7a, 0x9b, 0x34, 0xa5, 0xd0, 0x60, 0x63, 0x01, 0xdd,
+	0x3b, 0x22, 0xbd, 0x63, 0x6b, 0xc2, 0x3b, 0xe9, 0x39, 0xd9, 0x1d, 0x4b, 0x1f, 0xc1, 0x62, 0xd0,
+	0x77, 0xde, 0x19, 0x6b, 0x92, 0x1e, 0xd1, 0x9e, 0x90, 0xbe, 0x80, 0xa5, 0xb1, 0x98, 0x90, 0xe7,
+	0x1c, 0x2a, 0x83, 0x7e, 0xb7, 0x0a, 0x19, 0xdf, 0xe1, 0x4d, 0xef, 0xa7, 0xbb, 0x7b, 0x4a, 0xb3,
+	0xd1, 0x6c, 0xc9, 0x3f, 0xcf, 0xc7, 0x30, 0xc0, 0x4c, 0x7d, 0xab, 0xb3, 0xfb, 0x69, 0x23, 0x8f,
+	0xde, 0x7d, 0x00, 0x69, 0x37, 0x00, 0x71, 0x1a, 0x52, 0x8d, 0x4f, 0x0e, 0xea, 0x8f, 0xf2, 0x31,
+	0x3a, 0x65, 0xaf, 0xd5, 0x51, 0xf8, 0x10, 0xe1, 0x79, 0xc8, 0xc8, 0x8d, 0xfb, 0x8d, 0xc7, 0x4a,
+	0xb3, 0xde, 0xd9, 0xda, 0xc9, 0xc7, 0x31, 0x86, 0x1c, 0x27, 0xec, 0xb5, 0x04, 0x2d, 0x71, 0xeb,
+	0xcf, 0xb3, 0x30, 0xeb, 0x6c, 0x1f, 0x7e, 0x1f, 0x92, 0xfb, 0x13, 0xeb, 0x08, 0x2f, 0x7b, 0xd5,
+	0xe9, 0x33, 0x53, 0xb3, 0x89, 0x08, 0x83, 0x72, 0x71, 0x8a, 0xce, 0x4d, 0x94, 0x62, 0x78, 0x1b,
+	0x32, 0xbe, 0xee, 0x19, 0x47, 0xbe, 0x38, 0x95, 0xaf, 0x46, 0xdc, 0x1f, 0x3c, 0x8c, 0x0d, 0x84,
+	0x5b, 0x90, 0x63, 0x2c, 0xa7, 0x3b, 0xb6, 0xb0, 0xfb, 0x7c, 0x10, 0x75, 0x41, 0x2d, 0xaf, 0x9c,
+	0xc3, 0x75, 0xd5, 0xda, 0x09, 0x3e, 0xe8, 0x96, 0xa3, 0xde, 0x7e, 0xc3, 0xca, 0x45, 0x34, 0xa1,
+	0x52, 0x0c, 0x37, 0x00, 0xbc, 0x16, 0x0e, 0xbf, 0x15, 0x10, 0xf6, 0xb7, 0x9d, 0xe5, 0x72, 0x14,
+	0xcb, 0x85, 0xd9, 0x84, 0xb4, 0xdb, 0x88, 0xe0, 0x52, 0x44, 0x6f, 0xc2, 0x41, 0xce, 0xef, 0x5a,
+	0xa4, 0x18, 0xbe, 0x07, 0xd9, 0xfa, 0x70, 0x78, 0x19, 0x98, 0xb2, 0x9f, 0x63, 0x85, 0x71, 0x86,
+	0x50, 0x3c, 0xe7, 0xec, 0xc7, 0xef, 0xb8, 0x95, 0xee, 0xa5, 0x0d, 0x4d, 0xf9, 0x87, 0x17, 0xca,
+	0xb9, 0xab, 0x75, 0x60, 0x3e, 0x74, 0x54, 0xe3, 0x4a, 0x68, 0x76, 0xa8, 0x6b, 0x28, 0xaf, 0x9e,
+	0xcb, 0x77, 0x51, 0xbb, 0x50, 0xf0, 0xfc, 0xec, 0xbe, 0xfd, 0x63, 0x69, 0x7a, 0x13, 0xc2, 0x7f,
+	0x34, 0x94, 0x7f, 0xf0, 0x52, 0x19, 0x5f, 0x54, 0x1e, 0xc3, 0x72, 0xf4, 0x13, 0x39, 0xbe, 0x1e,
+	0x11, 0x33, 0xd3, 0xcf, 0xfd, 0xe5, 0x77, 0x2e, 0x12, 0xf3, 0x2d, 0xd6, 0x84, 0xac, 0xff, 0x39,
+	0x18, 0xbb, 0x61, 0x19, 0xf1, 0xda, 0x5c, 0xbe, 0x16, 0xcd, 0x0c, 0x64, 0x54, 0xd6, 0x5f, 0x94,
+	0x3c, 0xb8, 0x88, 0x32, 0x5f, 0xbe, 0x16, 0xcd, 0x74, 0xe0, 0xaa, 0x68, 0x03, 0x6d, 0x7e, 0xf8,
+	0xf4, 0x79, 0x25, 0xf6, 0xcd, 0xf3, 0x4a, 0xec, 0xdb, 0xe7, 0x15, 0xf4, 0xcb, 0xb3, 0x0a, 0xfa,
+	0xe3, 0x59, 0x05, 0x7d, 0x75, 0x56, 0x41, 0x4f, 0xcf, 0x2a, 0xe8, 0x5f, 0x67, 0x15, 0xf4, 0x9f,
+	0xb3, 0x4a, 0xec, 0xdb, 0xb3, 0x0a, 0xfa, 0xf5, 0x8b, 0x4a, 0xec, 0xe9, 0x8b, 0x4a, 0xec, 0x9b,
+	0x17, 0x95, 0xd8, 0x2f, 0x66, 0x7a, 0x43, 0x8d, 0x18, 0x76, 0x77, 0x86, 0xfd, 0x03, 0x74, 0xfb,
+	0xff, 0x03, 0x00, 0x21, 0x64, 0xd2, 0xeb, 0x7c, 0x1a, 0x00, 0x00,
 }
 
 func (x CountMethod) String() string {
@@ -2363,14 +2658,14 @@ func (this *LabelValueSeriesCount) Equal(that interface{}) bool {
 	}
 	return true
 }
-func (this *ReadRequest) Equal(that interface{}) bool {
+func (this *ActiveSeriesRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*ReadRequest)
+	that1, ok := that.(*ActiveSeriesRequest)
 	if !ok {
-		that2, ok := that.(ReadRequest)
+		that2, ok := that.(ActiveSeriesRequest)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2382,32 +2677,24 @@ func (this *ReadRequest) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if len(this.Queries) != len(that1.Queries) {
-		return false
-	}
-	for i := range this.Queries {
-		if !this.Queries[i].Equal(that1.Queries[i]) {
-			return false
-		}
-	}
-	if len(this.AcceptedResponseTypes) != len(that1.AcceptedResponseTypes) {
+	if len(this.Matchers) != len(that1.Matchers) {
 		return false
 	}
-	for i := range this.AcceptedResponseTypes {
-		if this.AcceptedResponseTypes[i] != that1.AcceptedResponseTypes[i] {
+	for i := range this.Matchers {
+		if !this.Matchers[i].Equal(that1.Matchers[i]) {
 			return false
 		}
 	}
 	return true
 }
-func (this *ReadResponse) Equal(that interface{}) bool {
+func (this *ActiveSeriesResponse) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*ReadResponse)
+	that1, ok := that.(*ActiveSeriesResponse)
 	if !ok {
-		that2, ok := that.(ReadResponse)
+		that2, ok := that.(ActiveSeriesResponse)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2419,12 +2706,78 @@ func (this *ReadResponse) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if len(this.Results) != len(that1.Results) {
+	if len(this.Metric) != len(that1.Metric) {
 		return false
 	}
-	for i := range this.Results {
-		if !this.Results[i].Equal(that1.Results[i]) {
-			return false
+	for i := range this.Metric {
+		if !this.Metric[i].Equal(that1.Metric[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *ReadRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ReadRequest)
+	if !ok {
+		that2, ok := that.(ReadRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Queries) != len(that1.Queries) {
+		return false
+	}
+	for i := range this.Queries {
+		if !this.Queries[i].Equal(that1.Queries[i]) {
+			return false
+		}
+	}
+	if len(this.AcceptedResponseTypes) != len(that1.AcceptedResponseTypes) {
+		return false
+	}
+	for i := range this.AcceptedResponseTypes {
+		if this.AcceptedResponseTypes[i] != that1.AcceptedResponseTypes[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *ReadResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ReadResponse)
+	if !ok {
+		that2, ok := that.(ReadResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Results) != len(that1.Results) {
+		return false
+	}
+	for i := range this.Results {
+		if !this.Results[i].Equal(that1.Results[i]) {
+			return false
 		}
 	}
 	return true
@@ -3305,6 +3658,109 @@ func (this *TimeSeriesFile) Equal(that interface{}) bool {
 	if !bytes.Equal(this.Data, that1.Data) {
 		return false
 	}
+	if this.Offset != that1.Offset {
+		return false
+	}
+	return true
+}
+func (this *TransferTSDBRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TransferTSDBRequest)
+	if !ok {
+		that2, ok := that.(TransferTSDBRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.FromIngesterId != that1.FromIngesterId {
+		return false
+	}
+	if len(this.Files) != len(that1.Files) {
+		return false
+	}
+	for i := range this.Files {
+		if !this.Files[i].Equal(that1.Files[i]) {
+			return false
+		}
+	}
+	if !this.Chunk.Equal(that1.Chunk) {
+		return false
+	}
+	if this.Commit != that1.Commit {
+		return false
+	}
+	return true
+}
+func (this *TransferTSDBFile) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TransferTSDBFile)
+	if !ok {
+		that2, ok := that.(TransferTSDBFile)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.UserId != that1.UserId {
+		return false
+	}
+	if this.Filename != that1.Filename {
+		return false
+	}
+	if this.SizeBytes != that1.SizeBytes {
+		return false
+	}
+	if this.Checksum != that1.Checksum {
+		return false
+	}
+	return true
+}
+func (this *TransferTSDBResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TransferTSDBResponse)
+	if !ok {
+		that2, ok := that.(TransferTSDBResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.ReceivedBytes) != len(that1.ReceivedBytes) {
+		return false
+	}
+	for i := range this.ReceivedBytes {
+		if this.ReceivedBytes[i] != that1.ReceivedBytes[i] {
+			return false
+		}
+	}
 	return true
 }
 func (this *LabelNamesAndValuesRequest) GoString() string {
@@ -3391,6 +3847,30 @@ func (this *LabelValueSeriesCount) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *ActiveSeriesRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&client.ActiveSeriesRequest{")
+	if this.Matchers != nil {
+		s = append(s, "Matchers: "+fmt.Sprintf("%#v", this.Matchers)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ActiveSeriesResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&client.ActiveSeriesResponse{")
+	if this.Metric != nil {
+		s = append(s, "Metric: "+fmt.Sprintf("%#v", this.Metric)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *ReadRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -3793,12 +4273,53 @@ func (this *TimeSeriesFile) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
+	s := make([]string, 0, 9)
 	s = append(s, "&client.TimeSeriesFile{")
 	s = append(s, "FromIngesterId: "+fmt.Sprintf("%#v", this.FromIngesterId)+",\n")
 	s = append(s, "UserId: "+fmt.Sprintf("%#v", this.UserId)+",\n")
 	s = append(s, "Filename: "+fmt.Sprintf("%#v", this.Filename)+",\n")
 	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "Offset: "+fmt.Sprintf("%#v", this.Offset)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TransferTSDBRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&client.TransferTSDBRequest{")
+	s = append(s, "FromIngesterId: "+fmt.Sprintf("%#v", this.FromIngesterId)+",\n")
+	if this.Files != nil {
+		s = append(s, "Files: "+fmt.Sprintf("%#v", this.Files)+",\n")
+	}
+	if this.Chunk != nil {
+		s = append(s, "Chunk: "+fmt.Sprintf("%#v", this.Chunk)+",\n")
+	}
+	s = append(s, "Commit: "+fmt.Sprintf("%#v", this.Commit)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TransferTSDBFile) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&client.TransferTSDBFile{")
+	s = append(s, "UserId: "+fmt.Sprintf("%#v", this.UserId)+",\n")
+	s = append(s, "Filename: "+fmt.Sprintf("%#v", this.Filename)+",\n")
+	s = append(s, "SizeBytes: "+fmt.Sprintf("%#v", this.SizeBytes)+",\n")
+	s = append(s, "Checksum: "+fmt.Sprintf("%#v", this.Checksum)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TransferTSDBResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&client.TransferTSDBResponse{")
+	s = append(s, "ReceivedBytes: "+fmt.Sprintf("%#v", this.ReceivedBytes)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -3839,6 +4360,14 @@ type IngesterClient interface {
 	// that match the matchers.
 	// The listing order of the labels is not guaranteed.
 	LabelValuesCardinality(ctx context.Context, in *LabelValuesCardinalityRequest, opts ...grpc.CallOption) (Ingester_LabelValuesCardinalityClient, error)
+	// ActiveSeries returns the label sets of the active series matching the matchers.
+	// The listing order of the series is not guaranteed.
+	ActiveSeries(ctx context.Context, in *ActiveSeriesRequest, opts ...grpc.CallOption) (Ingester_ActiveSeriesClient, error)
+	// TransferTSDB receives the TSDBs of a leaving ingester, which hands over its tokens in the ring
+	// once all the files have been received. The first request lists the files to transfer, and is
+	// answered with the number of bytes of each file already received, so that an interrupted
+	// transfer can be resumed.
+	TransferTSDB(ctx context.Context, opts ...grpc.CallOption) (Ingester_TransferTSDBClient, error)
 }
 
 type ingesterClient struct {
@@ -4017,6 +4546,69 @@ func (x *ingesterLabelValuesCardinalityClient) Recv() (*LabelValuesCardinalityRe
 	return m, nil
 }
 
+func (c *ingesterClient) ActiveSeries(ctx context.Context, in *ActiveSeriesRequest, opts ...grpc.CallOption) (Ingester_ActiveSeriesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Ingester_serviceDesc.Streams[3], "/cortex.Ingester/ActiveSeries", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ingesterActiveSeriesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Ingester_ActiveSeriesClient interface {
+	Recv() (*ActiveSeriesResponse, error)
+	grpc.ClientStream
+}
+
+type ingesterActiveSeriesClient struct {
+	grpc.ClientStream
+}
+
+func (x *ingesterActiveSeriesClient) Recv() (*ActiveSeriesResponse, error) {
+	m := new(ActiveSeriesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *ingesterClient) TransferTSDB(ctx context.Context, opts ...grpc.CallOption) (Ingester_TransferTSDBClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Ingester_serviceDesc.Streams[4], "/cortex.Ingester/TransferTSDB", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ingesterTransferTSDBClient{stream}
+	return x, nil
+}
+
+type Ingester_TransferTSDBClient interface {
+	Send(*TransferTSDBRequest) error
+	Recv() (*TransferTSDBResponse, error)
+	grpc.ClientStream
+}
+
+type ingesterTransferTSDBClient struct {
+	grpc.ClientStream
+}
+
+func (x *ingesterTransferTSDBClient) Send(m *TransferTSDBRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *ingesterTransferTSDBClient) Recv() (*TransferTSDBResponse, error) {
+	m := new(TransferTSDBResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // IngesterServer is the server API for Ingester service.
 type IngesterServer interface {
 	Push(context.Context, *mimirpb.WriteRequest) (*mimirpb.WriteResponse, error)
@@ -4035,6 +4627,14 @@ type IngesterServer interface {
 	// that match the matchers.
 	// The listing order of the labels is not guaranteed.
 	LabelValuesCardinality(*LabelValuesCardinalityRequest, Ingester_LabelValuesCardinalityServer) error
+	// ActiveSeries returns the label sets of the active series matching the matchers.
+	// The listing order of the series is not guaranteed.
+	ActiveSeries(*ActiveSeriesRequest, Ingester_ActiveSeriesServer) error
+	// TransferTSDB receives the TSDBs of a leaving ingester, which hands over its tokens in the ring
+	// once all the files have been received. The first request lists the files to transfer, and is
+	// answered with the number of bytes of each file already received, so that an interrupted
+	// transfer can be resumed.
+	TransferTSDB(Ingester_TransferTSDBServer) error
 }
 
 // UnimplementedIngesterServer can be embedded to have forward compatible implementations.
@@ -4074,6 +4674,12 @@ func (*UnimplementedIngesterServer) LabelNamesAndValues(req *LabelNamesAndValues
 func (*UnimplementedIngesterServer) LabelValuesCardinality(req *LabelValuesCardinalityRequest, srv Ingester_LabelValuesCardinalityServer) error {
 	return status.Errorf(codes.Unimplemented, "method LabelValuesCardinality not implemented")
 }
+func (*UnimplementedIngesterServer) ActiveSeries(req *ActiveSeriesRequest, srv Ingester_ActiveSeriesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ActiveSeries not implemented")
+}
+func (*UnimplementedIngesterServer) TransferTSDB(srv Ingester_TransferTSDBServer) error {
+	return status.Errorf(codes.Unimplemented, "method TransferTSDB not implemented")
+}
 
 func RegisterIngesterServer(s *grpc.Server, srv IngesterServer) {
 	s.RegisterService(&_Ingester_serviceDesc, srv)
@@ -4286,16 +4892,63 @@ func (x *ingesterLabelValuesCardinalityServer) Send(m *LabelValuesCardinalityRes
 	return x.ServerStream.SendMsg(m)
 }
 
-var _Ingester_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "cortex.Ingester",
-	HandlerType: (*IngesterServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Push",
-			Handler:    _Ingester_Push_Handler,
-		},
-		{
-			MethodName: "QueryExemplars",
+func _Ingester_ActiveSeries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ActiveSeriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IngesterServer).ActiveSeries(m, &ingesterActiveSeriesServer{stream})
+}
+
+type Ingester_ActiveSeriesServer interface {
+	Send(*ActiveSeriesResponse) error
+	grpc.ServerStream
+}
+
+type ingesterActiveSeriesServer struct {
+	grpc.ServerStream
+}
+
+func (x *ingesterActiveSeriesServer) Send(m *ActiveSeriesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Ingester_TransferTSDB_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IngesterServer).TransferTSDB(&ingesterTransferTSDBServer{stream})
+}
+
+type Ingester_TransferTSDBServer interface {
+	Send(*TransferTSDBResponse) error
+	Recv() (*TransferTSDBRequest, error)
+	grpc.ServerStream
+}
+
+type ingesterTransferTSDBServer struct {
+	grpc.ServerStream
+}
+
+func (x *ingesterTransferTSDBServer) Send(m *TransferTSDBResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *ingesterTransferTSDBServer) Recv() (*TransferTSDBRequest, error) {
+	m := new(TransferTSDBRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Ingester_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cortex.Ingester",
+	HandlerType: (*IngesterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Push",
+			Handler:    _Ingester_Push_Handler,
+		},
+		{
+			MethodName: "QueryExemplars",
 			Handler:    _Ingester_QueryExemplars_Handler,
 		},
 		{
@@ -4339,6 +4992,17 @@ var _Ingester_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Ingester_LabelValuesCardinality_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ActiveSeries",
+			Handler:       _Ingester_ActiveSeries_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TransferTSDB",
+			Handler:       _Ingester_TransferTSDB_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "ingester.proto",
 }
@@ -4591,6 +5255,80 @@ func (m *LabelValueSeriesCount) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ActiveSeriesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ActiveSeriesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ActiveSeriesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Matchers) > 0 {
+		for iNdEx := len(m.Matchers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Matchers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ActiveSeriesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ActiveSeriesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ActiveSeriesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Metric) > 0 {
+		for iNdEx := len(m.Metric) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Metric[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *ReadRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -5859,6 +6597,11 @@ func (m *TimeSeriesFile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Offset != 0 {
+		i = encodeVarintIngester(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x28
+	}
 	if len(m.Data) > 0 {
 		i -= len(m.Data)
 		copy(dAtA[i:], m.Data)
@@ -5890,6 +6633,161 @@ func (m *TimeSeriesFile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *TransferTSDBRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TransferTSDBRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransferTSDBRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Commit {
+		i--
+		if m.Commit {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Chunk != nil {
+		{
+			size, err := m.Chunk.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintIngester(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Files) > 0 {
+		for iNdEx := len(m.Files) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Files[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.FromIngesterId) > 0 {
+		i -= len(m.FromIngesterId)
+		copy(dAtA[i:], m.FromIngesterId)
+		i = encodeVarintIngester(dAtA, i, uint64(len(m.FromIngesterId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TransferTSDBFile) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TransferTSDBFile) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransferTSDBFile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Checksum != 0 {
+		i = encodeVarintIngester(dAtA, i, uint64(m.Checksum))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.SizeBytes != 0 {
+		i = encodeVarintIngester(dAtA, i, uint64(m.SizeBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Filename) > 0 {
+		i -= len(m.Filename)
+		copy(dAtA[i:], m.Filename)
+		i = encodeVarintIngester(dAtA, i, uint64(len(m.Filename)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.UserId) > 0 {
+		i -= len(m.UserId)
+		copy(dAtA[i:], m.UserId)
+		i = encodeVarintIngester(dAtA, i, uint64(len(m.UserId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TransferTSDBResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TransferTSDBResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransferTSDBResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ReceivedBytes) > 0 {
+		dAtA8 := make([]byte, len(m.ReceivedBytes)*10)
+		var j7 int
+		for _, num1 := range m.ReceivedBytes {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA8[j7] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j7++
+			}
+			dAtA8[j7] = uint8(num)
+			j7++
+		}
+		i -= j7
+		copy(dAtA[i:], dAtA8[:j7])
+		i = encodeVarintIngester(dAtA, i, uint64(j7))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintIngester(dAtA []byte, offset int, v uint64) int {
 	offset -= sovIngester(v)
 	base := offset
@@ -6010,36 +6908,66 @@ func (m *LabelValueSeriesCount) Size() (n int) {
 	return n
 }
 
-func (m *ReadRequest) Size() (n int) {
+func (m *ActiveSeriesRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if len(m.Queries) > 0 {
-		for _, e := range m.Queries {
+	if len(m.Matchers) > 0 {
+		for _, e := range m.Matchers {
 			l = e.Size()
 			n += 1 + l + sovIngester(uint64(l))
 		}
 	}
-	if len(m.AcceptedResponseTypes) > 0 {
-		l = 0
-		for _, e := range m.AcceptedResponseTypes {
-			l += sovIngester(uint64(e))
-		}
-		n += 1 + sovIngester(uint64(l)) + l
-	}
 	return n
 }
 
-func (m *ReadResponse) Size() (n int) {
+func (m *ActiveSeriesResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if len(m.Results) > 0 {
-		for _, e := range m.Results {
+	if len(m.Metric) > 0 {
+		for _, e := range m.Metric {
+			l = e.Size()
+			n += 1 + l + sovIngester(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ReadRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Queries) > 0 {
+		for _, e := range m.Queries {
+			l = e.Size()
+			n += 1 + l + sovIngester(uint64(l))
+		}
+	}
+	if len(m.AcceptedResponseTypes) > 0 {
+		l = 0
+		for _, e := range m.AcceptedResponseTypes {
+			l += sovIngester(uint64(e))
+		}
+		n += 1 + sovIngester(uint64(l)) + l
+	}
+	return n
+}
+
+func (m *ReadResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Results) > 0 {
+		for _, e := range m.Results {
 			l = e.Size()
 			n += 1 + l + sovIngester(uint64(l))
 		}
@@ -6566,6 +7494,74 @@ func (m *TimeSeriesFile) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovIngester(uint64(l))
 	}
+	if m.Offset != 0 {
+		n += 1 + sovIngester(uint64(m.Offset))
+	}
+	return n
+}
+
+func (m *TransferTSDBRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.FromIngesterId)
+	if l > 0 {
+		n += 1 + l + sovIngester(uint64(l))
+	}
+	if len(m.Files) > 0 {
+		for _, e := range m.Files {
+			l = e.Size()
+			n += 1 + l + sovIngester(uint64(l))
+		}
+	}
+	if m.Chunk != nil {
+		l = m.Chunk.Size()
+		n += 1 + l + sovIngester(uint64(l))
+	}
+	if m.Commit {
+		n += 2
+	}
+	return n
+}
+
+func (m *TransferTSDBFile) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.UserId)
+	if l > 0 {
+		n += 1 + l + sovIngester(uint64(l))
+	}
+	l = len(m.Filename)
+	if l > 0 {
+		n += 1 + l + sovIngester(uint64(l))
+	}
+	if m.SizeBytes != 0 {
+		n += 1 + sovIngester(uint64(m.SizeBytes))
+	}
+	if m.Checksum != 0 {
+		n += 1 + sovIngester(uint64(m.Checksum))
+	}
+	return n
+}
+
+func (m *TransferTSDBResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.ReceivedBytes) > 0 {
+		l = 0
+		for _, e := range m.ReceivedBytes {
+			l += sovIngester(uint64(e))
+		}
+		n += 1 + sovIngester(uint64(l)) + l
+	}
 	return n
 }
 
@@ -6669,6 +7665,36 @@ func (this *LabelValueSeriesCount) String() string {
 	}, "")
 	return s
 }
+func (this *ActiveSeriesRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForMatchers := "[]*LabelMatcher{"
+	for _, f := range this.Matchers {
+		repeatedStringForMatchers += strings.Replace(f.String(), "LabelMatcher", "LabelMatcher", 1) + ","
+	}
+	repeatedStringForMatchers += "}"
+	s := strings.Join([]string{`&ActiveSeriesRequest{`,
+		`Matchers:` + repeatedStringForMatchers + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ActiveSeriesResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForMetric := "[]*Metric{"
+	for _, f := range this.Metric {
+		repeatedStringForMetric += strings.Replace(fmt.Sprintf("%v", f), "Metric", "mimirpb.Metric", 1) + ","
+	}
+	repeatedStringForMetric += "}"
+	s := strings.Join([]string{`&ActiveSeriesResponse{`,
+		`Metric:` + repeatedStringForMetric + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *ReadRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -7091,6 +8117,48 @@ func (this *TimeSeriesFile) String() string {
 		`UserId:` + fmt.Sprintf("%v", this.UserId) + `,`,
 		`Filename:` + fmt.Sprintf("%v", this.Filename) + `,`,
 		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`Offset:` + fmt.Sprintf("%v", this.Offset) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TransferTSDBRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForFiles := "[]*TransferTSDBFile{"
+	for _, f := range this.Files {
+		repeatedStringForFiles += strings.Replace(f.String(), "TransferTSDBFile", "TransferTSDBFile", 1) + ","
+	}
+	repeatedStringForFiles += "}"
+	s := strings.Join([]string{`&TransferTSDBRequest{`,
+		`FromIngesterId:` + fmt.Sprintf("%v", this.FromIngesterId) + `,`,
+		`Files:` + repeatedStringForFiles + `,`,
+		`Chunk:` + strings.Replace(this.Chunk.String(), "TimeSeriesFile", "TimeSeriesFile", 1) + `,`,
+		`Commit:` + fmt.Sprintf("%v", this.Commit) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TransferTSDBFile) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TransferTSDBFile{`,
+		`UserId:` + fmt.Sprintf("%v", this.UserId) + `,`,
+		`Filename:` + fmt.Sprintf("%v", this.Filename) + `,`,
+		`SizeBytes:` + fmt.Sprintf("%v", this.SizeBytes) + `,`,
+		`Checksum:` + fmt.Sprintf("%v", this.Checksum) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TransferTSDBResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TransferTSDBResponse{`,
+		`ReceivedBytes:` + fmt.Sprintf("%v", this.ReceivedBytes) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -7817,7 +8885,7 @@ func (m *LabelValueSeriesCount) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ReadRequest) Unmarshal(dAtA []byte) error {
+func (m *ActiveSeriesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7840,15 +8908,15 @@ func (m *ReadRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ReadRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ActiveSeriesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ReadRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ActiveSeriesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Queries", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -7875,80 +8943,11 @@ func (m *ReadRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Queries = append(m.Queries, &QueryRequest{})
-			if err := m.Queries[len(m.Queries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Matchers = append(m.Matchers, &LabelMatcher{})
+			if err := m.Matchers[len(m.Matchers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType == 0 {
-				var v ReadRequest_ResponseType
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowIngester
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					v |= ReadRequest_ResponseType(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				m.AcceptedResponseTypes = append(m.AcceptedResponseTypes, v)
-			} else if wireType == 2 {
-				var packedLen int
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowIngester
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					packedLen |= int(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				if packedLen < 0 {
-					return ErrInvalidLengthIngester
-				}
-				postIndex := iNdEx + packedLen
-				if postIndex < 0 {
-					return ErrInvalidLengthIngester
-				}
-				if postIndex > l {
-					return io.ErrUnexpectedEOF
-				}
-				var elementCount int
-				if elementCount != 0 && len(m.AcceptedResponseTypes) == 0 {
-					m.AcceptedResponseTypes = make([]ReadRequest_ResponseType, 0, elementCount)
-				}
-				for iNdEx < postIndex {
-					var v ReadRequest_ResponseType
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowIngester
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						v |= ReadRequest_ResponseType(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					m.AcceptedResponseTypes = append(m.AcceptedResponseTypes, v)
-				}
-			} else {
-				return fmt.Errorf("proto: wrong wireType = %d for field AcceptedResponseTypes", wireType)
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipIngester(dAtA[iNdEx:])
@@ -7973,7 +8972,7 @@ func (m *ReadRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ReadResponse) Unmarshal(dAtA []byte) error {
+func (m *ActiveSeriesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7996,15 +8995,15 @@ func (m *ReadResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ReadResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ActiveSeriesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ReadResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ActiveSeriesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Results", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Metric", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -8031,8 +9030,8 @@ func (m *ReadResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Results = append(m.Results, &QueryResponse{})
-			if err := m.Results[len(m.Results)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Metric = append(m.Metric, &mimirpb.Metric{})
+			if err := m.Metric[len(m.Metric)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -8060,7 +9059,7 @@ func (m *ReadResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StreamReadResponse) Unmarshal(dAtA []byte) error {
+func (m *ReadRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8083,15 +9082,15 @@ func (m *StreamReadResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StreamReadResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ReadRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StreamReadResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ReadRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ChunkedSeries", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Queries", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -8118,29 +9117,79 @@ func (m *StreamReadResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ChunkedSeries = append(m.ChunkedSeries, &StreamChunkedSeries{})
-			if err := m.ChunkedSeries[len(m.ChunkedSeries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Queries = append(m.Queries, &QueryRequest{})
+			if err := m.Queries[len(m.Queries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QueryIndex", wireType)
-			}
-			m.QueryIndex = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowIngester
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.QueryIndex |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if wireType == 0 {
+				var v ReadRequest_ResponseType
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowIngester
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= ReadRequest_ResponseType(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.AcceptedResponseTypes = append(m.AcceptedResponseTypes, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowIngester
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthIngester
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthIngester
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				if elementCount != 0 && len(m.AcceptedResponseTypes) == 0 {
+					m.AcceptedResponseTypes = make([]ReadRequest_ResponseType, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v ReadRequest_ResponseType
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowIngester
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= ReadRequest_ResponseType(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.AcceptedResponseTypes = append(m.AcceptedResponseTypes, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field AcceptedResponseTypes", wireType)
 			}
 		default:
 			iNdEx = preIndex
@@ -8166,7 +9215,7 @@ func (m *StreamReadResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StreamChunkedSeries) Unmarshal(dAtA []byte) error {
+func (m *ReadResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8189,15 +9238,15 @@ func (m *StreamChunkedSeries) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StreamChunkedSeries: wiretype end group for non-group")
+			return fmt.Errorf("proto: ReadResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StreamChunkedSeries: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ReadResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Results", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -8224,14 +9273,67 @@ func (m *StreamChunkedSeries) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Labels = append(m.Labels, github_com_grafana_mimir_pkg_mimirpb.LabelAdapter{})
-			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Results = append(m.Results, &QueryResponse{})
+			if err := m.Results[len(m.Results)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StreamReadResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamReadResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamReadResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ChunkedSeries", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -8258,11 +9360,30 @@ func (m *StreamChunkedSeries) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Chunks = append(m.Chunks, StreamChunk{})
-			if err := m.Chunks[len(m.Chunks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.ChunkedSeries = append(m.ChunkedSeries, &StreamChunkedSeries{})
+			if err := m.ChunkedSeries[len(m.ChunkedSeries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryIndex", wireType)
+			}
+			m.QueryIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.QueryIndex |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipIngester(dAtA[iNdEx:])
@@ -8287,7 +9408,7 @@ func (m *StreamChunkedSeries) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StreamChunk) Unmarshal(dAtA []byte) error {
+func (m *StreamChunkedSeries) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8310,17 +9431,17 @@ func (m *StreamChunk) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StreamChunk: wiretype end group for non-group")
+			return fmt.Errorf("proto: StreamChunkedSeries: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StreamChunk: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StreamChunkedSeries: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MinTimeMs", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
 			}
-			m.MinTimeMs = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowIngester
@@ -8330,54 +9451,31 @@ func (m *StreamChunk) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.MinTimeMs |= int64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MaxTimeMs", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthIngester
 			}
-			m.MaxTimeMs = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowIngester
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.MaxTimeMs |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
 			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-			m.Type = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowIngester
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Type |= StreamChunk_Encoding(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			m.Labels = append(m.Labels, github_com_grafana_mimir_pkg_mimirpb.LabelAdapter{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		case 4:
+			iNdEx = postIndex
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowIngester
@@ -8387,22 +9485,23 @@ func (m *StreamChunk) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthIngester
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthIngester
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Data.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Chunks = append(m.Chunks, StreamChunk{})
+			if err := m.Chunks[len(m.Chunks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -8430,7 +9529,7 @@ func (m *StreamChunk) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryRequest) Unmarshal(dAtA []byte) error {
+func (m *StreamChunk) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8453,17 +9552,17 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: StreamChunk: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StreamChunk: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartTimestampMs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MinTimeMs", wireType)
 			}
-			m.StartTimestampMs = 0
+			m.MinTimeMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowIngester
@@ -8473,16 +9572,16 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StartTimestampMs |= int64(b&0x7F) << shift
+				m.MinTimeMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EndTimestampMs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxTimeMs", wireType)
 			}
-			m.EndTimestampMs = 0
+			m.MaxTimeMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowIngester
@@ -8492,16 +9591,159 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.EndTimestampMs |= int64(b&0x7F) << shift
+				m.MaxTimeMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			var msglen int
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= StreamChunk_Encoding(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Data.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimestampMs", wireType)
+			}
+			m.StartTimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndTimestampMs", wireType)
+			}
+			m.EndTimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EndTimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
+			}
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowIngester
@@ -11271,6 +12513,484 @@ func (m *TimeSeriesFile) Unmarshal(dAtA []byte) error {
 				m.Data = []byte{}
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TransferTSDBRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TransferTSDBRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TransferTSDBRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromIngesterId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FromIngesterId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Files", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Files = append(m.Files, &TransferTSDBFile{})
+			if err := m.Files[len(m.Files)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunk", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Chunk == nil {
+				m.Chunk = &TimeSeriesFile{}
+			}
+			if err := m.Chunk.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Commit = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TransferTSDBFile) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TransferTSDBFile: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TransferTSDBFile: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UserId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Filename", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Filename = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeBytes", wireType)
+			}
+			m.SizeBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SizeBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			m.Checksum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Checksum |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TransferTSDBResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TransferTSDBResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TransferTSDBResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType == 0 {
+				var v int64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowIngester
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.ReceivedBytes = append(m.ReceivedBytes, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowIngester
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthIngester
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthIngester
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.ReceivedBytes) == 0 {
+					m.ReceivedBytes = make([]int64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowIngester
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.ReceivedBytes = append(m.ReceivedBytes, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReceivedBytes", wireType)
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipIngester(dAtA[iNdEx:])
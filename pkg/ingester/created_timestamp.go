@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"math"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+// seriesCreatedTimestamps keeps the latest created timestamp of the in-memory series of a tenant for
+// which a zero sample has been considered, so that the zero sample is injected only once per created timestamp,
+// and the timestamp of the latest sample appended to these series, so that the zero sample is never injected
+// before an existing sample.
+type seriesCreatedTimestamps struct {
+	mtx   sync.Mutex
+	byRef map[chunks.HeadSeriesRef]createdTimestampEntry
+}
+
+type createdTimestampEntry struct {
+	created int64 // The latest created timestamp of the series.
+	latest  int64 // The timestamp of the latest sample appended to the series since its created timestamp is tracked.
+}
+
+func newSeriesCreatedTimestamps() *seriesCreatedTimestamps {
+	return &seriesCreatedTimestamps{
+		byRef: map[chunks.HeadSeriesRef]createdTimestampEntry{},
+	}
+}
+
+// update records the created timestamp ct of the series, and returns whether the series has a previously
+// recorded created timestamp older than ct, which means the series has been reset, and ct is newer than
+// the latest sample of the series.
+func (c *seriesCreatedTimestamps) update(ref storage.SeriesRef, ct int64) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	prev, ok := c.byRef[chunks.HeadSeriesRef(ref)]
+	if ok && prev.created >= ct {
+		return false
+	}
+	c.byRef[chunks.HeadSeriesRef(ref)] = createdTimestampEntry{created: ct, latest: prev.latest}
+	return ok && ct > prev.latest
+}
+
+// observe records the timestamp t of a sample appended to the series, if its created timestamp is tracked.
+func (c *seriesCreatedTimestamps) observe(ref storage.SeriesRef, t int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if entry, ok := c.byRef[chunks.HeadSeriesRef(ref)]; ok && t > entry.latest {
+		entry.latest = t
+		c.byRef[chunks.HeadSeriesRef(ref)] = entry
+	}
+}
+
+// delete removes the created timestamps of the input series. It's called when series are removed from the TSDB head.
+func (c *seriesCreatedTimestamps) delete(series map[chunks.HeadSeriesRef]labels.Labels) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for ref := range series {
+		delete(c.byRef, ref)
+	}
+}
+
+// appendCreatedTimestampZero appends a zero sample, or a zero native histogram, at the created timestamp of the
+// input series, so that the increase from zero to the first sample of a counter, histogram or summary which has
+// just been created or reset isn't lost by rate() and increase().
+//
+// The zero sample is appended only if the created timestamp is older than the first sample of the series in the
+// request and, for a series already in memory, if the created timestamp changed since the last time it has been
+// seen, because it's sent along with every sample, and is newer than the latest sample of the series, because a
+// zero sample between existing samples would be accepted if out-of-order ingestion is enabled. Failing to append
+// the zero sample is not an error, because the samples of the series are appended, and their errors handled,
+// right after.
+//
+// It returns the reference and the labels of the series, which are set if the series has been created.
+func appendCreatedTimestampZero(app extendedAppender, createdTimestamps *seriesCreatedTimestamps, ref storage.SeriesRef, copiedLabels, nonCopiedLabels labels.Labels,
+	ts *mimirpb.TimeSeries, nativeHistogramsIngestionEnabled, minAppendTimeAvailable bool, minAppendTime int64) (storage.SeriesRef, labels.Labels) {
+
+	ct := ts.CreatedTimestamp
+	first := int64(math.MaxInt64)
+	if len(ts.Samples) > 0 {
+		first = ts.Samples[0].TimestampMs
+	}
+
+	var firstHistogram *mimirpb.Histogram
+	if nativeHistogramsIngestionEnabled && len(ts.Histograms) > 0 && ts.Histograms[0].Timestamp < first {
+		firstHistogram = &ts.Histograms[0]
+		first = firstHistogram.Timestamp
+	}
+
+	if ct <= 0 || ct >= first || (minAppendTimeAvailable && ct < minAppendTime) {
+		return ref, copiedLabels
+	}
+	if firstHistogram != nil && firstHistogram.IsGauge() {
+		return ref, copiedLabels
+	}
+	if ref != 0 && !createdTimestamps.update(ref, ct) {
+		return ref, copiedLabels
+	}
+
+	if ref == 0 {
+		// Copy the label set because both TSDB and the active series tracker may retain it.
+		copiedLabels = mimirpb.CopyLabels(nonCopiedLabels)
+	}
+
+	var (
+		newRef storage.SeriesRef
+		err    error
+	)
+	if firstHistogram != nil {
+		ih, fh := zeroHistogramOf(firstHistogram)
+		newRef, err = app.AppendHistogram(ref, copiedLabels, ct, ih, fh)
+	} else {
+		newRef, err = app.Append(ref, copiedLabels, ct, 0)
+	}
+	if err != nil {
+		return ref, copiedLabels
+	}
+
+	if ref == 0 {
+		createdTimestamps.update(newRef, ct)
+	}
+	return newRef, copiedLabels
+}
+
+// latestSampleTimestamp returns the timestamp of the latest sample, or native histogram, of the input series which
+// isn't after maxTimestampMs, because these samples are rejected. The samples rejected for other reasons, for example
+// out-of-order or duplicated samples, are usually not newer than the latest sample of the series, and otherwise they
+// only prevent a zero sample from being injected.
+func latestSampleTimestamp(ts *mimirpb.TimeSeries, nativeHistogramsIngestionEnabled bool, maxTimestampMs int64) int64 {
+	latest := int64(math.MinInt64)
+	for _, s := range ts.Samples {
+		if s.TimestampMs > latest && s.TimestampMs <= maxTimestampMs {
+			latest = s.TimestampMs
+		}
+	}
+	if nativeHistogramsIngestionEnabled {
+		for _, h := range ts.Histograms {
+			if h.Timestamp > latest && h.Timestamp <= maxTimestampMs {
+				latest = h.Timestamp
+			}
+		}
+	}
+	return latest
+}
+
+// zeroHistogramOf returns an empty native histogram with the same schema, and type, of the input one.
+func zeroHistogramOf(h *mimirpb.Histogram) (*histogram.Histogram, *histogram.FloatHistogram) {
+	if h.IsFloatHistogram() {
+		return nil, &histogram.FloatHistogram{
+			Schema:           h.Schema,
+			ZeroThreshold:    h.ZeroThreshold,
+			CounterResetHint: histogram.CounterReset,
+		}
+	}
+	return &histogram.Histogram{
+		Schema:           h.Schema,
+		ZeroThreshold:    h.ZeroThreshold,
+		CounterResetHint: histogram.CounterReset,
+	}, nil
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+func TestSeriesCreatedTimestamps(t *testing.T) {
+	c := newSeriesCreatedTimestamps()
+
+	// The first created timestamp of a series is only recorded.
+	assert.False(t, c.update(storage.SeriesRef(1), 1000))
+	assert.False(t, c.update(storage.SeriesRef(1), 1000))
+	assert.False(t, c.update(storage.SeriesRef(1), 500))
+
+	// A newer created timestamp means the series has been reset.
+	assert.True(t, c.update(storage.SeriesRef(1), 2000))
+	assert.False(t, c.update(storage.SeriesRef(1), 2000))
+
+	// Created timestamps are tracked per series.
+	assert.False(t, c.update(storage.SeriesRef(2), 3000))
+
+	c.delete(map[chunks.HeadSeriesRef]labels.Labels{1: labels.EmptyLabels()})
+	assert.False(t, c.update(storage.SeriesRef(1), 4000))
+	assert.True(t, c.update(storage.SeriesRef(2), 4000))
+
+	// A newer created timestamp which isn't newer than the latest sample of the series is only recorded.
+	c.observe(storage.SeriesRef(2), 6000)
+	assert.False(t, c.update(storage.SeriesRef(2), 5000))
+	assert.False(t, c.update(storage.SeriesRef(2), 5000))
+	assert.True(t, c.update(storage.SeriesRef(2), 7000))
+
+	// The samples of the series whose created timestamp isn't tracked are ignored.
+	c.observe(storage.SeriesRef(3), 6000)
+	assert.False(t, c.update(storage.SeriesRef(3), 5000))
+}
+
+func TestIngester_Push_CreatedTimestampZeroIngestion(t *testing.T) {
+	metricLabels := labels.FromStrings(model.MetricNameLabel, "requests_total", "job", "test")
+
+	// The labels are copied because the ingester returns the request time series to the pool once pushed.
+	pushRequest := func(ct int64, samples ...mimirpb.Sample) *mimirpb.WriteRequest {
+		return &mimirpb.WriteRequest{
+			Timeseries: []mimirpb.PreallocTimeseries{{TimeSeries: &mimirpb.TimeSeries{
+				Labels:           mimirpb.FromLabelsToLabelAdapters(metricLabels.Copy()),
+				Samples:          samples,
+				CreatedTimestamp: ct,
+			}}},
+			Source: mimirpb.API,
+		}
+	}
+
+	tests := map[string]struct {
+		enabled              bool
+		outOfOrderTimeWindow time.Duration
+		requests             []*mimirpb.WriteRequest
+		expectedSamples      []model.SamplePair
+	}{
+		"disabled": {
+			enabled: false,
+			requests: []*mimirpb.WriteRequest{
+				pushRequest(1000, mimirpb.Sample{TimestampMs: 2000, Value: 5}),
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 2000, Value: 5}},
+		},
+		"zero sample injected for a new series": {
+			enabled: true,
+			requests: []*mimirpb.WriteRequest{
+				pushRequest(1000, mimirpb.Sample{TimestampMs: 2000, Value: 5}),
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 1000, Value: 0}, {Timestamp: 2000, Value: 5}},
+		},
+		"zero sample not injected if the created timestamp is not older than the first sample": {
+			enabled: true,
+			requests: []*mimirpb.WriteRequest{
+				pushRequest(2000, mimirpb.Sample{TimestampMs: 2000, Value: 5}),
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 2000, Value: 5}},
+		},
+		"zero sample injected only once per created timestamp": {
+			enabled: true,
+			requests: []*mimirpb.WriteRequest{
+				pushRequest(1000, mimirpb.Sample{TimestampMs: 2000, Value: 5}),
+				pushRequest(1000, mimirpb.Sample{TimestampMs: 3000, Value: 7}),
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 1000, Value: 0}, {Timestamp: 2000, Value: 5}, {Timestamp: 3000, Value: 7}},
+		},
+		"zero sample injected after a counter reset": {
+			enabled: true,
+			requests: []*mimirpb.WriteRequest{
+				pushRequest(1000, mimirpb.Sample{TimestampMs: 2000, Value: 5}),
+				pushRequest(3000, mimirpb.Sample{TimestampMs: 4000, Value: 1}),
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 1000, Value: 0}, {Timestamp: 2000, Value: 5}, {Timestamp: 3000, Value: 0}, {Timestamp: 4000, Value: 1}},
+		},
+		"zero sample injected after a counter reset with out-of-order ingestion enabled": {
+			enabled:              true,
+			outOfOrderTimeWindow: time.Hour,
+			requests: []*mimirpb.WriteRequest{
+				pushRequest(1000, mimirpb.Sample{TimestampMs: 2000, Value: 5}),
+				pushRequest(3000, mimirpb.Sample{TimestampMs: 4000, Value: 1}),
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 1000, Value: 0}, {Timestamp: 2000, Value: 5}, {Timestamp: 3000, Value: 0}, {Timestamp: 4000, Value: 1}},
+		},
+		"zero sample not injected before the latest sample with out-of-order ingestion enabled": {
+			enabled:              true,
+			outOfOrderTimeWindow: time.Hour,
+			requests: []*mimirpb.WriteRequest{
+				pushRequest(1000, mimirpb.Sample{TimestampMs: 2000, Value: 5}, mimirpb.Sample{TimestampMs: 6000, Value: 8}),
+				pushRequest(3000, mimirpb.Sample{TimestampMs: 4000, Value: 1}),
+				pushRequest(3000, mimirpb.Sample{TimestampMs: 5000, Value: 2}),
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 1000, Value: 0}, {Timestamp: 2000, Value: 5}, {Timestamp: 4000, Value: 1}, {Timestamp: 5000, Value: 2}, {Timestamp: 6000, Value: 8}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			limits := defaultLimitsTestConfig()
+			limits.CreatedTimestampZeroIngestionEnabled = tc.enabled
+			limits.OutOfOrderTimeWindow = model.Duration(tc.outOfOrderTimeWindow)
+
+			i, err := prepareIngesterWithBlocksStorageAndLimits(t, defaultIngesterTestConfig(t), limits, "", nil)
+			require.NoError(t, err)
+			require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+			t.Cleanup(func() {
+				_ = services.StopAndAwaitTerminated(context.Background(), i)
+			})
+
+			ctx := user.InjectOrgID(context.Background(), userID)
+			for _, req := range tc.requests {
+				_, err := i.Push(ctx, req)
+				require.NoError(t, err)
+			}
+
+			res, _, err := runTestQuery(ctx, t, i, labels.MatchEqual, model.MetricNameLabel, "requests_total")
+			require.NoError(t, err)
+			require.Len(t, res, 1)
+			assert.Equal(t, tc.expectedSamples, res[0].Values)
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 
 const (
 	integerUnavailableMsgFormat = "ingester is unavailable (current state: %s)"
+	ingesterReadOnlyMsg         = "ingester is in read-only mode and doesn't accept writes"
 )
 
 // errorWithStatus is used for wrapping errors returned by ingester.
@@ -146,6 +147,10 @@ func newSampleTimestampTooFarInFutureError(timestamp model.Time, labels []mimirp
 	return newSampleError(globalerror.SampleTooFarInFuture, "received a sample whose timestamp is too far in the future", timestamp, labels)
 }
 
+func newNativeHistogramCustomBucketsError(timestamp model.Time, labels []mimirpb.LabelAdapter) sampleError {
+	return newSampleError(globalerror.NativeHistogramCustomBuckets, "the native histogram has been rejected because native histograms with custom buckets are not supported yet", timestamp, labels)
+}
+
 func newSampleOutOfOrderError(timestamp model.Time, labels []mimirpb.LabelAdapter) sampleError {
 	return newSampleError(globalerror.SampleOutOfOrder, "the sample has been rejected because another sample with a more recent timestamp has already been ingested and out-of-order samples are not allowed", timestamp, labels)
 }
@@ -260,6 +265,33 @@ func (e perUserSeriesLimitReachedError) errorType() ingesterErrorType {
 // perUserSeriesLimitReachedError implements the softError interface.
 func (e perUserSeriesLimitReachedError) soft() {}
 
+// perUserMemoryLimitReachedError is an ingesterError indicating that a per-user estimated memory limit has been reached.
+type perUserMemoryLimitReachedError struct {
+	limit int64
+}
+
+// newPerUserMemoryLimitReachedError creates a new perUserMemoryLimitReachedError indicating that a per-user estimated memory limit has been reached.
+func newPerUserMemoryLimitReachedError(limit int64) perUserMemoryLimitReachedError {
+	return perUserMemoryLimitReachedError{
+		limit: limit,
+	}
+}
+
+func (e perUserMemoryLimitReachedError) Error() string {
+	return globalerror.MaxEstimatedMemoryPerUser.MessageWithPerTenantLimitConfig(
+		fmt.Sprintf("per-user estimated memory limit of %d bytes exceeded", e.limit),
+		validation.MaxEstimatedMemoryPerUserFlag,
+	)
+}
+
+// perUserMemoryLimitReachedError implements the ingesterError interface.
+func (e perUserMemoryLimitReachedError) errorType() ingesterErrorType {
+	return badData
+}
+
+// perUserMemoryLimitReachedError implements the softError interface.
+func (e perUserMemoryLimitReachedError) soft() {}
+
 // perUserMetadataLimitReachedError is an ingesterError indicating that a per-user metadata limit has been reached.
 type perUserMetadataLimitReachedError struct {
 	limit int
@@ -369,6 +401,24 @@ func (e unavailableError) errorType() ingesterErrorType {
 	return unavailable
 }
 
+// readOnlyError is an ingesterError indicating that the ingester is in read-only mode, and doesn't accept writes.
+type readOnlyError struct{}
+
+var errReadOnly = newReadOnlyError()
+
+func newReadOnlyError() readOnlyError {
+	return readOnlyError{}
+}
+
+func (e readOnlyError) Error() string {
+	return ingesterReadOnlyMsg
+}
+
+// readOnlyError implements the ingesterError interface.
+func (e readOnlyError) errorType() ingesterErrorType {
+	return unavailable
+}
+
 // instanceLimitReachedError is an ingesterError indicating that an instance limit has been reached.
 type instanceLimitReachedError struct {
 	message string
@@ -415,6 +465,8 @@ type ingesterErrSamplers struct {
 	maxMetadataPerMetricLimitExceeded *log.Sampler
 	maxSeriesPerUserLimitExceeded     *log.Sampler
 	maxMetadataPerUserLimitExceeded   *log.Sampler
+	maxMemoryPerUserLimitExceeded     *log.Sampler
+	nativeHistogramCustomBuckets      *log.Sampler
 }
 
 func newIngesterErrSamplers(freq int64) ingesterErrSamplers {
@@ -428,5 +480,7 @@ func newIngesterErrSamplers(freq int64) ingesterErrSamplers {
 		log.NewSampler(freq),
 		log.NewSampler(freq),
 		log.NewSampler(freq),
+		log.NewSampler(freq),
+		log.NewSampler(freq),
 	}
 }
@@ -40,6 +40,18 @@ func TestUnavailableError(t *testing.T) {
 	checkIngesterError(t, wrappedErr, unavailable, false)
 }
 
+func TestReadOnlyError(t *testing.T) {
+	err := newReadOnlyError()
+	require.Error(t, err)
+	require.EqualError(t, err, ingesterReadOnlyMsg)
+	checkIngesterError(t, err, unavailable, false)
+
+	wrappedErr := wrapOrAnnotateWithUser(err, userID)
+	require.ErrorIs(t, wrappedErr, err)
+	require.ErrorAs(t, wrappedErr, &readOnlyError{})
+	checkIngesterError(t, wrappedErr, unavailable, false)
+}
+
 func TestInstanceLimitReachedError(t *testing.T) {
 	limitErrorMessage := "this is a limit error message"
 	err := newInstanceLimitReachedError(limitErrorMessage)
@@ -86,6 +98,22 @@ func TestNewPerUserSeriesLimitError(t *testing.T) {
 	checkIngesterError(t, wrappedErr, badData, true)
 }
 
+func TestNewPerUserMemoryLimitError(t *testing.T) {
+	limit := int64(1024)
+	err := newPerUserMemoryLimitReachedError(limit)
+	expectedErrMsg := globalerror.MaxEstimatedMemoryPerUser.MessageWithPerTenantLimitConfig(
+		fmt.Sprintf("per-user estimated memory limit of %d bytes exceeded", limit),
+		validation.MaxEstimatedMemoryPerUserFlag,
+	)
+	require.Equal(t, expectedErrMsg, err.Error())
+	checkIngesterError(t, err, badData, true)
+
+	wrappedErr := wrapOrAnnotateWithUser(err, userID)
+	require.ErrorIs(t, wrappedErr, err)
+	require.ErrorAs(t, wrappedErr, &perUserMemoryLimitReachedError{})
+	checkIngesterError(t, wrappedErr, badData, true)
+}
+
 func TestNewPerUserMetadataLimitError(t *testing.T) {
 	limit := 100
 	err := newPerUserMetadataLimitReachedError(limit)
@@ -164,6 +192,10 @@ func TestNewSampleError(t *testing.T) {
 			err:         newSampleTimestampTooFarInFutureError(timestamp, seriesLabels),
 			expectedMsg: `received a sample whose timestamp is too far in the future (err-mimir-too-far-in-future). The affected sample has timestamp 1970-01-19T05:30:43.969Z and is from series {__name__="test"}`,
 		},
+		"newNativeHistogramCustomBucketsError": {
+			err:         newNativeHistogramCustomBucketsError(timestamp, seriesLabels),
+			expectedMsg: `the native histogram has been rejected because native histograms with custom buckets are not supported yet (err-mimir-native-histogram-custom-buckets-unsupported). The affected sample has timestamp 1970-01-19T05:30:43.969Z and is from series {__name__="test"}`,
+		},
 		"newSampleOutOfOrderError": {
 			err:         newSampleOutOfOrderError(timestamp, seriesLabels),
 			expectedMsg: `the sample has been rejected because another sample with a more recent timestamp has already been ingested and out-of-order samples are not allowed (err-mimir-sample-out-of-order). The affected sample has timestamp 1970-01-19T05:30:43.969Z and is from series {__name__="test"}`,
@@ -26,9 +26,11 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/gogo/status"
 	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/dskit/kv"
 	"github.com/grafana/dskit/ring"
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -51,13 +53,16 @@ import (
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/codes"
 
+	"github.com/grafana/mimir/pkg/costattribution"
 	"github.com/grafana/mimir/pkg/ingester/activeseries"
 	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/ingester/readonly"
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/storage/bucket"
 	"github.com/grafana/mimir/pkg/storage/sharding"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/streamaggr"
 	"github.com/grafana/mimir/pkg/usagestats"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/globalerror"
@@ -80,9 +85,16 @@ const (
 	// Period at which to attempt purging metadata from memory.
 	metadataPurgePeriod = 5 * time.Minute
 
+	// Period at which to refresh the estimated memory of the in-memory chunks.
+	chunksMemoryUpdatePeriod = 15 * time.Second
+
 	// How frequently update the usage statistics.
 	usageStatsUpdateInterval = usagestats.DefaultReportSendInterval / 10
 
+	// Period at which to remove the read-only mode of the ingesters which left the ring, and to switch
+	// the read-only ingester to the LEAVING state once it has joined the ring.
+	readOnlyCleanupPeriod = time.Minute
+
 	// IngesterRingKey is the key under which we store the ingesters ring in the KVStore.
 	IngesterRingKey = "ring"
 
@@ -101,6 +113,9 @@ const (
 	reasonSampleOutOfBounds    = "sample-out-of-bounds"
 	reasonPerUserSeriesLimit   = "per_user_series_limit"
 	reasonPerMetricSeriesLimit = "per_metric_series_limit"
+	reasonPerUserMemoryLimit   = "per_user_memory_limit"
+
+	reasonNativeHistogramCustomBuckets = "native-histogram-custom-buckets"
 
 	replicationFactorStatsName             = "ingester_replication_factor"
 	ringStoreStatsName                     = "ingester_ring_store"
@@ -122,12 +137,18 @@ var (
 	reasonIngesterMaxIngestionRate        = globalerror.IngesterMaxIngestionRate.LabelValue()
 	reasonIngesterMaxTenants              = globalerror.IngesterMaxTenants.LabelValue()
 	reasonIngesterMaxInMemorySeries       = globalerror.IngesterMaxInMemorySeries.LabelValue()
+	reasonIngesterMaxEstimatedMemory      = globalerror.IngesterMaxEstimatedMemory.LabelValue()
 	reasonIngesterMaxInflightPushRequests = globalerror.IngesterMaxInflightPushRequests.LabelValue()
 	// This is the closest fitting Prometheus API error code for requests rejected due to limiting.
 	tooBusyError = newErrorWithHTTPStatus(
 		errors.New(tooBusyErrorMsg),
 		http.StatusServiceUnavailable,
 	)
+
+	errSeriesEvictionWithoutActiveSeries   = fmt.Errorf("the -%s setting requires -%s to be enabled", validation.SeriesEvictionIdleTimeoutFlag, activeseries.EnabledFlag)
+	errSeriesEvictionIdleTimeoutTooLong    = fmt.Errorf("the -%s setting must not be greater than -%s", validation.SeriesEvictionIdleTimeoutFlag, activeseries.IdleTimeoutFlag)
+	errSampleLevelDedupWithoutActiveSeries = fmt.Errorf("the -%s setting requires -%s to be enabled", validation.HASampleLevelDedupWindowFlag, activeseries.EnabledFlag)
+	errSampleLevelDedupWindowTooLong       = fmt.Errorf("the -%s setting must not be greater than -%s", validation.HASampleLevelDedupWindowFlag, activeseries.IdleTimeoutFlag)
 )
 
 // BlocksUploader interface is used to have an easy way to mock it in tests.
@@ -179,6 +200,10 @@ type Config struct {
 	LimitInflightRequestsUsingGrpcMethodLimiter bool `yaml:"limit_inflight_requests_using_grpc_method_limiter" category:"experimental"`
 
 	ErrorSampleRate int64 `yaml:"error_sample_rate" json:"error_sample_rate" category:"experimental"`
+
+	Transfer TransferConfig `yaml:"transfer"`
+	// Injected internally, used to transfer the TSDBs to another ingester.
+	IngesterClientConfig client.Config `yaml:"-"`
 }
 
 // RegisterFlags adds the flags required to config this to the given FlagSet
@@ -197,16 +222,47 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	f.BoolVar(&cfg.LogUtilizationBasedLimiterCPUSamples, "ingester.log-utilization-based-limiter-cpu-samples", false, "Enable logging of utilization based limiter CPU samples.")
 	f.BoolVar(&cfg.LimitInflightRequestsUsingGrpcMethodLimiter, "ingester.limit-inflight-requests-using-grpc-method-limiter", false, "Use experimental method of limiting push requests.")
 	f.Int64Var(&cfg.ErrorSampleRate, "ingester.error-sample-rate", 0, "Each error will be logged once in this many times. Use 0 to log all of them.")
+
+	cfg.Transfer.RegisterFlags(f)
 }
 
-func (cfg *Config) Validate() error {
+func (cfg *Config) Validate(tsdbCfg mimir_tsdb.TSDBConfig) error {
 	if cfg.ErrorSampleRate < 0 {
 		return fmt.Errorf("error sample rate cannot be a negative number")
 	}
 
+	if err := cfg.Transfer.Validate(tsdbCfg); err != nil {
+		return err
+	}
+
 	return cfg.IngesterRing.Validate()
 }
 
+// ValidateLimits validates the runtime limits that can be set for each tenant against the ingester config.
+func (cfg *Config) ValidateLimits(limits validation.Limits) error {
+	if limits.SeriesEvictionIdleTimeout > 0 {
+		if !cfg.ActiveSeriesMetrics.Enabled {
+			return errSeriesEvictionWithoutActiveSeries
+		}
+		if time.Duration(limits.SeriesEvictionIdleTimeout) > cfg.ActiveSeriesMetrics.IdleTimeout {
+			return errSeriesEvictionIdleTimeoutTooLong
+		}
+	}
+
+	// The ingesters append the samples of the non-elected HA replicas based on the active series. The series
+	// which haven't been updated for longer than the idle timeout aren't tracked, so they can't be told apart.
+	if limits.HASampleLevelDedupWindow > 0 {
+		if !cfg.ActiveSeriesMetrics.Enabled {
+			return errSampleLevelDedupWithoutActiveSeries
+		}
+		if time.Duration(limits.HASampleLevelDedupWindow) > cfg.ActiveSeriesMetrics.IdleTimeout {
+			return errSampleLevelDedupWindowTooLong
+		}
+	}
+
+	return nil
+}
+
 func (cfg *Config) getIgnoreSeriesLimitForMetricNamesMap() map[string]struct{} {
 	if cfg.IgnoreSeriesLimitForMetricNames == "" {
 		return nil
@@ -258,6 +314,14 @@ type Ingester struct {
 	subservices  *services.Manager
 	activeGroups *util.ActiveGroupsCleanupService
 
+	// Per-tenant active series, broken down by the tenant's cost attribution labels. Nil if the
+	// active series metrics are disabled.
+	costAttribution *costattribution.Manager
+
+	// Aggregates the input series of the tenants' streaming aggregation rules whose output series are
+	// owned by this ingester.
+	streamAggregator *streamaggr.Aggregator
+
 	tsdbMetrics *tsdbMetrics
 
 	forceCompactTrigger chan requestWithUsersAndCallback
@@ -272,6 +336,9 @@ type Ingester struct {
 	// Number of series in memory, across all tenants.
 	seriesCount atomic.Int64
 
+	// Estimated memory, in bytes, of the series in memory, across all tenants.
+	memoryBytes atomic.Int64
+
 	// For storing metadata ingested.
 	usersMetadataMtx sync.RWMutex
 	usersMetadata    map[string]*userMetricsMetadata
@@ -280,6 +347,14 @@ type Ingester struct {
 	ingestionRate        *util_math.EwmaRate
 	inflightPushRequests atomic.Int64
 
+	// Unix timestamp, in milliseconds, the read-only mode has been enabled at, or 0 if the ingester is not read-only.
+	readOnlySince atomic.Int64
+	// Client of the KV store where the read-only mode is stored, so that distributors stop sending writes to the ingester.
+	readOnlyKV kv.Client
+
+	// Held while receiving the TSDBs of a leaving ingester.
+	transferMtx sync.Mutex
+
 	// Anonymous usage statistics tracked by ingester.
 	memorySeriesStats                  *expvar.Int
 	memoryTenantsStats                 *expvar.Int
@@ -343,6 +418,10 @@ func New(cfg Config, limits *validation.Overrides, activeGroupsCleanupService *u
 	i.ingestionRate = util_math.NewEWMARate(0.2, instanceIngestionRateTickInterval)
 	i.metrics = newIngesterMetrics(registerer, cfg.ActiveSeriesMetrics.Enabled, i.getInstanceLimits, i.ingestionRate, &i.inflightPushRequests)
 	i.activeGroups = activeGroupsCleanupService
+	if cfg.ActiveSeriesMetrics.Enabled {
+		i.costAttribution = costattribution.NewManager(cfg.ActiveSeriesMetrics.UpdatePeriod, cfg.ActiveSeriesMetrics.IdleTimeout, limits, registerer)
+	}
+	i.streamAggregator = streamaggr.NewAggregator(streamaggr.NewRules(limits), i.pushAggregatedSeries, logger, registerer)
 
 	if registerer != nil {
 		promauto.With(registerer).NewGaugeFunc(prometheus.GaugeOpts{
@@ -361,13 +440,24 @@ func New(cfg Config, limits *validation.Overrides, activeGroupsCleanupService *u
 		}, i.maxTsdbHeadTimestamp)
 	}
 
-	i.lifecycler, err = ring.NewLifecycler(cfg.IngesterRing.ToLifecyclerConfig(logger), i, "ingester", IngesterRingKey, cfg.BlocksStorageConfig.TSDB.FlushBlocksOnShutdown, logger, prometheus.WrapRegistererWithPrefix("cortex_", registerer))
+	lifecyclerCfg := cfg.IngesterRing.ToLifecyclerConfig(logger)
+	if cfg.Transfer.Enabled {
+		// Give a leaving ingester the time to transfer its TSDBs before joining the ring with new tokens.
+		lifecyclerCfg.JoinAfter = cfg.Transfer.JoinAfter
+	}
+
+	i.lifecycler, err = ring.NewLifecycler(lifecyclerCfg, i, "ingester", IngesterRingKey, cfg.BlocksStorageConfig.TSDB.FlushBlocksOnShutdown, logger, prometheus.WrapRegistererWithPrefix("cortex_", registerer))
 	if err != nil {
 		return nil, err
 	}
 	i.subservicesWatcher = services.NewFailureWatcher()
 	i.subservicesWatcher.WatchService(i.lifecycler)
 
+	i.readOnlyKV, err = kv.NewClient(cfg.IngesterRing.KVStore, readonly.GetCodec(), kv.RegistererWithKVName(prometheus.WrapRegistererWithPrefix("cortex_", registerer), "ingester-read-only-lifecycler"), logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the read-only mode KV store client")
+	}
+
 	// Init the limter and instantiate the user states which depend on it
 	i.limiter = NewLimiter(
 		limits,
@@ -431,6 +521,23 @@ func (i *Ingester) starting(ctx context.Context) error {
 		return errors.Wrap(err, "opening existing TSDBs")
 	}
 
+	// The read-only mode must be re-applied before the ingester joins the ring, so that distributors
+	// never send writes to it.
+	markerPath := readOnlyMarkerPath(i.cfg.BlocksStorageConfig.TSDB.Dir)
+	readOnlySince, readOnlyMarkerFound, err := readReadOnlyMarker(markerPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to check ingester read-only marker")
+	}
+
+	if readOnlyMarkerFound {
+		level.Info(i.logger).Log("msg", "detected existing read-only marker, switching to read-only mode", "path", markerPath, "since", readOnlySince)
+		if err := i.setReadOnly(ctx, readOnlySince); err != nil {
+			return err
+		}
+	} else if err := i.removeReadOnly(ctx); err != nil {
+		return errors.Wrap(err, "failed to remove the read-only mode from the KV store")
+	}
+
 	// Important: we want to keep lifecycler running until we ask it to stop, so we need to give it independent context
 	if err := i.lifecycler.StartAsync(context.Background()); err != nil {
 		return errors.Wrap(err, "failed to start lifecycler")
@@ -439,6 +546,12 @@ func (i *Ingester) starting(ctx context.Context) error {
 		return errors.Wrap(err, "failed to start lifecycler")
 	}
 
+	// The lifecycler switches a restarted ingester from LEAVING to ACTIVE, so a read-only ingester leaves
+	// the ring again. It rejects the writes it receives in the meantime.
+	if err := i.leaveRingIfReadOnly(ctx); err != nil {
+		return err
+	}
+
 	// let's start the rest of subservices via manager
 	servs := []services.Service(nil)
 
@@ -463,6 +576,14 @@ func (i *Ingester) starting(ctx context.Context) error {
 		servs = append(servs, i.utilizationBasedLimiter)
 	}
 
+	if i.costAttribution != nil {
+		servs = append(servs, i.costAttribution)
+	}
+
+	if i.streamAggregator != nil {
+		servs = append(servs, i.streamAggregator)
+	}
+
 	shutdownMarkerPath := shutdownmarker.GetPath(i.cfg.BlocksStorageConfig.TSDB.Dir)
 	shutdownMarkerFound, err := shutdownmarker.Exists(shutdownMarkerPath)
 	if err != nil {
@@ -502,6 +623,14 @@ func (i *Ingester) stopping(_ error) error {
 		level.Warn(i.logger).Log("msg", "failed to stop ingester lifecycler", "err", err)
 	}
 
+	// Forget the read-only mode of the ingester once it has left the ring, so that it doesn't apply to a new
+	// ingester with the same ID. It's re-applied from the read-only marker if the ingester restarts.
+	if i.isReadOnly() && i.lifecycler.ShouldUnregisterOnShutdown() {
+		if err := readonly.Update(context.Background(), i.readOnlyKV, i.lifecycler.ID, false, time.Time{}); err != nil {
+			level.Warn(i.logger).Log("msg", "failed to remove the read-only mode from the KV store", "err", err)
+		}
+	}
+
 	// Remove the shutdown marker if it exists since we are shutting down
 	shutdownMarkerPath := shutdownmarker.GetPath(i.cfg.BlocksStorageConfig.TSDB.Dir)
 	if err := shutdownmarker.Remove(shutdownMarkerPath); err != nil {
@@ -552,15 +681,23 @@ func (i *Ingester) updateLoop(ctx context.Context) error {
 	metadataPurgeTicker := time.NewTicker(metadataPurgePeriod)
 	defer metadataPurgeTicker.Stop()
 
+	chunksMemoryUpdateTicker := time.NewTicker(chunksMemoryUpdatePeriod)
+	defer chunksMemoryUpdateTicker.Stop()
+
 	usageStatsUpdateTicker := time.NewTicker(usageStatsUpdateInterval)
 	defer usageStatsUpdateTicker.Stop()
 
+	readOnlyCleanupTicker := time.NewTicker(readOnlyCleanupPeriod)
+	defer readOnlyCleanupTicker.Stop()
+
 	for {
 		select {
 		case <-metadataPurgeTicker.C:
 			i.purgeUserMetricsMetadata()
 		case <-ingestionRateTicker.C:
 			i.ingestionRate.Tick()
+		case <-chunksMemoryUpdateTicker.C:
+			i.updateEstimatedMemory()
 		case <-rateUpdateTicker.C:
 			i.tsdbsMtx.RLock()
 			for _, db := range i.tsdbs {
@@ -578,6 +715,12 @@ func (i *Ingester) updateLoop(ctx context.Context) error {
 		case <-usageStatsUpdateTicker.C:
 			i.updateUsageStats()
 
+		case <-readOnlyCleanupTicker.C:
+			i.removeLeftIngestersReadOnly(ctx)
+			if err := i.leaveRingIfReadOnly(ctx); err != nil {
+				level.Warn(i.logger).Log("msg", "failed to switch the read-only ingester to the LEAVING state", "err", err)
+			}
+
 		case <-ctx.Done():
 			return nil
 		case err := <-i.subservicesWatcher.Chan():
@@ -586,6 +729,24 @@ func (i *Ingester) updateLoop(ctx context.Context) error {
 	}
 }
 
+// updateEstimatedMemory refreshes the estimated memory of the in-memory chunks of each tenant.
+func (i *Ingester) updateEstimatedMemory() {
+	oooCapMax := int64(i.cfg.BlocksStorageConfig.TSDB.OutOfOrderCapacityMax)
+
+	for _, userID := range i.getTSDBUsers() {
+		userDB := i.getTSDB(userID)
+		if userDB == nil {
+			continue
+		}
+
+		if err := userDB.updateChunksMemory(oooCapMax); err != nil {
+			level.Warn(i.logger).Log("msg", "failed to update the estimated memory of the in-memory chunks", "user", userID, "err", err)
+			continue
+		}
+		i.metrics.estimatedMemory.WithLabelValues(userID).Set(float64(userDB.memoryStats().Total()))
+	}
+}
+
 func (i *Ingester) replaceMatchers(asm *activeseries.Matchers, userDB *userTSDB, now time.Time) {
 	i.metrics.deletePerUserCustomTrackerMetrics(userDB.userID, userDB.activeSeries.CurrentMatcherNames())
 	userDB.activeSeries.ReloadMatchers(asm, now)
@@ -602,13 +763,19 @@ func (i *Ingester) updateActiveSeries(now time.Time) {
 		if newMatchersConfig.String() != userDB.activeSeries.CurrentConfig().String() {
 			i.replaceMatchers(activeseries.NewMatchers(newMatchersConfig), userDB, now)
 		}
+		cat := i.costAttribution.Tracker(userID)
+		if cat != userDB.activeSeries.CurrentCostAttribution() {
+			userDB.activeSeries.ReloadCostAttribution(cat, now)
+		}
 		valid := userDB.activeSeries.Purge(now)
+		i.updateEvictableSeries(userID, userDB, now, valid)
 		if !valid {
 			// Active series config has been reloaded, exposing loading metric until MetricsIdleTimeout passes.
 			i.metrics.activeSeriesLoading.WithLabelValues(userID).Set(1)
 		} else {
 			allActive, activeMatching, allActiveHistograms, activeMatchingHistograms, allActiveBuckets, activeMatchingBuckets := userDB.activeSeries.ActiveWithMatchers()
 			i.metrics.activeSeriesLoading.DeleteLabelValues(userID)
+			cat.SetActiveSeries(userDB.activeSeries.ActiveByAttribution(), now)
 			if allActive > 0 {
 				i.metrics.activeSeriesPerUser.WithLabelValues(userID).Set(float64(allActive))
 			} else {
@@ -647,6 +814,27 @@ func (i *Ingester) updateActiveSeries(now time.Time) {
 	}
 }
 
+// updateEvictableSeries updates the number of in-memory series of a tenant which haven't received samples for
+// longer than the series eviction idle timeout. The active series must have been purged and be valid.
+func (i *Ingester) updateEvictableSeries(userID string, userDB *userTSDB, now time.Time, valid bool) {
+	idleTimeout := i.limits.SeriesEvictionIdleTimeout(userID)
+	if idleTimeout <= 0 {
+		userDB.evictableSeries.Store(0)
+		userDB.seriesAdmittedByEviction.Store(0)
+		i.metrics.evictableSeries.DeleteLabelValues(userID)
+		return
+	}
+
+	// The series loaded from disk can't be told apart from the idle ones until they've been tracked for the whole idle timeout.
+	evictable := int64(0)
+	if idleSince := now.Add(-idleTimeout); valid && !userDB.openedAt.After(idleSince) {
+		evictable = util_math.Max(0, int64(userDB.Head().NumSeries())-int64(userDB.activeSeries.ActiveSince(idleSince)))
+	}
+
+	userDB.evictableSeries.Store(evictable)
+	i.metrics.evictableSeries.WithLabelValues(userID).Set(float64(evictable))
+}
+
 // updateUsageStats updated some anonymous usage statistics tracked by the ingester.
 // This function is expected to be called periodically.
 func (i *Ingester) updateUsageStats() {
@@ -753,6 +941,11 @@ type pushStats struct {
 	newValueForTimestampCount int
 	perUserSeriesLimitCount   int
 	perMetricSeriesLimitCount int
+	perUserMemoryLimitCount   int
+
+	nativeHistogramCustomBucketsCount int
+
+	nonElectedReplicaDedupedCount int
 }
 
 // StartPushRequest checks if ingester can start push request, and increments relevant counters.
@@ -767,6 +960,9 @@ func (i *Ingester) StartPushRequest() error {
 	if err := i.checkAvailable(); err != nil {
 		return err
 	}
+	if i.isReadOnly() {
+		return errReadOnly
+	}
 
 	inflight := i.inflightPushRequests.Inc()
 	decreaseInflightInDefer := true
@@ -818,6 +1014,12 @@ func (i *Ingester) PushWithCleanup(ctx context.Context, req *mimirpb.WriteReques
 		return err
 	}
 
+	// The input series of a streaming aggregation rule are only aggregated, not ingested.
+	if req.StreamingAggregationRule != "" {
+		i.streamAggregator.Aggregate(userID, req.StreamingAggregationRule, req.Timeseries, time.Now())
+		return nil
+	}
+
 	// Given metadata is a best-effort approach, and we don't halt on errors
 	// process it before samples. Otherwise, we risk returning an error before ingestion.
 	if ingestedMetadata := i.pushMetadata(ctx, userID, req.GetMetadata()); ingestedMetadata > 0 {
@@ -879,7 +1081,14 @@ func (i *Ingester) PushWithCleanup(ctx context.Context, req *mimirpb.WriteReques
 
 	minAppendTime, minAppendTimeAvailable := db.Head().AppendableMinValidTime()
 
-	err = i.pushSamplesToAppender(userID, req.Timeseries, app, startAppend, &stats, updateFirstPartial, activeSeries, i.limits.OutOfOrderTimeWindow(userID), minAppendTimeAvailable, minAppendTime)
+	var createdTimestamps *seriesCreatedTimestamps
+	if i.limits.CreatedTimestampZeroIngestionEnabled(userID) {
+		createdTimestamps = db.createdTimestamps
+	}
+
+	nonElectedReplicaWindow := time.Duration(req.NonElectedReplicaWindowMs) * time.Millisecond
+
+	err = i.pushSamplesToAppender(userID, req.Timeseries, app, startAppend, &stats, updateFirstPartial, activeSeries, createdTimestamps, i.limits.OutOfOrderTimeWindow(userID), minAppendTimeAvailable, minAppendTime, nonElectedReplicaWindow)
 	if err != nil {
 		if err := app.Rollback(); err != nil {
 			level.Warn(i.logger).Log("msg", "failed to rollback appender on error", "user", userID, "err", err)
@@ -918,6 +1127,9 @@ func (i *Ingester) PushWithCleanup(ctx context.Context, req *mimirpb.WriteReques
 	// which will be converted into an HTTP 5xx and the client should/will retry.
 	i.metrics.ingestedSamples.WithLabelValues(userID).Add(float64(stats.succeededSamplesCount))
 	i.metrics.ingestedSamplesFail.WithLabelValues(userID).Add(float64(stats.failedSamplesCount))
+	if stats.nonElectedReplicaDedupedCount > 0 {
+		i.metrics.nonElectedReplicaDedupedSamples.WithLabelValues(userID).Add(float64(stats.nonElectedReplicaDedupedCount))
+	}
 	i.metrics.ingestedExemplars.Add(float64(stats.succeededExemplarsCount))
 	i.metrics.ingestedExemplarsFail.Add(float64(stats.failedExemplarsCount))
 	i.appendedSamplesStats.Inc(int64(stats.succeededSamplesCount))
@@ -956,6 +1168,12 @@ func (i *Ingester) updateMetricsFromPushStats(userID string, group string, stats
 	if stats.perMetricSeriesLimitCount > 0 {
 		discarded.perMetricSeriesLimit.WithLabelValues(userID, group).Add(float64(stats.perMetricSeriesLimitCount))
 	}
+	if stats.perUserMemoryLimitCount > 0 {
+		discarded.perUserMemoryLimit.WithLabelValues(userID, group).Add(float64(stats.perUserMemoryLimitCount))
+	}
+	if stats.nativeHistogramCustomBucketsCount > 0 {
+		discarded.nativeHistogramCustomBuckets.WithLabelValues(userID, group).Add(float64(stats.nativeHistogramCustomBucketsCount))
+	}
 	if stats.succeededSamplesCount > 0 {
 		i.ingestionRate.Add(int64(stats.succeededSamplesCount))
 
@@ -972,7 +1190,7 @@ func (i *Ingester) updateMetricsFromPushStats(userID string, group string, stats
 // must be of type softError.
 func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.PreallocTimeseries, app extendedAppender, startAppend time.Time,
 	stats *pushStats, updateFirstPartial func(sampler *util_log.Sampler, errFn softErrorFunction), activeSeries *activeseries.ActiveSeries,
-	outOfOrderWindow time.Duration, minAppendTimeAvailable bool, minAppendTime int64) error {
+	createdTimestamps *seriesCreatedTimestamps, outOfOrderWindow time.Duration, minAppendTimeAvailable bool, minAppendTime int64, nonElectedReplicaWindow time.Duration) error {
 
 	// Return true if handled as soft error, and we can ingest more series.
 	handleAppendError := func(err error, timestamp int64, labels []mimirpb.LabelAdapter) bool {
@@ -1032,6 +1250,20 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 				return newPerMetricSeriesLimitReachedError(i.limiter.limits.MaxGlobalSeriesPerMetric(userID), mimirpb.FromLabelAdaptersToLabelsWithCopy(labels))
 			})
 			return true
+
+		case globalerror.MaxEstimatedMemoryPerUser:
+			stats.perUserMemoryLimitCount++
+			updateFirstPartial(i.errorSamplers.maxMemoryPerUserLimitExceeded, func() softError {
+				return newPerUserMemoryLimitReachedError(i.limiter.limits.MaxGlobalEstimatedMemoryPerUser(userID))
+			})
+			return true
+
+		case globalerror.NativeHistogramCustomBuckets:
+			stats.nativeHistogramCustomBucketsCount++
+			updateFirstPartial(i.errorSamplers.nativeHistogramCustomBuckets, func() softError {
+				return newNativeHistogramCustomBucketsError(model.Time(timestamp), labels)
+			})
+			return true
 		}
 		return false
 	}
@@ -1098,6 +1330,21 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 		// and NOT the stable hashing because we use the stable hashing in ingesters only for query sharding.
 		ref, copiedLabels := app.GetRef(nonCopiedLabels, hash)
 
+		// The samples of a non-elected HA replica are only appended to the series that the elected replica
+		// stopped sending. They don't update the active series, so that the series stays stale until the
+		// elected replica sends it again.
+		if nonElectedReplicaWindow > 0 {
+			if !isStaleSeries(activeSeries, ref, startAppend.Add(-nonElectedReplicaWindow), startAppend) {
+				stats.nonElectedReplicaDedupedCount += len(ts.Samples) + len(ts.Histograms)
+				continue
+			}
+		}
+
+		// Inject a zero sample at the created timestamp of the series, if enabled for the tenant.
+		if createdTimestamps != nil && ts.CreatedTimestamp > 0 {
+			ref, copiedLabels = appendCreatedTimestampZero(app, createdTimestamps, ref, copiedLabels, nonCopiedLabels, ts.TimeSeries, nativeHistogramsIngestionEnabled, minAppendTimeAvailable, minAppendTime)
+		}
+
 		// To find out if any sample was added to this series, we keep old value.
 		oldSucceededSamplesCount := stats.succeededSamplesCount
 
@@ -1150,6 +1397,13 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 					continue
 				}
 
+				// The TSDB doesn't support custom buckets yet: it would store the histogram without its
+				// custom values, and interpret its buckets with an exponential schema.
+				if h.IsCustomBuckets() {
+					handleAppendError(globalerror.NativeHistogramCustomBuckets, h.Timestamp, ts.Labels)
+					continue
+				}
+
 				if h.IsFloatHistogram() {
 					fh = mimirpb.FromFloatHistogramProtoToFloatHistogram(&h)
 				} else {
@@ -1195,7 +1449,11 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 			}
 		}
 
-		if activeSeries != nil && stats.succeededSamplesCount > oldSucceededSamplesCount {
+		if createdTimestamps != nil && stats.succeededSamplesCount > oldSucceededSamplesCount {
+			createdTimestamps.observe(ref, latestSampleTimestamp(ts.TimeSeries, nativeHistogramsIngestionEnabled, maxTimestampMs))
+		}
+
+		if activeSeries != nil && stats.succeededSamplesCount > oldSucceededSamplesCount && nonElectedReplicaWindow <= 0 {
 			activeSeries.UpdateSeries(nonCopiedLabels, ref, startAppend, numNativeHistogramBuckets)
 		}
 
@@ -1245,6 +1503,15 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 	return nil
 }
 
+// isStaleSeries returns whether the series exists in the head and hasn't been updated since staleBefore.
+// The series are never considered stale if the active series aren't tracked.
+func isStaleSeries(activeSeries *activeseries.ActiveSeries, ref storage.SeriesRef, staleBefore, now time.Time) bool {
+	if ref == 0 || activeSeries == nil {
+		return false
+	}
+	return activeSeries.IsStale(ref, staleBefore, now)
+}
+
 func (i *Ingester) QueryExemplars(ctx context.Context, req *client.ExemplarQueryRequest) (*client.ExemplarQueryResponse, error) {
 	if err := i.checkRunning(); err != nil {
 		return nil, err
@@ -1588,6 +1855,42 @@ func (i *Ingester) LabelValuesCardinality(req *client.LabelValuesCardinalityRequ
 	)
 }
 
+// ActiveSeries implements the ActiveSeries RPC. It returns the label sets of the active series matching the matchers.
+func (i *Ingester) ActiveSeries(request *client.ActiveSeriesRequest, stream client.Ingester_ActiveSeriesServer) error {
+	if err := i.checkRunning(); err != nil {
+		return err
+	}
+	if err := i.checkReadOverloaded(); err != nil {
+		return err
+	}
+
+	userID, err := tenant.TenantID(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	matchers, err := client.FromLabelMatchers(request.GetMatchers())
+	if err != nil {
+		return err
+	}
+	shard, matchers, err := sharding.RemoveShardFromMatchers(matchers)
+	if err != nil {
+		return err
+	}
+
+	db := i.getTSDB(userID)
+	if db == nil {
+		return nil
+	}
+	idx, err := db.Head().Index()
+	if err != nil {
+		return err
+	}
+	defer idx.Close()
+
+	return listActiveSeries(idx, db.activeSeries, matchers, shard, activeSeriesTargetSizeBytes, stream)
+}
+
 func createUserStats(db *userTSDB, req *client.UserStatsRequest) (*client.UserStatsResponse, error) {
 	apiRate := db.ingestedAPISamples.Rate()
 	ruleRate := db.ingestedRuleSamples.Rate()
@@ -2169,15 +2472,20 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 	matchersConfig := i.limits.ActiveSeriesCustomTrackersConfig(userID)
 
 	userDB := &userTSDB{
-		userID:              userID,
-		activeSeries:        activeseries.NewActiveSeries(activeseries.NewMatchers(matchersConfig), i.cfg.ActiveSeriesMetrics.IdleTimeout),
-		seriesInMetric:      newMetricCounter(i.limiter, i.cfg.getIgnoreSeriesLimitForMetricNamesMap()),
-		ingestedAPISamples:  util_math.NewEWMARate(0.2, i.cfg.RateUpdatePeriod),
-		ingestedRuleSamples: util_math.NewEWMARate(0.2, i.cfg.RateUpdatePeriod),
-		instanceLimitsFn:    i.getInstanceLimits,
-		instanceSeriesCount: &i.seriesCount,
-		instanceErrors:      i.metrics.rejected,
-		blockMinRetention:   i.cfg.BlocksStorageConfig.TSDB.Retention,
+		userID:               userID,
+		activeSeries:         activeseries.NewActiveSeries(activeseries.NewMatchers(matchersConfig), i.costAttribution.Tracker(userID), i.cfg.ActiveSeriesMetrics.IdleTimeout),
+		seriesInMetric:       newMetricCounter(i.limiter, i.cfg.getIgnoreSeriesLimitForMetricNamesMap()),
+		ingestedAPISamples:   util_math.NewEWMARate(0.2, i.cfg.RateUpdatePeriod),
+		ingestedRuleSamples:  util_math.NewEWMARate(0.2, i.cfg.RateUpdatePeriod),
+		instanceLimitsFn:     i.getInstanceLimits,
+		instanceSeriesCount:  &i.seriesCount,
+		instanceMemoryBytes:  &i.memoryBytes,
+		instanceErrors:       i.metrics.rejected,
+		seriesEvictionDryRun: i.metrics.seriesEvictionDryRun,
+		openedAt:             time.Now(),
+		createdTimestamps:    newSeriesCreatedTimestamps(),
+		blockMinRetention:    i.cfg.BlocksStorageConfig.TSDB.Retention,
+		tsdbRegistry:         tsdbPromReg,
 	}
 
 	maxExemplars := i.limiter.convertGlobalToLocalLimit(userID, i.limits.MaxGlobalExemplarsPerUser(userID))
@@ -2253,6 +2561,15 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 
 	// Create a new shipper for this database
 	if i.cfg.BlocksStorageConfig.TSDB.IsBlocksShippingEnabled() {
+		var exemplarsToPersist storage.ExemplarQueryable
+		if i.cfg.BlocksStorageConfig.TSDB.PersistExemplars {
+			exemplarsToPersist = userDB
+		}
+		var metadataToPersist metadataHistorySource
+		if i.cfg.BlocksStorageConfig.TSDB.PersistMetadata {
+			metadataToPersist = userMetadataHistory{ingester: i, userID: userID}
+		}
+
 		userDB.shipper = newShipper(
 			userLogger,
 			i.limits,
@@ -2261,6 +2578,8 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 			udir,
 			bucket.NewUserBucketClient(userID, i.bucket, i.limits),
 			block.ReceiveSource,
+			exemplarsToPersist,
+			metadataToPersist,
 		)
 
 		// Initialise the shipper blocks cache.
@@ -2286,11 +2605,17 @@ func (i *Ingester) closeAllTSDB() {
 		go func(db *userTSDB) {
 			defer wg.Done()
 
+			// The series are added back to the series count and estimated memory when the TSDB is reopened.
+			numSeries := db.Head().NumSeries()
+
 			if err := db.Close(); err != nil {
 				level.Warn(i.logger).Log("msg", "unable to close TSDB", "err", err, "user", userID)
 				return
 			}
 
+			i.seriesCount.Sub(int64(numSeries))
+			db.releaseMemory()
+
 			// Now that the TSDB has been closed, we should remove it from the
 			// set of open ones. This lock acquisition doesn't deadlock with the
 			// outer one, because the outer one is released as soon as all go
@@ -2300,7 +2625,9 @@ func (i *Ingester) closeAllTSDB() {
 			i.tsdbsMtx.Unlock()
 
 			i.metrics.memUsers.Dec()
+			i.metrics.estimatedMemory.DeleteLabelValues(userID)
 			i.metrics.deletePerUserCustomTrackerMetrics(userID, db.activeSeries.CurrentMatcherNames())
+			i.costAttribution.RemoveTracker(userID)
 		}(userDB)
 	}
 
@@ -2422,8 +2749,12 @@ func (i *Ingester) findUserIDsWithTSDBOnFilesystem() ([]string, error) {
 			return nil
 		}
 
-		// Top level directories are assumed to be user TSDBs
+		// Top level directories are assumed to be user TSDBs, except the staging directory of TSDB transfers.
 		userID := info.Name()
+		if userID == transferStagingDirName {
+			return filepath.SkipDir
+		}
+
 		f, err := os.Open(path)
 		if err != nil {
 			level.Error(i.logger).Log("msg", "unable to open TSDB dir", "err", err, "user", userID, "path", path)
@@ -2621,6 +2952,9 @@ func (i *Ingester) compactionLoop(ctx context.Context) error {
 			// Check if any TSDB Head should be compacted to reduce the number of in-memory series.
 			i.compactBlocksToReduceInMemorySeries(ctx, time.Now())
 
+			// Check if any TSDB Head should be compacted to evict the idle series of a tenant which reached the series limit.
+			i.compactBlocksToEvictIdleSeries(ctx, time.Now())
+
 			// Run it at a regular (configured) interval after the first compaction.
 			if !tickerRunOnce {
 				ticker.Reset(i.cfg.BlocksStorageConfig.TSDB.HeadCompactionInterval)
@@ -2757,6 +3091,52 @@ func (i *Ingester) compactBlocksToReduceInMemorySeries(ctx context.Context, now
 	level.Info(i.logger).Log("msg", "run TSDB head compaction to reduce the number of in-memory series", "before_in_memory_series", totalMemorySeries, "after_in_memory_series", i.seriesCount.Load())
 }
 
+// compactBlocksToEvictIdleSeries compacts the TSDB Head of the tenants which admitted new series over the series
+// limit, up until "now - series eviction idle timeout", so that their idle series are evicted from memory.
+// Forcing a TSDB Head compaction cuts a block, which costs CPU, disk and object storage uploads, so the idle
+// series of a tenant are evicted at most once per series eviction idle timeout.
+func (i *Ingester) compactBlocksToEvictIdleSeries(ctx context.Context, now time.Time) {
+	for _, userID := range i.getTSDBUsers() {
+		db := i.getTSDB(userID)
+		if db == nil || !db.seriesEvictionRequested.Load() {
+			continue
+		}
+
+		idleTimeout := i.limits.SeriesEvictionIdleTimeout(userID)
+		if idleTimeout > 0 && now.Sub(db.lastSeriesEviction) < idleTimeout {
+			// The new series keep being charged against the idle series until they're evicted.
+			continue
+		}
+		db.seriesEvictionRequested.Store(false)
+
+		evictable := db.evictableSeries.Load()
+		if idleTimeout <= 0 || evictable <= 0 {
+			continue
+		}
+		db.lastSeriesEviction = now
+
+		if i.limits.SeriesEvictionDryRun(userID) {
+			level.Info(i.logger).Log("msg", "series eviction dry-run: idle series would have been evicted to make room for new series", "user", userID, "idle_series", evictable, "idle_timeout", idleTimeout)
+			continue
+		}
+
+		level.Info(i.logger).Log("msg", "running TSDB head compaction to evict idle series", "user", userID, "idle_series", evictable, "admitted_series", db.seriesAdmittedByEviction.Load(), "idle_timeout", idleTimeout)
+		before := db.Head().NumSeries()
+		i.compactBlocks(ctx, true, now.Add(-idleTimeout).UnixMilli(), util.NewAllowedTenants([]string{userID}, nil))
+		after := db.Head().NumSeries()
+
+		// The idle series are no longer in memory, so they can't be evicted again until the next update,
+		// and the series admitted by evicting them are now counted in the TSDB head.
+		db.evictableSeries.Store(0)
+		db.seriesAdmittedByEviction.Store(0)
+		i.metrics.evictableSeries.WithLabelValues(userID).Set(0)
+		if before > after {
+			i.metrics.evictedSeries.WithLabelValues(userID).Add(float64(before - after))
+		}
+		level.Info(i.logger).Log("msg", "run TSDB head compaction to evict idle series", "user", userID, "before_in_memory_series", before, "after_in_memory_series", after)
+	}
+}
+
 type seriesReductionEstimation struct {
 	userID              string
 	estimatedCount      int64
@@ -2852,6 +3232,7 @@ func (i *Ingester) closeAndDeleteUserTSDBIfIdle(userID string) tsdbCloseCheckRes
 	// but if we're closing TSDB because of tenant deletion mark, then it may still contain some series.
 	// We need to remove these series from series count.
 	i.seriesCount.Sub(int64(userDB.Head().NumSeries()))
+	userDB.releaseMemory()
 
 	dir := userDB.db.Dir()
 
@@ -2881,7 +3262,9 @@ func (i *Ingester) closeAndDeleteUserTSDBIfIdle(userID string) tsdbCloseCheckRes
 
 	i.deleteUserMetadata(userID)
 	i.metrics.deletePerUserMetrics(userID)
+	i.streamAggregator.RemoveTenant(userID)
 	i.metrics.deletePerUserCustomTrackerMetrics(userID, userDB.activeSeries.CurrentMatcherNames())
+	i.costAttribution.RemoveTracker(userID)
 
 	// And delete local data.
 	if err := os.RemoveAll(dir); err != nil {
@@ -2902,11 +3285,6 @@ func (i *Ingester) RemoveGroupMetricsForUser(userID, group string) {
 	i.metrics.deletePerGroupMetricsForUser(userID, group)
 }
 
-// TransferOut implements ring.FlushTransferer.
-func (i *Ingester) TransferOut(_ context.Context) error {
-	return ring.ErrTransferDisabled
-}
-
 // Flush will flush all data. It is called as part of Lifecycler's shutdown (if flush on shutdown is configured), or from the flusher.
 //
 // When called as during Lifecycler shutdown, this happens as part of normal Ingester shutdown (see stopping method).
@@ -2944,67 +3322,69 @@ func (i *Ingester) FlushHandler(w http.ResponseWriter, r *http.Request) {
 	tenants := r.Form[tenantParam]
 
 	allowedUsers := util.NewAllowedTenants(tenants, nil)
-	run := func() {
-		ingCtx := i.BasicService.ServiceContext()
-		if ingCtx == nil || ingCtx.Err() != nil {
-			level.Info(i.logger).Log("msg", "flushing TSDB blocks: ingester not running, ignoring flush request")
-			return
-		}
+	if len(r.Form[waitParam]) > 0 && r.Form[waitParam][0] == "true" {
+		// Run synchronously. This simplifies and speeds up tests.
+		i.compactAndShipBlocks(allowedUsers)
+	} else {
+		go i.compactAndShipBlocks(allowedUsers)
+	}
 
-		compactionCallbackCh := make(chan struct{})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// compactAndShipBlocks force-compacts the TSDB head of the allowed tenants, and then ships the resulting
+// blocks, waiting until both have been completed.
+func (i *Ingester) compactAndShipBlocks(allowedUsers *util.AllowedTenants) {
+	ingCtx := i.BasicService.ServiceContext()
+	if ingCtx == nil || ingCtx.Err() != nil {
+		level.Info(i.logger).Log("msg", "flushing TSDB blocks: ingester not running, ignoring flush request")
+		return
+	}
+
+	compactionCallbackCh := make(chan struct{})
+
+	level.Info(i.logger).Log("msg", "flushing TSDB blocks: triggering compaction")
+	select {
+	case i.forceCompactTrigger <- requestWithUsersAndCallback{users: allowedUsers, callback: compactionCallbackCh}:
+		// Compacting now.
+	case <-ingCtx.Done():
+		level.Warn(i.logger).Log("msg", "failed to compact TSDB blocks, ingester not running anymore")
+		return
+	}
+
+	// Wait until notified about compaction being finished.
+	select {
+	case <-compactionCallbackCh:
+		level.Info(i.logger).Log("msg", "finished compacting TSDB blocks")
+	case <-ingCtx.Done():
+		level.Warn(i.logger).Log("msg", "failed to compact TSDB blocks, ingester not running anymore")
+		return
+	}
+
+	if i.cfg.BlocksStorageConfig.TSDB.IsBlocksShippingEnabled() {
+		shippingCallbackCh := make(chan struct{}) // must be new channel, as compactionCallbackCh is closed now.
+
+		level.Info(i.logger).Log("msg", "flushing TSDB blocks: triggering shipping")
 
-		level.Info(i.logger).Log("msg", "flushing TSDB blocks: triggering compaction")
 		select {
-		case i.forceCompactTrigger <- requestWithUsersAndCallback{users: allowedUsers, callback: compactionCallbackCh}:
-			// Compacting now.
+		case i.shipTrigger <- requestWithUsersAndCallback{users: allowedUsers, callback: shippingCallbackCh}:
+			// shipping now
 		case <-ingCtx.Done():
-			level.Warn(i.logger).Log("msg", "failed to compact TSDB blocks, ingester not running anymore")
+			level.Warn(i.logger).Log("msg", "failed to ship TSDB blocks, ingester not running anymore")
 			return
 		}
 
-		// Wait until notified about compaction being finished.
+		// Wait until shipping finished.
 		select {
-		case <-compactionCallbackCh:
-			level.Info(i.logger).Log("msg", "finished compacting TSDB blocks")
+		case <-shippingCallbackCh:
+			level.Info(i.logger).Log("msg", "shipping of TSDB blocks finished")
 		case <-ingCtx.Done():
-			level.Warn(i.logger).Log("msg", "failed to compact TSDB blocks, ingester not running anymore")
+			level.Warn(i.logger).Log("msg", "failed to ship TSDB blocks, ingester not running anymore")
 			return
 		}
-
-		if i.cfg.BlocksStorageConfig.TSDB.IsBlocksShippingEnabled() {
-			shippingCallbackCh := make(chan struct{}) // must be new channel, as compactionCallbackCh is closed now.
-
-			level.Info(i.logger).Log("msg", "flushing TSDB blocks: triggering shipping")
-
-			select {
-			case i.shipTrigger <- requestWithUsersAndCallback{users: allowedUsers, callback: shippingCallbackCh}:
-				// shipping now
-			case <-ingCtx.Done():
-				level.Warn(i.logger).Log("msg", "failed to ship TSDB blocks, ingester not running anymore")
-				return
-			}
-
-			// Wait until shipping finished.
-			select {
-			case <-shippingCallbackCh:
-				level.Info(i.logger).Log("msg", "shipping of TSDB blocks finished")
-			case <-ingCtx.Done():
-				level.Warn(i.logger).Log("msg", "failed to ship TSDB blocks, ingester not running anymore")
-				return
-			}
-		}
-
-		level.Info(i.logger).Log("msg", "flushing TSDB blocks: finished")
-	}
-
-	if len(r.Form[waitParam]) > 0 && r.Form[waitParam][0] == "true" {
-		// Run synchronously. This simplifies and speeds up tests.
-		run()
-	} else {
-		go run()
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	level.Info(i.logger).Log("msg", "flushing TSDB blocks: finished")
 }
 
 func (i *Ingester) getInstanceLimits() *InstanceLimits {
@@ -3154,6 +3534,79 @@ func (i *Ingester) Push(ctx context.Context, req *mimirpb.WriteRequest) (*mimirp
 	return nil, handledErr
 }
 
+// pushAggregatedSeries ingests the output series of the tenant's streaming aggregation rules. The input series
+// have been routed to the ingesters owning the output series, so they're appended to this ingester. The output
+// series are appended directly to the tenant's TSDB: the push requests of the input series have already been
+// admitted, so they're not subject to the inflight push requests limit, nor rejected by a read-only ingester.
+func (i *Ingester) pushAggregatedSeries(ctx context.Context, userID string, series []mimirpb.PreallocTimeseries) error {
+	if len(series) == 0 {
+		return nil
+	}
+
+	// A read-only ingester is LEAVING, but it still creates the TSDB of the tenants whose input
+	// series have been received before the read-only mode has been enabled.
+	db, err := i.getOrCreateTSDB(userID, i.isReadOnly())
+	if err != nil {
+		return wrapOrAnnotateWithUser(err, userID)
+	}
+
+	req := &mimirpb.WriteRequest{Timeseries: series, Source: mimirpb.API}
+	lockState, err := db.acquireAppendLock(req.MinTimestamp())
+	if err != nil {
+		return wrapOrAnnotateWithUser(err, userID)
+	}
+	defer db.releaseAppendLock(lockState)
+
+	var (
+		startAppend     = time.Now()
+		stats           pushStats
+		firstPartialErr error
+	)
+	updateFirstPartial := func(sampler *util_log.Sampler, errFn softErrorFunction) {
+		if firstPartialErr == nil {
+			firstPartialErr = errFn()
+			if sampler != nil {
+				firstPartialErr = sampler.WrapError(firstPartialErr)
+			}
+		}
+	}
+
+	var activeSeries *activeseries.ActiveSeries
+	if i.cfg.ActiveSeriesMetrics.Enabled {
+		activeSeries = db.activeSeries
+	}
+	minAppendTime, minAppendTimeAvailable := db.Head().AppendableMinValidTime()
+
+	app := db.Appender(user.InjectOrgID(ctx, userID)).(extendedAppender)
+	err = i.pushSamplesToAppender(userID, series, app, startAppend, &stats, updateFirstPartial, activeSeries, nil, i.limits.OutOfOrderTimeWindow(userID), minAppendTimeAvailable, minAppendTime, 0)
+	if err != nil {
+		if err := app.Rollback(); err != nil {
+			level.Warn(i.logger).Log("msg", "failed to rollback appender on error", "user", userID, "err", err)
+		}
+		return wrapOrAnnotateWithUser(err, userID)
+	}
+
+	if err := app.Commit(); err != nil {
+		return wrapOrAnnotateWithUser(err, userID)
+	}
+
+	if stats.succeededSamplesCount > 0 {
+		db.setLastUpdate(time.Now())
+	}
+
+	i.metrics.ingestedSamples.WithLabelValues(userID).Add(float64(stats.succeededSamplesCount))
+	i.metrics.ingestedSamplesFail.WithLabelValues(userID).Add(float64(stats.failedSamplesCount))
+	i.appendedSamplesStats.Inc(int64(stats.succeededSamplesCount))
+
+	group := i.activeGroups.UpdateActiveGroupTimestamp(userID, validation.GroupLabel(i.limits, userID, series), startAppend)
+	i.updateMetricsFromPushStats(userID, group, &stats, req.Source, db, i.metrics.discarded)
+
+	if firstPartialErr != nil {
+		return wrapOrAnnotateWithUser(firstPartialErr, userID)
+	}
+	return nil
+}
+
 func handlePushError(err error) error {
 	var ingesterErr ingesterError
 	if errors.As(err, &ingesterErr) {
@@ -3219,7 +3672,7 @@ func (i *Ingester) getOrCreateUserMetadata(userID string) *userMetricsMetadata {
 	// Ensure it was not created between switching locks.
 	userMetadata, ok := i.usersMetadata[userID]
 	if !ok {
-		userMetadata = newMetadataMap(i.limiter, i.metrics, i.errorSamplers, userID)
+		userMetadata = newMetadataMap(i.limiter, i.metrics, i.errorSamplers, userID, i.cfg.BlocksStorageConfig.TSDB.PersistMetadata)
 		i.usersMetadata[userID] = userMetadata
 	}
 	return userMetadata
@@ -3231,6 +3684,26 @@ func (i *Ingester) getUserMetadata(userID string) *userMetricsMetadata {
 	return i.usersMetadata[userID]
 }
 
+// userMetadataHistory is the metadataHistorySource of a tenant. The tenant metadata is looked up
+// each time, because it's created on the first metadata received and deleted along with the TSDB.
+type userMetadataHistory struct {
+	ingester *Ingester
+	userID   string
+}
+
+func (h userMetadataHistory) metadataHistory(mint, maxt int64) []block.MetricMetadata {
+	if um := h.ingester.getUserMetadata(h.userID); um != nil {
+		return um.metadataHistory(mint, maxt)
+	}
+	return nil
+}
+
+func (h userMetadataHistory) purgeHistory(before int64) {
+	if um := h.ingester.getUserMetadata(h.userID); um != nil {
+		um.purgeHistory(before)
+	}
+}
+
 func (i *Ingester) deleteUserMetadata(userID string) {
 	i.usersMetadataMtx.Lock()
 	um := i.usersMetadata[userID]
@@ -0,0 +1,267 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/pkg/errors"
+
+	"github.com/grafana/mimir/pkg/ingester/readonly"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/shutdownmarker"
+)
+
+// readOnlyMarkerFilename is the name of the file, stored in the TSDB directory, used to re-apply
+// the read-only mode if the ingester restarts.
+const readOnlyMarkerFilename = "read-only-requested.txt"
+
+// readOnlyCleanupGracePeriod is how long the read-only mode of an ingester which is not in the ring is kept
+// since it has been updated.
+const readOnlyCleanupGracePeriod = 10 * time.Minute
+
+// readOnlyStatus is the response of the read-only endpoint.
+type readOnlyStatus struct {
+	ReadOnly bool `json:"read_only"`
+
+	// Since is the time the read-only mode has been enabled at. Empty if the ingester is not read-only.
+	Since string `json:"since,omitempty"`
+
+	// RingState is the state of the ingester in the ring. A read-only ingester is LEAVING, so that it's
+	// skipped by the writes while it's still queried.
+	RingState string `json:"ring_state"`
+
+	// AllDataShipped is true if the ingester has no in-memory series and all its TSDB blocks
+	// have been shipped to the long-term storage.
+	AllDataShipped bool `json:"all_data_shipped"`
+}
+
+func readOnlyMarkerPath(tsdbDir string) string {
+	return filepath.Join(tsdbDir, readOnlyMarkerFilename)
+}
+
+// isReadOnly returns whether the ingester is in read-only mode.
+func (i *Ingester) isReadOnly() bool {
+	return i.readOnlySince.Load() != 0
+}
+
+// setReadOnly puts the ingester in read-only mode. The read-only mode is stored in the KV store of the
+// ingesters ring, so that distributors keep querying the ingester once it's LEAVING and it's shown on the
+// ring status page, pushes are rejected from now on, and the ingester switches to the LEAVING state in the
+// ring if the lifecycler is running.
+func (i *Ingester) setReadOnly(ctx context.Context, since time.Time) error {
+	if err := readonly.Update(ctx, i.readOnlyKV, i.lifecycler.ID, true, since); err != nil {
+		return errors.Wrap(err, "failed to store the read-only mode in the KV store")
+	}
+
+	i.readOnlySince.CompareAndSwap(0, since.UnixMilli())
+	i.metrics.readOnly.Set(1)
+
+	if i.lifecycler.State() != services.Running {
+		return nil
+	}
+	return i.leaveRingIfReadOnly(ctx)
+}
+
+// leaveRingIfReadOnly switches a read-only ingester from the ACTIVE to the LEAVING state in the ring, so that
+// distributors stop sending writes to it, while they keep querying it. Distributors don't extend the replication
+// set of the series owned by a LEAVING ingester, so these series are written to the other replicas only. The
+// lifecycler doesn't allow a LEAVING ingester to switch back to ACTIVE, so the ingester stays LEAVING until
+// it restarts. The ingesters which aren't ACTIVE yet are switched later, once they've joined the ring.
+func (i *Ingester) leaveRingIfReadOnly(ctx context.Context) error {
+	if !i.isReadOnly() || i.lifecycler.GetState() != ring.ACTIVE {
+		return nil
+	}
+	return errors.Wrap(i.lifecycler.ChangeState(ctx, ring.LEAVING), "failed to switch to the LEAVING state")
+}
+
+// unsetReadOnly disables the read-only mode, and returns false if the ingester has already left the ring.
+// Pushes are accepted again before the read-only mode is removed from the KV store. An ingester which is
+// LEAVING stays read-only until it restarts without the read-only marker.
+func (i *Ingester) unsetReadOnly(ctx context.Context) (bool, error) {
+	if i.lifecycler.GetState() == ring.LEAVING {
+		return false, nil
+	}
+
+	i.readOnlySince.Store(0)
+	i.metrics.readOnly.Set(0)
+
+	return true, errors.Wrap(readonly.Update(ctx, i.readOnlyKV, i.lifecycler.ID, false, time.Time{}), "failed to remove the read-only mode from the KV store")
+}
+
+// removeReadOnly removes the read-only mode of the ingester from the KV store, if any. It's called when the
+// ingester starts without the read-only marker, after its read-only mode has been disabled while it was LEAVING.
+func (i *Ingester) removeReadOnly(ctx context.Context) error {
+	value, err := i.readOnlyKV.Get(ctx, readonly.Key)
+	if err != nil {
+		return err
+	}
+
+	desc, _ := value.(*readonly.Desc)
+	if _, readOnly := desc.IsReadOnly(i.lifecycler.ID); !readOnly {
+		return nil
+	}
+	return readonly.Update(ctx, i.readOnlyKV, i.lifecycler.ID, false, time.Time{})
+}
+
+// removeLeftIngestersReadOnly removes from the KV store the read-only mode of the ingesters which left the ring
+// without removing it, e.g. because they've been forgotten, so that it doesn't apply to a new ingester with the
+// same ID. The entries updated within the grace period are kept, because a restarting ingester re-applies its
+// read-only mode before joining the ring.
+func (i *Ingester) removeLeftIngestersReadOnly(ctx context.Context) {
+	value, err := i.lifecycler.KVStore.Get(ctx, IngesterRingKey)
+	if err != nil {
+		level.Warn(i.logger).Log("msg", "failed to read the ingesters ring from the KV store", "err", err)
+		return
+	}
+
+	// Don't remove anything until the ring is known.
+	desc, ok := value.(*ring.Desc)
+	if !ok || desc == nil || len(desc.Ingesters) == 0 {
+		return
+	}
+
+	inRing := func(instanceID string) bool {
+		_, ok := desc.Ingesters[instanceID]
+		return ok
+	}
+	if err := readonly.RemoveLeftInstances(ctx, i.readOnlyKV, inRing, time.Now().Add(-readOnlyCleanupGracePeriod)); err != nil {
+		level.Warn(i.logger).Log("msg", "failed to remove the read-only mode of the ingesters which left the ring from the KV store", "err", err)
+	}
+}
+
+// allDataShipped returns whether the ingester has no in-memory series and all its TSDB blocks have
+// been shipped to the long-term storage.
+func (i *Ingester) allDataShipped() bool {
+	if !i.cfg.BlocksStorageConfig.TSDB.IsBlocksShippingEnabled() {
+		return false
+	}
+
+	for _, userID := range i.getTSDBUsers() {
+		db := i.getTSDB(userID)
+		if db == nil {
+			continue
+		}
+		if db.Head().NumSeries() > 0 || db.getOldestUnshippedBlockTime() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// readReadOnlyMarker returns the time the read-only mode has been requested at, if the read-only
+// marker exists.
+func readReadOnlyMarker(path string) (time.Time, bool, error) {
+	exists, err := shutdownmarker.Exists(path)
+	if err != nil || !exists {
+		return time.Time{}, false, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	since, err := time.Parse(time.RFC3339, strings.TrimSpace(string(content)))
+	if err != nil {
+		// The marker is still valid, we just don't know when it has been created.
+		return time.Now(), true, nil
+	}
+	return since, true, nil
+}
+
+// ReadOnlyHandler inspects or changes the read-only mode of the ingester. A read-only ingester:
+//   - Rejects writes, and switches to the LEAVING state in the ring, so that distributors stop sending writes
+//     to it. The series it owns are written to their other replicas only.
+//   - Keeps serving queries for the data it holds, so that it can be queried until its data has been
+//     shipped to the long-term storage and is older than the queriers' -querier.query-ingesters-within.
+//
+// When the read-only mode is enabled, the ingester compacts its TSDB head and ships the resulting
+// blocks. It also creates a file on disk which is used to re-apply the read-only mode, before joining
+// the ring, if the ingester restarts.
+//
+// * `GET` shows the read-only status of the ingester
+// * `POST` enables the read-only mode
+// * `DELETE` disables the read-only mode. Once the ingester is LEAVING, it can't switch back to ACTIVE: the
+// read-only marker is removed, and the read-only mode is disabled only when the ingester restarts. In this
+// case, the response status is 202 Accepted instead of 204 No Content.
+func (i *Ingester) ReadOnlyHandler(w http.ResponseWriter, r *http.Request) {
+	// Don't allow callers to change the read-only mode while we're in the middle
+	// of starting or shutting down.
+	if i.State() != services.Running {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	markerPath := readOnlyMarkerPath(i.cfg.BlocksStorageConfig.TSDB.Dir)
+	switch r.Method {
+	case http.MethodGet:
+		status := readOnlyStatus{
+			ReadOnly:       i.isReadOnly(),
+			RingState:      i.lifecycler.GetState().String(),
+			AllDataShipped: i.allDataShipped(),
+		}
+		if since := i.readOnlySince.Load(); since != 0 {
+			status.Since = time.UnixMilli(since).UTC().Format(time.RFC3339)
+		}
+		util.WriteJSONResponse(w, status)
+	case http.MethodPost:
+		if err := shutdownmarker.Create(markerPath); err != nil {
+			level.Error(i.logger).Log("msg", "unable to create read-only marker file", "path", markerPath, "err", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		wasReadOnly := i.isReadOnly()
+		if err := i.setReadOnly(r.Context(), time.Now()); err != nil {
+			level.Error(i.logger).Log("msg", "unable to enable the read-only mode", "err", err)
+			if !wasReadOnly {
+				if err := shutdownmarker.Remove(markerPath); err != nil {
+					level.Warn(i.logger).Log("msg", "unable to remove read-only marker file", "path", markerPath, "err", err)
+				}
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		level.Info(i.logger).Log("msg", "enabled the read-only mode and created read-only marker file", "path", markerPath)
+
+		// Flush the in-memory series to the long-term storage, so that the ingester can be
+		// scaled down as soon as possible.
+		if !wasReadOnly {
+			go i.compactAndShipBlocks(util.NewAllowedTenants(nil, nil))
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if err := shutdownmarker.Remove(markerPath); err != nil {
+			level.Error(i.logger).Log("msg", "unable to remove read-only marker file", "path", markerPath, "err", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		disabled, err := i.unsetReadOnly(r.Context())
+		if err != nil {
+			level.Error(i.logger).Log("msg", "unable to disable the read-only mode", "err", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !disabled {
+			level.Info(i.logger).Log("msg", "removed read-only marker file, the read-only mode will be disabled when the ingester restarts", "path", markerPath)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		level.Info(i.logger).Log("msg", "disabled the read-only mode and removed read-only marker file", "path", markerPath)
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
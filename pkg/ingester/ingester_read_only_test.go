@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/mimir/pkg/ingester/readonly"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/shutdownmarker"
+)
+
+func TestIngester_ReadOnlyHandler(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.BlocksStorageConfig.TSDB.ShipConcurrency = 1
+	cfg.BlocksStorageConfig.TSDB.ShipInterval = 1 * time.Minute // Long enough to not be reached during the test.
+
+	reg := prometheus.NewPedanticRegistry()
+	i, err := prepareIngesterWithBlocksStorage(t, cfg, reg)
+	require.NoError(t, err)
+	readOnlyKV := mockReadOnlyKV(t, i)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), i)
+	})
+
+	test.Poll(t, 1*time.Second, 1, func() interface{} {
+		return i.lifecycler.HealthyInstancesCount()
+	})
+
+	getStatus := func() readOnlyStatus {
+		res := httptest.NewRecorder()
+		i.ReadOnlyHandler(res, httptest.NewRequest(http.MethodGet, "/ingester/read-only", nil))
+		require.Equal(t, http.StatusOK, res.Code)
+
+		var s readOnlyStatus
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &s))
+		return s
+	}
+
+	pushSingleSampleWithMetadata(t, i)
+
+	st := getStatus()
+	assert.False(t, st.ReadOnly)
+	assert.Empty(t, st.Since)
+	assert.Equal(t, ring.ACTIVE.String(), st.RingState)
+	assert.False(t, st.AllDataShipped)
+
+	// Enable the read-only mode.
+	res := httptest.NewRecorder()
+	i.ReadOnlyHandler(res, httptest.NewRequest(http.MethodPost, "/ingester/read-only", nil))
+	require.Equal(t, http.StatusNoContent, res.Code)
+
+	exists, err := shutdownmarker.Exists(readOnlyMarkerPath(i.cfg.BlocksStorageConfig.TSDB.Dir))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, bytes.NewBufferString(`
+		# HELP cortex_ingester_read_only If the ingester is in read-only mode, requested via endpoint or marker file.
+		# TYPE cortex_ingester_read_only gauge
+		cortex_ingester_read_only 1
+	`), "cortex_ingester_read_only"))
+
+	// The ingester switches to the LEAVING state in the ring, so that distributors stop sending writes
+	// to it while it's still queried, and writes are rejected.
+	assert.Equal(t, ring.LEAVING, i.lifecycler.GetState())
+	assert.True(t, isReadOnlyInKV(t, readOnlyKV, i.lifecycler.ID))
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	req, _, _, _ := mockWriteRequest(t, labels.FromStrings(labels.MetricName, "test"), 1, util.TimeToMillis(time.Now()))
+	_, err = i.Push(ctx, req)
+	require.ErrorIs(t, err, errReadOnly)
+	checkIngesterError(t, err, unavailable, false)
+	stat, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Unavailable, stat.Code())
+
+	// The in-memory series are eventually compacted and shipped.
+	test.Poll(t, 5*time.Second, true, func() interface{} {
+		return getStatus().AllDataShipped
+	})
+
+	st = getStatus()
+	assert.True(t, st.ReadOnly)
+	assert.NotEmpty(t, st.Since)
+	assert.Equal(t, ring.LEAVING.String(), st.RingState)
+
+	// The ingester keeps serving queries for the shipped data.
+	db := i.getTSDB(userID)
+	require.NotNil(t, db)
+	require.Len(t, db.Blocks(), 1)
+
+	// Disable the read-only mode. The ingester can't switch back from LEAVING to ACTIVE, so the
+	// read-only mode is only disabled once the ingester restarts without the marker.
+	res = httptest.NewRecorder()
+	i.ReadOnlyHandler(res, httptest.NewRequest(http.MethodDelete, "/ingester/read-only", nil))
+	require.Equal(t, http.StatusAccepted, res.Code)
+
+	exists, err = shutdownmarker.Exists(readOnlyMarkerPath(i.cfg.BlocksStorageConfig.TSDB.Dir))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	assert.True(t, getStatus().ReadOnly)
+	assert.True(t, isReadOnlyInKV(t, readOnlyKV, i.lifecycler.ID))
+	require.ErrorIs(t, i.StartPushRequest(), errReadOnly)
+
+	// If the ingester isn't running, requests to the read-only endpoint should fail.
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), i))
+
+	res = httptest.NewRecorder()
+	i.ReadOnlyHandler(res, httptest.NewRequest(http.MethodPost, "/ingester/read-only", nil))
+	require.Equal(t, http.StatusServiceUnavailable, res.Code)
+}
+
+func TestIngester_ReadOnlyMarkerOnStartup(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.RateUpdatePeriod = 100 * time.Millisecond
+
+	dataDir := t.TempDir()
+	require.NoError(t, shutdownmarker.Create(readOnlyMarkerPath(dataDir)))
+
+	reg := prometheus.NewPedanticRegistry()
+	i, err := prepareIngesterWithBlocksStorageAndLimits(t, cfg, defaultLimitsTestConfig(), dataDir, reg)
+	require.NoError(t, err)
+	readOnlyKV := mockReadOnlyKV(t, i)
+
+	// The read-only mode must be stored in the KV store before the ingester joins the ring.
+	readOnlyBeforeJoining := make(chan bool, 1)
+	i.lifecycler.AddListener(services.NewListener(func() {
+		value, err := readOnlyKV.Get(context.Background(), readonly.Key)
+		desc, _ := value.(*readonly.Desc)
+		_, readOnly := desc.IsReadOnly(i.lifecycler.ID)
+		readOnlyBeforeJoining <- err == nil && readOnly
+	}, nil, nil, nil, nil))
+
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	require.True(t, <-readOnlyBeforeJoining)
+
+	// The read-only mode is re-applied as soon as the ingester is running.
+	require.True(t, i.isReadOnly())
+	require.ErrorIs(t, i.StartPushRequest(), errReadOnly)
+
+	// The ingester leaves the ring again once it has joined it.
+	test.Poll(t, 5*time.Second, ring.LEAVING, func() interface{} {
+		return i.lifecycler.GetState()
+	})
+
+	// The read-only mode is removed from the KV store once the ingester has left the ring,
+	// so that it doesn't apply to a new ingester with the same ID.
+	require.True(t, i.lifecycler.ShouldUnregisterOnShutdown())
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), i))
+	require.False(t, isReadOnlyInKV(t, readOnlyKV, i.lifecycler.ID))
+}
+
+func TestIngester_ReadOnlyRemovedOnStartupWithoutMarker(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+
+	i, err := prepareIngesterWithBlocksStorage(t, cfg, prometheus.NewPedanticRegistry())
+	require.NoError(t, err)
+	readOnlyKV := mockReadOnlyKV(t, i)
+
+	// The read-only mode has been disabled while the ingester was LEAVING, so it's still in the KV store.
+	require.NoError(t, readonly.Update(context.Background(), readOnlyKV, i.lifecycler.ID, true, time.Now()))
+
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), i)
+	})
+
+	assert.False(t, i.isReadOnly())
+	assert.False(t, isReadOnlyInKV(t, readOnlyKV, i.lifecycler.ID))
+	test.Poll(t, 5*time.Second, ring.ACTIVE, func() interface{} {
+		return i.lifecycler.GetState()
+	})
+}
+
+func TestIngester_RemoveLeftIngestersReadOnly(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+
+	i, err := prepareIngesterWithBlocksStorage(t, cfg, prometheus.NewPedanticRegistry())
+	require.NoError(t, err)
+	readOnlyKV := mockReadOnlyKV(t, i)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), i)
+	})
+
+	test.Poll(t, 1*time.Second, 1, func() interface{} {
+		return i.lifecycler.HealthyInstancesCount()
+	})
+
+	old := time.Now().Add(-2 * readOnlyCleanupGracePeriod).UnixMilli()
+	require.NoError(t, readOnlyKV.CAS(context.Background(), readonly.Key, func(interface{}) (interface{}, bool, error) {
+		return &readonly.Desc{Instances: map[string]readonly.InstanceDesc{
+			i.lifecycler.ID:     {ReadOnly: true, ReadOnlySince: old, UpdatedAt: old},
+			"left-ingester":     {ReadOnly: true, ReadOnlySince: old, UpdatedAt: old},
+			"starting-ingester": {ReadOnly: true, ReadOnlySince: old, UpdatedAt: time.Now().UnixMilli()},
+		}}, true, nil
+	}))
+
+	i.removeLeftIngestersReadOnly(context.Background())
+
+	// Only the read-only mode of the ingester which left the ring more than the grace period ago is removed.
+	assert.True(t, isReadOnlyInKV(t, readOnlyKV, i.lifecycler.ID))
+	assert.False(t, isReadOnlyInKV(t, readOnlyKV, "left-ingester"))
+	assert.True(t, isReadOnlyInKV(t, readOnlyKV, "starting-ingester"))
+}
+
+func mockReadOnlyKV(t *testing.T, i *Ingester) kv.Client {
+	client, closer := consul.NewInMemoryClient(readonly.GetCodec(), log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+
+	i.readOnlyKV = client
+	return client
+}
+
+func isReadOnlyInKV(t *testing.T, client kv.Client, instanceID string) bool {
+	value, err := client.Get(context.Background(), readonly.Key)
+	require.NoError(t, err)
+
+	desc, _ := value.(*readonly.Desc)
+	_, readOnly := desc.IsReadOnly(instanceID)
+	return readOnly
+}
@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestIngester_SeriesEviction(t *testing.T) {
+	tests := map[string]struct {
+		dryRun bool
+	}{
+		"enabled": {dryRun: false},
+		"dry-run": {dryRun: true},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var (
+				ctx         = context.Background()
+				ctxWithUser = user.InjectOrgID(ctx, userID)
+				now         = time.Now()
+				idleNow     = now.Add(15 * time.Minute)
+			)
+
+			cfg := defaultIngesterTestConfig(t)
+			cfg.ActiveSeriesMetrics.Enabled = true
+			cfg.ActiveSeriesMetrics.IdleTimeout = 20 * time.Minute
+			cfg.BlocksStorageConfig.TSDB.HeadCompactionInterval = time.Hour // Do not trigger it during the test, so that we trigger it manually.
+			cfg.IngesterRing.ReplicationFactor = 1                          // Ensure the local series limit is equal to the global one.
+
+			limits := defaultLimitsTestConfig()
+			limits.MaxGlobalSeriesPerUser = 2
+			limits.SeriesEvictionIdleTimeout = model.Duration(10 * time.Minute)
+			limits.SeriesEvictionDryRun = testData.dryRun
+			limits.CreationGracePeriod = model.Duration(24 * time.Hour) // This test writes samples in the future.
+
+			ingester, err := prepareIngesterWithBlocksStorageAndLimits(t, cfg, limits, "", nil)
+			require.NoError(t, err)
+			require.NoError(t, services.StartAndAwaitRunning(ctx, ingester))
+			t.Cleanup(func() {
+				require.NoError(t, services.StopAndAwaitTerminated(ctx, ingester))
+			})
+
+			// Wait until it's ACTIVE and healthy, so that the limits are computed on the ring.
+			test.Poll(t, time.Second, ring.ACTIVE, func() interface{} {
+				return ingester.lifecycler.GetState()
+			})
+			test.Poll(t, time.Second, 1, func() interface{} {
+				return ingester.lifecycler.HealthyInstancesCount()
+			})
+
+			// Reach the series limit.
+			require.NoError(t, pushSeriesToIngester(ctxWithUser, t, ingester, []series{
+				{labels.FromStrings(labels.MetricName, "idle_1"), 1, now.UnixMilli()},
+				{labels.FromStrings(labels.MetricName, "idle_2"), 1, now.UnixMilli()},
+			}))
+
+			// New series are rejected because there are no idle series yet.
+			ingester.updateActiveSeries(now)
+			err = pushSeriesToIngester(ctxWithUser, t, ingester, []series{{labels.FromStrings(labels.MetricName, "new_1"), 1, idleNow.UnixMilli()}})
+			require.ErrorContains(t, err, "per-user series limit")
+
+			db := ingester.getTSDB(userID)
+			require.Equal(t, int64(0), db.evictableSeries.Load())
+
+			// Once the series are idle, they make room for new series.
+			ingester.updateActiveSeries(idleNow)
+			require.Equal(t, int64(2), db.evictableSeries.Load())
+			assert.Equal(t, float64(2), testutil.ToFloat64(ingester.metrics.evictableSeries.WithLabelValues(userID)))
+
+			err = pushSeriesToIngester(ctxWithUser, t, ingester, []series{{labels.FromStrings(labels.MetricName, "new_1"), 1, idleNow.UnixMilli()}})
+			if testData.dryRun {
+				require.ErrorContains(t, err, "per-user series limit")
+				assert.Equal(t, float64(1), testutil.ToFloat64(ingester.metrics.seriesEvictionDryRun.WithLabelValues(userID)))
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, float64(0), testutil.ToFloat64(ingester.metrics.seriesEvictionDryRun.WithLabelValues(userID)))
+			}
+
+			// The idle series are evicted from memory, unless running in dry-run mode.
+			ingester.compactBlocksToEvictIdleSeries(ctx, idleNow)
+
+			userBlocksDir := filepath.Join(ingester.cfg.BlocksStorageConfig.TSDB.Dir, userID)
+			if testData.dryRun {
+				require.Len(t, listBlocksInDir(t, userBlocksDir), 0)
+				assert.Equal(t, uint64(2), db.Head().NumSeries())
+				assert.Equal(t, int64(2), db.evictableSeries.Load())
+				assert.Equal(t, float64(0), testutil.ToFloat64(ingester.metrics.evictedSeries.WithLabelValues(userID)))
+			} else {
+				require.Len(t, listBlocksInDir(t, userBlocksDir), 1)
+				assert.Equal(t, uint64(1), db.Head().NumSeries())
+				assert.Equal(t, int64(0), db.evictableSeries.Load())
+				assert.Equal(t, float64(2), testutil.ToFloat64(ingester.metrics.evictedSeries.WithLabelValues(userID)))
+			}
+		})
+	}
+}
+
+func TestIngester_updateEvictableSeries_ShouldIgnoreSeriesLoadedFromDiskUntilIdleTimeout(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		ctxWithUser = user.InjectOrgID(ctx, userID)
+		now         = time.Now()
+	)
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.ActiveSeriesMetrics.Enabled = true
+
+	limits := defaultLimitsTestConfig()
+	limits.SeriesEvictionIdleTimeout = model.Duration(10 * time.Minute)
+
+	ingester, err := prepareIngesterWithBlocksStorageAndLimits(t, cfg, limits, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(ctx, ingester))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(ctx, ingester))
+	})
+
+	require.NoError(t, pushSeriesToIngester(ctxWithUser, t, ingester, []series{
+		{labels.FromStrings(labels.MetricName, "series_1"), 1, now.UnixMilli()},
+	}))
+
+	// Simulate series which have been loaded from disk and not tracked by the active series.
+	db := ingester.getTSDB(userID)
+	db.activeSeries.Purge(now.Add(time.Hour))
+
+	ingester.updateEvictableSeries(userID, db, now.Add(5*time.Minute), true)
+	assert.Equal(t, int64(0), db.evictableSeries.Load())
+
+	ingester.updateEvictableSeries(userID, db, now.Add(15*time.Minute), true)
+	assert.Equal(t, int64(1), db.evictableSeries.Load())
+
+	// Invalid active series can't be used to find idle series.
+	ingester.updateEvictableSeries(userID, db, now.Add(15*time.Minute), false)
+	assert.Equal(t, int64(0), db.evictableSeries.Load())
+}
+
+func TestIngester_SeriesEviction_ShouldChargeAdmittedSeriesAgainstIdleSeries(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		ctxWithUser = user.InjectOrgID(ctx, userID)
+		now         = time.Now()
+		idleNow     = now.Add(15 * time.Minute)
+	)
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.ActiveSeriesMetrics.Enabled = true
+	cfg.ActiveSeriesMetrics.IdleTimeout = 20 * time.Minute
+	cfg.BlocksStorageConfig.TSDB.HeadCompactionInterval = time.Hour // Do not trigger it during the test, so that we trigger it manually.
+	cfg.IngesterRing.ReplicationFactor = 1                          // Ensure the local series limit is equal to the global one.
+
+	limits := defaultLimitsTestConfig()
+	limits.MaxGlobalSeriesPerUser = 2
+	limits.SeriesEvictionIdleTimeout = model.Duration(10 * time.Minute)
+	limits.CreationGracePeriod = model.Duration(24 * time.Hour) // This test writes samples in the future.
+
+	ingester, err := prepareIngesterWithBlocksStorageAndLimits(t, cfg, limits, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(ctx, ingester))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(ctx, ingester))
+	})
+	test.Poll(t, time.Second, 1, func() interface{} {
+		return ingester.lifecycler.HealthyInstancesCount()
+	})
+
+	require.NoError(t, pushSeriesToIngester(ctxWithUser, t, ingester, []series{
+		{labels.FromStrings(labels.MetricName, "idle_1"), 1, now.UnixMilli()},
+		{labels.FromStrings(labels.MetricName, "idle_2"), 1, now.UnixMilli()},
+	}))
+	ingester.updateActiveSeries(idleNow)
+
+	db := ingester.getTSDB(userID)
+	require.Equal(t, int64(2), db.evictableSeries.Load())
+
+	// Concurrent admissions can't admit more series than the idle ones.
+	admitted := atomic.NewInt64(0)
+	wg := sync.WaitGroup{}
+	for n := 0; n < 10; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if db.admitSeriesByEviction() {
+				admitted.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(2), admitted.Load())
+
+	// The admitted series stay charged when the idle series are updated, until they're evicted.
+	ingester.updateActiveSeries(idleNow)
+	assert.False(t, db.admitSeriesByEviction())
+
+	ingester.compactBlocksToEvictIdleSeries(ctx, idleNow)
+	assert.Equal(t, uint64(0), db.Head().NumSeries())
+	assert.Equal(t, int64(0), db.seriesAdmittedByEviction.Load())
+
+	// The idle series are evicted at most once per idle timeout.
+	require.NoError(t, pushSeriesToIngester(ctxWithUser, t, ingester, []series{
+		{labels.FromStrings(labels.MetricName, "idle_3"), 1, idleNow.UnixMilli()},
+		{labels.FromStrings(labels.MetricName, "idle_4"), 1, idleNow.UnixMilli()},
+	}))
+	secondIdleNow := idleNow.Add(11 * time.Minute)
+	ingester.updateActiveSeries(secondIdleNow)
+	require.NoError(t, pushSeriesToIngester(ctxWithUser, t, ingester, []series{{labels.FromStrings(labels.MetricName, "new_1"), 1, secondIdleNow.UnixMilli()}}))
+
+	ingester.compactBlocksToEvictIdleSeries(ctx, idleNow.Add(5*time.Minute))
+	assert.Equal(t, uint64(3), db.Head().NumSeries())
+	assert.True(t, db.seriesEvictionRequested.Load())
+
+	ingester.compactBlocksToEvictIdleSeries(ctx, secondIdleNow)
+	assert.Equal(t, uint64(1), db.Head().NumSeries())
+	assert.False(t, db.seriesEvictionRequested.Load())
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/streamaggr"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestIngester_Push_StreamingAggregationInput(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		ctxWithUser = user.InjectOrgID(ctx, userID)
+		now         = time.Now()
+		sampleTime  = now.Add(-30 * time.Second)
+	)
+
+	limits := defaultLimitsTestConfig()
+	limits.StreamingAggregationRules = []*streamaggr.Rule{
+		{Match: `requests_total`, By: []string{"job"}, Output: streamaggr.OutputSum, Interval: model.Duration(time.Minute), Suffix: ":sum"},
+	}
+
+	ingester, err := prepareIngesterWithBlocksStorageAndLimits(t, defaultIngesterTestConfig(t), limits, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(ctx, ingester))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(ctx, ingester))
+	})
+
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	var ruleKey string
+	series := labels.FromStrings(model.MetricNameLabel, "requests_total", "job", "api", "pod", "1")
+	streamaggr.NewRules(overrides).Route(userID, series, func(key string, _ labels.Labels) { ruleKey = key })
+	require.NotEmpty(t, ruleKey)
+
+	req, _, _, _ := mockWriteRequest(t, series, 10, sampleTime.UnixMilli())
+	req.StreamingAggregationRule = ruleKey
+	_, err = ingester.Push(ctxWithUser, req)
+	require.NoError(t, err)
+
+	req, _, _, _ = mockWriteRequest(t, labels.FromStrings(model.MetricNameLabel, "requests_total", "job", "api", "pod", "2"), 20, sampleTime.UnixMilli())
+	req.StreamingAggregationRule = ruleKey
+	_, err = ingester.Push(ctxWithUser, req)
+	require.NoError(t, err)
+
+	// The input series are not ingested.
+	res, _, err := runTestQuery(ctxWithUser, t, ingester, labels.MatchEqual, model.MetricNameLabel, "requests_total")
+	require.NoError(t, err)
+	assert.Empty(t, res)
+
+	// The output series are ingested once their interval is flushed, even if the ingester has been made
+	// read-only since the input series have been received.
+	require.NoError(t, ingester.setReadOnly(ctx, now))
+	ingester.streamAggregator.Flush(ctx, now.Add(2*time.Minute))
+	assert.Zero(t, ingester.inflightPushRequests.Load())
+
+	res, _, err = runTestQuery(ctxWithUser, t, ingester, labels.MatchEqual, model.MetricNameLabel, "requests_total:sum")
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, model.Metric{model.MetricNameLabel: "requests_total:sum", "job": "api"}, res[0].Metric)
+
+	intervalEnd := sampleTime.Truncate(time.Minute).Add(time.Minute)
+	assert.Equal(t, []model.SamplePair{{Timestamp: model.Time(intervalEnd.UnixMilli()), Value: 30}}, res[0].Values)
+}
@@ -828,6 +828,75 @@ func TestIngester_Push(t *testing.T) {
 				cortex_ingester_tsdb_head_max_timestamp_seconds ` + fmt.Sprintf("%g", float64(now.UnixMilli())/1000) + `
 			`,
 		},
+		"should soft fail on native histograms with custom buckets": {
+			nativeHistograms: true,
+			reqs: []*mimirpb.WriteRequest{
+				{
+					Timeseries: []mimirpb.PreallocTimeseries{
+						{
+							TimeSeries: &mimirpb.TimeSeries{
+								Labels: metricLabelAdapters,
+								Histograms: []mimirpb.Histogram{
+									{
+										Count:          &mimirpb.Histogram_CountInt{CountInt: 3},
+										Sum:            5,
+										Schema:         mimirpb.CustomBucketsSchema,
+										PositiveSpans:  []mimirpb.BucketSpan{{Offset: 0, Length: 2}},
+										PositiveDeltas: []int64{1, 1},
+										CustomValues:   []float64{1, 10},
+										Timestamp:      now.UnixMilli() - 1,
+									},
+									mimirpb.FromHistogramToHistogramProto(now.UnixMilli(), util_test.GenerateTestHistogram(0))},
+							},
+						},
+					},
+				},
+			},
+			expectedErr: newErrorWithHTTPStatus(wrapOrAnnotateWithUser(newNativeHistogramCustomBucketsError(model.Time(now.UnixMilli()-1), metricLabelAdapters), userID), http.StatusBadRequest),
+			expectedIngested: model.Matrix{
+				&model.SampleStream{Metric: metricLabelSet, Histograms: []model.SampleHistogramPair{
+					{Timestamp: model.Time(now.UnixMilli()), Histogram: mimirpb.FromHistogramToPromHistogram(util_test.GenerateTestGaugeHistogram(0))},
+				}},
+			},
+			expectedMetrics: `
+				# HELP cortex_ingester_ingested_samples_total The total number of samples ingested per user.
+				# TYPE cortex_ingester_ingested_samples_total counter
+				cortex_ingester_ingested_samples_total{user="test"} 1
+				# HELP cortex_ingester_ingested_samples_failures_total The total number of samples that errored on ingestion per user.
+				# TYPE cortex_ingester_ingested_samples_failures_total counter
+				cortex_ingester_ingested_samples_failures_total{user="test"} 1
+				# HELP cortex_ingester_memory_users The current number of users in memory.
+				# TYPE cortex_ingester_memory_users gauge
+				cortex_ingester_memory_users 1
+				# HELP cortex_ingester_memory_series The current number of series in memory.
+				# TYPE cortex_ingester_memory_series gauge
+				cortex_ingester_memory_series 1
+				# HELP cortex_ingester_memory_series_created_total The total number of series that were created per user.
+				# TYPE cortex_ingester_memory_series_created_total counter
+				cortex_ingester_memory_series_created_total{user="test"} 1
+				# HELP cortex_ingester_memory_series_removed_total The total number of series that were removed per user.
+				# TYPE cortex_ingester_memory_series_removed_total counter
+				cortex_ingester_memory_series_removed_total{user="test"} 0
+				# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+				# TYPE cortex_discarded_samples_total counter
+				cortex_discarded_samples_total{group="",reason="native-histogram-custom-buckets",user="test"} 1
+				# HELP cortex_ingester_active_series Number of currently active series per user.
+				# TYPE cortex_ingester_active_series gauge
+				cortex_ingester_active_series{user="test"} 1
+				# HELP cortex_ingester_active_native_histogram_buckets Number of currently active native histogram buckets per user.
+				# TYPE cortex_ingester_active_native_histogram_buckets gauge
+				cortex_ingester_active_native_histogram_buckets{user="test"} 8
+				# HELP cortex_ingester_active_native_histogram_series Number of currently active native histogram series per user.
+				# TYPE cortex_ingester_active_native_histogram_series gauge
+				cortex_ingester_active_native_histogram_series{user="test"} 1
+				# HELP cortex_ingester_tsdb_head_min_timestamp_seconds Minimum timestamp of the head block across all tenants.
+				# TYPE cortex_ingester_tsdb_head_min_timestamp_seconds gauge
+				cortex_ingester_tsdb_head_min_timestamp_seconds ` + fmt.Sprintf("%g", float64(now.UnixMilli())/1000) + `
+				# HELP cortex_ingester_tsdb_head_max_timestamp_seconds Maximum timestamp of the head block across all tenants.
+				# TYPE cortex_ingester_tsdb_head_max_timestamp_seconds gauge
+				cortex_ingester_tsdb_head_max_timestamp_seconds ` + fmt.Sprintf("%g", float64(now.UnixMilli())/1000) + `
+			`,
+		},
 		"should soft fail on some exemplars with timestamp too far in future in a write request": {
 			maxExemplars: 1,
 			reqs: []*mimirpb.WriteRequest{
@@ -1310,6 +1379,75 @@ func TestIngester_Push(t *testing.T) {
 	}
 }
 
+func TestIngester_Push_NonElectedReplicaSamples(t *testing.T) {
+	pushRequest := func(windowMs int64, ts int64, names ...string) *mimirpb.WriteRequest {
+		req := &mimirpb.WriteRequest{Source: mimirpb.API, NonElectedReplicaWindowMs: windowMs}
+		for _, name := range names {
+			req.Timeseries = append(req.Timeseries, mimirpb.PreallocTimeseries{TimeSeries: &mimirpb.TimeSeries{
+				Labels:  mimirpb.FromLabelsToLabelAdapters(labels.FromStrings(model.MetricNameLabel, name)),
+				Samples: []mimirpb.Sample{{TimestampMs: ts, Value: float64(ts)}},
+			}})
+		}
+		return req
+	}
+
+	registry := prometheus.NewRegistry()
+	i, err := prepareIngesterWithBlocksStorage(t, defaultIngesterTestConfig(t), registry)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), i)
+	})
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	window := (5 * time.Minute).Milliseconds()
+
+	// The elected replica sends both series.
+	_, err = i.Push(ctx, pushRequest(0, 1000, "series_a", "series_b"))
+	require.NoError(t, err)
+
+	// Right after the active series tracking has started, e.g. after a restart, the series which aren't tracked
+	// may have been recently updated, so the samples of the non-elected replica are deduplicated.
+	db := i.getTSDB(userID)
+	db.activeSeries.ReloadMatchers(activeseries.NewMatchers(db.activeSeries.CurrentConfig()), time.Now())
+	_, err = i.Push(ctx, pushRequest(0, 2000, "series_a"))
+	require.NoError(t, err)
+	_, err = i.Push(ctx, pushRequest(window, 3000, "series_a", "series_b", "series_c"))
+	require.NoError(t, err)
+
+	// Once the series have been tracked for the idle timeout, the elected replica stopped sending series_b.
+	db.activeSeries.ReloadMatchers(activeseries.NewMatchers(db.activeSeries.CurrentConfig()), time.Now().Add(-i.cfg.ActiveSeriesMetrics.IdleTimeout))
+	_, err = i.Push(ctx, pushRequest(0, 4000, "series_a"))
+	require.NoError(t, err)
+
+	// The samples of the non-elected replica are only appended to the existing series which are stale.
+	_, err = i.Push(ctx, pushRequest(window, 5000, "series_a", "series_b", "series_c"))
+	require.NoError(t, err)
+
+	for name, expected := range map[string][]model.SamplePair{
+		"series_a": {{Timestamp: 1000, Value: 1000}, {Timestamp: 2000, Value: 2000}, {Timestamp: 4000, Value: 4000}},
+		"series_b": {{Timestamp: 1000, Value: 1000}, {Timestamp: 5000, Value: 5000}},
+	} {
+		res, _, err := runTestQuery(ctx, t, i, labels.MatchEqual, model.MetricNameLabel, name)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, expected, res[0].Values, name)
+	}
+	res, _, err := runTestQuery(ctx, t, i, labels.MatchEqual, model.MetricNameLabel, "series_c")
+	require.NoError(t, err)
+	assert.Empty(t, res)
+
+	// The samples of the non-elected replica don't make the series active.
+	total, _, _ := i.getTSDB(userID).activeSeries.Active()
+	assert.Equal(t, 1, total)
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+		# HELP cortex_ingester_non_elected_replica_deduped_samples_total The total number of samples received from a non-elected HA replica and deduplicated because their series has been recently updated, or doesn't exist.
+		# TYPE cortex_ingester_non_elected_replica_deduped_samples_total counter
+		cortex_ingester_non_elected_replica_deduped_samples_total{user="1"} 5
+	`), "cortex_ingester_non_elected_replica_deduped_samples_total"))
+}
+
 func TestIngester_Push_ShouldCorrectlyTrackMetricsInMultiTenantScenario(t *testing.T) {
 	metricLabelAdapters := [][]mimirpb.LabelAdapter{{{Name: labels.MetricName, Value: "test"}}}
 	metricLabelAdaptersHist := [][]mimirpb.LabelAdapter{{{Name: labels.MetricName, Value: "test_histogram"}}}
@@ -5804,6 +5942,31 @@ func TestIngester_PushInstanceLimits(t *testing.T) {
 			expectedErr: errMaxInMemorySeriesReached,
 		},
 
+		"should fail creating series once the estimated memory limit is reached": {
+			limits: InstanceLimits{MaxInMemoryTenants: 1, MaxEstimatedMemory: 1},
+
+			reqs: map[string][]*mimirpb.WriteRequest{
+				"test": {
+					mimirpb.ToWriteRequest(
+						[][]mimirpb.LabelAdapter{{{Name: labels.MetricName, Value: "test1"}}},
+						[]mimirpb.Sample{{Value: 1, TimestampMs: 9}},
+						nil,
+						nil,
+						mimirpb.API,
+					),
+
+					mimirpb.ToWriteRequest(
+						[][]mimirpb.LabelAdapter{{{Name: labels.MetricName, Value: "test2"}}}, // another series
+						[]mimirpb.Sample{{Value: 1, TimestampMs: 10}},
+						nil,
+						nil,
+						mimirpb.API,
+					),
+				},
+			},
+			expectedErr: errMaxEstimatedMemoryReached,
+		},
+
 		"should fail creating two users": {
 			limits: InstanceLimits{MaxInMemorySeries: 1, MaxInMemoryTenants: 1},
 
@@ -6141,6 +6304,7 @@ func TestIngester_instanceLimitsMetrics(t *testing.T) {
 	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
 		# HELP cortex_ingester_instance_limits Instance limits used by this ingester.
 		# TYPE cortex_ingester_instance_limits gauge
+		cortex_ingester_instance_limits{limit="max_estimated_memory_bytes"} 0
 		cortex_ingester_instance_limits{limit="max_inflight_push_requests"} 0
 		cortex_ingester_instance_limits{limit="max_ingestion_rate"} 10
 		cortex_ingester_instance_limits{limit="max_series"} 30
@@ -6149,10 +6313,12 @@ func TestIngester_instanceLimitsMetrics(t *testing.T) {
 
 	l.MaxInMemoryTenants = 1000
 	l.MaxInMemorySeries = 2000
+	l.MaxEstimatedMemory = 3000
 
 	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
 		# HELP cortex_ingester_instance_limits Instance limits used by this ingester.
 		# TYPE cortex_ingester_instance_limits gauge
+		cortex_ingester_instance_limits{limit="max_estimated_memory_bytes"} 3000
 		cortex_ingester_instance_limits{limit="max_inflight_push_requests"} 0
 		cortex_ingester_instance_limits{limit="max_ingestion_rate"} 10
 		cortex_ingester_instance_limits{limit="max_series"} 2000
@@ -6253,6 +6419,7 @@ func TestIngester_inflightPushRequests(t *testing.T) {
 	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
 		# HELP cortex_ingester_instance_rejected_requests_total Requests rejected for hitting per-instance limits
 		# TYPE cortex_ingester_instance_rejected_requests_total counter
+		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_estimated_memory"} 0
 		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_inflight_push_requests"} 1
 		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_ingestion_rate"} 0
 		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_series"} 0
@@ -0,0 +1,681 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+)
+
+const (
+	// transferStagingDirName is the name of the directory, stored in the TSDB directory, where the files
+	// received from a leaving ingester are written until the transfer is committed. The name contains a
+	// character not allowed in tenant IDs, so that it can't clash with the TSDB directory of a tenant.
+	transferStagingDirName = "~transfer"
+
+	// transferChunkSize is the max size of the file content sent in a single transfer request.
+	transferChunkSize = 1 << 20
+
+	transferDirectionSent     = "sent"
+	transferDirectionReceived = "received"
+)
+
+var (
+	transferChecksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+	errTransferNoTarget              = errors.New("no PENDING ingester found to transfer the TSDBs to")
+	errTransferWithoutMemorySnapshot = errors.New("the TSDB transfer requires -blocks-storage.tsdb.memory-snapshot-on-shutdown to be enabled")
+	errTransferInvalidMaxAttempts    = errors.New("the maximum number of TSDB transfer attempts must be greater than 0")
+)
+
+// TransferConfig configures the transfer of the TSDBs of a leaving ingester to the ingester taking over its tokens.
+type TransferConfig struct {
+	Enabled     bool          `yaml:"enabled" category:"experimental"`
+	JoinAfter   time.Duration `yaml:"join_after" category:"experimental"`
+	MaxAttempts int           `yaml:"max_attempts" category:"experimental"`
+}
+
+func (cfg *TransferConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "ingester.transfer.enabled", false, "True to transfer the TSDBs of a leaving ingester to a PENDING ingester, which takes over the tokens of the leaving ingester in the ring. The in-memory series are transferred using the format of the memory snapshot taken on shutdown, so -blocks-storage.tsdb.memory-snapshot-on-shutdown must be enabled.")
+	f.DurationVar(&cfg.JoinAfter, "ingester.transfer.join-after", 0, "How long an ingester without tokens waits in the PENDING state for a leaving ingester to transfer its TSDBs, before joining the ring with new tokens. Only applies if the TSDB transfer is enabled. TSDBs can only be transferred to PENDING ingesters, so this must be set to a value greater than 0 for transfers to happen.")
+	f.IntVar(&cfg.MaxAttempts, "ingester.transfer.max-attempts", 5, "Maximum number of attempts to transfer the TSDBs of a leaving ingester. Each attempt resumes the transfer from the files already received. If all attempts fail, the TSDBs are flushed if -blocks-storage.tsdb.flush-blocks-on-shutdown is enabled.")
+}
+
+func (cfg *TransferConfig) Validate(tsdbCfg mimir_tsdb.TSDBConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if !tsdbCfg.MemorySnapshotOnShutdown {
+		return errTransferWithoutMemorySnapshot
+	}
+	if cfg.MaxAttempts < 1 {
+		return errTransferInvalidMaxAttempts
+	}
+	return nil
+}
+
+// TransferOut implements ring.FlushTransferer. It's called while the ingester is leaving the ring, and
+// transfers its TSDBs to a PENDING ingester, which takes over the tokens of this ingester.
+//
+// The TSDBs are closed before the transfer, so that the in-memory series are snapshotted on disk and no file
+// changes during the transfer. Only the WAL segments not covered by the snapshot are transferred. If the
+// transfer fails, the TSDBs are reopened so that they can be flushed. If there's no PENDING ingester, the
+// TSDBs are left open and no transfer is attempted.
+func (i *Ingester) TransferOut(ctx context.Context) error {
+	if !i.cfg.Transfer.Enabled {
+		return ring.ErrTransferDisabled
+	}
+
+	targetID, _, err := i.findTransferTarget(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	level.Info(i.logger).Log("msg", "closing TSDBs before transferring them", "target", targetID)
+	i.closeAllTSDB()
+
+	err = i.transferOut(ctx, targetID)
+	if err == nil {
+		return nil
+	}
+
+	level.Error(i.logger).Log("msg", "failed to transfer TSDBs, reopening them", "err", err)
+	if openErr := i.openExistingTSDB(context.Background()); openErr != nil {
+		level.Error(i.logger).Log("msg", "failed to reopen TSDBs after a failed transfer", "err", openErr)
+	}
+	return err
+}
+
+func (i *Ingester) transferOut(ctx context.Context, targetID string) error {
+	files, err := i.listTransferFiles()
+	if err != nil {
+		return errors.Wrap(err, "list TSDB files to transfer")
+	}
+
+	clientMetrics := client.NewMetrics(nil)
+	boff := backoff.New(ctx, backoff.Config{
+		MinBackoff: time.Second,
+		MaxBackoff: 10 * time.Second,
+		MaxRetries: i.cfg.Transfer.MaxAttempts,
+	})
+
+	for boff.Ongoing() {
+		var target ring.InstanceDesc
+		targetID, target, err = i.findTransferTarget(ctx, targetID)
+		if err == nil {
+			level.Info(i.logger).Log("msg", "transferring TSDBs", "target", targetID, "files", len(files))
+			err = i.transferTo(ctx, target, files, clientMetrics)
+		}
+		if err == nil {
+			i.metrics.tsdbTransfers.WithLabelValues(transferDirectionSent, "success").Inc()
+			level.Info(i.logger).Log("msg", "successfully transferred TSDBs", "target", targetID)
+			return nil
+		}
+
+		i.metrics.tsdbTransfers.WithLabelValues(transferDirectionSent, "fail").Inc()
+		if errors.Is(err, errTransferNoTarget) {
+			// The target isn't PENDING anymore and there's no other one: retrying won't help.
+			return err
+		}
+		level.Warn(i.logger).Log("msg", "TSDB transfer attempt failed", "target", targetID, "attempt", boff.NumRetries()+1, "err", err)
+		boff.Wait()
+	}
+	return err
+}
+
+// findTransferTarget returns a PENDING ingester to transfer the TSDBs to. The previous target is preferred, if
+// still PENDING, so that an interrupted transfer is resumed. If zone-awareness is enabled, only ingesters in the
+// same zone are considered.
+func (i *Ingester) findTransferTarget(ctx context.Context, previousID string) (string, ring.InstanceDesc, error) {
+	obj, err := i.lifecycler.KVStore.Get(ctx, i.lifecycler.RingKey)
+	if err != nil {
+		return "", ring.InstanceDesc{}, errors.Wrap(err, "get ring")
+	}
+	desc := ring.GetOrCreateRingDesc(obj)
+
+	isTarget := func(id string, inst ring.InstanceDesc) bool {
+		if id == i.lifecycler.ID || inst.State != ring.PENDING || !inst.IsHeartbeatHealthy(i.cfg.IngesterRing.HeartbeatTimeout, time.Now()) {
+			return false
+		}
+		return !i.cfg.IngesterRing.ZoneAwarenessEnabled || inst.Zone == i.lifecycler.Zone
+	}
+
+	if inst, ok := desc.Ingesters[previousID]; ok && isTarget(previousID, inst) {
+		return previousID, inst, nil
+	}
+
+	ids := make([]string, 0, len(desc.Ingesters))
+	for id, inst := range desc.Ingesters {
+		if isTarget(id, inst) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return "", ring.InstanceDesc{}, errTransferNoTarget
+	}
+
+	sort.Strings(ids)
+	return ids[0], desc.Ingesters[ids[0]], nil
+}
+
+// listTransferFiles returns the files of the TSDBs on disk to transfer, with their checksum.
+func (i *Ingester) listTransferFiles() ([]*client.TransferTSDBFile, error) {
+	userIDs, err := i.findUserIDsWithTSDBOnFilesystem()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*client.TransferTSDBFile
+	for _, userID := range userIDs {
+		userDir := i.cfg.BlocksStorageConfig.TSDB.BlocksDir(userID)
+
+		// The WAL segments preceding the last chunk snapshot are not needed to restore the in-memory series.
+		snapshotDir, snapshotIndex := "", -1
+		if dir, idx, _, err := tsdb.LastChunkSnapshot(userDir); err == nil {
+			snapshotDir, snapshotIndex = filepath.Base(dir), idx
+		}
+
+		err := filepath.WalkDir(userDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(userDir, path)
+			if err != nil {
+				return err
+			}
+			if rel == "." {
+				return nil
+			}
+
+			if skipTransferFile(filepath.ToSlash(rel), d.IsDir(), snapshotDir, snapshotIndex) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			size, checksum, err := transferFileChecksum(path)
+			if err != nil {
+				return err
+			}
+			files = append(files, &client.TransferTSDBFile{
+				UserId:    userID,
+				Filename:  filepath.ToSlash(rel),
+				SizeBytes: size,
+				Checksum:  checksum,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "list TSDB files of user %s", userID)
+		}
+	}
+	return files, nil
+}
+
+// skipTransferFile returns whether the file, or directory, at the input path relative to the TSDB directory of a
+// tenant should not be transferred. snapshotDir and snapshotIndex are the name of the last chunk snapshot and the
+// index of the WAL segment it has been taken at, or empty and -1 if there's no chunk snapshot.
+func skipTransferFile(rel string, isDir bool, snapshotDir string, snapshotIndex int) bool {
+	name := filepath.Base(rel)
+
+	// Lock files and temporary directories left behind by interrupted operations.
+	if rel == "lock" || (isDir && strings.HasSuffix(name, ".tmp")) {
+		return true
+	}
+
+	// Older chunk snapshots.
+	if isDir && rel == name && strings.HasPrefix(name, "chunk_snapshot.") {
+		return name != snapshotDir
+	}
+
+	if snapshotIndex < 0 || filepath.Dir(rel) != "wal" {
+		return false
+	}
+
+	// WAL checkpoints and segments covered by the chunk snapshot.
+	if isDir {
+		idx, err := strconv.Atoi(strings.TrimPrefix(name, "checkpoint."))
+		return strings.HasPrefix(name, "checkpoint.") && err == nil && idx < snapshotIndex
+	}
+	idx, err := strconv.Atoi(name)
+	return err == nil && idx < snapshotIndex
+}
+
+func transferFileChecksum(path string) (int64, uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	h := crc32.New(transferChecksumTable)
+	size, err := io.Copy(h, f)
+	return size, h.Sum32(), err
+}
+
+// transferTo sends the files to the target ingester, skipping the bytes it has already received.
+func (i *Ingester) transferTo(ctx context.Context, target ring.InstanceDesc, files []*client.TransferTSDBFile, clientMetrics *client.Metrics) error {
+	c, err := client.MakeIngesterClient(target, i.cfg.IngesterClientConfig, clientMetrics, i.logger)
+	if err != nil {
+		return errors.Wrap(err, "create ingester client")
+	}
+	defer c.Close() //nolint:errcheck
+
+	ctx, cancel := context.WithCancel(user.InjectOrgID(ctx, "0")) // fake: the ingester client insists on having an org ID
+	defer cancel()
+
+	stream, err := c.TransferTSDB(ctx)
+	if err != nil {
+		return errors.Wrap(err, "open transfer stream")
+	}
+
+	if err := stream.Send(&client.TransferTSDBRequest{FromIngesterId: i.lifecycler.ID, Files: files}); err != nil {
+		return errors.Wrap(err, "send files to transfer")
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return errors.Wrap(err, "receive transfer state")
+	}
+	if len(resp.ReceivedBytes) != len(files) {
+		return fmt.Errorf("unexpected transfer state: %d files received, %d files sent", len(resp.ReceivedBytes), len(files))
+	}
+
+	buf := make([]byte, transferChunkSize)
+	for idx, file := range files {
+		if err := i.sendTransferFile(stream, file, resp.ReceivedBytes[idx], buf); err != nil {
+			return errors.Wrapf(err, "send file %s of user %s", file.Filename, file.UserId)
+		}
+	}
+
+	if err := stream.Send(&client.TransferTSDBRequest{FromIngesterId: i.lifecycler.ID, Commit: true}); err != nil {
+		return errors.Wrap(err, "send transfer commit")
+	}
+	if _, err := stream.Recv(); err != nil {
+		return errors.Wrap(err, "commit transfer")
+	}
+	return stream.CloseSend()
+}
+
+func (i *Ingester) sendTransferFile(stream client.Ingester_TransferTSDBClient, file *client.TransferTSDBFile, offset int64, buf []byte) error {
+	if offset >= file.SizeBytes {
+		return nil
+	}
+
+	f, err := os.Open(filepath.Join(i.cfg.BlocksStorageConfig.TSDB.BlocksDir(file.UserId), filepath.FromSlash(file.Filename)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	for offset < file.SizeBytes {
+		n, err := io.ReadFull(f, buf[:min(int64(len(buf)), file.SizeBytes-offset)])
+		if err != nil {
+			return err
+		}
+
+		err = stream.Send(&client.TransferTSDBRequest{
+			FromIngesterId: i.lifecycler.ID,
+			Chunk: &client.TimeSeriesFile{
+				UserId:   file.UserId,
+				Filename: file.Filename,
+				Offset:   offset,
+				Data:     buf[:n],
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		offset += int64(n)
+		i.metrics.tsdbTransferredBytes.WithLabelValues(transferDirectionSent).Add(float64(n))
+	}
+	return nil
+}
+
+// TransferTSDB implements client.IngesterServer. It receives the TSDBs of a leaving ingester, opens them once all
+// the files have been received, and takes over the tokens of the leaving ingester in the ring.
+//
+// The received files are written in a staging directory, which is kept if the transfer fails, so that the leaving
+// ingester can resume the transfer.
+func (i *Ingester) TransferTSDB(stream client.Ingester_TransferTSDBServer) (err error) {
+	if !i.cfg.Transfer.Enabled {
+		return errors.New("TSDB transfer is disabled")
+	}
+	if err := i.checkAvailable(); err != nil {
+		return err
+	}
+	if !i.transferMtx.TryLock() {
+		return errors.New("another TSDB transfer is in progress")
+	}
+	defer i.transferMtx.Unlock()
+
+	if state := i.lifecycler.GetState(); state != ring.PENDING {
+		return fmt.Errorf("TSDB transfer can only be received in the %s state, the ingester is %s", ring.PENDING, state)
+	}
+	if users := i.getTSDBUsers(); len(users) > 0 {
+		return fmt.Errorf("TSDB transfer can only be received by an ingester without TSDBs, the ingester has %d TSDBs", len(users))
+	}
+
+	defer func() {
+		outcome := "success"
+		if err != nil {
+			outcome = "fail"
+		}
+		i.metrics.tsdbTransfers.WithLabelValues(transferDirectionReceived, outcome).Inc()
+	}()
+
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	fromID := req.FromIngesterId
+	if !isValidTransferPathElement(fromID) {
+		return fmt.Errorf("invalid leaving ingester ID %q", fromID)
+	}
+
+	staging, err := newTransferStaging(filepath.Join(i.cfg.BlocksStorageConfig.TSDB.Dir, transferStagingDirName, fromID), req.Files)
+	if err != nil {
+		return err
+	}
+	defer staging.close()
+
+	level.Info(i.logger).Log("msg", "receiving TSDB transfer", "from", fromID, "files", len(req.Files))
+	if err := stream.Send(&client.TransferTSDBResponse{ReceivedBytes: staging.received}); err != nil {
+		return err
+	}
+
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if req.Commit {
+			break
+		}
+		if req.Chunk == nil {
+			continue
+		}
+
+		if err := staging.write(req.Chunk); err != nil {
+			return err
+		}
+		i.metrics.tsdbTransferredBytes.WithLabelValues(transferDirectionReceived).Add(float64(len(req.Chunk.Data)))
+	}
+
+	if err := i.commitTransfer(stream.Context(), fromID, staging); err != nil {
+		level.Error(i.logger).Log("msg", "failed to commit TSDB transfer", "from", fromID, "err", err)
+		return err
+	}
+
+	level.Info(i.logger).Log("msg", "successfully received TSDB transfer", "from", fromID)
+	return stream.Send(&client.TransferTSDBResponse{})
+}
+
+// commitTransfer verifies the received files, opens the transferred TSDBs and claims the tokens of the leaving ingester.
+func (i *Ingester) commitTransfer(ctx context.Context, fromID string, staging *transferStaging) error {
+	if err := staging.verify(); err != nil {
+		return err
+	}
+
+	// The ingester stays PENDING while the TSDBs are opened: if the commit fails, the lifecycler still
+	// joins the ring with new tokens once the join-after period has elapsed.
+	if state := i.lifecycler.GetState(); state != ring.PENDING {
+		return fmt.Errorf("TSDB transfer can only be committed in the %s state, the ingester is %s", ring.PENDING, state)
+	}
+
+	if err := i.openTransferredTSDBs(staging); err != nil {
+		i.closeAllTSDB()
+		i.restoreTransferredTSDBs(staging)
+		return err
+	}
+
+	// The received files have been moved, so the staging directories of all the leaving ingesters can be removed.
+	if err := os.RemoveAll(filepath.Join(i.cfg.BlocksStorageConfig.TSDB.Dir, transferStagingDirName)); err != nil {
+		level.Warn(i.logger).Log("msg", "failed to remove the TSDB transfer staging directory", "err", err)
+	}
+
+	if err := i.lifecycler.ClaimTokensFor(ctx, fromID); err != nil {
+		return errors.Wrap(err, "claim tokens")
+	}
+
+	// The lifecycler may have joined the ring in the meantime, in which case the claimed tokens replaced
+	// the generated ones.
+	if i.lifecycler.GetState() != ring.PENDING {
+		return nil
+	}
+	return errors.Wrap(i.lifecycler.ChangeState(ctx, ring.ACTIVE), "switch to the ACTIVE state")
+}
+
+func (i *Ingester) openTransferredTSDBs(staging *transferStaging) error {
+	for _, userID := range staging.userIDs() {
+		userDir := i.cfg.BlocksStorageConfig.TSDB.BlocksDir(userID)
+		if err := os.Rename(filepath.Join(staging.dir, userID), userDir); err != nil {
+			return errors.Wrapf(err, "move TSDB of user %s", userID)
+		}
+
+		db, err := i.createTSDB(userID, 0)
+		if err != nil {
+			return errors.Wrapf(err, "open TSDB of user %s", userID)
+		}
+
+		i.tsdbsMtx.Lock()
+		i.tsdbs[userID] = db
+		i.tsdbsMtx.Unlock()
+		i.metrics.memUsers.Inc()
+	}
+
+	i.updateUsageStats()
+	return nil
+}
+
+// restoreTransferredTSDBs moves the TSDBs back to the staging directory after a failed commit, so that
+// the transfer can be resumed.
+func (i *Ingester) restoreTransferredTSDBs(staging *transferStaging) {
+	for _, userID := range staging.userIDs() {
+		userDir := i.cfg.BlocksStorageConfig.TSDB.BlocksDir(userID)
+		if _, err := os.Stat(userDir); err != nil {
+			continue
+		}
+		if err := os.Rename(userDir, filepath.Join(staging.dir, userID)); err != nil {
+			level.Warn(i.logger).Log("msg", "failed to move transferred TSDB back to the staging directory", "user", userID, "err", err)
+		}
+	}
+}
+
+func isValidTransferPathElement(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, `/\`)
+}
+
+// transferStaging tracks the files received from a leaving ingester, and written in the staging directory.
+type transferStaging struct {
+	dir      string
+	files    []*client.TransferTSDBFile
+	received []int64
+	index    map[string]int
+
+	// The file currently written. Files are sent one after the other.
+	current     int
+	currentFile *os.File
+}
+
+// newTransferStaging returns the staging of the input files in dir. The files partially received by a previous
+// transfer are kept, unless they're bigger than expected.
+func newTransferStaging(dir string, files []*client.TransferTSDBFile) (*transferStaging, error) {
+	s := &transferStaging{
+		dir:      dir,
+		files:    files,
+		received: make([]int64, len(files)),
+		index:    make(map[string]int, len(files)),
+		current:  -1,
+	}
+
+	for idx, f := range files {
+		if !isValidTransferPathElement(f.UserId) || tenant.ValidTenantID(f.UserId) != nil {
+			return nil, fmt.Errorf("invalid user ID %q", f.UserId)
+		}
+		if !filepath.IsLocal(filepath.FromSlash(f.Filename)) {
+			return nil, fmt.Errorf("invalid filename %q of user %s", f.Filename, f.UserId)
+		}
+		s.index[transferFileKey(f.UserId, f.Filename)] = idx
+
+		info, err := os.Stat(s.path(f))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			continue
+		case err != nil:
+			return nil, err
+		case info.Size() > f.SizeBytes:
+			if err := os.Remove(s.path(f)); err != nil {
+				return nil, err
+			}
+		default:
+			s.received[idx] = info.Size()
+		}
+	}
+
+	return s, os.MkdirAll(dir, os.ModePerm)
+}
+
+func transferFileKey(userID, filename string) string {
+	return userID + "/" + filename
+}
+
+func (s *transferStaging) path(f *client.TransferTSDBFile) string {
+	return filepath.Join(s.dir, f.UserId, filepath.FromSlash(f.Filename))
+}
+
+// write appends the chunk to the staged file. Chunks must be received in order.
+func (s *transferStaging) write(chunk *client.TimeSeriesFile) error {
+	idx, ok := s.index[transferFileKey(chunk.UserId, chunk.Filename)]
+	if !ok {
+		return fmt.Errorf("unexpected file %s of user %s", chunk.Filename, chunk.UserId)
+	}
+
+	file := s.files[idx]
+	if chunk.Offset != s.received[idx] {
+		return fmt.Errorf("unexpected offset %d of file %s of user %s, expected %d", chunk.Offset, file.Filename, file.UserId, s.received[idx])
+	}
+	if chunk.Offset+int64(len(chunk.Data)) > file.SizeBytes {
+		return fmt.Errorf("file %s of user %s is bigger than %d bytes", file.Filename, file.UserId, file.SizeBytes)
+	}
+
+	if s.current != idx {
+		if err := s.closeCurrent(); err != nil {
+			return err
+		}
+
+		path := s.path(file)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o666)
+		if err != nil {
+			return err
+		}
+		s.current, s.currentFile = idx, f
+	}
+
+	if _, err := s.currentFile.Write(chunk.Data); err != nil {
+		return err
+	}
+	s.received[idx] += int64(len(chunk.Data))
+	return nil
+}
+
+func (s *transferStaging) closeCurrent() error {
+	if s.currentFile == nil {
+		return nil
+	}
+
+	err := s.currentFile.Sync()
+	if closeErr := s.currentFile.Close(); err == nil {
+		err = closeErr
+	}
+	s.current, s.currentFile = -1, nil
+	return err
+}
+
+func (s *transferStaging) close() {
+	_ = s.closeCurrent()
+}
+
+// verify checks that all the files have been completely received, and their checksum. Files with a checksum
+// mismatch are removed, so that they're transferred again by the next attempt.
+func (s *transferStaging) verify() error {
+	if err := s.closeCurrent(); err != nil {
+		return err
+	}
+
+	for idx, f := range s.files {
+		if s.received[idx] != f.SizeBytes {
+			return fmt.Errorf("file %s of user %s is incomplete: received %d bytes out of %d", f.Filename, f.UserId, s.received[idx], f.SizeBytes)
+		}
+
+		// Files may be empty, in which case they've never been written.
+		if f.SizeBytes == 0 {
+			if err := os.MkdirAll(filepath.Dir(s.path(f)), os.ModePerm); err != nil {
+				return err
+			}
+			if err := os.WriteFile(s.path(f), nil, 0o666); err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, checksum, err := transferFileChecksum(s.path(f))
+		if err != nil {
+			return err
+		}
+		if checksum != f.Checksum {
+			if err := os.Remove(s.path(f)); err != nil {
+				return err
+			}
+			return fmt.Errorf("checksum mismatch of file %s of user %s", f.Filename, f.UserId)
+		}
+	}
+	return nil
+}
+
+// userIDs returns the users with at least a file in the transfer.
+func (s *transferStaging) userIDs() []string {
+	seen := map[string]struct{}{}
+	var userIDs []string
+	for _, f := range s.files {
+		if _, ok := seen[f.UserId]; !ok {
+			seen[f.UserId] = struct{}{}
+			userIDs = append(userIDs, f.UserId)
+		}
+	}
+	return userIDs
+}
@@ -0,0 +1,384 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"hash/crc32"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+)
+
+func TestTransferConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		cfg                      TransferConfig
+		memorySnapshotOnShutdown bool
+		expectedError            error
+	}{
+		"disabled passes validation": {
+			cfg: TransferConfig{Enabled: false, MaxAttempts: 0},
+		},
+		"enabled with memory snapshot on shutdown passes validation": {
+			cfg:                      TransferConfig{Enabled: true, MaxAttempts: 5},
+			memorySnapshotOnShutdown: true,
+		},
+		"enabled without memory snapshot on shutdown doesn't pass validation": {
+			cfg:           TransferConfig{Enabled: true, MaxAttempts: 5},
+			expectedError: errTransferWithoutMemorySnapshot,
+		},
+		"enabled without attempts doesn't pass validation": {
+			cfg:                      TransferConfig{Enabled: true, MaxAttempts: 0},
+			memorySnapshotOnShutdown: true,
+			expectedError:            errTransferInvalidMaxAttempts,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			tsdbCfg := mimir_tsdb.TSDBConfig{MemorySnapshotOnShutdown: testData.memorySnapshotOnShutdown}
+			require.Equal(t, testData.expectedError, testData.cfg.Validate(tsdbCfg))
+		})
+	}
+}
+
+func TestSkipTransferFile(t *testing.T) {
+	tests := map[string]struct {
+		rel           string
+		isDir         bool
+		snapshotDir   string
+		snapshotIndex int
+		expected      bool
+	}{
+		"block":                           {rel: "01HCZ6JXZ7Q3F6P1N9Y1G6V2ZP", isDir: true, snapshotIndex: -1},
+		"block file":                      {rel: "01HCZ6JXZ7Q3F6P1N9Y1G6V2ZP/index", snapshotIndex: -1},
+		"head chunks":                     {rel: "chunks_head/000001", snapshotIndex: -1},
+		"lock file":                       {rel: "lock", snapshotIndex: -1, expected: true},
+		"temporary directory":             {rel: "01HCZ6JXZ7Q3F6P1N9Y1G6V2ZP.tmp", isDir: true, snapshotIndex: -1, expected: true},
+		"last chunk snapshot":             {rel: "chunk_snapshot.000003.0000000100", isDir: true, snapshotDir: "chunk_snapshot.000003.0000000100", snapshotIndex: 3},
+		"older chunk snapshot":            {rel: "chunk_snapshot.000002.0000000100", isDir: true, snapshotDir: "chunk_snapshot.000003.0000000100", snapshotIndex: 3, expected: true},
+		"WAL segment without snapshot":    {rel: "wal/00000001", snapshotIndex: -1},
+		"WAL checkpoint without snapshot": {rel: "wal/checkpoint.00000001", isDir: true, snapshotIndex: -1},
+		"WAL segment before snapshot":     {rel: "wal/00000002", snapshotDir: "chunk_snapshot.000003.0000000100", snapshotIndex: 3, expected: true},
+		"WAL segment of snapshot":         {rel: "wal/00000003", snapshotDir: "chunk_snapshot.000003.0000000100", snapshotIndex: 3},
+		"WAL segment after snapshot":      {rel: "wal/00000004", snapshotDir: "chunk_snapshot.000003.0000000100", snapshotIndex: 3},
+		"WAL checkpoint before snapshot":  {rel: "wal/checkpoint.00000002", isDir: true, snapshotDir: "chunk_snapshot.000003.0000000100", snapshotIndex: 3, expected: true},
+		"OOO WAL segment":                 {rel: "wbl/00000001", snapshotDir: "chunk_snapshot.000003.0000000100", snapshotIndex: 3},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, skipTransferFile(tc.rel, tc.isDir, tc.snapshotDir, tc.snapshotIndex))
+		})
+	}
+}
+
+func TestTransferStaging(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte("0123456789")
+	files := []*client.TransferTSDBFile{
+		{UserId: "user-1", Filename: "wal/00000001", SizeBytes: int64(len(content)), Checksum: crc32.Checksum(content, transferChecksumTable)},
+		{UserId: "user-2", Filename: "empty", SizeBytes: 0},
+	}
+
+	t.Run("invalid files are rejected", func(t *testing.T) {
+		_, err := newTransferStaging(dir, []*client.TransferTSDBFile{{UserId: "user-1", Filename: "../escape"}})
+		require.Error(t, err)
+
+		_, err = newTransferStaging(dir, []*client.TransferTSDBFile{{UserId: "..", Filename: "wal/00000001"}})
+		require.Error(t, err)
+	})
+
+	// Receive the first half of the file, then interrupt the transfer.
+	staging, err := newTransferStaging(dir, files)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 0}, staging.received)
+
+	require.NoError(t, staging.write(&client.TimeSeriesFile{UserId: "user-1", Filename: "wal/00000001", Offset: 0, Data: content[:5]}))
+	require.Error(t, staging.write(&client.TimeSeriesFile{UserId: "user-1", Filename: "wal/00000001", Offset: 7, Data: content[7:]}), "out of order chunks are rejected")
+	require.Error(t, staging.write(&client.TimeSeriesFile{UserId: "user-1", Filename: "unknown", Offset: 0, Data: content}), "unexpected files are rejected")
+	require.Error(t, staging.verify(), "incomplete files are rejected")
+	staging.close()
+
+	// The next transfer is resumed from the bytes already received.
+	staging, err = newTransferStaging(dir, files)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5, 0}, staging.received)
+
+	require.NoError(t, staging.write(&client.TimeSeriesFile{UserId: "user-1", Filename: "wal/00000001", Offset: 5, Data: content[5:]}))
+	require.NoError(t, staging.verify())
+	staging.close()
+
+	actual, err := os.ReadFile(filepath.Join(dir, "user-1", "wal", "00000001"))
+	require.NoError(t, err)
+	assert.Equal(t, content, actual)
+	assert.FileExists(t, filepath.Join(dir, "user-2", "empty"))
+	assert.Equal(t, []string{"user-1", "user-2"}, staging.userIDs())
+
+	// A file with a checksum mismatch is removed, so that it's transferred again.
+	files[0].Checksum++
+	staging, err = newTransferStaging(dir, files)
+	require.NoError(t, err)
+	require.Error(t, staging.verify())
+	staging.close()
+	assert.NoFileExists(t, filepath.Join(dir, "user-1", "wal", "00000001"))
+}
+
+func TestIngester_TransferOut(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), userID)
+
+	// The ingester receiving the TSDBs.
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	targetCfg := defaultIngesterTestConfig(t)
+	targetCfg.IngesterRing.InstanceID = "ingester-target"
+	targetCfg.IngesterRing.InstancePort, err = strconv.Atoi(port)
+	require.NoError(t, err)
+	targetCfg.Transfer.Enabled = true
+	targetCfg.Transfer.JoinAfter = time.Hour
+	targetCfg.Transfer.MaxAttempts = 1
+	targetCfg.BlocksStorageConfig.TSDB.MemorySnapshotOnShutdown = true
+
+	target, err := prepareIngesterWithBlocksStorage(t, targetCfg, nil)
+	require.NoError(t, err)
+
+	serv := grpc.NewServer(grpc.StreamInterceptor(middleware.StreamServerUserHeaderInterceptor))
+	t.Cleanup(serv.Stop)
+	client.RegisterIngesterServer(serv, target)
+	go func() {
+		_ = serv.Serve(listener)
+	}()
+
+	// The leaving ingester, sharing the ring with the target.
+	sourceCfg := defaultIngesterTestConfig(t)
+	sourceCfg.IngesterRing.KVStore.Mock = targetCfg.IngesterRing.KVStore.Mock
+	sourceCfg.IngesterRing.InstanceID = "ingester-source"
+	sourceCfg.Transfer.Enabled = true
+	sourceCfg.Transfer.JoinAfter = 0
+	sourceCfg.Transfer.MaxAttempts = 1
+	sourceCfg.IngesterClientConfig = defaultClientTestConfig()
+	sourceCfg.BlocksStorageConfig.TSDB.MemorySnapshotOnShutdown = true
+
+	source, err := prepareIngesterWithBlocksStorage(t, sourceCfg, nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), source))
+	test.Poll(t, time.Second, ring.ACTIVE, func() interface{} {
+		return source.lifecycler.GetState()
+	})
+
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), target))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), target)
+	})
+	test.Poll(t, time.Second, ring.PENDING, func() interface{} {
+		return target.lifecycler.GetState()
+	})
+
+	// Push samples, compacting the first ones in a block, so that both blocks and in-memory series are transferred.
+	series := labels.FromStrings(model.MetricNameLabel, "test", "pod", "1")
+	req, _, _, _ := mockWriteRequest(t, series, 1, 1000)
+	_, err = source.Push(ctx, req)
+	require.NoError(t, err)
+	source.compactBlocks(context.Background(), true, math.MaxInt64, nil)
+
+	req, _, _, _ = mockWriteRequest(t, series, 2, 2000)
+	_, err = source.Push(ctx, req)
+	require.NoError(t, err)
+
+	ringTokens := func(id string) []uint32 {
+		obj, err := targetCfg.IngesterRing.KVStore.Mock.Get(context.Background(), IngesterRingKey)
+		require.NoError(t, err)
+		return ring.GetOrCreateRingDesc(obj).Ingesters[id].Tokens
+	}
+
+	sourceTokens := ringTokens("ingester-source")
+	require.NotEmpty(t, sourceTokens)
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), source))
+
+	// The target took over the tokens of the leaving ingester, and serves its series.
+	assert.Equal(t, ring.ACTIVE, target.lifecycler.GetState())
+	assert.Equal(t, sourceTokens, ringTokens("ingester-target"))
+	assert.NoDirExists(t, filepath.Join(targetCfg.BlocksStorageConfig.TSDB.Dir, transferStagingDirName))
+
+	res, _, err := runTestQuery(ctx, t, target, labels.MatchEqual, model.MetricNameLabel, "test")
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, []model.SamplePair{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}}, res[0].Values)
+
+	db := target.getTSDB(userID)
+	require.NotNil(t, db)
+	assert.Equal(t, uint64(1), db.Head().NumSeries())
+	assert.Len(t, db.Blocks(), 1)
+}
+
+func TestIngester_TransferTSDB_ShouldRejectTransfersIfNotPending(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.Transfer.Enabled = true
+	cfg.Transfer.JoinAfter = 0
+	cfg.Transfer.MaxAttempts = 1
+
+	i, err := prepareIngesterWithBlocksStorage(t, cfg, nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), i)
+	})
+	test.Poll(t, time.Second, ring.ACTIVE, func() interface{} {
+		return i.lifecycler.GetState()
+	})
+
+	err = i.TransferTSDB(nil)
+	require.ErrorContains(t, err, "TSDB transfer can only be received in the PENDING state")
+}
+
+func TestIngester_TransferOut_ShouldNotCloseTSDBsWithoutTarget(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), userID)
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.Transfer.Enabled = true
+	cfg.Transfer.MaxAttempts = 3
+	cfg.BlocksStorageConfig.TSDB.MemorySnapshotOnShutdown = true
+
+	i, err := prepareIngesterWithBlocksStorage(t, cfg, nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), i)
+	})
+	test.Poll(t, time.Second, ring.ACTIVE, func() interface{} {
+		return i.lifecycler.GetState()
+	})
+
+	req, _, _, _ := mockWriteRequest(t, labels.FromStrings(model.MetricNameLabel, "test"), 1, 1000)
+	_, err = i.Push(ctx, req)
+	require.NoError(t, err)
+	db := i.getTSDB(userID)
+	require.NotNil(t, db)
+
+	// There's no PENDING ingester, so the TSDBs are neither closed nor reopened.
+	require.ErrorIs(t, i.TransferOut(context.Background()), errTransferNoTarget)
+	assert.Same(t, db, i.getTSDB(userID))
+}
+
+func TestIngester_TransferTSDB_ShouldJoinAfterAFailedCommit(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.Transfer.Enabled = true
+	cfg.Transfer.JoinAfter = time.Second
+	cfg.Transfer.MaxAttempts = 1
+
+	i, err := prepareIngesterWithBlocksStorage(t, cfg, nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), i)
+	})
+	require.Equal(t, ring.PENDING, i.lifecycler.GetState())
+
+	// Receive a TSDB whose directory can't be moved in place, so that the commit fails.
+	content := []byte("0123456789")
+	staging, err := newTransferStaging(filepath.Join(i.cfg.BlocksStorageConfig.TSDB.Dir, transferStagingDirName, "ingester-source"), []*client.TransferTSDBFile{
+		{UserId: "user-1", Filename: "wal/00000001", SizeBytes: int64(len(content)), Checksum: crc32.Checksum(content, transferChecksumTable)},
+	})
+	require.NoError(t, err)
+	t.Cleanup(staging.close)
+	require.NoError(t, staging.write(&client.TimeSeriesFile{UserId: "user-1", Filename: "wal/00000001", Offset: 0, Data: content}))
+	require.NoError(t, os.MkdirAll(filepath.Join(i.cfg.BlocksStorageConfig.TSDB.BlocksDir("user-1"), "wal"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(i.cfg.BlocksStorageConfig.TSDB.BlocksDir("user-1"), "wal", "00000001"), nil, os.ModePerm))
+
+	require.Error(t, i.commitTransfer(context.Background(), "ingester-source", staging))
+	assert.Equal(t, ring.PENDING, i.lifecycler.GetState())
+	assert.Nil(t, i.getTSDB("user-1"))
+
+	// The ingester still joins the ring with new tokens once the join-after period has elapsed.
+	test.Poll(t, 5*time.Second, ring.ACTIVE, func() interface{} {
+		return i.lifecycler.GetState()
+	})
+}
+
+func TestIngester_TransferOut_ShouldReopenTSDBsAfterAFailedTransfer(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), userID)
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.Transfer.Enabled = true
+	cfg.Transfer.MaxAttempts = 1
+	cfg.IngesterClientConfig = defaultClientTestConfig()
+	cfg.BlocksStorageConfig.TSDB.MemorySnapshotOnShutdown = true
+
+	registry := prometheus.NewRegistry()
+	i, err := prepareIngesterWithBlocksStorage(t, cfg, registry)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), i)
+	})
+	test.Poll(t, time.Second, ring.ACTIVE, func() interface{} {
+		return i.lifecycler.GetState()
+	})
+
+	for _, pod := range []string{"1", "2"} {
+		req, _, _, _ := mockWriteRequest(t, labels.FromStrings(model.MetricNameLabel, "test", "pod", pod), 1, 1000)
+		_, err = i.Push(ctx, req)
+		require.NoError(t, err)
+	}
+	i.updateEstimatedMemory()
+
+	// The estimated memory of the series and postings is compared, because the chunks memory is only
+	// refreshed periodically.
+	expectedMemory := i.getTSDB(userID).memoryStats()
+	expectedMemory.HeadChunks, expectedMemory.OOOChunks = 0, 0
+	require.Equal(t, int64(2), i.seriesCount.Load())
+	require.Greater(t, expectedMemory.Total(), int64(0))
+
+	// Register a PENDING ingester which isn't reachable, so that the transfer fails.
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	targetAddr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	require.NoError(t, cfg.IngesterRing.KVStore.Mock.CAS(context.Background(), IngesterRingKey, func(in interface{}) (interface{}, bool, error) {
+		desc := ring.GetOrCreateRingDesc(in)
+		desc.Ingesters["ingester-target"] = ring.InstanceDesc{Addr: targetAddr, State: ring.PENDING, Timestamp: time.Now().Unix()}
+		return desc, true, nil
+	}))
+
+	require.Error(t, i.TransferOut(context.Background()))
+
+	// The TSDBs have been reopened, and their series are counted once.
+	db := i.getTSDB(userID)
+	require.NotNil(t, db)
+
+	assert.Equal(t, int64(2), i.seriesCount.Load())
+	assert.Equal(t, expectedMemory, db.memoryStats())
+	assert.Equal(t, expectedMemory.Total(), i.memoryBytes.Load())
+
+	// The estimated memory of the closed TSDBs isn't exported until it's refreshed.
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+		# HELP cortex_ingester_memory_users The current number of users in memory.
+		# TYPE cortex_ingester_memory_users gauge
+		cortex_ingester_memory_users 1
+	`), "cortex_ingester_memory_users", "cortex_ingester_estimated_memory_bytes"))
+}
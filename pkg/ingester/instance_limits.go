@@ -18,6 +18,7 @@ const (
 	maxInMemoryTenantsFlag      = "ingester.instance-limits.max-tenants"
 	maxInMemorySeriesFlag       = "ingester.instance-limits.max-series"
 	maxInflightPushRequestsFlag = "ingester.instance-limits.max-inflight-push-requests"
+	maxEstimatedMemoryFlag      = "ingester.instance-limits.max-estimated-memory-bytes"
 )
 
 // We don't include values in the messages for per-instance limits to avoid leaking Mimir cluster configuration to users.
@@ -26,6 +27,7 @@ var (
 	errMaxTenantsReached          = newInstanceLimitReachedError(globalerror.IngesterMaxTenants.MessageWithPerInstanceLimitConfig("the write request has been rejected because the ingester exceeded the allowed number of tenants", maxInMemoryTenantsFlag))
 	errMaxInMemorySeriesReached   = newInstanceLimitReachedError(globalerror.IngesterMaxInMemorySeries.MessageWithPerInstanceLimitConfig("the write request has been rejected because the ingester exceeded the allowed number of in-memory series", maxInMemorySeriesFlag))
 	errMaxInflightRequestsReached = newInstanceLimitReachedError(globalerror.IngesterMaxInflightPushRequests.MessageWithPerInstanceLimitConfig("the write request has been rejected because the ingester exceeded the allowed number of inflight push requests", maxInflightPushRequestsFlag))
+	errMaxEstimatedMemoryReached  = newInstanceLimitReachedError(globalerror.IngesterMaxEstimatedMemory.MessageWithPerInstanceLimitConfig("the write request has been rejected because the ingester exceeded the allowed estimated memory of in-memory series", maxEstimatedMemoryFlag))
 )
 
 // InstanceLimits describes limits used by ingester. Reaching any of these will result in Push method to return
@@ -35,6 +37,7 @@ type InstanceLimits struct {
 	MaxInMemoryTenants      int64   `yaml:"max_tenants" category:"advanced"`
 	MaxInMemorySeries       int64   `yaml:"max_series" category:"advanced"`
 	MaxInflightPushRequests int64   `yaml:"max_inflight_push_requests" category:"advanced"`
+	MaxEstimatedMemory      int64   `yaml:"max_estimated_memory_bytes" category:"experimental"`
 }
 
 func (l *InstanceLimits) RegisterFlags(f *flag.FlagSet) {
@@ -42,6 +45,7 @@ func (l *InstanceLimits) RegisterFlags(f *flag.FlagSet) {
 	f.Int64Var(&l.MaxInMemoryTenants, maxInMemoryTenantsFlag, 0, "Max tenants that this ingester can hold. Requests from additional tenants will be rejected. 0 = unlimited.")
 	f.Int64Var(&l.MaxInMemorySeries, maxInMemorySeriesFlag, 0, "Max series that this ingester can hold (across all tenants). Requests to create additional series will be rejected. 0 = unlimited.")
 	f.Int64Var(&l.MaxInflightPushRequests, maxInflightPushRequestsFlag, 30000, "Max inflight push requests that this ingester can handle (across all tenants). Additional requests will be rejected. 0 = unlimited.")
+	f.Int64Var(&l.MaxEstimatedMemory, maxEstimatedMemoryFlag, 0, "Max estimated memory, in bytes, of the in-memory series that this ingester can hold (across all tenants), including the series labels, postings, head chunks and out-of-order chunks. Requests to create additional series will be rejected. 0 = unlimited.")
 }
 
 // Sets default limit values for unmarshalling.
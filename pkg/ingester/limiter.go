@@ -66,6 +66,13 @@ func (l *Limiter) IsWithinMaxSeriesPerUser(userID string, series int) bool {
 	return series < actualLimit
 }
 
+// IsWithinMaxEstimatedMemoryPerUser returns true if limit has not been reached compared to the current
+// estimated memory, in bytes, in input; otherwise returns false.
+func (l *Limiter) IsWithinMaxEstimatedMemoryPerUser(userID string, memory int64) bool {
+	actualLimit := l.maxEstimatedMemoryPerUser(userID)
+	return memory < actualLimit
+}
+
 // IsWithinMaxMetricsWithMetadataPerUser returns true if limit has not been reached compared to the current
 // number of metrics with metadata in input; otherwise returns false.
 func (l *Limiter) IsWithinMaxMetricsWithMetadataPerUser(userID string, metrics int) bool {
@@ -89,6 +96,18 @@ func (l *Limiter) maxMetadataPerUser(userID string) int {
 	return l.convertGlobalToLocalLimitOrUnlimited(userID, l.limits.MaxGlobalMetricsWithMetadataPerUser)
 }
 
+func (l *Limiter) maxEstimatedMemoryPerUser(userID string) int64 {
+	// We can assume that the memory is evenly distributed across ingesters, like the series.
+	localLimit := int64(l.convertGlobalToLocalLimit(userID, int(l.limits.MaxGlobalEstimatedMemoryPerUser(userID))))
+
+	// If the limit is disabled
+	if localLimit == 0 {
+		localLimit = math.MaxInt64
+	}
+
+	return localLimit
+}
+
 func (l *Limiter) convertGlobalToLocalLimitOrUnlimited(userID string, globalLimitFn func(string) int) int {
 	// We can assume that series/metadata are evenly distributed across ingesters
 	globalLimit := globalLimitFn(userID)
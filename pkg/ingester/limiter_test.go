@@ -521,6 +521,78 @@ func TestLimiter_AssertMaxMetricsWithMetadataPerUser(t *testing.T) {
 	}
 }
 
+func TestLimiter_IsWithinMaxEstimatedMemoryPerUser(t *testing.T) {
+	tests := map[string]struct {
+		maxGlobalEstimatedMemoryPerUser int64
+		ringReplicationFactor           int
+		ringIngesterCount               int
+		shardSize                       int
+		memory                          int64
+		expected                        bool
+	}{
+		"limit is disabled": {
+			maxGlobalEstimatedMemoryPerUser: 0,
+			ringReplicationFactor:           1,
+			ringIngesterCount:               1,
+			memory:                          1 << 40,
+			expected:                        true,
+		},
+		"current estimated memory is below the limit": {
+			maxGlobalEstimatedMemoryPerUser: 1000,
+			ringReplicationFactor:           3,
+			ringIngesterCount:               10,
+			memory:                          299,
+			expected:                        true,
+		},
+		"current estimated memory is equal to the limit": {
+			maxGlobalEstimatedMemoryPerUser: 1000,
+			ringReplicationFactor:           3,
+			ringIngesterCount:               10,
+			memory:                          300,
+			expected:                        false,
+		},
+		"current estimated memory is below the limit of the ingesters in the tenant shard": {
+			maxGlobalEstimatedMemoryPerUser: 1000,
+			ringReplicationFactor:           3,
+			ringIngesterCount:               10,
+			shardSize:                       5,
+			memory:                          599,
+			expected:                        true,
+		},
+		"current estimated memory is equal to the limit of the ingesters in the tenant shard": {
+			maxGlobalEstimatedMemoryPerUser: 1000,
+			ringReplicationFactor:           3,
+			ringIngesterCount:               10,
+			shardSize:                       5,
+			memory:                          600,
+			expected:                        false,
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			// Mock the ring
+			ring := &ringCountMock{}
+			ring.On("InstancesCount").Return(testData.ringIngesterCount)
+			ring.On("ZonesCount").Return(1)
+
+			// Mock limits
+			limits, err := validation.NewOverrides(validation.Limits{
+				MaxGlobalEstimatedMemoryPerUser: testData.maxGlobalEstimatedMemoryPerUser,
+				IngestionTenantShardSize:        testData.shardSize,
+			}, nil)
+			require.NoError(t, err)
+
+			limiter := NewLimiter(limits, ring, testData.ringReplicationFactor, false)
+			actual := limiter.IsWithinMaxEstimatedMemoryPerUser("test", testData.memory)
+
+			assert.Equal(t, testData.expected, actual)
+		})
+	}
+}
+
 type ringCountMock struct {
 	mock.Mock
 }
@@ -26,6 +26,8 @@ type ingesterMetrics struct {
 	ingestedExemplarsFail prometheus.Counter
 	ingestedMetadataFail  prometheus.Counter
 
+	nonElectedReplicaDedupedSamples *prometheus.CounterVec
+
 	queries          prometheus.Counter
 	queriedSamples   prometheus.Histogram
 	queriedExemplars prometheus.Histogram
@@ -35,6 +37,12 @@ type ingesterMetrics struct {
 	memUsers                prometheus.Gauge
 	memMetadataCreatedTotal *prometheus.CounterVec
 	memMetadataRemovedTotal *prometheus.CounterVec
+	estimatedMemory         *prometheus.GaugeVec
+
+	// Series eviction metrics.
+	evictableSeries      *prometheus.GaugeVec
+	evictedSeries        *prometheus.CounterVec
+	seriesEvictionDryRun *prometheus.CounterVec
 
 	activeSeriesLoading                               *prometheus.GaugeVec
 	activeSeriesPerUser                               *prometheus.GaugeVec
@@ -47,6 +55,7 @@ type ingesterMetrics struct {
 	// Global limit metrics
 	maxUsersGauge           prometheus.GaugeFunc
 	maxSeriesGauge          prometheus.GaugeFunc
+	maxEstimatedMemory      prometheus.GaugeFunc
 	maxIngestionRate        prometheus.GaugeFunc
 	ingestionRate           prometheus.GaugeFunc
 	maxInflightPushRequests prometheus.GaugeFunc
@@ -63,6 +72,10 @@ type ingesterMetrics struct {
 	// Open all existing TSDBs metrics
 	openExistingTSDB prometheus.Counter
 
+	// TSDB transfer metrics
+	tsdbTransfers        *prometheus.CounterVec
+	tsdbTransferredBytes *prometheus.CounterVec
+
 	discarded *discardedMetrics
 	rejected  *prometheus.CounterVec
 
@@ -72,6 +85,7 @@ type ingesterMetrics struct {
 
 	// Shutdown marker for ingester scale down
 	shutdownMarker prometheus.Gauge
+	readOnly       prometheus.Gauge
 
 	// Count number of requests rejected due to utilization based limiting.
 	utilizationLimitedRequests *prometheus.CounterVec
@@ -129,6 +143,10 @@ func newIngesterMetrics(
 			Name: "cortex_ingester_ingested_samples_failures_total",
 			Help: "The total number of samples that errored on ingestion per user.",
 		}, []string{"user"}),
+		nonElectedReplicaDedupedSamples: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_non_elected_replica_deduped_samples_total",
+			Help: "The total number of samples received from a non-elected HA replica and deduplicated because their series has been recently updated, or doesn't exist.",
+		}, []string{"user"}),
 		ingestedExemplarsFail: promauto.With(r).NewCounter(prometheus.CounterOpts{
 			Name: "cortex_ingester_ingested_exemplars_failures_total",
 			Help: "The total number of exemplars that errored on ingestion.",
@@ -175,6 +193,22 @@ func newIngesterMetrics(
 			Name: "cortex_ingester_memory_metadata_removed_total",
 			Help: "The total number of metadata that were removed per user.",
 		}, []string{"user"}),
+		estimatedMemory: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cortex_ingester_estimated_memory_bytes",
+			Help: "The estimated memory, in bytes, of the in-memory series per user, including their labels, postings and chunks.",
+		}, []string{"user"}),
+		evictableSeries: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cortex_ingester_evictable_series",
+			Help: "The number of in-memory series per user which haven't received samples for longer than the series eviction idle timeout.",
+		}, []string{"user"}),
+		evictedSeries: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_evicted_series_total",
+			Help: "The total number of idle in-memory series per user evicted to make room for new series once the user reached the series limit.",
+		}, []string{"user"}),
+		seriesEvictionDryRun: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_series_eviction_dry_run_admissions_total",
+			Help: "The total number of new series per user rejected because of the series limit, which would have been admitted by evicting idle series if the series eviction dry-run mode was disabled.",
+		}, []string{"user"}),
 		utilizationLimitedRequests: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
 			Name: "cortex_ingester_utilization_limited_read_requests_total",
 			Help: "Total number of times read requests have been rejected due to utilization based limiting.",
@@ -202,6 +236,17 @@ func newIngesterMetrics(
 			return 0
 		}),
 
+		maxEstimatedMemory: promauto.With(r).NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        instanceLimits,
+			Help:        instanceLimitsHelp,
+			ConstLabels: map[string]string{limitLabel: "max_estimated_memory_bytes"},
+		}, func() float64 {
+			if g := instanceLimitsFn(); g != nil {
+				return float64(g.MaxEstimatedMemory)
+			}
+			return 0
+		}),
+
 		maxIngestionRate: promauto.With(r).NewGaugeFunc(prometheus.GaugeOpts{
 			Name:        instanceLimits,
 			Help:        instanceLimitsHelp,
@@ -321,6 +366,15 @@ func newIngesterMetrics(
 			Help: "The total time it takes to open all existing TSDBs at ingester startup. This time also includes the TSDBs WAL replay duration.",
 		}),
 
+		tsdbTransfers: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_tsdb_transfers_total",
+			Help: "Total number of attempts to transfer the TSDBs of a leaving ingester, by direction and outcome.",
+		}, []string{"direction", "outcome"}),
+		tsdbTransferredBytes: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_tsdb_transferred_bytes_total",
+			Help: "Total number of bytes of TSDB files sent to, or received from, another ingester.",
+		}, []string{"direction"}),
+
 		discarded: newDiscardedMetrics(r),
 		rejected: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
 			Name: "cortex_ingester_instance_rejected_requests_total",
@@ -334,12 +388,17 @@ func newIngesterMetrics(
 			Name: "cortex_ingester_prepare_shutdown_requested",
 			Help: "If the ingester has been requested to prepare for shutdown via endpoint or marker file.",
 		}),
+		readOnly: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Name: "cortex_ingester_read_only",
+			Help: "If the ingester is in read-only mode, requested via endpoint or marker file.",
+		}),
 	}
 
 	// Initialize expected rejected request labels
 	m.rejected.WithLabelValues(reasonIngesterMaxIngestionRate)
 	m.rejected.WithLabelValues(reasonIngesterMaxTenants)
 	m.rejected.WithLabelValues(reasonIngesterMaxInMemorySeries)
+	m.rejected.WithLabelValues(reasonIngesterMaxEstimatedMemory)
 	m.rejected.WithLabelValues(reasonIngesterMaxInflightPushRequests)
 
 	return m
@@ -348,8 +407,13 @@ func newIngesterMetrics(
 func (m *ingesterMetrics) deletePerUserMetrics(userID string) {
 	m.ingestedSamples.DeleteLabelValues(userID)
 	m.ingestedSamplesFail.DeleteLabelValues(userID)
+	m.nonElectedReplicaDedupedSamples.DeleteLabelValues(userID)
 	m.memMetadataCreatedTotal.DeleteLabelValues(userID)
 	m.memMetadataRemovedTotal.DeleteLabelValues(userID)
+	m.estimatedMemory.DeleteLabelValues(userID)
+	m.evictableSeries.DeleteLabelValues(userID)
+	m.evictedSeries.DeleteLabelValues(userID)
+	m.seriesEvictionDryRun.DeleteLabelValues(userID)
 
 	filter := prometheus.Labels{"user": userID}
 	m.discarded.DeletePartialMatch(filter)
@@ -382,6 +446,9 @@ type discardedMetrics struct {
 	newValueForTimestamp *prometheus.CounterVec
 	perUserSeriesLimit   *prometheus.CounterVec
 	perMetricSeriesLimit *prometheus.CounterVec
+	perUserMemoryLimit   *prometheus.CounterVec
+
+	nativeHistogramCustomBuckets *prometheus.CounterVec
 }
 
 func newDiscardedMetrics(r prometheus.Registerer) *discardedMetrics {
@@ -393,6 +460,9 @@ func newDiscardedMetrics(r prometheus.Registerer) *discardedMetrics {
 		newValueForTimestamp: validation.DiscardedSamplesCounter(r, reasonNewValueForTimestamp),
 		perUserSeriesLimit:   validation.DiscardedSamplesCounter(r, reasonPerUserSeriesLimit),
 		perMetricSeriesLimit: validation.DiscardedSamplesCounter(r, reasonPerMetricSeriesLimit),
+		perUserMemoryLimit:   validation.DiscardedSamplesCounter(r, reasonPerUserMemoryLimit),
+
+		nativeHistogramCustomBuckets: validation.DiscardedSamplesCounter(r, reasonNativeHistogramCustomBuckets),
 	}
 }
 
@@ -404,6 +474,8 @@ func (m *discardedMetrics) DeletePartialMatch(filter prometheus.Labels) {
 	m.newValueForTimestamp.DeletePartialMatch(filter)
 	m.perUserSeriesLimit.DeletePartialMatch(filter)
 	m.perMetricSeriesLimit.DeletePartialMatch(filter)
+	m.perUserMemoryLimit.DeletePartialMatch(filter)
+	m.nativeHistogramCustomBuckets.DeletePartialMatch(filter)
 }
 
 func (m *discardedMetrics) DeleteLabelValues(userID string, group string) {
@@ -414,6 +486,8 @@ func (m *discardedMetrics) DeleteLabelValues(userID string, group string) {
 	m.newValueForTimestamp.DeleteLabelValues(userID, group)
 	m.perUserSeriesLimit.DeleteLabelValues(userID, group)
 	m.perMetricSeriesLimit.DeleteLabelValues(userID, group)
+	m.perUserMemoryLimit.DeleteLabelValues(userID, group)
+	m.nativeHistogramCustomBuckets.DeleteLabelValues(userID, group)
 }
 
 // TSDB metrics collector. Each tenant has its own registry, that TSDB code uses.
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package readonly
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/kv/codec"
+	"github.com/grafana/dskit/kv/memberlist"
+)
+
+// Key is the key, in the KV store of the ingesters ring, under which the read-only mode
+// of the ingesters is stored.
+const Key = "ingester-read-only"
+
+// ProtoDescFactory makes new Descs.
+func ProtoDescFactory() proto.Message {
+	return NewDesc()
+}
+
+// NewDesc returns an empty *readonly.Desc.
+func NewDesc() *Desc {
+	return &Desc{Instances: map[string]InstanceDesc{}}
+}
+
+// GetCodec returns the codec used to encode and decode the Desc stored in the KV store.
+func GetCodec() codec.Codec {
+	return codec.NewProtoCodec("readOnlyDesc", ProtoDescFactory)
+}
+
+// Update stores in the KV store whether the instance is in read-only mode, and since when.
+func Update(ctx context.Context, client kv.Client, instanceID string, readOnly bool, since time.Time) error {
+	now := time.Now()
+
+	return client.CAS(ctx, Key, func(in interface{}) (out interface{}, retry bool, err error) {
+		desc, ok := in.(*Desc)
+		if !ok || desc == nil {
+			desc = NewDesc()
+		}
+		if desc.Instances == nil {
+			desc.Instances = map[string]InstanceDesc{}
+		}
+
+		instance := InstanceDesc{ReadOnly: readOnly, UpdatedAt: now.UnixMilli()}
+		if readOnly {
+			instance.ReadOnlySince = since.UnixMilli()
+		}
+
+		// Make sure the updated entry wins over the stored one, even if the clocks are skewed.
+		if prev, ok := desc.Instances[instanceID]; ok && prev.UpdatedAt >= instance.UpdatedAt {
+			instance.UpdatedAt = prev.UpdatedAt + 1
+		}
+
+		desc.Instances[instanceID] = instance
+		return desc, true, nil
+	})
+}
+
+// RemoveLeftInstances removes from the KV store the read-only mode of the instances which are not in the ring,
+// and whose entry has been updated before the input time. The read-only entries are replaced with tombstones,
+// so that the removal is propagated by memberlist, while the tombstones are deleted.
+func RemoveLeftInstances(ctx context.Context, client kv.Client, inRing func(instanceID string) bool, updatedBefore time.Time) error {
+	now := time.Now()
+
+	return client.CAS(ctx, Key, func(in interface{}) (out interface{}, retry bool, err error) {
+		desc, ok := in.(*Desc)
+		if !ok || desc == nil {
+			return nil, false, nil
+		}
+
+		changed := false
+		for id, instance := range desc.Instances {
+			if inRing(id) || !time.UnixMilli(instance.UpdatedAt).Before(updatedBefore) {
+				continue
+			}
+
+			changed = true
+			if !instance.ReadOnly {
+				delete(desc.Instances, id)
+				continue
+			}
+
+			// Make sure the tombstone wins over the stored entry, even if the clocks are skewed.
+			desc.Instances[id] = InstanceDesc{UpdatedAt: max(now.UnixMilli(), instance.UpdatedAt+1)}
+		}
+
+		if !changed {
+			return nil, false, nil
+		}
+		return desc, true, nil
+	})
+}
+
+// IsReadOnly returns whether the instance is in read-only mode, and since when.
+func (d *Desc) IsReadOnly(instanceID string) (time.Time, bool) {
+	if d == nil {
+		return time.Time{}, false
+	}
+
+	instance, ok := d.Instances[instanceID]
+	if !ok || !instance.ReadOnly {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(instance.ReadOnlySince), true
+}
+
+// Merge implements memberlist.Mergeable. For each instance, the most recently updated entry wins.
+func (d *Desc) Merge(mergeable memberlist.Mergeable, _ bool) (memberlist.Mergeable, error) {
+	if mergeable == nil {
+		return nil, nil
+	}
+
+	other, ok := mergeable.(*Desc)
+	if !ok {
+		return nil, fmt.Errorf("expected *readonly.Desc, got %T", mergeable)
+	}
+	if other == nil {
+		return nil, nil
+	}
+
+	if d.Instances == nil {
+		d.Instances = map[string]InstanceDesc{}
+	}
+
+	changed := NewDesc()
+	for id, instance := range other.Instances {
+		if !isNewer(instance, d.Instances[id]) {
+			continue
+		}
+
+		d.Instances[id] = instance
+		changed.Instances[id] = instance
+	}
+
+	if len(changed.Instances) == 0 {
+		return nil, nil
+	}
+	return changed, nil
+}
+
+// isNewer returns whether a has been updated after b. Entries updated at the same time are
+// ordered by their content, so that merging them is commutative.
+func isNewer(a, b InstanceDesc) bool {
+	if a.UpdatedAt != b.UpdatedAt {
+		return a.UpdatedAt > b.UpdatedAt
+	}
+	if a.ReadOnly != b.ReadOnly {
+		return a.ReadOnly
+	}
+	return a.ReadOnlySince > b.ReadOnlySince
+}
+
+// MergeContent implements memberlist.Mergeable.
+func (d *Desc) MergeContent() []string {
+	result := make([]string, 0, len(d.Instances))
+	for id := range d.Instances {
+		result = append(result, id)
+	}
+	return result
+}
+
+// RemoveTombstones implements memberlist.Mergeable. The instances not in read-only mode are tombstones.
+func (d *Desc) RemoveTombstones(limit time.Time) (total, removed int) {
+	for id, instance := range d.Instances {
+		if instance.ReadOnly {
+			continue
+		}
+
+		if limit.IsZero() || time.UnixMilli(instance.UpdatedAt).Before(limit) {
+			delete(d.Instances, id)
+			removed++
+		} else {
+			total++
+		}
+	}
+	return
+}
+
+// Clone implements memberlist.Mergeable.
+func (d *Desc) Clone() memberlist.Mergeable {
+	return proto.Clone(d).(*Desc)
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package readonly
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDesc_Merge(t *testing.T) {
+	readOnly := InstanceDesc{ReadOnly: true, ReadOnlySince: 1000, UpdatedAt: 1000}
+	notReadOnly := InstanceDesc{ReadOnly: false, UpdatedAt: 2000}
+	other := InstanceDesc{ReadOnly: true, ReadOnlySince: 1500, UpdatedAt: 1500}
+
+	t.Run("the most recently updated entries win", func(t *testing.T) {
+		local := &Desc{Instances: map[string]InstanceDesc{"ingester-1": readOnly, "ingester-2": other}}
+		incoming := &Desc{Instances: map[string]InstanceDesc{"ingester-1": notReadOnly, "ingester-2": readOnly, "ingester-3": other}}
+
+		change, err := local.Merge(incoming, false)
+		require.NoError(t, err)
+		assert.Equal(t, &Desc{Instances: map[string]InstanceDesc{"ingester-1": notReadOnly, "ingester-3": other}}, change)
+		assert.Equal(t, &Desc{Instances: map[string]InstanceDesc{"ingester-1": notReadOnly, "ingester-2": other, "ingester-3": other}}, local)
+
+		// Merging again is a no-op.
+		change, err = local.Merge(incoming, false)
+		require.NoError(t, err)
+		assert.Nil(t, change)
+	})
+
+	t.Run("merging is commutative", func(t *testing.T) {
+		a := func() *Desc {
+			return &Desc{Instances: map[string]InstanceDesc{"ingester-1": readOnly, "ingester-2": notReadOnly}}
+		}
+		b := func() *Desc {
+			return &Desc{Instances: map[string]InstanceDesc{"ingester-1": other, "ingester-2": {ReadOnly: true, ReadOnlySince: 2000, UpdatedAt: 2000}}}
+		}
+
+		ab, ba := a(), b()
+		_, err := ab.Merge(b(), false)
+		require.NoError(t, err)
+		_, err = ba.Merge(a(), false)
+		require.NoError(t, err)
+		assert.Equal(t, ab, ba)
+		assert.Equal(t, other, ab.Instances["ingester-1"])
+		assert.True(t, ab.Instances["ingester-2"].ReadOnly)
+	})
+
+	t.Run("merging into an empty desc", func(t *testing.T) {
+		local := &Desc{}
+		change, err := local.Merge(&Desc{Instances: map[string]InstanceDesc{"ingester-1": readOnly}}, false)
+		require.NoError(t, err)
+		assert.Equal(t, &Desc{Instances: map[string]InstanceDesc{"ingester-1": readOnly}}, change)
+		assert.Equal(t, &Desc{Instances: map[string]InstanceDesc{"ingester-1": readOnly}}, local)
+	})
+}
+
+func TestDesc_RemoveTombstones(t *testing.T) {
+	desc := &Desc{Instances: map[string]InstanceDesc{
+		"read-only":     {ReadOnly: true, ReadOnlySince: 1000, UpdatedAt: 1000},
+		"old-tombstone": {ReadOnly: false, UpdatedAt: 1000},
+		"new-tombstone": {ReadOnly: false, UpdatedAt: 3000},
+	}}
+
+	total, removed := desc.RemoveTombstones(time.UnixMilli(2000))
+	assert.Equal(t, 1, total)
+	assert.Equal(t, 1, removed)
+	assert.ElementsMatch(t, []string{"read-only", "new-tombstone"}, desc.MergeContent())
+
+	total, removed = desc.RemoveTombstones(time.Time{})
+	assert.Equal(t, 0, total)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, []string{"read-only"}, desc.MergeContent())
+}
+
+func TestUpdateAndWatcher(t *testing.T) {
+	ctx := context.Background()
+	client, closer := consul.NewInMemoryClient(GetCodec(), log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+
+	since := time.UnixMilli(time.Now().UnixMilli()).Add(-time.Hour)
+	require.NoError(t, Update(ctx, client, "ingester-1", true, since))
+
+	w := NewWatcher(client, log.NewNopLogger())
+	require.NoError(t, services.StartAndAwaitRunning(ctx, w))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(ctx, w))
+	})
+
+	// The read-only ingesters are known as soon as the watcher is running.
+	readOnlySince, readOnly := w.IsReadOnly("ingester-1")
+	assert.True(t, readOnly)
+	assert.Equal(t, since, readOnlySince)
+
+	_, readOnly = w.IsReadOnly("ingester-2")
+	assert.False(t, readOnly)
+
+	require.NoError(t, Update(ctx, client, "ingester-2", true, since))
+	require.NoError(t, Update(ctx, client, "ingester-1", false, time.Time{}))
+
+	test.Poll(t, time.Second, true, func() interface{} {
+		_, readOnly1 := w.IsReadOnly("ingester-1")
+		_, readOnly2 := w.IsReadOnly("ingester-2")
+		return !readOnly1 && readOnly2
+	})
+}
+
+func TestRemoveLeftInstances(t *testing.T) {
+	ctx := context.Background()
+	client, closer := consul.NewInMemoryClient(GetCodec(), log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+
+	// No-op if the desc doesn't exist yet.
+	require.NoError(t, RemoveLeftInstances(ctx, client, func(string) bool { return false }, time.Now()))
+	value, err := client.Get(ctx, Key)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	now := time.Now()
+	old := now.Add(-time.Hour).UnixMilli()
+	require.NoError(t, client.CAS(ctx, Key, func(interface{}) (interface{}, bool, error) {
+		return &Desc{Instances: map[string]InstanceDesc{
+			"in-ring":           {ReadOnly: true, ReadOnlySince: old, UpdatedAt: old},
+			"left":              {ReadOnly: true, ReadOnlySince: old, UpdatedAt: old},
+			"left-tombstone":    {ReadOnly: false, UpdatedAt: old},
+			"recently-updated":  {ReadOnly: true, ReadOnlySince: old, UpdatedAt: now.UnixMilli()},
+			"recent-tombstone":  {ReadOnly: false, UpdatedAt: now.UnixMilli()},
+			"in-ring-tombstone": {ReadOnly: false, UpdatedAt: old},
+			"updated-in-future": {ReadOnly: true, ReadOnlySince: old, UpdatedAt: now.Add(time.Hour).UnixMilli()},
+		}}, true, nil
+	}))
+
+	inRing := func(instanceID string) bool {
+		return instanceID == "in-ring" || instanceID == "in-ring-tombstone"
+	}
+	require.NoError(t, RemoveLeftInstances(ctx, client, inRing, now.Add(-time.Minute/2)))
+
+	value, err = client.Get(ctx, Key)
+	require.NoError(t, err)
+	desc := value.(*Desc)
+
+	// The read-only mode of the instances which left the ring is removed.
+	assert.ElementsMatch(t, []string{"in-ring", "left", "recently-updated", "recent-tombstone", "in-ring-tombstone", "updated-in-future"}, desc.MergeContent())
+	_, readOnly := desc.IsReadOnly("left")
+	assert.False(t, readOnly)
+	assert.GreaterOrEqual(t, desc.Instances["left"].UpdatedAt, now.UnixMilli())
+	for _, id := range []string{"in-ring", "recently-updated", "updated-in-future"} {
+		_, readOnly := desc.IsReadOnly(id)
+		assert.True(t, readOnly, id)
+	}
+}
@@ -0,0 +1,858 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: readonly.proto
+
+package readonly
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	reflect "reflect"
+	strings "strings"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// Desc holds the read-only mode of the ingesters, stored in the KV store of the ingesters ring.
+type Desc struct {
+	// Instances by instance ID.
+	Instances map[string]InstanceDesc `protobuf:"bytes,1,rep,name=instances,proto3" json:"instances" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *Desc) Reset()      { *m = Desc{} }
+func (*Desc) ProtoMessage() {}
+func (*Desc) Descriptor() ([]byte, []int) {
+	return fileDescriptor_31c36bd20e4bd97c, []int{0}
+}
+func (m *Desc) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Desc) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Desc.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Desc) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Desc.Merge(m, src)
+}
+func (m *Desc) XXX_Size() int {
+	return m.Size()
+}
+func (m *Desc) XXX_DiscardUnknown() {
+	xxx_messageInfo_Desc.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Desc proto.InternalMessageInfo
+
+func (m *Desc) GetInstances() map[string]InstanceDesc {
+	if m != nil {
+		return m.Instances
+	}
+	return nil
+}
+
+type InstanceDesc struct {
+	ReadOnly bool `protobuf:"varint,1,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	// Unix timestamp in milliseconds when the read-only mode has been enabled.
+	ReadOnlySince int64 `protobuf:"varint,2,opt,name=read_only_since,json=readOnlySince,proto3" json:"read_only_since,omitempty"`
+	// Unix timestamp in milliseconds of the last update of this entry. It's used to merge the
+	// entries received from other memberlist members: the most recently updated entry wins.
+	// An entry not in read-only mode is a tombstone, removed once older than the memberlist
+	// tombstone retention.
+	UpdatedAt int64 `protobuf:"varint,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (m *InstanceDesc) Reset()      { *m = InstanceDesc{} }
+func (*InstanceDesc) ProtoMessage() {}
+func (*InstanceDesc) Descriptor() ([]byte, []int) {
+	return fileDescriptor_31c36bd20e4bd97c, []int{1}
+}
+func (m *InstanceDesc) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *InstanceDesc) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_InstanceDesc.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *InstanceDesc) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstanceDesc.Merge(m, src)
+}
+func (m *InstanceDesc) XXX_Size() int {
+	return m.Size()
+}
+func (m *InstanceDesc) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstanceDesc.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstanceDesc proto.InternalMessageInfo
+
+func (m *InstanceDesc) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
+	}
+	return false
+}
+
+func (m *InstanceDesc) GetReadOnlySince() int64 {
+	if m != nil {
+		return m.ReadOnlySince
+	}
+	return 0
+}
+
+func (m *InstanceDesc) GetUpdatedAt() int64 {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Desc)(nil), "readonly.Desc")
+	proto.RegisterMapType((map[string]InstanceDesc)(nil), "readonly.Desc.InstancesEntry")
+	proto.RegisterType((*InstanceDesc)(nil), "readonly.InstanceDesc")
+}
+
+func init() { proto.RegisterFile("readonly.proto", fileDescriptor_31c36bd20e4bd97c) }
+
+var fileDescriptor_31c36bd20e4bd97c = []byte{
+	// 310 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x90, 0x31, 0x4f, 0xc2, 0x40,
+	0x14, 0xc7, 0xef, 0x51, 0x34, 0xf4, 0x50, 0x34, 0x37, 0x98, 0x06, 0xc3, 0x93, 0x30, 0x18, 0x06,
+	0x2d, 0x09, 0x3a, 0x18, 0x37, 0x88, 0x0e, 0x4e, 0x26, 0xd5, 0x9d, 0x94, 0x52, 0x91, 0x88, 0x77,
+	0xa4, 0xbd, 0x9a, 0x74, 0xf3, 0x23, 0xf8, 0x0d, 0x5c, 0xfd, 0x28, 0x8c, 0x8c, 0x4c, 0x46, 0x8e,
+	0xc5, 0x91, 0x8f, 0x60, 0xee, 0x6a, 0x41, 0xb7, 0xf7, 0x7f, 0xff, 0xdf, 0xff, 0xfe, 0xb9, 0x47,
+	0x2b, 0x51, 0xe8, 0x0f, 0x04, 0x1f, 0xa7, 0xee, 0x24, 0x12, 0x52, 0xb0, 0x52, 0xae, 0xab, 0xa7,
+	0xc3, 0x91, 0x7c, 0x4c, 0xfa, 0x6e, 0x20, 0x9e, 0x5b, 0x43, 0x31, 0x14, 0x2d, 0x03, 0xf4, 0x93,
+	0x07, 0xa3, 0x8c, 0x30, 0x53, 0x16, 0x6c, 0xbc, 0x03, 0x2d, 0x5e, 0x85, 0x71, 0xc0, 0x3a, 0xd4,
+	0x1e, 0xf1, 0x58, 0xfa, 0x3c, 0x08, 0x63, 0x07, 0xea, 0x56, 0xb3, 0xdc, 0xae, 0xb9, 0xeb, 0x16,
+	0x8d, 0xb8, 0x37, 0xb9, 0x7f, 0xcd, 0x65, 0x94, 0x76, 0x8b, 0xd3, 0xcf, 0x23, 0xe2, 0x6d, 0x52,
+	0xd5, 0x7b, 0x5a, 0xf9, 0x8f, 0xb0, 0x7d, 0x6a, 0x3d, 0x85, 0xa9, 0x03, 0x75, 0x68, 0xda, 0x9e,
+	0x1e, 0xd9, 0x09, 0xdd, 0x7a, 0xf1, 0xc7, 0x49, 0xe8, 0x14, 0xea, 0xd0, 0x2c, 0xb7, 0x0f, 0x36,
+	0x15, 0x79, 0x54, 0x57, 0x79, 0x19, 0x74, 0x59, 0xb8, 0x80, 0x46, 0x44, 0x77, 0xfe, 0x5a, 0xec,
+	0x90, 0xda, 0x3a, 0xd3, 0xd3, 0x21, 0xf3, 0x72, 0xc9, 0x33, 0xbf, 0xbf, 0xe5, 0xe3, 0x94, 0x1d,
+	0xd3, 0xbd, 0xb5, 0xd9, 0x8b, 0x47, 0x3c, 0xc8, 0x8a, 0x2c, 0x6f, 0x37, 0x47, 0xee, 0xf4, 0x92,
+	0xd5, 0x28, 0x4d, 0x26, 0x03, 0x5f, 0x86, 0x83, 0x9e, 0x2f, 0x1d, 0xcb, 0x20, 0xf6, 0xef, 0xa6,
+	0x23, 0xbb, 0xe7, 0xb3, 0x05, 0x92, 0xf9, 0x02, 0xc9, 0x6a, 0x81, 0xf0, 0xaa, 0x10, 0x3e, 0x14,
+	0xc2, 0x54, 0x21, 0xcc, 0x14, 0xc2, 0x97, 0x42, 0xf8, 0x56, 0x48, 0x56, 0x0a, 0xe1, 0x6d, 0x89,
+	0x64, 0xb6, 0x44, 0x32, 0x5f, 0x22, 0xe9, 0x6f, 0x9b, 0x93, 0x9e, 0xfd, 0x0c, 0x00, 0x67, 0x5f,
+	0x14, 0xbf, 0x9d, 0x01, 0x00, 0x00,
+}
+
+func (this *Desc) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Desc)
+	if !ok {
+		that2, ok := that.(Desc)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Instances) != len(that1.Instances) {
+		return false
+	}
+	for i := range this.Instances {
+		a := this.Instances[i]
+		b := that1.Instances[i]
+		if !(&a).Equal(&b) {
+			return false
+		}
+	}
+	return true
+}
+func (this *InstanceDesc) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstanceDesc)
+	if !ok {
+		that2, ok := that.(InstanceDesc)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.ReadOnly != that1.ReadOnly {
+		return false
+	}
+	if this.ReadOnlySince != that1.ReadOnlySince {
+		return false
+	}
+	if this.UpdatedAt != that1.UpdatedAt {
+		return false
+	}
+	return true
+}
+func (this *Desc) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&readonly.Desc{")
+	keysForInstances := make([]string, 0, len(this.Instances))
+	for k, _ := range this.Instances {
+		keysForInstances = append(keysForInstances, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForInstances)
+	mapStringForInstances := "map[string]InstanceDesc{"
+	for _, k := range keysForInstances {
+		mapStringForInstances += fmt.Sprintf("%#v: %#v,", k, this.Instances[k])
+	}
+	mapStringForInstances += "}"
+	if this.Instances != nil {
+		s = append(s, "Instances: "+mapStringForInstances+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *InstanceDesc) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&readonly.InstanceDesc{")
+	s = append(s, "ReadOnly: "+fmt.Sprintf("%#v", this.ReadOnly)+",\n")
+	s = append(s, "ReadOnlySince: "+fmt.Sprintf("%#v", this.ReadOnlySince)+",\n")
+	s = append(s, "UpdatedAt: "+fmt.Sprintf("%#v", this.UpdatedAt)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringReadonly(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *Desc) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Desc) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Desc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Instances) > 0 {
+		for k := range m.Instances {
+			v := m.Instances[k]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintReadonly(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintReadonly(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintReadonly(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *InstanceDesc) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *InstanceDesc) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *InstanceDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.UpdatedAt != 0 {
+		i = encodeVarintReadonly(dAtA, i, uint64(m.UpdatedAt))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.ReadOnlySince != 0 {
+		i = encodeVarintReadonly(dAtA, i, uint64(m.ReadOnlySince))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReadOnly {
+		i--
+		if m.ReadOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintReadonly(dAtA []byte, offset int, v uint64) int {
+	offset -= sovReadonly(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Desc) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Instances) > 0 {
+		for k, v := range m.Instances {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovReadonly(uint64(len(k))) + 1 + l + sovReadonly(uint64(l))
+			n += mapEntrySize + 1 + sovReadonly(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *InstanceDesc) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReadOnly {
+		n += 2
+	}
+	if m.ReadOnlySince != 0 {
+		n += 1 + sovReadonly(uint64(m.ReadOnlySince))
+	}
+	if m.UpdatedAt != 0 {
+		n += 1 + sovReadonly(uint64(m.UpdatedAt))
+	}
+	return n
+}
+
+func sovReadonly(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozReadonly(x uint64) (n int) {
+	return sovReadonly(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *Desc) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForInstances := make([]string, 0, len(this.Instances))
+	for k, _ := range this.Instances {
+		keysForInstances = append(keysForInstances, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForInstances)
+	mapStringForInstances := "map[string]InstanceDesc{"
+	for _, k := range keysForInstances {
+		mapStringForInstances += fmt.Sprintf("%v: %v,", k, this.Instances[k])
+	}
+	mapStringForInstances += "}"
+	s := strings.Join([]string{`&Desc{`,
+		`Instances:` + mapStringForInstances + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *InstanceDesc) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&InstanceDesc{`,
+		`ReadOnly:` + fmt.Sprintf("%v", this.ReadOnly) + `,`,
+		`ReadOnlySince:` + fmt.Sprintf("%v", this.ReadOnlySince) + `,`,
+		`UpdatedAt:` + fmt.Sprintf("%v", this.UpdatedAt) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringReadonly(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *Desc) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowReadonly
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Desc: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Desc: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Instances", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReadonly
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthReadonly
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthReadonly
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Instances == nil {
+				m.Instances = make(map[string]InstanceDesc)
+			}
+			var mapkey string
+			mapvalue := &InstanceDesc{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowReadonly
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowReadonly
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthReadonly
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthReadonly
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowReadonly
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthReadonly
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthReadonly
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &InstanceDesc{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipReadonly(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthReadonly
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Instances[mapkey] = *mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipReadonly(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthReadonly
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthReadonly
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *InstanceDesc) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowReadonly
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: InstanceDesc: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: InstanceDesc: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReadonly
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ReadOnly = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOnlySince", wireType)
+			}
+			m.ReadOnlySince = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReadonly
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadOnlySince |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdatedAt", wireType)
+			}
+			m.UpdatedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReadonly
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UpdatedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipReadonly(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthReadonly
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthReadonly
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipReadonly(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowReadonly
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowReadonly
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowReadonly
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthReadonly
+			}
+			iNdEx += length
+			if iNdEx < 0 {
+				return 0, ErrInvalidLengthReadonly
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowReadonly
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipReadonly(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+				if iNdEx < 0 {
+					return 0, ErrInvalidLengthReadonly
+				}
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthReadonly = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowReadonly   = fmt.Errorf("proto: integer overflow")
+)
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package readonly
+
+import (
+	"bytes"
+	_ "embed" // Used to embed html template
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/grafana/mimir/pkg/util"
+)
+
+var (
+	//go:embed ring_status.gohtml
+	ringStatusPageHTML     string
+	ringStatusPageTemplate = template.Must(template.New("main").Funcs(template.FuncMap{
+		"mod":        func(i, j int) bool { return i%j == 0 },
+		"humanFloat": func(f float64) string { return fmt.Sprintf("%.3g", f) },
+		"timeOrEmptyString": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format(time.RFC3339Nano)
+		},
+		"durationSince": func(t time.Time) string { return time.Since(t).Truncate(time.Millisecond).String() },
+	}).Parse(ringStatusPageHTML))
+)
+
+type ringStatusPageContents struct {
+	Instances  []ringInstance `json:"shards"`
+	Now        time.Time      `json:"now"`
+	ShowTokens bool           `json:"-"`
+}
+
+type ringInstance struct {
+	ID                  string     `json:"id"`
+	State               string     `json:"state"`
+	Address             string     `json:"address"`
+	HeartbeatTimestamp  time.Time  `json:"timestamp"`
+	RegisteredTimestamp time.Time  `json:"registered_timestamp"`
+	Zone                string     `json:"zone"`
+	Tokens              []uint32   `json:"tokens"`
+	ReadOnly            bool       `json:"read_only"`
+	ReadOnlySince       *time.Time `json:"read_only_since,omitempty"`
+	NumTokens           int        `json:"-"`
+	Ownership           float64    `json:"-"`
+}
+
+// readOnlyChecker tells whether an instance is in read-only mode.
+type readOnlyChecker interface {
+	IsReadOnly(instanceID string) (time.Time, bool)
+}
+
+type ringPageHandler struct {
+	next     http.Handler
+	readOnly readOnlyChecker
+}
+
+// NewRingPageHandler returns the ingesters ring status page, showing which ingesters are in read-only
+// mode. The ring content is served by next, the ring status page of the ring or of the lifecycler,
+// which also handles the requests to forget an instance.
+func NewRingPageHandler(next http.Handler, readOnly readOnlyChecker) http.Handler {
+	return &ringPageHandler{
+		next:     next,
+		readOnly: readOnly,
+	}
+}
+
+func (h *ringPageHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+
+	// Read the ring content in JSON format from the wrapped status page.
+	jsonReq := req.Clone(req.Context())
+	jsonReq.Header.Set("Accept", "application/json")
+	rec := &bufferedResponseWriter{header: http.Header{}, status: http.StatusOK}
+	h.next.ServeHTTP(rec, jsonReq)
+
+	if rec.status != http.StatusOK {
+		for name, values := range rec.header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(rec.status)
+		_, _ = w.Write(rec.body.Bytes())
+		return
+	}
+
+	var contents ringStatusPageContents
+	if err := json.Unmarshal(rec.body.Bytes(), &contents); err != nil {
+		http.Error(w, fmt.Sprintf("unable to decode the ring status: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	ownership := tokensOwnership(contents.Instances)
+	for i := range contents.Instances {
+		instance := &contents.Instances[i]
+		if since, readOnly := h.readOnly.IsReadOnly(instance.ID); readOnly {
+			instance.ReadOnly = true
+			instance.ReadOnlySince = &since
+		}
+		instance.NumTokens = len(instance.Tokens)
+		instance.Ownership = ownership[instance.ID] * 100
+	}
+	contents.ShowTokens = req.URL.Query().Get("tokens") == "true"
+
+	util.RenderHTTPResponse(w, contents, ringStatusPageTemplate, req)
+}
+
+// tokensOwnership returns the fraction of the tokens space owned by each instance.
+func tokensOwnership(instances []ringInstance) map[string]float64 {
+	type tokenOwner struct {
+		token uint32
+		id    string
+	}
+
+	var tokens []tokenOwner
+	for _, instance := range instances {
+		for _, token := range instance.Tokens {
+			tokens = append(tokens, tokenOwner{token: token, id: instance.ID})
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].token < tokens[j].token })
+
+	ownership := make(map[string]float64, len(instances))
+	for i, t := range tokens {
+		// Each token owns the range from the previous token. The first token owns the range
+		// wrapping around the ring from the last token.
+		var owned uint64
+		if i == 0 {
+			owned = uint64(t.token) + math.MaxUint32 + 1 - uint64(tokens[len(tokens)-1].token)
+		} else {
+			owned = uint64(t.token) - uint64(tokens[i-1].token)
+		}
+		ownership[t.id] += float64(owned) / (math.MaxUint32 + 1)
+	}
+	return ownership
+}
+
+// bufferedResponseWriter is a http.ResponseWriter keeping the response in memory.
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header         { return w.header }
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+func (w *bufferedResponseWriter) WriteHeader(status int)      { w.status = status }
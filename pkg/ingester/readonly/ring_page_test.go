@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package readonly
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingPageHandler(t *testing.T) {
+	since := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	forgotten := ""
+
+	ringPage := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			forgotten = req.FormValue("forget")
+			w.WriteHeader(http.StatusFound)
+			return
+		}
+
+		require.Equal(t, "application/json", req.Header.Get("Accept"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"shards":[
+			{"id":"ingester-1","state":"ACTIVE","address":"127.0.0.1","zone":"zone-a","tokens":[100,200]},
+			{"id":"ingester-2","state":"ACTIVE","address":"127.0.0.2","zone":"zone-b","tokens":[300]},
+			{"id":"ingester-3","state":"LEAVING","address":"127.0.0.3","zone":"zone-c","tokens":[]}
+		],"now":"2024-01-01T12:00:00Z"}`))
+	})
+
+	handler := NewRingPageHandler(ringPage, readOnlyInstances{"ingester-2": since})
+
+	t.Run("JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ingester/ring", nil)
+		req.Header.Set("Accept", "application/json")
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		require.Equal(t, http.StatusOK, res.Code)
+
+		var contents ringStatusPageContents
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &contents))
+		require.Len(t, contents.Instances, 3)
+
+		assert.False(t, contents.Instances[0].ReadOnly)
+		assert.Nil(t, contents.Instances[0].ReadOnlySince)
+		assert.True(t, contents.Instances[1].ReadOnly)
+		assert.Equal(t, since, contents.Instances[1].ReadOnlySince.UTC())
+		assert.Equal(t, "ACTIVE", contents.Instances[1].State)
+		assert.False(t, contents.Instances[2].ReadOnly)
+		assert.Equal(t, "LEAVING", contents.Instances[2].State)
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/ingester/ring", nil))
+		require.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), "<th>Read-only</th>")
+		assert.Contains(t, res.Body.String(), "since 2024-01-01T10:00:00Z")
+	})
+
+	t.Run("forget", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/ingester/ring?forget=ingester-3", nil))
+		assert.Equal(t, http.StatusFound, res.Code)
+		assert.Equal(t, "ingester-3", forgotten)
+	})
+}
+
+func TestTokensOwnership(t *testing.T) {
+	ownership := tokensOwnership([]ringInstance{
+		{ID: "ingester-1", Tokens: []uint32{1 << 30, 3 << 30}},
+		{ID: "ingester-2", Tokens: []uint32{2 << 30}},
+		{ID: "ingester-3"},
+	})
+
+	assert.InDelta(t, 0.75, ownership["ingester-1"], 1e-9)
+	assert.InDelta(t, 0.25, ownership["ingester-2"], 1e-9)
+	assert.Zero(t, ownership["ingester-3"])
+
+	// A single token owns the whole ring.
+	ownership = tokensOwnership([]ringInstance{{ID: "ingester-1", Tokens: []uint32{math.MaxUint32}}})
+	assert.InDelta(t, 1, ownership["ingester-1"], 1e-9)
+}
+
+type readOnlyInstances map[string]time.Time
+
+func (r readOnlyInstances) IsReadOnly(instanceID string) (time.Time, bool) {
+	since, ok := r[instanceID]
+	return since, ok
+}
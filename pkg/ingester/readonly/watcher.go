@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package readonly
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/services"
+	"github.com/pkg/errors"
+)
+
+// Watcher keeps track of the ingesters in read-only mode, watching the Desc stored in the KV store.
+type Watcher struct {
+	services.Service
+
+	client kv.Client
+	logger log.Logger
+
+	mtx  sync.RWMutex
+	desc *Desc
+}
+
+// NewWatcher makes a new Watcher.
+func NewWatcher(client kv.Client, logger log.Logger) *Watcher {
+	w := &Watcher{
+		client: client,
+		logger: logger,
+	}
+	w.Service = services.NewBasicService(w.starting, w.running, nil)
+	return w
+}
+
+func (w *Watcher) starting(ctx context.Context) error {
+	// Load the read-only ingesters before the watcher is running, so that the read-only
+	// ingesters are known as soon as the ring is used.
+	value, err := w.client.Get(ctx, Key)
+	if err != nil {
+		return errors.Wrap(err, "unable to initialise read-only ingesters")
+	}
+
+	w.update(value)
+	return nil
+}
+
+func (w *Watcher) running(ctx context.Context) error {
+	w.client.WatchKey(ctx, Key, func(value interface{}) bool {
+		w.update(value)
+		return true
+	})
+	return nil
+}
+
+func (w *Watcher) update(value interface{}) {
+	desc, ok := value.(*Desc)
+	if value != nil && !ok {
+		level.Warn(w.logger).Log("msg", "unexpected read-only ingesters value in the KV store", "type", fmt.Sprintf("%T", value))
+		return
+	}
+
+	w.mtx.Lock()
+	w.desc = desc
+	w.mtx.Unlock()
+}
+
+// IsReadOnly returns whether the instance is in read-only mode, and since when.
+func (w *Watcher) IsReadOnly(instanceID string) (time.Time, bool) {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+
+	return w.desc.IsReadOnly(instanceID)
+}
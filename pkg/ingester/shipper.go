@@ -22,9 +22,12 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb/fileutil"
 	"github.com/thanos-io/objstore"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 )
@@ -69,6 +72,23 @@ type shipper struct {
 	metrics     *shipperMetrics
 	bucket      objstore.Bucket
 	source      block.SourceType
+
+	// exemplars is used to persist the exemplars of each block before uploading it. Exemplars
+	// are not persisted if nil.
+	exemplars storage.ExemplarQueryable
+
+	// metadata is used to persist the metric metadata history of each block before uploading it.
+	// Metadata is not persisted if nil.
+	metadata metadataHistorySource
+}
+
+// metadataHistorySource provides the history of the metric metadata to persist in the blocks.
+type metadataHistorySource interface {
+	// metadataHistory returns the metadata observed within the [mint, maxt] time range.
+	metadataHistory(mint, maxt int64) []block.MetricMetadata
+
+	// purgeHistory removes the metadata last observed before the input time, once persisted.
+	purgeHistory(before int64)
 }
 
 // newShipper creates a new uploader that detects new TSDB blocks in dir and uploads them to
@@ -82,6 +102,8 @@ func newShipper(
 	dir string,
 	bucket objstore.Bucket,
 	source block.SourceType,
+	exemplars storage.ExemplarQueryable,
+	metadata metadataHistorySource,
 ) *shipper {
 	if logger == nil {
 		logger = log.NewNopLogger()
@@ -95,6 +117,8 @@ func newShipper(
 		bucket:      bucket,
 		metrics:     metrics,
 		source:      source,
+		exemplars:   exemplars,
+		metadata:    metadata,
 	}
 }
 
@@ -169,6 +193,12 @@ func (s *shipper) Sync(ctx context.Context) (shipped int, err error) {
 		shipped++
 		s.metrics.uploads.Inc()
 		s.metrics.lastSuccessfulUploadTime.SetToCurrentTime()
+
+		// Blocks are shipped from the oldest, so the metadata observed before the end of this block has been
+		// persisted, unless a previous block failed to upload and will be retried.
+		if s.metadata != nil && uploadErrs == 0 {
+			s.metadata.purgeHistory(m.MaxTime)
+		}
 	}
 
 	if err := writeShipperMetaFile(s.logger, s.dir, meta); err != nil {
@@ -197,10 +227,77 @@ func (s *shipper) upload(ctx context.Context, meta *block.Meta) error {
 		meta.Thanos.Labels[mimir_tsdb.OutOfOrderExternalLabel] = mimir_tsdb.OutOfOrderExternalLabelValue
 	}
 
+	if s.exemplars != nil {
+		// The block is uploaded without exemplars if they can't be persisted, to not block the shipping.
+		if err := s.persistExemplars(ctx, meta, blockDir); err != nil {
+			level.Warn(s.logger).Log("msg", "failed to persist block exemplars", "block", meta.ULID, "err", err)
+		}
+	}
+
+	if s.metadata != nil {
+		// The block is uploaded without metadata if it can't be persisted, to not block the shipping.
+		if err := persistMetadata(s.metadata, meta, blockDir); err != nil {
+			level.Warn(s.logger).Log("msg", "failed to persist block metadata", "block", meta.ULID, "err", err)
+		}
+	}
+
 	// Upload block with custom metadata.
 	return block.Upload(ctx, s.logger, s.bucket, blockDir, meta)
 }
 
+// persistExemplars writes the exemplars within the time range of the block to the exemplars file
+// in the block directory, unless the file already exists or there are no exemplars.
+func (s *shipper) persistExemplars(ctx context.Context, meta *block.Meta, blockDir string) error {
+	if _, err := os.Stat(filepath.Join(blockDir, block.ExemplarsFilename)); err == nil {
+		return nil
+	}
+
+	q, err := s.exemplars.ExemplarQuerier(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The block max time is exclusive, while the exemplars query range is inclusive.
+	results, err := q.Select(meta.MinTime, meta.MaxTime-1, []*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".+"),
+	})
+	if err != nil {
+		return err
+	}
+
+	series := make([]mimirpb.TimeSeries, 0, len(results))
+	for _, r := range results {
+		if len(r.Exemplars) == 0 {
+			continue
+		}
+		series = append(series, mimirpb.TimeSeries{
+			Labels:    mimirpb.FromLabelsToLabelAdapters(r.SeriesLabels),
+			Exemplars: mimirpb.FromExemplarsToExemplarProtos(r.Exemplars),
+		})
+	}
+	if len(series) == 0 {
+		return nil
+	}
+
+	return block.WriteExemplarsFile(blockDir, series)
+}
+
+// persistMetadata writes the metric metadata observed within the time range of the block to the metadata
+// file in the block directory, unless the file already exists or there is no metadata.
+func persistMetadata(source metadataHistorySource, meta *block.Meta, blockDir string) error {
+	if _, err := os.Stat(filepath.Join(blockDir, block.MetadataFilename)); err == nil {
+		return nil
+	}
+
+	// The block max time is exclusive.
+	metadata := source.metadataHistory(meta.MinTime, meta.MaxTime-1)
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	return block.WriteMetadataFile(blockDir, metadata)
+}
+
 // blockMetasFromOldest returns the block meta of each block found in dir
 // sorted by minTime asc.
 func (s *shipper) blockMetasFromOldest() (metas []*block.Meta, _ error) {
@@ -20,10 +20,13 @@ import (
 	"github.com/grafana/dskit/concurrency"
 	"github.com/oklog/ulid"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/stretchr/testify/require"
 	"github.com/thanos-io/objstore"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/storage/bucket/filesystem"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
@@ -51,7 +54,7 @@ func TestShipper(t *testing.T) {
 	logger := log.NewLogfmtLogger(logs)
 	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
-	s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource)
+	s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource, nil, nil)
 
 	t.Run("no shipper file yet", func(t *testing.T) {
 		// No shipper file = nothing is reported as shipped.
@@ -193,7 +196,7 @@ func TestShipper_DeceivingUploadErrors(t *testing.T) {
 	logger := log.NewLogfmtLogger(os.Stderr)
 	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
-	s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource)
+	s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource, nil, nil)
 
 	// Create and upload a block
 	id1 := ulid.MustNew(1, nil)
@@ -259,7 +262,7 @@ func TestIterBlockMetas(t *testing.T) {
 	}.WriteToDir(log.NewNopLogger(), path.Join(dir, id3.String())))
 	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
-	shipper := newShipper(nil, overrides, "", newShipperMetrics(nil), dir, nil, block.TestSource)
+	shipper := newShipper(nil, overrides, "", newShipperMetrics(nil), dir, nil, block.TestSource, nil, nil)
 	metas, err := shipper.blockMetasFromOldest()
 	require.NoError(t, err)
 	require.Equal(t, sort.SliceIsSorted(metas, func(i, j int) bool {
@@ -273,7 +276,7 @@ func TestShipperAddsSegmentFiles(t *testing.T) {
 	inmemory := objstore.NewInMemBucket()
 	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
-	s := newShipper(nil, overrides, "", newShipperMetrics(nil), dir, inmemory, block.TestSource)
+	s := newShipper(nil, overrides, "", newShipperMetrics(nil), dir, inmemory, block.TestSource, nil, nil)
 
 	id := ulid.MustNew(1, nil)
 	blockDir := path.Join(dir, id.String())
@@ -417,7 +420,7 @@ func TestShipper_AddOOOLabel(t *testing.T) {
 			}
 			overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), validation.NewMockTenantLimits(tenantLimits))
 			require.NoError(t, err)
-			s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource)
+			s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource, nil, nil)
 
 			createBlock(t, blocksDir, tc.meta.ULID, tc.meta)
 
@@ -439,3 +442,144 @@ func metaWithOOOHint(meta block.Meta) block.Meta {
 	meta.Compaction.SetOutOfOrder()
 	return meta
 }
+
+func TestShipper_PersistExemplars(t *testing.T) {
+	ctx := context.Background()
+	blocksDir := t.TempDir()
+	bkt := objstore.NewInMemBucket()
+
+	series := labels.FromStrings(labels.MetricName, "request_duration_seconds")
+	exemplars, err := tsdb.NewCircularExemplarStorage(10, tsdb.NewExemplarMetrics(nil))
+	require.NoError(t, err)
+	for _, ts := range []int64{500, 1000, 1500, 2000} {
+		require.NoError(t, exemplars.AddExemplar(series, exemplar.Exemplar{
+			Labels: labels.FromStrings("trace_id", fmt.Sprintf("trace-%d", ts)),
+			Value:  1,
+			Ts:     ts,
+			HasTs:  true,
+		}))
+	}
+
+	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	s := newShipper(log.NewNopLogger(), overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource, exemplars, nil)
+
+	// Create a block with exemplars, and a block without.
+	withExemplars := ulid.MustNew(1, nil)
+	withoutExemplars := ulid.MustNew(2, nil)
+	for id, minTime := range map[ulid.ULID]int64{withExemplars: 1000, withoutExemplars: 3000} {
+		createBlock(t, blocksDir, id, block.Meta{
+			BlockMeta: tsdb.BlockMeta{
+				ULID:    id,
+				MinTime: minTime,
+				MaxTime: minTime + 1000,
+				Version: 1,
+				Stats: tsdb.BlockStats{
+					NumSamples: 100, // Shipper checks if number of samples is greater than 0.
+				},
+			},
+		})
+	}
+
+	uploaded, err := s.Sync(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, uploaded)
+
+	// Only the exemplars within the block time range (max time is exclusive) should have been uploaded.
+	meta, err := block.DownloadMeta(ctx, log.NewNopLogger(), bkt, withExemplars)
+	require.NoError(t, err)
+	require.True(t, meta.HasExemplars())
+
+	r, err := bkt.Get(ctx, path.Join(withExemplars.String(), block.ExemplarsFilename))
+	require.NoError(t, err)
+	var actual []mimirpb.TimeSeries
+	require.NoError(t, block.ReadExemplars(r, func(s mimirpb.TimeSeries) error {
+		actual = append(actual, s)
+		return nil
+	}))
+	require.Equal(t, []mimirpb.TimeSeries{{
+		Labels: mimirpb.FromLabelsToLabelAdapters(series),
+		Exemplars: []mimirpb.Exemplar{
+			{Labels: []mimirpb.LabelAdapter{{Name: "trace_id", Value: "trace-1000"}}, Value: 1, TimestampMs: 1000},
+			{Labels: []mimirpb.LabelAdapter{{Name: "trace_id", Value: "trace-1500"}}, Value: 1, TimestampMs: 1500},
+		},
+	}}, actual)
+
+	meta, err = block.DownloadMeta(ctx, log.NewNopLogger(), bkt, withoutExemplars)
+	require.NoError(t, err)
+	require.False(t, meta.HasExemplars())
+
+	exists, err := bkt.Exists(ctx, path.Join(withoutExemplars.String(), block.ExemplarsFilename))
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+type metadataHistoryMock struct {
+	history []block.MetricMetadata
+	purged  []int64
+}
+
+func (m *metadataHistoryMock) metadataHistory(mint, maxt int64) []block.MetricMetadata {
+	return block.FilterMetadata(m.history, mint, maxt, "")
+}
+
+func (m *metadataHistoryMock) purgeHistory(before int64) {
+	m.purged = append(m.purged, before)
+}
+
+func TestShipper_PersistMetadata(t *testing.T) {
+	ctx := context.Background()
+	blocksDir := t.TempDir()
+	bkt := objstore.NewInMemBucket()
+
+	metadata := &metadataHistoryMock{history: []block.MetricMetadata{
+		{Metric: "requests_total", Type: "counter", Help: "Total requests.", MinTime: 500, MaxTime: 1200},
+		{Metric: "requests_total", Type: "counter", Help: "Total number of requests.", MinTime: 1500, MaxTime: 2500},
+	}}
+
+	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	s := newShipper(log.NewNopLogger(), overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource, nil, metadata)
+
+	// Create a block with metadata, and a block without.
+	withMetadata := ulid.MustNew(1, nil)
+	withoutMetadata := ulid.MustNew(2, nil)
+	for id, minTime := range map[ulid.ULID]int64{withMetadata: 1000, withoutMetadata: 3000} {
+		createBlock(t, blocksDir, id, block.Meta{
+			BlockMeta: tsdb.BlockMeta{
+				ULID:    id,
+				MinTime: minTime,
+				MaxTime: minTime + 1000,
+				Version: 1,
+				Stats: tsdb.BlockStats{
+					NumSamples: 100, // Shipper checks if number of samples is greater than 0.
+				},
+			},
+		})
+	}
+
+	uploaded, err := s.Sync(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, uploaded)
+
+	// Only the metadata observed within the block time range (max time is exclusive) should have been uploaded.
+	meta, err := block.DownloadMeta(ctx, log.NewNopLogger(), bkt, withMetadata)
+	require.NoError(t, err)
+	require.True(t, meta.HasMetadata())
+
+	r, err := bkt.Get(ctx, path.Join(withMetadata.String(), block.MetadataFilename))
+	require.NoError(t, err)
+	actual, err := block.ReadMetadata(r)
+	require.NoError(t, err)
+	require.Equal(t, []block.MetricMetadata{
+		{Metric: "requests_total", Type: "counter", Help: "Total requests.", MinTime: 1000, MaxTime: 1200},
+		{Metric: "requests_total", Type: "counter", Help: "Total number of requests.", MinTime: 1500, MaxTime: 1999},
+	}, actual)
+
+	meta, err = block.DownloadMeta(ctx, log.NewNopLogger(), bkt, withoutMetadata)
+	require.NoError(t, err)
+	require.False(t, meta.HasMetadata())
+
+	// The history has been purged after each block has been shipped.
+	require.Equal(t, []int64{2000, 4000}, metadata.purged)
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	dskit_metrics "github.com/grafana/dskit/metrics"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// The estimated size, in bytes, of the in-memory TSDB data structures. The actual size depends on the Go
+// runtime and the TSDB implementation details, so these are meant to be good enough to compare tenants
+// and to protect the ingester from running out of memory, not to be exact.
+const (
+	// estimatedSeriesBytes is the size of a head series (memSeries), including its entries in the
+	// head series maps, excluding its labels and chunks.
+	estimatedSeriesBytes = 256
+
+	// estimatedLabelOverheadBytes is the size of a series label, in addition to its name and value.
+	estimatedLabelOverheadBytes = 2
+
+	// estimatedPostingsEntryBytes is the size of a series reference in the postings list of one of its labels.
+	estimatedPostingsEntryBytes = 8
+
+	// estimatedHeadChunkBytes is the size of the open head chunk of a series, which is kept in memory
+	// until it's full and m-mapped to disk.
+	estimatedHeadChunkBytes = 256
+
+	// estimatedMmappedChunkBytes is the size of the in-memory reference to a head chunk m-mapped to disk.
+	estimatedMmappedChunkBytes = 32
+
+	// estimatedOOOSampleBytes is the size of an out-of-order sample, which is kept uncompressed in memory
+	// until the out-of-order chunk of the series is full and m-mapped to disk.
+	estimatedOOOSampleBytes = 32
+)
+
+// tenantMemoryStats is the estimated memory, in bytes, of the in-memory series of a tenant.
+type tenantMemoryStats struct {
+	// Series is the memory of the series, including their labels.
+	Series int64
+	// Postings is the memory of the postings lists of the series labels.
+	Postings int64
+	// HeadChunks is the memory of the head chunks, including the references to the m-mapped ones.
+	HeadChunks int64
+	// OOOChunks is the memory of the out-of-order samples not m-mapped yet.
+	OOOChunks int64
+}
+
+// Total returns the total estimated memory.
+func (s tenantMemoryStats) Total() int64 {
+	return s.Series + s.Postings + s.HeadChunks + s.OOOChunks
+}
+
+// estimatedSeriesLabelsBytes returns the estimated memory of a series with the input labels, and of the
+// postings referencing it.
+func estimatedSeriesLabelsBytes(lbls labels.Labels) (seriesBytes, postingsBytes int64) {
+	seriesBytes = estimatedSeriesBytes
+	lbls.Range(func(l labels.Label) {
+		seriesBytes += int64(len(l.Name) + len(l.Value) + estimatedLabelOverheadBytes)
+		postingsBytes += estimatedPostingsEntryBytes
+	})
+	return seriesBytes, postingsBytes
+}
+
+// addSeriesMemory updates the estimated memory of the series and postings of the tenant, and of the ingester.
+func (u *userTSDB) addSeriesMemory(seriesBytes, postingsBytes int64) {
+	u.seriesBytes.Add(seriesBytes)
+	u.postingsBytes.Add(postingsBytes)
+	u.instanceMemoryBytes.Add(seriesBytes + postingsBytes)
+}
+
+// setChunksMemory updates the estimated memory of the chunks of the tenant, and of the ingester.
+func (u *userTSDB) setChunksMemory(headChunksBytes, oooChunksBytes int64) {
+	prevHead := u.headChunksBytes.Swap(headChunksBytes)
+	prevOOO := u.oooChunksBytes.Swap(oooChunksBytes)
+	u.instanceMemoryBytes.Add(headChunksBytes - prevHead + oooChunksBytes - prevOOO)
+}
+
+// memoryStats returns the estimated memory of the in-memory series of the tenant.
+func (u *userTSDB) memoryStats() tenantMemoryStats {
+	return tenantMemoryStats{
+		Series:     u.seriesBytes.Load(),
+		Postings:   u.postingsBytes.Load(),
+		HeadChunks: u.headChunksBytes.Load(),
+		OOOChunks:  u.oooChunksBytes.Load(),
+	}
+}
+
+// releaseMemory removes the estimated memory of the tenant from the ingester's one. It's called when the TSDB is closed.
+func (u *userTSDB) releaseMemory() {
+	u.instanceMemoryBytes.Sub(u.memoryStats().Total())
+}
+
+// updateChunksMemory refreshes the estimated memory of the chunks of the tenant from the TSDB head metrics,
+// because the head chunks are created and m-mapped by the TSDB without notifying the ingester.
+//
+// Each series has at most one open head chunk, while the other head chunks are m-mapped. Out-of-order samples
+// are kept in memory until the out-of-order chunk of their series reaches oooCapMax samples, and are removed
+// from memory by the head compaction, so the out-of-order samples in memory are estimated from the ones
+// appended since the last head truncation, capped to oooCapMax per series.
+//
+// This function must not be called concurrently.
+func (u *userTSDB) updateChunksMemory(oooCapMax int64) error {
+	if u.tsdbRegistry == nil {
+		return nil
+	}
+
+	mfm, err := dskit_metrics.NewMetricFamilyMapFromGatherer(u.tsdbRegistry)
+	if err != nil {
+		return err
+	}
+
+	var (
+		numSeries        = int64(u.Head().NumSeries())
+		numChunks        = int64(mfm.SumGauges("prometheus_tsdb_head_chunks"))
+		truncations      = mfm.SumCounters("prometheus_tsdb_head_truncations_total")
+		oooSamplesTotal  = mfm.SumCounters("prometheus_tsdb_head_out_of_order_samples_appended_total")
+		openChunks       = min(numSeries, numChunks)
+		headChunksBytes  = openChunks*estimatedHeadChunkBytes + (numChunks-openChunks)*estimatedMmappedChunkBytes
+		oooSamplesMemory int64
+	)
+
+	if truncations != u.lastHeadTruncations {
+		u.lastHeadTruncations = truncations
+		u.oooSamplesAtLastTruncation = oooSamplesTotal
+	}
+	oooSamplesMemory = min(int64(oooSamplesTotal-u.oooSamplesAtLastTruncation), numSeries*oooCapMax)
+
+	u.setChunksMemory(headChunksBytes, max(0, oooSamplesMemory)*estimatedOOOSampleBytes)
+	return nil
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/util"
+)
+
+func TestEstimatedSeriesLabelsBytes(t *testing.T) {
+	seriesBytes, postingsBytes := estimatedSeriesLabelsBytes(labels.EmptyLabels())
+	assert.Equal(t, int64(estimatedSeriesBytes), seriesBytes)
+	assert.Equal(t, int64(0), postingsBytes)
+
+	seriesBytes, postingsBytes = estimatedSeriesLabelsBytes(labels.FromStrings(labels.MetricName, "up", "job", "test"))
+	assert.Equal(t, int64(estimatedSeriesBytes+len("__name__up")+len("jobtest")+2*estimatedLabelOverheadBytes), seriesBytes)
+	assert.Equal(t, int64(2*estimatedPostingsEntryBytes), postingsBytes)
+}
+
+func TestIngester_EstimatedMemory(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	reg := prometheus.NewPedanticRegistry()
+	i, err := prepareIngesterWithBlocksStorage(t, cfg, reg)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), i)
+	})
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	now := util.TimeToMillis(time.Now())
+	series := []labels.Labels{
+		labels.FromStrings(labels.MetricName, "test", "pod", "a"),
+		labels.FromStrings(labels.MetricName, "test", "pod", "b"),
+	}
+
+	var expectedSeriesBytes, expectedPostingsBytes int64
+	for _, lbls := range series {
+		req, _, _, _ := mockWriteRequest(t, lbls, 1, now)
+		_, err := i.Push(ctx, req)
+		require.NoError(t, err)
+
+		seriesBytes, postingsBytes := estimatedSeriesLabelsBytes(lbls)
+		expectedSeriesBytes += seriesBytes
+		expectedPostingsBytes += postingsBytes
+	}
+
+	db := i.getTSDB(userID)
+	require.NotNil(t, db)
+
+	// The memory of the chunks is unknown until it's refreshed.
+	assert.Equal(t, tenantMemoryStats{Series: expectedSeriesBytes, Postings: expectedPostingsBytes}, db.memoryStats())
+	assert.Equal(t, expectedSeriesBytes+expectedPostingsBytes, i.memoryBytes.Load())
+
+	i.updateEstimatedMemory()
+
+	expected := tenantMemoryStats{
+		Series:     expectedSeriesBytes,
+		Postings:   expectedPostingsBytes,
+		HeadChunks: 2 * estimatedHeadChunkBytes,
+	}
+	assert.Equal(t, expected, db.memoryStats())
+	assert.Equal(t, expected.Total(), i.memoryBytes.Load())
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_ingester_estimated_memory_bytes The estimated memory, in bytes, of the in-memory series per user, including their labels, postings and chunks.
+		# TYPE cortex_ingester_estimated_memory_bytes gauge
+		cortex_ingester_estimated_memory_bytes{user="1"} `+fmt.Sprint(expected.Total())+`
+	`), "cortex_ingester_estimated_memory_bytes"))
+
+	// Once the series are compacted and removed from the head, their memory is released.
+	i.compactBlocks(context.Background(), true, math.MaxInt64, nil)
+	i.updateEstimatedMemory()
+
+	assert.Equal(t, tenantMemoryStats{}, db.memoryStats())
+	assert.Equal(t, int64(0), i.memoryBytes.Load())
+}
+
+func TestIngester_PerUserEstimatedMemoryLimit(t *testing.T) {
+	limits := defaultLimitsTestConfig()
+	limits.MaxGlobalEstimatedMemoryPerUser = 1
+
+	reg := prometheus.NewPedanticRegistry()
+	i, err := prepareIngesterWithBlocksStorageAndLimits(t, defaultIngesterTestConfig(t), limits, "", reg)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), i)
+	})
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	now := util.TimeToMillis(time.Now())
+
+	// The first series is accepted because the tenant has no memory in use yet.
+	req, _, _, _ := mockWriteRequest(t, labels.FromStrings(labels.MetricName, "test", "pod", "a"), 1, now)
+	_, err = i.Push(ctx, req)
+	require.NoError(t, err)
+
+	// Samples for the existing series are still accepted, while new series are rejected.
+	req, _, _, _ = mockWriteRequest(t, labels.FromStrings(labels.MetricName, "test", "pod", "a"), 2, now+1)
+	_, err = i.Push(ctx, req)
+	require.NoError(t, err)
+
+	req, _, _, _ = mockWriteRequest(t, labels.FromStrings(labels.MetricName, "test", "pod", "b"), 1, now)
+	_, err = i.Push(ctx, req)
+	require.ErrorAs(t, err, &perUserMemoryLimitReachedError{})
+	checkIngesterError(t, err, badData, true)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+		# TYPE cortex_discarded_samples_total counter
+		cortex_discarded_samples_total{group="",reason="per_user_memory_limit",user="1"} 1
+	`), "cortex_discarded_samples_total"))
+}
@@ -28,6 +28,9 @@ type tenantStats struct {
 	MinTime string
 	MaxTime string
 
+	// Memory is the estimated memory of the in-memory series of the tenant.
+	Memory tenantMemoryStats
+
 	Warning string
 }
 
@@ -85,6 +88,7 @@ func (i *Ingester) TenantsHandler(w http.ResponseWriter, req *http.Request) {
 		s.MinTime = formatMillisTime(db.Head().MinTime())
 		maxMillis := db.Head().MaxTime()
 		s.MaxTime = formatMillisTime(maxMillis)
+		s.Memory = db.memoryStats()
 
 		if maxMillis-nowMillis > i.limits.CreationGracePeriod(t).Milliseconds() {
 			s.Warning = "TSDB Head max timestamp too far in the future"
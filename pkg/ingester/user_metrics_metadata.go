@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/mimir/pkg/ingester/client"
 	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 )
 
 // userMetricsMetadata allows metric metadata of a tenant to be held by the ingester.
@@ -26,17 +27,26 @@ type userMetricsMetadata struct {
 	mtx              sync.RWMutex
 	metricToMetadata map[string]metricMetadataSet
 
+	// history keeps the time range within which each metadata has been observed, until it is
+	// persisted in the blocks. It is not purged after the metadata retain period, and it's nil
+	// if the history is not kept.
+	history map[mimirpb.MetricMetadata]metadataObservation
+
 	errorSamplers ingesterErrSamplers
 }
 
-func newMetadataMap(l *Limiter, m *ingesterMetrics, errorSamplers ingesterErrSamplers, userID string) *userMetricsMetadata {
-	return &userMetricsMetadata{
+func newMetadataMap(l *Limiter, m *ingesterMetrics, errorSamplers ingesterErrSamplers, userID string, keepHistory bool) *userMetricsMetadata {
+	mm := &userMetricsMetadata{
 		metricToMetadata: map[string]metricMetadataSet{},
 		limiter:          l,
 		metrics:          m,
 		errorSamplers:    errorSamplers,
 		userID:           userID,
 	}
+	if keepHistory {
+		mm.history = map[mimirpb.MetricMetadata]metadataObservation{}
+	}
+	return mm
 }
 
 func (mm *userMetricsMetadata) add(metric string, metadata *mimirpb.MetricMetadata) error {
@@ -69,10 +79,53 @@ func (mm *userMetricsMetadata) add(metric string, metadata *mimirpb.MetricMetada
 		mm.metrics.memMetadataCreatedTotal.WithLabelValues(mm.userID).Inc()
 	}
 
-	mm.metricToMetadata[metric][*metadata] = time.Now()
+	now := time.Now()
+	mm.metricToMetadata[metric][*metadata] = now
+
+	if mm.history != nil {
+		ts := now.UnixMilli()
+		if o, ok := mm.history[*metadata]; ok {
+			o.maxTime = ts
+			mm.history[*metadata] = o
+		} else {
+			mm.history[*metadata] = metadataObservation{minTime: ts, maxTime: ts}
+		}
+	}
 	return nil
 }
 
+// metadataHistory returns the metadata observed within the [mint, maxt] time range, with the
+// observation time range clamped to it.
+func (mm *userMetricsMetadata) metadataHistory(mint, maxt int64) []block.MetricMetadata {
+	mm.mtx.RLock()
+	defer mm.mtx.RUnlock()
+
+	result := make([]block.MetricMetadata, 0, len(mm.history))
+	for m, o := range mm.history {
+		result = append(result, block.MetricMetadata{
+			Metric:  m.MetricFamilyName,
+			Type:    string(mimirpb.MetricMetadataMetricTypeToMetricType(m.Type)),
+			Help:    m.Help,
+			Unit:    m.Unit,
+			MinTime: o.minTime,
+			MaxTime: o.maxTime,
+		})
+	}
+	return block.FilterMetadata(result, mint, maxt, "")
+}
+
+// purgeHistory removes the metadata history last observed before the input time, in milliseconds.
+func (mm *userMetricsMetadata) purgeHistory(before int64) {
+	mm.mtx.Lock()
+	defer mm.mtx.Unlock()
+
+	for m, o := range mm.history {
+		if o.maxTime < before {
+			delete(mm.history, m)
+		}
+	}
+}
+
 // If deadline is zero, all metadata is purged.
 func (mm *userMetricsMetadata) purge(deadline time.Time) {
 	mm.mtx.Lock()
@@ -130,6 +183,11 @@ func (mm *userMetricsMetadata) toClientMetadata(req *client.MetricsMetadataReque
 
 type metricMetadataSet map[mimirpb.MetricMetadata]time.Time
 
+// metadataObservation is the time range, in milliseconds, within which a metadata has been observed.
+type metadataObservation struct {
+	minTime, maxTime int64
+}
+
 // If deadline is zero time, all metrics are purged.
 func (mms metricMetadataSet) purge(deadline time.Time) int {
 	var deleted int
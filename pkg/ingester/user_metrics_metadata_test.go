@@ -93,7 +93,7 @@ func TestUserMetricsMetadata(t *testing.T) {
 				nil,
 			)
 
-			mm := newMetadataMap(limiter, metrics, errorSamplers, "test")
+			mm := newMetadataMap(limiter, metrics, errorSamplers, "test", false)
 
 			// Attempt to add all metadata
 			for _, i := range testData.inputMetadata {
@@ -149,7 +149,7 @@ func TestUserMetricsMetadataRequest(t *testing.T) {
 		nil,
 	)
 
-	mm := newMetadataMap(limiter, metrics, newIngesterErrSamplers(0), "test")
+	mm := newMetadataMap(limiter, metrics, newIngesterErrSamplers(0), "test", false)
 
 	inputMetadata := []mimirpb.MetricMetadata{
 		{Type: mimirpb.COUNTER, MetricFamilyName: "test_metric_1", Help: "foo"},
@@ -261,3 +261,53 @@ func TestUserMetricsMetadataRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestUserMetricsMetadataHistory(t *testing.T) {
+	ring := &ringCountMock{}
+	ring.On("InstancesCount").Return(1)
+	ring.On("ZonesCount").Return(1)
+
+	limits, err := validation.NewOverrides(validation.Limits{}, nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, ring, 1, false)
+
+	metrics := newIngesterMetrics(
+		prometheus.NewPedanticRegistry(),
+		true,
+		func() *InstanceLimits { return nil },
+		nil,
+		nil,
+	)
+
+	mm := newMetadataMap(limiter, metrics, newIngesterErrSamplers(0), "test", true)
+
+	start := time.Now().UnixMilli()
+	require.NoError(t, mm.add("test_metric", &mimirpb.MetricMetadata{Type: mimirpb.COUNTER, MetricFamilyName: "test_metric", Help: "foo"}))
+	require.NoError(t, mm.add("test_metric", &mimirpb.MetricMetadata{Type: mimirpb.COUNTER, MetricFamilyName: "test_metric", Help: "foo"}))
+	require.NoError(t, mm.add("test_metric", &mimirpb.MetricMetadata{Type: mimirpb.GAUGE, MetricFamilyName: "test_metric", Help: "bar", Unit: "seconds"}))
+	end := time.Now().UnixMilli()
+
+	// The history is kept even when the metadata is purged after the retain period.
+	mm.purge(time.Time{})
+	assert.Empty(t, mm.metricToMetadata)
+
+	history := mm.metadataHistory(start, end)
+	require.Len(t, history, 2)
+	for _, m := range history {
+		assert.Equal(t, "test_metric", m.Metric)
+		assert.GreaterOrEqual(t, m.MinTime, start)
+		assert.LessOrEqual(t, m.MaxTime, end)
+		assert.LessOrEqual(t, m.MinTime, m.MaxTime)
+	}
+	assert.ElementsMatch(t, []string{"counter/foo/", "gauge/bar/seconds"}, []string{
+		history[0].Type + "/" + history[0].Help + "/" + history[0].Unit,
+		history[1].Type + "/" + history[1].Help + "/" + history[1].Unit,
+	})
+
+	// Metadata not observed within the requested time range is not returned.
+	assert.Empty(t, mm.metadataHistory(end+1, end+1000))
+
+	// Metadata last observed before the purge time is removed from the history.
+	mm.purgeHistory(end + 1)
+	assert.Empty(t, mm.metadataHistory(start, end))
+}
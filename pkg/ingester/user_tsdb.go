@@ -90,9 +90,25 @@ type userTSDB struct {
 	limiter        *Limiter
 
 	instanceSeriesCount *atomic.Int64 // Shared across all userTSDB instances created by ingester.
+	instanceMemoryBytes *atomic.Int64 // Shared across all userTSDB instances created by ingester.
 	instanceLimitsFn    func() *InstanceLimits
 	instanceErrors      *prometheus.CounterVec
 
+	// Number of in-memory series which haven't received samples for longer than the series eviction idle timeout,
+	// periodically updated from the active series. Each new series admitted by evicting them is charged in
+	// seriesAdmittedByEviction, until a TSDB head compaction actually removes the idle series from memory.
+	evictableSeries          atomic.Int64
+	seriesAdmittedByEviction atomic.Int64
+	seriesEvictionRequested  atomic.Bool
+	seriesEvictionDryRun     *prometheus.CounterVec
+
+	// Time of the last TSDB head compaction run to evict idle series. Only accessed by the compaction loop.
+	lastSeriesEviction time.Time
+
+	// Time when the TSDB has been opened. The series loaded from disk are not tracked by the active series
+	// until they receive samples.
+	openedAt time.Time
+
 	stateMtx                                     sync.RWMutex
 	state                                        tsdbState
 	inFlightAppends                              sync.WaitGroup // Increased with stateMtx read lock held.
@@ -122,6 +138,23 @@ type userTSDB struct {
 	// Cached shipped blocks.
 	shippedBlocksMtx sync.Mutex
 	shippedBlocks    map[ulid.ULID]time.Time
+
+	// Estimated memory, in bytes, of the in-memory series. The memory of the series and postings is updated
+	// when series are created and deleted, while the memory of the chunks is periodically refreshed.
+	seriesBytes     atomic.Int64
+	postingsBytes   atomic.Int64
+	headChunksBytes atomic.Int64
+	oooChunksBytes  atomic.Int64
+
+	// Created timestamps of the in-memory series, used to inject zero samples at the created timestamps.
+	createdTimestamps *seriesCreatedTimestamps
+
+	// Registry of the TSDB metrics, used to estimate the memory of the chunks.
+	tsdbRegistry prometheus.Gatherer
+
+	// Used to estimate the out-of-order samples in memory. Only accessed by updateChunksMemory.
+	lastHeadTruncations        float64
+	oooSamplesAtLastTruncation float64
 }
 
 func (u *userTSDB) Appender(ctx context.Context) storage.Appender {
@@ -272,12 +305,24 @@ func (u *userTSDB) PreCreation(metric labels.Labels) error {
 			return errMaxInMemorySeriesReached
 		}
 	}
+	if gl != nil && gl.MaxEstimatedMemory > 0 {
+		if memory := u.instanceMemoryBytes.Load(); memory >= gl.MaxEstimatedMemory {
+			u.instanceErrors.WithLabelValues(reasonIngesterMaxEstimatedMemory).Inc()
+			return errMaxEstimatedMemoryReached
+		}
+	}
 
 	// Total series limit.
-	if !u.limiter.IsWithinMaxSeriesPerUser(u.userID, int(u.Head().NumSeries())) {
+	numSeries := int(u.Head().NumSeries())
+	if !u.limiter.IsWithinMaxSeriesPerUser(u.userID, numSeries) && !u.admitSeriesByEviction() {
 		return globalerror.MaxSeriesPerUser
 	}
 
+	// Total estimated memory limit.
+	if !u.limiter.IsWithinMaxEstimatedMemoryPerUser(u.userID, u.memoryStats().Total()) {
+		return globalerror.MaxEstimatedMemoryPerUser
+	}
+
 	// Series per metric name limit.
 	metricName, err := extract.MetricNameFromLabels(metric)
 	if err != nil {
@@ -292,6 +337,7 @@ func (u *userTSDB) PreCreation(metric labels.Labels) error {
 
 func (u *userTSDB) PostCreation(metric labels.Labels) {
 	u.instanceSeriesCount.Inc()
+	u.addSeriesMemory(estimatedSeriesLabelsBytes(metric))
 
 	metricName, err := extract.MetricNameFromLabels(metric)
 	if err != nil {
@@ -301,10 +347,35 @@ func (u *userTSDB) PostCreation(metric labels.Labels) {
 	u.seriesInMetric.increaseSeriesForMetric(metricName)
 }
 
+// admitSeriesByEviction returns whether a new series can be created even if the series limit has been reached,
+// because there are enough idle series to evict to make room for it. Each admitted series is charged against the
+// idle series, so that no more series are admitted than the ones which will be evicted. The idle series are
+// evicted asynchronously.
+func (u *userTSDB) admitSeriesByEviction() bool {
+	for {
+		admitted := u.seriesAdmittedByEviction.Load()
+		if admitted >= u.evictableSeries.Load() {
+			return false
+		}
+
+		u.seriesEvictionRequested.Store(true)
+		if u.limiter.limits.SeriesEvictionDryRun(u.userID) {
+			u.seriesEvictionDryRun.WithLabelValues(u.userID).Inc()
+			return false
+		}
+		if u.seriesAdmittedByEviction.CompareAndSwap(admitted, admitted+1) {
+			return true
+		}
+	}
+}
+
 func (u *userTSDB) PostDeletion(metrics map[chunks.HeadSeriesRef]labels.Labels) {
 	u.instanceSeriesCount.Sub(int64(len(metrics)))
 
 	for _, lbls := range metrics {
+		seriesBytes, postingsBytes := estimatedSeriesLabelsBytes(lbls)
+		u.addSeriesMemory(-seriesBytes, -postingsBytes)
+
 		metricName, err := extract.MetricNameFromLabels(lbls)
 		if err != nil {
 			// This should never happen because it has already been checked in PreCreation().
@@ -314,6 +385,10 @@ func (u *userTSDB) PostDeletion(metrics map[chunks.HeadSeriesRef]labels.Labels)
 	}
 
 	u.activeSeries.PostDeletion(metrics)
+
+	if u.createdTimestamps != nil {
+		u.createdTimestamps.delete(metrics)
+	}
 }
 
 // blocksToDelete filters the input blocks and returns the blocks which are safe to be deleted from the ingester.
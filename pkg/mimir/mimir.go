@@ -36,7 +36,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
-	"github.com/prometheus/prometheus/promql"
 	prom_storage "github.com/prometheus/prometheus/storage"
 	"go.opentelemetry.io/otel"
 	"go.uber.org/atomic"
@@ -56,8 +55,10 @@ import (
 	frontendv1 "github.com/grafana/mimir/pkg/frontend/v1"
 	"github.com/grafana/mimir/pkg/ingester"
 	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/ingester/readonly"
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier"
+	"github.com/grafana/mimir/pkg/querier/engine"
 	"github.com/grafana/mimir/pkg/querier/tenantfederation"
 	querier_worker "github.com/grafana/mimir/pkg/querier/worker"
 	"github.com/grafana/mimir/pkg/ruler"
@@ -251,7 +252,7 @@ func (c *Config) Validate(log log.Logger) error {
 	if err := c.IngesterClient.Validate(); err != nil {
 		return errors.Wrap(err, "invalid ingester_client config")
 	}
-	if err := c.Ingester.Validate(); err != nil {
+	if err := c.Ingester.Validate(c.BlocksStorage.TSDB); err != nil {
 		return errors.Wrap(err, "invalid ingester config")
 	}
 	if err := c.Worker.Validate(); err != nil {
@@ -299,6 +300,12 @@ func (c *Config) ValidateLimits(limits validation.Limits) error {
 	if err := c.Querier.ValidateLimits(limits); err != nil {
 		return errors.Wrap(err, "invalid limits config for querier")
 	}
+	if err := c.Ingester.ValidateLimits(limits); err != nil {
+		return errors.Wrap(err, "invalid limits config for ingester")
+	}
+	if err := c.Compactor.ValidateLimits(limits); err != nil {
+		return errors.Wrap(err, "invalid limits config for compactor")
+	}
 	return nil
 }
 
@@ -678,6 +685,7 @@ type Mimir struct {
 	API                      *api.API
 	Server                   *server.Server
 	Ring                     *ring.Ring
+	IngesterReadOnly         *readonly.Watcher
 	TenantLimits             validation.TenantLimits
 	Overrides                *validation.Overrides
 	ActiveGroupsCleanup      *util.ActiveGroupsCleanupService
@@ -689,7 +697,7 @@ type Mimir struct {
 	QuerierQueryable         prom_storage.SampleAndChunkQueryable
 	ExemplarQueryable        prom_storage.ExemplarQueryable
 	MetadataSupplier         querier.MetadataSupplier
-	QuerierEngine            *promql.Engine
+	QuerierEngine            engine.QueryEngine
 	QueryFrontendTripperware querymiddleware.Tripperware
 	QueryFrontendCodec       querymiddleware.Codec
 	Ruler                    *ruler.Ruler
@@ -699,6 +707,7 @@ type Mimir struct {
 	Compactor                *compactor.MultitenantCompactor
 	StoreGateway             *storegateway.StoreGateway
 	StoreQueryable           prom_storage.Queryable
+	DeleteRequestsLoader     *tsdb.DeleteRequestsLoader
 	MemberlistKV             *memberlist.KVInitService
 	ActivityTracker          *activitytracker.ActivityTracker
 	Vault                    *vault.Vault
@@ -734,6 +743,7 @@ func New(cfg Config, reg prometheus.Registerer) (*Mimir, error) {
 			"/frontend.Frontend/NotifyClientShutdown",
 			"/ruler.Ruler/SyncRules",
 			"/schedulerpb.SchedulerForFrontend/FrontendLoop",
+			"/schedulerpb.SchedulerForFrontend/ConnectedFrontends",
 			"/schedulerpb.SchedulerForQuerier/QuerierLoop",
 			"/schedulerpb.SchedulerForQuerier/NotifyQuerierShutdown",
 		}, cfg.NoAuthTenant)
@@ -820,9 +830,9 @@ func (t *Mimir) Run() error {
 	// implementation provided by module.Ring over the BasicLifecycler
 	// available in ingesters
 	if t.Ring != nil {
-		t.API.RegisterRing(t.Ring)
+		t.API.RegisterRing(readonly.NewRingPageHandler(t.Ring, t.IngesterReadOnly))
 	} else if t.Ingester != nil {
-		t.API.RegisterRing(t.Ingester.RingHandler())
+		t.API.RegisterRing(readonly.NewRingPageHandler(t.Ingester.RingHandler(), t.IngesterReadOnly))
 	}
 
 	// get all services, create service manager and tell it to start
@@ -460,6 +460,95 @@ func TestConfig_ValidateLimits(t *testing.T) {
 			}(),
 			hasError: true,
 		},
+		{
+			name:       "series eviction idle timeout greater than the active series idle timeout should return error",
+			testConfig: newDefaultConfig(),
+			limitsConfig: func() validation.Limits {
+				limits := newDefaultConfig().LimitsConfig
+				limits.SeriesEvictionIdleTimeout = model.Duration(time.Hour)
+				return limits
+			}(),
+			hasError: true,
+		},
+		{
+			name: "series eviction with active series tracking disabled should return error",
+			testConfig: func() *Config {
+				c := newDefaultConfig()
+				c.Ingester.ActiveSeriesMetrics.Enabled = false
+				return c
+			}(),
+			limitsConfig: func() validation.Limits {
+				limits := newDefaultConfig().LimitsConfig
+				limits.SeriesEvictionIdleTimeout = model.Duration(5 * time.Minute)
+				return limits
+			}(),
+			hasError: true,
+		},
+		{
+			name:       "delete requests grace period not greater than query-ingesters-within should return error",
+			testConfig: newDefaultConfig(),
+			limitsConfig: func() validation.Limits {
+				limits := newDefaultConfig().LimitsConfig
+				limits.QueryIngestersWithin = model.Duration(24 * time.Hour)
+				return limits
+			}(),
+			hasError: true,
+		},
+		{
+			name: "delete requests grace period greater than query-ingesters-within should pass validation",
+			testConfig: func() *Config {
+				c := newDefaultConfig()
+				c.Compactor.DeleteRequestsGracePeriod = 48 * time.Hour
+				return c
+			}(),
+			limitsConfig: func() validation.Limits {
+				limits := newDefaultConfig().LimitsConfig
+				limits.QueryIngestersWithin = model.Duration(24 * time.Hour)
+				return limits
+			}(),
+		},
+		{
+			name: "sample-level HA dedup with active series tracking disabled should return error",
+			testConfig: func() *Config {
+				c := newDefaultConfig()
+				c.Ingester.ActiveSeriesMetrics.Enabled = false
+				return c
+			}(),
+			limitsConfig: func() validation.Limits {
+				limits := newDefaultConfig().LimitsConfig
+				limits.HASampleLevelDedupWindow = model.Duration(5 * time.Minute)
+				return limits
+			}(),
+			hasError: true,
+		},
+		{
+			name:       "sample-level HA dedup window greater than the active series idle timeout should return error",
+			testConfig: newDefaultConfig(),
+			limitsConfig: func() validation.Limits {
+				limits := newDefaultConfig().LimitsConfig
+				limits.HASampleLevelDedupWindow = model.Duration(time.Hour)
+				return limits
+			}(),
+			hasError: true,
+		},
+		{
+			name:       "sample-level HA dedup window within the active series idle timeout should pass validation",
+			testConfig: newDefaultConfig(),
+			limitsConfig: func() validation.Limits {
+				limits := newDefaultConfig().LimitsConfig
+				limits.HASampleLevelDedupWindow = model.Duration(5 * time.Minute)
+				return limits
+			}(),
+		},
+		{
+			name:       "series eviction idle timeout within the active series idle timeout should pass validation",
+			testConfig: newDefaultConfig(),
+			limitsConfig: func() validation.Limits {
+				limits := newDefaultConfig().LimitsConfig
+				limits.SeriesEvictionIdleTimeout = model.Duration(5 * time.Minute)
+				return limits
+			}(),
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			err := tc.testConfig.ValidateLimits(tc.limitsConfig)
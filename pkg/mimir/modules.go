@@ -17,6 +17,7 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/dns"
 	httpgrpc_server "github.com/grafana/dskit/httpgrpc/server"
+	"github.com/grafana/dskit/kv"
 	"github.com/grafana/dskit/kv/memberlist"
 	"github.com/grafana/dskit/modules"
 	"github.com/grafana/dskit/ring"
@@ -41,9 +42,11 @@ import (
 	"github.com/grafana/mimir/pkg/distributor"
 	"github.com/grafana/mimir/pkg/flusher"
 	"github.com/grafana/mimir/pkg/frontend"
+	"github.com/grafana/mimir/pkg/frontend/activequeries"
 	"github.com/grafana/mimir/pkg/frontend/querymiddleware"
 	"github.com/grafana/mimir/pkg/frontend/transport"
 	"github.com/grafana/mimir/pkg/ingester"
+	"github.com/grafana/mimir/pkg/ingester/readonly"
 	"github.com/grafana/mimir/pkg/querier"
 	"github.com/grafana/mimir/pkg/querier/engine"
 	"github.com/grafana/mimir/pkg/querier/tenantfederation"
@@ -51,6 +54,7 @@ import (
 	"github.com/grafana/mimir/pkg/ruler"
 	"github.com/grafana/mimir/pkg/scheduler"
 	"github.com/grafana/mimir/pkg/storage/bucket"
+	"github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storegateway"
 	"github.com/grafana/mimir/pkg/storegateway/indexheader"
 	"github.com/grafana/mimir/pkg/usagestats"
@@ -69,6 +73,7 @@ const (
 	API                        string = "api"
 	SanityCheck                string = "sanity-check"
 	Ring                       string = "ring"
+	IngesterReadOnly           string = "ingester-read-only"
 	RuntimeConfig              string = "runtime-config"
 	Overrides                  string = "overrides"
 	OverridesExporter          string = "overrides-exporter"
@@ -82,6 +87,7 @@ const (
 	Querier                    string = "querier"
 	Queryable                  string = "queryable"
 	StoreQueryable             string = "store-queryable"
+	DeleteRequestsLoader       string = "delete-requests-loader"
 	QueryFrontend              string = "query-frontend"
 	QueryFrontendTripperware   string = "query-frontend-tripperware"
 	RulerStorage               string = "ruler-storage"
@@ -314,6 +320,16 @@ func (t *Mimir) initServer() (services.Service, error) {
 	return s, nil
 }
 
+func (t *Mimir) initIngesterReadOnly() (serv services.Service, err error) {
+	client, err := kv.NewClient(t.Cfg.Ingester.IngesterRing.KVStore, readonly.GetCodec(), kv.RegistererWithKVName(prometheus.WrapRegistererWithPrefix("cortex_", t.Registerer), "ingester-read-only"), util_log.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	t.IngesterReadOnly = readonly.NewWatcher(client, util_log.Logger)
+	return t.IngesterReadOnly, nil
+}
+
 func (t *Mimir) initRing() (serv services.Service, err error) {
 	t.Ring, err = ring.New(t.Cfg.Ingester.IngesterRing.ToRingConfig(), "ingester", ingester.IngesterRingKey, util_log.Logger, prometheus.WrapRegistererWithPrefix("cortex_", t.Registerer))
 	if err != nil {
@@ -412,6 +428,7 @@ func (t *Mimir) initOverridesExporter() (services.Service, error) {
 func (t *Mimir) initDistributorService() (serv services.Service, err error) {
 	t.Cfg.Distributor.DistributorRing.Common.ListenPort = t.Cfg.Server.GRPCListenPort
 	t.Cfg.Distributor.InstanceLimitsFn = distributorInstanceLimits(t.RuntimeConfig)
+	t.Cfg.Distributor.ReadOnlyIngesters = t.IngesterReadOnly
 
 	if t.Cfg.Querier.ShuffleShardingIngestersEnabled {
 		t.Cfg.Distributor.ShuffleShardingLookbackPeriod = t.Cfg.BlocksStorage.TSDB.Retention
@@ -452,11 +469,14 @@ func (t *Mimir) initQueryable() (serv services.Service, err error) {
 
 	// Create a querier queryable and PromQL engine
 	t.QuerierQueryable, t.ExemplarQueryable, t.QuerierEngine = querier.New(
-		t.Cfg.Querier, t.Overrides, t.Distributor, t.StoreQueryable, querierRegisterer, util_log.Logger, t.ActivityTracker,
+		t.Cfg.Querier, t.Overrides, t.Distributor, t.StoreQueryable, t.DeleteRequestsLoader, querierRegisterer, util_log.Logger, t.ActivityTracker,
 	)
 
 	// Use the distributor to return metric metadata by default
 	t.MetadataSupplier = t.Distributor
+	if store, ok := t.StoreQueryable.(querier.MetricMetadataStore); ok && t.Cfg.Querier.QueryMetadataFromStoreGateways {
+		t.MetadataSupplier = querier.NewStoreMetadataSupplier(t.Distributor, store)
+	}
 
 	// Register the default endpoints that are always enabled for the querier module
 	t.API.RegisterQueryable(t.Distributor)
@@ -595,6 +615,18 @@ func (t *Mimir) initStoreQueryable() (services.Service, error) {
 	return q, nil
 }
 
+// initDeleteRequestsLoader instantiates the loader of the tenants' delete requests, which are used by queriers
+// and rulers to filter out the deleted series, and by query-frontends to invalidate the cached results.
+func (t *Mimir) initDeleteRequestsLoader() (services.Service, error) {
+	bkt, err := bucket.NewClient(context.Background(), t.Cfg.BlocksStorage.Bucket, "delete-requests-loader", util_log.Logger, t.Registerer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize delete requests bucket client: %v", err)
+	}
+
+	t.DeleteRequestsLoader = tsdb.NewDeleteRequestsLoader(bkt, time.Minute, time.Hour, util_log.Logger)
+	return t.DeleteRequestsLoader, nil
+}
+
 func (t *Mimir) initActiveGroupsCleanupService() (services.Service, error) {
 	t.ActiveGroupsCleanup = util.NewActiveGroupsCleanupService(3*time.Minute, t.Cfg.Ingester.ActiveSeriesMetrics.IdleTimeout, t.Cfg.MaxSeparateMetricsGroupsPerUser)
 	return t.ActiveGroupsCleanup, nil
@@ -608,6 +640,7 @@ func (t *Mimir) initIngesterService() (serv services.Service, err error) {
 	t.Cfg.Ingester.IngesterRing.ListenPort = t.Cfg.Server.GRPCListenPort
 	t.Cfg.Ingester.StreamTypeFn = ingesterChunkStreaming(t.RuntimeConfig)
 	t.Cfg.Ingester.InstanceLimitsFn = ingesterInstanceLimits(t.RuntimeConfig)
+	t.Cfg.Ingester.IngesterClientConfig = t.Cfg.IngesterClient
 	t.tsdbIngesterConfig()
 
 	t.Ingester, err = ingester.New(t.Cfg.Ingester, t.Overrides, t.ActiveGroupsCleanup, t.Registerer, util_log.Logger)
@@ -663,6 +696,7 @@ func (t *Mimir) initQueryFrontendTripperware() (serv services.Service, err error
 		t.QueryFrontendCodec,
 		querymiddleware.PrometheusResponseExtractor{},
 		engine.NewPromQLEngineOptions(t.Cfg.Querier.EngineConfig, t.ActivityTracker, util_log.Logger, promqlEngineRegisterer),
+		t.DeleteRequestsLoader,
 		t.Registerer,
 	)
 	if err != nil {
@@ -686,16 +720,25 @@ func (t *Mimir) initQueryFrontend() (serv services.Service, err error) {
 
 	handler := transport.NewHandler(t.Cfg.Frontend.Handler, roundTripper, util_log.Logger, t.Registerer, t.ActivityTracker)
 	t.API.RegisterQueryFrontendHandler(handler, t.BuildInfoHandler)
+	if slowQueryLogHandler := handler.SlowQueryLogHandler(); slowQueryLogHandler != nil {
+		t.API.RegisterQueryFrontendSlowQueryLog(slowQueryLogHandler)
+	}
 
-	var frontendSvc services.Service
+	var (
+		frontendSvc services.Service
+		// Without query-schedulers, the query-frontends can't find each other to list and cancel the active queries.
+		activeQueriesPeers activequeries.Peers
+	)
 	if frontendV1 != nil {
 		t.API.RegisterQueryFrontend1(frontendV1)
 		t.Frontend = frontendV1
 		frontendSvc = frontendV1
 	} else if frontendV2 != nil {
-		t.API.RegisterQueryFrontend2(frontendV2)
+		t.API.RegisterQueryFrontend2(frontendV2, handler.ActiveQueries())
 		frontendSvc = frontendV2
+		activeQueriesPeers = frontendV2
 	}
+	t.API.RegisterQueryFrontendActiveQueries(activequeries.NewHandler(handler.ActiveQueries(), activeQueriesPeers, util_log.Logger))
 
 	w := services.NewFailureWatcher()
 	return services.NewBasicService(func(_ context.Context) error {
@@ -771,7 +814,7 @@ func (t *Mimir) initRuler() (serv services.Service, err error) {
 		// TODO: Consider wrapping logger to differentiate from querier module logger
 		rulerRegisterer := prometheus.WrapRegistererWith(prometheus.Labels{"engine": "ruler"}, t.Registerer)
 
-		queryable, _, eng := querier.New(t.Cfg.Querier, t.Overrides, t.Distributor, t.StoreQueryable, rulerRegisterer, util_log.Logger, t.ActivityTracker)
+		queryable, _, eng := querier.New(t.Cfg.Querier, t.Overrides, t.Distributor, t.StoreQueryable, t.DeleteRequestsLoader, rulerRegisterer, util_log.Logger, t.ActivityTracker)
 		queryable = querier.NewErrorTranslateQueryableWithFn(queryable, ruler.WrapQueryableErrors)
 
 		if t.Cfg.Ruler.TenantFederation.Enabled {
@@ -785,15 +828,15 @@ func (t *Mimir) initRuler() (serv services.Service, err error) {
 
 			federatedQueryable = tenantfederation.NewQueryable(queryable, bypassForSingleQuerier, t.Cfg.TenantFederation.MaxConcurrent, util_log.Logger)
 
-			regularQueryFunc := rules.EngineQueryFunc(eng, queryable)
-			federatedQueryFunc := rules.EngineQueryFunc(eng, federatedQueryable)
+			regularQueryFunc := ruler.EngineQueryFunc(eng, queryable)
+			federatedQueryFunc := ruler.EngineQueryFunc(eng, federatedQueryable)
 
 			embeddedQueryable = federatedQueryable
 			queryFunc = ruler.TenantFederationQueryFunc(regularQueryFunc, federatedQueryFunc)
 
 		} else {
 			embeddedQueryable = queryable
-			queryFunc = rules.EngineQueryFunc(eng, queryable)
+			queryFunc = ruler.EngineQueryFunc(eng, queryable)
 		}
 	}
 	managerFactory := ruler.DefaultTenantManagerFactory(
@@ -899,7 +942,7 @@ func (t *Mimir) initStoreGateway() (serv services.Service, err error) {
 
 func (t *Mimir) initMemberlistKV() (services.Service, error) {
 	// Append to the list of codecs instead of overwriting the value to allow third parties to inject their own codecs.
-	t.Cfg.MemberlistKV.Codecs = append(t.Cfg.MemberlistKV.Codecs, ring.GetCodec())
+	t.Cfg.MemberlistKV.Codecs = append(t.Cfg.MemberlistKV.Codecs, ring.GetCodec(), readonly.GetCodec())
 
 	dnsProviderReg := prometheus.WrapRegistererWithPrefix(
 		"cortex_",
@@ -972,6 +1015,7 @@ func (t *Mimir) setupModuleManager() error {
 	mm.RegisterModule(API, t.initAPI, modules.UserInvisibleModule)
 	mm.RegisterModule(RuntimeConfig, t.initRuntimeConfig, modules.UserInvisibleModule)
 	mm.RegisterModule(MemberlistKV, t.initMemberlistKV, modules.UserInvisibleModule)
+	mm.RegisterModule(IngesterReadOnly, t.initIngesterReadOnly, modules.UserInvisibleModule)
 	mm.RegisterModule(Ring, t.initRing, modules.UserInvisibleModule)
 	mm.RegisterModule(Overrides, t.initOverrides, modules.UserInvisibleModule)
 	mm.RegisterModule(OverridesExporter, t.initOverridesExporter)
@@ -984,6 +1028,7 @@ func (t *Mimir) setupModuleManager() error {
 	mm.RegisterModule(Queryable, t.initQueryable, modules.UserInvisibleModule)
 	mm.RegisterModule(Querier, t.initQuerier)
 	mm.RegisterModule(StoreQueryable, t.initStoreQueryable, modules.UserInvisibleModule)
+	mm.RegisterModule(DeleteRequestsLoader, t.initDeleteRequestsLoader, modules.UserInvisibleModule)
 	mm.RegisterModule(QueryFrontendTripperware, t.initQueryFrontendTripperware, modules.UserInvisibleModule)
 	mm.RegisterModule(QueryFrontend, t.initQueryFrontend)
 	mm.RegisterModule(RulerStorage, t.initRulerStorage, modules.UserInvisibleModule)
@@ -1006,18 +1051,19 @@ func (t *Mimir) setupModuleManager() error {
 		API:                      {Server},
 		MemberlistKV:             {API, Vault},
 		RuntimeConfig:            {API},
-		Ring:                     {API, RuntimeConfig, MemberlistKV, Vault},
+		IngesterReadOnly:         {RuntimeConfig, MemberlistKV, Vault},
+		Ring:                     {API, RuntimeConfig, MemberlistKV, Vault, IngesterReadOnly},
 		Overrides:                {RuntimeConfig},
 		OverridesExporter:        {Overrides, MemberlistKV, Vault},
 		Distributor:              {DistributorService, API, ActiveGroupsCleanupService, Vault},
-		DistributorService:       {Ring, Overrides, Vault},
+		DistributorService:       {Ring, Overrides, Vault, IngesterReadOnly},
 		Ingester:                 {IngesterService, API, ActiveGroupsCleanupService, Vault},
-		IngesterService:          {Overrides, RuntimeConfig, MemberlistKV},
+		IngesterService:          {Overrides, RuntimeConfig, MemberlistKV, IngesterReadOnly},
 		Flusher:                  {Overrides, API},
 		Queryable:                {Overrides, DistributorService, Ring, API, StoreQueryable, MemberlistKV},
 		Querier:                  {TenantFederation, Vault},
-		StoreQueryable:           {Overrides, MemberlistKV},
-		QueryFrontendTripperware: {API, Overrides},
+		StoreQueryable:           {Overrides, MemberlistKV, DeleteRequestsLoader},
+		QueryFrontendTripperware: {API, Overrides, DeleteRequestsLoader},
 		QueryFrontend:            {QueryFrontendTripperware, MemberlistKV, Vault},
 		QueryScheduler:           {API, Overrides, MemberlistKV, Vault},
 		Ruler:                    {DistributorService, StoreQueryable, RulerStorage, Vault},
@@ -44,6 +44,97 @@ func (h Histogram) IsGauge() bool {
 	return h.ResetHint == Histogram_GAUGE
 }
 
+// CustomBucketsSchema is the schema of the native histograms with custom buckets, whose bucket boundaries are
+// the custom values of the histogram instead of being derived from an exponential schema.
+const CustomBucketsSchema int32 = -53
+
+// IsCustomBuckets returns whether the histogram is a native histogram with custom buckets.
+func (h Histogram) IsCustomBuckets() bool {
+	return h.Schema == CustomBucketsSchema
+}
+
+// BucketCount returns the number of positive and negative buckets of the histogram.
+func (h Histogram) BucketCount() int {
+	if h.IsFloatHistogram() {
+		return len(h.NegativeCounts) + len(h.PositiveCounts)
+	}
+	return len(h.NegativeDeltas) + len(h.PositiveDeltas)
+}
+
+// ReduceResolution reduces the resolution of the histogram to the target schema, merging the buckets
+// which fall into the same bucket at the target schema. It does nothing if the target schema is not
+// lower than the histogram's schema.
+func (h *Histogram) ReduceResolution(targetSchema int32) {
+	if targetSchema >= h.Schema {
+		return
+	}
+
+	if h.IsFloatHistogram() {
+		h.PositiveSpans, h.PositiveCounts = reduceBuckets(h.PositiveSpans, h.PositiveCounts, h.Schema, targetSchema, false)
+		h.NegativeSpans, h.NegativeCounts = reduceBuckets(h.NegativeSpans, h.NegativeCounts, h.Schema, targetSchema, false)
+	} else {
+		h.PositiveSpans, h.PositiveDeltas = reduceBuckets(h.PositiveSpans, h.PositiveDeltas, h.Schema, targetSchema, true)
+		h.NegativeSpans, h.NegativeDeltas = reduceBuckets(h.NegativeSpans, h.NegativeDeltas, h.Schema, targetSchema, true)
+	}
+	h.Schema = targetSchema
+}
+
+// reduceBuckets merges the buckets described by spans into the buckets of the target schema. If deltas is true,
+// the input and output buckets are delta-encoded, otherwise they hold absolute counts.
+func reduceBuckets[T int64 | float64](spans []BucketSpan, buckets []T, originSchema, targetSchema int32, deltas bool) ([]BucketSpan, []T) {
+	var (
+		targetSpans   []BucketSpan
+		targetBuckets []T
+		lastTargetIdx int32
+		count         T
+		bucketIdx     int32
+		bucketPos     int
+	)
+
+	for _, span := range spans {
+		bucketIdx += span.Offset
+		for i := uint32(0); i < span.Length; i++ {
+			if deltas {
+				count += buckets[bucketPos]
+			} else {
+				count = buckets[bucketPos]
+			}
+			bucketPos++
+
+			// The bucket with index idx has upper bound base^idx, so the target bucket is the one
+			// including the bucket's upper bound.
+			targetIdx := ((bucketIdx - 1) >> (originSchema - targetSchema)) + 1
+
+			switch {
+			case len(targetSpans) == 0:
+				targetSpans = append(targetSpans, BucketSpan{Offset: targetIdx, Length: 1})
+				targetBuckets = append(targetBuckets, count)
+			case targetIdx == lastTargetIdx:
+				targetBuckets[len(targetBuckets)-1] += count
+			case targetIdx == lastTargetIdx+1:
+				targetSpans[len(targetSpans)-1].Length++
+				targetBuckets = append(targetBuckets, count)
+			default:
+				targetSpans = append(targetSpans, BucketSpan{Offset: targetIdx - lastTargetIdx - 1, Length: 1})
+				targetBuckets = append(targetBuckets, count)
+			}
+
+			lastTargetIdx = targetIdx
+			bucketIdx++
+		}
+	}
+
+	if deltas {
+		var prev T
+		for i, curr := range targetBuckets {
+			targetBuckets[i] = curr - prev
+			prev = curr
+		}
+	}
+
+	return targetSpans, targetBuckets
+}
+
 // UnsafeByteSlice is an alternative to the default handling of []byte values in protobuf messages.
 // Unlike the default protobuf implementation, when unmarshalling, UnsafeByteSlice holds a reference to the
 // subslice of the original protobuf-encoded bytes, rather than copying them from the encoded buffer to a second slice.
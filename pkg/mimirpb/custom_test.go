@@ -8,6 +8,7 @@ package mimirpb
 import (
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -86,3 +87,130 @@ func TestIsFloatHistogram(t *testing.T) {
 		})
 	}
 }
+
+// customValuesMessage only holds the custom values of a Histogram, and is marshalled by reflection, in order to
+// check the wire format of the custom values.
+type customValuesMessage struct {
+	CustomValues []float64 `protobuf:"fixed64,16,rep,packed,name=custom_values,json=customValues,proto3" json:"custom_values,omitempty"`
+}
+
+func (m *customValuesMessage) Reset()         { *m = customValuesMessage{} }
+func (m *customValuesMessage) String() string { return proto.CompactTextString(m) }
+func (*customValuesMessage) ProtoMessage()    {}
+
+func TestHistogram_CustomBuckets(t *testing.T) {
+	h := Histogram{
+		Count:          &Histogram_CountInt{CountInt: 6},
+		Sum:            12.5,
+		Schema:         CustomBucketsSchema,
+		PositiveSpans:  []BucketSpan{{Offset: 0, Length: 2}, {Offset: 1, Length: 1}},
+		PositiveDeltas: []int64{1, 2, -1},
+		ResetHint:      Histogram_NO,
+		Timestamp:      1000,
+		CustomValues:   []float64{0.1, 0.5, 1, 5},
+	}
+	require.True(t, h.IsCustomBuckets())
+	require.False(t, Histogram{Schema: 3}.IsCustomBuckets())
+
+	data, err := h.Marshal()
+	require.NoError(t, err)
+	require.Len(t, data, h.Size())
+
+	var decoded Histogram
+	require.NoError(t, decoded.Unmarshal(data))
+	require.Equal(t, h, decoded)
+	require.True(t, h.Equal(decoded))
+
+	decoded.CustomValues[3] = 10
+	require.False(t, h.Equal(decoded))
+
+	// The custom values are encoded like any other packed repeated double field.
+	expected, err := proto.Marshal(&customValuesMessage{CustomValues: h.CustomValues})
+	require.NoError(t, err)
+	actual, err := (&Histogram{CustomValues: h.CustomValues}).Marshal()
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestHistogram_ReduceResolution(t *testing.T) {
+	tests := map[string]struct {
+		histogram    Histogram
+		targetSchema int32
+		expected     Histogram
+	}{
+		"int histogram": {
+			histogram: FromHistogramToHistogramProto(0, &histogram.Histogram{
+				Count:           23,
+				Sum:             100,
+				Schema:          1,
+				ZeroThreshold:   0.001,
+				PositiveSpans:   []histogram.Span{{Offset: 0, Length: 4}, {Offset: 2, Length: 1}},
+				PositiveBuckets: []int64{1, 1, 1, 1, 1}, // Counts: 1, 2, 3, 4, 5.
+				NegativeSpans:   []histogram.Span{{Offset: -2, Length: 1}, {Offset: 3, Length: 1}},
+				NegativeBuckets: []int64{4, -1}, // Counts: 4, 3.
+			}),
+			targetSchema: 0,
+			expected: FromHistogramToHistogramProto(0, &histogram.Histogram{
+				Count:           23,
+				Sum:             100,
+				Schema:          0,
+				ZeroThreshold:   0.001,
+				PositiveSpans:   []histogram.Span{{Offset: 0, Length: 4}},
+				PositiveBuckets: []int64{1, 4, -1, 1}, // Counts: 1, 5, 4, 5.
+				NegativeSpans:   []histogram.Span{{Offset: -1, Length: 1}, {Offset: 1, Length: 1}},
+				NegativeBuckets: []int64{4, -1}, // Counts: 4, 3.
+			}),
+		},
+		"float histogram reduced by more than one schema": {
+			histogram: FromFloatHistogramToHistogramProto(0, &histogram.FloatHistogram{
+				Count:           10,
+				Sum:             100,
+				Schema:          3,
+				ZeroThreshold:   0.001,
+				PositiveSpans:   []histogram.Span{{Offset: 1, Length: 4}},
+				PositiveBuckets: []float64{1, 2, 3, 4},
+			}),
+			targetSchema: 1,
+			expected: FromFloatHistogramToHistogramProto(0, &histogram.FloatHistogram{
+				Count:           10,
+				Sum:             100,
+				Schema:          1,
+				ZeroThreshold:   0.001,
+				PositiveSpans:   []histogram.Span{{Offset: 1, Length: 1}},
+				PositiveBuckets: []float64{10},
+			}),
+		},
+		"target schema not lower than the histogram's schema": {
+			histogram: FromFloatHistogramToHistogramProto(0, &histogram.FloatHistogram{
+				Count:           3,
+				Schema:          1,
+				PositiveSpans:   []histogram.Span{{Offset: 0, Length: 2}},
+				PositiveBuckets: []float64{1, 2},
+			}),
+			targetSchema: 1,
+			expected: FromFloatHistogramToHistogramProto(0, &histogram.FloatHistogram{
+				Count:           3,
+				Schema:          1,
+				PositiveSpans:   []histogram.Span{{Offset: 0, Length: 2}},
+				PositiveBuckets: []float64{1, 2},
+			}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tc.histogram.ReduceResolution(tc.targetSchema)
+
+			assert.Equal(t, tc.expected.Schema, tc.histogram.Schema)
+			assert.Equal(t, tc.expected.PositiveSpans, tc.histogram.PositiveSpans)
+			assert.Equal(t, tc.expected.NegativeSpans, tc.histogram.NegativeSpans)
+			if tc.expected.IsFloatHistogram() {
+				assert.Equal(t, tc.expected.PositiveCounts, tc.histogram.PositiveCounts)
+				assert.Equal(t, tc.expected.NegativeCounts, tc.histogram.NegativeCounts)
+			} else {
+				assert.Equal(t, tc.expected.PositiveDeltas, tc.histogram.PositiveDeltas)
+				assert.Equal(t, tc.expected.NegativeDeltas, tc.histogram.NegativeDeltas)
+			}
+		})
+	}
+}
@@ -187,6 +187,12 @@ type WriteRequest struct {
 	Metadata   []*MetricMetadata       `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty"`
 	// Skip validation of label names.
 	SkipLabelNameValidation bool `protobuf:"varint,1000,opt,name=skip_label_name_validation,json=skipLabelNameValidation,proto3" json:"skip_label_name_validation,omitempty"`
+	// If greater than 0, the series have been received from a non-elected HA replica, and their samples must
+	// be appended only to the existing series which haven't been updated within this period, in milliseconds.
+	NonElectedReplicaWindowMs int64 `protobuf:"varint,1001,opt,name=non_elected_replica_window_ms,json=nonElectedReplicaWindowMs,proto3" json:"non_elected_replica_window_ms,omitempty"`
+	// If not empty, the series are the input series of the tenant's streaming aggregation rule with this key,
+	// routed to the ingesters owning its output series. They're aggregated, and not ingested.
+	StreamingAggregationRule string `protobuf:"bytes,1002,opt,name=streaming_aggregation_rule,json=streamingAggregationRule,proto3" json:"streaming_aggregation_rule,omitempty"`
 }
 
 func (m *WriteRequest) Reset()      { *m = WriteRequest{} }
@@ -242,6 +248,20 @@ func (m *WriteRequest) GetSkipLabelNameValidation() bool {
 	return false
 }
 
+func (m *WriteRequest) GetNonElectedReplicaWindowMs() int64 {
+	if m != nil {
+		return m.NonElectedReplicaWindowMs
+	}
+	return 0
+}
+
+func (m *WriteRequest) GetStreamingAggregationRule() string {
+	if m != nil {
+		return m.StreamingAggregationRule
+	}
+	return ""
+}
+
 type WriteResponse struct {
 }
 
@@ -283,6 +303,9 @@ type TimeSeries struct {
 	Samples    []Sample    `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples"`
 	Exemplars  []Exemplar  `protobuf:"bytes,3,rep,name=exemplars,proto3" json:"exemplars"`
 	Histograms []Histogram `protobuf:"bytes,4,rep,name=histograms,proto3" json:"histograms"`
+	// created_timestamp is the time, in milliseconds, at which the counter, histogram or
+	// summary has been created or reset. Zero if unknown.
+	CreatedTimestamp int64 `protobuf:"varint,5,opt,name=created_timestamp,json=createdTimestamp,proto3" json:"created_timestamp,omitempty"`
 }
 
 func (m *TimeSeries) Reset()      { *m = TimeSeries{} }
@@ -338,6 +361,13 @@ func (m *TimeSeries) GetHistograms() []Histogram {
 	return nil
 }
 
+func (m *TimeSeries) GetCreatedTimestamp() int64 {
+	if m != nil {
+		return m.CreatedTimestamp
+	}
+	return 0
+}
+
 type LabelPair struct {
 	Name  []byte `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -636,6 +666,10 @@ type Histogram struct {
 	ResetHint      Histogram_ResetHint `protobuf:"varint,14,opt,name=reset_hint,json=resetHint,proto3,enum=cortexpb.Histogram_ResetHint" json:"reset_hint,omitempty"`
 	// timestamp is in ms format
 	Timestamp int64 `protobuf:"varint,15,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The upper bounds of the custom buckets, excluding +Inf, when the
+	// schema is -53 (custom buckets). The positive spans and counts
+	// reference them by index, and the last bucket is the +Inf one.
+	CustomValues []float64 `protobuf:"fixed64,16,rep,packed,name=custom_values,json=customValues,proto3" json:"custom_values,omitempty"`
 }
 
 func (m *Histogram) Reset()      { *m = Histogram{} }
@@ -819,6 +853,13 @@ func (m *Histogram) GetTimestamp() int64 {
 	return 0
 }
 
+func (m *Histogram) GetCustomValues() []float64 {
+	if m != nil {
+		return m.CustomValues
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Histogram) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -1787,117 +1828,124 @@ func init() {
 func init() { proto.RegisterFile("mimir.proto", fileDescriptor_86d4d7485f544059) }
 
 var fileDescriptor_86d4d7485f544059 = []byte{
-	// 1758 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcf, 0x73, 0x1b, 0x49,
-	0x15, 0x56, 0x4b, 0x63, 0x49, 0xf3, 0x2c, 0xc9, 0xb3, 0xbd, 0xa9, 0xa0, 0x4d, 0x6d, 0x64, 0x67,
-	0x28, 0x16, 0x43, 0x81, 0x42, 0x65, 0x21, 0x5b, 0xbb, 0x15, 0x0a, 0x46, 0xf2, 0x24, 0xb6, 0xd7,
-	0x96, 0x4c, 0x4b, 0xca, 0xb2, 0x5c, 0x54, 0x23, 0xb9, 0x6d, 0x4d, 0xed, 0x8c, 0x66, 0x98, 0x19,
-	0x85, 0x98, 0x13, 0x17, 0x28, 0x8a, 0x13, 0x17, 0x2e, 0x14, 0x37, 0x0e, 0xf0, 0x17, 0xf0, 0x37,
-	0xa4, 0x8a, 0xa2, 0x2a, 0xc7, 0x2d, 0x0e, 0x29, 0xe2, 0x5c, 0xf6, 0x98, 0x03, 0x27, 0x4e, 0x54,
-	0xbf, 0x9e, 0x1f, 0x1a, 0xd9, 0x86, 0xc0, 0xfa, 0x36, 0xfd, 0xfa, 0x7b, 0xaf, 0xbf, 0x7e, 0xfd,
-	0xf5, 0xd3, 0x6b, 0xc1, 0xba, 0x6b, 0xbb, 0x76, 0xd0, 0xf6, 0x03, 0x2f, 0xf2, 0x68, 0x75, 0xea,
-	0x05, 0x11, 0x7f, 0xea, 0x4f, 0x6e, 0x7d, 0xfb, 0xd4, 0x8e, 0x66, 0x8b, 0x49, 0x7b, 0xea, 0xb9,
-	0x77, 0x4f, 0xbd, 0x53, 0xef, 0x2e, 0x02, 0x26, 0x8b, 0x13, 0x1c, 0xe1, 0x00, 0xbf, 0xa4, 0xa3,
-	0xfe, 0x97, 0x22, 0xd4, 0x3e, 0x09, 0xec, 0x88, 0x33, 0xfe, 0xd3, 0x05, 0x0f, 0x23, 0x7a, 0x04,
-	0x10, 0xd9, 0x2e, 0x0f, 0x79, 0x60, 0xf3, 0xb0, 0x49, 0xb6, 0x4a, 0xdb, 0xeb, 0xf7, 0x6e, 0xb4,
-	0x93, 0xf0, 0xed, 0xa1, 0xed, 0xf2, 0x01, 0xce, 0x75, 0x6e, 0x3d, 0x7b, 0xb1, 0x59, 0xf8, 0xfb,
-	0x8b, 0x4d, 0x7a, 0x14, 0x70, 0xcb, 0x71, 0xbc, 0xe9, 0x30, 0xf5, 0x63, 0x4b, 0x31, 0xe8, 0x87,
-	0x50, 0x1e, 0x78, 0x8b, 0x60, 0xca, 0x9b, 0xc5, 0x2d, 0xb2, 0xdd, 0xb8, 0x77, 0x27, 0x8b, 0xb6,
-	0xbc, 0x72, 0x5b, 0x82, 0xcc, 0xf9, 0xc2, 0x65, 0xb1, 0x03, 0xfd, 0x08, 0xaa, 0x2e, 0x8f, 0xac,
-	0x63, 0x2b, 0xb2, 0x9a, 0x25, 0xa4, 0xd2, 0xcc, 0x9c, 0x0f, 0x79, 0x14, 0xd8, 0xd3, 0xc3, 0x78,
-	0xbe, 0xa3, 0x3c, 0x7b, 0xb1, 0x49, 0x58, 0x8a, 0xa7, 0x0f, 0xe0, 0x56, 0xf8, 0x99, 0xed, 0x8f,
-	0x1d, 0x6b, 0xc2, 0x9d, 0xf1, 0xdc, 0x72, 0xf9, 0xf8, 0x89, 0xe5, 0xd8, 0xc7, 0x56, 0x64, 0x7b,
-	0xf3, 0xe6, 0x17, 0x95, 0x2d, 0xb2, 0x5d, 0x65, 0x5f, 0x11, 0x90, 0x03, 0x81, 0xe8, 0x59, 0x2e,
-	0x7f, 0x9c, 0xce, 0xeb, 0x9b, 0x00, 0x19, 0x1f, 0x5a, 0x81, 0x92, 0x71, 0xb4, 0xa7, 0x15, 0x68,
-	0x15, 0x14, 0x36, 0x3a, 0x30, 0x35, 0xa2, 0x6f, 0x40, 0x3d, 0x66, 0x1f, 0xfa, 0xde, 0x3c, 0xe4,
-	0xfa, 0x3f, 0x09, 0x40, 0x96, 0x1d, 0x6a, 0x40, 0x19, 0x57, 0x4e, 0x72, 0xf8, 0x76, 0x46, 0x1c,
-	0xd7, 0x3b, 0xb2, 0xec, 0xa0, 0x73, 0x23, 0x4e, 0x61, 0x0d, 0x4d, 0xc6, 0xb1, 0xe5, 0x47, 0x3c,
-	0x60, 0xb1, 0x23, 0xfd, 0x0e, 0x54, 0x42, 0xcb, 0xf5, 0x1d, 0x1e, 0x36, 0x8b, 0x18, 0x43, 0xcb,
-	0x62, 0x0c, 0x70, 0x02, 0x37, 0x5d, 0x60, 0x09, 0x8c, 0xde, 0x07, 0x95, 0x3f, 0xe5, 0xae, 0xef,
-	0x58, 0x41, 0x18, 0x27, 0x8c, 0x66, 0x3e, 0x66, 0x3c, 0x15, 0x7b, 0x65, 0x50, 0xfa, 0x21, 0xc0,
-	0xcc, 0x0e, 0x23, 0xef, 0x34, 0xb0, 0xdc, 0xb0, 0xa9, 0xac, 0x12, 0xde, 0x4d, 0xe6, 0x62, 0xcf,
-	0x25, 0xb0, 0xfe, 0x3d, 0x50, 0xd3, 0xfd, 0x50, 0x0a, 0x8a, 0x48, 0x74, 0x93, 0x6c, 0x91, 0xed,
-	0x1a, 0xc3, 0x6f, 0x7a, 0x03, 0xd6, 0x9e, 0x58, 0xce, 0x42, 0x9e, 0x7e, 0x8d, 0xc9, 0x81, 0x6e,
-	0x40, 0x59, 0x6e, 0x81, 0xde, 0x81, 0x1a, 0x8a, 0x25, 0xb2, 0x5c, 0x7f, 0xec, 0x86, 0x08, 0x2b,
-	0xb1, 0xf5, 0xd4, 0x76, 0x18, 0x66, 0x21, 0x44, 0x5c, 0x92, 0x84, 0xf8, 0x7d, 0x11, 0x1a, 0x79,
-	0x0d, 0xd0, 0x0f, 0x40, 0x89, 0xce, 0x7c, 0x89, 0x6b, 0xdc, 0xfb, 0xea, 0x55, 0x5a, 0x89, 0x87,
-	0xc3, 0x33, 0x9f, 0x33, 0x74, 0xa0, 0xdf, 0x02, 0xea, 0xa2, 0x6d, 0x7c, 0x62, 0xb9, 0xb6, 0x73,
-	0x86, 0x7a, 0x41, 0x2a, 0x2a, 0xd3, 0xe4, 0xcc, 0x43, 0x9c, 0x10, 0x32, 0x11, 0xdb, 0x9c, 0x71,
-	0xc7, 0x6f, 0x2a, 0x38, 0x8f, 0xdf, 0xc2, 0xb6, 0x98, 0xdb, 0x51, 0x73, 0x4d, 0xda, 0xc4, 0xb7,
-	0x7e, 0x06, 0x90, 0xad, 0x44, 0xd7, 0xa1, 0x32, 0xea, 0x7d, 0xdc, 0xeb, 0x7f, 0xd2, 0xd3, 0x0a,
-	0x62, 0xd0, 0xed, 0x8f, 0x7a, 0x43, 0x93, 0x69, 0x84, 0xaa, 0xb0, 0xf6, 0xc8, 0x18, 0x3d, 0x32,
-	0xb5, 0x22, 0xad, 0x83, 0xba, 0xbb, 0x37, 0x18, 0xf6, 0x1f, 0x31, 0xe3, 0x50, 0x2b, 0x51, 0x0a,
-	0x0d, 0x9c, 0xc9, 0x6c, 0x8a, 0x70, 0x1d, 0x8c, 0x0e, 0x0f, 0x0d, 0xf6, 0xa9, 0xb6, 0x26, 0x04,
-	0xb9, 0xd7, 0x7b, 0xd8, 0xd7, 0xca, 0xb4, 0x06, 0xd5, 0xc1, 0xd0, 0x18, 0x9a, 0x03, 0x73, 0xa8,
-	0x55, 0xf4, 0x8f, 0xa1, 0x2c, 0x97, 0xbe, 0x06, 0x21, 0xea, 0xbf, 0x22, 0x50, 0x4d, 0xc4, 0x73,
-	0x1d, 0xc2, 0xce, 0x49, 0x22, 0x39, 0xcf, 0x0b, 0x42, 0x28, 0x5d, 0x10, 0x82, 0xfe, 0xd7, 0x35,
-	0x50, 0x53, 0x31, 0xd2, 0xdb, 0xa0, 0x4e, 0xbd, 0xc5, 0x3c, 0x1a, 0xdb, 0xf3, 0x08, 0x8f, 0x5c,
-	0xd9, 0x2d, 0xb0, 0x2a, 0x9a, 0xf6, 0xe6, 0x11, 0xbd, 0x03, 0xeb, 0x72, 0xfa, 0xc4, 0xf1, 0xac,
-	0x48, 0xae, 0xb5, 0x5b, 0x60, 0x80, 0xc6, 0x87, 0xc2, 0x46, 0x35, 0x28, 0x85, 0x0b, 0x17, 0x57,
-	0x22, 0x4c, 0x7c, 0xd2, 0x9b, 0x50, 0x0e, 0xa7, 0x33, 0xee, 0x5a, 0x78, 0xb8, 0x6f, 0xb1, 0x78,
-	0x44, 0xbf, 0x06, 0x8d, 0x9f, 0xf3, 0xc0, 0x1b, 0x47, 0xb3, 0x80, 0x87, 0x33, 0xcf, 0x39, 0xc6,
-	0x83, 0x26, 0xac, 0x2e, 0xac, 0xc3, 0xc4, 0x48, 0xdf, 0x8b, 0x61, 0x19, 0xaf, 0x32, 0xf2, 0x22,
-	0xac, 0x26, 0xec, 0xdd, 0x84, 0xdb, 0x37, 0x41, 0x5b, 0xc2, 0x49, 0x82, 0x15, 0x24, 0x48, 0x58,
-	0x23, 0x45, 0x4a, 0x92, 0x06, 0x34, 0xe6, 0xfc, 0xd4, 0x8a, 0xec, 0x27, 0x7c, 0x1c, 0xfa, 0xd6,
-	0x3c, 0x6c, 0x56, 0x57, 0xab, 0x72, 0x67, 0x31, 0xfd, 0x8c, 0x47, 0x03, 0xdf, 0x9a, 0xc7, 0x37,
-	0xb4, 0x9e, 0x78, 0x08, 0x5b, 0x48, 0xbf, 0x0e, 0x1b, 0x69, 0x88, 0x63, 0xee, 0x44, 0x56, 0xd8,
-	0x54, 0xb7, 0x4a, 0xdb, 0x94, 0xa5, 0x91, 0x77, 0xd0, 0x9a, 0x03, 0x22, 0xb7, 0xb0, 0x09, 0x5b,
-	0xa5, 0x6d, 0x92, 0x01, 0x91, 0x98, 0x28, 0x6f, 0x0d, 0xdf, 0x0b, 0xed, 0x25, 0x52, 0xeb, 0xff,
-	0x9d, 0x54, 0xe2, 0x91, 0x92, 0x4a, 0x43, 0xc4, 0xa4, 0x6a, 0x92, 0x54, 0x62, 0xce, 0x48, 0xa5,
-	0xc0, 0x98, 0x54, 0x5d, 0x92, 0x4a, 0xcc, 0x31, 0xa9, 0x07, 0x00, 0x01, 0x0f, 0x79, 0x34, 0x9e,
-	0x89, 0xcc, 0x37, 0xb0, 0x08, 0xdc, 0xbe, 0xa4, 0x8c, 0xb5, 0x99, 0x40, 0xed, 0xda, 0xf3, 0x88,
-	0xa9, 0x41, 0xf2, 0x49, 0xdf, 0x05, 0x35, 0xd5, 0x5a, 0x73, 0x03, 0xc5, 0x97, 0x19, 0xf4, 0x8f,
-	0x40, 0x4d, 0xbd, 0xf2, 0x57, 0xb9, 0x02, 0xa5, 0x4f, 0xcd, 0x81, 0x46, 0x68, 0x19, 0x8a, 0xbd,
-	0xbe, 0x56, 0xcc, 0xae, 0x73, 0xe9, 0x96, 0xf2, 0xeb, 0x3f, 0xb6, 0x48, 0xa7, 0x02, 0x6b, 0xc8,
-	0xbb, 0x53, 0x03, 0xc8, 0x8e, 0x5d, 0xff, 0x9b, 0x02, 0x0d, 0x3c, 0xe2, 0x4c, 0xd2, 0x21, 0x50,
-	0x9c, 0xe3, 0xc1, 0x78, 0x65, 0x27, 0xf5, 0x8e, 0xf9, 0xaf, 0x17, 0x9b, 0xc6, 0xd2, 0xaf, 0xbb,
-	0x1f, 0x78, 0x2e, 0x8f, 0x66, 0x7c, 0x11, 0x2e, 0x7f, 0xba, 0xde, 0x31, 0x77, 0xee, 0xa6, 0x05,
-	0xba, 0xdd, 0x95, 0xe1, 0xb2, 0x1d, 0x6b, 0xd3, 0x15, 0xcb, 0x97, 0xd5, 0xfc, 0xed, 0xe5, 0x4d,
-	0x49, 0x15, 0x33, 0x35, 0xd5, 0xb0, 0xb8, 0xec, 0x72, 0x26, 0xbe, 0xec, 0x38, 0xb8, 0xe4, 0xe6,
-	0x5d, 0x83, 0xa2, 0xae, 0xe1, 0xa6, 0x7c, 0x03, 0xb4, 0x94, 0xc5, 0x04, 0xb1, 0x89, 0xd8, 0x52,
-	0x0d, 0xca, 0x10, 0x08, 0x4d, 0x57, 0x4b, 0xa0, 0xf2, 0xb2, 0xa4, 0x77, 0x28, 0x86, 0xee, 0x2b,
-	0x55, 0xa2, 0x15, 0xf7, 0x95, 0x6a, 0x59, 0xab, 0xec, 0x2b, 0x55, 0x55, 0x83, 0x7d, 0xa5, 0x5a,
-	0xd3, 0xea, 0xfb, 0x4a, 0x75, 0x43, 0xd3, 0x58, 0x56, 0xc5, 0xd8, 0x4a, 0xf5, 0x60, 0xab, 0xd7,
-	0x96, 0xad, 0x5e, 0x99, 0x65, 0x89, 0x3e, 0x00, 0xc8, 0xb6, 0x27, 0x4e, 0xd5, 0x3b, 0x39, 0x09,
-	0xb9, 0x2c, 0x8d, 0x6f, 0xb1, 0x78, 0x24, 0xec, 0x0e, 0x9f, 0x9f, 0x46, 0x33, 0x3c, 0x90, 0x3a,
-	0x8b, 0x47, 0xfa, 0x02, 0x68, 0x5e, 0x8c, 0xf8, 0x8b, 0xfe, 0x06, 0xbf, 0xce, 0x0f, 0x40, 0x4d,
-	0xe5, 0x86, 0x6b, 0xe5, 0xba, 0xb4, 0x7c, 0xcc, 0xb8, 0x4b, 0xcb, 0x1c, 0xf4, 0x39, 0x6c, 0xc8,
-	0x46, 0x20, 0xbb, 0x04, 0xa9, 0x62, 0xc8, 0x25, 0x8a, 0x29, 0x66, 0x8a, 0x79, 0x1f, 0x2a, 0x49,
-	0xde, 0x65, 0xaf, 0xf3, 0xce, 0x65, 0x2d, 0x0b, 0x22, 0x58, 0x82, 0xd4, 0x43, 0xd8, 0x58, 0x99,
-	0xa3, 0x2d, 0x80, 0x89, 0xb7, 0x98, 0x1f, 0x5b, 0x71, 0xcb, 0x4b, 0xb6, 0xd7, 0xd8, 0x92, 0x45,
-	0xf0, 0x71, 0xbc, 0x9f, 0xf1, 0x20, 0x51, 0x30, 0x0e, 0x84, 0x75, 0xe1, 0xfb, 0x3c, 0x88, 0x35,
-	0x2c, 0x07, 0x19, 0x77, 0x65, 0x89, 0xbb, 0xee, 0xc0, 0xdb, 0x2b, 0x9b, 0xc4, 0xe4, 0xe6, 0x2a,
-	0x4e, 0x71, 0xa5, 0xe2, 0xd0, 0x0f, 0x2e, 0xe6, 0xf5, 0x9d, 0xd5, 0x06, 0x30, 0x8d, 0xb7, 0x9c,
-	0xd2, 0x3f, 0x29, 0x50, 0xff, 0xd1, 0x82, 0x07, 0x67, 0x49, 0x6f, 0x4a, 0xef, 0x43, 0x39, 0x8c,
-	0xac, 0x68, 0x11, 0xc6, 0x9d, 0x51, 0x2b, 0x8b, 0x93, 0x03, 0xb6, 0x07, 0x88, 0x62, 0x31, 0x9a,
-	0xfe, 0x10, 0x80, 0x07, 0x81, 0x17, 0x8c, 0xb1, 0xab, 0xba, 0xd0, 0xbe, 0xe7, 0x7d, 0x4d, 0x81,
-	0xc4, 0x9e, 0x4a, 0xe5, 0xc9, 0xa7, 0xc8, 0x07, 0x0e, 0x30, 0x4b, 0x2a, 0x93, 0x03, 0xda, 0x16,
-	0x7c, 0x02, 0x7b, 0x7e, 0x8a, 0x69, 0xca, 0x5d, 0xd0, 0x01, 0xda, 0x77, 0xac, 0xc8, 0xda, 0x2d,
-	0xb0, 0x18, 0x25, 0xf0, 0x4f, 0xf8, 0x34, 0xf2, 0x02, 0xac, 0x40, 0x39, 0xfc, 0x63, 0xb4, 0x27,
-	0x78, 0x89, 0xc2, 0xf8, 0x53, 0xcb, 0xb1, 0x02, 0xfc, 0xf9, 0xcd, 0xc7, 0x47, 0x7b, 0x1a, 0x1f,
-	0x47, 0x02, 0xef, 0x5a, 0x51, 0x60, 0x3f, 0xc5, 0xf2, 0x95, 0xc3, 0x1f, 0xa2, 0x3d, 0xc1, 0x4b,
-	0x94, 0xfe, 0x1e, 0x94, 0x65, 0xa6, 0x44, 0xad, 0x37, 0x19, 0xeb, 0x33, 0xd9, 0xd2, 0x0d, 0x46,
-	0xdd, 0xae, 0x39, 0x18, 0x68, 0x44, 0x16, 0x7e, 0xfd, 0x77, 0x04, 0xd4, 0x34, 0x2d, 0xa2, 0x57,
-	0xeb, 0xf5, 0x7b, 0xa6, 0x84, 0x0e, 0xf7, 0x0e, 0xcd, 0xfe, 0x68, 0xa8, 0x11, 0xd1, 0xb8, 0x75,
-	0x8d, 0x5e, 0xd7, 0x3c, 0x30, 0x77, 0x64, 0x03, 0x68, 0xfe, 0xd8, 0xec, 0x8e, 0x86, 0x7b, 0xfd,
-	0x9e, 0x56, 0x12, 0x93, 0x1d, 0x63, 0x67, 0xbc, 0x63, 0x0c, 0x0d, 0x4d, 0x11, 0xa3, 0x3d, 0xd1,
-	0x33, 0xf6, 0x8c, 0x03, 0x6d, 0x8d, 0x6e, 0xc0, 0xfa, 0xa8, 0x67, 0x3c, 0x36, 0xf6, 0x0e, 0x8c,
-	0xce, 0x81, 0xa9, 0x95, 0x85, 0x6f, 0xaf, 0x3f, 0x1c, 0x3f, 0xec, 0x8f, 0x7a, 0x3b, 0x5a, 0x45,
-	0x34, 0x8f, 0x62, 0x68, 0x74, 0xbb, 0xe6, 0xd1, 0x10, 0x21, 0xd5, 0xf8, 0x07, 0xa9, 0x0c, 0x8a,
-	0xe8, 0x83, 0x75, 0x13, 0x20, 0xcb, 0x77, 0xbe, 0xcd, 0x56, 0xaf, 0x6a, 0xcb, 0x2e, 0x56, 0x00,
-	0xfd, 0x97, 0x04, 0x20, 0x3b, 0x07, 0x7a, 0x3f, 0x7b, 0xb7, 0xc8, 0x16, 0xf1, 0xe6, 0xea, 0x71,
-	0x5d, 0xfe, 0x7a, 0xf9, 0x41, 0xee, 0x15, 0x52, 0x5c, 0xbd, 0xd2, 0xd2, 0xf5, 0x3f, 0xbd, 0x45,
-	0xc6, 0x50, 0x5b, 0x8e, 0x2f, 0x4a, 0x9d, 0xec, 0xdd, 0x91, 0x87, 0xca, 0xe2, 0xd1, 0xff, 0xdf,
-	0x7f, 0xfe, 0x86, 0xc0, 0xc6, 0x0a, 0x8d, 0x2b, 0x17, 0xc9, 0x95, 0xc5, 0xe2, 0x1b, 0x94, 0xc5,
-	0xc2, 0xd2, 0x1d, 0x7e, 0x13, 0x32, 0xe2, 0xf0, 0x52, 0x31, 0x5f, 0xfe, 0x46, 0x7a, 0x93, 0xc3,
-	0xeb, 0x00, 0x64, 0x1a, 0xa7, 0xdf, 0x85, 0x72, 0xee, 0xe9, 0x7f, 0x73, 0xf5, 0x26, 0xc4, 0x8f,
-	0x7f, 0x49, 0x38, 0xc6, 0xea, 0x7f, 0x20, 0x50, 0x5b, 0x9e, 0xbe, 0x32, 0x29, 0xff, 0xfb, 0x93,
-	0xb6, 0x93, 0x13, 0x85, 0xac, 0xf3, 0xef, 0x5e, 0x95, 0x47, 0x7c, 0x7b, 0x5c, 0xd0, 0x45, 0xe7,
-	0xfb, 0xcf, 0x5f, 0xb6, 0x0a, 0x9f, 0xbf, 0x6c, 0x15, 0x5e, 0xbf, 0x6c, 0x91, 0x5f, 0x9c, 0xb7,
-	0xc8, 0x9f, 0xcf, 0x5b, 0xe4, 0xd9, 0x79, 0x8b, 0x3c, 0x3f, 0x6f, 0x91, 0x7f, 0x9c, 0xb7, 0xc8,
-	0x17, 0xe7, 0xad, 0xc2, 0xeb, 0xf3, 0x16, 0xf9, 0xed, 0xab, 0x56, 0xe1, 0xf9, 0xab, 0x56, 0xe1,
-	0xf3, 0x57, 0xad, 0xc2, 0x4f, 0x2a, 0xf8, 0x07, 0x8b, 0x3f, 0x99, 0x94, 0xf1, 0xaf, 0x92, 0xf7,
-	0xff, 0x1d, 0x00, 0x00, 0xff, 0xff, 0x65, 0xcf, 0xac, 0x82, 0x72, 0x11, 0x00, 0x00,
+	// 1870 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcd, 0x73, 0x1b, 0x59,
+	0x11, 0xd7, 0x48, 0x63, 0x49, 0xd3, 0x96, 0xe4, 0xc9, 0xdb, 0x54, 0x98, 0xa4, 0x36, 0x8a, 0x33,
+	0x5b, 0x2c, 0xe6, 0xcb, 0xa1, 0xb2, 0x90, 0xad, 0xdd, 0xca, 0x16, 0x8c, 0xe4, 0x49, 0xec, 0xac,
+	0x2d, 0x99, 0x27, 0x29, 0x61, 0xb9, 0x4c, 0x8d, 0xe5, 0x67, 0x79, 0x6a, 0xe7, 0x8b, 0xf9, 0x48,
+	0x62, 0x4e, 0x5c, 0xa0, 0x28, 0x4e, 0x5c, 0xb8, 0x50, 0x5c, 0x28, 0x0e, 0xf0, 0x27, 0xf0, 0x27,
+	0xe4, 0x42, 0x55, 0x8e, 0x5b, 0x1c, 0x52, 0xc4, 0xb9, 0x2c, 0x9c, 0x72, 0xe6, 0x44, 0xbd, 0x7e,
+	0xf3, 0x21, 0xc9, 0x36, 0x04, 0xc8, 0x6d, 0xba, 0xfb, 0xd7, 0xfd, 0xfa, 0xf5, 0xfb, 0xbd, 0x56,
+	0x3f, 0xc1, 0xaa, 0xe7, 0x78, 0x4e, 0xb4, 0x19, 0x46, 0x41, 0x12, 0x90, 0xe6, 0x34, 0x88, 0x12,
+	0xf6, 0x34, 0x3c, 0xb8, 0xf6, 0xed, 0x99, 0x93, 0x1c, 0xa7, 0x07, 0x9b, 0xd3, 0xc0, 0xbb, 0x35,
+	0x0b, 0x66, 0xc1, 0x2d, 0x04, 0x1c, 0xa4, 0x47, 0x28, 0xa1, 0x80, 0x5f, 0xc2, 0x51, 0xff, 0x73,
+	0x0d, 0x5a, 0x8f, 0x22, 0x27, 0x61, 0x94, 0xfd, 0x24, 0x65, 0x71, 0x42, 0xf6, 0x01, 0x12, 0xc7,
+	0x63, 0x31, 0x8b, 0x1c, 0x16, 0x6b, 0xd2, 0x7a, 0x6d, 0x63, 0xf5, 0xf6, 0xe5, 0xcd, 0x3c, 0xfc,
+	0xe6, 0xd8, 0xf1, 0xd8, 0x08, 0x6d, 0xbd, 0x6b, 0xcf, 0x5e, 0xdc, 0xa8, 0xfc, 0xf5, 0xc5, 0x0d,
+	0xb2, 0x1f, 0x31, 0xdb, 0x75, 0x83, 0xe9, 0xb8, 0xf0, 0xa3, 0x73, 0x31, 0xc8, 0x47, 0x50, 0x1f,
+	0x05, 0x69, 0x34, 0x65, 0x5a, 0x75, 0x5d, 0xda, 0xe8, 0xdc, 0xbe, 0x59, 0x46, 0x9b, 0x5f, 0x79,
+	0x53, 0x80, 0x4c, 0x3f, 0xf5, 0x68, 0xe6, 0x40, 0x3e, 0x86, 0xa6, 0xc7, 0x12, 0xfb, 0xd0, 0x4e,
+	0x6c, 0xad, 0x86, 0xa9, 0x68, 0xa5, 0xf3, 0x1e, 0x4b, 0x22, 0x67, 0xba, 0x97, 0xd9, 0x7b, 0xf2,
+	0xb3, 0x17, 0x37, 0x24, 0x5a, 0xe0, 0xc9, 0x5d, 0xb8, 0x16, 0x7f, 0xee, 0x84, 0x96, 0x6b, 0x1f,
+	0x30, 0xd7, 0xf2, 0x6d, 0x8f, 0x59, 0x8f, 0x6d, 0xd7, 0x39, 0xb4, 0x13, 0x27, 0xf0, 0xb5, 0x2f,
+	0x1b, 0xeb, 0xd2, 0x46, 0x93, 0x7e, 0x85, 0x43, 0x76, 0x39, 0x62, 0x60, 0x7b, 0xec, 0x61, 0x61,
+	0x27, 0x06, 0x5c, 0xf7, 0x03, 0xdf, 0x62, 0x2e, 0x9b, 0x26, 0xec, 0xd0, 0x8a, 0x58, 0xe8, 0x3a,
+	0x53, 0xdb, 0x7a, 0xe2, 0xf8, 0x87, 0xc1, 0x13, 0xcb, 0x8b, 0xb5, 0xbf, 0xf3, 0x00, 0x35, 0x7a,
+	0xd5, 0x0f, 0x7c, 0x53, 0x80, 0xa8, 0xc0, 0x3c, 0x42, 0xc8, 0x5e, 0x4c, 0x3e, 0x81, 0x6b, 0x71,
+	0x12, 0x31, 0xdb, 0x73, 0xfc, 0x99, 0x65, 0xcf, 0x66, 0x11, 0x9b, 0x61, 0x6c, 0x2b, 0x4a, 0x5d,
+	0xa6, 0xfd, 0x83, 0xfb, 0x2b, 0x54, 0x2b, 0x20, 0x46, 0x89, 0xa0, 0xa9, 0xcb, 0xf4, 0x1b, 0x00,
+	0x65, 0x45, 0x48, 0x03, 0x6a, 0xc6, 0xfe, 0x8e, 0x5a, 0x21, 0x4d, 0x90, 0xe9, 0x64, 0xd7, 0x54,
+	0x25, 0x7d, 0x0d, 0xda, 0x59, 0xfd, 0xe2, 0x30, 0xf0, 0x63, 0xa6, 0xff, 0xbe, 0x0a, 0x50, 0x9e,
+	0x0f, 0x31, 0xa0, 0x8e, 0x7b, 0xcf, 0x4f, 0xf1, 0x9d, 0xb2, 0x74, 0xb8, 0xe3, 0x7d, 0xdb, 0x89,
+	0x7a, 0x97, 0xb3, 0x43, 0x6c, 0xa1, 0xca, 0x38, 0xb4, 0xc3, 0x84, 0x45, 0x34, 0x73, 0x24, 0xdf,
+	0x81, 0x46, 0x6c, 0x7b, 0xa1, 0xcb, 0x62, 0xad, 0x8a, 0x31, 0xd4, 0x32, 0xc6, 0x08, 0x0d, 0x58,
+	0xf6, 0x0a, 0xcd, 0x61, 0xe4, 0x0e, 0x28, 0xec, 0x29, 0xf3, 0x42, 0xd7, 0x8e, 0xe2, 0xec, 0xc8,
+	0x48, 0xe9, 0x63, 0x66, 0xa6, 0xcc, 0xab, 0x84, 0x92, 0x8f, 0x00, 0x8e, 0x9d, 0x38, 0x09, 0x66,
+	0x91, 0xed, 0xc5, 0x9a, 0xbc, 0x9c, 0xf0, 0x76, 0x6e, 0xcb, 0x3c, 0xe7, 0xc0, 0xe4, 0x9b, 0x70,
+	0x69, 0x1a, 0x31, 0x9b, 0x1f, 0x13, 0xb2, 0x2e, 0xb1, 0xbd, 0x50, 0x5b, 0xc1, 0xd3, 0x51, 0x33,
+	0xc3, 0x38, 0xd7, 0xeb, 0xdf, 0x03, 0xa5, 0xd8, 0x3c, 0x21, 0x20, 0x73, 0x5e, 0x68, 0xd2, 0xba,
+	0xb4, 0xd1, 0xa2, 0xf8, 0x4d, 0x2e, 0xc3, 0xca, 0x63, 0xdb, 0x4d, 0x05, 0x59, 0x5b, 0x54, 0x08,
+	0xba, 0x01, 0x75, 0xb1, 0x5f, 0x72, 0x13, 0x5a, 0xc5, 0x2a, 0x9c, 0x07, 0x55, 0x5c, 0x68, 0xb5,
+	0xd0, 0xed, 0xc5, 0x65, 0x08, 0x1e, 0x57, 0xca, 0x43, 0xfc, 0xb6, 0x0a, 0x9d, 0x45, 0xca, 0x92,
+	0x0f, 0x41, 0x4e, 0x4e, 0x42, 0x81, 0xeb, 0xdc, 0x7e, 0xef, 0x22, 0x6a, 0x67, 0xe2, 0xf8, 0x24,
+	0x64, 0x14, 0x1d, 0xc8, 0xb7, 0x80, 0x78, 0xa8, 0xb3, 0x8e, 0x6c, 0xcf, 0x71, 0x4f, 0x90, 0xde,
+	0x98, 0x8a, 0x42, 0x55, 0x61, 0xb9, 0x87, 0x06, 0xce, 0x6a, 0xbe, 0xcd, 0x63, 0xe6, 0x86, 0x9a,
+	0x8c, 0x76, 0xfc, 0xe6, 0xba, 0xd4, 0x77, 0x12, 0xac, 0x93, 0x42, 0xf1, 0x5b, 0x3f, 0x01, 0x28,
+	0x57, 0x22, 0xab, 0xd0, 0x98, 0x0c, 0x3e, 0x1d, 0x0c, 0x1f, 0x0d, 0xd4, 0x0a, 0x17, 0xfa, 0xc3,
+	0xc9, 0x60, 0x6c, 0x52, 0x55, 0x22, 0x0a, 0xac, 0xdc, 0x37, 0x26, 0xf7, 0x4d, 0xb5, 0x4a, 0xda,
+	0xa0, 0x6c, 0xef, 0x8c, 0xc6, 0xc3, 0xfb, 0xd4, 0xd8, 0x53, 0x6b, 0x84, 0x40, 0x07, 0x2d, 0xa5,
+	0x4e, 0xe6, 0xae, 0xa3, 0xc9, 0xde, 0x9e, 0x41, 0x3f, 0x53, 0x57, 0x38, 0x7b, 0x77, 0x06, 0xf7,
+	0x86, 0x6a, 0x9d, 0xb4, 0xa0, 0x39, 0x1a, 0x1b, 0x63, 0x73, 0x64, 0x8e, 0xd5, 0x86, 0xfe, 0x29,
+	0xd4, 0xc5, 0xd2, 0x6f, 0x81, 0xb5, 0xfa, 0x2f, 0x24, 0x68, 0xe6, 0x4c, 0x7b, 0x1b, 0xb7, 0x60,
+	0x81, 0x12, 0xf9, 0x79, 0x9e, 0x21, 0x42, 0xed, 0x0c, 0x11, 0xf4, 0xd7, 0x2b, 0xa0, 0x14, 0xcc,
+	0x25, 0xd7, 0x41, 0x99, 0x06, 0xa9, 0x9f, 0x58, 0x8e, 0x9f, 0xe0, 0x91, 0xcb, 0xdb, 0x15, 0xda,
+	0x44, 0xd5, 0x8e, 0x9f, 0x90, 0x9b, 0xb0, 0x2a, 0xcc, 0x47, 0x6e, 0x60, 0x27, 0x62, 0xad, 0xed,
+	0x0a, 0x05, 0x54, 0xde, 0xe3, 0x3a, 0xa2, 0x42, 0x2d, 0x4e, 0x3d, 0x5c, 0x49, 0xa2, 0xfc, 0x93,
+	0x5c, 0x81, 0x7a, 0x3c, 0x3d, 0x66, 0x9e, 0x8d, 0x87, 0x7b, 0x89, 0x66, 0x12, 0xf9, 0x2a, 0x74,
+	0x7e, 0xca, 0xa2, 0xc0, 0x4a, 0x8e, 0x23, 0x16, 0x1f, 0x07, 0xee, 0x21, 0x1e, 0xb4, 0x44, 0xdb,
+	0x5c, 0x3b, 0xce, 0x95, 0xe4, 0xfd, 0x0c, 0x56, 0xe6, 0x55, 0xc7, 0xbc, 0x24, 0xda, 0xe2, 0xfa,
+	0x7e, 0x9e, 0xdb, 0x37, 0x40, 0x9d, 0xc3, 0x89, 0x04, 0x1b, 0x98, 0xa0, 0x44, 0x3b, 0x05, 0x52,
+	0x24, 0x69, 0x40, 0xc7, 0xc7, 0x3e, 0xf6, 0x98, 0x59, 0x71, 0x68, 0xfb, 0xb1, 0xd6, 0x5c, 0xfe,
+	0x11, 0xe9, 0xa5, 0xd3, 0xcf, 0x59, 0x32, 0x0a, 0x6d, 0x3f, 0xbb, 0xce, 0xed, 0xdc, 0x83, 0xeb,
+	0x62, 0xf2, 0x35, 0x58, 0x2b, 0x42, 0x1c, 0x32, 0x37, 0xb1, 0x63, 0x4d, 0x59, 0xaf, 0x6d, 0x10,
+	0x5a, 0x44, 0xde, 0x42, 0xed, 0x02, 0x10, 0x73, 0x8b, 0x35, 0x58, 0xaf, 0x6d, 0x48, 0x25, 0x10,
+	0x13, 0xe3, 0xbd, 0xb0, 0x13, 0x06, 0xb1, 0x33, 0x97, 0xd4, 0xea, 0x7f, 0x4e, 0x2a, 0xf7, 0x28,
+	0x92, 0x2a, 0x42, 0x64, 0x49, 0xb5, 0x44, 0x52, 0xb9, 0xba, 0x4c, 0xaa, 0x00, 0x66, 0x49, 0xb5,
+	0x45, 0x52, 0xb9, 0x3a, 0x4b, 0xea, 0x2e, 0x40, 0xc4, 0x62, 0x96, 0x58, 0xc7, 0xbc, 0xf2, 0x1d,
+	0x6c, 0x02, 0xd7, 0xcf, 0xe9, 0x79, 0x9b, 0x94, 0xa3, 0xb6, 0x1d, 0x3f, 0xa1, 0x4a, 0x94, 0x7f,
+	0x92, 0x77, 0x41, 0x29, 0xdb, 0xdd, 0x1a, 0x92, 0xaf, 0x54, 0x90, 0xf7, 0xa0, 0x3d, 0x4d, 0xe3,
+	0x24, 0xf0, 0x2c, 0x64, 0x6b, 0xac, 0xa9, 0x98, 0x42, 0x4b, 0x28, 0x1f, 0xa2, 0x4e, 0xff, 0x18,
+	0x94, 0x22, 0xf4, 0xe2, 0x7d, 0x6f, 0x40, 0xed, 0x33, 0x73, 0xa4, 0x4a, 0xa4, 0x0e, 0xd5, 0xc1,
+	0x50, 0xad, 0x96, 0x77, 0xbe, 0x76, 0x4d, 0xfe, 0xe5, 0x1f, 0xba, 0x52, 0xaf, 0x01, 0x2b, 0xb8,
+	0xb9, 0x5e, 0x0b, 0xa0, 0xe4, 0x86, 0xfe, 0x17, 0x19, 0x3a, 0xc8, 0x83, 0x92, 0xf7, 0x31, 0x10,
+	0xb4, 0xb1, 0xc8, 0x5a, 0xda, 0x6e, 0xbb, 0x67, 0xfe, 0xf3, 0xc5, 0x0d, 0x63, 0x6e, 0x62, 0x09,
+	0xa3, 0xc0, 0x63, 0xc9, 0x31, 0x4b, 0xe3, 0xf9, 0x4f, 0x2f, 0x38, 0x64, 0xee, 0xad, 0xa2, 0xe5,
+	0x6f, 0xf6, 0x45, 0xb8, 0xb2, 0x2c, 0xea, 0x74, 0x49, 0xf3, 0xff, 0x5e, 0x8c, 0xeb, 0xf3, 0x9b,
+	0x12, 0x54, 0xa7, 0x4a, 0x41, 0x74, 0xde, 0x11, 0x84, 0x25, 0xeb, 0x08, 0x28, 0x9c, 0x73, 0x3d,
+	0xdf, 0x02, 0xed, 0xde, 0xc2, 0x75, 0xfa, 0x3a, 0xa8, 0x45, 0x16, 0x07, 0x88, 0xcd, 0x19, 0x59,
+	0x10, 0x55, 0x84, 0x40, 0x68, 0xb1, 0x5a, 0x0e, 0x15, 0x37, 0xaa, 0xb8, 0x68, 0x19, 0xf4, 0x81,
+	0xdc, 0x94, 0xd4, 0xea, 0x03, 0xb9, 0x59, 0x57, 0x1b, 0x0f, 0xe4, 0xa6, 0xa2, 0xc2, 0x03, 0xb9,
+	0xd9, 0x52, 0xdb, 0x0f, 0xe4, 0xe6, 0x9a, 0xaa, 0xd2, 0xb2, 0xd5, 0xd1, 0xa5, 0x16, 0x43, 0x97,
+	0xef, 0x36, 0x5d, 0xbe, 0x57, 0x73, 0x3c, 0xd6, 0xef, 0x02, 0x94, 0xdb, 0xe3, 0xa7, 0x1a, 0x1c,
+	0x1d, 0xc5, 0x4c, 0xf4, 0xcf, 0x4b, 0x34, 0x93, 0xb8, 0xde, 0x65, 0xfe, 0x2c, 0x39, 0xc6, 0x03,
+	0x69, 0xd3, 0x4c, 0xd2, 0x53, 0x20, 0x8b, 0x64, 0xc4, 0x9f, 0xfd, 0x37, 0xf8, 0x09, 0xbf, 0x0b,
+	0x4a, 0x41, 0x37, 0x5c, 0x6b, 0x61, 0xf2, 0x5c, 0x8c, 0x99, 0x4d, 0x9e, 0xa5, 0x83, 0xee, 0xc3,
+	0x9a, 0x98, 0x16, 0xca, 0x4b, 0x50, 0x30, 0x46, 0x3a, 0x87, 0x31, 0xd5, 0x92, 0x31, 0x1f, 0x40,
+	0x23, 0xaf, 0xbb, 0x98, 0x9e, 0xae, 0x9e, 0x37, 0x04, 0x21, 0x82, 0xe6, 0x48, 0x3d, 0x86, 0xb5,
+	0x25, 0x1b, 0xe9, 0x02, 0x1c, 0x04, 0xa9, 0x7f, 0x68, 0x67, 0x63, 0xbc, 0xb4, 0xb1, 0x42, 0xe7,
+	0x34, 0x3c, 0x1f, 0x37, 0x78, 0xc2, 0xa2, 0x9c, 0xc1, 0x28, 0x70, 0x6d, 0x1a, 0x86, 0x2c, 0xca,
+	0x38, 0x2c, 0x84, 0x32, 0x77, 0x79, 0x2e, 0x77, 0xdd, 0x85, 0x77, 0x96, 0x36, 0x89, 0xc5, 0x5d,
+	0x68, 0x4b, 0xd5, 0xe5, 0xb6, 0xf4, 0xe1, 0xd9, 0xba, 0x5e, 0x5d, 0x1e, 0x29, 0x8b, 0x78, 0xf3,
+	0x25, 0xfd, 0xa3, 0x0c, 0xed, 0x1f, 0xa6, 0x2c, 0x3a, 0xc9, 0xa7, 0x5d, 0x72, 0x07, 0xea, 0x71,
+	0x62, 0x27, 0x69, 0x9c, 0x8d, 0x4f, 0xdd, 0x32, 0xce, 0x02, 0x70, 0x73, 0x84, 0x28, 0x9a, 0xa1,
+	0xc9, 0x0f, 0x00, 0x58, 0x14, 0x05, 0x91, 0x85, 0xa3, 0xd7, 0x99, 0x27, 0xc9, 0xa2, 0xaf, 0xc9,
+	0x91, 0x38, 0x78, 0x29, 0x2c, 0xff, 0xe4, 0xf5, 0x40, 0x01, 0xab, 0xa4, 0x50, 0x21, 0x90, 0x4d,
+	0x9e, 0x4f, 0xe4, 0xf8, 0x33, 0x2c, 0xd3, 0xc2, 0x05, 0x1d, 0xa1, 0x7e, 0xcb, 0x4e, 0xec, 0xed,
+	0x0a, 0xcd, 0x50, 0x1c, 0xff, 0x98, 0x4d, 0x93, 0x20, 0xd2, 0x56, 0x96, 0xf1, 0x0f, 0x51, 0x9f,
+	0xe3, 0x05, 0x0a, 0xe3, 0x4f, 0x6d, 0xd7, 0x8e, 0xb4, 0xfa, 0x32, 0x7e, 0x84, 0xfa, 0x22, 0x3e,
+	0x4a, 0x1c, 0xef, 0xd9, 0x49, 0xe4, 0x3c, 0xd5, 0x1a, 0xcb, 0xf8, 0x3d, 0xd4, 0xe7, 0x78, 0x81,
+	0xd2, 0xdf, 0x87, 0xba, 0xa8, 0x14, 0xef, 0xf5, 0x26, 0xa5, 0x43, 0x2a, 0xe6, 0xbe, 0xd1, 0xa4,
+	0xdf, 0x37, 0x47, 0x23, 0x55, 0x12, 0x8d, 0x5f, 0xff, 0x8d, 0x04, 0x4a, 0x51, 0x16, 0x3e, 0xd0,
+	0x0d, 0x86, 0x03, 0x53, 0x40, 0xc7, 0x3b, 0x7b, 0xe6, 0x70, 0x32, 0x56, 0x25, 0x3e, 0xdd, 0xf5,
+	0x8d, 0x41, 0xdf, 0xdc, 0x35, 0xb7, 0xc4, 0x94, 0x68, 0xfe, 0xc8, 0xec, 0x4f, 0xc6, 0x3b, 0xc3,
+	0x81, 0x5a, 0xe3, 0xc6, 0x9e, 0xb1, 0x65, 0x6d, 0x19, 0x63, 0x43, 0x95, 0xb9, 0xb4, 0xc3, 0x07,
+	0xcb, 0x81, 0xb1, 0xab, 0xae, 0x90, 0x35, 0x58, 0x9d, 0x0c, 0x8c, 0x87, 0xc6, 0xce, 0xae, 0xd1,
+	0xdb, 0x35, 0xd5, 0x3a, 0xf7, 0x1d, 0x0c, 0xc7, 0xd6, 0xbd, 0xe1, 0x64, 0xb0, 0xa5, 0x36, 0xf8,
+	0x84, 0xc9, 0x45, 0xa3, 0xdf, 0x37, 0xf7, 0xc7, 0x08, 0x69, 0x66, 0x3f, 0x48, 0x75, 0x90, 0xf9,
+	0xb0, 0xac, 0x9b, 0x00, 0x65, 0xbd, 0x17, 0x67, 0x71, 0xe5, 0xa2, 0xd9, 0xed, 0x6c, 0x07, 0xd0,
+	0x7f, 0x2e, 0x01, 0x94, 0xe7, 0x40, 0xee, 0x94, 0x2f, 0x21, 0x31, 0x47, 0x5e, 0x59, 0x3e, 0xae,
+	0xf3, 0xdf, 0x43, 0xdf, 0x5f, 0x78, 0xd7, 0x54, 0x97, 0xaf, 0xb4, 0x70, 0xfd, 0x37, 0xaf, 0x1b,
+	0xdd, 0x82, 0xd6, 0x7c, 0x7c, 0xde, 0xea, 0xc4, 0x80, 0x8f, 0x79, 0x28, 0x34, 0x93, 0xfe, 0xf7,
+	0x21, 0xf5, 0x57, 0x12, 0xac, 0x2d, 0xa5, 0x71, 0xe1, 0x22, 0x0b, 0x6d, 0xb1, 0xfa, 0x06, 0x6d,
+	0xb1, 0x32, 0x77, 0x87, 0xdf, 0x24, 0x19, 0x7e, 0x78, 0x05, 0x99, 0xcf, 0x7f, 0x48, 0xbd, 0xc9,
+	0xe1, 0xf5, 0x00, 0x4a, 0x8e, 0x93, 0xef, 0x42, 0x7d, 0xe1, 0xef, 0x8c, 0x2b, 0xcb, 0x37, 0x21,
+	0xfb, 0x43, 0x43, 0x24, 0x9c, 0x61, 0xf5, 0xdf, 0x49, 0xd0, 0x9a, 0x37, 0x5f, 0x58, 0x94, 0xff,
+	0xfe, 0x91, 0xdc, 0x5b, 0x20, 0x85, 0xe8, 0xf3, 0xef, 0x5e, 0x54, 0x47, 0x7c, 0xa0, 0x9c, 0xe1,
+	0x45, 0xef, 0x93, 0xe7, 0x2f, 0xbb, 0x95, 0x2f, 0x5e, 0x76, 0x2b, 0xaf, 0x5f, 0x76, 0xa5, 0x9f,
+	0x9d, 0x76, 0xa5, 0x3f, 0x9d, 0x76, 0xa5, 0x67, 0xa7, 0x5d, 0xe9, 0xf9, 0x69, 0x57, 0xfa, 0xdb,
+	0x69, 0x57, 0xfa, 0xf2, 0xb4, 0x5b, 0x79, 0x7d, 0xda, 0x95, 0x7e, 0xfd, 0xaa, 0x5b, 0x79, 0xfe,
+	0xaa, 0x5b, 0xf9, 0xe2, 0x55, 0xb7, 0xf2, 0xe3, 0x06, 0xfe, 0x69, 0x14, 0x1e, 0x1c, 0xd4, 0xf1,
+	0xef, 0x9f, 0x0f, 0xfe, 0x35, 0x00, 0xd0, 0xd5, 0x54, 0x3e, 0x46, 0x12, 0x00, 0x00,
 }
 
 func (x WriteRequest_SourceEnum) String() string {
@@ -1976,6 +2024,12 @@ func (this *WriteRequest) Equal(that interface{}) bool {
 	if this.SkipLabelNameValidation != that1.SkipLabelNameValidation {
 		return false
 	}
+	if this.NonElectedReplicaWindowMs != that1.NonElectedReplicaWindowMs {
+		return false
+	}
+	if this.StreamingAggregationRule != that1.StreamingAggregationRule {
+		return false
+	}
 	return true
 }
 func (this *WriteResponse) Equal(that interface{}) bool {
@@ -2050,6 +2104,9 @@ func (this *TimeSeries) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.CreatedTimestamp != that1.CreatedTimestamp {
+		return false
+	}
 	return true
 }
 func (this *LabelPair) Equal(that interface{}) bool {
@@ -2303,6 +2360,14 @@ func (this *Histogram) Equal(that interface{}) bool {
 	if this.Timestamp != that1.Timestamp {
 		return false
 	}
+	if len(this.CustomValues) != len(that1.CustomValues) {
+		return false
+	}
+	for i := range this.CustomValues {
+		if this.CustomValues[i] != that1.CustomValues[i] {
+			return false
+		}
+	}
 	return true
 }
 func (this *Histogram_CountInt) Equal(that interface{}) bool {
@@ -2995,7 +3060,7 @@ func (this *WriteRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
+	s := make([]string, 0, 10)
 	s = append(s, "&mimirpb.WriteRequest{")
 	s = append(s, "Timeseries: "+fmt.Sprintf("%#v", this.Timeseries)+",\n")
 	s = append(s, "Source: "+fmt.Sprintf("%#v", this.Source)+",\n")
@@ -3003,6 +3068,8 @@ func (this *WriteRequest) GoString() string {
 		s = append(s, "Metadata: "+fmt.Sprintf("%#v", this.Metadata)+",\n")
 	}
 	s = append(s, "SkipLabelNameValidation: "+fmt.Sprintf("%#v", this.SkipLabelNameValidation)+",\n")
+	s = append(s, "NonElectedReplicaWindowMs: "+fmt.Sprintf("%#v", this.NonElectedReplicaWindowMs)+",\n")
+	s = append(s, "StreamingAggregationRule: "+fmt.Sprintf("%#v", this.StreamingAggregationRule)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -3019,7 +3086,7 @@ func (this *TimeSeries) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
+	s := make([]string, 0, 9)
 	s = append(s, "&mimirpb.TimeSeries{")
 	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
 	if this.Samples != nil {
@@ -3043,6 +3110,7 @@ func (this *TimeSeries) GoString() string {
 		}
 		s = append(s, "Histograms: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
+	s = append(s, "CreatedTimestamp: "+fmt.Sprintf("%#v", this.CreatedTimestamp)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -3107,7 +3175,7 @@ func (this *Histogram) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 19)
+	s := make([]string, 0, 20)
 	s = append(s, "&mimirpb.Histogram{")
 	if this.Count != nil {
 		s = append(s, "Count: "+fmt.Sprintf("%#v", this.Count)+",\n")
@@ -3138,6 +3206,7 @@ func (this *Histogram) GoString() string {
 	s = append(s, "PositiveCounts: "+fmt.Sprintf("%#v", this.PositiveCounts)+",\n")
 	s = append(s, "ResetHint: "+fmt.Sprintf("%#v", this.ResetHint)+",\n")
 	s = append(s, "Timestamp: "+fmt.Sprintf("%#v", this.Timestamp)+",\n")
+	s = append(s, "CustomValues: "+fmt.Sprintf("%#v", this.CustomValues)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -3452,6 +3521,22 @@ func (m *WriteRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.StreamingAggregationRule) > 0 {
+		i -= len(m.StreamingAggregationRule)
+		copy(dAtA[i:], m.StreamingAggregationRule)
+		i = encodeVarintMimir(dAtA, i, uint64(len(m.StreamingAggregationRule)))
+		i--
+		dAtA[i] = 0x3e
+		i--
+		dAtA[i] = 0xd2
+	}
+	if m.NonElectedReplicaWindowMs != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.NonElectedReplicaWindowMs))
+		i--
+		dAtA[i] = 0x3e
+		i--
+		dAtA[i] = 0xc8
+	}
 	if m.SkipLabelNameValidation {
 		i--
 		if m.SkipLabelNameValidation {
@@ -3543,6 +3628,11 @@ func (m *TimeSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.CreatedTimestamp != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.CreatedTimestamp))
+		i--
+		dAtA[i] = 0x28
+	}
 	if len(m.Histograms) > 0 {
 		for iNdEx := len(m.Histograms) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -3827,6 +3917,18 @@ func (m *Histogram) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.CustomValues) > 0 {
+		for iNdEx := len(m.CustomValues) - 1; iNdEx >= 0; iNdEx-- {
+			f1 := math.Float64bits(float64(m.CustomValues[iNdEx]))
+			i -= 8
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f1))
+		}
+		i = encodeVarintMimir(dAtA, i, uint64(len(m.CustomValues)*8))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
 	if m.Timestamp != 0 {
 		i = encodeVarintMimir(dAtA, i, uint64(m.Timestamp))
 		i--
@@ -4791,6 +4893,13 @@ func (m *WriteRequest) Size() (n int) {
 	if m.SkipLabelNameValidation {
 		n += 3
 	}
+	if m.NonElectedReplicaWindowMs != 0 {
+		n += 2 + sovMimir(uint64(m.NonElectedReplicaWindowMs))
+	}
+	l = len(m.StreamingAggregationRule)
+	if l > 0 {
+		n += 2 + l + sovMimir(uint64(l))
+	}
 	return n
 }
 
@@ -4833,6 +4942,9 @@ func (m *TimeSeries) Size() (n int) {
 			n += 1 + l + sovMimir(uint64(l))
 		}
 	}
+	if m.CreatedTimestamp != 0 {
+		n += 1 + sovMimir(uint64(m.CreatedTimestamp))
+	}
 	return n
 }
 
@@ -4987,6 +5099,9 @@ func (m *Histogram) Size() (n int) {
 	if m.Timestamp != 0 {
 		n += 1 + sovMimir(uint64(m.Timestamp))
 	}
+	if len(m.CustomValues) > 0 {
+		n += 2 + sovMimir(uint64(len(m.CustomValues)*8)) + len(m.CustomValues)*8
+	}
 	return n
 }
 
@@ -5385,6 +5500,8 @@ func (this *WriteRequest) String() string {
 		`Source:` + fmt.Sprintf("%v", this.Source) + `,`,
 		`Metadata:` + repeatedStringForMetadata + `,`,
 		`SkipLabelNameValidation:` + fmt.Sprintf("%v", this.SkipLabelNameValidation) + `,`,
+		`NonElectedReplicaWindowMs:` + fmt.Sprintf("%v", this.NonElectedReplicaWindowMs) + `,`,
+		`StreamingAggregationRule:` + fmt.Sprintf("%v", this.StreamingAggregationRule) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -5422,6 +5539,7 @@ func (this *TimeSeries) String() string {
 		`Samples:` + repeatedStringForSamples + `,`,
 		`Exemplars:` + repeatedStringForExemplars + `,`,
 		`Histograms:` + repeatedStringForHistograms + `,`,
+		`CreatedTimestamp:` + fmt.Sprintf("%v", this.CreatedTimestamp) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -5511,6 +5629,7 @@ func (this *Histogram) String() string {
 		`PositiveCounts:` + fmt.Sprintf("%v", this.PositiveCounts) + `,`,
 		`ResetHint:` + fmt.Sprintf("%v", this.ResetHint) + `,`,
 		`Timestamp:` + fmt.Sprintf("%v", this.Timestamp) + `,`,
+		`CustomValues:` + fmt.Sprintf("%v", this.CustomValues) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -5948,6 +6067,57 @@ func (m *WriteRequest) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.SkipLabelNameValidation = bool(v != 0)
+		case 1001:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NonElectedReplicaWindowMs", wireType)
+			}
+			m.NonElectedReplicaWindowMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NonElectedReplicaWindowMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 1002:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StreamingAggregationRule", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMimir
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMimir
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StreamingAggregationRule = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMimir(dAtA[iNdEx:])
@@ -6190,6 +6360,25 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedTimestamp", wireType)
+			}
+			m.CreatedTimestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatedTimestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMimir(dAtA[iNdEx:])
@@ -7294,6 +7483,60 @@ func (m *Histogram) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 16:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.CustomValues = append(m.CustomValues, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMimir
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMimir
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthMimir
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 8
+				if elementCount != 0 && len(m.CustomValues) == 0 {
+					m.CustomValues = make([]float64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.CustomValues = append(m.CustomValues, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field CustomValues", wireType)
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMimir(dAtA[iNdEx:])
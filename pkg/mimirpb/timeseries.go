@@ -440,6 +440,7 @@ func ReuseTimeseries(ts *TimeSeries) {
 	ts.Labels = ts.Labels[:0]
 	ts.Samples = ts.Samples[:0]
 	ts.Histograms = ts.Histograms[:0]
+	ts.CreatedTimestamp = 0
 
 	ClearExemplars(ts)
 	timeSeriesPool.Put(ts)
@@ -507,6 +508,7 @@ func DeepCopyTimeseries(dst, src PreallocTimeseries, keepExemplars bool) Preallo
 		dstTs.Samples = dstTs.Samples[:len(srcTs.Samples)]
 	}
 	copy(dstTs.Samples, srcTs.Samples)
+	dstTs.CreatedTimestamp = srcTs.CreatedTimestamp
 
 	// Prepare the slice of exemplars.
 	if keepExemplars {
@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/util/annotations"
@@ -34,6 +35,7 @@ import (
 	grpc_metadata "google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/storage/bucket"
 	"github.com/grafana/mimir/pkg/storage/series"
@@ -302,6 +304,32 @@ func (q *BlocksStoreQueryable) Querier(mint, maxt int64) (storage.Querier, error
 		return nil, errors.Errorf("BlocksStoreQueryable is not running: %v", s)
 	}
 
+	return q.newQuerier(mint, maxt), nil
+}
+
+// ExemplarQuerier returns a new storage.ExemplarQuerier querying the exemplars persisted in the blocks.
+func (q *BlocksStoreQueryable) ExemplarQuerier(ctx context.Context) (storage.ExemplarQuerier, error) {
+	if s := q.State(); s != services.Running {
+		return nil, errors.Errorf("BlocksStoreQueryable is not running: %v", s)
+	}
+
+	return &blocksStoreExemplarQuerier{
+		ctx:       ctx,
+		queryable: q,
+	}, nil
+}
+
+// MetricMetadata returns the metric metadata persisted in the blocks, observed within the [mint, maxt]
+// time range. If metric is not empty, only the metadata of that metric is returned.
+func (q *BlocksStoreQueryable) MetricMetadata(ctx context.Context, mint, maxt int64, metric string) ([]block.MetricMetadata, error) {
+	if s := q.State(); s != services.Running {
+		return nil, errors.Errorf("BlocksStoreQueryable is not running: %v", s)
+	}
+
+	return q.newQuerier(mint, maxt).selectMetricMetadata(ctx, metric)
+}
+
+func (q *BlocksStoreQueryable) newQuerier(mint, maxt int64) *blocksStoreQuerier {
 	return &blocksStoreQuerier{
 		minT:                     mint,
 		maxT:                     maxt,
@@ -313,7 +341,29 @@ func (q *BlocksStoreQueryable) Querier(mint, maxt int64) (storage.Querier, error
 		consistency:              q.consistency,
 		logger:                   q.logger,
 		queryStoreAfter:          q.queryStoreAfter,
-	}, nil
+	}
+}
+
+type blocksStoreExemplarQuerier struct {
+	ctx       context.Context
+	queryable *BlocksStoreQueryable
+}
+
+// Select implements storage.ExemplarQuerier interface.
+func (q *blocksStoreExemplarQuerier) Select(start, end int64, matchers ...[]*labels.Matcher) ([]exemplar.QueryResult, error) {
+	series, err := q.queryable.newQuerier(start, end).selectExemplars(q.ctx, matchers...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]exemplar.QueryResult, 0, len(series))
+	for _, s := range series {
+		result = append(result, exemplar.QueryResult{
+			SeriesLabels: mimirpb.FromLabelAdaptersToLabels(s.Labels),
+			Exemplars:    mimirpb.FromExemplarProtosToExemplars(s.Exemplars),
+		})
+	}
+	return result, nil
 }
 
 type blocksStoreQuerier struct {
@@ -431,6 +481,78 @@ func (q *blocksStoreQuerier) LabelValues(ctx context.Context, name string, match
 	return util.MergeSlices(resValueSets...), resWarnings, nil
 }
 
+// selectExemplars returns the exemplars persisted in the blocks, of the series matching any of the sets of matchers.
+func (q *blocksStoreQuerier) selectExemplars(ctx context.Context, matchers ...[]*labels.Matcher) ([]mimirpb.TimeSeries, error) {
+	spanLog, ctx := spanlogger.NewWithLogger(ctx, q.logger, "blocksStoreQuerier.selectExemplars")
+	defer spanLog.Span.Finish()
+
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	level.Debug(spanLog).Log("start", util.TimeFromMillis(q.minT).UTC().String(), "end",
+		util.TimeFromMillis(q.maxT).UTC().String(), "matchers", util.MultiMatchersStringer(matchers))
+
+	var (
+		resSeriesSets     = [][]mimirpb.TimeSeries{}
+		convertedMatchers = make([]storepb.ExemplarMatchers, 0, len(matchers))
+	)
+	for _, set := range matchers {
+		convertedMatchers = append(convertedMatchers, storepb.ExemplarMatchers{Matchers: convertMatchersToLabelMatcher(set)})
+	}
+
+	queryF := func(clients map[BlocksStoreClient][]ulid.ULID, minT, maxT int64) ([]ulid.ULID, error) {
+		seriesSets, queriedBlocks, err := q.fetchExemplarsFromStore(ctx, clients, minT, maxT, tenantID, convertedMatchers)
+		if err != nil {
+			return nil, err
+		}
+
+		resSeriesSets = append(resSeriesSets, seriesSets...)
+
+		return queriedBlocks, nil
+	}
+
+	if err := q.queryWithConsistencyCheck(ctx, spanLog, q.minT, q.maxT, tenantID, nil, queryF); err != nil {
+		return nil, err
+	}
+
+	return block.MergeExemplars(resSeriesSets...), nil
+}
+
+// selectMetricMetadata returns the metric metadata persisted in the blocks, optionally restricted to the input metric.
+func (q *blocksStoreQuerier) selectMetricMetadata(ctx context.Context, metric string) ([]block.MetricMetadata, error) {
+	spanLog, ctx := spanlogger.NewWithLogger(ctx, q.logger, "blocksStoreQuerier.selectMetricMetadata")
+	defer spanLog.Span.Finish()
+
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	level.Debug(spanLog).Log("start", util.TimeFromMillis(q.minT).UTC().String(), "end",
+		util.TimeFromMillis(q.maxT).UTC().String(), "metric", metric)
+
+	resMetadataSets := [][]block.MetricMetadata{}
+
+	queryF := func(clients map[BlocksStoreClient][]ulid.ULID, minT, maxT int64) ([]ulid.ULID, error) {
+		metadataSets, queriedBlocks, err := q.fetchMetricMetadataFromStore(ctx, clients, minT, maxT, tenantID, metric)
+		if err != nil {
+			return nil, err
+		}
+
+		resMetadataSets = append(resMetadataSets, metadataSets...)
+
+		return queriedBlocks, nil
+	}
+
+	if err := q.queryWithConsistencyCheck(ctx, spanLog, q.minT, q.maxT, tenantID, nil, queryF); err != nil {
+		return nil, err
+	}
+
+	return block.MergeMetadata(resMetadataSets...), nil
+}
+
 func (q *blocksStoreQuerier) Close() error {
 	return nil
 }
@@ -1105,6 +1227,174 @@ func (q *blocksStoreQuerier) fetchLabelValuesFromStore(
 	return valueSets, warnings, queriedBlocks, nil
 }
 
+func (q *blocksStoreQuerier) fetchExemplarsFromStore(
+	ctx context.Context,
+	clients map[BlocksStoreClient][]ulid.ULID,
+	minT int64,
+	maxT int64,
+	tenantID string,
+	matchers []storepb.ExemplarMatchers,
+) ([][]mimirpb.TimeSeries, []ulid.ULID, error) {
+	var (
+		reqCtx        = grpc_metadata.AppendToOutgoingContext(ctx, storegateway.GrpcContextMetadataTenantID, tenantID)
+		g, gCtx       = errgroup.WithContext(reqCtx)
+		mtx           = sync.Mutex{}
+		seriesSets    = [][]mimirpb.TimeSeries{}
+		queriedBlocks = []ulid.ULID(nil)
+		spanLog       = spanlogger.FromContext(ctx, q.logger)
+	)
+
+	// Concurrently fetch exemplars from all clients.
+	for c, blockIDs := range clients {
+		// Change variables scope since it will be used in a goroutine.
+		c := c
+		blockIDs := blockIDs
+
+		g.Go(func() error {
+			req, err := createExemplarsRequest(minT, maxT, blockIDs, matchers)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create exemplars request")
+			}
+
+			exemplarsResp, err := c.Exemplars(gCtx, req)
+			if err != nil {
+				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+					return err
+				}
+
+				level.Warn(spanLog).Log("msg", "failed to fetch exemplars", "remote", c.RemoteAddress(), "err", err)
+				return nil
+			}
+
+			myQueriedBlocks := []ulid.ULID(nil)
+			if exemplarsResp.Hints != nil {
+				hints := hintspb.ExemplarsResponseHints{}
+				if err := types.UnmarshalAny(exemplarsResp.Hints, &hints); err != nil {
+					return errors.Wrapf(err, "failed to unmarshal exemplars hints from %s", c.RemoteAddress())
+				}
+
+				ids, err := convertBlockHintsToULIDs(hints.QueriedBlocks)
+				if err != nil {
+					return errors.Wrapf(err, "failed to parse queried block IDs from received hints")
+				}
+
+				myQueriedBlocks = ids
+			}
+
+			level.Debug(spanLog).Log("msg", "received exemplars from store-gateway",
+				"instance", c,
+				"num series", len(exemplarsResp.Timeseries),
+				"requested blocks", strings.Join(convertULIDsToString(blockIDs), " "),
+				"queried blocks", strings.Join(convertULIDsToString(myQueriedBlocks), " "))
+
+			// Store the result.
+			mtx.Lock()
+			seriesSets = append(seriesSets, exemplarsResp.Timeseries)
+			queriedBlocks = append(queriedBlocks, myQueriedBlocks...)
+			mtx.Unlock()
+
+			return nil
+		})
+	}
+
+	// Wait until all client requests complete.
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	return seriesSets, queriedBlocks, nil
+}
+
+func (q *blocksStoreQuerier) fetchMetricMetadataFromStore(
+	ctx context.Context,
+	clients map[BlocksStoreClient][]ulid.ULID,
+	minT int64,
+	maxT int64,
+	tenantID string,
+	metric string,
+) ([][]block.MetricMetadata, []ulid.ULID, error) {
+	var (
+		reqCtx        = grpc_metadata.AppendToOutgoingContext(ctx, storegateway.GrpcContextMetadataTenantID, tenantID)
+		g, gCtx       = errgroup.WithContext(reqCtx)
+		mtx           = sync.Mutex{}
+		metadataSets  = [][]block.MetricMetadata{}
+		queriedBlocks = []ulid.ULID(nil)
+		spanLog       = spanlogger.FromContext(ctx, q.logger)
+	)
+
+	// Concurrently fetch metric metadata from all clients.
+	for c, blockIDs := range clients {
+		// Change variables scope since it will be used in a goroutine.
+		c := c
+		blockIDs := blockIDs
+
+		g.Go(func() error {
+			req, err := createMetricMetadataRequest(minT, maxT, blockIDs, metric)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create metric metadata request")
+			}
+
+			metadataResp, err := c.MetricMetadata(gCtx, req)
+			if err != nil {
+				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+					return err
+				}
+
+				level.Warn(spanLog).Log("msg", "failed to fetch metric metadata", "remote", c.RemoteAddress(), "err", err)
+				return nil
+			}
+
+			myQueriedBlocks := []ulid.ULID(nil)
+			if metadataResp.Hints != nil {
+				hints := hintspb.MetricMetadataResponseHints{}
+				if err := types.UnmarshalAny(metadataResp.Hints, &hints); err != nil {
+					return errors.Wrapf(err, "failed to unmarshal metric metadata hints from %s", c.RemoteAddress())
+				}
+
+				ids, err := convertBlockHintsToULIDs(hints.QueriedBlocks)
+				if err != nil {
+					return errors.Wrapf(err, "failed to parse queried block IDs from received hints")
+				}
+
+				myQueriedBlocks = ids
+			}
+
+			level.Debug(spanLog).Log("msg", "received metric metadata from store-gateway",
+				"instance", c,
+				"num metadata", len(metadataResp.Metadata),
+				"requested blocks", strings.Join(convertULIDsToString(blockIDs), " "),
+				"queried blocks", strings.Join(convertULIDsToString(myQueriedBlocks), " "))
+
+			metadata := make([]block.MetricMetadata, 0, len(metadataResp.Metadata))
+			for _, m := range metadataResp.Metadata {
+				metadata = append(metadata, block.MetricMetadata{
+					Metric:  m.Metric,
+					Type:    m.Type,
+					Help:    m.Help,
+					Unit:    m.Unit,
+					MinTime: m.MinTime,
+					MaxTime: m.MaxTime,
+				})
+			}
+
+			// Store the result.
+			mtx.Lock()
+			metadataSets = append(metadataSets, metadata)
+			queriedBlocks = append(queriedBlocks, myQueriedBlocks...)
+			mtx.Unlock()
+
+			return nil
+		})
+	}
+
+	// Wait until all client requests complete.
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	return metadataSets, queriedBlocks, nil
+}
+
 func createSeriesRequest(minT, maxT int64, matchers []storepb.LabelMatcher, skipChunks bool, blockIDs []ulid.ULID, streamingBatchSize uint64) (*storepb.SeriesRequest, error) {
 	// Selectively query only specific blocks.
 	hints := &hintspb.SeriesRequestHints{
@@ -1193,6 +1483,62 @@ func createLabelValuesRequest(minT, maxT int64, label string, blockIDs []ulid.UL
 	return req, nil
 }
 
+func createExemplarsRequest(minT, maxT int64, blockIDs []ulid.ULID, matchers []storepb.ExemplarMatchers) (*storepb.ExemplarsRequest, error) {
+	req := &storepb.ExemplarsRequest{
+		Start:    minT,
+		End:      maxT,
+		Matchers: matchers,
+	}
+
+	// Selectively query only specific blocks.
+	hints := &hintspb.ExemplarsRequestHints{
+		BlockMatchers: []storepb.LabelMatcher{
+			{
+				Type:  storepb.LabelMatcher_RE,
+				Name:  block.BlockIDLabel,
+				Value: strings.Join(convertULIDsToString(blockIDs), "|"),
+			},
+		},
+	}
+
+	anyHints, err := types.MarshalAny(hints)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal exemplars request hints")
+	}
+
+	req.Hints = anyHints
+
+	return req, nil
+}
+
+func createMetricMetadataRequest(minT, maxT int64, blockIDs []ulid.ULID, metric string) (*storepb.MetricMetadataRequest, error) {
+	req := &storepb.MetricMetadataRequest{
+		Start:  minT,
+		End:    maxT,
+		Metric: metric,
+	}
+
+	// Selectively query only specific blocks.
+	hints := &hintspb.MetricMetadataRequestHints{
+		BlockMatchers: []storepb.LabelMatcher{
+			{
+				Type:  storepb.LabelMatcher_RE,
+				Name:  block.BlockIDLabel,
+				Value: strings.Join(convertULIDsToString(blockIDs), "|"),
+			},
+		},
+	}
+
+	anyHints, err := types.MarshalAny(hints)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal metric metadata request hints")
+	}
+
+	req.Hints = anyHints
+
+	return req, nil
+}
+
 func convertULIDsToString(ids []ulid.ULID) []string {
 	res := make([]string, len(ids))
 	for idx, id := range ids {
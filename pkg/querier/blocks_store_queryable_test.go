@@ -37,6 +37,7 @@ import (
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/storage/sharding"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
 	"github.com/grafana/mimir/pkg/storegateway/hintspb"
 	"github.com/grafana/mimir/pkg/storegateway/storegatewaypb"
@@ -1608,6 +1609,227 @@ func TestBlocksStoreQuerier_Labels(t *testing.T) {
 	})
 }
 
+func TestBlocksStoreQuerier_SelectExemplars(t *testing.T) {
+	const (
+		minT = int64(10)
+		maxT = int64(20)
+	)
+
+	var (
+		block1  = ulid.MustNew(1, nil)
+		block2  = ulid.MustNew(2, nil)
+		series1 = labels.FromStrings(labels.MetricName, "series_1")
+		series2 = labels.FromStrings(labels.MetricName, "series_2")
+	)
+
+	exemplars := func(lbls labels.Labels, timestamps ...int64) mimirpb.TimeSeries {
+		s := mimirpb.TimeSeries{Labels: mimirpb.FromLabelsToLabelAdapters(lbls)}
+		for _, ts := range timestamps {
+			s.Exemplars = append(s.Exemplars, mimirpb.Exemplar{
+				Labels:      []mimirpb.LabelAdapter{{Name: "trace_id", Value: fmt.Sprintf("trace-%d", ts)}},
+				Value:       float64(ts),
+				TimestampMs: ts,
+			})
+		}
+		return s
+	}
+
+	tests := map[string]struct {
+		storeSetResponses []interface{}
+		expectedSeries    []mimirpb.TimeSeries
+		expectedErr       string
+	}{
+		"a single store-gateway holding all the blocks": {
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedExemplarsResponse: &storepb.ExemplarsResponse{
+						Timeseries: []mimirpb.TimeSeries{exemplars(series1, 10, 15), exemplars(series2, 12)},
+						Hints:      mockExemplarsHints(block1, block2),
+					}}: {block1, block2},
+				},
+			},
+			expectedSeries: []mimirpb.TimeSeries{exemplars(series1, 10, 15), exemplars(series2, 12)},
+		},
+		"multiple store-gateways returning the same series": {
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedExemplarsResponse: &storepb.ExemplarsResponse{
+						Timeseries: []mimirpb.TimeSeries{exemplars(series1, 10, 15)},
+						Hints:      mockExemplarsHints(block1),
+					}}: {block1},
+					&storeGatewayClientMock{remoteAddr: "2.2.2.2", mockedExemplarsResponse: &storepb.ExemplarsResponse{
+						Timeseries: []mimirpb.TimeSeries{exemplars(series1, 15, 20)},
+						Hints:      mockExemplarsHints(block2),
+					}}: {block2},
+				},
+			},
+			expectedSeries: []mimirpb.TimeSeries{exemplars(series1, 10, 15, 20)},
+		},
+		"a block missing in the first store-gateway is queried from another one": {
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedExemplarsResponse: &storepb.ExemplarsResponse{
+						Timeseries: []mimirpb.TimeSeries{exemplars(series1, 10)},
+						Hints:      mockExemplarsHints(block1),
+					}}: {block1, block2},
+				},
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "2.2.2.2", mockedExemplarsResponse: &storepb.ExemplarsResponse{
+						Timeseries: []mimirpb.TimeSeries{exemplars(series2, 20)},
+						Hints:      mockExemplarsHints(block2),
+					}}: {block2},
+				},
+			},
+			expectedSeries: []mimirpb.TimeSeries{exemplars(series1, 10), exemplars(series2, 20)},
+		},
+		"a block missing in all the store-gateways": {
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedExemplarsResponse: &storepb.ExemplarsResponse{
+						Hints: mockExemplarsHints(block1),
+					}}: {block1, block2},
+				},
+				errors.New("no store-gateway remaining after exclude"),
+			},
+			expectedErr: newStoreConsistencyCheckFailedError([]ulid.ULID{block2}).Error(),
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx := user.InjectOrgID(context.Background(), "user-1")
+			reg := prometheus.NewPedanticRegistry()
+
+			finder := &blocksFinderMock{}
+			finder.On("GetBlocks", mock.Anything, "user-1", minT, maxT).Return(bucketindex.Blocks{
+				{ID: block1},
+				{ID: block2},
+			}, map[ulid.ULID]*bucketindex.BlockDeletionMark(nil), nil)
+
+			q := &blocksStoreQuerier{
+				minT:        minT,
+				maxT:        maxT,
+				finder:      finder,
+				stores:      &blocksStoreSetMock{mockedResponses: testData.storeSetResponses},
+				consistency: NewBlocksConsistencyChecker(0, 0, log.NewNopLogger(), nil),
+				logger:      log.NewNopLogger(),
+				metrics:     newBlocksStoreQueryableMetrics(reg),
+				limits:      &blocksStoreLimitsMock{},
+			}
+
+			actual, err := q.selectExemplars(ctx, []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, "series_.*")})
+			if testData.expectedErr != "" {
+				require.EqualError(t, err, testData.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testData.expectedSeries, actual)
+		})
+	}
+}
+
+func TestBlocksStoreQuerier_SelectMetricMetadata(t *testing.T) {
+	const (
+		minT = int64(10)
+		maxT = int64(20)
+	)
+
+	var (
+		block1 = ulid.MustNew(1, nil)
+		block2 = ulid.MustNew(2, nil)
+	)
+
+	tests := map[string]struct {
+		storeSetResponses []interface{}
+		expectedMetadata  []block.MetricMetadata
+		expectedErr       string
+	}{
+		"a single store-gateway holding all the blocks": {
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedMetadataResponse: &storepb.MetricMetadataResponse{
+						Metadata: []storepb.MetricMetadata{
+							{Metric: "metric_1", Type: "counter", Help: "help", MinTime: 10, MaxTime: 15},
+							{Metric: "metric_2", Type: "gauge", Help: "help", MinTime: 12, MaxTime: 20},
+						},
+						Hints: mockMetadataHints(block1, block2),
+					}}: {block1, block2},
+				},
+			},
+			expectedMetadata: []block.MetricMetadata{
+				{Metric: "metric_1", Type: "counter", Help: "help", MinTime: 10, MaxTime: 15},
+				{Metric: "metric_2", Type: "gauge", Help: "help", MinTime: 12, MaxTime: 20},
+			},
+		},
+		"multiple store-gateways returning the history of the same metric": {
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedMetadataResponse: &storepb.MetricMetadataResponse{
+						Metadata: []storepb.MetricMetadata{{Metric: "metric_1", Type: "counter", Help: "help", MinTime: 10, MaxTime: 14}},
+						Hints:    mockMetadataHints(block1),
+					}}: {block1},
+					&storeGatewayClientMock{remoteAddr: "2.2.2.2", mockedMetadataResponse: &storepb.MetricMetadataResponse{
+						Metadata: []storepb.MetricMetadata{
+							{Metric: "metric_1", Type: "counter", Help: "help", MinTime: 15, MaxTime: 16},
+							{Metric: "metric_1", Type: "counter", Help: "new help", MinTime: 17, MaxTime: 20},
+						},
+						Hints: mockMetadataHints(block2),
+					}}: {block2},
+				},
+			},
+			expectedMetadata: []block.MetricMetadata{
+				{Metric: "metric_1", Type: "counter", Help: "help", MinTime: 10, MaxTime: 16},
+				{Metric: "metric_1", Type: "counter", Help: "new help", MinTime: 17, MaxTime: 20},
+			},
+		},
+		"a block missing in all the store-gateways": {
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedMetadataResponse: &storepb.MetricMetadataResponse{
+						Hints: mockMetadataHints(block1),
+					}}: {block1, block2},
+				},
+				errors.New("no store-gateway remaining after exclude"),
+			},
+			expectedErr: newStoreConsistencyCheckFailedError([]ulid.ULID{block2}).Error(),
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx := user.InjectOrgID(context.Background(), "user-1")
+			reg := prometheus.NewPedanticRegistry()
+
+			finder := &blocksFinderMock{}
+			finder.On("GetBlocks", mock.Anything, "user-1", minT, maxT).Return(bucketindex.Blocks{
+				{ID: block1},
+				{ID: block2},
+			}, map[ulid.ULID]*bucketindex.BlockDeletionMark(nil), nil)
+
+			q := &blocksStoreQuerier{
+				minT:        minT,
+				maxT:        maxT,
+				finder:      finder,
+				stores:      &blocksStoreSetMock{mockedResponses: testData.storeSetResponses},
+				consistency: NewBlocksConsistencyChecker(0, 0, log.NewNopLogger(), nil),
+				logger:      log.NewNopLogger(),
+				metrics:     newBlocksStoreQueryableMetrics(reg),
+				limits:      &blocksStoreLimitsMock{},
+			}
+
+			actual, err := q.selectMetricMetadata(ctx, "")
+			if testData.expectedErr != "" {
+				require.EqualError(t, err, testData.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testData.expectedMetadata, actual)
+		})
+	}
+}
+
 func TestBlocksStoreQuerier_SelectSortedShouldHonorQueryStoreAfter(t *testing.T) {
 	now := time.Now()
 	ctx := context.Background()
@@ -2036,6 +2258,10 @@ type storeGatewayClientMock struct {
 	mockedLabelNamesErr       error
 	mockedLabelValuesResponse *storepb.LabelValuesResponse
 	mockedLabelValuesErr      error
+	mockedExemplarsResponse   *storepb.ExemplarsResponse
+	mockedExemplarsErr        error
+	mockedMetadataResponse    *storepb.MetricMetadataResponse
+	mockedMetadataErr         error
 }
 
 func (m *storeGatewayClientMock) Series(ctx context.Context, _ *storepb.SeriesRequest, _ ...grpc.CallOption) (storegatewaypb.StoreGateway_SeriesClient, error) {
@@ -2055,6 +2281,14 @@ func (m *storeGatewayClientMock) LabelValues(context.Context, *storepb.LabelValu
 	return m.mockedLabelValuesResponse, m.mockedLabelValuesErr
 }
 
+func (m *storeGatewayClientMock) Exemplars(context.Context, *storepb.ExemplarsRequest, ...grpc.CallOption) (*storepb.ExemplarsResponse, error) {
+	return m.mockedExemplarsResponse, m.mockedExemplarsErr
+}
+
+func (m *storeGatewayClientMock) MetricMetadata(context.Context, *storepb.MetricMetadataRequest, ...grpc.CallOption) (*storepb.MetricMetadataResponse, error) {
+	return m.mockedMetadataResponse, m.mockedMetadataErr
+}
+
 func (m *storeGatewayClientMock) RemoteAddress() string {
 	return m.remoteAddr
 }
@@ -2131,6 +2365,16 @@ func (m *cancelerStoreGatewayClientMock) LabelValues(ctx context.Context, _ *sto
 	return nil, ctx.Err()
 }
 
+func (m *cancelerStoreGatewayClientMock) Exemplars(ctx context.Context, _ *storepb.ExemplarsRequest, _ ...grpc.CallOption) (*storepb.ExemplarsResponse, error) {
+	m.cancel()
+	return nil, ctx.Err()
+}
+
+func (m *cancelerStoreGatewayClientMock) MetricMetadata(ctx context.Context, _ *storepb.MetricMetadataRequest, _ ...grpc.CallOption) (*storepb.MetricMetadataResponse, error) {
+	m.cancel()
+	return nil, ctx.Err()
+}
+
 func (m *cancelerStoreGatewayClientMock) RemoteAddress() string {
 	return m.remoteAddr
 }
@@ -2252,6 +2496,34 @@ func mockNamesHints(ids ...ulid.ULID) *types.Any {
 	return marshalled
 }
 
+func mockExemplarsHints(ids ...ulid.ULID) *types.Any {
+	hints := &hintspb.ExemplarsResponseHints{}
+	for _, id := range ids {
+		hints.AddQueriedBlock(id)
+	}
+
+	marshalled, err := types.MarshalAny(hints)
+	if err != nil {
+		panic(err)
+	}
+
+	return marshalled
+}
+
+func mockMetadataHints(ids ...ulid.ULID) *types.Any {
+	hints := &hintspb.MetricMetadataResponseHints{}
+	for _, id := range ids {
+		hints.AddQueriedBlock(id)
+	}
+
+	marshalled, err := types.MarshalAny(hints)
+	if err != nil {
+		panic(err)
+	}
+
+	return marshalled
+}
+
 func mockValuesHints(ids ...ulid.ULID) *types.Any {
 	hints := &hintspb.LabelValuesResponseHints{}
 	for _, id := range ids {
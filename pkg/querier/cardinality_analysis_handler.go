@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/tenant"
 	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/mimir/pkg/cardinality"
 	ingester_client "github.com/grafana/mimir/pkg/ingester/client"
@@ -78,6 +79,36 @@ func LabelValuesCardinalityHandler(distributor Distributor, limits *validation.O
 	})
 }
 
+// ActiveSeriesHandler creates handler for active series endpoint.
+func ActiveSeriesHandler(d Distributor, limits *validation.Overrides) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !limits.CardinalityAnalysisEnabled(tenantID) {
+			http.Error(w, fmt.Sprintf("cardinality analysis is disabled for the tenant: %v", tenantID), http.StatusBadRequest)
+			return
+		}
+
+		req, err := cardinality.DecodeActiveSeriesRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		series, err := d.ActiveSeries(ctx, req.Matchers)
+		if err != nil {
+			respondFromError(err, w)
+			return
+		}
+
+		util.WriteJSONResponse(w, toActiveSeriesResponse(series))
+	})
+}
+
 func respondFromError(err error, w http.ResponseWriter) {
 	httpResp, ok := httpgrpc.HTTPResponseFromError(errors.Cause(err))
 	if !ok {
@@ -189,6 +220,23 @@ func limitLabelValuesCardinality(labelValuesCardinality []labelValuesCardinality
 	return labelValuesCardinality[:limit]
 }
 
+// toActiveSeriesResponse converts the active series to ActiveSeriesResponse, sorted by labels
+// to have a stable output.
+func toActiveSeriesResponse(series []labels.Labels) *ActiveSeriesResponse {
+	sort.Slice(series, func(i, j int) bool {
+		return labels.Compare(series[i], series[j]) < 0
+	})
+
+	if series == nil {
+		series = []labels.Labels{}
+	}
+	return &ActiveSeriesResponse{Data: series}
+}
+
+type ActiveSeriesResponse struct {
+	Data []labels.Labels `json:"data"`
+}
+
 type labelValuesCardinality struct {
 	LabelValue  string `json:"label_value"`
 	SeriesCount uint64 `json:"series_count"`
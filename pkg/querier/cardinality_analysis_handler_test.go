@@ -786,7 +786,79 @@ func TestLabelValuesCardinalityHandler_DistributorError(t *testing.T) {
 	}
 }
 
-// createEnabledHandler creates a cardinalityHandler that can be either a LabelNamesCardinalityHandler or a LabelValuesCardinalityHandler
+func TestActiveSeriesHandler(t *testing.T) {
+	series := []labels.Labels{
+		labels.FromStrings(labels.MetricName, "metric", "pod", "b"),
+		labels.FromStrings(labels.MetricName, "metric", "pod", "a"),
+	}
+	matchers := []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "metric")}
+
+	distributor := &mockDistributor{}
+	distributor.On("ActiveSeries", mock.Anything, matchers).Return(series, nil)
+	handler := createEnabledHandler(t, ActiveSeriesHandler, distributor)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, createRequest("/ignored-url?selector=metric", "team-a"))
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	require.JSONEq(t, `{"data":[{"__name__":"metric","pod":"a"},{"__name__":"metric","pod":"b"}]}`, recorder.Body.String())
+}
+
+func TestActiveSeriesHandler_NegativeTests(t *testing.T) {
+	tests := map[string]struct {
+		request                     *http.Request
+		cardinalityAnalysisDisabled bool
+		distributorError            error
+		expectedHTTPStatusCode      int
+		expectedErrorMessage        string
+	}{
+		"missing selector": {
+			request:                createRequest("/ignored-url", "team-a"),
+			expectedHTTPStatusCode: http.StatusBadRequest,
+			expectedErrorMessage:   "'selector' param is required",
+		},
+		"invalid selector": {
+			request:                createRequest("/ignored-url?selector={", "team-a"),
+			expectedHTTPStatusCode: http.StatusBadRequest,
+			expectedErrorMessage:   "failed to parse selector",
+		},
+		"missing tenant": {
+			request:                createRequest("/ignored-url?selector=metric", ""),
+			expectedHTTPStatusCode: http.StatusBadRequest,
+			expectedErrorMessage:   "no org id",
+		},
+		"cardinality analysis disabled": {
+			request:                     createRequest("/ignored-url?selector=metric", "team-a"),
+			cardinalityAnalysisDisabled: true,
+			expectedHTTPStatusCode:      http.StatusBadRequest,
+			expectedErrorMessage:        "cardinality analysis is disabled for the tenant: team-a",
+		},
+		"distributor error": {
+			request:                createRequest("/ignored-url?selector=metric", "team-a"),
+			distributorError:       fmt.Errorf("non httpgrpc error"),
+			expectedHTTPStatusCode: http.StatusInternalServerError,
+			expectedErrorMessage:   "non httpgrpc error",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			distributor := &mockDistributor{}
+			distributor.On("ActiveSeries", mock.Anything, mock.Anything).Return([]labels.Labels(nil), tc.distributorError)
+
+			overrides, err := validation.NewOverrides(validation.Limits{CardinalityAnalysisEnabled: !tc.cardinalityAnalysisDisabled}, nil)
+			require.NoError(t, err)
+			handler := ActiveSeriesHandler(distributor, overrides)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, tc.request)
+			require.Equal(t, tc.expectedHTTPStatusCode, recorder.Result().StatusCode)
+			require.Contains(t, recorder.Body.String(), tc.expectedErrorMessage)
+		})
+	}
+}
+
+// createEnabledHandler creates a cardinalityHandler that can be either a LabelNamesCardinalityHandler, a LabelValuesCardinalityHandler
+// or an ActiveSeriesHandler
 func createEnabledHandler(t *testing.T, cardinalityHandler func(Distributor, *validation.Overrides) http.Handler, distributor *mockDistributor) http.Handler {
 	limits := validation.Limits{CardinalityAnalysisEnabled: true}
 	overrides, err := validation.NewOverrides(limits, nil)
@@ -4,6 +4,8 @@ package engine
 
 import (
 	"flag"
+	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,9 +13,21 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/prometheus/promql"
 
+	"github.com/grafana/mimir/pkg/querier/engine/streaming"
 	"github.com/grafana/mimir/pkg/util/activitytracker" //lint:ignore faillint activitytracker is fine
 )
 
+const (
+	// PrometheusEngine is the Prometheus PromQL engine.
+	PrometheusEngine = "prometheus"
+	// StreamingEngine is the streaming PromQL engine, which reduces the memory consumed by each query.
+	StreamingEngine = "streaming"
+)
+
+var supportedEngines = []string{PrometheusEngine, StreamingEngine}
+
+var errInvalidPromQLEngine = fmt.Errorf("invalid PromQL engine, supported values: %s", strings.Join(supportedEngines, ", "))
+
 // Config holds the PromQL engine config exposed by Mimir.
 type Config struct {
 	MaxConcurrent int           `yaml:"max_concurrent"`
@@ -28,6 +42,10 @@ type Config struct {
 	// LookbackDelta determines the time since the last sample after which a time
 	// series is considered stale.
 	LookbackDelta time.Duration `yaml:"lookback_delta" category:"advanced"`
+
+	PromQLEngine                          string `yaml:"promql_engine" category:"experimental"`
+	EnablePromQLEngineFallback            bool   `yaml:"enable_promql_engine_fallback" category:"experimental"`
+	MaxEstimatedMemoryConsumptionPerQuery uint64 `yaml:"max_estimated_memory_consumption_per_query" category:"experimental"`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
@@ -44,6 +62,38 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.MaxSamples, "querier.max-samples", 50e6, sharedWithQueryFrontend("Maximum number of samples a single query can load into memory."))
 	f.DurationVar(&cfg.DefaultEvaluationInterval, "querier.default-evaluation-interval", time.Minute, sharedWithQueryFrontend("The default evaluation interval or step size for subqueries."))
 	f.DurationVar(&cfg.LookbackDelta, "querier.lookback-delta", 5*time.Minute, sharedWithQueryFrontend("Time since the last sample after which a time series is considered stale and ignored by expression evaluations."))
+	f.StringVar(&cfg.PromQLEngine, "querier.promql-engine", PrometheusEngine, fmt.Sprintf("PromQL engine used by the querier and the ruler to evaluate queries. The %s engine evaluates queries one series at a time to reduce their memory consumption, but only supports a subset of PromQL. Supported values: %s.", StreamingEngine, strings.Join(supportedEngines, ", ")))
+	f.BoolVar(&cfg.EnablePromQLEngineFallback, "querier.enable-promql-engine-fallback", true, fmt.Sprintf("If set to true and the %s PromQL engine is used, the queries it doesn't support are evaluated by the Prometheus engine. If set to false, such queries fail.", StreamingEngine))
+	f.Uint64Var(&cfg.MaxEstimatedMemoryConsumptionPerQuery, streaming.MaxEstimatedMemoryConsumptionPerQueryFlag, 0, fmt.Sprintf("Maximum estimated memory, in bytes, of the samples and series held by a single query evaluated by the %s PromQL engine, including its result. The chunks fetched from the storage aren't included. The queries exceeding the limit fail. 0 to disable.", StreamingEngine))
+}
+
+func (cfg *Config) Validate() error {
+	if !slices.Contains(supportedEngines, cfg.PromQLEngine) {
+		return errInvalidPromQLEngine
+	}
+	return nil
+}
+
+// NewQueryEngine returns the PromQL engine selected by the config.
+func NewQueryEngine(cfg Config, activityTracker *activitytracker.ActivityTracker, logger log.Logger, reg prometheus.Registerer) QueryEngine {
+	prometheusEngine := promql.NewEngine(NewPromQLEngineOptions(cfg, activityTracker, logger, reg))
+	if cfg.PromQLEngine != StreamingEngine {
+		return prometheusEngine
+	}
+
+	streamingEngine := streaming.NewEngine(streaming.Opts{
+		Timeout:                               cfg.Timeout,
+		LookbackDelta:                         cfg.LookbackDelta,
+		MaxEstimatedMemoryConsumptionPerQuery: cfg.MaxEstimatedMemoryConsumptionPerQuery,
+		MaxSamples:                            cfg.MaxSamples,
+		ActiveQueryTracker:                    newQueryTracker(activityTracker),
+		Logger:                                logger,
+		Reg:                                   reg,
+	})
+	if !cfg.EnablePromQLEngineFallback {
+		return streamingEngine
+	}
+	return newEngineWithFallback(streamingEngine, prometheusEngine, reg, logger)
 }
 
 // NewPromQLEngineOptions returns the PromQL engine options based on the provided config.
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/util/stats"
+
+	"github.com/grafana/mimir/pkg/querier/engine/streaming"
+)
+
+// QueryEngine is a PromQL engine, as used by the Prometheus API and the ruler.
+type QueryEngine interface {
+	SetQueryLogger(l promql.QueryLogger)
+	NewInstantQuery(ctx context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, ts time.Time) (promql.Query, error)
+	NewRangeQuery(ctx context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, start, end time.Time, interval time.Duration) (promql.Query, error)
+}
+
+// engineWithFallback evaluates the queries with the streaming engine, and falls back to the Prometheus engine
+// for the queries the streaming engine doesn't support, either when creating the query, or when executing it.
+type engineWithFallback struct {
+	preferred *streaming.Engine
+	fallback  QueryEngine
+	logger    log.Logger
+
+	unsupportedQueries *prometheus.CounterVec
+}
+
+func newEngineWithFallback(preferred *streaming.Engine, fallback QueryEngine, reg prometheus.Registerer, logger log.Logger) *engineWithFallback {
+	return &engineWithFallback{
+		preferred: preferred,
+		fallback:  fallback,
+		logger:    logger,
+		unsupportedQueries: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_querier_streaming_engine_unsupported_queries_total",
+			Help: "Number of queries not supported by the streaming PromQL engine, and evaluated by the Prometheus engine instead.",
+		}, []string{"reason"}),
+	}
+}
+
+// SetQueryLogger sets the query logger of both engines, so that the queries are logged by the engine executing
+// them. The queries falling back to the Prometheus engine when executed are logged by both engines. The logger
+// is closed by the Prometheus engine only, once replaced.
+func (e *engineWithFallback) SetQueryLogger(l promql.QueryLogger) {
+	e.fallback.SetQueryLogger(l)
+
+	if l == nil {
+		e.preferred.SetQueryLogger(nil)
+		return
+	}
+	e.preferred.SetQueryLogger(nopCloserQueryLogger{l})
+}
+
+// nopCloserQueryLogger is a promql.QueryLogger which isn't closed by the engine it's set to, because it's
+// shared with another engine.
+type nopCloserQueryLogger struct {
+	promql.QueryLogger
+}
+
+func (nopCloserQueryLogger) Close() error { return nil }
+
+func (e *engineWithFallback) NewInstantQuery(ctx context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, ts time.Time) (promql.Query, error) {
+	newFallbackQuery := func() (promql.Query, error) {
+		return e.fallback.NewInstantQuery(ctx, q, opts, qs, ts)
+	}
+
+	qry, err := e.preferred.NewInstantQuery(ctx, q, opts, qs, ts)
+	return e.wrap(ctx, qs, qry, err, newFallbackQuery)
+}
+
+func (e *engineWithFallback) NewRangeQuery(ctx context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, start, end time.Time, interval time.Duration) (promql.Query, error) {
+	newFallbackQuery := func() (promql.Query, error) {
+		return e.fallback.NewRangeQuery(ctx, q, opts, qs, start, end, interval)
+	}
+
+	qry, err := e.preferred.NewRangeQuery(ctx, q, opts, qs, start, end, interval)
+	return e.wrap(ctx, qs, qry, err, newFallbackQuery)
+}
+
+// wrap returns the query of the streaming engine, falling back to the Prometheus engine if it's not supported.
+func (e *engineWithFallback) wrap(ctx context.Context, qs string, qry promql.Query, err error, newFallbackQuery func() (promql.Query, error)) (promql.Query, error) {
+	if err == nil {
+		return &queryWithFallback{Query: qry, engine: e, newFallbackQuery: newFallbackQuery}, nil
+	}
+
+	var notSupported streaming.NotSupportedError
+	if !errors.As(err, &notSupported) {
+		return nil, err
+	}
+
+	e.recordFallback(ctx, qs, notSupported)
+	return newFallbackQuery()
+}
+
+func (e *engineWithFallback) recordFallback(ctx context.Context, qs string, notSupported streaming.NotSupportedError) {
+	e.unsupportedQueries.WithLabelValues(notSupported.Feature).Inc()
+
+	logger := log.With(e.logger, "query", qs, "reason", notSupported.Feature)
+	if traceID, ok := tracing.ExtractSampledTraceID(ctx); ok {
+		logger = log.With(logger, "traceID", traceID)
+	}
+	level.Debug(logger).Log("msg", "falling back to the Prometheus engine for a query not supported by the streaming engine")
+}
+
+// queryWithFallback is a query of the streaming engine, which is evaluated by the Prometheus engine
+// instead if it turns out to be unsupported when executed, for example because it selects native histograms.
+type queryWithFallback struct {
+	engine           *engineWithFallback
+	newFallbackQuery func() (promql.Query, error)
+
+	mtx sync.Mutex
+	promql.Query
+}
+
+func (q *queryWithFallback) Exec(ctx context.Context) *promql.Result {
+	res := q.current().Exec(ctx)
+
+	var notSupported streaming.NotSupportedError
+	if !errors.As(res.Err, &notSupported) {
+		return res
+	}
+
+	q.engine.recordFallback(ctx, q.String(), notSupported)
+	fallbackQuery, err := q.newFallbackQuery()
+	if err != nil {
+		return &promql.Result{Err: err}
+	}
+
+	q.mtx.Lock()
+	q.Query.Close()
+	q.Query = fallbackQuery
+	q.mtx.Unlock()
+
+	return fallbackQuery.Exec(ctx)
+}
+
+func (q *queryWithFallback) current() promql.Query {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	return q.Query
+}
+
+func (q *queryWithFallback) Close()                      { q.current().Close() }
+func (q *queryWithFallback) Statement() parser.Statement { return q.current().Statement() }
+func (q *queryWithFallback) Stats() *stats.Statistics    { return q.current().Stats() }
+func (q *queryWithFallback) Cancel()                     { q.current().Cancel() }
+func (q *queryWithFallback) String() string              { return q.current().String() }
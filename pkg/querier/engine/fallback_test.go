@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package engine
+
+import (
+	"context"
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/querier/engine/streaming"
+)
+
+const fallbackTestData = `
+load 1m
+	http_requests_total{pod="1"} 0+10x10
+	http_requests_total{pod="2"} 0+20x10
+	request_duration_seconds     {{schema:0 sum:5 count:4 buckets:[1 2 1]}}x10
+`
+
+func TestEngineWithFallback(t *testing.T) {
+	testStorage := promql.LoadedStorage(t, fallbackTestData)
+	t.Cleanup(func() { require.NoError(t, testStorage.Close()) })
+
+	for name, tc := range map[string]struct {
+		query          string
+		expectedReason string
+	}{
+		"supported query": {
+			query: `sum(rate(http_requests_total[5m]))`,
+		},
+		"query not supported when created": {
+			query:          `topk(1, http_requests_total)`,
+			expectedReason: "'topk' aggregation",
+		},
+		"query not supported when executed": {
+			query:          `request_duration_seconds`,
+			expectedReason: "native histograms",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			cfg.PromQLEngine = StreamingEngine
+
+			reg := prometheus.NewPedanticRegistry()
+			engine := NewQueryEngine(cfg, nil, log.NewNopLogger(), reg)
+			prometheusEngine := promql.NewEngine(NewPromQLEngineOptions(cfg, nil, log.NewNopLogger(), nil))
+
+			for _, newQuery := range []func(QueryEngine) (promql.Query, error){
+				func(e QueryEngine) (promql.Query, error) {
+					return e.NewInstantQuery(context.Background(), testStorage, nil, tc.query, time.Unix(5*60, 0))
+				},
+				func(e QueryEngine) (promql.Query, error) {
+					return e.NewRangeQuery(context.Background(), testStorage, nil, tc.query, time.Unix(0, 0), time.Unix(10*60, 0), time.Minute)
+				},
+			} {
+				expectedQuery, err := newQuery(prometheusEngine)
+				require.NoError(t, err)
+				expected := expectedQuery.Exec(context.Background())
+				require.NoError(t, expected.Err)
+
+				q, err := newQuery(engine)
+				require.NoError(t, err)
+				actual := q.Exec(context.Background())
+				require.NoError(t, actual.Err)
+				require.Equal(t, expected.Value.String(), actual.Value.String())
+
+				q.Close()
+				expectedQuery.Close()
+			}
+
+			if tc.expectedReason == "" {
+				assert.Zero(t, testutil.CollectAndCount(reg, "cortex_querier_streaming_engine_unsupported_queries_total"))
+				return
+			}
+			metrics, err := reg.Gather()
+			require.NoError(t, err)
+			found := false
+			for _, mf := range metrics {
+				if mf.GetName() != "cortex_querier_streaming_engine_unsupported_queries_total" {
+					continue
+				}
+				require.Len(t, mf.GetMetric(), 1)
+				assert.Equal(t, tc.expectedReason, mf.GetMetric()[0].GetLabel()[0].GetValue())
+				assert.Equal(t, float64(2), mf.GetMetric()[0].GetCounter().GetValue())
+				found = true
+			}
+			require.True(t, found, "the unsupported queries metric should be exported")
+		})
+	}
+}
+
+func TestEngineWithFallback_QueryLogger(t *testing.T) {
+	testStorage := promql.LoadedStorage(t, fallbackTestData)
+	t.Cleanup(func() { require.NoError(t, testStorage.Close()) })
+
+	cfg := newTestConfig(t)
+	cfg.PromQLEngine = StreamingEngine
+	engine := NewQueryEngine(cfg, nil, log.NewNopLogger(), nil)
+
+	first := &testQueryLogger{}
+	engine.SetQueryLogger(first)
+
+	for _, qs := range []string{`sum(http_requests_total)`, `topk(1, http_requests_total)`, `request_duration_seconds`} {
+		q, err := engine.NewInstantQuery(context.Background(), testStorage, nil, qs, time.Unix(5*60, 0))
+		require.NoError(t, err)
+		require.NoError(t, q.Exec(context.Background()).Err)
+		q.Close()
+	}
+
+	// The queries are logged by the engine executing them: the query falling back to the Prometheus engine when
+	// executed is logged by both engines.
+	assert.Equal(t, []string{`sum(http_requests_total)`, `topk(1, http_requests_total)`, `request_duration_seconds`, `request_duration_seconds`}, first.queries())
+
+	// The previous logger is closed once, when replaced.
+	engine.SetQueryLogger(&testQueryLogger{})
+	assert.Equal(t, 1, first.closed)
+}
+
+type testQueryLogger struct {
+	mtx     sync.Mutex
+	entries [][]interface{}
+	closed  int
+}
+
+func (l *testQueryLogger) Log(fields ...interface{}) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.entries = append(l.entries, fields)
+	return nil
+}
+
+func (l *testQueryLogger) Close() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.closed++
+	return nil
+}
+
+func (l *testQueryLogger) queries() []string {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	var queries []string
+	for _, fields := range l.entries {
+		queries = append(queries, fields[1].(map[string]interface{})["query"].(string))
+	}
+	return queries
+}
+
+func TestEngineWithoutFallback(t *testing.T) {
+	testStorage := promql.LoadedStorage(t, fallbackTestData)
+	t.Cleanup(func() { require.NoError(t, testStorage.Close()) })
+
+	cfg := newTestConfig(t)
+	cfg.PromQLEngine = StreamingEngine
+	cfg.EnablePromQLEngineFallback = false
+	engine := NewQueryEngine(cfg, nil, log.NewNopLogger(), nil)
+
+	_, err := engine.NewInstantQuery(context.Background(), testStorage, nil, `topk(1, http_requests_total)`, time.Unix(5*60, 0))
+	require.ErrorIs(t, err, streaming.NotSupportedError{Feature: "'topk' aggregation"})
+
+	q, err := engine.NewInstantQuery(context.Background(), testStorage, nil, `request_duration_seconds`, time.Unix(5*60, 0))
+	require.NoError(t, err)
+	defer q.Close()
+	require.ErrorIs(t, q.Exec(context.Background()).Err, streaming.NotSupportedError{Feature: "native histograms"})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := newTestConfig(t)
+	require.NoError(t, cfg.Validate())
+
+	cfg.PromQLEngine = StreamingEngine
+	require.NoError(t, cfg.Validate())
+
+	cfg.PromQLEngine = "unknown"
+	require.ErrorIs(t, cfg.Validate(), errInvalidPromQLEngine)
+}
+
+func newTestConfig(t *testing.T) Config {
+	cfg := Config{}
+	fs := flag.NewFlagSet("test", flag.PanicOnError)
+	cfg.RegisterFlags(fs)
+	require.NoError(t, fs.Parse(nil))
+	return cfg
+}
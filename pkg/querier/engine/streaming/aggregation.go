@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streaming
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// aggregation aggregates the series of its input by group. Each group is returned as soon as all its
+// input series have been read, so only the groups whose input series are being read are held in memory.
+type aggregation struct {
+	inner     instantVectorOperator
+	memory    *memoryConsumptionTracker
+	timeRange queryTimeRange
+
+	op       parser.ItemType
+	grouping []string
+	without  bool
+
+	// The group of each input series, in the order they're returned by the inner operator.
+	seriesGroups []*aggregationGroup
+	// The groups, in the order they're returned.
+	groups []*aggregationGroup
+}
+
+type aggregationGroup struct {
+	labels labels.Labels
+	// The number of input series of the group which haven't been read yet.
+	remainingSeries int
+	// The index of the last input series of the group.
+	lastSeriesIndex int
+
+	// The aggregated values at each step, and the number of input series with a value at each step.
+	values []float64
+	counts []float64
+}
+
+func (a *aggregation) SeriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	series, err := a.inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		groups = map[string]*aggregationGroup{}
+		b      = labels.NewBuilder(labels.EmptyLabels())
+	)
+
+	a.seriesGroups = make([]*aggregationGroup, 0, len(series))
+	for idx, s := range series {
+		b.Reset(s)
+		switch {
+		case a.without:
+			b.Del(a.grouping...)
+			b.Del(labels.MetricName)
+		case len(a.grouping) > 0:
+			b.Keep(a.grouping...)
+		default:
+			b.Reset(labels.EmptyLabels())
+		}
+		groupLabels := b.Labels()
+
+		key := string(groupLabels.Bytes(nil))
+		g := groups[key]
+		if g == nil {
+			g = &aggregationGroup{labels: groupLabels}
+			groups[key] = g
+			a.groups = append(a.groups, g)
+		}
+		g.remainingSeries++
+		g.lastSeriesIndex = idx
+		a.seriesGroups = append(a.seriesGroups, g)
+	}
+
+	// Return the groups in the order they're complete.
+	sort.Slice(a.groups, func(i, j int) bool {
+		return a.groups[i].lastSeriesIndex < a.groups[j].lastSeriesIndex
+	})
+
+	metadata := make([]labels.Labels, 0, len(a.groups))
+	for _, g := range a.groups {
+		metadata = append(metadata, g.labels)
+	}
+	return metadata, nil
+}
+
+func (a *aggregation) NextSeries(ctx context.Context) ([]promql.FPoint, error) {
+	next := a.groups[0]
+	a.groups = a.groups[1:]
+
+	// Read the input series until the next group is complete.
+	for next.remainingSeries > 0 {
+		points, err := a.inner.NextSeries(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		g := a.seriesGroups[0]
+		a.seriesGroups = a.seriesGroups[1:]
+		err = a.accumulate(g, points)
+		a.memory.putFPoints(points)
+		if err != nil {
+			return nil, err
+		}
+		g.remainingSeries--
+	}
+
+	points, err := a.memory.getFPoints(a.timeRange.steps)
+	if err != nil {
+		return nil, err
+	}
+	for step, count := range next.counts {
+		if count == 0 {
+			continue
+		}
+
+		v := next.values[step]
+		switch a.op {
+		case parser.COUNT:
+			v = count
+		case parser.GROUP:
+			v = 1
+		}
+		points = append(points, promql.FPoint{T: a.timeRange.start + int64(step)*a.timeRange.interval, F: v})
+	}
+
+	a.releaseGroup(next)
+	return points, nil
+}
+
+// accumulate aggregates the points of an input series into its group, like the Prometheus engine does.
+func (a *aggregation) accumulate(g *aggregationGroup, points []promql.FPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	if g.values == nil {
+		if err := a.memory.increase(uint64(a.timeRange.steps) * 2 * float64Size); err != nil {
+			return err
+		}
+		g.values = make([]float64, a.timeRange.steps)
+		g.counts = make([]float64, a.timeRange.steps)
+	}
+
+	for _, p := range points {
+		step := a.timeRange.stepIndex(p.T)
+		g.counts[step]++
+		if g.counts[step] == 1 {
+			g.values[step] = p.F
+			continue
+		}
+
+		switch a.op {
+		case parser.SUM:
+			g.values[step] += p.F
+		case parser.AVG:
+			mean := g.values[step]
+			if math.IsInf(mean, 0) {
+				if math.IsInf(p.F, 0) && (mean > 0) == (p.F > 0) {
+					// The mean and the value are infinities of the same sign, so the mean is correct already.
+					continue
+				}
+				if !math.IsInf(p.F, 0) && !math.IsNaN(p.F) {
+					// The mean stays infinite when adding a finite value.
+					continue
+				}
+			}
+			// Divide each side of the subtraction by the count to avoid float64 overflows.
+			g.values[step] += p.F/g.counts[step] - mean/g.counts[step]
+		case parser.MAX:
+			if g.values[step] < p.F || math.IsNaN(g.values[step]) {
+				g.values[step] = p.F
+			}
+		case parser.MIN:
+			if g.values[step] > p.F || math.IsNaN(g.values[step]) {
+				g.values[step] = p.F
+			}
+		}
+	}
+	return nil
+}
+
+func (a *aggregation) releaseGroup(g *aggregationGroup) {
+	if g.values != nil {
+		a.memory.decrease(uint64(a.timeRange.steps) * 2 * float64Size)
+		g.values, g.counts = nil, nil
+	}
+}
+
+func (a *aggregation) Close() {
+	for _, g := range a.groups {
+		a.releaseGroup(g)
+	}
+	a.groups = nil
+	a.inner.Close()
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streaming
+
+import (
+	"context"
+	"math"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// vectorScalarBinaryOperation applies a binary operator between each value of an instant vector and a number.
+type vectorScalarBinaryOperation struct {
+	inner  instantVectorOperator
+	scalar float64
+	op     parser.ItemType
+	// scalarOnLeft is true if the number is the left-hand side of the operation.
+	scalarOnLeft bool
+	returnBool   bool
+}
+
+func (b *vectorScalarBinaryOperation) SeriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	series, err := b.inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Comparisons filtering the series keep the metric name.
+	if b.op.IsComparisonOperator() && !b.returnBool {
+		return series, nil
+	}
+	return dropMetricName(series)
+}
+
+func (b *vectorScalarBinaryOperation) NextSeries(ctx context.Context) ([]promql.FPoint, error) {
+	points, err := b.inner.NextSeries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The points are filtered in place.
+	kept := points[:0]
+	for _, p := range points {
+		lhs, rhs := p.F, b.scalar
+		if b.scalarOnLeft {
+			lhs, rhs = rhs, lhs
+		}
+
+		v, keep := vectorElemBinop(b.op, lhs, rhs)
+		if b.op.IsComparisonOperator() {
+			// The output value of comparisons is always the value of the vector.
+			v = p.F
+		}
+		if b.returnBool {
+			v = 0
+			if keep {
+				v = 1
+			}
+			keep = true
+		}
+		if keep {
+			kept = append(kept, promql.FPoint{T: p.T, F: v})
+		}
+	}
+	return kept, nil
+}
+
+func (b *vectorScalarBinaryOperation) Close() {
+	b.inner.Close()
+}
+
+// vectorElemBinop evaluates a binary operation between two floats, and returns whether the
+// result is kept for comparison operators.
+func vectorElemBinop(op parser.ItemType, lhs, rhs float64) (float64, bool) {
+	switch op {
+	case parser.ADD:
+		return lhs + rhs, true
+	case parser.SUB:
+		return lhs - rhs, true
+	case parser.MUL:
+		return lhs * rhs, true
+	case parser.DIV:
+		return lhs / rhs, true
+	case parser.POW:
+		return math.Pow(lhs, rhs), true
+	case parser.MOD:
+		return math.Mod(lhs, rhs), true
+	case parser.ATAN2:
+		return math.Atan2(lhs, rhs), true
+	case parser.EQLC:
+		return lhs, lhs == rhs
+	case parser.NEQ:
+		return lhs, lhs != rhs
+	case parser.GTR:
+		return lhs, lhs > rhs
+	case parser.LSS:
+		return lhs, lhs < rhs
+	case parser.GTE:
+		return lhs, lhs >= rhs
+	case parser.LTE:
+		return lhs, lhs <= rhs
+	default:
+		// The operators are checked when planning the query.
+		panic("unexpected binary operator " + op.String())
+	}
+}
+
+// unaryNegation negates the values of an instant vector.
+type unaryNegation struct {
+	inner instantVectorOperator
+}
+
+func (u *unaryNegation) SeriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	series, err := u.inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dropMetricName(series)
+}
+
+func (u *unaryNegation) NextSeries(ctx context.Context) ([]promql.FPoint, error) {
+	points, err := u.inner.NextSeries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range points {
+		points[i].F = -points[i].F
+	}
+	return points, nil
+}
+
+func (u *unaryNegation) Close() {
+	u.inner.Close()
+}
@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package streaming implements a PromQL engine which evaluates queries one series at a time, instead of
+// materialising the whole matrix of each expression, so that the samples held by a query are limited to
+// the series being evaluated and the query result. The selected series, and the chunks the storage holds
+// for them, are still held until they're evaluated. It supports a subset of PromQL, and returns a
+// NotSupportedError for the queries it doesn't support.
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/util/stats"
+)
+
+// MaxEstimatedMemoryConsumptionPerQueryFlag is the flag of the per-query memory consumption limit.
+const MaxEstimatedMemoryConsumptionPerQueryFlag = "querier.max-estimated-memory-consumption-per-query"
+
+// Opts configures the Engine.
+type Opts struct {
+	Timeout       time.Duration
+	LookbackDelta time.Duration
+
+	// MaxEstimatedMemoryConsumptionPerQuery is the limit of the estimated memory consumed by the data
+	// held by a query, including its result, or 0 if unlimited.
+	MaxEstimatedMemoryConsumptionPerQuery uint64
+
+	// MaxSamples is the maximum number of samples a query can hold in memory at once, including its
+	// result, like the Prometheus engine's one, or 0 if unlimited.
+	MaxSamples int
+
+	// ActiveQueryTracker tracks the queries being executed. It's optional.
+	ActiveQueryTracker promql.QueryTracker
+
+	Logger log.Logger
+	Reg    prometheus.Registerer
+}
+
+// Engine is a streaming PromQL engine.
+type Engine struct {
+	timeout                               time.Duration
+	lookbackDelta                         time.Duration
+	maxEstimatedMemoryConsumptionPerQuery uint64
+	maxSamples                            int
+	activeQueryTracker                    promql.QueryTracker
+	logger                                log.Logger
+
+	queryLoggerMtx sync.RWMutex
+	queryLogger    promql.QueryLogger
+
+	queriesRejectedDueToMemoryConsumption prometheus.Counter
+}
+
+// NewEngine returns a new streaming Engine.
+func NewEngine(opts Opts) *Engine {
+	lookbackDelta := opts.LookbackDelta
+	if lookbackDelta == 0 {
+		lookbackDelta = 5 * time.Minute
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	return &Engine{
+		timeout:                               opts.Timeout,
+		lookbackDelta:                         lookbackDelta,
+		maxEstimatedMemoryConsumptionPerQuery: opts.MaxEstimatedMemoryConsumptionPerQuery,
+		maxSamples:                            opts.MaxSamples,
+		activeQueryTracker:                    opts.ActiveQueryTracker,
+		logger:                                logger,
+
+		queriesRejectedDueToMemoryConsumption: promauto.With(opts.Reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_querier_streaming_engine_queries_rejected_due_to_memory_consumption_total",
+			Help: "Number of queries rejected by the streaming PromQL engine because they exceeded the maximum estimated memory consumption per query.",
+		}),
+	}
+}
+
+// SetQueryLogger implements the query engine interface of the Prometheus API. Like the Prometheus engine,
+// the executed queries are logged to l, and the previous query logger is closed.
+func (e *Engine) SetQueryLogger(l promql.QueryLogger) {
+	e.queryLoggerMtx.Lock()
+	defer e.queryLoggerMtx.Unlock()
+
+	if e.queryLogger != nil {
+		if err := e.queryLogger.Close(); err != nil {
+			level.Warn(e.logger).Log("msg", "error while closing the previous query log file", "err", err)
+		}
+	}
+	e.queryLogger = l
+}
+
+// logQuery logs the executed query to the query logger, if any, in the same format as the Prometheus engine.
+func (e *Engine) logQuery(ctx context.Context, q *query, err error) {
+	e.queryLoggerMtx.RLock()
+	defer e.queryLoggerMtx.RUnlock()
+
+	if e.queryLogger == nil {
+		return
+	}
+
+	params := map[string]interface{}{
+		"query": q.qs,
+		"start": formatDate(q.statement.Start),
+		"end":   formatDate(q.statement.End),
+		// The step provided by the user is in seconds.
+		"step": int64(q.statement.Interval / time.Second),
+	}
+	fields := []interface{}{"params", params}
+	if err != nil {
+		fields = append(fields, "error", err)
+	}
+	fields = append(fields, "stats", stats.NewQueryStats(q.Stats()))
+	if origin := ctx.Value(promql.QueryOrigin{}); origin != nil {
+		for k, v := range origin.(map[string]interface{}) {
+			fields = append(fields, k, v)
+		}
+	}
+
+	if err := e.queryLogger.Log(fields...); err != nil {
+		level.Error(e.logger).Log("msg", "can't log query", "err", err)
+	}
+}
+
+func formatDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// NewInstantQuery returns a query evaluating the expression at the given time.
+func (e *Engine) NewInstantQuery(_ context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, ts time.Time) (promql.Query, error) {
+	return e.newQuery(q, opts, qs, ts, ts, 0)
+}
+
+// NewRangeQuery returns a query evaluating the expression at each step of the given time range.
+func (e *Engine) NewRangeQuery(_ context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, start, end time.Time, interval time.Duration) (promql.Query, error) {
+	if interval <= 0 {
+		return nil, errors.New("zero or negative query resolution step widths are not accepted. Try a positive integer")
+	}
+	return e.newQuery(q, opts, qs, start, end, interval)
+}
+
+func (e *Engine) newQuery(q storage.Queryable, opts promql.QueryOpts, qs string, start, end time.Time, interval time.Duration) (*query, error) {
+	expr, err := parser.ParseExpr(qs)
+	if err != nil {
+		return nil, err
+	}
+	if expr.Type() != parser.ValueTypeVector {
+		return nil, newNotSupportedError(fmt.Sprintf("%s result", parser.DocumentedType(expr.Type())))
+	}
+
+	lookbackDelta := e.lookbackDelta
+	if opts != nil && opts.LookbackDelta() > 0 {
+		lookbackDelta = opts.LookbackDelta()
+	}
+
+	qry := &query{
+		engine:    e,
+		queryable: q,
+		qs:        qs,
+		statement: &parser.EvalStmt{
+			Expr:          expr,
+			Start:         start,
+			End:           end,
+			Interval:      interval,
+			LookbackDelta: lookbackDelta,
+		},
+		timeRange: newQueryTimeRange(start.UnixMilli(), end.UnixMilli(), interval.Milliseconds()),
+		memory:    newMemoryConsumptionTracker(e.maxEstimatedMemoryConsumptionPerQuery, e.maxSamples),
+	}
+
+	qry.root, err = qry.newOperator(expr, nil)
+	if err != nil {
+		return nil, err
+	}
+	return qry, nil
+}
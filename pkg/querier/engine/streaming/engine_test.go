@@ -0,0 +1,317 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streaming
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testData = `
+load 1m
+	http_requests_total{job="api", pod="1", status="200"} 0+10x30 0+5x30
+	http_requests_total{job="api", pod="2", status="200"} 0+20x60
+	http_requests_total{job="api", pod="1", status="500"} 0+1x10 _x10 10+1x40
+	http_requests_total{job="db", pod="1", status="200"}  0+3x20 stale 100+3x39
+	queue_length{job="api"}                               5 -2 7 NaN 3 Inf 1 -Inf 4 9 2 _x10 6+1x40
+	other{job="api"}                                      1x60
+`
+
+func TestEngine_ComparedToPrometheusEngine(t *testing.T) {
+	testStorage := promql.LoadedStorage(t, testData)
+	t.Cleanup(func() { require.NoError(t, testStorage.Close()) })
+
+	prometheusEngine := promql.NewEngine(promql.EngineOpts{
+		MaxSamples:           1e6,
+		Timeout:              time.Minute,
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+	})
+	streamingEngine := NewEngine(Opts{Timeout: time.Minute})
+
+	queries := []string{
+		`http_requests_total`,
+		`http_requests_total{status="200"}`,
+		`http_requests_total offset 10m`,
+		`queue_length`,
+		`rate(http_requests_total[5m])`,
+		`rate(http_requests_total[1m])`,
+		`increase(http_requests_total[10m] offset 3m)`,
+		`delta(queue_length[5m])`,
+		`rate(queue_length[5m])`,
+		`avg_over_time(queue_length[5m])`,
+		`count_over_time(http_requests_total[7m])`,
+		`last_over_time(http_requests_total[3m])`,
+		`max_over_time(queue_length[10m])`,
+		`min_over_time(queue_length[10m])`,
+		`sum_over_time(http_requests_total[2m])`,
+		`sum(http_requests_total)`,
+		`sum by (job) (http_requests_total)`,
+		`sum without (pod) (rate(http_requests_total[5m]))`,
+		`avg by (status) (http_requests_total)`,
+		`min by (job) (queue_length)`,
+		`max(queue_length)`,
+		`count by (job, status) (http_requests_total)`,
+		`group by (__name__) ({job="api"})`,
+		`sum by (job) (sum by (job, status) (http_requests_total))`,
+		`http_requests_total * 2`,
+		`2 - http_requests_total`,
+		`(http_requests_total) / (10)`,
+		`http_requests_total % 7`,
+		`http_requests_total ^ 0.5`,
+		`http_requests_total atan2 2`,
+		`http_requests_total > 100`,
+		`100 < http_requests_total`,
+		`http_requests_total > bool 100`,
+		`queue_length == 5`,
+		`queue_length != bool 5`,
+		`-sum by (job) (rate(http_requests_total[5m]))`,
+		`sum by (job) (rate(http_requests_total[5m]))`,
+		`rate(http_requests_total[5m]) * -1`,
+		`+http_requests_total`,
+		`non_existent_metric`,
+		`sum(non_existent_metric)`,
+	}
+
+	ranges := map[string]struct {
+		start, end time.Time
+		step       time.Duration
+	}{
+		"instant query":                {start: time.Unix(30*60, 0), end: time.Unix(30*60, 0)},
+		"instant query before data":    {start: time.Unix(-60, 0), end: time.Unix(-60, 0)},
+		"range query":                  {start: time.Unix(0, 0), end: time.Unix(70*60, 0), step: time.Minute},
+		"range query with large step":  {start: time.Unix(0, 0), end: time.Unix(70*60, 0), step: 7 * time.Minute},
+		"range query with small step":  {start: time.Unix(10*60, 0), end: time.Unix(20*60, 0), step: 15 * time.Second},
+		"range query with single step": {start: time.Unix(5*60, 0), end: time.Unix(5*60, 0), step: time.Minute},
+	}
+
+	for _, qs := range queries {
+		for name, r := range ranges {
+			t.Run(qs+"/"+name, func(t *testing.T) {
+				newQuery := func(engine interface {
+					NewInstantQuery(context.Context, storage.Queryable, promql.QueryOpts, string, time.Time) (promql.Query, error)
+					NewRangeQuery(context.Context, storage.Queryable, promql.QueryOpts, string, time.Time, time.Time, time.Duration) (promql.Query, error)
+				}) promql.Query {
+					var (
+						q   promql.Query
+						err error
+					)
+					if r.step == 0 {
+						q, err = engine.NewInstantQuery(context.Background(), testStorage, nil, qs, r.start)
+					} else {
+						q, err = engine.NewRangeQuery(context.Background(), testStorage, nil, qs, r.start, r.end, r.step)
+					}
+					require.NoError(t, err)
+					return q
+				}
+
+				expectedQuery := newQuery(prometheusEngine)
+				defer expectedQuery.Close()
+				expected := expectedQuery.Exec(context.Background())
+				require.NoError(t, expected.Err)
+
+				actualQuery := newQuery(streamingEngine)
+				defer actualQuery.Close()
+				actual := actualQuery.Exec(context.Background())
+				require.NoError(t, actual.Err)
+
+				requireEqualResults(t, expected, actual)
+			})
+		}
+	}
+}
+
+func requireEqualResults(t *testing.T, expected, actual *promql.Result) {
+	t.Helper()
+
+	assert.Equal(t, expected.Warnings.AsErrors(), actual.Warnings.AsErrors())
+
+	// The values are compared as strings, because NaN isn't equal to itself.
+	switch expectedValue := expected.Value.(type) {
+	case promql.Vector:
+		actualValue, ok := actual.Value.(promql.Vector)
+		require.True(t, ok, "expected a vector, got %T", actual.Value)
+		require.ElementsMatch(t, samplesAsStrings(expectedValue), samplesAsStrings(actualValue))
+
+	case promql.Matrix:
+		actualValue, ok := actual.Value.(promql.Matrix)
+		require.True(t, ok, "expected a matrix, got %T", actual.Value)
+		require.Equal(t, expectedValue.String(), actualValue.String())
+
+	default:
+		require.Failf(t, "unexpected result type", "%T", expected.Value)
+	}
+}
+
+func samplesAsStrings(vector promql.Vector) []string {
+	samples := make([]string, 0, len(vector))
+	for _, s := range vector {
+		samples = append(samples, s.String())
+	}
+	return samples
+}
+
+func TestEngine_NotSupported(t *testing.T) {
+	testStorage := promql.LoadedStorage(t, testData)
+	t.Cleanup(func() { require.NoError(t, testStorage.Close()) })
+
+	engine := NewEngine(Opts{})
+
+	for qs, expectedFeature := range map[string]string{
+		`1`:                                         "scalar result",
+		`http_requests_total[5m]`:                   "range vector result",
+		`http_requests_total @ 100`:                 "'@' modifier",
+		`rate(http_requests_total[5m] @ end())`:     "'@' modifier",
+		`topk(1, http_requests_total)`:              "'topk' aggregation",
+		`abs(http_requests_total)`:                  "'abs' function",
+		`rate(http_requests_total[5m:1m])`:          "'rate' function over a subquery",
+		`http_requests_total / http_requests_total`: "binary expression between two instant vectors or two scalars",
+		`http_requests_total / scalar(other)`:       "binary expression between two instant vectors or two scalars",
+	} {
+		t.Run(qs, func(t *testing.T) {
+			_, err := engine.NewInstantQuery(context.Background(), testStorage, nil, qs, time.Unix(0, 0))
+			require.ErrorIs(t, err, NotSupportedError{Feature: expectedFeature})
+		})
+	}
+
+	t.Run("series with the same labels after dropping the metric name", func(t *testing.T) {
+		q, err := engine.NewInstantQuery(context.Background(), testStorage, nil, `rate({job="api", __name__=~"queue_length|other"}[5m])`, time.Unix(10*60, 0))
+		require.NoError(t, err)
+		defer q.Close()
+
+		res := q.Exec(context.Background())
+		require.ErrorIs(t, res.Err, NotSupportedError{Feature: "series with the same labels after dropping the metric name"})
+	})
+}
+
+func TestEngine_MaxEstimatedMemoryConsumptionPerQuery(t *testing.T) {
+	testStorage := promql.LoadedStorage(t, testData)
+	t.Cleanup(func() { require.NoError(t, testStorage.Close()) })
+
+	const (
+		// Each series holds at most 61 points of 16 bytes, plus the labels.
+		query = `http_requests_total`
+		steps = 61
+	)
+
+	for name, tc := range map[string]struct {
+		limit       uint64
+		expectedErr bool
+	}{
+		"unlimited":           {limit: 0},
+		"below the limit":     {limit: 4 * steps * fPointSize * 2},
+		"exceeding the limit": {limit: 2 * steps * fPointSize, expectedErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			reg := prometheus.NewPedanticRegistry()
+			engine := NewEngine(Opts{MaxEstimatedMemoryConsumptionPerQuery: tc.limit, Reg: reg})
+
+			q, err := engine.NewRangeQuery(context.Background(), testStorage, nil, query, time.Unix(0, 0), time.Unix(60*60, 0), time.Minute)
+			require.NoError(t, err)
+			defer q.Close()
+
+			res := q.Exec(context.Background())
+			if tc.expectedErr {
+				require.ErrorIs(t, res.Err, errMaxEstimatedMemoryConsumptionPerQuery)
+				assert.Equal(t, float64(1), testutil.ToFloat64(engine.queriesRejectedDueToMemoryConsumption))
+				return
+			}
+			require.NoError(t, res.Err)
+			require.Len(t, res.Value.(promql.Matrix), 4)
+			assert.Equal(t, float64(0), testutil.ToFloat64(engine.queriesRejectedDueToMemoryConsumption))
+		})
+	}
+}
+
+func TestEngine_MaxSamples(t *testing.T) {
+	testStorage := promql.LoadedStorage(t, testData)
+	t.Cleanup(func() { require.NoError(t, testStorage.Close()) })
+
+	// Each of the 4 series holds 61 points, which are all returned in the result, while the aggregation
+	// only holds the points of the series being aggregated.
+	const steps = 61
+
+	for name, tc := range map[string]struct {
+		query       string
+		limit       int
+		expectedErr bool
+	}{
+		"unlimited":                        {query: `http_requests_total`, limit: 0},
+		"at the limit":                     {query: `http_requests_total`, limit: 4 * steps},
+		"exceeding the limit":              {query: `http_requests_total`, limit: 4*steps - 1, expectedErr: true},
+		"range vector at the limit":        {query: `rate(http_requests_total[5m])`, limit: 4*steps + 6},
+		"range vector exceeding the limit": {query: `rate(http_requests_total[5m])`, limit: 4*steps + 5, expectedErr: true},
+		"aggregation at the limit":         {query: `sum(http_requests_total)`, limit: steps},
+		"aggregation exceeding the limit":  {query: `sum(http_requests_total)`, limit: steps - 1, expectedErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			engine := NewEngine(Opts{MaxSamples: tc.limit})
+
+			q, err := engine.NewRangeQuery(context.Background(), testStorage, nil, tc.query, time.Unix(0, 0), time.Unix(60*60, 0), time.Minute)
+			require.NoError(t, err)
+			defer q.Close()
+
+			res := q.Exec(context.Background())
+			if tc.expectedErr {
+				require.ErrorAs(t, res.Err, new(promql.ErrTooManySamples))
+				return
+			}
+			require.NoError(t, res.Err)
+		})
+	}
+}
+
+func TestSelector_MemoryConsumption(t *testing.T) {
+	testStorage := promql.LoadedStorage(t, testData)
+	t.Cleanup(func() { require.NoError(t, testStorage.Close()) })
+
+	memory := newMemoryConsumptionTracker(0, 0)
+	v := &instantVectorSelector{selector: selector{
+		queryable: testStorage,
+		timeRange: newQueryTimeRange(0, time.Hour.Milliseconds(), time.Minute.Milliseconds()),
+		matchers:  []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "http_requests_total")},
+		lookback:  (5 * time.Minute).Milliseconds(),
+		memory:    memory,
+	}}
+
+	// The selected series are tracked until they're iterated.
+	series, err := v.SeriesMetadata(context.Background())
+	require.NoError(t, err)
+	require.Len(t, series, 4)
+	assert.Equal(t, 4*seriesSize+seriesLabelsSize(series), memory.current)
+
+	points, err := v.NextSeries(context.Background())
+	require.NoError(t, err)
+	memory.putFPoints(points)
+	assert.Equal(t, 3*seriesSize+seriesLabelsSize(series[1:]), memory.current)
+
+	// The series which haven't been iterated are released when the selector is closed.
+	v.Close()
+	assert.Zero(t, memory.current)
+}
+
+func TestEngine_Cancel(t *testing.T) {
+	testStorage := promql.LoadedStorage(t, testData)
+	t.Cleanup(func() { require.NoError(t, testStorage.Close()) })
+
+	engine := NewEngine(Opts{})
+	q, err := engine.NewInstantQuery(context.Background(), testStorage, nil, `sum(http_requests_total)`, time.Unix(0, 0))
+	require.NoError(t, err)
+	defer q.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res := q.Exec(ctx)
+	var canceled promql.ErrQueryCanceled
+	require.ErrorAs(t, res.Err, &canceled)
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streaming
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/mimir/pkg/util/globalerror"
+)
+
+// NotSupportedError is returned when a query uses a feature the streaming engine doesn't support.
+// Such queries can be evaluated by the Prometheus engine instead.
+type NotSupportedError struct {
+	// Feature is a short description of the unsupported feature. It's used as label value, so its cardinality
+	// must be bounded.
+	Feature string
+}
+
+func newNotSupportedError(feature string) error {
+	return NotSupportedError{Feature: feature}
+}
+
+func (e NotSupportedError) Error() string {
+	return "not supported by the streaming PromQL engine: " + e.Feature
+}
+
+var errMaxEstimatedMemoryConsumptionPerQuery = errors.New(globalerror.MaxEstimatedMemoryConsumptionPerQuery.MessageWithPerInstanceLimitConfig(
+	"the query exceeded the maximum allowed estimated amount of memory consumed by a single query",
+	MaxEstimatedMemoryConsumptionPerQueryFlag,
+))
+
+// errTooManySamples is returned when a query exceeds the max samples limit, like the Prometheus engine does.
+var errTooManySamples = promql.ErrTooManySamples("query execution")
+
+// contextError returns the error to return once the context of the query is done.
+func contextError(ctx context.Context, env string) error {
+	switch err := ctx.Err(); {
+	case errors.Is(err, context.DeadlineExceeded):
+		return promql.ErrQueryTimeout(env)
+	case err != nil:
+		return promql.ErrQueryCanceled(env)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streaming
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser/posrange"
+	"github.com/prometheus/prometheus/util/annotations"
+)
+
+// rangeFunction computes the value of a function over the samples of a range, or returns false if
+// the function has no value for the range.
+type rangeFunction func(samples []promql.FPoint, rangeStart, rangeEnd int64) (float64, bool)
+
+// rangeFunctions are the functions over range vectors supported by the engine.
+var rangeFunctions = map[string]rangeFunction{
+	"rate":     extrapolatedRate(true, true),
+	"increase": extrapolatedRate(true, false),
+	"delta":    extrapolatedRate(false, false),
+
+	"avg_over_time":   avgOverTime,
+	"count_over_time": countOverTime,
+	"last_over_time":  lastOverTime,
+	"max_over_time":   maxOverTime,
+	"min_over_time":   minOverTime,
+	"sum_over_time":   sumOverTime,
+}
+
+// rangeVectorFunction applies a function over the samples of each series in the range preceding each step.
+type rangeVectorFunction struct {
+	selector
+	annos *annotations.Annotations
+
+	name     string
+	function rangeFunction
+	// The position of the range vector selector, used in the annotations.
+	position posrange.PositionRange
+
+	metricNames []string
+}
+
+func (f *rangeVectorFunction) SeriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	series, err := f.seriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The metric name is dropped from the output series, but it's still needed for the annotations.
+	f.metricNames = make([]string, 0, len(series))
+	for _, l := range series {
+		f.metricNames = append(f.metricNames, l.Get(labels.MetricName))
+	}
+
+	// The last_over_time function acts like offset, so it keeps the metric name.
+	if f.name == "last_over_time" {
+		return series, nil
+	}
+	return dropMetricName(series)
+}
+
+func (f *rangeVectorFunction) NextSeries(ctx context.Context) ([]promql.FPoint, error) {
+	if err := contextError(ctx, "expression evaluation"); err != nil {
+		return nil, err
+	}
+
+	metricName := f.metricNames[0]
+	f.metricNames = f.metricNames[1:]
+
+	it := f.nextSeries()
+	points, err := f.memory.getFPoints(f.timeRange.steps)
+	if err != nil {
+		return nil, err
+	}
+
+	// The samples within the range of the current step.
+	window, err := f.memory.getFPoints(0)
+	if err != nil {
+		f.memory.putFPoints(points)
+		return nil, err
+	}
+	defer func() { f.memory.putFPoints(window) }()
+
+	hasSamples := false
+	for ts := f.timeRange.start; ts <= f.timeRange.end; ts += f.timeRange.interval {
+		rangeEnd := ts - f.offset
+		rangeStart := rangeEnd - f.lookback
+
+		// Drop the samples which are now out of the range. The ranges of the steps overlap
+		// as long as the range is longer than the step.
+		drop := 0
+		for drop < len(window) && window[drop].T < rangeStart {
+			drop++
+		}
+		window = window[:copy(window, window[drop:])]
+
+		for {
+			t, v, ok, err := it.peek()
+			if err != nil {
+				f.memory.putFPoints(points)
+				return nil, err
+			}
+			if !ok || t > rangeEnd {
+				break
+			}
+			it.next()
+
+			if t < rangeStart || value.IsStaleNaN(v) {
+				continue
+			}
+			if window, err = f.memory.growFPoints(window); err != nil {
+				f.memory.putFPoints(points)
+				return nil, err
+			}
+			window = append(window, promql.FPoint{T: t, F: v})
+		}
+
+		if len(window) == 0 {
+			continue
+		}
+		hasSamples = true
+
+		if v, ok := f.function(window, rangeStart, rangeEnd); ok {
+			points = append(points, promql.FPoint{T: ts, F: v})
+		}
+	}
+
+	if hasSamples && (f.name == "rate" || f.name == "increase") && !isCounterName(metricName) {
+		f.annos.Add(annotations.NewPossibleNonCounterInfo(metricName, f.position))
+	}
+	return points, nil
+}
+
+func (f *rangeVectorFunction) Close() {
+	f.close()
+}
+
+func isCounterName(name string) bool {
+	return strings.HasSuffix(name, "_total") || strings.HasSuffix(name, "_sum") || strings.HasSuffix(name, "_count")
+}
+
+// extrapolatedRate returns a function computing the rate, increase or delta of the samples, extrapolated
+// to the boundaries of the range, like the Prometheus engine does.
+func extrapolatedRate(isCounter, isRate bool) rangeFunction {
+	return func(samples []promql.FPoint, rangeStart, rangeEnd int64) (float64, bool) {
+		if len(samples) < 2 {
+			return 0, false
+		}
+
+		var (
+			numSamplesMinusOne = len(samples) - 1
+			firstT             = samples[0].T
+			lastT              = samples[numSamplesMinusOne].T
+			result             = samples[numSamplesMinusOne].F - samples[0].F
+		)
+
+		if isCounter {
+			// Handle counter resets.
+			prevValue := samples[0].F
+			for _, p := range samples[1:] {
+				if p.F < prevValue {
+					result += prevValue
+				}
+				prevValue = p.F
+			}
+		}
+
+		// Duration between first/last samples and boundary of range.
+		durationToStart := float64(firstT-rangeStart) / 1000
+		durationToEnd := float64(rangeEnd-lastT) / 1000
+
+		sampledInterval := float64(lastT-firstT) / 1000
+		averageDurationBetweenSamples := sampledInterval / float64(numSamplesMinusOne)
+
+		if isCounter && result > 0 && samples[0].F >= 0 {
+			// Counters cannot be negative, so the extrapolation stops at the zero point of the counter.
+			durationToZero := sampledInterval * (samples[0].F / result)
+			if durationToZero < durationToStart {
+				durationToStart = durationToZero
+			}
+		}
+
+		// If the first/last samples are close to the boundaries of the range, extrapolate the result.
+		extrapolationThreshold := averageDurationBetweenSamples * 1.1
+		extrapolateToInterval := sampledInterval
+
+		if durationToStart < extrapolationThreshold {
+			extrapolateToInterval += durationToStart
+		} else {
+			extrapolateToInterval += averageDurationBetweenSamples / 2
+		}
+		if durationToEnd < extrapolationThreshold {
+			extrapolateToInterval += durationToEnd
+		} else {
+			extrapolateToInterval += averageDurationBetweenSamples / 2
+		}
+
+		factor := extrapolateToInterval / sampledInterval
+		if isRate {
+			factor /= float64(rangeEnd-rangeStart) / 1000
+		}
+		return result * factor, true
+	}
+}
+
+func avgOverTime(samples []promql.FPoint, _, _ int64) (float64, bool) {
+	var mean, count, c float64
+	for _, p := range samples {
+		count++
+		if math.IsInf(mean, 0) {
+			if math.IsInf(p.F, 0) && (mean > 0) == (p.F > 0) {
+				// The mean and the value are infinities of the same sign, so the mean is correct already.
+				continue
+			}
+			if !math.IsInf(p.F, 0) && !math.IsNaN(p.F) {
+				// The mean stays infinite when adding a finite value.
+				continue
+			}
+		}
+		mean, c = kahanSumInc(p.F/count-mean/count, mean, c)
+	}
+
+	if math.IsInf(mean, 0) {
+		return mean, true
+	}
+	return mean + c, true
+}
+
+func countOverTime(samples []promql.FPoint, _, _ int64) (float64, bool) {
+	return float64(len(samples)), true
+}
+
+func lastOverTime(samples []promql.FPoint, _, _ int64) (float64, bool) {
+	return samples[len(samples)-1].F, true
+}
+
+func maxOverTime(samples []promql.FPoint, _, _ int64) (float64, bool) {
+	maxValue := samples[0].F
+	for _, p := range samples {
+		if p.F > maxValue || math.IsNaN(maxValue) {
+			maxValue = p.F
+		}
+	}
+	return maxValue, true
+}
+
+func minOverTime(samples []promql.FPoint, _, _ int64) (float64, bool) {
+	minValue := samples[0].F
+	for _, p := range samples {
+		if p.F < minValue || math.IsNaN(minValue) {
+			minValue = p.F
+		}
+	}
+	return minValue, true
+}
+
+func sumOverTime(samples []promql.FPoint, _, _ int64) (float64, bool) {
+	var sum, c float64
+	for _, p := range samples {
+		sum, c = kahanSumInc(p.F, sum, c)
+	}
+	if math.IsInf(sum, 0) {
+		return sum, true
+	}
+	return sum + c, true
+}
+
+// kahanSumInc adds the increment to the sum, using the Kahan-Babuska-Neumaier compensated summation.
+func kahanSumInc(inc, sum, c float64) (newSum, newC float64) {
+	t := sum + inc
+	// Using Neumaier improvement, swap if next term larger than sum.
+	if math.Abs(sum) >= math.Abs(inc) {
+		c += (sum - t) + inc
+	} else {
+		c += (inc - t) + sum
+	}
+	return t, c
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streaming
+
+import (
+	"unsafe"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+)
+
+var (
+	fPointSize  = uint64(unsafe.Sizeof(promql.FPoint{}))
+	float64Size = uint64(unsafe.Sizeof(float64(0)))
+	labelsSize  = uint64(unsafe.Sizeof(labels.Labels{}))
+	seriesSize  = uint64(unsafe.Sizeof(storage.Series(nil)))
+)
+
+// memoryConsumptionTracker tracks the estimated memory consumed by the data held by a query, and the number
+// of samples it holds, and fails the allocations which would exceed the per-query limits. It's not safe for
+// concurrent use.
+type memoryConsumptionTracker struct {
+	// The limits, or 0 if unlimited.
+	maxEstimatedMemoryConsumption uint64
+	maxSamples                    int
+
+	current uint64
+	peak    uint64
+
+	// The number of samples the points slices can hold, like the samples loaded in memory by the Prometheus engine.
+	currentSamples int
+}
+
+func newMemoryConsumptionTracker(maxEstimatedMemoryConsumption uint64, maxSamples int) *memoryConsumptionTracker {
+	return &memoryConsumptionTracker{maxEstimatedMemoryConsumption: maxEstimatedMemoryConsumption, maxSamples: maxSamples}
+}
+
+// increase records an allocation of the given number of bytes, or returns an error if it would exceed the limit.
+func (t *memoryConsumptionTracker) increase(bytes uint64) error {
+	if t.maxEstimatedMemoryConsumption > 0 && t.current+bytes > t.maxEstimatedMemoryConsumption {
+		return errMaxEstimatedMemoryConsumptionPerQuery
+	}
+
+	t.current += bytes
+	if t.current > t.peak {
+		t.peak = t.current
+	}
+	return nil
+}
+
+// decrease records the release of the given number of bytes.
+func (t *memoryConsumptionTracker) decrease(bytes uint64) {
+	if bytes > t.current {
+		bytes = t.current
+	}
+	t.current -= bytes
+}
+
+// getFPoints returns an empty slice of points with the given capacity.
+func (t *memoryConsumptionTracker) getFPoints(size int) ([]promql.FPoint, error) {
+	if t.maxSamples > 0 && t.currentSamples+size > t.maxSamples {
+		return nil, errTooManySamples
+	}
+	if err := t.increase(uint64(size) * fPointSize); err != nil {
+		return nil, err
+	}
+	t.currentSamples += size
+	return make([]promql.FPoint, 0, size), nil
+}
+
+// putFPoints releases a slice of points returned by getFPoints.
+func (t *memoryConsumptionTracker) putFPoints(points []promql.FPoint) {
+	t.decrease(uint64(cap(points)) * fPointSize)
+	t.currentSamples = max(0, t.currentSamples-cap(points))
+}
+
+// growFPoints returns the slice of points with room for at least one more point.
+func (t *memoryConsumptionTracker) growFPoints(points []promql.FPoint) ([]promql.FPoint, error) {
+	if len(points) < cap(points) {
+		return points, nil
+	}
+
+	size := max(2*cap(points), 16)
+	if t.maxSamples > 0 {
+		// Don't grow the slice beyond the samples limit if it can hold one more point within it.
+		size = max(min(size, t.maxSamples-t.currentSamples+cap(points)), cap(points)+1)
+	}
+
+	// The points of the slice being grown are released once they're copied, so they're not counted
+	// twice against the samples limit.
+	t.currentSamples -= cap(points)
+	grown, err := t.getFPoints(size)
+	t.currentSamples += cap(points)
+	if err != nil {
+		return nil, err
+	}
+	grown = append(grown, points...)
+	t.putFPoints(points)
+	return grown, nil
+}
+
+// seriesLabelsSize returns the estimated memory consumed by the labels of the series.
+func seriesLabelsSize(series []labels.Labels) uint64 {
+	var size uint64
+	for _, l := range series {
+		size += labelsMemorySize(l)
+	}
+	return size
+}
+
+// labelsMemorySize returns the estimated memory consumed by the labels.
+func labelsMemorySize(l labels.Labels) uint64 {
+	size := labelsSize
+	l.Range(func(l labels.Label) {
+		size += uint64(len(l.Name) + len(l.Value))
+	})
+	return size
+}
+
+// selectedSeriesSize returns the estimated memory consumed by a series selected from the storage, until it's
+// iterated. The chunks of the series aren't included, because they're held by the storage.
+func selectedSeriesSize(series storage.Series) uint64 {
+	return seriesSize + labelsMemorySize(series.Labels())
+}
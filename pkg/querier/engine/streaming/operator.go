@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streaming
+
+import (
+	"context"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+)
+
+// instantVectorOperator evaluates an expression returning an instant vector, one series at a time,
+// so that only the series being evaluated need to be held in memory.
+type instantVectorOperator interface {
+	// SeriesMetadata returns the labels of the series returned by the operator, in the order
+	// they're returned by NextSeries. It must be called once, before NextSeries.
+	SeriesMetadata(ctx context.Context) ([]labels.Labels, error)
+
+	// NextSeries returns the points of the next series, one per step the series has a value at.
+	// The caller owns the returned slice, and must release it with memoryConsumptionTracker.putFPoints.
+	NextSeries(ctx context.Context) ([]promql.FPoint, error)
+
+	// Close releases the resources held by the operator.
+	Close()
+}
+
+// queryTimeRange is the time range a query is evaluated over, in milliseconds.
+type queryTimeRange struct {
+	start int64
+	end   int64
+	// interval is the step of range queries, and 1 for instant queries.
+	interval int64
+	// steps is the number of steps the query is evaluated at.
+	steps int
+}
+
+func newQueryTimeRange(start, end, interval int64) queryTimeRange {
+	if interval == 0 {
+		interval = 1
+	}
+	return queryTimeRange{
+		start:    start,
+		end:      end,
+		interval: interval,
+		steps:    int((end-start)/interval) + 1,
+	}
+}
+
+// stepIndex returns the index of the step at the given timestamp.
+func (r queryTimeRange) stepIndex(ts int64) int {
+	return int((ts - r.start) / r.interval)
+}
+
+// dropMetricName returns the labels of the series without the metric name. If this results in multiple series
+// with the same labels, the query isn't supported, because the Prometheus engine fails it only if the series
+// have values at the same step.
+func dropMetricName(series []labels.Labels) ([]labels.Labels, error) {
+	seen := make(map[string]struct{}, len(series))
+	b := labels.NewScratchBuilder(0)
+
+	for i, l := range series {
+		b.Reset()
+		l.Range(func(l labels.Label) {
+			if l.Name != labels.MetricName {
+				b.Add(l.Name, l.Value)
+			}
+		})
+		series[i] = b.Labels()
+
+		key := string(series[i].Bytes(nil))
+		if _, ok := seen[key]; ok {
+			return nil, newNotSupportedError("series with the same labels after dropping the metric name")
+		}
+		seen[key] = struct{}{}
+	}
+	return series, nil
+}
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/util/annotations"
+	"github.com/prometheus/prometheus/util/stats"
+)
+
+// query implements promql.Query.
+type query struct {
+	engine    *Engine
+	queryable storage.Queryable
+	qs        string
+	statement *parser.EvalStmt
+	timeRange queryTimeRange
+	root      instantVectorOperator
+	memory    *memoryConsumptionTracker
+	annos     annotations.Annotations
+	stats     *stats.QueryTimers
+	cancel    context.CancelFunc
+}
+
+// newOperator returns the operator evaluating the expression. The parent is the node the expression is an
+// argument of, if any.
+func (q *query) newOperator(expr parser.Expr, parent parser.Node) (instantVectorOperator, error) {
+	switch e := expr.(type) {
+	case *parser.VectorSelector:
+		if e.Timestamp != nil || e.StartOrEnd != 0 {
+			return nil, newNotSupportedError("'@' modifier")
+		}
+		return &instantVectorSelector{
+			selector: q.newSelector(e, q.statement.LookbackDelta.Milliseconds(), parent),
+		}, nil
+
+	case *parser.Call:
+		function, ok := rangeFunctions[e.Func.Name]
+		if !ok {
+			return nil, newNotSupportedError(fmt.Sprintf("'%s' function", e.Func.Name))
+		}
+		matrix, ok := e.Args[0].(*parser.MatrixSelector)
+		if !ok {
+			return nil, newNotSupportedError(fmt.Sprintf("'%s' function over a subquery", e.Func.Name))
+		}
+		vs := matrix.VectorSelector.(*parser.VectorSelector)
+		if vs.Timestamp != nil || vs.StartOrEnd != 0 {
+			return nil, newNotSupportedError("'@' modifier")
+		}
+		return &rangeVectorFunction{
+			selector: q.newSelector(vs, matrix.Range.Milliseconds(), e),
+			annos:    &q.annos,
+			name:     e.Func.Name,
+			function: function,
+			position: matrix.PositionRange(),
+		}, nil
+
+	case *parser.AggregateExpr:
+		switch e.Op {
+		case parser.SUM, parser.AVG, parser.MIN, parser.MAX, parser.COUNT, parser.GROUP:
+		default:
+			return nil, newNotSupportedError(fmt.Sprintf("'%s' aggregation", e.Op))
+		}
+		inner, err := q.newOperator(e.Expr, e)
+		if err != nil {
+			return nil, err
+		}
+		return &aggregation{
+			inner:     inner,
+			memory:    q.memory,
+			timeRange: q.timeRange,
+			op:        e.Op,
+			grouping:  e.Grouping,
+			without:   e.Without,
+		}, nil
+
+	case *parser.BinaryExpr:
+		lhs, lhsIsNumber := unwrapNumberLiteral(e.LHS)
+		rhs, rhsIsNumber := unwrapNumberLiteral(e.RHS)
+		if lhsIsNumber == rhsIsNumber {
+			return nil, newNotSupportedError("binary expression between two instant vectors or two scalars")
+		}
+
+		vectorExpr, scalar := e.LHS, rhs
+		if lhsIsNumber {
+			vectorExpr, scalar = e.RHS, lhs
+		}
+		if vectorExpr.Type() != parser.ValueTypeVector {
+			return nil, newNotSupportedError("binary expression with a scalar expression")
+		}
+
+		inner, err := q.newOperator(vectorExpr, e)
+		if err != nil {
+			return nil, err
+		}
+		return &vectorScalarBinaryOperation{
+			inner:        inner,
+			scalar:       scalar,
+			op:           e.Op,
+			scalarOnLeft: lhsIsNumber,
+			returnBool:   e.ReturnBool,
+		}, nil
+
+	case *parser.UnaryExpr:
+		inner, err := q.newOperator(e.Expr, parent)
+		if err != nil {
+			return nil, err
+		}
+		if e.Op == parser.SUB {
+			return &unaryNegation{inner: inner}, nil
+		}
+		return inner, nil
+
+	case *parser.ParenExpr:
+		return q.newOperator(e.Expr, parent)
+
+	default:
+		return nil, newNotSupportedError(fmt.Sprintf("%T expression", expr))
+	}
+}
+
+// newSelector returns the selector of the series of the vector selector.
+func (q *query) newSelector(vs *parser.VectorSelector, lookback int64, parent parser.Node) selector {
+	hints := storage.SelectHints{
+		Step: q.statement.Interval.Milliseconds(),
+	}
+	switch p := parent.(type) {
+	case *parser.Call:
+		hints.Func = p.Func.Name
+		hints.Range = lookback
+	case *parser.AggregateExpr:
+		hints.Func = p.Op.String()
+		hints.By, hints.Grouping = !p.Without, p.Grouping
+	}
+
+	return selector{
+		queryable: q.queryable,
+		timeRange: q.timeRange,
+		matchers:  vs.LabelMatchers,
+		offset:    vs.OriginalOffset.Milliseconds(),
+		lookback:  lookback,
+		hints:     hints,
+		memory:    q.memory,
+	}
+}
+
+// unwrapNumberLiteral returns the value of the expression if it's a number, possibly in parentheses.
+func unwrapNumberLiteral(expr parser.Expr) (float64, bool) {
+	switch e := expr.(type) {
+	case *parser.NumberLiteral:
+		return e.Val, true
+	case *parser.ParenExpr:
+		return unwrapNumberLiteral(e.Expr)
+	default:
+		return 0, false
+	}
+}
+
+// Exec implements promql.Query.
+func (q *query) Exec(ctx context.Context) (res *promql.Result) {
+	q.stats = stats.NewQueryTimers()
+	defer func() { q.engine.logQuery(ctx, q, res.Err) }()
+	defer q.stats.GetTimer(stats.ExecTotalTime).Start().Stop()
+
+	if q.engine.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.engine.timeout)
+		defer cancel()
+	}
+	ctx, q.cancel = context.WithCancel(ctx)
+	defer q.cancel()
+
+	if tracker := q.engine.activeQueryTracker; tracker != nil {
+		queryID, err := tracker.Insert(ctx, q.qs)
+		if err != nil {
+			return &promql.Result{Err: err}
+		}
+		defer tracker.Delete(queryID)
+	}
+
+	defer q.stats.GetTimer(stats.EvalTotalTime).Start().Stop()
+
+	// The queriers are closed once the query is evaluated, like the Prometheus engine does.
+	defer q.root.Close()
+
+	value, err := q.eval(ctx)
+	if err != nil && ctx.Err() != nil {
+		// The storage returns the error of the context as is when the query is canceled or times out.
+		err = contextError(ctx, "expression evaluation")
+	}
+
+	if errors.Is(err, errMaxEstimatedMemoryConsumptionPerQuery) {
+		q.engine.queriesRejectedDueToMemoryConsumption.Inc()
+	}
+	if err != nil {
+		return &promql.Result{Err: err, Warnings: q.annos}
+	}
+	return &promql.Result{Value: value, Warnings: q.annos}
+}
+
+// eval evaluates the query, and returns a vector for instant queries, or a matrix for range queries.
+func (q *query) eval(ctx context.Context) (parser.Value, error) {
+	series, err := q.root.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.memory.increase(seriesLabelsSize(series)); err != nil {
+		return nil, err
+	}
+
+	isInstant := q.statement.Start.Equal(q.statement.End) && q.statement.Interval == 0
+	var (
+		vector = promql.Vector{}
+		matrix = promql.Matrix{}
+	)
+
+	for _, lbls := range series {
+		points, err := q.root.NextSeries(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// The series without values aren't returned.
+		if len(points) == 0 {
+			q.memory.putFPoints(points)
+			continue
+		}
+
+		if isInstant {
+			vector = append(vector, promql.Sample{Metric: lbls, T: q.timeRange.start, F: points[0].F})
+			q.memory.putFPoints(points)
+			continue
+		}
+		matrix = append(matrix, promql.Series{Metric: lbls, Floats: points})
+	}
+
+	if isInstant {
+		return vector, nil
+	}
+
+	// Like the Prometheus engine, the series of range queries are sorted by labels.
+	sort.Sort(matrix)
+	return matrix, nil
+}
+
+// Close implements promql.Query. It's safe to call it after Exec closed the operators already.
+func (q *query) Close() {
+	q.root.Close()
+}
+
+// Statement implements promql.Query.
+func (q *query) Statement() parser.Statement {
+	return q.statement
+}
+
+// Stats implements promql.Query.
+func (q *query) Stats() *stats.Statistics {
+	timers := q.stats
+	if timers == nil {
+		timers = stats.NewQueryTimers()
+	}
+	return &stats.Statistics{Timers: timers}
+}
+
+// Cancel implements promql.Query.
+func (q *query) Cancel() {
+	if q.cancel != nil {
+		q.cancel()
+	}
+}
+
+// String implements promql.Query.
+func (q *query) String() string {
+	return q.qs
+}
+
+var _ promql.Query = &query{}
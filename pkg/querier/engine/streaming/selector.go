@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streaming
+
+import (
+	"context"
+	"math"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+)
+
+// selector selects the series matching a vector selector from the storage, and iterates over their samples.
+type selector struct {
+	queryable storage.Queryable
+	timeRange queryTimeRange
+	matchers  []*labels.Matcher
+	offset    int64
+	// lookback is the lookback delta of instant vector selectors, and the range of range vector selectors.
+	lookback int64
+	hints    storage.SelectHints
+	memory   *memoryConsumptionTracker
+
+	querier storage.Querier
+	// series are the selected series which haven't been iterated yet. Their estimated memory is tracked
+	// until they're iterated.
+	series []storage.Series
+}
+
+// seriesMetadata selects the series from the storage, and returns their labels.
+func (s *selector) seriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	// The samples at the start of the lookback window are selected too.
+	s.hints.Start = s.timeRange.start - s.offset - s.lookback
+	s.hints.End = s.timeRange.end - s.offset
+
+	var err error
+	s.querier, err = s.queryable.Querier(s.hints.Start, s.hints.End)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like the Prometheus engine, the series aren't required to be sorted, so that the aggregations
+	// add up the values in the same order.
+	set := s.querier.Select(ctx, false, &s.hints, s.matchers...)
+	var metadata []labels.Labels
+	for set.Next() {
+		series := set.At()
+		if err := s.memory.increase(selectedSeriesSize(series)); err != nil {
+			return nil, err
+		}
+		s.series = append(s.series, series)
+		metadata = append(metadata, series.Labels())
+	}
+	if err := set.Err(); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// nextSeries returns an iterator over the samples of the next series.
+func (s *selector) nextSeries() *sampleIterator {
+	series := s.series[0]
+	s.series = s.series[1:]
+	s.memory.decrease(selectedSeriesSize(series))
+	return &sampleIterator{it: series.Iterator(nil)}
+}
+
+func (s *selector) close() {
+	for _, series := range s.series {
+		s.memory.decrease(selectedSeriesSize(series))
+	}
+	s.series = nil
+	if s.querier != nil {
+		_ = s.querier.Close()
+		s.querier = nil
+	}
+}
+
+// sampleIterator iterates over the float samples of a series, allowing to peek at the next sample.
+type sampleIterator struct {
+	it chunkenc.Iterator
+
+	peeked    bool
+	exhausted bool
+	t         int64
+	f         float64
+}
+
+// peek returns the next sample without consuming it, or false if there are no more samples.
+func (i *sampleIterator) peek() (int64, float64, bool, error) {
+	if i.exhausted {
+		return 0, 0, false, nil
+	}
+	if !i.peeked {
+		switch i.it.Next() {
+		case chunkenc.ValNone:
+			i.exhausted = true
+			return 0, 0, false, i.it.Err()
+		case chunkenc.ValFloat:
+			i.t, i.f = i.it.At()
+			i.peeked = true
+		default:
+			return 0, 0, false, newNotSupportedError("native histograms")
+		}
+	}
+	return i.t, i.f, true, nil
+}
+
+// next consumes the sample returned by peek.
+func (i *sampleIterator) next() {
+	i.peeked = false
+}
+
+// instantVectorSelector returns, at each step, the latest sample of each series within the lookback delta.
+type instantVectorSelector struct {
+	selector
+}
+
+func (v *instantVectorSelector) SeriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	return v.seriesMetadata(ctx)
+}
+
+func (v *instantVectorSelector) NextSeries(ctx context.Context) ([]promql.FPoint, error) {
+	if err := contextError(ctx, "expression evaluation"); err != nil {
+		return nil, err
+	}
+
+	it := v.nextSeries()
+	points, err := v.memory.getFPoints(v.timeRange.steps)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		lastT    = int64(math.MinInt64)
+		lastF    float64
+		haveLast bool
+	)
+
+	for ts := v.timeRange.start; ts <= v.timeRange.end; ts += v.timeRange.interval {
+		refTime := ts - v.offset
+
+		for {
+			t, f, ok, err := it.peek()
+			if err != nil {
+				v.memory.putFPoints(points)
+				return nil, err
+			}
+			if !ok || t > refTime {
+				break
+			}
+			lastT, lastF, haveLast = t, f, true
+			it.next()
+		}
+
+		if haveLast && lastT >= refTime-v.lookback && !value.IsStaleNaN(lastF) {
+			points = append(points, promql.FPoint{T: ts, F: lastF})
+		}
+	}
+	return points, nil
+}
+
+func (v *instantVectorSelector) Close() {
+	v.close()
+}
@@ -7,8 +7,10 @@ package querier
 
 import (
 	"context"
+	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/prometheus/prometheus/scrape"
 
@@ -34,11 +36,23 @@ type metricMetadata struct {
 	Unit string `json:"unit"`
 }
 
+// metricMetadataHistory is a metric metadata along with the first and last time, in seconds, it has been observed.
+type metricMetadataHistory struct {
+	metricMetadata
+	FirstSeen float64 `json:"first_seen"`
+	LastSeen  float64 `json:"last_seen"`
+}
+
 type metadataSuccessResult struct {
 	Status string                      `json:"status"`
 	Data   map[string][]metricMetadata `json:"data"`
 }
 
+type metadataHistorySuccessResult struct {
+	Status string                             `json:"status"`
+	Data   map[string][]metricMetadataHistory `json:"data"`
+}
+
 type metadataErrorResult struct {
 	Status string `json:"status"`
 	Error  string `json:"error"`
@@ -46,14 +60,22 @@ type metadataErrorResult struct {
 
 // NewMetadataHandler creates a http.Handler for serving metric metadata held by
 // Mimir for a given tenant. It is kept and returned as a set.
+//
+// If the MetadataSupplier implements MetadataHistorySupplier, the metadata observed within
+// the time range given by the optional start and end parameters is returned, and the history
+// of the metadata of each metric is returned if the history parameter is true.
 func NewMetadataHandler(m MetadataSupplier) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError := func(msg string) {
+			w.WriteHeader(http.StatusBadRequest)
+			util.WriteJSONResponse(w, metadataErrorResult{Status: statusError, Error: msg})
+		}
+
 		limit := -1
 		if s := r.FormValue("limit"); s != "" {
 			var err error
 			if limit, err = strconv.Atoi(s); err != nil {
-				w.WriteHeader(http.StatusBadRequest)
-				util.WriteJSONResponse(w, metadataErrorResult{Status: statusError, Error: "limit must be a number"})
+				writeError("limit must be a number")
 				return
 			}
 		}
@@ -61,46 +83,119 @@ func NewMetadataHandler(m MetadataSupplier) http.Handler {
 		if s := r.FormValue("limit_per_metric"); s != "" {
 			var err error
 			if limitPerMetric, err = strconv.Atoi(s); err != nil {
-				w.WriteHeader(http.StatusBadRequest)
-				util.WriteJSONResponse(w, metadataErrorResult{Status: statusError, Error: "limit_per_metric must be a number"})
+				writeError("limit_per_metric must be a number")
 				return
 			}
 		}
 		metric := r.FormValue("metric")
-		req := &client.MetricsMetadataRequest{
-			Limit:          int32(limit),
-			LimitPerMetric: int32(limitPerMetric),
-			Metric:         metric,
+
+		start, end, history := r.FormValue("start"), r.FormValue("end"), r.FormValue("history")
+		if start == "" && end == "" && history == "" {
+			req := &client.MetricsMetadataRequest{
+				Limit:          int32(limit),
+				LimitPerMetric: int32(limitPerMetric),
+				Metric:         metric,
+			}
+
+			resp, err := m.MetricsMetadata(r.Context(), req)
+			if err != nil {
+				writeError(err.Error())
+				return
+			}
+
+			metrics := map[string][]metricMetadata{}
+			for _, m := range resp {
+				addMetricMetadata(metrics, m.Metric, metricMetadata{Type: string(m.Type), Help: m.Help, Unit: m.Unit}, limit, limitPerMetric)
+			}
+
+			util.WriteJSONResponse(w, metadataSuccessResult{Status: statusSuccess, Data: metrics})
+			return
 		}
 
-		resp, err := m.MetricsMetadata(r.Context(), req)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			util.WriteJSONResponse(w, metadataErrorResult{Status: statusError, Error: err.Error()})
+		hs, ok := m.(MetadataHistorySupplier)
+		if !ok {
+			writeError("querying the metric metadata by time range or history is not enabled")
 			return
 		}
 
-		// Put all the elements of the pseudo-set into a map of slices for marshalling.
-		metrics := map[string][]metricMetadata{}
-		for _, m := range resp {
-			ms, ok := metrics[m.Metric]
-			// We enforce this both here and in the ingesters. Doing it in the ingesters is
-			// more efficient as it is earlier in the process, but since that one is per user,
-			// we still need to do it here after all the results are merged.
-			if limitPerMetric > 0 && len(ms) >= limitPerMetric {
-				continue
+		var (
+			startMs, endMs int64 = 0, math.MaxInt64
+			withHistory    bool
+			err            error
+		)
+		if start != "" {
+			if startMs, err = util.ParseTime(start); err != nil {
+				writeError("start must be a valid timestamp")
+				return
 			}
-			if !ok {
-				if limit >= 0 && len(metrics) >= limit {
-					break
-				}
-				// Most metrics will only hold 1 copy of the same metadata.
-				ms = make([]metricMetadata, 0, 1)
-				metrics[m.Metric] = ms
+		}
+		if end != "" {
+			if endMs, err = util.ParseTime(end); err != nil {
+				writeError("end must be a valid timestamp")
+				return
 			}
-			metrics[m.Metric] = append(ms, metricMetadata{Type: string(m.Type), Help: m.Help, Unit: m.Unit})
+		}
+		if endMs < startMs {
+			writeError("end timestamp must not be before start time")
+			return
+		}
+		if history != "" {
+			if withHistory, err = strconv.ParseBool(history); err != nil {
+				writeError("history must be a boolean")
+				return
+			}
+		}
+
+		resp, err := hs.MetricsMetadataHistory(r.Context(), startMs, endMs, metric)
+		if err != nil {
+			writeError(err.Error())
+			return
+		}
+
+		if !withHistory {
+			// The same metadata is observed once per metric, so it can be returned as is.
+			metrics := map[string][]metricMetadata{}
+			for _, m := range resp {
+				addMetricMetadata(metrics, m.Metric, metricMetadata{Type: m.Type, Help: m.Help, Unit: m.Unit}, limit, limitPerMetric)
+			}
+
+			util.WriteJSONResponse(w, metadataSuccessResult{Status: statusSuccess, Data: metrics})
+			return
+		}
+
+		metrics := map[string][]metricMetadataHistory{}
+		for _, m := range resp {
+			addMetricMetadata(metrics, m.Metric, metricMetadataHistory{
+				metricMetadata: metricMetadata{Type: m.Type, Help: m.Help, Unit: m.Unit},
+				FirstSeen:      millisToSeconds(m.MinTime),
+				LastSeen:       millisToSeconds(m.MaxTime),
+			}, limit, limitPerMetric)
 		}
 
-		util.WriteJSONResponse(w, metadataSuccessResult{Status: statusSuccess, Data: metrics})
+		util.WriteJSONResponse(w, metadataHistorySuccessResult{Status: statusSuccess, Data: metrics})
 	})
 }
+
+// addMetricMetadata puts an element of the pseudo-set into a map of slices for marshalling,
+// honoring the limits.
+func addMetricMetadata[T any](metrics map[string][]T, metric string, m T, limit, limitPerMetric int) {
+	ms, ok := metrics[metric]
+	// We enforce this both here and in the ingesters. Doing it in the ingesters is
+	// more efficient as it is earlier in the process, but since that one is per user,
+	// we still need to do it here after all the results are merged.
+	if limitPerMetric > 0 && len(ms) >= limitPerMetric {
+		return
+	}
+	if !ok {
+		if limit >= 0 && len(metrics) >= limit {
+			return
+		}
+		// Most metrics will only hold 1 copy of the same metadata.
+		ms = make([]T, 0, 1)
+	}
+	metrics[metric] = append(ms, m)
+}
+
+func millisToSeconds(ms int64) float64 {
+	return float64(ms) / float64(time.Second/time.Millisecond)
+}
@@ -6,6 +6,7 @@
 package querier
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,6 +17,8 @@ import (
 	"github.com/prometheus/prometheus/scrape"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 )
 
 func TestMetadataHandler_Success(t *testing.T) {
@@ -242,3 +245,122 @@ func TestMetadataHandler_Error(t *testing.T) {
 
 	require.JSONEq(t, expectedJSON, string(responseBody))
 }
+
+type metadataHistorySupplierMock struct {
+	mockDistributor
+	history []block.MetricMetadata
+}
+
+func (m *metadataHistorySupplierMock) MetricsMetadataHistory(_ context.Context, start, end int64, metric string) ([]block.MetricMetadata, error) {
+	return block.FilterMetadata(m.history, start, end, metric), nil
+}
+
+func TestMetadataHandler_History(t *testing.T) {
+	supplier := &metadataHistorySupplierMock{history: []block.MetricMetadata{
+		{Metric: "requests_total", Type: "counter", Help: "Total requests.", MinTime: 10000, MaxTime: 50000},
+		{Metric: "requests_total", Type: "counter", Help: "Total number of requests.", MinTime: 50000, MaxTime: 90000},
+		{Metric: "up", Type: "gauge", Help: "Target up.", Unit: "", MinTime: 20000, MaxTime: 30000},
+	}}
+
+	testCases := map[string]struct {
+		queryParams    url.Values
+		supplier       MetadataSupplier
+		expectedStatus int
+		expectedJSON   string
+	}{
+		"history": {
+			queryParams:    url.Values{"history": {"true"}},
+			supplier:       supplier,
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"status": "success",
+				"data": {
+					"requests_total": [
+						{"type": "counter", "help": "Total requests.", "unit": "", "first_seen": 10, "last_seen": 50},
+						{"type": "counter", "help": "Total number of requests.", "unit": "", "first_seen": 50, "last_seen": 90}
+					],
+					"up": [
+						{"type": "gauge", "help": "Target up.", "unit": "", "first_seen": 20, "last_seen": 30}
+					]
+				}
+			}`,
+		},
+		"history with time range and metric": {
+			queryParams:    url.Values{"history": {"true"}, "start": {"40"}, "end": {"60"}, "metric": {"requests_total"}},
+			supplier:       supplier,
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"status": "success",
+				"data": {
+					"requests_total": [
+						{"type": "counter", "help": "Total requests.", "unit": "", "first_seen": 40, "last_seen": 50},
+						{"type": "counter", "help": "Total number of requests.", "unit": "", "first_seen": 50, "last_seen": 60}
+					]
+				}
+			}`,
+		},
+		"time range without history": {
+			queryParams:    url.Values{"start": {"60"}},
+			supplier:       supplier,
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"status": "success",
+				"data": {
+					"requests_total": [
+						{"type": "counter", "help": "Total number of requests.", "unit": ""}
+					]
+				}
+			}`,
+		},
+		"history with limit per metric": {
+			queryParams:    url.Values{"history": {"true"}, "limit_per_metric": {"1"}, "limit": {"1"}},
+			supplier:       supplier,
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"status": "success",
+				"data": {
+					"requests_total": [
+						{"type": "counter", "help": "Total requests.", "unit": "", "first_seen": 10, "last_seen": 50}
+					]
+				}
+			}`,
+		},
+		"invalid start": {
+			queryParams:    url.Values{"start": {"foo"}},
+			supplier:       supplier,
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `{"status": "error", "error": "start must be a valid timestamp"}`,
+		},
+		"end before start": {
+			queryParams:    url.Values{"start": {"60"}, "end": {"40"}},
+			supplier:       supplier,
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `{"status": "error", "error": "end timestamp must not be before start time"}`,
+		},
+		"history not supported": {
+			queryParams:    url.Values{"history": {"true"}},
+			supplier:       &mockDistributor{},
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `{"status": "error", "error": "querying the metric metadata by time range or history is not enabled"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewMetadataHandler(tc.supplier)
+
+			request, err := http.NewRequest("GET", "/metadata", nil)
+			require.NoError(t, err)
+			request.URL.RawQuery = tc.queryParams.Encode()
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Result().StatusCode)
+			responseBody, err := io.ReadAll(recorder.Result().Body)
+			require.NoError(t, err)
+
+			require.JSONEq(t, tc.expectedJSON, string(responseBody))
+		})
+	}
+}
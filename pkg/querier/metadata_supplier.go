@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+// MetadataHistorySupplier is implemented by the MetadataSupplier able to return the metric metadata
+// observed within a time range, along with the time range within which each metadata has been observed.
+type MetadataHistorySupplier interface {
+	// MetricsMetadataHistory returns the metric metadata observed within the [start, end] time range,
+	// sorted by metric and then by the time it has been first observed. If metric is not empty, only
+	// the metadata of that metric is returned.
+	MetricsMetadataHistory(ctx context.Context, start, end int64, metric string) ([]block.MetricMetadata, error)
+}
+
+// MetricMetadataStore returns the metric metadata persisted in the long-term storage.
+type MetricMetadataStore interface {
+	MetricMetadata(ctx context.Context, mint, maxt int64, metric string) ([]block.MetricMetadata, error)
+}
+
+// NewStoreMetadataSupplier returns a MetadataSupplier, also implementing MetadataHistorySupplier, which
+// merges the metric metadata held by the ingesters with the metric metadata persisted in the store.
+func NewStoreMetadataSupplier(ingesters MetadataSupplier, store MetricMetadataStore) MetadataSupplier {
+	return &storeMetadataSupplier{
+		ingesters: ingesters,
+		store:     store,
+		now:       time.Now,
+	}
+}
+
+type storeMetadataSupplier struct {
+	ingesters MetadataSupplier
+	store     MetricMetadataStore
+	now       func() time.Time
+}
+
+// MetricsMetadata implements MetadataSupplier. It returns the metadata observed at any time, each
+// distinct metadata of a metric being returned once.
+func (s *storeMetadataSupplier) MetricsMetadata(ctx context.Context, req *client.MetricsMetadataRequest) ([]scrape.MetricMetadata, error) {
+	history, err := s.MetricsMetadataHistory(ctx, 0, math.MaxInt64, req.Metric)
+	if err != nil {
+		return nil, err
+	}
+
+	// The history is sorted by metric, and the same metadata is returned once per metric by MergeMetadata,
+	// so no further deduplication is required. Limits are applied by the caller.
+	result := make([]scrape.MetricMetadata, 0, len(history))
+	for _, m := range history {
+		result = append(result, scrape.MetricMetadata{
+			Metric: m.Metric,
+			Type:   textparse.MetricType(m.Type),
+			Help:   m.Help,
+			Unit:   m.Unit,
+		})
+	}
+	return result, nil
+}
+
+// MetricsMetadataHistory implements MetadataHistorySupplier. The metadata held by the ingesters has been
+// recently received, so it's reported as observed at the time of the request, and only returned if the
+// time range includes it.
+func (s *storeMetadataSupplier) MetricsMetadataHistory(ctx context.Context, start, end int64, metric string) ([]block.MetricMetadata, error) {
+	now := s.now().UnixMilli()
+
+	var (
+		ingestersMetadata []block.MetricMetadata
+		storeMetadata     []block.MetricMetadata
+	)
+
+	g, gCtx := errgroup.WithContext(ctx)
+	if start <= now && end >= now {
+		g.Go(func() error {
+			res, err := s.ingesters.MetricsMetadata(gCtx, &client.MetricsMetadataRequest{Limit: -1, LimitPerMetric: -1, Metric: metric})
+			if err != nil {
+				return err
+			}
+
+			ingestersMetadata = make([]block.MetricMetadata, 0, len(res))
+			for _, m := range res {
+				ingestersMetadata = append(ingestersMetadata, block.MetricMetadata{
+					Metric:  m.Metric,
+					Type:    string(m.Type),
+					Help:    m.Help,
+					Unit:    m.Unit,
+					MinTime: now,
+					MaxTime: now,
+				})
+			}
+			return nil
+		})
+	}
+	if storeEnd := min(end, now); start <= storeEnd {
+		g.Go(func() error {
+			var err error
+			storeMetadata, err = s.store.MetricMetadata(gCtx, start, storeEnd, metric)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return block.MergeMetadata(storeMetadata, ingestersMetadata), nil
+}
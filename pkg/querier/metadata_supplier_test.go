@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+type metricMetadataStoreFunc func(ctx context.Context, mint, maxt int64, metric string) ([]block.MetricMetadata, error)
+
+func (f metricMetadataStoreFunc) MetricMetadata(ctx context.Context, mint, maxt int64, metric string) ([]block.MetricMetadata, error) {
+	return f(ctx, mint, maxt, metric)
+}
+
+func TestStoreMetadataSupplier(t *testing.T) {
+	now := time.UnixMilli(100)
+
+	d := &mockDistributor{}
+	d.On("MetricsMetadata", mock.Anything, mock.Anything).Return([]scrape.MetricMetadata{
+		{Metric: "requests_total", Type: "counter", Help: "Total number of requests."},
+		{Metric: "up", Type: "gauge", Help: "Target up."},
+	}, nil)
+
+	var storeCalls [][2]int64
+	store := metricMetadataStoreFunc(func(_ context.Context, mint, maxt int64, metric string) ([]block.MetricMetadata, error) {
+		storeCalls = append(storeCalls, [2]int64{mint, maxt})
+		return block.FilterMetadata([]block.MetricMetadata{
+			{Metric: "requests_total", Type: "counter", Help: "Total requests.", MinTime: 10, MaxTime: 50},
+			{Metric: "requests_total", Type: "counter", Help: "Total number of requests.", MinTime: 50, MaxTime: 90},
+			{Metric: "old_metric", Type: "gauge", Help: "Not pushed anymore.", MinTime: 10, MaxTime: 20},
+		}, mint, maxt, metric), nil
+	})
+
+	supplier := NewStoreMetadataSupplier(d, store).(*storeMetadataSupplier)
+	supplier.now = func() time.Time { return now }
+
+	t.Run("history including now", func(t *testing.T) {
+		storeCalls = nil
+
+		actual, err := supplier.MetricsMetadataHistory(context.Background(), 0, math.MaxInt64, "")
+		require.NoError(t, err)
+		assert.Equal(t, []block.MetricMetadata{
+			{Metric: "old_metric", Type: "gauge", Help: "Not pushed anymore.", MinTime: 10, MaxTime: 20},
+			{Metric: "requests_total", Type: "counter", Help: "Total requests.", MinTime: 10, MaxTime: 50},
+			{Metric: "requests_total", Type: "counter", Help: "Total number of requests.", MinTime: 50, MaxTime: 100},
+			{Metric: "up", Type: "gauge", Help: "Target up.", MinTime: 100, MaxTime: 100},
+		}, actual)
+
+		// The store is not queried after now.
+		assert.Equal(t, [][2]int64{{0, 100}}, storeCalls)
+	})
+
+	t.Run("history in the past", func(t *testing.T) {
+		actual, err := supplier.MetricsMetadataHistory(context.Background(), 0, 30, "")
+		require.NoError(t, err)
+		assert.Equal(t, []block.MetricMetadata{
+			{Metric: "old_metric", Type: "gauge", Help: "Not pushed anymore.", MinTime: 10, MaxTime: 20},
+			{Metric: "requests_total", Type: "counter", Help: "Total requests.", MinTime: 10, MaxTime: 30},
+		}, actual)
+	})
+
+	t.Run("metadata without time range", func(t *testing.T) {
+		actual, err := supplier.MetricsMetadata(context.Background(), client.DefaultMetricsMetadataRequest())
+		require.NoError(t, err)
+		assert.Equal(t, []scrape.MetricMetadata{
+			{Metric: "old_metric", Type: "gauge", Help: "Not pushed anymore."},
+			{Metric: "requests_total", Type: "counter", Help: "Total requests."},
+			{Metric: "requests_total", Type: "counter", Help: "Total number of requests."},
+			{Metric: "up", Type: "gauge", Help: "Target up."},
+		}, actual)
+	})
+}
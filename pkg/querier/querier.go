@@ -18,18 +18,20 @@ import (
 	"github.com/grafana/dskit/tenant"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
-	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/util/annotations"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier/batch"
 	"github.com/grafana/mimir/pkg/querier/engine"
 	"github.com/grafana/mimir/pkg/querier/iterators"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/storage/chunk"
 	"github.com/grafana/mimir/pkg/storage/lazyquery"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/activitytracker"
 	"github.com/grafana/mimir/pkg/util/limiter"
@@ -58,6 +60,8 @@ type Config struct {
 	StreamingChunksPerStoreGatewaySeriesBufferSize uint64        `yaml:"streaming_chunks_per_store_gateway_series_buffer_size" category:"experimental"`
 	MinimizeIngesterRequests                       bool          `yaml:"minimize_ingester_requests" category:"experimental"`
 	MinimiseIngesterRequestsHedgingDelay           time.Duration `yaml:"minimize_ingester_requests_hedging_delay" category:"experimental"`
+	QueryExemplarsFromStoreGateways                bool          `yaml:"query_exemplars_from_store_gateways" category:"experimental"`
+	QueryMetadataFromStoreGateways                 bool          `yaml:"query_metadata_from_store_gateways" category:"experimental"`
 
 	// PromQL engine config.
 	EngineConfig engine.Config `yaml:",inline"`
@@ -93,6 +97,9 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.MinimizeIngesterRequests, minimiseIngesterRequestsFlagName, false, "If true, when querying ingesters, only the minimum required ingesters required to reach quorum will be queried initially, with other ingesters queried only if needed due to failures from the initial set of ingesters. Enabling this option reduces resource consumption for the happy path at the cost of increased latency for the unhappy path.")
 	f.DurationVar(&cfg.MinimiseIngesterRequestsHedgingDelay, minimiseIngesterRequestsFlagName+"-hedging-delay", 3*time.Second, "Delay before initiating requests to further ingesters when request minimization is enabled and the initially selected set of ingesters have not all responded. Ignored if -"+minimiseIngesterRequestsFlagName+" is not enabled.")
 
+	f.BoolVar(&cfg.QueryExemplarsFromStoreGateways, "querier.query-exemplars-from-store-gateways", false, "If true, exemplars are queried from the store-gateways too, which serve the exemplars persisted in the blocks by the ingesters when -blocks-storage.tsdb.persist-exemplars is enabled.")
+	f.BoolVar(&cfg.QueryMetadataFromStoreGateways, "querier.query-metadata-from-store-gateways", false, "If true, metric metadata is queried from the store-gateways too, which serve the metric metadata persisted in the blocks by the ingesters when -blocks-storage.tsdb.persist-metadata is enabled. This also allows to query the metric metadata by time range and its history.")
+
 	// Why 256 series / ingester/store-gateway?
 	// Based on our testing, 256 series / ingester was a good balance between memory consumption and the CPU overhead of managing a batch of series.
 	f.Uint64Var(&cfg.StreamingChunksPerIngesterSeriesBufferSize, "querier.streaming-chunks-per-ingester-buffer-size", 256, "Number of series to buffer per ingester when streaming chunks from ingesters.")
@@ -107,7 +114,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 }
 
 func (cfg *Config) Validate() error {
-	return nil
+	return cfg.EngineConfig.Validate()
 }
 
 func (cfg *Config) ValidateLimits(limits validation.Limits) error {
@@ -153,14 +160,26 @@ func ShouldQueryBlockStore(queryStoreAfter time.Duration, now time.Time, queryMi
 }
 
 // New builds a queryable and promql engine.
-func New(cfg Config, limits *validation.Overrides, distributor Distributor, storeQueryable storage.Queryable, reg prometheus.Registerer, logger log.Logger, tracker *activitytracker.ActivityTracker) (storage.SampleAndChunkQueryable, storage.ExemplarQueryable, *promql.Engine) {
+func New(cfg Config, limits *validation.Overrides, distributor Distributor, storeQueryable storage.Queryable, tombstonesLoader TombstonesLoader, reg prometheus.Registerer, logger log.Logger, tracker *activitytracker.ActivityTracker) (storage.SampleAndChunkQueryable, storage.ExemplarQueryable, engine.QueryEngine) {
 	iteratorFunc := getChunksIteratorFunction(cfg)
 	queryMetrics := stats.NewQueryMetrics(reg)
 
 	distributorQueryable := newDistributorQueryable(distributor, iteratorFunc, limits, queryMetrics, logger)
 
+	var exemplarQueryable storage.ExemplarQueryable = newDistributorExemplarQueryable(distributor, logger)
+	if storeExemplarQueryable, ok := storeQueryable.(storage.ExemplarQueryable); ok && cfg.QueryExemplarsFromStoreGateways {
+		exemplarQueryable = newMergeExemplarQueryable(exemplarQueryable, storeExemplarQueryable)
+	}
+
+	// Filter out the samples deleted by pending delete requests, both from ingesters and store-gateways.
+	if tombstonesLoader != nil {
+		distributorQueryable = newTombstonesQueryable(distributorQueryable, tombstonesLoader)
+		if storeQueryable != nil {
+			storeQueryable = newTombstonesQueryable(storeQueryable, tombstonesLoader)
+		}
+	}
+
 	queryable := newQueryable(distributorQueryable, storeQueryable, iteratorFunc, cfg, limits, queryMetrics, logger)
-	exemplarQueryable := newDistributorExemplarQueryable(distributor, logger)
 
 	lazyQueryable := storage.QueryableFunc(func(minT int64, maxT int64) (storage.Querier, error) {
 		querier, err := queryable.Querier(minT, maxT)
@@ -170,8 +189,76 @@ func New(cfg Config, limits *validation.Overrides, distributor Distributor, stor
 		return lazyquery.NewLazyQuerier(querier), nil
 	})
 
-	engine := promql.NewEngine(engine.NewPromQLEngineOptions(cfg.EngineConfig, tracker, logger, reg))
-	return NewSampleAndChunkQueryable(lazyQueryable), exemplarQueryable, engine
+	eng := engine.NewQueryEngine(cfg.EngineConfig, tracker, logger, reg)
+	return NewSampleAndChunkQueryable(lazyQueryable), exemplarQueryable, eng
+}
+
+// mergeExemplarQueryable queries the exemplars from multiple upstream queryables, merging the exemplars
+// of the same series.
+type mergeExemplarQueryable struct {
+	upstreams []storage.ExemplarQueryable
+}
+
+func newMergeExemplarQueryable(upstreams ...storage.ExemplarQueryable) storage.ExemplarQueryable {
+	return &mergeExemplarQueryable{upstreams: upstreams}
+}
+
+func (m *mergeExemplarQueryable) ExemplarQuerier(ctx context.Context) (storage.ExemplarQuerier, error) {
+	queriers := make([]storage.ExemplarQuerier, 0, len(m.upstreams))
+	for _, upstream := range m.upstreams {
+		q, err := upstream.ExemplarQuerier(ctx)
+		if err != nil {
+			return nil, err
+		}
+		queriers = append(queriers, q)
+	}
+
+	return &mergeExemplarQuerier{queriers: queriers}, nil
+}
+
+type mergeExemplarQuerier struct {
+	queriers []storage.ExemplarQuerier
+}
+
+// Select queries the exemplars from all the queriers concurrently, and merges them. The exemplars of
+// the same series with the same timestamp are deduplicated.
+func (m *mergeExemplarQuerier) Select(start, end int64, matchers ...[]*labels.Matcher) ([]exemplar.QueryResult, error) {
+	results := make([][]mimirpb.TimeSeries, len(m.queriers))
+
+	g := errgroup.Group{}
+	for i, q := range m.queriers {
+		i, q := i, q
+
+		g.Go(func() error {
+			res, err := q.Select(start, end, matchers...)
+			if err != nil {
+				return err
+			}
+
+			series := make([]mimirpb.TimeSeries, 0, len(res))
+			for _, r := range res {
+				series = append(series, mimirpb.TimeSeries{
+					Labels:    mimirpb.FromLabelsToLabelAdapters(r.SeriesLabels),
+					Exemplars: mimirpb.FromExemplarsToExemplarProtos(r.Exemplars),
+				})
+			}
+			results[i] = series
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	merged := block.MergeExemplars(results...)
+	ret := make([]exemplar.QueryResult, 0, len(merged))
+	for _, s := range merged {
+		ret = append(ret, exemplar.QueryResult{
+			SeriesLabels: mimirpb.FromLabelAdaptersToLabels(s.Labels),
+			Exemplars:    mimirpb.FromExemplarProtosToExemplars(s.Exemplars),
+		})
+	}
+	return ret, nil
 }
 
 // NewSampleAndChunkQueryable creates a SampleAndChunkQueryable from a Queryable.
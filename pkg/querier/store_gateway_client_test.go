@@ -87,3 +87,11 @@ func (m *mockStoreGatewayServer) LabelNames(context.Context, *storepb.LabelNames
 func (m *mockStoreGatewayServer) LabelValues(context.Context, *storepb.LabelValuesRequest) (*storepb.LabelValuesResponse, error) {
 	return nil, nil
 }
+
+func (m *mockStoreGatewayServer) Exemplars(context.Context, *storepb.ExemplarsRequest) (*storepb.ExemplarsResponse, error) {
+	return nil, nil
+}
+
+func (m *mockStoreGatewayServer) MetricMetadata(context.Context, *storepb.MetricMetadataRequest) (*storepb.MetricMetadataResponse, error) {
+	return nil, nil
+}
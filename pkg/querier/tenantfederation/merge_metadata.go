@@ -4,6 +4,7 @@ package tenantfederation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/go-kit/log"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/grafana/mimir/pkg/ingester/client"
 	"github.com/grafana/mimir/pkg/querier"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/util/spanlogger"
 )
 
@@ -87,3 +89,45 @@ func (m *mergeMetadataSupplier) MetricsMetadata(ctx context.Context, req *client
 
 	return out, nil
 }
+
+// MetricsMetadataHistory implements querier.MetadataHistorySupplier, merging the metadata history of all
+// tenant IDs that are part of the request. It fails if the next supplier doesn't support the history.
+func (m *mergeMetadataSupplier) MetricsMetadataHistory(ctx context.Context, start, end int64, metric string) ([]block.MetricMetadata, error) {
+	spanlog, ctx := spanlogger.NewWithLogger(ctx, m.logger, "mergeMetadataSupplier.MetricsMetadataHistory")
+	defer spanlog.Finish()
+
+	next, ok := m.next.(querier.MetadataHistorySupplier)
+	if !ok {
+		return nil, errors.New("querying the metric metadata by time range or history is not enabled")
+	}
+
+	tenantIDs, err := m.resolver.TenantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tenantIDs) == 1 {
+		level.Debug(spanlog).Log("msg", "only a single tenant, bypassing federated metadata supplier")
+		return next.MetricsMetadataHistory(ctx, start, end, metric)
+	}
+
+	results := make([][]block.MetricMetadata, len(tenantIDs))
+	run := func(jobCtx context.Context, idx int) error {
+		tenantID := tenantIDs[idx]
+		res, err := next.MetricsMetadataHistory(user.InjectOrgID(jobCtx, tenantID), start, end, metric)
+		if err != nil {
+			return fmt.Errorf("unable to run federated metadata history request for %s: %w", tenantID, err)
+		}
+
+		level.Debug(spanlog).Log("msg", "adding results for tenant to merged results", "user", tenantID, "results", len(res))
+		results[idx] = res
+		return nil
+	}
+
+	if err := concurrency.ForEachJob(ctx, len(tenantIDs), m.maxConcurrency, run); err != nil {
+		return nil, err
+	}
+
+	// The same metadata observed by multiple tenants is merged into a single entry.
+	return block.MergeMetadata(results...), nil
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/querier"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/util/test"
 )
 
@@ -104,3 +106,47 @@ func TestMergeMetadataSupplier_MetricsMetadata(t *testing.T) {
 		assert.Contains(t, res, fixtureMetadata2)
 	})
 }
+
+type mockMetadataHistorySupplier struct {
+	mockMetadataSupplier
+	history map[string][]block.MetricMetadata
+}
+
+func (m *mockMetadataHistorySupplier) MetricsMetadataHistory(ctx context.Context, _, _ int64, _ string) ([]block.MetricMetadata, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse single tenant ID from context: %w", err)
+	}
+
+	return m.history[tenantID], nil
+}
+
+func TestMergeMetadataSupplier_MetricsMetadataHistory(t *testing.T) {
+	t.Run("history not supported by the next supplier", func(t *testing.T) {
+		supplier := NewMetadataSupplier(&mockMetadataSupplier{}, defaultConcurrency, test.NewTestingLogger(t))
+		_, err := supplier.(querier.MetadataHistorySupplier).MetricsMetadataHistory(user.InjectOrgID(context.Background(), "team-a"), 0, 10, "")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("multiple tenants", func(t *testing.T) {
+		upstream := &mockMetadataHistorySupplier{
+			history: map[string][]block.MetricMetadata{
+				"team-a": {{Metric: "up", Type: "gauge", MinTime: 0, MaxTime: 5}},
+				"team-b": {
+					{Metric: "up", Type: "gauge", MinTime: 3, MaxTime: 10},
+					{Metric: "requests", Type: "counter", MinTime: 1, MaxTime: 2},
+				},
+			},
+		}
+
+		supplier := NewMetadataSupplier(upstream, defaultConcurrency, test.NewTestingLogger(t))
+		res, err := supplier.(querier.MetadataHistorySupplier).MetricsMetadataHistory(user.InjectOrgID(context.Background(), "team-a|team-b"), 0, 10, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, []block.MetricMetadata{
+			{Metric: "requests", Type: "counter", MinTime: 1, MaxTime: 2},
+			{Metric: "up", Type: "gauge", MinTime: 0, MaxTime: 10},
+		}, res)
+	})
+}
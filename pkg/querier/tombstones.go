@@ -0,0 +1,326 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/tombstones"
+	"github.com/prometheus/prometheus/util/annotations"
+	"golang.org/x/exp/slices"
+
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+)
+
+// TombstonesLoader returns the tombstones of the pending delete requests of a tenant.
+// It's implemented by mimir_tsdb.DeleteRequestsLoader, which keeps them updated in background.
+type TombstonesLoader interface {
+	Tombstones(ctx context.Context, userID string) (*mimir_tsdb.Tombstones, error)
+}
+
+// newTombstonesQueryable returns a queryable filtering out the samples deleted by the tenant's pending delete requests.
+func newTombstonesQueryable(next storage.Queryable, loader TombstonesLoader) storage.Queryable {
+	return storage.QueryableFunc(func(mint, maxt int64) (storage.Querier, error) {
+		q, err := next.Querier(mint, maxt)
+		if err != nil {
+			return nil, err
+		}
+		return &tombstonesQuerier{Querier: q, loader: loader, mint: mint, maxt: maxt}, nil
+	})
+}
+
+// tombstonesQuerier filters out the deleted samples from the selected series, and the label names and
+// values found only in the series whose samples within the querier time range have all been deleted.
+type tombstonesQuerier struct {
+	storage.Querier
+
+	loader     TombstonesLoader
+	mint, maxt int64
+}
+
+func (q *tombstonesQuerier) Select(ctx context.Context, sortSeries bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	stones, err := q.tombstones(ctx)
+	if err != nil {
+		return storage.ErrSeriesSet(err)
+	}
+
+	mint, maxt := q.mint, q.maxt
+	if hints != nil {
+		mint, maxt = hints.Start, hints.End
+	}
+
+	if !stones.Overlaps(mint, maxt) {
+		return q.Querier.Select(ctx, sortSeries, hints, matchers...)
+	}
+
+	// The series-only requests don't return the chunks, which are required to know whether the
+	// series have any sample left within the time range, so the chunks are requested anyway.
+	seriesOnly := hints != nil && hints.Func == "series"
+	if seriesOnly {
+		withChunks := *hints
+		withChunks.Func = ""
+		hints = &withChunks
+	}
+
+	set := q.Querier.Select(ctx, sortSeries, hints, matchers...)
+	return &tombstonesSeriesSet{SeriesSet: set, stones: stones, mint: mint, maxt: maxt, skipEmpty: seriesOnly}
+}
+
+// LabelValues returns the values of the label name, without the values found only in the deleted samples.
+// The values are read from the index, and only the values of the series matching the delete requests
+// overlapping the querier time range are checked against the series samples, which is more expensive.
+func (q *tombstonesQuerier) LabelValues(ctx context.Context, name string, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	values, warnings, err := q.Querier.LabelValues(ctx, name, matchers...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selectors, err := q.selectors(ctx)
+	if err != nil || len(selectors) == 0 {
+		return values, warnings, err
+	}
+
+	// Find the values of the series matching the delete requests: the other values can't be deleted.
+	affected := map[string]map[string]struct{}{}
+	ws, err := q.selectAffected(ctx, selectors, append([]*labels.Matcher{labels.MustNewMatcher(labels.MatchNotEqual, name, "")}, matchers...), func(lset labels.Labels) {
+		addLabelValue(affected, name, lset.Get(name))
+	})
+	warnings.Merge(ws)
+	if err != nil || len(affected) == 0 {
+		return values, warnings, err
+	}
+
+	// Keep the affected values which are found in some samples left.
+	kept := map[string]struct{}{}
+	ws, err = q.selectNotDeleted(ctx, append([]*labels.Matcher{valuesMatcher(name, affected[name])}, matchers...), func(lset labels.Labels) bool {
+		kept[lset.Get(name)] = struct{}{}
+		return len(kept) < len(affected[name])
+	})
+	warnings.Merge(ws)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := affected[name][v]; !ok {
+			res = append(res, v)
+		} else if _, ok := kept[v]; ok {
+			res = append(res, v)
+		}
+	}
+	return res, warnings, nil
+}
+
+// LabelNames returns the label names, without the names found only in the deleted samples. Like
+// LabelValues, only the names of the series matching the delete requests overlapping the querier time
+// range are checked against the series samples.
+func (q *tombstonesQuerier) LabelNames(ctx context.Context, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	names, warnings, err := q.Querier.LabelNames(ctx, matchers...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selectors, err := q.selectors(ctx)
+	if err != nil || len(selectors) == 0 {
+		return names, warnings, err
+	}
+
+	// Find the names, and their values, of the series matching the delete requests.
+	affected := map[string]map[string]struct{}{}
+	ws, err := q.selectAffected(ctx, selectors, matchers, func(lset labels.Labels) {
+		lset.Range(func(l labels.Label) {
+			addLabelValue(affected, l.Name, l.Value)
+		})
+	})
+	warnings.Merge(ws)
+	if err != nil || len(affected) == 0 {
+		return names, warnings, err
+	}
+
+	res := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, ok := affected[name]; !ok {
+			res = append(res, name)
+			continue
+		}
+
+		// The name is kept if it has values not found in the series matching the delete requests,
+		// or if some samples are left in the series having the affected values.
+		values, ws, err := q.Querier.LabelValues(ctx, name, matchers...)
+		warnings.Merge(ws)
+		if err != nil {
+			return nil, nil, err
+		}
+		keep := slices.ContainsFunc(values, func(v string) bool {
+			_, ok := affected[name][v]
+			return !ok
+		})
+		if !keep {
+			ws, err = q.selectNotDeleted(ctx, append([]*labels.Matcher{valuesMatcher(name, affected[name])}, matchers...), func(labels.Labels) bool {
+				keep = true
+				return false
+			})
+			warnings.Merge(ws)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if keep {
+			res = append(res, name)
+		}
+	}
+	return res, warnings, nil
+}
+
+// selectors returns the series selectors of the delete requests overlapping the querier time range.
+func (q *tombstonesQuerier) selectors(ctx context.Context) ([][]*labels.Matcher, error) {
+	stones, err := q.tombstones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stones.Selectors(q.mint, q.maxt), nil
+}
+
+// selectAffected calls fn with the labels of the series matching both the matchers and any of the
+// selectors, regardless of their samples.
+func (q *tombstonesQuerier) selectAffected(ctx context.Context, selectors [][]*labels.Matcher, matchers []*labels.Matcher, fn func(labels.Labels)) (annotations.Annotations, error) {
+	var warnings annotations.Annotations
+	for _, selector := range selectors {
+		set := q.Querier.Select(ctx, false, &storage.SelectHints{Start: q.mint, End: q.maxt, Func: "series"}, append(slices.Clone(matchers), selector...)...)
+		for set.Next() {
+			fn(set.At().Labels())
+		}
+		warnings.Merge(set.Warnings())
+		if err := set.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return warnings, nil
+}
+
+// selectNotDeleted calls fn with the labels of the series matching the matchers which have samples
+// left within the querier time range, until fn returns false.
+func (q *tombstonesQuerier) selectNotDeleted(ctx context.Context, matchers []*labels.Matcher, fn func(labels.Labels) bool) (annotations.Annotations, error) {
+	stones, err := q.tombstones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	set := q.Querier.Select(ctx, false, &storage.SelectHints{Start: q.mint, End: q.maxt}, matchers...)
+	set = &tombstonesSeriesSet{SeriesSet: set, stones: stones, mint: q.mint, maxt: q.maxt, skipEmpty: true}
+	for set.Next() {
+		if !fn(set.At().Labels()) {
+			break
+		}
+	}
+	return set.Warnings(), set.Err()
+}
+
+func (q *tombstonesQuerier) tombstones(ctx context.Context) (*mimir_tsdb.Tombstones, error) {
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return q.loader.Tombstones(ctx, userID)
+}
+
+func addLabelValue(m map[string]map[string]struct{}, name, value string) {
+	values, ok := m[name]
+	if !ok {
+		values = map[string]struct{}{}
+		m[name] = values
+	}
+	values[value] = struct{}{}
+}
+
+// valuesMatcher returns a matcher selecting the series having any of the values for the label name.
+func valuesMatcher(name string, values map[string]struct{}) *labels.Matcher {
+	quoted := make([]string, 0, len(values))
+	for _, v := range sortedKeys(values) {
+		quoted = append(quoted, regexp.QuoteMeta(v))
+	}
+	return labels.MustNewMatcher(labels.MatchRegexp, name, strings.Join(quoted, "|"))
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// tombstonesSeriesSet wraps the series having deleted samples, and skips the series whose
+// samples have been deleted for the whole queried time range. If skipEmpty is true, the
+// series having no sample left within the queried time range are skipped as well.
+type tombstonesSeriesSet struct {
+	storage.SeriesSet
+
+	stones     *mimir_tsdb.Tombstones
+	mint, maxt int64
+	skipEmpty  bool
+	curr       storage.Series
+}
+
+func (s *tombstonesSeriesSet) Next() bool {
+	for s.SeriesSet.Next() {
+		series := s.SeriesSet.At()
+
+		intervals := s.stones.Intervals(series.Labels())
+		if len(intervals) == 0 {
+			s.curr = series
+			return true
+		}
+		if (tombstones.Interval{Mint: s.mint, Maxt: s.maxt}).IsSubrange(intervals) {
+			continue
+		}
+
+		curr := &tombstonesSeries{Series: series, intervals: intervals}
+		if s.skipEmpty && !hasSamples(curr, s.mint, s.maxt) {
+			continue
+		}
+
+		s.curr = curr
+		return true
+	}
+	return false
+}
+
+// hasSamples returns whether the series has any sample within the mint and maxt time range.
+func hasSamples(series storage.Series, mint, maxt int64) bool {
+	it := series.Iterator(nil)
+	if it.Seek(mint) == chunkenc.ValNone {
+		return false
+	}
+	return it.AtT() <= maxt
+}
+
+func (s *tombstonesSeriesSet) At() storage.Series { return s.curr }
+
+func (s *tombstonesSeriesSet) Warnings() annotations.Annotations { return s.SeriesSet.Warnings() }
+
+type tombstonesSeries struct {
+	storage.Series
+
+	intervals tombstones.Intervals
+}
+
+func (s *tombstonesSeries) Iterator(it chunkenc.Iterator) chunkenc.Iterator {
+	// Reuse the wrapped iterator, if any.
+	if deleted, ok := it.(*tsdb.DeletedIterator); ok {
+		deleted.Iter = s.Series.Iterator(deleted.Iter)
+		deleted.Intervals = s.intervals
+		return deleted
+	}
+	return &tsdb.DeletedIterator{Iter: s.Series.Iterator(it), Intervals: s.intervals}
+}
@@ -0,0 +1,272 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/util/annotations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	"github.com/grafana/mimir/pkg/storage/series"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+)
+
+func TestTombstonesQueryable(t *testing.T) {
+	const userID = "user"
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	bkt := objstore.NewInMemBucket()
+	userBkt := bucket.NewUserBucketClient(userID, bkt, nil)
+	now := time.Now()
+
+	pending, err := mimir_tsdb.NewDeleteRequest([]string{`{job="a"}`}, 2, 3, now)
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBkt, pending))
+
+	fullyDeleted, err := mimir_tsdb.NewDeleteRequest([]string{`{job="b"}`}, 0, 10, now)
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBkt, fullyDeleted))
+
+	// Processed requests are not applied anymore.
+	processed, err := mimir_tsdb.NewDeleteRequest([]string{`{job="c"}`}, 0, 10, now)
+	require.NoError(t, err)
+	processed.State = mimir_tsdb.DeleteRequestProcessed
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBkt, processed))
+
+	samples := []model.SamplePair{{Timestamp: 1, Value: 1}, {Timestamp: 2, Value: 2}, {Timestamp: 3, Value: 3}, {Timestamp: 4, Value: 4}}
+	next := storage.QueryableFunc(func(int64, int64) (storage.Querier, error) {
+		return mockQuerier{
+			seriesSet: series.NewConcreteSeriesSetFromUnsortedSeries([]storage.Series{
+				series.NewConcreteSeries(labels.FromStrings("job", "a"), samples, nil),
+				series.NewConcreteSeries(labels.FromStrings("job", "b"), samples, nil),
+				series.NewConcreteSeries(labels.FromStrings("job", "c"), samples, nil),
+			}),
+		}, nil
+	})
+
+	queryable := newTombstonesQueryable(next, mimir_tsdb.NewDeleteRequestsLoader(bkt, time.Minute, time.Hour, log.NewNopLogger()))
+	q, err := queryable.Querier(0, 10)
+	require.NoError(t, err)
+
+	set := q.Select(ctx, true, &storage.SelectHints{Start: 0, End: 10})
+
+	actual := map[string][]int64{}
+	for set.Next() {
+		var timestamps []int64
+		it := set.At().Iterator(nil)
+		for it.Next() != 0 {
+			ts, _ := it.At()
+			timestamps = append(timestamps, ts)
+		}
+		require.NoError(t, it.Err())
+		actual[set.At().Labels().Get("job")] = timestamps
+	}
+	require.NoError(t, set.Err())
+
+	assert.Equal(t, map[string][]int64{
+		"a": {1, 4},
+		"c": {1, 2, 3, 4},
+	}, actual)
+}
+
+func TestTombstonesQueryable_ShouldFilterOutLabelsAndSeriesOfDeletedSamples(t *testing.T) {
+	const userID = "user"
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	bkt := objstore.NewInMemBucket()
+	userBkt := bucket.NewUserBucketClient(userID, bkt, nil)
+	now := time.Now()
+
+	for _, selector := range []string{`{job="a"}`, `{job="b"}`, `{job="d"}`} {
+		startTime, endTime := int64(0), int64(10)
+		if selector == `{job="a"}` {
+			startTime, endTime = 2, 3
+		} else if selector == `{job="d"}` {
+			endTime = 6
+		}
+		req, err := mimir_tsdb.NewDeleteRequest([]string{selector}, startTime, endTime, now)
+		require.NoError(t, err)
+		require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBkt, req))
+	}
+
+	samples := []model.SamplePair{{Timestamp: 1, Value: 1}, {Timestamp: 2, Value: 2}, {Timestamp: 3, Value: 3}, {Timestamp: 4, Value: 4}}
+	allSeries := []storage.Series{
+		// Partially deleted.
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "metric", "job", "a", "env", "prod"), samples, nil),
+		// Deleted for the whole time range.
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "metric", "job", "b", "team", "x"), samples, nil),
+		// Not deleted.
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "metric", "job", "c"), samples, nil),
+		// All the samples within the time range have been deleted, but not the whole time range.
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "metric", "job", "d", "zone", "z"), []model.SamplePair{{Timestamp: 5, Value: 5}}, nil),
+	}
+
+	var selectedFuncs []string
+	next := storage.QueryableFunc(func(int64, int64) (storage.Querier, error) {
+		return &filteringQuerier{series: allSeries, onSelect: func(hints *storage.SelectHints, _ []*labels.Matcher) {
+			selectedFuncs = append(selectedFuncs, hints.Func)
+		}}, nil
+	})
+
+	queryable := newTombstonesQueryable(next, mimir_tsdb.NewDeleteRequestsLoader(bkt, time.Minute, time.Hour, log.NewNopLogger()))
+	q, err := queryable.Querier(1, 10)
+	require.NoError(t, err)
+
+	names, _, err := q.LabelNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{labels.MetricName, "env", "job"}, names)
+
+	values, _, err := q.LabelValues(ctx, "job")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, values)
+
+	values, _, err = q.LabelValues(ctx, "job", labels.MustNewMatcher(labels.MatchNotEqual, "job", "a"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, values)
+
+	// The series-only requests load the chunks to know whether any sample is left.
+	selectedFuncs = nil
+	set := q.Select(ctx, true, &storage.SelectHints{Start: 1, End: 10, Func: "series"}, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "metric"))
+
+	var jobs []string
+	for set.Next() {
+		jobs = append(jobs, set.At().Labels().Get("job"))
+	}
+	require.NoError(t, set.Err())
+	assert.Equal(t, []string{"a", "c"}, jobs)
+	assert.Equal(t, []string{""}, selectedFuncs)
+
+	// Without any delete request overlapping the time range, the requests are forwarded as is.
+	q, err = queryable.Querier(20, 30)
+	require.NoError(t, err)
+
+	values, _, err = q.LabelValues(ctx, "job")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, values)
+
+	selectedFuncs = nil
+	set = q.Select(ctx, true, &storage.SelectHints{Start: 20, End: 30, Func: "series"})
+	for set.Next() {
+	}
+	require.NoError(t, set.Err())
+	assert.Equal(t, []string{"series"}, selectedFuncs)
+}
+
+func TestTombstonesQueryable_ShouldOnlySelectTheSeriesAffectedByDeleteRequestsForLabels(t *testing.T) {
+	const userID = "user"
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	bkt := objstore.NewInMemBucket()
+	userBkt := bucket.NewUserBucketClient(userID, bkt, nil)
+
+	req, err := mimir_tsdb.NewDeleteRequest([]string{`{job="a"}`}, 0, 10, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteDeleteRequest(ctx, userBkt, req))
+
+	samples := []model.SamplePair{{Timestamp: 1, Value: 1}, {Timestamp: 2, Value: 2}}
+	allSeries := []storage.Series{
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "metric", "job", "a", "env", "prod", "team", "x"), samples, nil),
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "metric", "job", "b", "env", "prod"), samples, nil),
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "metric", "job", "c", "env", "dev"), samples, nil),
+	}
+
+	var selected []string
+	next := storage.QueryableFunc(func(int64, int64) (storage.Querier, error) {
+		return &filteringQuerier{series: allSeries, onSelect: func(_ *storage.SelectHints, matchers []*labels.Matcher) {
+			selected = append(selected, fmt.Sprint(matchers))
+		}}, nil
+	})
+
+	queryable := newTombstonesQueryable(next, mimir_tsdb.NewDeleteRequestsLoader(bkt, time.Minute, time.Hour, log.NewNopLogger()))
+	q, err := queryable.Querier(0, 10)
+	require.NoError(t, err)
+
+	names, _, err := q.LabelNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{labels.MetricName, "env", "job"}, names)
+
+	// The series matching the delete request are selected first, and then only the series sharing their
+	// values for the names which may be found only in the deleted samples.
+	assert.Equal(t, []string{
+		`[job="a"]`,
+		`[__name__=~"metric"]`,
+		`[team=~"x"]`,
+	}, selected)
+
+	selected = nil
+	values, _, err := q.LabelValues(ctx, "env")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "prod"}, values)
+	assert.Equal(t, []string{
+		`[env!="" job="a"]`,
+		`[env=~"prod"]`,
+	}, selected)
+
+	// The values of the series not matching the delete requests are not checked.
+	selected = nil
+	values, _, err = q.LabelValues(ctx, "env", labels.MustNewMatcher(labels.MatchEqual, "job", "c"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev"}, values)
+	assert.Equal(t, []string{`[env!="" job="c" job="a"]`}, selected)
+}
+
+// filteringQuerier is a storage.Querier returning the series matching the matchers, regardless of the time range.
+type filteringQuerier struct {
+	series   []storage.Series
+	onSelect func(hints *storage.SelectHints, matchers []*labels.Matcher)
+}
+
+func (q *filteringQuerier) Select(_ context.Context, _ bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	q.onSelect(hints, matchers)
+
+	var res []storage.Series
+	for _, s := range q.series {
+		if matchesAllMatchers(s.Labels(), matchers) {
+			res = append(res, s)
+		}
+	}
+	return series.NewConcreteSeriesSetFromUnsortedSeries(res)
+}
+
+func (q *filteringQuerier) LabelValues(_ context.Context, name string, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	values := map[string]struct{}{}
+	for _, s := range q.series {
+		if v := s.Labels().Get(name); v != "" && matchesAllMatchers(s.Labels(), matchers) {
+			values[v] = struct{}{}
+		}
+	}
+	return sortedKeys(values), nil, nil
+}
+
+func (q *filteringQuerier) LabelNames(_ context.Context, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	names := map[string]struct{}{}
+	for _, s := range q.series {
+		if matchesAllMatchers(s.Labels(), matchers) {
+			s.Labels().Range(func(l labels.Label) { names[l.Name] = struct{}{} })
+		}
+	}
+	return sortedKeys(names), nil, nil
+}
+
+func (q *filteringQuerier) Close() error { return nil }
+
+func matchesAllMatchers(lset labels.Labels, matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		if !m.Matches(lset.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
@@ -228,6 +228,7 @@ func (sp *schedulerProcessor) runRequest(ctx context.Context, logger log.Logger,
 			QueryID:      queryID,
 			HttpResponse: response,
 			Stats:        stats,
+			QuerierID:    sp.querierID,
 		})
 		if err == nil || retries >= maxNotifyFrontendRetries {
 			break
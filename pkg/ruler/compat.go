@@ -28,6 +28,7 @@ import (
 
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier"
+	"github.com/grafana/mimir/pkg/querier/engine"
 	querier_stats "github.com/grafana/mimir/pkg/querier/stats"
 	util_log "github.com/grafana/mimir/pkg/util/log"
 )
@@ -147,6 +148,36 @@ type RulesLimits interface {
 	RulerSyncRulesOnChangesEnabled(userID string) bool
 }
 
+// EngineQueryFunc returns a query function executing instant queries with the given engine, which
+// is not necessarily the Prometheus engine, unlike rules.EngineQueryFunc. Scalar results are converted
+// to vectors.
+func EngineQueryFunc(engine engine.QueryEngine, q storage.Queryable) rules.QueryFunc {
+	return func(ctx context.Context, qs string, t time.Time) (promql.Vector, error) {
+		qry, err := engine.NewInstantQuery(ctx, q, nil, qs, t)
+		if err != nil {
+			return nil, err
+		}
+		defer qry.Close()
+
+		res := qry.Exec(ctx)
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		switch v := res.Value.(type) {
+		case promql.Vector:
+			return v, nil
+		case promql.Scalar:
+			return promql.Vector{promql.Sample{
+				T:      v.T,
+				F:      v.V,
+				Metric: labels.EmptyLabels(),
+			}}, nil
+		default:
+			return nil, errors.New("rule result is not a vector or scalar")
+		}
+	}
+}
+
 func MetricsQueryFunc(qf rules.QueryFunc, queries, failedQueries prometheus.Counter) rules.QueryFunc {
 	return func(ctx context.Context, qs string, t time.Time) (promql.Vector, error) {
 		queries.Inc()
@@ -10,6 +10,7 @@ import (
 	"flag"
 	"io"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -321,6 +322,19 @@ func (s *Scheduler) frontendDisconnected(frontendAddress string) {
 	}
 }
 
+// ConnectedFrontends returns the addresses of the query-frontends connected to the scheduler.
+func (s *Scheduler) ConnectedFrontends(_ context.Context, _ *schedulerpb.ConnectedFrontendsRequest) (*schedulerpb.ConnectedFrontendsResponse, error) {
+	s.connectedFrontendsMu.Lock()
+	defer s.connectedFrontendsMu.Unlock()
+
+	resp := &schedulerpb.ConnectedFrontendsResponse{Addresses: make([]string, 0, len(s.connectedFrontends))}
+	for addr := range s.connectedFrontends {
+		resp.Addresses = append(resp.Addresses, addr)
+	}
+	sort.Strings(resp.Addresses)
+	return resp, nil
+}
+
 func (s *Scheduler) enqueueRequest(requestContext context.Context, frontendAddr string, msg *schedulerpb.FrontendToScheduler) error {
 	// Create new context for this request, to support cancellation.
 	ctx, cancel := context.WithCancel(requestContext)
@@ -107,6 +107,22 @@ func TestSchedulerBasicEnqueue(t *testing.T) {
 	verifyNoPendingRequestsLeft(t, scheduler)
 }
 
+func TestSchedulerConnectedFrontends(t *testing.T) {
+	_, frontendClient, _ := setupScheduler(t, nil)
+
+	resp, err := frontendClient.ConnectedFrontends(context.Background(), &schedulerpb.ConnectedFrontendsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Addresses)
+
+	initFrontendLoop(t, frontendClient, "frontend-2")
+	initFrontendLoop(t, frontendClient, "frontend-1")
+	initFrontendLoop(t, frontendClient, "frontend-1")
+
+	resp, err = frontendClient.ConnectedFrontends(context.Background(), &schedulerpb.ConnectedFrontendsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"frontend-1", "frontend-2"}, resp.Addresses)
+}
+
 func TestSchedulerEnqueueWithCancel(t *testing.T) {
 	scheduler, frontendClient, querierClient := setupScheduler(t, nil)
 
@@ -424,6 +424,84 @@ func (m *NotifyQuerierShutdownResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_NotifyQuerierShutdownResponse proto.InternalMessageInfo
 
+type ConnectedFrontendsRequest struct {
+}
+
+func (m *ConnectedFrontendsRequest) Reset()      { *m = ConnectedFrontendsRequest{} }
+func (*ConnectedFrontendsRequest) ProtoMessage() {}
+func (*ConnectedFrontendsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{6}
+}
+func (m *ConnectedFrontendsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ConnectedFrontendsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ConnectedFrontendsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ConnectedFrontendsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectedFrontendsRequest.Merge(m, src)
+}
+func (m *ConnectedFrontendsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ConnectedFrontendsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectedFrontendsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectedFrontendsRequest proto.InternalMessageInfo
+
+type ConnectedFrontendsResponse struct {
+	Addresses []string `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+}
+
+func (m *ConnectedFrontendsResponse) Reset()      { *m = ConnectedFrontendsResponse{} }
+func (*ConnectedFrontendsResponse) ProtoMessage() {}
+func (*ConnectedFrontendsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{7}
+}
+func (m *ConnectedFrontendsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ConnectedFrontendsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ConnectedFrontendsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ConnectedFrontendsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectedFrontendsResponse.Merge(m, src)
+}
+func (m *ConnectedFrontendsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ConnectedFrontendsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectedFrontendsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectedFrontendsResponse proto.InternalMessageInfo
+
+func (m *ConnectedFrontendsResponse) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("schedulerpb.FrontendToSchedulerType", FrontendToSchedulerType_name, FrontendToSchedulerType_value)
 	proto.RegisterEnum("schedulerpb.SchedulerToFrontendStatus", SchedulerToFrontendStatus_name, SchedulerToFrontendStatus_value)
@@ -433,52 +511,58 @@ func init() {
 	proto.RegisterType((*SchedulerToFrontend)(nil), "schedulerpb.SchedulerToFrontend")
 	proto.RegisterType((*NotifyQuerierShutdownRequest)(nil), "schedulerpb.NotifyQuerierShutdownRequest")
 	proto.RegisterType((*NotifyQuerierShutdownResponse)(nil), "schedulerpb.NotifyQuerierShutdownResponse")
+	proto.RegisterType((*ConnectedFrontendsRequest)(nil), "schedulerpb.ConnectedFrontendsRequest")
+	proto.RegisterType((*ConnectedFrontendsResponse)(nil), "schedulerpb.ConnectedFrontendsResponse")
 }
 
 func init() { proto.RegisterFile("scheduler.proto", fileDescriptor_2b3fc28395a6d9c5) }
 
 var fileDescriptor_2b3fc28395a6d9c5 = []byte{
-	// 640 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xcf, 0x4f, 0x1a, 0x41,
-	0x14, 0xc7, 0x77, 0x10, 0x50, 0x1f, 0xb6, 0x6e, 0x47, 0x6d, 0x29, 0xb1, 0x23, 0x21, 0x4d, 0x43,
-	0x3d, 0x80, 0xa1, 0x87, 0xf6, 0x60, 0x9a, 0x50, 0x5d, 0x2b, 0xa9, 0x5d, 0x74, 0x19, 0xd2, 0x1f,
-	0x17, 0x02, 0xec, 0x08, 0x44, 0xbb, 0xb3, 0xce, 0xce, 0xa6, 0xe1, 0xd6, 0x63, 0x8f, 0xfd, 0x33,
-	0xfa, 0xa7, 0xf4, 0xd2, 0xc4, 0xa3, 0x87, 0x1e, 0xea, 0x7a, 0xe9, 0xd1, 0x3f, 0xa1, 0x61, 0x59,
-	0xe8, 0x62, 0x17, 0xf5, 0xf6, 0xe6, 0xf1, 0xfd, 0x2e, 0xef, 0x7d, 0xde, 0x9b, 0x81, 0x45, 0xa7,
-	0xdd, 0x65, 0xa6, 0x7b, 0xcc, 0x44, 0xc1, 0x16, 0x5c, 0x72, 0x9c, 0x1a, 0x27, 0xec, 0x56, 0x66,
-	0xb9, 0xc3, 0x3b, 0xdc, 0xcf, 0x17, 0x07, 0xd1, 0x50, 0x92, 0xd9, 0xe8, 0xf4, 0x64, 0xd7, 0x6d,
-	0x15, 0xda, 0xfc, 0x53, 0xb1, 0x23, 0x9a, 0x87, 0x4d, 0xab, 0x59, 0x34, 0x9d, 0xa3, 0x9e, 0x2c,
-	0x76, 0xa5, 0xb4, 0x3b, 0xc2, 0x6e, 0x8f, 0x83, 0xa1, 0x23, 0x57, 0x02, 0x7c, 0xe0, 0x32, 0xd1,
-	0x63, 0x82, 0xf2, 0xda, 0xe8, 0xfb, 0x78, 0x15, 0xe6, 0x4f, 0x86, 0xd9, 0xca, 0x76, 0x1a, 0x65,
-	0x51, 0x7e, 0xde, 0xf8, 0x97, 0xc8, 0xfd, 0x44, 0x80, 0xc7, 0x5a, 0xca, 0x03, 0x3f, 0x4e, 0xc3,
-	0xec, 0x40, 0xd3, 0x0f, 0x2c, 0x71, 0x63, 0x74, 0xc4, 0xcf, 0x21, 0x35, 0xf8, 0x5b, 0x83, 0x9d,
-	0xb8, 0xcc, 0x91, 0xe9, 0x58, 0x16, 0xe5, 0x53, 0xa5, 0x95, 0xc2, 0xb8, 0x94, 0x5d, 0x4a, 0xf7,
-	0x83, 0x1f, 0x8d, 0xb0, 0x12, 0xe7, 0x61, 0xf1, 0x50, 0x70, 0x4b, 0x32, 0xcb, 0x2c, 0x9b, 0xa6,
-	0x60, 0x8e, 0x93, 0x9e, 0xf1, 0xab, 0xb9, 0x9a, 0xc6, 0xf7, 0x21, 0xe9, 0x3a, 0x7e, 0xb9, 0x71,
-	0x5f, 0x10, 0x9c, 0x70, 0x0e, 0x16, 0x1c, 0xd9, 0x94, 0x8e, 0x66, 0x35, 0x5b, 0xc7, 0xcc, 0x4c,
-	0x27, 0xb2, 0x28, 0x3f, 0x67, 0x4c, 0xe4, 0x72, 0x5f, 0x63, 0xb0, 0xb4, 0x13, 0x7c, 0x2f, 0x4c,
-	0xe1, 0x05, 0xc4, 0x65, 0xdf, 0x66, 0x7e, 0x37, 0x77, 0x4b, 0x8f, 0x0b, 0x21, 0xfe, 0x85, 0x08,
-	0x3d, 0xed, 0xdb, 0xcc, 0xf0, 0x1d, 0x51, 0x75, 0xc7, 0xa2, 0xeb, 0x0e, 0x41, 0x9b, 0x99, 0x84,
-	0x36, 0xad, 0xa3, 0x2b, 0x30, 0x13, 0xb7, 0x86, 0x79, 0x15, 0x45, 0x32, 0x02, 0xc5, 0x11, 0x2c,
-	0x85, 0x26, 0x3b, 0x6a, 0x12, 0xbf, 0x84, 0xe4, 0x40, 0xe6, 0x3a, 0x01, 0x8b, 0x27, 0x13, 0x2c,
-	0x22, 0x1c, 0x35, 0x5f, 0x6d, 0x04, 0x2e, 0xbc, 0x0c, 0x09, 0x26, 0x04, 0x17, 0x01, 0x85, 0xe1,
-	0x21, 0xb7, 0x09, 0xab, 0x3a, 0x97, 0xbd, 0xc3, 0x7e, 0xb0, 0x41, 0xb5, 0xae, 0x2b, 0x4d, 0xfe,
-	0xd9, 0x1a, 0x15, 0x7c, 0xfd, 0x16, 0xae, 0xc1, 0xa3, 0x29, 0x6e, 0xc7, 0xe6, 0x96, 0xc3, 0xd6,
-	0x37, 0xe1, 0xc1, 0x94, 0x29, 0xe1, 0x39, 0x88, 0x57, 0xf4, 0x0a, 0x55, 0x15, 0x9c, 0x82, 0x59,
-	0x4d, 0x3f, 0xa8, 0x6b, 0x75, 0x4d, 0x45, 0x18, 0x20, 0xb9, 0x55, 0xd6, 0xb7, 0xb4, 0x3d, 0x35,
-	0xb6, 0xde, 0x86, 0x87, 0x53, 0xfb, 0xc2, 0x49, 0x88, 0x55, 0xdf, 0xa8, 0x0a, 0xce, 0xc2, 0x2a,
-	0xad, 0x56, 0x1b, 0x6f, 0xcb, 0xfa, 0x87, 0x86, 0xa1, 0x1d, 0xd4, 0xb5, 0x1a, 0xad, 0x35, 0xf6,
-	0x35, 0xa3, 0x41, 0x35, 0xbd, 0xac, 0x53, 0x15, 0xe1, 0x79, 0x48, 0x68, 0x86, 0x51, 0x35, 0xd4,
-	0x18, 0xbe, 0x07, 0x77, 0x6a, 0xbb, 0x75, 0x4a, 0x2b, 0xfa, 0xeb, 0xc6, 0x76, 0xf5, 0x9d, 0xae,
-	0xce, 0x94, 0x7e, 0xa1, 0x10, 0xef, 0x1d, 0x2e, 0x46, 0x57, 0xa9, 0x0e, 0xa9, 0x20, 0xdc, 0xe3,
-	0xdc, 0xc6, 0x6b, 0x13, 0xb8, 0xff, 0xbf, 0xaf, 0x99, 0xb5, 0x69, 0xf3, 0x08, 0xb4, 0x39, 0x25,
-	0x8f, 0x36, 0x10, 0xb6, 0x60, 0x25, 0x12, 0x19, 0x7e, 0x3a, 0xe1, 0xbf, 0x6e, 0x28, 0x99, 0xf5,
-	0xdb, 0x48, 0x87, 0x13, 0x28, 0xd9, 0xb0, 0x1c, 0xee, 0x6e, 0xbc, 0x4e, 0xef, 0x61, 0x61, 0x14,
-	0xfb, 0xfd, 0x65, 0x6f, 0xba, 0x5a, 0x99, 0xec, 0x4d, 0x0b, 0x37, 0xec, 0xf0, 0x55, 0xf9, 0xf4,
-	0x9c, 0x28, 0x67, 0xe7, 0x44, 0xb9, 0x3c, 0x27, 0xe8, 0x8b, 0x47, 0xd0, 0x77, 0x8f, 0xa0, 0x1f,
-	0x1e, 0x41, 0xa7, 0x1e, 0x41, 0xbf, 0x3d, 0x82, 0xfe, 0x78, 0x44, 0xb9, 0xf4, 0x08, 0xfa, 0x76,
-	0x41, 0x94, 0xd3, 0x0b, 0xa2, 0x9c, 0x5d, 0x10, 0xe5, 0x63, 0xf8, 0x65, 0x6d, 0x25, 0xfd, 0x87,
-	0xf1, 0xd9, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x67, 0xdc, 0x4e, 0xdc, 0x80, 0x05, 0x00, 0x00,
+	// 690 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xf5, 0xe6, 0xab, 0xed, 0xa4, 0xd0, 0xb0, 0x6d, 0x21, 0x35, 0xc5, 0x8d, 0x2c, 0x04, 0xa1,
+	0x87, 0xa4, 0x0a, 0x07, 0x10, 0xaa, 0x90, 0x42, 0xeb, 0xd2, 0x88, 0xe2, 0xb4, 0x8e, 0x23, 0x3e,
+	0x2e, 0x51, 0x12, 0x6f, 0x93, 0xa8, 0xc5, 0xeb, 0xfa, 0x43, 0x28, 0x37, 0x8e, 0x1c, 0xf9, 0x19,
+	0xfc, 0x14, 0x2e, 0x48, 0x3d, 0x16, 0x89, 0x03, 0x75, 0x2f, 0x1c, 0xfb, 0x13, 0x90, 0xed, 0x75,
+	0x70, 0x5a, 0xa7, 0xed, 0x6d, 0x76, 0xfc, 0xde, 0xee, 0xcc, 0x7b, 0xb3, 0x6b, 0x98, 0xb3, 0xba,
+	0x7d, 0xa2, 0x39, 0x87, 0xc4, 0x2c, 0x19, 0x26, 0xb5, 0x29, 0xce, 0x8e, 0x12, 0x46, 0x87, 0x5f,
+	0xe8, 0xd1, 0x1e, 0xf5, 0xf3, 0x65, 0x2f, 0x0a, 0x20, 0xfc, 0x5a, 0x6f, 0x60, 0xf7, 0x9d, 0x4e,
+	0xa9, 0x4b, 0x3f, 0x95, 0x7b, 0x66, 0x7b, 0xbf, 0xad, 0xb7, 0xcb, 0x9a, 0x75, 0x30, 0xb0, 0xcb,
+	0x7d, 0xdb, 0x36, 0x7a, 0xa6, 0xd1, 0x1d, 0x05, 0x01, 0x43, 0xac, 0x00, 0xde, 0x73, 0x88, 0x39,
+	0x20, 0xa6, 0x4a, 0x1b, 0xe1, 0xfe, 0x78, 0x19, 0x66, 0x8e, 0x82, 0x6c, 0x6d, 0x33, 0x8f, 0x0a,
+	0xa8, 0x38, 0xa3, 0xfc, 0x4f, 0x88, 0x3f, 0x11, 0xe0, 0x11, 0x56, 0xa5, 0x8c, 0x8f, 0xf3, 0x30,
+	0xe5, 0x61, 0x86, 0x8c, 0x92, 0x52, 0xc2, 0x25, 0x7e, 0x06, 0x59, 0xef, 0x58, 0x85, 0x1c, 0x39,
+	0xc4, 0xb2, 0xf3, 0x89, 0x02, 0x2a, 0x66, 0x2b, 0x8b, 0xa5, 0x51, 0x29, 0xdb, 0xaa, 0xba, 0xcb,
+	0x3e, 0x2a, 0x51, 0x24, 0x2e, 0xc2, 0xdc, 0xbe, 0x49, 0x75, 0x9b, 0xe8, 0x5a, 0x55, 0xd3, 0x4c,
+	0x62, 0x59, 0xf9, 0xa4, 0x5f, 0xcd, 0xc5, 0x34, 0xbe, 0x0b, 0x19, 0xc7, 0xf2, 0xcb, 0x4d, 0xf9,
+	0x00, 0xb6, 0xc2, 0x22, 0xcc, 0x5a, 0x76, 0xdb, 0xb6, 0x24, 0xbd, 0xdd, 0x39, 0x24, 0x5a, 0x3e,
+	0x5d, 0x40, 0xc5, 0x69, 0x65, 0x2c, 0x27, 0x7e, 0x4d, 0xc0, 0xfc, 0x16, 0xdb, 0x2f, 0xaa, 0xc2,
+	0x73, 0x48, 0xd9, 0x43, 0x83, 0xf8, 0xdd, 0xdc, 0xae, 0x3c, 0x2c, 0x45, 0xf4, 0x2f, 0xc5, 0xe0,
+	0xd5, 0xa1, 0x41, 0x14, 0x9f, 0x11, 0x57, 0x77, 0x22, 0xbe, 0xee, 0x88, 0x68, 0xc9, 0x71, 0xd1,
+	0x26, 0x75, 0x74, 0x41, 0xcc, 0xf4, 0x8d, 0xc5, 0xbc, 0x28, 0x45, 0x26, 0x46, 0x8a, 0x03, 0x98,
+	0x8f, 0x38, 0x1b, 0x36, 0x89, 0x5f, 0x42, 0xc6, 0x83, 0x39, 0x16, 0xd3, 0xe2, 0xd1, 0x98, 0x16,
+	0x31, 0x8c, 0x86, 0x8f, 0x56, 0x18, 0x0b, 0x2f, 0x40, 0x9a, 0x98, 0x26, 0x35, 0x99, 0x0a, 0xc1,
+	0x42, 0x5c, 0x87, 0x65, 0x99, 0xda, 0x83, 0xfd, 0x21, 0x9b, 0xa0, 0x46, 0xdf, 0xb1, 0x35, 0xfa,
+	0x59, 0x0f, 0x0b, 0xbe, 0x7a, 0x0a, 0x57, 0xe0, 0xc1, 0x04, 0xb6, 0x65, 0x50, 0xdd, 0x22, 0xe2,
+	0x7d, 0x58, 0xda, 0xa0, 0xba, 0x4e, 0xba, 0x36, 0xd1, 0xc2, 0xba, 0x2c, 0xb6, 0xb7, 0xf8, 0x02,
+	0xf8, 0xb8, 0x8f, 0x01, 0xd5, 0x3b, 0xb9, 0x1d, 0x18, 0x44, 0xbc, 0x96, 0x93, 0xde, 0xc9, 0xa3,
+	0xc4, 0xea, 0x3a, 0xdc, 0x9b, 0x60, 0x3f, 0x9e, 0x86, 0x54, 0x4d, 0xae, 0xa9, 0x39, 0x0e, 0x67,
+	0x61, 0x4a, 0x92, 0xf7, 0x9a, 0x52, 0x53, 0xca, 0x21, 0x0c, 0x90, 0xd9, 0xa8, 0xca, 0x1b, 0xd2,
+	0x4e, 0x2e, 0xb1, 0xda, 0x85, 0xa5, 0x89, 0x82, 0xe1, 0x0c, 0x24, 0xea, 0x6f, 0x72, 0x1c, 0x2e,
+	0xc0, 0xb2, 0x5a, 0xaf, 0xb7, 0xde, 0x56, 0xe5, 0x0f, 0x2d, 0x45, 0xda, 0x6b, 0x4a, 0x0d, 0xb5,
+	0xd1, 0xda, 0x95, 0x94, 0x96, 0x2a, 0xc9, 0x55, 0x59, 0xcd, 0x21, 0x3c, 0x03, 0x69, 0x49, 0x51,
+	0xea, 0x4a, 0x2e, 0x81, 0xef, 0xc0, 0xad, 0xc6, 0x76, 0x53, 0x55, 0x6b, 0xf2, 0xeb, 0xd6, 0x66,
+	0xfd, 0x9d, 0x9c, 0x4b, 0x56, 0x7e, 0xa3, 0x88, 0x91, 0x5b, 0xd4, 0x0c, 0xef, 0x68, 0x13, 0xb2,
+	0x2c, 0xdc, 0xa1, 0xd4, 0xc0, 0x2b, 0x63, 0x3e, 0x5e, 0x7e, 0x08, 0xf8, 0x95, 0x49, 0x46, 0x33,
+	0xac, 0xc8, 0x15, 0xd1, 0x1a, 0xc2, 0x3a, 0x2c, 0xc6, 0x7a, 0x81, 0x9f, 0x8c, 0xf1, 0xaf, 0x72,
+	0x9b, 0x5f, 0xbd, 0x09, 0x34, 0xf0, 0xa7, 0xf2, 0x0b, 0xc1, 0x42, 0xb4, 0xbd, 0xd1, 0xa0, 0xbe,
+	0x87, 0xd9, 0x30, 0xf6, 0x1b, 0x2c, 0x5c, 0x77, 0x69, 0xf9, 0xc2, 0x75, 0xa3, 0xcc, 0x5a, 0xec,
+	0x01, 0xbe, 0x3c, 0x30, 0x78, 0xfc, 0x22, 0x4c, 0x1c, 0x37, 0xfe, 0xf1, 0xb5, 0x38, 0x36, 0xb4,
+	0xdc, 0xab, 0xea, 0xf1, 0xa9, 0xc0, 0x9d, 0x9c, 0x0a, 0xdc, 0xf9, 0xa9, 0x80, 0xbe, 0xb8, 0x02,
+	0xfa, 0xee, 0x0a, 0xe8, 0x87, 0x2b, 0xa0, 0x63, 0x57, 0x40, 0x7f, 0x5c, 0x01, 0xfd, 0x75, 0x05,
+	0xee, 0xdc, 0x15, 0xd0, 0xb7, 0x33, 0x81, 0x3b, 0x3e, 0x13, 0xb8, 0x93, 0x33, 0x81, 0xfb, 0x18,
+	0xfd, 0x39, 0x74, 0x32, 0xfe, 0xdb, 0xfe, 0xf4, 0xdf, 0x00, 0x91, 0xda, 0x09, 0xfd, 0x43, 0x06,
+	0x00, 0x00,
 }
 
 func (x FrontendToSchedulerType) String() string {
@@ -666,6 +750,56 @@ func (this *NotifyQuerierShutdownResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *ConnectedFrontendsRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ConnectedFrontendsRequest)
+	if !ok {
+		that2, ok := that.(ConnectedFrontendsRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *ConnectedFrontendsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ConnectedFrontendsResponse)
+	if !ok {
+		that2, ok := that.(ConnectedFrontendsResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Addresses) != len(that1.Addresses) {
+		return false
+	}
+	for i := range this.Addresses {
+		if this.Addresses[i] != that1.Addresses[i] {
+			return false
+		}
+	}
+	return true
+}
 func (this *QuerierToScheduler) GoString() string {
 	if this == nil {
 		return "nil"
@@ -739,6 +873,25 @@ func (this *NotifyQuerierShutdownResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *ConnectedFrontendsRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&schedulerpb.ConnectedFrontendsRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ConnectedFrontendsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&schedulerpb.ConnectedFrontendsResponse{")
+	s = append(s, "Addresses: "+fmt.Sprintf("%#v", this.Addresses)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringScheduler(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -922,6 +1075,8 @@ type SchedulerForFrontendClient interface {
 	// parties... if connection breaks, frontend can cancel (and possibly retry on different scheduler) all pending
 	// requests sent to this scheduler, while scheduler can cancel queued requests from given frontend.
 	FrontendLoop(ctx context.Context, opts ...grpc.CallOption) (SchedulerForFrontend_FrontendLoopClient, error)
+	// Returns the addresses of the query-frontends connected to the scheduler, so that they can find each other.
+	ConnectedFrontends(ctx context.Context, in *ConnectedFrontendsRequest, opts ...grpc.CallOption) (*ConnectedFrontendsResponse, error)
 }
 
 type schedulerForFrontendClient struct {
@@ -963,6 +1118,15 @@ func (x *schedulerForFrontendFrontendLoopClient) Recv() (*SchedulerToFrontend, e
 	return m, nil
 }
 
+func (c *schedulerForFrontendClient) ConnectedFrontends(ctx context.Context, in *ConnectedFrontendsRequest, opts ...grpc.CallOption) (*ConnectedFrontendsResponse, error) {
+	out := new(ConnectedFrontendsResponse)
+	err := c.cc.Invoke(ctx, "/schedulerpb.SchedulerForFrontend/ConnectedFrontends", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SchedulerForFrontendServer is the server API for SchedulerForFrontend service.
 type SchedulerForFrontendServer interface {
 	// After calling this method, both Frontend and Scheduler enter a loop. Frontend will keep sending ENQUEUE and
@@ -972,6 +1136,8 @@ type SchedulerForFrontendServer interface {
 	// parties... if connection breaks, frontend can cancel (and possibly retry on different scheduler) all pending
 	// requests sent to this scheduler, while scheduler can cancel queued requests from given frontend.
 	FrontendLoop(SchedulerForFrontend_FrontendLoopServer) error
+	// Returns the addresses of the query-frontends connected to the scheduler, so that they can find each other.
+	ConnectedFrontends(context.Context, *ConnectedFrontendsRequest) (*ConnectedFrontendsResponse, error)
 }
 
 // UnimplementedSchedulerForFrontendServer can be embedded to have forward compatible implementations.
@@ -981,6 +1147,9 @@ type UnimplementedSchedulerForFrontendServer struct {
 func (*UnimplementedSchedulerForFrontendServer) FrontendLoop(srv SchedulerForFrontend_FrontendLoopServer) error {
 	return status.Errorf(codes.Unimplemented, "method FrontendLoop not implemented")
 }
+func (*UnimplementedSchedulerForFrontendServer) ConnectedFrontends(ctx context.Context, req *ConnectedFrontendsRequest) (*ConnectedFrontendsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConnectedFrontends not implemented")
+}
 
 func RegisterSchedulerForFrontendServer(s *grpc.Server, srv SchedulerForFrontendServer) {
 	s.RegisterService(&_SchedulerForFrontend_serviceDesc, srv)
@@ -1012,10 +1181,33 @@ func (x *schedulerForFrontendFrontendLoopServer) Recv() (*FrontendToScheduler, e
 	return m, nil
 }
 
+func _SchedulerForFrontend_ConnectedFrontends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectedFrontendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerForFrontendServer).ConnectedFrontends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schedulerpb.SchedulerForFrontend/ConnectedFrontends",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerForFrontendServer).ConnectedFrontends(ctx, req.(*ConnectedFrontendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SchedulerForFrontend_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "schedulerpb.SchedulerForFrontend",
 	HandlerType: (*SchedulerForFrontendServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ConnectedFrontends",
+			Handler:    _SchedulerForFrontend_ConnectedFrontends_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "FrontendLoop",
@@ -1278,6 +1470,61 @@ func (m *NotifyQuerierShutdownResponse) MarshalToSizedBuffer(dAtA []byte) (int,
 	return len(dAtA) - i, nil
 }
 
+func (m *ConnectedFrontendsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConnectedFrontendsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ConnectedFrontendsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *ConnectedFrontendsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConnectedFrontendsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ConnectedFrontendsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Addresses) > 0 {
+		for iNdEx := len(m.Addresses) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Addresses[iNdEx])
+			copy(dAtA[i:], m.Addresses[iNdEx])
+			i = encodeVarintScheduler(dAtA, i, uint64(len(m.Addresses[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintScheduler(dAtA []byte, offset int, v uint64) int {
 	offset -= sovScheduler(v)
 	base := offset
@@ -1397,6 +1644,30 @@ func (m *NotifyQuerierShutdownResponse) Size() (n int) {
 	return n
 }
 
+func (m *ConnectedFrontendsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *ConnectedFrontendsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Addresses) > 0 {
+		for _, s := range m.Addresses {
+			l = len(s)
+			n += 1 + l + sovScheduler(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovScheduler(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1472,6 +1743,25 @@ func (this *NotifyQuerierShutdownResponse) String() string {
 	}, "")
 	return s
 }
+func (this *ConnectedFrontendsRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ConnectedFrontendsRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ConnectedFrontendsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ConnectedFrontendsResponse{`,
+		`Addresses:` + fmt.Sprintf("%v", this.Addresses) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringScheduler(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -2210,6 +2500,144 @@ func (m *NotifyQuerierShutdownResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ConnectedFrontendsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowScheduler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConnectedFrontendsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConnectedFrontendsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipScheduler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthScheduler
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthScheduler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConnectedFrontendsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowScheduler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConnectedFrontendsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConnectedFrontendsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Addresses", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowScheduler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthScheduler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthScheduler
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Addresses = append(m.Addresses, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipScheduler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthScheduler
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthScheduler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipScheduler(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
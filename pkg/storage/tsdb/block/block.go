@@ -121,6 +121,18 @@ func Upload(ctx context.Context, logger log.Logger, bkt objstore.Bucket, blockDi
 		return cleanUp(logger, bkt, id, errors.Wrap(err, "upload index"))
 	}
 
+	if meta.HasExemplars() {
+		if err := objstore.UploadFile(ctx, logger, bkt, filepath.Join(blockDir, ExemplarsFilename), path.Join(id.String(), ExemplarsFilename)); err != nil {
+			return cleanUp(logger, bkt, id, errors.Wrap(err, "upload exemplars"))
+		}
+	}
+
+	if meta.HasMetadata() {
+		if err := objstore.UploadFile(ctx, logger, bkt, filepath.Join(blockDir, MetadataFilename), path.Join(id.String(), MetadataFilename)); err != nil {
+			return cleanUp(logger, bkt, id, errors.Wrap(err, "upload metadata"))
+		}
+	}
+
 	// Meta.json always need to be uploaded as a last item. This will allow to assume block directories without meta file to be pending uploads.
 	if err := bkt.Upload(ctx, path.Join(id.String(), MetaFilename), strings.NewReader(metaEncoded.String())); err != nil {
 		// Don't call cleanUp here. Despite getting error, meta.json may have been uploaded in certain cases,
@@ -313,6 +325,19 @@ func GatherFileStats(blockDir string) (res []File, _ error) {
 	}
 	res = append(res, mf)
 
+	// The exemplars and metadata files are optional.
+	for _, name := range []string{ExemplarsFilename, MetadataFilename} {
+		optionalFile, err := os.Stat(filepath.Join(blockDir, name))
+		if err == nil {
+			res = append(res, File{
+				RelPath:   optionalFile.Name(),
+				SizeBytes: optionalFile.Size(),
+			})
+		} else if !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "stat %v", filepath.Join(blockDir, name))
+		}
+	}
+
 	metaFile, err := os.Stat(filepath.Join(blockDir, MetaFilename))
 	if err != nil {
 		return nil, errors.Wrapf(err, "stat %v", filepath.Join(blockDir, MetaFilename))
@@ -0,0 +1,340 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/fileutil"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+const (
+	// ExemplarsFilename is the known filename of the optional file storing the exemplars of a block.
+	ExemplarsFilename = "exemplars"
+
+	// exemplarsMagic is the magic number at the beginning of the exemplars file.
+	exemplarsMagic = 0x45584d50
+	// exemplarsFormatV1 is the only supported version of the exemplars file format.
+	exemplarsFormatV1 = 1
+
+	exemplarsHeaderLen = 5
+)
+
+var errInvalidExemplarsFile = errors.New("invalid exemplars file")
+
+// HasExemplars returns whether the block has an exemplars file, according to the files listed in the meta.
+func (m *Meta) HasExemplars() bool {
+	for _, f := range m.Thanos.Files {
+		if f.RelPath == ExemplarsFilename {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteExemplarsFile writes the exemplars of the input series to the exemplars file of the block in
+// blockDir. The file is written atomically, and the series are sorted by labels before being written.
+func WriteExemplarsFile(blockDir string, series []mimirpb.TimeSeries) error {
+	sortExemplarSeries(series)
+
+	w, err := NewExemplarsWriter(blockDir)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range series {
+		if err := w.Append(s); err != nil {
+			w.Discard()
+			return err
+		}
+	}
+
+	return w.Close()
+}
+
+// ExemplarsWriter writes the exemplars file of a block one series at a time, so that the exemplars
+// of a block don't have to be kept in memory to be written.
+//
+// The file starts with a 4 bytes magic number followed by the format version byte, and then contains
+// a record for each series: the uvarint encoded length of the record followed by the series (labels
+// and exemplars) encoded as a mimirpb.TimeSeries.
+type ExemplarsWriter struct {
+	dst, tmp string
+	f        *os.File
+	w        *bufio.Writer
+	buf      []byte
+}
+
+// NewExemplarsWriter creates a writer of the exemplars file of the block in blockDir. The file is
+// written to a temporary file, which replaces the exemplars file on Close.
+func NewExemplarsWriter(blockDir string) (*ExemplarsWriter, error) {
+	dst := filepath.Join(blockDir, ExemplarsFilename)
+	tmp := dst + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ExemplarsWriter{dst: dst, tmp: tmp, f: f, w: bufio.NewWriter(f)}
+
+	header := make([]byte, exemplarsHeaderLen)
+	binary.BigEndian.PutUint32(header, exemplarsMagic)
+	header[4] = exemplarsFormatV1
+	if _, err := w.w.Write(header); err != nil {
+		w.Discard()
+		return nil, errors.Wrap(err, "write exemplars header")
+	}
+
+	return w, nil
+}
+
+// Append writes the exemplars of the input series. Series must be appended sorted by labels. Series
+// without exemplars are skipped.
+func (w *ExemplarsWriter) Append(series mimirpb.TimeSeries) error {
+	if len(series.Exemplars) == 0 {
+		return nil
+	}
+
+	size := series.Size()
+	w.buf = binary.AppendUvarint(w.buf[:0], uint64(size))
+	n := len(w.buf)
+	w.buf = append(w.buf, make([]byte, size)...)
+	if _, err := series.MarshalToSizedBuffer(w.buf[n:]); err != nil {
+		return errors.Wrap(err, "encode series exemplars")
+	}
+	if _, err := w.w.Write(w.buf); err != nil {
+		return errors.Wrap(err, "write series exemplars")
+	}
+	return nil
+}
+
+// Close flushes the written series and atomically replaces the exemplars file of the block. If Close
+// fails, the temporary file is removed.
+func (w *ExemplarsWriter) Close() (err error) {
+	defer func() {
+		if err != nil {
+			w.Discard()
+		}
+	}()
+
+	if err = w.w.Flush(); err != nil {
+		return errors.Wrap(err, "flush exemplars file")
+	}
+	if err = w.f.Sync(); err != nil {
+		return errors.Wrap(err, "sync exemplars file")
+	}
+	if err = w.f.Close(); err != nil {
+		return errors.Wrap(err, "close exemplars file")
+	}
+
+	return fileutil.Replace(w.tmp, w.dst)
+}
+
+// Discard removes the temporary file, leaving the exemplars file of the block untouched.
+func (w *ExemplarsWriter) Discard() {
+	_ = w.f.Close()
+	_ = os.Remove(w.tmp)
+}
+
+// ExemplarsReader decodes the series of an exemplars file one at a time.
+type ExemplarsReader struct {
+	r *bufio.Reader
+}
+
+// NewExemplarsReader returns a reader of the exemplars file read from r, after having checked its header.
+// Errors returned by r are returned as is, while malformed content is reported as an invalid file.
+func NewExemplarsReader(r io.Reader) (*ExemplarsReader, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, exemplarsHeaderLen)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, exemplarsReadError(err, "read header")
+	}
+	if magic := binary.BigEndian.Uint32(header); magic != exemplarsMagic {
+		return nil, errors.Wrapf(errInvalidExemplarsFile, "invalid magic number %x", magic)
+	}
+	if version := header[4]; version != exemplarsFormatV1 {
+		return nil, errors.Wrapf(errInvalidExemplarsFile, "unsupported version %d", version)
+	}
+
+	return &ExemplarsReader{r: br}, nil
+}
+
+// Next returns the next series of the file, or io.EOF once all the series have been read. Each series
+// is decoded from its own buffer, so it can be retained by the caller.
+func (r *ExemplarsReader) Next() (mimirpb.TimeSeries, error) {
+	size, err := binary.ReadUvarint(r.r)
+	if errors.Is(err, io.EOF) {
+		return mimirpb.TimeSeries{}, io.EOF
+	}
+	if err != nil {
+		return mimirpb.TimeSeries{}, exemplarsReadError(err, "read series length")
+	}
+
+	// The decoded labels reference the buffer, so it can't be reused across series.
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return mimirpb.TimeSeries{}, exemplarsReadError(err, "read series")
+	}
+
+	var series mimirpb.TimeSeries
+	if err := series.Unmarshal(data); err != nil {
+		return mimirpb.TimeSeries{}, errors.Wrap(err, "decode series exemplars")
+	}
+	return series, nil
+}
+
+// exemplarsReadError reports a truncated file as an invalid file, and any other read error as is.
+func exemplarsReadError(err error, msg string) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return errors.Wrap(errInvalidExemplarsFile, msg)
+	}
+	return errors.Wrap(err, msg)
+}
+
+// ReadExemplars decodes the exemplars file from r, calling fn for each series. Each series is decoded
+// from its own buffer, so it can be retained by fn.
+func ReadExemplars(r io.Reader, fn func(series mimirpb.TimeSeries) error) error {
+	er, err := NewExemplarsReader(r)
+	if err != nil {
+		return err
+	}
+
+	for {
+		series, err := er.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(series); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadExemplarsFile reads all the series from the exemplars file of the block in blockDir. If the block
+// has no exemplars file, no series and no error are returned.
+func ReadExemplarsFile(blockDir string) ([]mimirpb.TimeSeries, error) {
+	f, err := os.Open(filepath.Join(blockDir, ExemplarsFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var series []mimirpb.TimeSeries
+	err = ReadExemplars(f, func(s mimirpb.TimeSeries) error {
+		series = append(series, s)
+		return nil
+	})
+	return series, err
+}
+
+// MergeExemplars merges the input sets of series, deduplicating the exemplars of the same series having
+// the same timestamp. The returned series are sorted by labels, and their exemplars by timestamp.
+func MergeExemplars(sets ...[]mimirpb.TimeSeries) []mimirpb.TimeSeries {
+	merged := map[string]*mimirpb.TimeSeries{}
+	var buf []byte
+
+	for _, set := range sets {
+		for _, s := range set {
+			buf = mimirpb.FromLabelAdaptersToLabels(s.Labels).Bytes(buf)
+			if existing, ok := merged[string(buf)]; ok {
+				existing.Exemplars = append(existing.Exemplars, s.Exemplars...)
+				continue
+			}
+
+			merged[string(buf)] = &mimirpb.TimeSeries{
+				Labels:    s.Labels,
+				Exemplars: append([]mimirpb.Exemplar(nil), s.Exemplars...),
+			}
+		}
+	}
+
+	result := make([]mimirpb.TimeSeries, 0, len(merged))
+	for _, s := range merged {
+		s.Exemplars = DedupeExemplars(s.Exemplars)
+		result = append(result, *s)
+	}
+
+	sortExemplarSeries(result)
+	return result
+}
+
+// DedupeExemplars sorts the input exemplars by timestamp and removes the exemplars with the same timestamp,
+// which are the same exemplar ingested by different replicas. The input slice is modified in place.
+func DedupeExemplars(exemplars []mimirpb.Exemplar) []mimirpb.Exemplar {
+	sort.SliceStable(exemplars, func(i, j int) bool {
+		return exemplars[i].TimestampMs < exemplars[j].TimestampMs
+	})
+
+	deduped := exemplars[:0]
+	for i, e := range exemplars {
+		if i > 0 && e.TimestampMs == deduped[len(deduped)-1].TimestampMs {
+			continue
+		}
+		deduped = append(deduped, e)
+	}
+	return deduped
+}
+
+// FilterExemplars returns the series matching at least one of the input sets of matchers, with only the
+// exemplars within the [mint, maxt] time range. Series without exemplars in the range are not returned.
+// If no matchers are given, series are filtered by time only.
+func FilterExemplars(series []mimirpb.TimeSeries, mint, maxt int64, matchers ...[]*labels.Matcher) []mimirpb.TimeSeries {
+	var result []mimirpb.TimeSeries
+
+	for _, s := range series {
+		if len(matchers) > 0 && !matchesAnySet(mimirpb.FromLabelAdaptersToLabels(s.Labels), matchers) {
+			continue
+		}
+
+		var exemplars []mimirpb.Exemplar
+		for _, e := range s.Exemplars {
+			if e.TimestampMs >= mint && e.TimestampMs <= maxt {
+				exemplars = append(exemplars, e)
+			}
+		}
+		if len(exemplars) > 0 {
+			result = append(result, mimirpb.TimeSeries{Labels: s.Labels, Exemplars: exemplars})
+		}
+	}
+
+	return result
+}
+
+func matchesAnySet(lset labels.Labels, sets [][]*labels.Matcher) bool {
+	for _, set := range sets {
+		matches := true
+		for _, m := range set {
+			if !m.Matches(lset.Get(m.Name)) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func sortExemplarSeries(series []mimirpb.TimeSeries) {
+	sort.Slice(series, func(i, j int) bool {
+		return labels.Compare(mimirpb.FromLabelAdaptersToLabels(series[i].Labels), mimirpb.FromLabelAdaptersToLabels(series[j].Labels)) < 0
+	})
+}
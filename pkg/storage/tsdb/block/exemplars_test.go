@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+func exemplarsSeries(lbls labels.Labels, timestamps ...int64) mimirpb.TimeSeries {
+	s := mimirpb.TimeSeries{Labels: mimirpb.FromLabelsToLabelAdapters(lbls)}
+	for _, ts := range timestamps {
+		s.Exemplars = append(s.Exemplars, mimirpb.Exemplar{
+			Labels:      []mimirpb.LabelAdapter{{Name: "trace_id", Value: "trace"}},
+			Value:       float64(ts),
+			TimestampMs: ts,
+		})
+	}
+	return s
+}
+
+func TestWriteAndReadExemplarsFile(t *testing.T) {
+	dir := t.TempDir()
+
+	series := []mimirpb.TimeSeries{
+		exemplarsSeries(labels.FromStrings(labels.MetricName, "b"), 10, 20),
+		exemplarsSeries(labels.FromStrings(labels.MetricName, "a"), 30),
+		exemplarsSeries(labels.FromStrings(labels.MetricName, "c")),
+	}
+	require.NoError(t, WriteExemplarsFile(dir, series))
+
+	// The temporary file should have been renamed.
+	_, err := os.Stat(filepath.Join(dir, ExemplarsFilename+".tmp"))
+	require.True(t, os.IsNotExist(err))
+
+	actual, err := ReadExemplarsFile(dir)
+	require.NoError(t, err)
+
+	// Series are sorted by labels, and series without exemplars are skipped.
+	assert.Equal(t, []mimirpb.TimeSeries{
+		exemplarsSeries(labels.FromStrings(labels.MetricName, "a"), 30),
+		exemplarsSeries(labels.FromStrings(labels.MetricName, "b"), 10, 20),
+	}, actual)
+}
+
+func TestReadExemplarsFile_NoFile(t *testing.T) {
+	actual, err := ReadExemplarsFile(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+}
+
+func TestReadExemplars_InvalidFile(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":               {},
+		"invalid magic":       {0x00, 0x01, 0x02, 0x03, exemplarsFormatV1},
+		"unsupported version": {0x45, 0x58, 0x4d, 0x50, 2},
+		"truncated series":    {0x45, 0x58, 0x4d, 0x50, exemplarsFormatV1, 10, 0x01},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ReadExemplars(bytes.NewReader(data), func(mimirpb.TimeSeries) error { return nil })
+			require.ErrorIs(t, err, errInvalidExemplarsFile)
+		})
+	}
+}
+
+func TestReadExemplars_ReaderError(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, WriteExemplarsFile(dir, []mimirpb.TimeSeries{exemplarsSeries(labels.FromStrings(labels.MetricName, "a"), 10)}))
+	data, err := os.ReadFile(filepath.Join(dir, ExemplarsFilename))
+	require.NoError(t, err)
+
+	// Errors of the underlying reader are returned as is, instead of being reported as an invalid file.
+	readerErr := errors.New("reader error")
+	err = ReadExemplars(io.MultiReader(bytes.NewReader(data[:exemplarsHeaderLen+1]), iotest.ErrReader(readerErr)), func(mimirpb.TimeSeries) error { return nil })
+	require.ErrorIs(t, err, readerErr)
+	require.NotErrorIs(t, err, errInvalidExemplarsFile)
+}
+
+func TestExemplarsWriter_Discard(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := NewExemplarsWriter(dir)
+	require.NoError(t, err)
+	require.NoError(t, w.Append(exemplarsSeries(labels.FromStrings(labels.MetricName, "a"), 10)))
+	w.Discard()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestMergeExemplars(t *testing.T) {
+	a := labels.FromStrings(labels.MetricName, "a")
+	b := labels.FromStrings(labels.MetricName, "b")
+
+	actual := MergeExemplars(
+		[]mimirpb.TimeSeries{exemplarsSeries(b, 10, 30), exemplarsSeries(a, 20)},
+		[]mimirpb.TimeSeries{exemplarsSeries(b, 20, 30, 40)},
+		nil,
+	)
+
+	assert.Equal(t, []mimirpb.TimeSeries{
+		exemplarsSeries(a, 20),
+		exemplarsSeries(b, 10, 20, 30, 40),
+	}, actual)
+}
+
+func TestFilterExemplars(t *testing.T) {
+	series := []mimirpb.TimeSeries{
+		exemplarsSeries(labels.FromStrings(labels.MetricName, "a", "job", "1"), 10, 20, 30),
+		exemplarsSeries(labels.FromStrings(labels.MetricName, "b", "job", "1"), 10),
+		exemplarsSeries(labels.FromStrings(labels.MetricName, "c", "job", "2"), 20),
+	}
+
+	t.Run("time range only", func(t *testing.T) {
+		assert.Equal(t, []mimirpb.TimeSeries{
+			exemplarsSeries(labels.FromStrings(labels.MetricName, "a", "job", "1"), 20, 30),
+			exemplarsSeries(labels.FromStrings(labels.MetricName, "c", "job", "2"), 20),
+		}, FilterExemplars(series, 15, 30))
+	})
+
+	t.Run("matchers sets", func(t *testing.T) {
+		assert.Equal(t, []mimirpb.TimeSeries{
+			exemplarsSeries(labels.FromStrings(labels.MetricName, "b", "job", "1"), 10),
+			exemplarsSeries(labels.FromStrings(labels.MetricName, "c", "job", "2"), 20),
+		}, FilterExemplars(series, 0, 100,
+			[]*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "b")},
+			[]*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "2")},
+		))
+	})
+}
+
+func TestUpload_ShouldUploadExemplarsFile(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	bkt := objstore.NewInMemBucket()
+
+	id, err := CreateBlock(ctx, tmpDir, fiveLabels, 100, 0, 1000, labels.FromStrings("ext1", "val1"))
+	require.NoError(t, err)
+
+	blockDir := filepath.Join(tmpDir, id.String())
+	series := []mimirpb.TimeSeries{exemplarsSeries(labels.FromStrings("a", "1"), 100)}
+	require.NoError(t, WriteExemplarsFile(blockDir, series))
+
+	require.NoError(t, Upload(ctx, log.NewNopLogger(), bkt, blockDir, nil))
+
+	r, err := bkt.Get(ctx, path.Join(id.String(), ExemplarsFilename))
+	require.NoError(t, err)
+	var uploaded []mimirpb.TimeSeries
+	require.NoError(t, ReadExemplars(r, func(s mimirpb.TimeSeries) error {
+		uploaded = append(uploaded, s)
+		return nil
+	}))
+	assert.Equal(t, series, uploaded)
+
+	meta, err := DownloadMeta(ctx, log.NewNopLogger(), bkt, id)
+	require.NoError(t, err)
+	assert.True(t, meta.HasExemplars())
+}
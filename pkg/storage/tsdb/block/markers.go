@@ -72,6 +72,9 @@ const (
 	IndexSizeExceedingNoCompactReason = "index-size-exceeding"
 	// OutOfOrderChunksNoCompactReason is a reason of to no compact block with index contains out of order chunk so that the compaction is not blocked.
 	OutOfOrderChunksNoCompactReason = "block-index-out-of-order-chunk"
+	// DeleteRequestRewriteNoCompactReason is a reason to not compact a block while the compactor rewrites it to
+	// remove the series matching a delete request. The mark is removed once the rewrite is done.
+	DeleteRequestRewriteNoCompactReason = "delete-request-rewrite"
 )
 
 // NoCompactMark marker stores reason of block being excluded from compaction if needed.
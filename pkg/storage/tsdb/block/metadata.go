@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb/fileutil"
+)
+
+const (
+	// MetadataFilename is the known filename of the optional file storing the metric metadata of a block.
+	MetadataFilename = "metadata"
+
+	// MetadataVersion1 is the only supported version of the metadata file format.
+	MetadataVersion1 = 1
+)
+
+var errInvalidMetadataFile = errors.New("invalid metadata file")
+
+// MetricMetadata is a metric metadata (type, help and unit) observed for a metric, along with the
+// time range within which it has been observed.
+type MetricMetadata struct {
+	Metric string `json:"metric"`
+	Type   string `json:"type"`
+	Help   string `json:"help"`
+	Unit   string `json:"unit"`
+
+	// MinTime and MaxTime are the first and last time, in milliseconds, the metadata has been observed.
+	MinTime int64 `json:"min_time"`
+	MaxTime int64 `json:"max_time"`
+}
+
+// sameMetadata returns whether m and o are the same metadata of the same metric, regardless of
+// when they have been observed.
+func (m MetricMetadata) sameMetadata(o MetricMetadata) bool {
+	return m.Metric == o.Metric && m.Type == o.Type && m.Help == o.Help && m.Unit == o.Unit
+}
+
+type metadataFile struct {
+	Version  int              `json:"version"`
+	Metadata []MetricMetadata `json:"metadata"`
+}
+
+// HasMetadata returns whether the block has a metric metadata file, according to the files listed in the meta.
+func (m *Meta) HasMetadata() bool {
+	for _, f := range m.Thanos.Files {
+		if f.RelPath == MetadataFilename {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteMetadataFile writes the input metric metadata to the metadata file of the block in blockDir.
+// The file is written atomically, and the metadata is sorted before being written.
+func WriteMetadataFile(blockDir string, metadata []MetricMetadata) (err error) {
+	sortMetadata(metadata)
+
+	dst := filepath.Join(blockDir, MetadataFilename)
+	tmp := dst + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	enc := json.NewEncoder(f)
+	if err = enc.Encode(metadataFile{Version: MetadataVersion1, Metadata: metadata}); err != nil {
+		return errors.Wrap(err, "encode metadata file")
+	}
+	if err = f.Sync(); err != nil {
+		return errors.Wrap(err, "sync metadata file")
+	}
+	if err = f.Close(); err != nil {
+		return errors.Wrap(err, "close metadata file")
+	}
+
+	return fileutil.Replace(tmp, dst)
+}
+
+// ReadMetadata decodes the metadata file from r.
+func ReadMetadata(r io.Reader) ([]MetricMetadata, error) {
+	var f metadataFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		// Errors of the underlying reader are returned as is, while malformed content is reported as an invalid file.
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+			return nil, errors.Wrapf(errInvalidMetadataFile, "decode: %v", err)
+		}
+		return nil, errors.Wrap(err, "read metadata file")
+	}
+	if f.Version != MetadataVersion1 {
+		return nil, errors.Wrapf(errInvalidMetadataFile, "unsupported version %d", f.Version)
+	}
+	return f.Metadata, nil
+}
+
+// ReadMetadataFile reads the metric metadata from the metadata file of the block in blockDir. If the
+// block has no metadata file, no metadata and no error are returned.
+func ReadMetadataFile(blockDir string) ([]MetricMetadata, error) {
+	f, err := os.Open(filepath.Join(blockDir, MetadataFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return ReadMetadata(f)
+}
+
+// MergeMetadata merges the input sets of metric metadata. The same metadata of the same metric observed
+// in multiple sets is merged into a single entry spanning the time ranges of all the observations. The
+// returned metadata is sorted by metric and then by the time it has been first observed, so that the
+// entries of each metric are the history of its metadata changes.
+func MergeMetadata(sets ...[]MetricMetadata) []MetricMetadata {
+	var all []MetricMetadata
+	for _, set := range sets {
+		all = append(all, set...)
+	}
+	if len(all) == 0 {
+		return nil
+	}
+
+	// Group the same metadata together, so that it can be merged in a single pass.
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Help != b.Help {
+			return a.Help < b.Help
+		}
+		return a.Unit < b.Unit
+	})
+
+	result := make([]MetricMetadata, 0, len(all))
+	for _, m := range all {
+		if n := len(result); n > 0 && result[n-1].sameMetadata(m) {
+			result[n-1].MinTime = min(result[n-1].MinTime, m.MinTime)
+			result[n-1].MaxTime = max(result[n-1].MaxTime, m.MaxTime)
+			continue
+		}
+		result = append(result, m)
+	}
+
+	sortMetadata(result)
+	return result
+}
+
+// FilterMetadata returns the metric metadata observed within the [mint, maxt] time range, with the
+// observation time range clamped to it. If metric is not empty, only the metadata of that metric
+// is returned.
+func FilterMetadata(metadata []MetricMetadata, mint, maxt int64, metric string) []MetricMetadata {
+	var result []MetricMetadata
+	for _, m := range metadata {
+		if metric != "" && m.Metric != metric {
+			continue
+		}
+		if m.MaxTime < mint || m.MinTime > maxt {
+			continue
+		}
+
+		m.MinTime = max(m.MinTime, mint)
+		m.MaxTime = min(m.MaxTime, maxt)
+		result = append(result, m)
+	}
+	return result
+}
+
+func sortMetadata(metadata []MetricMetadata) {
+	sort.SliceStable(metadata, func(i, j int) bool {
+		if metadata[i].Metric != metadata[j].Metric {
+			return metadata[i].Metric < metadata[j].Metric
+		}
+		return metadata[i].MinTime < metadata[j].MinTime
+	})
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+)
+
+func TestWriteAndReadMetadataFile(t *testing.T) {
+	dir := t.TempDir()
+
+	metadata := []MetricMetadata{
+		{Metric: "b", Type: "gauge", Help: "b help", MinTime: 10, MaxTime: 20},
+		{Metric: "a", Type: "counter", Help: "a new help", MinTime: 30, MaxTime: 40},
+		{Metric: "a", Type: "counter", Help: "a help", Unit: "seconds", MinTime: 10, MaxTime: 20},
+	}
+	require.NoError(t, WriteMetadataFile(dir, metadata))
+
+	// The temporary file should have been renamed.
+	_, err := os.Stat(filepath.Join(dir, MetadataFilename+".tmp"))
+	require.True(t, os.IsNotExist(err))
+
+	actual, err := ReadMetadataFile(dir)
+	require.NoError(t, err)
+
+	// Metadata is sorted by metric and time.
+	assert.Equal(t, []MetricMetadata{
+		{Metric: "a", Type: "counter", Help: "a help", Unit: "seconds", MinTime: 10, MaxTime: 20},
+		{Metric: "a", Type: "counter", Help: "a new help", MinTime: 30, MaxTime: 40},
+		{Metric: "b", Type: "gauge", Help: "b help", MinTime: 10, MaxTime: 20},
+	}, actual)
+}
+
+func TestReadMetadataFile_NoFile(t *testing.T) {
+	actual, err := ReadMetadataFile(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+}
+
+func TestReadMetadata_InvalidFile(t *testing.T) {
+	tests := map[string]string{
+		"empty":               ``,
+		"invalid json":        `{"version":1,"metadata":[`,
+		"invalid syntax":      `{"version":1,"metadata":]}`,
+		"invalid type":        `{"version":"1","metadata":[]}`,
+		"unsupported version": `{"version":2,"metadata":[]}`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadMetadata(strings.NewReader(data))
+			require.ErrorIs(t, err, errInvalidMetadataFile)
+		})
+	}
+}
+
+func TestReadMetadata_ReaderError(t *testing.T) {
+	// Errors of the underlying reader are returned as is, instead of being reported as an invalid file.
+	readerErr := errors.New("reader error")
+	_, err := ReadMetadata(io.MultiReader(strings.NewReader(`{"version":1,`), iotest.ErrReader(readerErr)))
+	require.ErrorIs(t, err, readerErr)
+	require.NotErrorIs(t, err, errInvalidMetadataFile)
+}
+
+func TestMergeMetadata(t *testing.T) {
+	actual := MergeMetadata(
+		[]MetricMetadata{
+			{Metric: "b", Type: "gauge", Help: "b help", MinTime: 10, MaxTime: 20},
+			{Metric: "a", Type: "counter", Help: "a help", MinTime: 10, MaxTime: 20},
+		},
+		[]MetricMetadata{
+			{Metric: "a", Type: "counter", Help: "a new help", MinTime: 25, MaxTime: 40},
+			{Metric: "a", Type: "counter", Help: "a help", MinTime: 20, MaxTime: 30},
+		},
+		nil,
+	)
+
+	assert.Equal(t, []MetricMetadata{
+		{Metric: "a", Type: "counter", Help: "a help", MinTime: 10, MaxTime: 30},
+		{Metric: "a", Type: "counter", Help: "a new help", MinTime: 25, MaxTime: 40},
+		{Metric: "b", Type: "gauge", Help: "b help", MinTime: 10, MaxTime: 20},
+	}, actual)
+
+	assert.Empty(t, MergeMetadata(nil, nil))
+}
+
+func TestFilterMetadata(t *testing.T) {
+	metadata := []MetricMetadata{
+		{Metric: "a", Type: "counter", Help: "a help", MinTime: 10, MaxTime: 30},
+		{Metric: "a", Type: "counter", Help: "a new help", MinTime: 35, MaxTime: 40},
+		{Metric: "b", Type: "gauge", Help: "b help", MinTime: 10, MaxTime: 20},
+	}
+
+	t.Run("time range only", func(t *testing.T) {
+		assert.Equal(t, []MetricMetadata{
+			{Metric: "a", Type: "counter", Help: "a help", MinTime: 25, MaxTime: 30},
+			{Metric: "a", Type: "counter", Help: "a new help", MinTime: 35, MaxTime: 38},
+		}, FilterMetadata(metadata, 25, 38, ""))
+	})
+
+	t.Run("metric", func(t *testing.T) {
+		assert.Equal(t, []MetricMetadata{
+			{Metric: "b", Type: "gauge", Help: "b help", MinTime: 10, MaxTime: 20},
+		}, FilterMetadata(metadata, 0, 100, "b"))
+	})
+}
+
+func TestUpload_ShouldUploadMetadataFile(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	bkt := objstore.NewInMemBucket()
+
+	id, err := CreateBlock(ctx, tmpDir, fiveLabels, 100, 0, 1000, labels.FromStrings("ext1", "val1"))
+	require.NoError(t, err)
+
+	blockDir := filepath.Join(tmpDir, id.String())
+	metadata := []MetricMetadata{{Metric: "a", Type: "counter", Help: "a help", MinTime: 0, MaxTime: 1000}}
+	require.NoError(t, WriteMetadataFile(blockDir, metadata))
+
+	require.NoError(t, Upload(ctx, log.NewNopLogger(), bkt, blockDir, nil))
+
+	r, err := bkt.Get(ctx, path.Join(id.String(), MetadataFilename))
+	require.NoError(t, err)
+	uploaded, err := ReadMetadata(r)
+	require.NoError(t, err)
+	assert.Equal(t, metadata, uploaded)
+
+	meta, err := DownloadMeta(ctx, log.NewNopLogger(), bkt, id)
+	require.NoError(t, err)
+	assert.True(t, meta.HasMetadata())
+}
@@ -58,17 +58,21 @@ func (cfg *ChunksCacheConfig) Validate() error {
 type MetadataCacheConfig struct {
 	cache.BackendConfig `yaml:",inline"`
 
-	TenantsListTTL          time.Duration `yaml:"tenants_list_ttl" category:"advanced"`
-	TenantBlocksListTTL     time.Duration `yaml:"tenant_blocks_list_ttl" category:"advanced"`
-	ChunksListTTL           time.Duration `yaml:"chunks_list_ttl" category:"advanced"`
-	MetafileExistsTTL       time.Duration `yaml:"metafile_exists_ttl" category:"advanced"`
-	MetafileDoesntExistTTL  time.Duration `yaml:"metafile_doesnt_exist_ttl" category:"advanced"`
-	MetafileContentTTL      time.Duration `yaml:"metafile_content_ttl" category:"advanced"`
-	MetafileMaxSize         int           `yaml:"metafile_max_size_bytes" category:"advanced"`
-	MetafileAttributesTTL   time.Duration `yaml:"metafile_attributes_ttl" category:"advanced"`
-	BlockIndexAttributesTTL time.Duration `yaml:"block_index_attributes_ttl" category:"advanced"`
-	BucketIndexContentTTL   time.Duration `yaml:"bucket_index_content_ttl" category:"advanced"`
-	BucketIndexMaxSize      int           `yaml:"bucket_index_max_size_bytes" category:"advanced"`
+	TenantsListTTL           time.Duration `yaml:"tenants_list_ttl" category:"advanced"`
+	TenantBlocksListTTL      time.Duration `yaml:"tenant_blocks_list_ttl" category:"advanced"`
+	ChunksListTTL            time.Duration `yaml:"chunks_list_ttl" category:"advanced"`
+	MetafileExistsTTL        time.Duration `yaml:"metafile_exists_ttl" category:"advanced"`
+	MetafileDoesntExistTTL   time.Duration `yaml:"metafile_doesnt_exist_ttl" category:"advanced"`
+	MetafileContentTTL       time.Duration `yaml:"metafile_content_ttl" category:"advanced"`
+	MetafileMaxSize          int           `yaml:"metafile_max_size_bytes" category:"advanced"`
+	MetafileAttributesTTL    time.Duration `yaml:"metafile_attributes_ttl" category:"advanced"`
+	BlockIndexAttributesTTL  time.Duration `yaml:"block_index_attributes_ttl" category:"advanced"`
+	BucketIndexContentTTL    time.Duration `yaml:"bucket_index_content_ttl" category:"advanced"`
+	BucketIndexMaxSize       int           `yaml:"bucket_index_max_size_bytes" category:"advanced"`
+	BlockExemplarsContentTTL time.Duration `yaml:"block_exemplars_content_ttl" category:"experimental"`
+	BlockExemplarsMaxSize    int           `yaml:"block_exemplars_max_size_bytes" category:"experimental"`
+	BlockMetadataContentTTL  time.Duration `yaml:"block_metadata_content_ttl" category:"experimental"`
+	BlockMetadataMaxSize     int           `yaml:"block_metadata_max_size_bytes" category:"experimental"`
 }
 
 func (cfg *MetadataCacheConfig) RegisterFlagsWithPrefix(f *flag.FlagSet, prefix string) {
@@ -88,6 +92,10 @@ func (cfg *MetadataCacheConfig) RegisterFlagsWithPrefix(f *flag.FlagSet, prefix
 	f.DurationVar(&cfg.BlockIndexAttributesTTL, prefix+"block-index-attributes-ttl", 168*time.Hour, "How long to cache attributes of the block index.")
 	f.DurationVar(&cfg.BucketIndexContentTTL, prefix+"bucket-index-content-ttl", 5*time.Minute, "How long to cache content of the bucket index.")
 	f.IntVar(&cfg.BucketIndexMaxSize, prefix+"bucket-index-max-size-bytes", 1*1024*1024, "Maximum size of bucket index content to cache in bytes. Caching will be skipped if the content exceeds this size. This is useful to avoid network round trip for large content if the configured caching backend has an hard limit on cached items size (in this case, you should set this limit to the same limit in the caching backend).")
+	f.DurationVar(&cfg.BlockExemplarsContentTTL, prefix+"block-exemplars-content-ttl", 24*time.Hour, "How long to cache content of the block exemplars file.")
+	f.IntVar(&cfg.BlockExemplarsMaxSize, prefix+"block-exemplars-max-size-bytes", 1*1024*1024, "Maximum size of block exemplars file content to cache in bytes. Caching will be skipped if the content exceeds this size. This is useful to avoid network round trip for large content if the configured caching backend has an hard limit on cached items size (in this case, you should set this limit to the same limit in the caching backend).")
+	f.DurationVar(&cfg.BlockMetadataContentTTL, prefix+"block-metadata-content-ttl", 24*time.Hour, "How long to cache content of the block metric metadata file.")
+	f.IntVar(&cfg.BlockMetadataMaxSize, prefix+"block-metadata-max-size-bytes", 1*1024*1024, "Maximum size of block metric metadata file content to cache in bytes. Caching will be skipped if the content exceeds this size. This is useful to avoid network round trip for large content if the configured caching backend has an hard limit on cached items size (in this case, you should set this limit to the same limit in the caching backend).")
 }
 
 func (cfg *MetadataCacheConfig) Validate() error {
@@ -111,6 +119,8 @@ func CreateCachingBucket(chunksCache cache.Cache, chunksConfig ChunksCacheConfig
 		cfg.CacheAttributes("metafile", metadataCache, isMetaFile, metadataConfig.MetafileAttributesTTL)
 		cfg.CacheAttributes("block-index", metadataCache, isBlockIndexFile, metadataConfig.BlockIndexAttributesTTL)
 		cfg.CacheGet("bucket-index", metadataCache, isBucketIndexFile, metadataConfig.BucketIndexMaxSize, metadataConfig.BucketIndexContentTTL /* do not cache exist / not exist: */, 0, 0)
+		cfg.CacheGet("block-exemplars", metadataCache, isBlockExemplarsFile, metadataConfig.BlockExemplarsMaxSize, metadataConfig.BlockExemplarsContentTTL /* do not cache exist / not exist: */, 0, 0)
+		cfg.CacheGet("block-metadata", metadataCache, isBlockMetadataFile, metadataConfig.BlockMetadataMaxSize, metadataConfig.BlockMetadataContentTTL /* do not cache exist / not exist: */, 0, 0)
 
 		codec := bucketcache.SnappyIterCodec{IterCodec: bucketcache.JSONIterCodec{}}
 		cfg.CacheIter("tenants-iter", metadataCache, isTenantsDir, metadataConfig.TenantsListTTL, codec)
@@ -160,8 +170,20 @@ func isMetaFile(name string) bool {
 }
 
 func isBlockIndexFile(name string) bool {
-	// Ensure the path ends with "<block id>/<index filename>".
-	if !strings.HasSuffix(name, "/"+block.IndexFilename) {
+	return isBlockFile(name, block.IndexFilename)
+}
+
+func isBlockExemplarsFile(name string) bool {
+	return isBlockFile(name, block.ExemplarsFilename)
+}
+
+func isBlockMetadataFile(name string) bool {
+	return isBlockFile(name, block.MetadataFilename)
+}
+
+func isBlockFile(name, filename string) bool {
+	// Ensure the path ends with "<block id>/<filename>".
+	if !strings.HasSuffix(name, "/"+filename) {
 		return false
 	}
 
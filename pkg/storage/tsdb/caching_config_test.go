@@ -39,3 +39,25 @@ func TestIsBlockIndexFile(t *testing.T) {
 	assert.True(t, isBlockIndexFile(fmt.Sprintf("%s/index", blockID.String())))
 	assert.True(t, isBlockIndexFile(fmt.Sprintf("/%s/index", blockID.String())))
 }
+
+func TestIsBlockExemplarsFile(t *testing.T) {
+	blockID := ulid.MustNew(1, nil)
+
+	assert.False(t, isBlockExemplarsFile(""))
+	assert.False(t, isBlockExemplarsFile("/exemplars"))
+	assert.False(t, isBlockExemplarsFile("test/exemplars"))
+	assert.False(t, isBlockExemplarsFile(fmt.Sprintf("%s/index", blockID.String())))
+	assert.True(t, isBlockExemplarsFile(fmt.Sprintf("%s/exemplars", blockID.String())))
+	assert.True(t, isBlockExemplarsFile(fmt.Sprintf("test/%s/exemplars", blockID.String())))
+}
+
+func TestIsBlockMetadataFile(t *testing.T) {
+	blockID := ulid.MustNew(1, nil)
+
+	assert.False(t, isBlockMetadataFile(""))
+	assert.False(t, isBlockMetadataFile("/metadata"))
+	assert.False(t, isBlockMetadataFile("test/metadata"))
+	assert.False(t, isBlockMetadataFile(fmt.Sprintf("%s/exemplars", blockID.String())))
+	assert.True(t, isBlockMetadataFile(fmt.Sprintf("%s/metadata", blockID.String())))
+	assert.True(t, isBlockMetadataFile(fmt.Sprintf("test/%s/metadata", blockID.String())))
+}
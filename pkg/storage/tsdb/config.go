@@ -107,6 +107,11 @@ const (
 	maxBucketSizeBytesFlag      = "blocks-storage.bucket-store.chunk-pool-max-bucket-size-bytes"
 	seriesSelectionStrategyFlag = "blocks-storage.bucket-store.series-selection-strategy"
 	bucketIndexFlagPrefix       = "blocks-storage.bucket-store.bucket-index."
+
+	MaxExemplarsBlocksPerQueryFlag = "blocks-storage.bucket-store.max-exemplars-blocks-per-query"
+	MaxExemplarsBytesPerQueryFlag  = "blocks-storage.bucket-store.max-exemplars-bytes-per-query"
+	MaxMetadataBlocksPerQueryFlag  = "blocks-storage.bucket-store.max-metadata-blocks-per-query"
+	MaxMetadataBytesPerQueryFlag   = "blocks-storage.bucket-store.max-metadata-bytes-per-query"
 )
 
 // Validation errors
@@ -208,6 +213,8 @@ type TSDBConfig struct {
 	FlushBlocksOnShutdown     bool          `yaml:"flush_blocks_on_shutdown" category:"advanced"`
 	CloseIdleTSDBTimeout      time.Duration `yaml:"close_idle_tsdb_timeout" category:"advanced"`
 	MemorySnapshotOnShutdown  bool          `yaml:"memory_snapshot_on_shutdown" category:"experimental"`
+	PersistExemplars          bool          `yaml:"persist_exemplars" category:"experimental"`
+	PersistMetadata           bool          `yaml:"persist_metadata" category:"experimental"`
 	HeadChunksWriteQueueSize  int           `yaml:"head_chunks_write_queue_size" category:"advanced"`
 
 	// Series hash cache.
@@ -293,6 +300,8 @@ func (cfg *TSDBConfig) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.FlushBlocksOnShutdown, "blocks-storage.tsdb.flush-blocks-on-shutdown", false, "True to flush blocks to storage on shutdown. If false, incomplete blocks will be reused after restart.")
 	f.DurationVar(&cfg.CloseIdleTSDBTimeout, "blocks-storage.tsdb.close-idle-tsdb-timeout", 13*time.Hour, "If TSDB has not received any data for this duration, and all blocks from TSDB have been shipped, TSDB is closed and deleted from local disk. If set to positive value, this value should be equal or higher than -querier.query-ingesters-within flag to make sure that TSDB is not closed prematurely, which could cause partial query results. 0 or negative value disables closing of idle TSDB.")
 	f.BoolVar(&cfg.MemorySnapshotOnShutdown, "blocks-storage.tsdb.memory-snapshot-on-shutdown", false, "True to enable snapshotting of in-memory TSDB data on disk when shutting down.")
+	f.BoolVar(&cfg.PersistExemplars, "blocks-storage.tsdb.persist-exemplars", false, "True to persist the exemplars of each block in a file uploaded along with the block, so that the exemplars can be queried from the store-gateways once the block has been shipped.")
+	f.BoolVar(&cfg.PersistMetadata, "blocks-storage.tsdb.persist-metadata", false, "True to keep the history of the metric metadata received by the ingester and persist it in a file uploaded along with each block, so that the metadata can be queried from the store-gateways for any time range.")
 	f.IntVar(&cfg.HeadChunksWriteQueueSize, "blocks-storage.tsdb.head-chunks-write-queue-size", 1000000, headChunksWriteQueueSizeHelp)
 	f.IntVar(&cfg.OutOfOrderCapacityMax, "blocks-storage.tsdb.out-of-order-capacity-max", 32, "Maximum capacity for out of order chunks, in samples between 1 and 255.")
 	f.DurationVar(&cfg.HeadPostingsForMatchersCacheTTL, "blocks-storage.tsdb.head-postings-for-matchers-cache-ttl", tsdb.DefaultPostingsForMatchersCacheTTL, "How long to cache postings for matchers in the Head and OOOHead. 0 disables the cache and just deduplicates the in-flight calls.")
@@ -418,6 +427,12 @@ type BucketStoreConfig struct {
 	SelectionStrategies         struct {
 		WorstCaseSeriesPreference float64 `yaml:"worst_case_series_preference" category:"experimental"`
 	} `yaml:"series_selection_strategies"`
+
+	// Controls the limits of the queries of the exemplars and metric metadata persisted in the blocks.
+	MaxExemplarsBlocksPerQuery int    `yaml:"max_exemplars_blocks_per_query" category:"experimental"`
+	MaxExemplarsBytesPerQuery  uint64 `yaml:"max_exemplars_bytes_per_query" category:"experimental"`
+	MaxMetadataBlocksPerQuery  int    `yaml:"max_metadata_blocks_per_query" category:"experimental"`
+	MaxMetadataBytesPerQuery   uint64 `yaml:"max_metadata_bytes_per_query" category:"experimental"`
 }
 
 const (
@@ -462,6 +477,10 @@ func (cfg *BucketStoreConfig) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.StreamingBatchSize, "blocks-storage.bucket-store.batch-series-size", 5000, "This option controls how many series to fetch per batch. The batch size must be greater than 0.")
 	f.StringVar(&cfg.SeriesSelectionStrategyName, seriesSelectionStrategyFlag, WorstCasePostingsStrategy, "This option controls the strategy to selection of series and deferring application of matchers. A more aggressive strategy will fetch less posting lists at the cost of more series. This is useful when querying large blocks in which many series share the same label name and value. Supported values (most aggressive to least aggressive): "+strings.Join(validSeriesSelectionStrategies, ", ")+".")
 	f.Float64Var(&cfg.SelectionStrategies.WorstCaseSeriesPreference, "blocks-storage.bucket-store.series-selection-strategies.worst-case-series-preference", 0.75, "This option is only used when "+seriesSelectionStrategyFlag+"="+WorstCasePostingsStrategy+". Increasing the series preference results in fetching more series than postings. Must be a positive floating point number.")
+	f.IntVar(&cfg.MaxExemplarsBlocksPerQuery, MaxExemplarsBlocksPerQueryFlag, 1000, "Maximum number of blocks with exemplars that a single exemplars query can read from a store-gateway. 0 to disable the limit.")
+	f.Uint64Var(&cfg.MaxExemplarsBytesPerQuery, MaxExemplarsBytesPerQueryFlag, uint64(512*units.Mebibyte), "Maximum size - in bytes - of the exemplars files that a single exemplars query can read from a store-gateway. 0 to disable the limit.")
+	f.IntVar(&cfg.MaxMetadataBlocksPerQuery, MaxMetadataBlocksPerQueryFlag, 1000, "Maximum number of blocks with metric metadata that a single metric metadata query can read from a store-gateway. 0 to disable the limit.")
+	f.Uint64Var(&cfg.MaxMetadataBytesPerQuery, MaxMetadataBytesPerQueryFlag, uint64(256*units.Mebibyte), "Maximum size - in bytes - of the metric metadata files that a single metric metadata query can read from a store-gateway. 0 to disable the limit.")
 }
 
 // Validate the config.
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tsdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/tsdb/tombstones"
+	"github.com/thanos-io/objstore"
+
+	util_log "github.com/grafana/mimir/pkg/util/log"
+)
+
+// Relative to user-specific prefix.
+const DeleteRequestsPath = "markers/delete-requests"
+
+type DeleteRequestState string
+
+const (
+	// DeleteRequestPending is the state of a delete request whose matching series may still be
+	// stored in some blocks. Matching series are filtered out at query time.
+	DeleteRequestPending DeleteRequestState = "pending"
+
+	// DeleteRequestProcessed is the state of a delete request for which the compactor has
+	// rewritten all the blocks containing matching series.
+	DeleteRequestProcessed DeleteRequestState = "processed"
+)
+
+// DeleteRequest is a request to delete all samples of the series matching any of the selectors,
+// within the StartTime and EndTime range (both included).
+type DeleteRequest struct {
+	RequestID string             `json:"request_id"`
+	Selectors []string           `json:"selectors"`
+	StartTime int64              `json:"start_time"` // Milliseconds.
+	EndTime   int64              `json:"end_time"`   // Milliseconds.
+	State     DeleteRequestState `json:"state"`
+
+	// Unix timestamp when the delete request was created.
+	CreationTime int64 `json:"creation_time"`
+
+	// Unix timestamp when the delete request was processed.
+	ProcessedTime int64 `json:"processed_time,omitempty"`
+
+	// ProcessedBlocks contains the IDs of the blocks which don't contain any series matching the
+	// request: the new blocks resulting from the rewrite by the compactor, and the blocks which didn't
+	// contain any matching series. The blocks compacted from them are checked again.
+	ProcessedBlocks []ulid.ULID `json:"processed_blocks,omitempty"`
+}
+
+// NewDeleteRequest returns a new pending delete request, validating the input selectors and time range.
+func NewDeleteRequest(selectors []string, startTime, endTime int64, now time.Time) (*DeleteRequest, error) {
+	if len(selectors) == 0 {
+		return nil, errors.New("at least one series selector is required")
+	}
+	for _, s := range selectors {
+		if _, err := parser.ParseMetricSelector(s); err != nil {
+			return nil, errors.Wrapf(err, "invalid series selector %q", s)
+		}
+	}
+	if startTime > endTime {
+		return nil, fmt.Errorf("the start time %d is after the end time %d", startTime, endTime)
+	}
+	if endTime > now.UnixMilli() {
+		return nil, fmt.Errorf("the end time %d is in the future", endTime)
+	}
+
+	return &DeleteRequest{
+		RequestID:    ulid.MustNew(ulid.Timestamp(now), rand.Reader).String(),
+		Selectors:    selectors,
+		StartTime:    startTime,
+		EndTime:      endTime,
+		State:        DeleteRequestPending,
+		CreationTime: now.Unix(),
+	}, nil
+}
+
+// Matchers returns the parsed matchers of each selector.
+func (r *DeleteRequest) Matchers() ([][]*labels.Matcher, error) {
+	res := make([][]*labels.Matcher, 0, len(r.Selectors))
+	for _, s := range r.Selectors {
+		ms, err := parser.ParseMetricSelector(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid series selector %q", s)
+		}
+		res = append(res, ms)
+	}
+	return res, nil
+}
+
+// IsBlockProcessed returns whether the given block has already been processed for this request.
+func (r *DeleteRequest) IsBlockProcessed(id ulid.ULID) bool {
+	for _, processed := range r.ProcessedBlocks {
+		if processed == id {
+			return true
+		}
+	}
+	return false
+}
+
+func deleteRequestPath(requestID string) string {
+	return path.Join(DeleteRequestsPath, requestID+".json")
+}
+
+// WriteDeleteRequest uploads the delete request to the tenant location in the bucket. The input
+// bucket client is expected to be the user bucket client.
+func WriteDeleteRequest(ctx context.Context, userBkt objstore.Bucket, req *DeleteRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "serialize delete request")
+	}
+
+	return errors.Wrap(userBkt.Upload(ctx, deleteRequestPath(req.RequestID), bytes.NewReader(data)), "upload delete request")
+}
+
+// DeleteDeleteRequest removes the delete request from the tenant location in the bucket. The input
+// bucket client is expected to be the user bucket client.
+func DeleteDeleteRequest(ctx context.Context, userBkt objstore.Bucket, requestID string) error {
+	return errors.Wrap(userBkt.Delete(ctx, deleteRequestPath(requestID)), "delete delete request")
+}
+
+// ReadDeleteRequest returns the delete request with the given ID. If it doesn't exist, returns nil request,
+// and no error. The input bucket client is expected to be the user bucket client.
+func ReadDeleteRequest(ctx context.Context, userBkt objstore.BucketReader, requestID string) (*DeleteRequest, error) {
+	return readDeleteRequest(ctx, userBkt, deleteRequestPath(requestID))
+}
+
+// ReadDeleteRequests returns all the delete requests of the tenant, sorted by creation time. The input
+// bucket client is expected to be the user bucket client.
+func ReadDeleteRequests(ctx context.Context, userBkt objstore.BucketReader) ([]*DeleteRequest, error) {
+	var requests []*DeleteRequest
+
+	err := userBkt.Iter(ctx, DeleteRequestsPath+"/", func(name string) error {
+		if !strings.HasSuffix(name, ".json") {
+			return nil
+		}
+
+		req, err := readDeleteRequest(ctx, userBkt, name)
+		if err != nil {
+			return err
+		}
+		if req != nil {
+			requests = append(requests, req)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "list delete requests")
+	}
+
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].CreationTime < requests[j].CreationTime
+	})
+	return requests, nil
+}
+
+func readDeleteRequest(ctx context.Context, bkt objstore.BucketReader, name string) (*DeleteRequest, error) {
+	r, err := bkt.Get(ctx, name)
+	if err != nil {
+		if bkt.IsObjNotFoundErr(err) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "failed to read delete request object: %s", name)
+	}
+
+	req := &DeleteRequest{}
+	err = json.NewDecoder(r).Decode(req)
+
+	// Close reader before dealing with decode error.
+	if closeErr := r.Close(); closeErr != nil {
+		level.Warn(util_log.Logger).Log("msg", "failed to close bucket reader", "err", closeErr)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode delete request object: %s", name)
+	}
+
+	return req, nil
+}
+
+// Tombstones holds the parsed delete requests of a tenant, and is used to filter out
+// deleted samples at query time.
+type Tombstones struct {
+	stones []tombstone
+}
+
+type tombstone struct {
+	matchers [][]*labels.Matcher
+	interval tombstones.Interval
+}
+
+// NewTombstones builds the tombstones from the input delete requests.
+func NewTombstones(requests []*DeleteRequest) (*Tombstones, error) {
+	t := &Tombstones{}
+	for _, req := range requests {
+		matchers, err := req.Matchers()
+		if err != nil {
+			return nil, err
+		}
+		t.stones = append(t.stones, tombstone{
+			matchers: matchers,
+			interval: tombstones.Interval{Mint: req.StartTime, Maxt: req.EndTime},
+		})
+	}
+	return t, nil
+}
+
+// Empty returns true if there are no tombstones.
+func (t *Tombstones) Empty() bool {
+	return t == nil || len(t.stones) == 0
+}
+
+// Intervals returns the deleted intervals for the series with the given labels, if any.
+func (t *Tombstones) Intervals(lset labels.Labels) tombstones.Intervals {
+	if t.Empty() {
+		return nil
+	}
+
+	var res tombstones.Intervals
+	for _, s := range t.stones {
+		for _, ms := range s.matchers {
+			if matchesAll(ms, lset) {
+				res = res.Add(s.interval)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// Overlaps returns true if any of the tombstones overlaps with the given time range.
+func (t *Tombstones) Overlaps(mint, maxt int64) bool {
+	if t.Empty() {
+		return false
+	}
+	for _, s := range t.stones {
+		if s.interval.Mint <= maxt && mint <= s.interval.Maxt {
+			return true
+		}
+	}
+	return false
+}
+
+// Selectors returns the series selectors of the tombstones overlapping with the given time range.
+func (t *Tombstones) Selectors(mint, maxt int64) [][]*labels.Matcher {
+	if t.Empty() {
+		return nil
+	}
+	var res [][]*labels.Matcher
+	for _, s := range t.stones {
+		if s.interval.Mint <= maxt && mint <= s.interval.Maxt {
+			res = append(res, s.matchers...)
+		}
+	}
+	return res
+}
+
+func matchesAll(ms []*labels.Matcher, lset labels.Labels) bool {
+	for _, m := range ms {
+		if !m.Matches(lset.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tsdb
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/pkg/errors"
+	"github.com/thanos-io/objstore"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+)
+
+// DeleteRequestsLoader lazily loads the pending delete requests of the tenants and, once loaded for the
+// first time, keeps them updated in background. The delete requests of a tenant are offloaded once they
+// haven't been requested for the idle timeout. Processed delete requests never change, so they're read
+// from the bucket only once.
+type DeleteRequestsLoader struct {
+	services.Service
+
+	bkt             objstore.Bucket
+	refreshInterval time.Duration
+	idleTimeout     time.Duration
+	logger          log.Logger
+
+	mtx     sync.RWMutex
+	entries map[string]*deleteRequestsEntry
+}
+
+type deleteRequestsEntry struct {
+	tombstones *Tombstones
+
+	// requests are the delete requests read from the bucket, by object name.
+	requests map[string]*DeleteRequest
+
+	// generation identifies the set of pending delete requests, and changedAt is
+	// when the loader has observed it for the first time.
+	generation string
+	changedAt  time.Time
+
+	// Unix timestamp (seconds) of the last time the delete requests have been requested.
+	requestedAt atomic.Int64
+}
+
+// NewDeleteRequestsLoader makes a new DeleteRequestsLoader.
+func NewDeleteRequestsLoader(bkt objstore.Bucket, refreshInterval, idleTimeout time.Duration, logger log.Logger) *DeleteRequestsLoader {
+	l := &DeleteRequestsLoader{
+		bkt:             bkt,
+		refreshInterval: refreshInterval,
+		idleTimeout:     idleTimeout,
+		logger:          logger,
+		entries:         map[string]*deleteRequestsEntry{},
+	}
+	l.Service = services.NewTimerService(refreshInterval, nil, l.refresh, nil)
+	return l
+}
+
+// Tombstones returns the tombstones of the pending delete requests of the tenant. Only the first call
+// for a tenant reads the delete requests from the bucket: the next ones return the tombstones kept
+// updated in background.
+func (l *DeleteRequestsLoader) Tombstones(ctx context.Context, userID string) (*Tombstones, error) {
+	entry, err := l.getEntry(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return entry.tombstones, nil
+}
+
+// ResultsCacheGeneration returns the generation of the pending delete requests of the tenant, which
+// changes whenever a delete request is created, canceled or processed. The generation is empty when
+// the tenant has no pending delete requests. The returned propagated flag is false when the generation
+// has changed within the last refresh interval, because the other components may not have loaded it yet.
+func (l *DeleteRequestsLoader) ResultsCacheGeneration(ctx context.Context, userID string) (generation string, propagated bool, err error) {
+	entry, err := l.getEntry(ctx, userID)
+	if err != nil {
+		return "", false, err
+	}
+	return entry.generation, time.Since(entry.changedAt) >= l.refreshInterval, nil
+}
+
+func (l *DeleteRequestsLoader) getEntry(ctx context.Context, userID string) (*deleteRequestsEntry, error) {
+	l.mtx.RLock()
+	entry := l.entries[userID]
+	l.mtx.RUnlock()
+
+	if entry != nil {
+		entry.requestedAt.Store(time.Now().Unix())
+		return entry, nil
+	}
+
+	entry, err := l.load(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The pending delete requests loaded for the first time may not have been loaded by the other
+	// components yet, so they're considered propagated only once the refresh interval has elapsed.
+	if entry.generation == "" {
+		entry.changedAt = time.Time{}
+	}
+	entry.requestedAt.Store(time.Now().Unix())
+
+	l.mtx.Lock()
+	// Not an issue if, due to concurrency, other delete requests were already loaded.
+	l.entries[userID] = entry
+	l.mtx.Unlock()
+
+	return entry, nil
+}
+
+// load reads the delete requests of the tenant. The processed delete requests found in the previously
+// loaded ones are not read again.
+func (l *DeleteRequestsLoader) load(ctx context.Context, userID string, previous map[string]*DeleteRequest) (*deleteRequestsEntry, error) {
+	requests := map[string]*DeleteRequest{}
+	userBkt := bucket.NewUserBucketClient(userID, l.bkt, nil)
+
+	err := userBkt.Iter(ctx, DeleteRequestsPath+"/", func(name string) error {
+		if !strings.HasSuffix(name, ".json") {
+			return nil
+		}
+
+		if req := previous[name]; req != nil && req.State == DeleteRequestProcessed {
+			requests[name] = req
+			return nil
+		}
+
+		req, err := readDeleteRequest(ctx, userBkt, name)
+		if err != nil {
+			return err
+		}
+		if req != nil {
+			requests[name] = req
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "list delete requests")
+	}
+
+	// Once a delete request has been processed, the compactor has removed the matching samples from the
+	// blocks, so they don't need to be filtered out anymore. Ingesters never apply the delete requests:
+	// a delete request is processed only after a grace period longer than the time range queried from the
+	// ingesters, so queriers don't read the deleted samples from the ingesters anymore.
+	var pending []*DeleteRequest
+	for _, req := range requests {
+		if req.State == DeleteRequestPending {
+			pending = append(pending, req)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestID < pending[j].RequestID
+	})
+
+	stones, err := NewTombstones(pending)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &deleteRequestsEntry{tombstones: stones, requests: requests, changedAt: time.Now()}
+	if len(pending) > 0 {
+		hasher := fnv.New64a()
+		for _, req := range pending {
+			_, _ = hasher.Write([]byte(req.RequestID))
+			_, _ = hasher.Write([]byte{','})
+		}
+		entry.generation = strconv.FormatUint(hasher.Sum64(), 16)
+	}
+	return entry, nil
+}
+
+func (l *DeleteRequestsLoader) refresh(ctx context.Context) error {
+	l.mtx.RLock()
+	entries := make(map[string]*deleteRequestsEntry, len(l.entries))
+	for userID, entry := range l.entries {
+		entries[userID] = entry
+	}
+	l.mtx.RUnlock()
+
+	idleDeadline := time.Now().Add(-l.idleTimeout).Unix()
+
+	for userID, entry := range entries {
+		if entry.requestedAt.Load() < idleDeadline {
+			l.mtx.Lock()
+			delete(l.entries, userID)
+			l.mtx.Unlock()
+
+			level.Debug(l.logger).Log("msg", "offloaded delete requests of idle tenant", "user", userID)
+			continue
+		}
+
+		updated, err := l.load(ctx, userID, entry.requests)
+		if err != nil {
+			// Keep the previously loaded delete requests.
+			level.Warn(l.logger).Log("msg", "failed to refresh delete requests, using previously loaded ones", "user", userID, "err", err)
+			continue
+		}
+		if updated.generation == entry.generation {
+			updated.changedAt = entry.changedAt
+		}
+		updated.requestedAt.Store(entry.requestedAt.Load())
+
+		l.mtx.Lock()
+		l.entries[userID] = updated
+		l.mtx.Unlock()
+	}
+
+	// Never return an error, otherwise the service terminates.
+	return nil
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tsdb
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+)
+
+func TestDeleteRequestsLoader(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	bkt := &bucket.ErrorInjectedBucketClient{Bucket: objstore.NewInMemBucket()}
+	userBkt := bucket.NewUserBucketClient(userID, bkt, nil)
+	loader := NewDeleteRequestsLoader(bkt, time.Minute, time.Hour, log.NewNopLogger())
+
+	// Without pending delete requests, the generation is empty and propagated.
+	stones, err := loader.Tombstones(ctx, userID)
+	require.NoError(t, err)
+	require.True(t, stones.Empty())
+	generation, propagated, err := loader.ResultsCacheGeneration(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, generation)
+	assert.True(t, propagated)
+
+	// The delete requests created after the first load are loaded in background.
+	req, err := NewDeleteRequest([]string{`{job="a"}`}, 0, 10, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, WriteDeleteRequest(ctx, userBkt, req))
+
+	stones, err = loader.Tombstones(ctx, userID)
+	require.NoError(t, err)
+	require.True(t, stones.Empty())
+
+	require.NoError(t, loader.refresh(ctx))
+
+	stones, err = loader.Tombstones(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, stones.Intervals(labels.FromStrings("job", "a")), 1)
+	generation, propagated, err = loader.ResultsCacheGeneration(ctx, userID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, generation)
+	assert.False(t, propagated)
+
+	// The generation doesn't change until the pending delete requests change.
+	require.NoError(t, loader.refresh(ctx))
+	sameGeneration, _, err := loader.ResultsCacheGeneration(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, generation, sameGeneration)
+
+	// Processed delete requests are not loaded anymore.
+	req.State = DeleteRequestProcessed
+	require.NoError(t, WriteDeleteRequest(ctx, userBkt, req))
+	require.NoError(t, loader.refresh(ctx))
+
+	stones, err = loader.Tombstones(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, stones.Empty())
+	generation, propagated, err = loader.ResultsCacheGeneration(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, generation)
+	assert.False(t, propagated)
+}
+
+func TestDeleteRequestsLoader_ShouldReturnPreviouslyLoadedTombstonesOnError(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	bkt := &bucket.ErrorInjectedBucketClient{Bucket: objstore.NewInMemBucket()}
+	userBkt := bucket.NewUserBucketClient(userID, bkt, nil)
+
+	req, err := NewDeleteRequest([]string{`{job="a"}`}, 0, 10, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, WriteDeleteRequest(ctx, userBkt, req))
+
+	loader := NewDeleteRequestsLoader(bkt, time.Minute, time.Hour, log.NewNopLogger())
+	stones, err := loader.Tombstones(ctx, userID)
+	require.NoError(t, err)
+	require.False(t, stones.Empty())
+
+	bkt.Injector = func(bucket.Operation, string) error { return assert.AnError }
+	require.NoError(t, loader.refresh(ctx))
+
+	stones, err = loader.Tombstones(ctx, userID)
+	require.NoError(t, err)
+	require.False(t, stones.Empty())
+
+	_, err = loader.Tombstones(ctx, "another-user")
+	require.Error(t, err)
+}
+
+func TestDeleteRequestsLoader_ShouldOffloadIdleTenants(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	loader := NewDeleteRequestsLoader(objstore.NewInMemBucket(), time.Minute, time.Hour, log.NewNopLogger())
+
+	_, err := loader.Tombstones(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, loader.entries, 1)
+
+	loader.entries[userID].requestedAt.Store(time.Now().Add(-2 * time.Hour).Unix())
+	require.NoError(t, loader.refresh(ctx))
+	require.Empty(t, loader.entries)
+}
+
+func TestDeleteRequestsLoader_ShouldNotReadProcessedDeleteRequestsAgain(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	bkt := &bucket.ErrorInjectedBucketClient{Bucket: objstore.NewInMemBucket()}
+	userBkt := bucket.NewUserBucketClient(userID, bkt, nil)
+
+	pending, err := NewDeleteRequest([]string{`{job="a"}`}, 0, 10, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, WriteDeleteRequest(ctx, userBkt, pending))
+
+	processed, err := NewDeleteRequest([]string{`{job="b"}`}, 0, 10, time.Now())
+	require.NoError(t, err)
+	processed.State = DeleteRequestProcessed
+	require.NoError(t, WriteDeleteRequest(ctx, userBkt, processed))
+
+	loader := NewDeleteRequestsLoader(bkt, time.Minute, time.Hour, log.NewNopLogger())
+	_, err = loader.Tombstones(ctx, userID)
+	require.NoError(t, err)
+
+	var reads []string
+	bkt.Injector = func(op bucket.Operation, name string) error {
+		if op == bucket.OpGet {
+			reads = append(reads, name)
+		}
+		return nil
+	}
+	require.NoError(t, loader.refresh(ctx))
+
+	// Only the pending delete request is read again.
+	assert.Equal(t, []string{path.Join(userID, deleteRequestPath(pending.RequestID))}, reads)
+
+	stones, err := loader.Tombstones(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, stones.Intervals(labels.FromStrings("job", "a")), 1)
+	assert.Empty(t, stones.Intervals(labels.FromStrings("job", "b")))
+
+	// The delete requests removed from the bucket are not kept.
+	require.NoError(t, DeleteDeleteRequest(ctx, userBkt, processed.RequestID))
+	require.NoError(t, loader.refresh(ctx))
+	assert.Len(t, loader.entries[userID].requests, 1)
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tsdb
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/tombstones"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+)
+
+func TestNewDeleteRequest(t *testing.T) {
+	now := time.Now()
+
+	for name, tc := range map[string]struct {
+		selectors   []string
+		start, end  int64
+		expectedErr string
+	}{
+		"valid request": {
+			selectors: []string{`{job="test"}`, `up`},
+			start:     0,
+			end:       now.UnixMilli(),
+		},
+		"no selectors": {
+			end:         now.UnixMilli(),
+			expectedErr: "at least one series selector is required",
+		},
+		"invalid selector": {
+			selectors:   []string{`{job=}`},
+			end:         now.UnixMilli(),
+			expectedErr: "invalid series selector",
+		},
+		"start after end": {
+			selectors:   []string{`up`},
+			start:       10,
+			end:         5,
+			expectedErr: "the start time 10 is after the end time 5",
+		},
+		"end in the future": {
+			selectors:   []string{`up`},
+			end:         now.Add(time.Hour).UnixMilli(),
+			expectedErr: "is in the future",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, err := NewDeleteRequest(tc.selectors, tc.start, tc.end, now)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, DeleteRequestPending, req.State)
+			assert.Equal(t, now.Unix(), req.CreationTime)
+			_, err = ulid.Parse(req.RequestID)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWriteAndReadDeleteRequests(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	bkt := objstore.NewInMemBucket()
+	userBkt := bucket.NewUserBucketClient(userID, bkt, nil)
+
+	requests, err := ReadDeleteRequests(ctx, userBkt)
+	require.NoError(t, err)
+	require.Empty(t, requests)
+
+	first, err := NewDeleteRequest([]string{`{job="first"}`}, 0, 10, time.Unix(100, 0))
+	require.NoError(t, err)
+	second, err := NewDeleteRequest([]string{`{job="second"}`}, 0, 10, time.Unix(200, 0))
+	require.NoError(t, err)
+
+	require.NoError(t, WriteDeleteRequest(ctx, userBkt, second))
+	require.NoError(t, WriteDeleteRequest(ctx, userBkt, first))
+
+	requests, err = ReadDeleteRequests(ctx, userBkt)
+	require.NoError(t, err)
+	require.Equal(t, []*DeleteRequest{first, second}, requests)
+
+	// The delete requests are stored in the tenant location.
+	exists, err := bkt.Exists(ctx, path.Join(userID, DeleteRequestsPath, first.RequestID+".json"))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	req, err := ReadDeleteRequest(ctx, userBkt, first.RequestID)
+	require.NoError(t, err)
+	require.Equal(t, first, req)
+
+	require.NoError(t, DeleteDeleteRequest(ctx, userBkt, first.RequestID))
+
+	req, err = ReadDeleteRequest(ctx, userBkt, first.RequestID)
+	require.NoError(t, err)
+	require.Nil(t, req)
+
+	requests, err = ReadDeleteRequests(ctx, userBkt)
+	require.NoError(t, err)
+	require.Equal(t, []*DeleteRequest{second}, requests)
+}
+
+func TestDeleteRequest_IsBlockProcessed(t *testing.T) {
+	first := ulid.MustNew(1, nil)
+	second := ulid.MustNew(2, nil)
+	req := &DeleteRequest{ProcessedBlocks: []ulid.ULID{first}}
+
+	assert.True(t, req.IsBlockProcessed(first))
+	assert.False(t, req.IsBlockProcessed(second))
+}
+
+func TestTombstones(t *testing.T) {
+	stones, err := NewTombstones([]*DeleteRequest{
+		{Selectors: []string{`{job="a"}`, `{job="b", instance="1"}`}, StartTime: 10, EndTime: 20},
+		{Selectors: []string{`{job="a"}`}, StartTime: 15, EndTime: 30},
+	})
+	require.NoError(t, err)
+	require.False(t, stones.Empty())
+
+	assert.Equal(t, tombstones.Intervals{{Mint: 10, Maxt: 30}}, stones.Intervals(labels.FromStrings("job", "a")))
+	assert.Equal(t, tombstones.Intervals{{Mint: 10, Maxt: 20}}, stones.Intervals(labels.FromStrings("job", "b", "instance", "1")))
+	assert.Empty(t, stones.Intervals(labels.FromStrings("job", "b", "instance", "2")))
+	assert.Empty(t, stones.Intervals(labels.FromStrings("job", "c")))
+
+	assert.True(t, stones.Overlaps(0, 10))
+	assert.True(t, stones.Overlaps(25, 40))
+	assert.False(t, stones.Overlaps(31, 40))
+
+	var empty *Tombstones
+	assert.True(t, empty.Empty())
+	assert.Empty(t, empty.Intervals(labels.FromStrings("job", "a")))
+}
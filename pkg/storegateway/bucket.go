@@ -27,6 +27,7 @@ import (
 	"github.com/oklog/ulid"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb/chunks"
@@ -113,6 +114,10 @@ type BucketStore struct {
 	seriesLimiterFactory SeriesLimiterFactory
 	partitioners         blockPartitioners
 
+	// Limits of the blocks and bytes read by each Exemplars() and MetricMetadata() call.
+	exemplarsLimits sidecarFileLimits
+	metadataLimits  sidecarFileLimits
+
 	// Every how many posting offset entry we pool in heap memory. Default in Prometheus is 32.
 	postingOffsetsInMemSampling int
 
@@ -228,6 +233,22 @@ func NewBucketStore(
 		userID:                      userID,
 		maxSeriesPerBatch:           bucketStoreConfig.StreamingBatchSize,
 		postingsStrategy:            postingsStrategy,
+		exemplarsLimits: sidecarFileLimits{
+			maxBlocks:       uint64(bucketStoreConfig.MaxExemplarsBlocksPerQuery),
+			maxBytes:        bucketStoreConfig.MaxExemplarsBytesPerQuery,
+			blocksMsgFormat: maxExemplarsBlocksPerQueryMsgFormat,
+			bytesMsgFormat:  maxExemplarsBytesPerQueryMsgFormat,
+			blocksDropped:   metrics.queriesDropped.WithLabelValues("exemplars_blocks"),
+			bytesDropped:    metrics.queriesDropped.WithLabelValues("exemplars_bytes"),
+		},
+		metadataLimits: sidecarFileLimits{
+			maxBlocks:       uint64(bucketStoreConfig.MaxMetadataBlocksPerQuery),
+			maxBytes:        bucketStoreConfig.MaxMetadataBytesPerQuery,
+			blocksMsgFormat: maxMetadataBlocksPerQueryMsgFormat,
+			bytesMsgFormat:  maxMetadataBytesPerQueryMsgFormat,
+			blocksDropped:   metrics.queriesDropped.WithLabelValues("metadata_blocks"),
+			bytesDropped:    metrics.queriesDropped.WithLabelValues("metadata_bytes"),
+		},
 	}
 
 	for _, option := range options {
@@ -1560,6 +1581,219 @@ func (s *BucketStore) LabelValues(ctx context.Context, req *storepb.LabelValuesR
 	}, nil
 }
 
+// Exemplars returns the exemplars persisted in the blocks for the requested time range and sets of matchers.
+// Blocks without an exemplars file are reported as queried, because they have no exemplars to return.
+func (s *BucketStore) Exemplars(ctx context.Context, req *storepb.ExemplarsRequest) (*storepb.ExemplarsResponse, error) {
+	reqSeriesMatchers := make([][]*labels.Matcher, 0, len(req.Matchers))
+	for _, set := range req.Matchers {
+		matchers, err := storepb.MatchersToPromMatchers(set.Matchers...)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "translate request labels matchers").Error())
+		}
+		reqSeriesMatchers = append(reqSeriesMatchers, matchers)
+	}
+
+	resHints := &hintspb.ExemplarsResponseHints{}
+
+	var reqBlockMatchers []*labels.Matcher
+	if req.Hints != nil {
+		reqHints := &hintspb.ExemplarsRequestHints{}
+		err := types.UnmarshalAny(req.Hints, reqHints)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "unmarshal exemplars request hints").Error())
+		}
+
+		reqBlockMatchers, err = storepb.MatchersToPromMatchers(reqHints.BlockMatchers...)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "translate request hints labels matchers").Error())
+		}
+	}
+
+	seriesLimiter := s.seriesLimiterFactory(s.metrics.queriesDropped.WithLabelValues("series"))
+	blocks := s.blocksWithSidecarFile(req.Start, req.End, reqBlockMatchers, (*block.Meta).HasExemplars, resHints.AddQueriedBlock)
+
+	sets, err := readBlocksSidecarFile(ctx, blocks, block.ExemplarsFilename, s.exemplarsLimits, func(r io.Reader) ([]mimirpb.TimeSeries, error) {
+		return readBlockExemplars(r, req.Start, req.End, reqSeriesMatchers, seriesLimiter)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	anyHints, err := types.MarshalAny(resHints)
+	if err != nil {
+		return nil, status.Error(codes.Unknown, errors.Wrap(err, "marshal exemplars response hints").Error())
+	}
+
+	return &storepb.ExemplarsResponse{
+		Timeseries: block.MergeExemplars(sets...),
+		Hints:      anyHints,
+	}, nil
+}
+
+// readBlockExemplars returns the exemplars read from the exemplars file of a block within the [mint, maxt]
+// time range, of the series matching any of the sets of matchers. The returned series are reserved out
+// of the seriesLimiter.
+func readBlockExemplars(r io.Reader, mint, maxt int64, matchers [][]*labels.Matcher, seriesLimiter SeriesLimiter) ([]mimirpb.TimeSeries, error) {
+	var result []mimirpb.TimeSeries
+	err := block.ReadExemplars(r, func(series mimirpb.TimeSeries) error {
+		filtered := block.FilterExemplars([]mimirpb.TimeSeries{series}, mint, maxt, matchers...)
+		if len(filtered) == 0 {
+			return nil
+		}
+		if err := seriesLimiter.Reserve(1); err != nil {
+			return err
+		}
+		result = append(result, filtered...)
+		return nil
+	})
+	return result, err
+}
+
+// MetricMetadata returns the metric metadata persisted in the blocks for the requested time range.
+// Blocks without a metadata file are reported as queried, because they have no metadata to return.
+func (s *BucketStore) MetricMetadata(ctx context.Context, req *storepb.MetricMetadataRequest) (*storepb.MetricMetadataResponse, error) {
+	resHints := &hintspb.MetricMetadataResponseHints{}
+
+	var reqBlockMatchers []*labels.Matcher
+	if req.Hints != nil {
+		reqHints := &hintspb.MetricMetadataRequestHints{}
+		err := types.UnmarshalAny(req.Hints, reqHints)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "unmarshal metric metadata request hints").Error())
+		}
+
+		reqBlockMatchers, err = storepb.MatchersToPromMatchers(reqHints.BlockMatchers...)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "translate request hints labels matchers").Error())
+		}
+	}
+
+	blocks := s.blocksWithSidecarFile(req.Start, req.End, reqBlockMatchers, (*block.Meta).HasMetadata, resHints.AddQueriedBlock)
+
+	sets, err := readBlocksSidecarFile(ctx, blocks, block.MetadataFilename, s.metadataLimits, func(r io.Reader) ([]block.MetricMetadata, error) {
+		metadata, err := block.ReadMetadata(r)
+		if err != nil {
+			return nil, err
+		}
+		return block.FilterMetadata(metadata, req.Start, req.End, req.Metric), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	anyHints, err := types.MarshalAny(resHints)
+	if err != nil {
+		return nil, status.Error(codes.Unknown, errors.Wrap(err, "marshal metric metadata response hints").Error())
+	}
+
+	merged := block.MergeMetadata(sets...)
+	metadata := make([]storepb.MetricMetadata, 0, len(merged))
+	for _, m := range merged {
+		metadata = append(metadata, storepb.MetricMetadata{
+			Metric:  m.Metric,
+			Type:    m.Type,
+			Help:    m.Help,
+			Unit:    m.Unit,
+			MinTime: m.MinTime,
+			MaxTime: m.MaxTime,
+		})
+	}
+
+	return &storepb.MetricMetadataResponse{
+		Metadata: metadata,
+		Hints:    anyHints,
+	}, nil
+}
+
+// blocksWithSidecarFile returns the blocks within the [mint, maxt] time range and matching the block matchers
+// which have the sidecar file checked by hasFile, such as the exemplars or metadata file. All the blocks within
+// the time range and matching the block matchers are passed to onQueried, because the blocks without the
+// sidecar file have no data to return.
+func (s *BucketStore) blocksWithSidecarFile(mint, maxt int64, blockMatchers []*labels.Matcher, hasFile func(*block.Meta) bool, onQueried func(ulid.ULID)) []*bucketBlock {
+	s.blocksMx.RLock()
+	defer s.blocksMx.RUnlock()
+
+	var blocks []*bucketBlock
+	for _, b := range s.blocks {
+		if !b.overlapsClosedInterval(mint, maxt) {
+			continue
+		}
+		if len(blockMatchers) > 0 && !b.matchLabels(blockMatchers) {
+			continue
+		}
+
+		onQueried(b.meta.ULID)
+
+		if hasFile(b.meta) {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+// sidecarFileLimits holds the limits of a query reading a sidecar file of the blocks. 0 disables the limit.
+type sidecarFileLimits struct {
+	maxBlocks, maxBytes             uint64
+	blocksMsgFormat, bytesMsgFormat string
+	blocksDropped, bytesDropped     prometheus.Counter
+}
+
+// readBlocksSidecarFile concurrently reads the sidecar file named filename of the input blocks, calling read with
+// the content of the file of each block. The sidecar files are cached by the caching bucket, if the metadata
+// cache is configured. The number of blocks is checked against the limits before reading any file, and the bytes
+// read are reserved out of the limits while reading the files. The returned error is a gRPC status error.
+func readBlocksSidecarFile[T any](ctx context.Context, blocks []*bucketBlock, filename string, limits sidecarFileLimits, read func(r io.Reader) ([]T, error)) ([][]T, error) {
+	blocksLimiter := NewLimiter(limits.maxBlocks, limits.blocksDropped, limits.blocksMsgFormat)
+	bytesLimiter := NewLimiter(limits.maxBytes, limits.bytesDropped, limits.bytesMsgFormat)
+
+	if err := blocksLimiter.Reserve(uint64(len(blocks))); err != nil {
+		return nil, err
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	var mtx sync.Mutex
+	var sets [][]T
+	for _, b := range blocks {
+		b := b
+
+		g.Go(func() error {
+			r, err := b.bkt.Get(gctx, path.Join(b.meta.ULID.String(), filename))
+			if err != nil {
+				return errors.Wrapf(err, "block %s: get %s file", b.meta.ULID, filename)
+			}
+			defer runutil.CloseWithLogOnErr(b.logger, r, "close block %s reader", filename)
+
+			result, err := read(&limitingReader{r: r, limiter: bytesLimiter})
+			if err != nil {
+				return errors.Wrapf(err, "block %s: read %s file", b.meta.ULID, filename)
+			}
+
+			if len(result) > 0 {
+				mtx.Lock()
+				sets = append(sets, result)
+				mtx.Unlock()
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		// Limit errors are returned with their own status code.
+		if st, ok := status.FromError(errors.Cause(err)); ok {
+			return nil, status.Error(st.Code(), err.Error())
+		}
+		if errors.Is(err, context.Canceled) {
+			return nil, status.Error(codes.Canceled, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return sets, nil
+}
+
 // blockLabelValues returns sorted values of the label with requested name,
 // optionally restricting the search to the series that match the matchers provided.
 // - First we fetch all possible values for this label from the index.
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -2747,6 +2748,401 @@ func TestLabelNames_Cancelled(t *testing.T) {
 	assert.Equal(t, codes.Canceled, s.Code())
 }
 
+func TestBucketStore_Exemplars(t *testing.T) {
+	tmpDir := t.TempDir()
+	bktDir := filepath.Join(tmpDir, "bkt")
+	bkt, err := filesystem.NewBucket(bktDir)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, bkt.Close()) })
+
+	logger := log.NewNopLogger()
+	random := rand.New(rand.NewSource(120))
+
+	exemplarSeries := func(name string, timestamps ...int64) mimirpb.TimeSeries {
+		s := mimirpb.TimeSeries{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings(labels.MetricName, name))}
+		for _, ts := range timestamps {
+			s.Exemplars = append(s.Exemplars, mimirpb.Exemplar{
+				Labels:      []mimirpb.LabelAdapter{{Name: "trace_id", Value: fmt.Sprintf("trace-%d", ts)}},
+				Value:       1,
+				TimestampMs: ts,
+			})
+		}
+		return s
+	}
+
+	// Create three blocks, only the first two having exemplars.
+	var blocks []ulid.ULID
+	for i := 0; i < 3; i++ {
+		head, _ := createHeadWithSeries(t, 0, headGenOptions{
+			TSDBDir:          filepath.Join(tmpDir, strconv.Itoa(i)),
+			SamplesPerSeries: 1,
+			Series:           2,
+			Random:           random,
+		})
+		blockID := createBlockFromHead(t, bktDir, head)
+		require.NoError(t, head.Close())
+
+		blockDir := filepath.Join(bktDir, blockID.String())
+		switch i {
+		case 0:
+			require.NoError(t, block.WriteExemplarsFile(blockDir, []mimirpb.TimeSeries{
+				exemplarSeries("series_1", 0, 1),
+				exemplarSeries("series_2", 1),
+			}))
+		case 1:
+			// The same exemplar ingested by another replica.
+			require.NoError(t, block.WriteExemplarsFile(blockDir, []mimirpb.TimeSeries{
+				exemplarSeries("series_1", 1),
+			}))
+		}
+
+		files, err := block.GatherFileStats(blockDir)
+		require.NoError(t, err)
+		_, err = block.InjectThanosMeta(logger, blockDir, block.ThanosMeta{Source: block.TestSource, Files: files}, nil)
+		require.NoError(t, err)
+
+		blocks = append(blocks, blockID)
+	}
+
+	instrBkt := objstore.WithNoopInstr(bkt)
+	fetcher, err := block.NewMetaFetcher(logger, 10, instrBkt, tmpDir, nil, nil)
+	require.NoError(t, err)
+
+	newStore := func(t *testing.T, maxSeries uint64, maxBlocks int, maxBytes uint64) *BucketStore {
+		store, err := NewBucketStore(
+			"tenant",
+			instrBkt,
+			fetcher,
+			t.TempDir(),
+			mimir_tsdb.BucketStoreConfig{
+				StreamingBatchSize:          5000,
+				BlockSyncConcurrency:        10,
+				PostingOffsetsInMemSampling: mimir_tsdb.DefaultPostingOffsetInMemorySampling,
+				IndexHeader: indexheader.Config{
+					SparsePersistenceEnabled: true,
+				},
+				MaxExemplarsBlocksPerQuery: maxBlocks,
+				MaxExemplarsBytesPerQuery:  maxBytes,
+			},
+			selectAllStrategy{},
+			newStaticChunksLimiterFactory(100),
+			newStaticSeriesLimiterFactory(maxSeries),
+			newGapBasedPartitioners(mimir_tsdb.DefaultPartitionerMaxGapSize, nil),
+			hashcache.NewSeriesHashCache(1024*1024),
+			NewBucketStoreMetrics(nil),
+			WithLogger(logger),
+		)
+		require.NoError(t, err)
+		require.NoError(t, store.SyncBlocks(context.Background()))
+		t.Cleanup(func() { assert.NoError(t, store.RemoveBlocksAndClose()) })
+		return store
+	}
+
+	store := newStore(t, 0, 0, 0)
+
+	tests := map[string]struct {
+		req            *storepb.ExemplarsRequest
+		expectedSeries []mimirpb.TimeSeries
+		expectedBlocks []ulid.ULID
+	}{
+		"all series": {
+			req: &storepb.ExemplarsRequest{
+				Start: 0,
+				End:   1,
+				Matchers: []storepb.ExemplarMatchers{
+					{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_RE, Name: labels.MetricName, Value: "series_.*"}}},
+				},
+			},
+			expectedSeries: []mimirpb.TimeSeries{exemplarSeries("series_1", 0, 1), exemplarSeries("series_2", 1)},
+			expectedBlocks: blocks,
+		},
+		"time range": {
+			req: &storepb.ExemplarsRequest{
+				Start: 0,
+				End:   0,
+				Matchers: []storepb.ExemplarMatchers{
+					{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_RE, Name: labels.MetricName, Value: "series_.*"}}},
+				},
+			},
+			expectedSeries: []mimirpb.TimeSeries{exemplarSeries("series_1", 0)},
+			expectedBlocks: blocks,
+		},
+		"multiple sets of matchers": {
+			req: &storepb.ExemplarsRequest{
+				Start: 0,
+				End:   1,
+				Matchers: []storepb.ExemplarMatchers{
+					{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: labels.MetricName, Value: "series_2"}}},
+					{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: labels.MetricName, Value: "unknown"}}},
+				},
+			},
+			expectedSeries: []mimirpb.TimeSeries{exemplarSeries("series_2", 1)},
+			expectedBlocks: blocks,
+		},
+		"block without exemplars": {
+			req: &storepb.ExemplarsRequest{
+				Start: 0,
+				End:   1,
+				Matchers: []storepb.ExemplarMatchers{
+					{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_RE, Name: labels.MetricName, Value: "series_.*"}}},
+				},
+				Hints: mustMarshalAny(&hintspb.ExemplarsRequestHints{
+					BlockMatchers: []storepb.LabelMatcher{
+						{Type: storepb.LabelMatcher_EQ, Name: block.BlockIDLabel, Value: blocks[2].String()},
+					},
+				}),
+			},
+			expectedSeries: []mimirpb.TimeSeries{},
+			expectedBlocks: blocks[2:],
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := store.Exemplars(context.Background(), tc.req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSeries, resp.Timeseries)
+
+			var hints hintspb.ExemplarsResponseHints
+			require.NoError(t, types.UnmarshalAny(resp.Hints, &hints))
+			expectedHints := hintspb.ExemplarsResponseHints{}
+			for _, id := range tc.expectedBlocks {
+				expectedHints.AddQueriedBlock(id)
+			}
+			assert.ElementsMatch(t, expectedHints.QueriedBlocks, hints.QueriedBlocks)
+		})
+	}
+
+	t.Run("limits", func(t *testing.T) {
+		req := &storepb.ExemplarsRequest{
+			Start: 0,
+			End:   1,
+			Matchers: []storepb.ExemplarMatchers{
+				{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_RE, Name: labels.MetricName, Value: "series_.*"}}},
+			},
+		}
+
+		limitTests := map[string]struct {
+			maxSeries     uint64
+			maxBlocks     int
+			maxBytes      uint64
+			expectedError string
+		}{
+			// Only the blocks with exemplars count towards the blocks limit.
+			"within the limits": {
+				maxSeries: 3,
+				maxBlocks: 2,
+				maxBytes:  1024,
+			},
+			"series limit exceeded": {
+				maxSeries:     2,
+				expectedError: "exceeded the maximum number of series",
+			},
+			"blocks limit exceeded": {
+				maxBlocks:     1,
+				expectedError: "exceeded the maximum number of blocks with exemplars",
+			},
+			"bytes limit exceeded": {
+				maxBytes:      10,
+				expectedError: "exceeded the aggregated exemplars files size limit",
+			},
+		}
+
+		for name, tc := range limitTests {
+			t.Run(name, func(t *testing.T) {
+				resp, err := newStore(t, tc.maxSeries, tc.maxBlocks, tc.maxBytes).Exemplars(context.Background(), req)
+				if tc.expectedError == "" {
+					require.NoError(t, err)
+					assert.Equal(t, []mimirpb.TimeSeries{exemplarSeries("series_1", 0, 1), exemplarSeries("series_2", 1)}, resp.Timeseries)
+					return
+				}
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Equal(t, codes.Code(http.StatusUnprocessableEntity), status.Code(err))
+			})
+		}
+	})
+}
+
+func TestBucketStore_MetricMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	bktDir := filepath.Join(tmpDir, "bkt")
+	bkt, err := filesystem.NewBucket(bktDir)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, bkt.Close()) })
+
+	logger := log.NewNopLogger()
+	random := rand.New(rand.NewSource(120))
+
+	// Create three blocks, only the first two having metadata.
+	var blocks []ulid.ULID
+	for i := 0; i < 3; i++ {
+		head, _ := createHeadWithSeries(t, 0, headGenOptions{
+			TSDBDir:          filepath.Join(tmpDir, strconv.Itoa(i)),
+			SamplesPerSeries: 1,
+			Series:           2,
+			Random:           random,
+		})
+		blockID := createBlockFromHead(t, bktDir, head)
+		require.NoError(t, head.Close())
+
+		blockDir := filepath.Join(bktDir, blockID.String())
+		switch i {
+		case 0:
+			require.NoError(t, block.WriteMetadataFile(blockDir, []block.MetricMetadata{
+				{Metric: "series_1", Type: "counter", Help: "help", MinTime: 0, MaxTime: 0},
+				{Metric: "series_2", Type: "gauge", Help: "help", MinTime: 0, MaxTime: 1},
+			}))
+		case 1:
+			require.NoError(t, block.WriteMetadataFile(blockDir, []block.MetricMetadata{
+				{Metric: "series_1", Type: "counter", Help: "help", MinTime: 1, MaxTime: 1},
+			}))
+		}
+
+		files, err := block.GatherFileStats(blockDir)
+		require.NoError(t, err)
+		_, err = block.InjectThanosMeta(logger, blockDir, block.ThanosMeta{Source: block.TestSource, Files: files}, nil)
+		require.NoError(t, err)
+
+		blocks = append(blocks, blockID)
+	}
+
+	instrBkt := objstore.WithNoopInstr(bkt)
+	fetcher, err := block.NewMetaFetcher(logger, 10, instrBkt, tmpDir, nil, nil)
+	require.NoError(t, err)
+
+	newStore := func(t *testing.T, maxBlocks int, maxBytes uint64) *BucketStore {
+		store, err := NewBucketStore(
+			"tenant",
+			instrBkt,
+			fetcher,
+			t.TempDir(),
+			mimir_tsdb.BucketStoreConfig{
+				StreamingBatchSize:          5000,
+				BlockSyncConcurrency:        10,
+				PostingOffsetsInMemSampling: mimir_tsdb.DefaultPostingOffsetInMemorySampling,
+				IndexHeader: indexheader.Config{
+					SparsePersistenceEnabled: true,
+				},
+				MaxMetadataBlocksPerQuery: maxBlocks,
+				MaxMetadataBytesPerQuery:  maxBytes,
+			},
+			selectAllStrategy{},
+			newStaticChunksLimiterFactory(100),
+			newStaticSeriesLimiterFactory(0),
+			newGapBasedPartitioners(mimir_tsdb.DefaultPartitionerMaxGapSize, nil),
+			hashcache.NewSeriesHashCache(1024*1024),
+			NewBucketStoreMetrics(nil),
+			WithLogger(logger),
+		)
+		require.NoError(t, err)
+		require.NoError(t, store.SyncBlocks(context.Background()))
+		t.Cleanup(func() { assert.NoError(t, store.RemoveBlocksAndClose()) })
+		return store
+	}
+
+	store := newStore(t, 0, 0)
+
+	tests := map[string]struct {
+		req              *storepb.MetricMetadataRequest
+		expectedMetadata []storepb.MetricMetadata
+		expectedBlocks   []ulid.ULID
+	}{
+		"all metrics": {
+			req: &storepb.MetricMetadataRequest{Start: 0, End: 1},
+			expectedMetadata: []storepb.MetricMetadata{
+				{Metric: "series_1", Type: "counter", Help: "help", MinTime: 0, MaxTime: 1},
+				{Metric: "series_2", Type: "gauge", Help: "help", MinTime: 0, MaxTime: 1},
+			},
+			expectedBlocks: blocks,
+		},
+		"time range": {
+			req: &storepb.MetricMetadataRequest{Start: 1, End: 1},
+			expectedMetadata: []storepb.MetricMetadata{
+				{Metric: "series_1", Type: "counter", Help: "help", MinTime: 1, MaxTime: 1},
+				{Metric: "series_2", Type: "gauge", Help: "help", MinTime: 1, MaxTime: 1},
+			},
+			expectedBlocks: blocks,
+		},
+		"metric": {
+			req: &storepb.MetricMetadataRequest{Start: 0, End: 1, Metric: "series_2"},
+			expectedMetadata: []storepb.MetricMetadata{
+				{Metric: "series_2", Type: "gauge", Help: "help", MinTime: 0, MaxTime: 1},
+			},
+			expectedBlocks: blocks,
+		},
+		"block without metadata": {
+			req: &storepb.MetricMetadataRequest{
+				Start: 0,
+				End:   1,
+				Hints: mustMarshalAny(&hintspb.MetricMetadataRequestHints{
+					BlockMatchers: []storepb.LabelMatcher{
+						{Type: storepb.LabelMatcher_EQ, Name: block.BlockIDLabel, Value: blocks[2].String()},
+					},
+				}),
+			},
+			expectedMetadata: []storepb.MetricMetadata{},
+			expectedBlocks:   blocks[2:],
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := store.MetricMetadata(context.Background(), tc.req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMetadata, resp.Metadata)
+
+			var hints hintspb.MetricMetadataResponseHints
+			require.NoError(t, types.UnmarshalAny(resp.Hints, &hints))
+			expectedHints := hintspb.MetricMetadataResponseHints{}
+			for _, id := range tc.expectedBlocks {
+				expectedHints.AddQueriedBlock(id)
+			}
+			assert.ElementsMatch(t, expectedHints.QueriedBlocks, hints.QueriedBlocks)
+		})
+	}
+
+	t.Run("limits", func(t *testing.T) {
+		req := &storepb.MetricMetadataRequest{Start: 0, End: 1}
+
+		limitTests := map[string]struct {
+			maxBlocks     int
+			maxBytes      uint64
+			expectedError string
+		}{
+			// Only the blocks with metadata count towards the blocks limit.
+			"within the limits": {
+				maxBlocks: 2,
+				maxBytes:  1024,
+			},
+			"blocks limit exceeded": {
+				maxBlocks:     1,
+				expectedError: "exceeded the maximum number of blocks with metric metadata",
+			},
+			"bytes limit exceeded": {
+				maxBytes:      10,
+				expectedError: "exceeded the aggregated metric metadata files size limit",
+			},
+		}
+
+		for name, tc := range limitTests {
+			t.Run(name, func(t *testing.T) {
+				resp, err := newStore(t, tc.maxBlocks, tc.maxBytes).MetricMetadata(context.Background(), req)
+				if tc.expectedError == "" {
+					require.NoError(t, err)
+					assert.Len(t, resp.Metadata, 2)
+					return
+				}
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Equal(t, codes.Code(http.StatusUnprocessableEntity), status.Code(err))
+			})
+		}
+	})
+}
+
 func TestLabelValues_Cancelled(t *testing.T) {
 	_, store, _, _, _, _, cleanup := setupStoreForHintsTest(t, 5000)
 	defer cleanup()
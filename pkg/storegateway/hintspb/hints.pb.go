@@ -290,6 +290,158 @@ func (m *LabelValuesResponseHints) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_LabelValuesResponseHints proto.InternalMessageInfo
 
+type ExemplarsRequestHints struct {
+	/// block_matchers is a list of label matchers that are evaluated against each single block's
+	/// labels to filter which blocks get queried. If the list is empty, no per-block filtering
+	/// is applied.
+	BlockMatchers []storepb.LabelMatcher `protobuf:"bytes,1,rep,name=block_matchers,json=blockMatchers,proto3" json:"block_matchers"`
+}
+
+func (m *ExemplarsRequestHints) Reset()      { *m = ExemplarsRequestHints{} }
+func (*ExemplarsRequestHints) ProtoMessage() {}
+func (*ExemplarsRequestHints) Descriptor() ([]byte, []int) {
+	return fileDescriptor_522be8e0d2634375, []int{7}
+}
+func (m *ExemplarsRequestHints) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExemplarsRequestHints) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExemplarsRequestHints.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExemplarsRequestHints) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExemplarsRequestHints.Merge(m, src)
+}
+func (m *ExemplarsRequestHints) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExemplarsRequestHints) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExemplarsRequestHints.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExemplarsRequestHints proto.InternalMessageInfo
+
+type ExemplarsResponseHints struct {
+	/// queried_blocks is the list of blocks that have been queried.
+	QueriedBlocks []Block `protobuf:"bytes,1,rep,name=queried_blocks,json=queriedBlocks,proto3" json:"queried_blocks"`
+}
+
+func (m *ExemplarsResponseHints) Reset()      { *m = ExemplarsResponseHints{} }
+func (*ExemplarsResponseHints) ProtoMessage() {}
+func (*ExemplarsResponseHints) Descriptor() ([]byte, []int) {
+	return fileDescriptor_522be8e0d2634375, []int{8}
+}
+func (m *ExemplarsResponseHints) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExemplarsResponseHints) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExemplarsResponseHints.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExemplarsResponseHints) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExemplarsResponseHints.Merge(m, src)
+}
+func (m *ExemplarsResponseHints) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExemplarsResponseHints) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExemplarsResponseHints.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExemplarsResponseHints proto.InternalMessageInfo
+
+type MetricMetadataRequestHints struct {
+	/// block_matchers is a list of label matchers that are evaluated against each single block's
+	/// labels to filter which blocks get queried. If the list is empty, no per-block filtering
+	/// is applied.
+	BlockMatchers []storepb.LabelMatcher `protobuf:"bytes,1,rep,name=block_matchers,json=blockMatchers,proto3" json:"block_matchers"`
+}
+
+func (m *MetricMetadataRequestHints) Reset()      { *m = MetricMetadataRequestHints{} }
+func (*MetricMetadataRequestHints) ProtoMessage() {}
+func (*MetricMetadataRequestHints) Descriptor() ([]byte, []int) {
+	return fileDescriptor_522be8e0d2634375, []int{9}
+}
+func (m *MetricMetadataRequestHints) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MetricMetadataRequestHints) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MetricMetadataRequestHints.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MetricMetadataRequestHints) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetricMetadataRequestHints.Merge(m, src)
+}
+func (m *MetricMetadataRequestHints) XXX_Size() int {
+	return m.Size()
+}
+func (m *MetricMetadataRequestHints) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetricMetadataRequestHints.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetricMetadataRequestHints proto.InternalMessageInfo
+
+type MetricMetadataResponseHints struct {
+	/// queried_blocks is the list of blocks that have been queried.
+	QueriedBlocks []Block `protobuf:"bytes,1,rep,name=queried_blocks,json=queriedBlocks,proto3" json:"queried_blocks"`
+}
+
+func (m *MetricMetadataResponseHints) Reset()      { *m = MetricMetadataResponseHints{} }
+func (*MetricMetadataResponseHints) ProtoMessage() {}
+func (*MetricMetadataResponseHints) Descriptor() ([]byte, []int) {
+	return fileDescriptor_522be8e0d2634375, []int{10}
+}
+func (m *MetricMetadataResponseHints) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MetricMetadataResponseHints) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MetricMetadataResponseHints.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MetricMetadataResponseHints) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetricMetadataResponseHints.Merge(m, src)
+}
+func (m *MetricMetadataResponseHints) XXX_Size() int {
+	return m.Size()
+}
+func (m *MetricMetadataResponseHints) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetricMetadataResponseHints.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetricMetadataResponseHints proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*SeriesRequestHints)(nil), "hintspb.SeriesRequestHints")
 	proto.RegisterType((*SeriesResponseHints)(nil), "hintspb.SeriesResponseHints")
@@ -298,35 +450,41 @@ func init() {
 	proto.RegisterType((*LabelNamesResponseHints)(nil), "hintspb.LabelNamesResponseHints")
 	proto.RegisterType((*LabelValuesRequestHints)(nil), "hintspb.LabelValuesRequestHints")
 	proto.RegisterType((*LabelValuesResponseHints)(nil), "hintspb.LabelValuesResponseHints")
+	proto.RegisterType((*ExemplarsRequestHints)(nil), "hintspb.ExemplarsRequestHints")
+	proto.RegisterType((*ExemplarsResponseHints)(nil), "hintspb.ExemplarsResponseHints")
+	proto.RegisterType((*MetricMetadataRequestHints)(nil), "hintspb.MetricMetadataRequestHints")
+	proto.RegisterType((*MetricMetadataResponseHints)(nil), "hintspb.MetricMetadataResponseHints")
 }
 
 func init() { proto.RegisterFile("hints.proto", fileDescriptor_522be8e0d2634375) }
 
 var fileDescriptor_522be8e0d2634375 = []byte{
-	// 358 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x92, 0xb1, 0x4e, 0xfb, 0x30,
-	0x10, 0xc6, 0xed, 0xfe, 0xff, 0x80, 0x70, 0x45, 0x86, 0x80, 0x68, 0xd5, 0xe1, 0xa8, 0x32, 0x75,
-	0x21, 0x91, 0x60, 0x44, 0x0c, 0xed, 0xc4, 0x00, 0x0c, 0x41, 0x6a, 0x25, 0x40, 0xaa, 0x9c, 0xd6,
-	0x4d, 0xa2, 0x36, 0x71, 0x1a, 0x3b, 0x42, 0xdd, 0x78, 0x04, 0x1e, 0x83, 0x47, 0xe9, 0xd8, 0xb1,
-	0x13, 0x22, 0xe9, 0xc2, 0xd8, 0x47, 0x40, 0x75, 0x12, 0xa9, 0xec, 0xd9, 0xfc, 0x7d, 0x77, 0xf7,
-	0xf3, 0x77, 0xd2, 0x91, 0xba, 0xe7, 0x87, 0x52, 0x98, 0x51, 0xcc, 0x25, 0xd7, 0x8f, 0x94, 0x88,
-	0x9c, 0xd6, 0xa5, 0xeb, 0x4b, 0x2f, 0x71, 0xcc, 0x11, 0x0f, 0x2c, 0x97, 0xbb, 0xdc, 0x52, 0x75,
-	0x27, 0x99, 0x28, 0xa5, 0x84, 0x7a, 0xe5, 0x73, 0xad, 0xdb, 0xfd, 0xf6, 0x98, 0x4e, 0x68, 0x48,
-	0xad, 0xc0, 0x0f, 0xfc, 0xd8, 0x8a, 0xa6, 0xae, 0x25, 0x24, 0x8f, 0x99, 0x4b, 0x25, 0x7b, 0xa3,
-	0x8b, 0x5c, 0x44, 0x8e, 0x25, 0x17, 0x11, 0x2b, 0xbe, 0x35, 0x06, 0x44, 0x7f, 0x62, 0xb1, 0xcf,
-	0x84, 0xcd, 0xe6, 0x09, 0x13, 0xf2, 0x6e, 0x97, 0x42, 0xef, 0x12, 0xcd, 0x99, 0xf1, 0xd1, 0x74,
-	0x18, 0x50, 0x39, 0xf2, 0x58, 0x2c, 0x9a, 0xb8, 0xfd, 0xaf, 0x53, 0xbf, 0x3a, 0x33, 0xa5, 0x47,
-	0x43, 0x2e, 0xcc, 0x7b, 0xea, 0xb0, 0xd9, 0x43, 0x5e, 0xec, 0xfd, 0x5f, 0x7e, 0x5d, 0x20, 0xfb,
-	0x44, 0x4d, 0x14, 0x9e, 0x30, 0x6c, 0x72, 0x5a, 0x82, 0x45, 0xc4, 0x43, 0xc1, 0x72, 0xf2, 0x0d,
-	0xd1, 0xe6, 0xc9, 0xce, 0x1f, 0x0f, 0x55, 0x7f, 0x49, 0xd6, 0xcc, 0x62, 0x7f, 0xb3, 0xb7, 0xb3,
-	0x4b, 0x66, 0xd1, 0xab, 0x3c, 0x61, 0x34, 0xc8, 0x81, 0x7a, 0xe9, 0x1a, 0xa9, 0xf9, 0xe3, 0x26,
-	0x6e, 0xe3, 0xce, 0xb1, 0x5d, 0xf3, 0xc7, 0xc6, 0x0b, 0x39, 0x57, 0x89, 0x1e, 0x69, 0x50, 0xfd,
-	0x26, 0x7d, 0xd2, 0xd8, 0x87, 0x57, 0xb6, 0xcd, 0x6b, 0xc1, 0xed, 0xd3, 0x59, 0x52, 0x7d, 0xea,
-	0x01, 0x69, 0xfe, 0xa1, 0x57, 0x15, 0xbb, 0xd7, 0x5d, 0xa6, 0x80, 0x56, 0x29, 0xa0, 0x75, 0x0a,
-	0x68, 0x9b, 0x02, 0x7e, 0xcf, 0x00, 0x7f, 0x66, 0x80, 0x97, 0x19, 0xe0, 0x55, 0x06, 0xf8, 0x3b,
-	0x03, 0xfc, 0x93, 0x01, 0xda, 0x66, 0x80, 0x3f, 0x36, 0x80, 0x56, 0x1b, 0x40, 0xeb, 0x0d, 0xa0,
-	0xe7, 0xf2, 0xc4, 0x9d, 0x43, 0x75, 0x7b, 0xd7, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xe1, 0x13,
-	0x56, 0x30, 0x01, 0x03, 0x00, 0x00,
+	// 398 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x94, 0xbf, 0xae, 0xd3, 0x30,
+	0x14, 0x87, 0xed, 0xcb, 0x3f, 0xe1, 0x2b, 0x32, 0x04, 0x68, 0xab, 0x22, 0x99, 0x2a, 0x53, 0x17,
+	0x12, 0x09, 0x46, 0xc4, 0xd0, 0x4a, 0x48, 0x0c, 0x94, 0x21, 0x88, 0x56, 0x2a, 0x48, 0x95, 0x93,
+	0xb8, 0x89, 0xd5, 0x24, 0x4e, 0x6d, 0x47, 0xd0, 0x8d, 0x47, 0xe0, 0x31, 0x78, 0x94, 0x8e, 0x1d,
+	0x3b, 0x21, 0x92, 0x2e, 0x8c, 0x7d, 0x04, 0x54, 0x27, 0x91, 0x0a, 0xb3, 0x37, 0x9f, 0x9f, 0x8f,
+	0xbf, 0xf3, 0x9d, 0x21, 0x41, 0xb7, 0x09, 0xcb, 0x95, 0x74, 0x0b, 0xc1, 0x15, 0xb7, 0x1f, 0xe8,
+	0xa2, 0x08, 0x86, 0x2f, 0x62, 0xa6, 0x92, 0x32, 0x70, 0x43, 0x9e, 0x79, 0x31, 0x8f, 0xb9, 0xa7,
+	0xef, 0x83, 0x72, 0xad, 0x2b, 0x5d, 0xe8, 0x53, 0xf3, 0x6e, 0xf8, 0xe6, 0xba, 0x5d, 0x90, 0x35,
+	0xc9, 0x89, 0x97, 0xb1, 0x8c, 0x09, 0xaf, 0xd8, 0xc4, 0x9e, 0x54, 0x5c, 0xd0, 0x98, 0x28, 0xfa,
+	0x95, 0xec, 0x9a, 0xa2, 0x08, 0x3c, 0xb5, 0x2b, 0x68, 0x3b, 0xd6, 0x59, 0x20, 0xfb, 0x23, 0x15,
+	0x8c, 0x4a, 0x9f, 0x6e, 0x4b, 0x2a, 0xd5, 0xbb, 0x8b, 0x85, 0x3d, 0x41, 0x56, 0x90, 0xf2, 0x70,
+	0xb3, 0xca, 0x88, 0x0a, 0x13, 0x2a, 0xe4, 0x00, 0x8e, 0xee, 0x8c, 0x6f, 0x5f, 0x3e, 0x71, 0x55,
+	0x42, 0x72, 0x2e, 0xdd, 0xf7, 0x24, 0xa0, 0xe9, 0xac, 0xb9, 0x9c, 0xde, 0xdd, 0xff, 0x7a, 0x0e,
+	0xfc, 0x47, 0xfa, 0x45, 0x9b, 0x49, 0xc7, 0x47, 0x8f, 0x3b, 0xb0, 0x2c, 0x78, 0x2e, 0x69, 0x43,
+	0x7e, 0x8d, 0xac, 0x6d, 0x79, 0xc9, 0xa3, 0x95, 0xee, 0xef, 0xc8, 0x96, 0xdb, 0xee, 0xef, 0x4e,
+	0x2f, 0x71, 0xc7, 0x6c, 0x7b, 0x75, 0x26, 0x9d, 0x3e, 0xba, 0xa7, 0x4f, 0xb6, 0x85, 0x6e, 0x58,
+	0x34, 0x80, 0x23, 0x38, 0x7e, 0xe8, 0xdf, 0xb0, 0xc8, 0xf9, 0x8c, 0x7a, 0xda, 0xe8, 0x03, 0xc9,
+	0xcc, 0x6f, 0x32, 0x47, 0xfd, 0x6b, 0xb8, 0xb1, 0x6d, 0xbe, 0xb4, 0xdc, 0x39, 0x49, 0x4b, 0xf3,
+	0xd6, 0x0b, 0x34, 0xf8, 0x87, 0x6e, 0x4c, 0x7b, 0x89, 0x9e, 0xbe, 0xfd, 0x46, 0xb3, 0x22, 0x25,
+	0xc2, 0xb8, 0xf4, 0x27, 0xd4, 0xbb, 0x62, 0x1b, 0x53, 0x5e, 0xa1, 0xe1, 0x8c, 0x2a, 0xc1, 0xc2,
+	0x19, 0x55, 0x24, 0x22, 0x8a, 0x98, 0xf6, 0x5e, 0xa2, 0x67, 0xff, 0x0f, 0x30, 0x25, 0x3f, 0x9d,
+	0xec, 0x2b, 0x0c, 0x0e, 0x15, 0x06, 0xc7, 0x0a, 0x83, 0x73, 0x85, 0xe1, 0xf7, 0x1a, 0xc3, 0x9f,
+	0x35, 0x86, 0xfb, 0x1a, 0xc3, 0x43, 0x8d, 0xe1, 0xef, 0x1a, 0xc3, 0x3f, 0x35, 0x06, 0xe7, 0x1a,
+	0xc3, 0x1f, 0x27, 0x0c, 0x0e, 0x27, 0x0c, 0x8e, 0x27, 0x0c, 0x96, 0xdd, 0x2f, 0x25, 0xb8, 0xaf,
+	0xbf, 0xf5, 0x57, 0x7f, 0x07, 0x00, 0x42, 0x85, 0x6b, 0xa5, 0x71, 0x04, 0x00, 0x00,
 }
 
 func (this *SeriesRequestHints) Equal(that interface{}) bool {
@@ -527,6 +685,122 @@ func (this *LabelValuesResponseHints) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *ExemplarsRequestHints) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ExemplarsRequestHints)
+	if !ok {
+		that2, ok := that.(ExemplarsRequestHints)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.BlockMatchers) != len(that1.BlockMatchers) {
+		return false
+	}
+	for i := range this.BlockMatchers {
+		if !this.BlockMatchers[i].Equal(&that1.BlockMatchers[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *ExemplarsResponseHints) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ExemplarsResponseHints)
+	if !ok {
+		that2, ok := that.(ExemplarsResponseHints)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.QueriedBlocks) != len(that1.QueriedBlocks) {
+		return false
+	}
+	for i := range this.QueriedBlocks {
+		if !this.QueriedBlocks[i].Equal(&that1.QueriedBlocks[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *MetricMetadataRequestHints) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*MetricMetadataRequestHints)
+	if !ok {
+		that2, ok := that.(MetricMetadataRequestHints)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.BlockMatchers) != len(that1.BlockMatchers) {
+		return false
+	}
+	for i := range this.BlockMatchers {
+		if !this.BlockMatchers[i].Equal(&that1.BlockMatchers[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *MetricMetadataResponseHints) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*MetricMetadataResponseHints)
+	if !ok {
+		that2, ok := that.(MetricMetadataResponseHints)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.QueriedBlocks) != len(that1.QueriedBlocks) {
+		return false
+	}
+	for i := range this.QueriedBlocks {
+		if !this.QueriedBlocks[i].Equal(&that1.QueriedBlocks[i]) {
+			return false
+		}
+	}
+	return true
+}
 func (this *SeriesRequestHints) GoString() string {
 	if this == nil {
 		return "nil"
@@ -633,6 +907,70 @@ func (this *LabelValuesResponseHints) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *ExemplarsRequestHints) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&hintspb.ExemplarsRequestHints{")
+	if this.BlockMatchers != nil {
+		vs := make([]*storepb.LabelMatcher, len(this.BlockMatchers))
+		for i := range vs {
+			vs[i] = &this.BlockMatchers[i]
+		}
+		s = append(s, "BlockMatchers: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ExemplarsResponseHints) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&hintspb.ExemplarsResponseHints{")
+	if this.QueriedBlocks != nil {
+		vs := make([]*Block, len(this.QueriedBlocks))
+		for i := range vs {
+			vs[i] = &this.QueriedBlocks[i]
+		}
+		s = append(s, "QueriedBlocks: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *MetricMetadataRequestHints) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&hintspb.MetricMetadataRequestHints{")
+	if this.BlockMatchers != nil {
+		vs := make([]*storepb.LabelMatcher, len(this.BlockMatchers))
+		for i := range vs {
+			vs[i] = &this.BlockMatchers[i]
+		}
+		s = append(s, "BlockMatchers: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *MetricMetadataResponseHints) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&hintspb.MetricMetadataResponseHints{")
+	if this.QueriedBlocks != nil {
+		vs := make([]*Block, len(this.QueriedBlocks))
+		for i := range vs {
+			vs[i] = &this.QueriedBlocks[i]
+		}
+		s = append(s, "QueriedBlocks: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringHints(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -893,6 +1231,154 @@ func (m *LabelValuesResponseHints) MarshalToSizedBuffer(dAtA []byte) (int, error
 	return len(dAtA) - i, nil
 }
 
+func (m *ExemplarsRequestHints) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExemplarsRequestHints) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExemplarsRequestHints) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.BlockMatchers) > 0 {
+		for iNdEx := len(m.BlockMatchers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.BlockMatchers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintHints(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExemplarsResponseHints) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExemplarsResponseHints) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExemplarsResponseHints) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.QueriedBlocks) > 0 {
+		for iNdEx := len(m.QueriedBlocks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.QueriedBlocks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintHints(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MetricMetadataRequestHints) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MetricMetadataRequestHints) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MetricMetadataRequestHints) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.BlockMatchers) > 0 {
+		for iNdEx := len(m.BlockMatchers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.BlockMatchers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintHints(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MetricMetadataResponseHints) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MetricMetadataResponseHints) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MetricMetadataResponseHints) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.QueriedBlocks) > 0 {
+		for iNdEx := len(m.QueriedBlocks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.QueriedBlocks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintHints(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintHints(dAtA []byte, offset int, v uint64) int {
 	offset -= sovHints(v)
 	base := offset
@@ -1007,6 +1493,66 @@ func (m *LabelValuesResponseHints) Size() (n int) {
 	return n
 }
 
+func (m *ExemplarsRequestHints) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.BlockMatchers) > 0 {
+		for _, e := range m.BlockMatchers {
+			l = e.Size()
+			n += 1 + l + sovHints(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ExemplarsResponseHints) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.QueriedBlocks) > 0 {
+		for _, e := range m.QueriedBlocks {
+			l = e.Size()
+			n += 1 + l + sovHints(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *MetricMetadataRequestHints) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.BlockMatchers) > 0 {
+		for _, e := range m.BlockMatchers {
+			l = e.Size()
+			n += 1 + l + sovHints(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *MetricMetadataResponseHints) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.QueriedBlocks) > 0 {
+		for _, e := range m.QueriedBlocks {
+			l = e.Size()
+			n += 1 + l + sovHints(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovHints(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1113,6 +1659,66 @@ func (this *LabelValuesResponseHints) String() string {
 	}, "")
 	return s
 }
+func (this *ExemplarsRequestHints) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForBlockMatchers := "[]LabelMatcher{"
+	for _, f := range this.BlockMatchers {
+		repeatedStringForBlockMatchers += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForBlockMatchers += "}"
+	s := strings.Join([]string{`&ExemplarsRequestHints{`,
+		`BlockMatchers:` + repeatedStringForBlockMatchers + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ExemplarsResponseHints) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForQueriedBlocks := "[]Block{"
+	for _, f := range this.QueriedBlocks {
+		repeatedStringForQueriedBlocks += strings.Replace(strings.Replace(f.String(), "Block", "Block", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForQueriedBlocks += "}"
+	s := strings.Join([]string{`&ExemplarsResponseHints{`,
+		`QueriedBlocks:` + repeatedStringForQueriedBlocks + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *MetricMetadataRequestHints) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForBlockMatchers := "[]LabelMatcher{"
+	for _, f := range this.BlockMatchers {
+		repeatedStringForBlockMatchers += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForBlockMatchers += "}"
+	s := strings.Join([]string{`&MetricMetadataRequestHints{`,
+		`BlockMatchers:` + repeatedStringForBlockMatchers + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *MetricMetadataResponseHints) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForQueriedBlocks := "[]Block{"
+	for _, f := range this.QueriedBlocks {
+		repeatedStringForQueriedBlocks += strings.Replace(strings.Replace(f.String(), "Block", "Block", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForQueriedBlocks += "}"
+	s := strings.Join([]string{`&MetricMetadataResponseHints{`,
+		`QueriedBlocks:` + repeatedStringForQueriedBlocks + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringHints(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -1728,6 +2334,354 @@ func (m *LabelValuesResponseHints) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ExemplarsRequestHints) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHints
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExemplarsRequestHints: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExemplarsRequestHints: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockMatchers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHints
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHints
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthHints
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockMatchers = append(m.BlockMatchers, storepb.LabelMatcher{})
+			if err := m.BlockMatchers[len(m.BlockMatchers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHints(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHints
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthHints
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExemplarsResponseHints) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHints
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExemplarsResponseHints: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExemplarsResponseHints: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueriedBlocks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHints
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHints
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthHints
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QueriedBlocks = append(m.QueriedBlocks, Block{})
+			if err := m.QueriedBlocks[len(m.QueriedBlocks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHints(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHints
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthHints
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MetricMetadataRequestHints) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHints
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MetricMetadataRequestHints: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MetricMetadataRequestHints: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockMatchers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHints
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHints
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthHints
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockMatchers = append(m.BlockMatchers, storepb.LabelMatcher{})
+			if err := m.BlockMatchers[len(m.BlockMatchers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHints(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHints
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthHints
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MetricMetadataResponseHints) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHints
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MetricMetadataResponseHints: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MetricMetadataResponseHints: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueriedBlocks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHints
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHints
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthHints
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QueriedBlocks = append(m.QueriedBlocks, Block{})
+			if err := m.QueriedBlocks[len(m.QueriedBlocks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHints(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHints
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthHints
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipHints(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
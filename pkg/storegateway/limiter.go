@@ -6,6 +6,7 @@
 package storegateway
 
 import (
+	"io"
 	"net/http"
 	"sync"
 
@@ -13,9 +14,30 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/atomic"
 
+	"github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/util/globalerror"
 	"github.com/grafana/mimir/pkg/util/limiter"
 )
 
+var (
+	maxExemplarsBlocksPerQueryMsgFormat = globalerror.MaxExemplarsBlocksPerQuery.MessageWithPerInstanceLimitConfig(
+		"the query exceeded the maximum number of blocks with exemplars (limit: %d blocks)",
+		tsdb.MaxExemplarsBlocksPerQueryFlag,
+	)
+	maxExemplarsBytesPerQueryMsgFormat = globalerror.MaxExemplarsBytesPerQuery.MessageWithPerInstanceLimitConfig(
+		"the query exceeded the aggregated exemplars files size limit (limit: %d bytes)",
+		tsdb.MaxExemplarsBytesPerQueryFlag,
+	)
+	maxMetadataBlocksPerQueryMsgFormat = globalerror.MaxMetadataBlocksPerQuery.MessageWithPerInstanceLimitConfig(
+		"the query exceeded the maximum number of blocks with metric metadata (limit: %d blocks)",
+		tsdb.MaxMetadataBlocksPerQueryFlag,
+	)
+	maxMetadataBytesPerQueryMsgFormat = globalerror.MaxMetadataBytesPerQuery.MessageWithPerInstanceLimitConfig(
+		"the query exceeded the aggregated metric metadata files size limit (limit: %d bytes)",
+		tsdb.MaxMetadataBytesPerQueryFlag,
+	)
+)
+
 type ChunksLimiter interface {
 	// Reserve num chunks out of the total number of chunks enforced by the limiter.
 	// Returns an error if the limit has been exceeded. This function must be
@@ -80,3 +102,22 @@ func NewSeriesLimiterFactory(limitsExtractor func() uint64) SeriesLimiterFactory
 		return NewLimiter(limitsExtractor(), failedCounter, limiter.MaxSeriesHitMsgFormat)
 	}
 }
+
+// limitingReader reserves the bytes read from the wrapped reader out of the limiter, failing the read
+// once the limit has been exceeded.
+type limitingReader struct {
+	r       io.Reader
+	limiter *Limiter
+}
+
+func (r *limitingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		// The bytes exceeding the limit are not returned, so that the caller can't successfully
+		// complete the read by ignoring the error.
+		if limitErr := r.limiter.Reserve(uint64(n)); limitErr != nil {
+			return 0, limitErr
+		}
+	}
+	return n, err
+}
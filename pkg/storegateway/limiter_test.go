@@ -6,7 +6,9 @@
 package storegateway
 
 import (
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -56,3 +58,22 @@ func newStaticSeriesLimiterFactory(limit uint64) SeriesLimiterFactory {
 		return limit
 	})
 }
+
+func TestLimitingReader(t *testing.T) {
+	c := promauto.With(nil).NewCounter(prometheus.CounterOpts{})
+	r := &limitingReader{r: strings.NewReader("0123456789"), limiter: NewLimiter(5, c, "limit of %v exceeded")}
+
+	buf := make([]byte, 4)
+	n, err := r.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+
+	// The bytes exceeding the limit are not returned.
+	n, err = r.Read(buf)
+	assert.ErrorContains(t, err, "limit of 5 exceeded")
+	assert.Equal(t, 0, n)
+	assert.Equal(t, float64(1), prom_testutil.ToFloat64(c))
+
+	_, err = io.ReadAll(&limitingReader{r: strings.NewReader("0123456789"), limiter: NewLimiter(0, c, "limit of %v exceeded")})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,413 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamaggr
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+const (
+	flushCheckInterval = time.Second
+
+	// An interval is flushed once this period, or the rule interval if shorter, has elapsed after its end, so that
+	// the samples sent late, for example because of remote write retries, are still aggregated into it.
+	maxFlushDelay = time.Minute
+
+	// The state of an input series is kept for at least this period after its last sample, so that the
+	// increase of counters which skip an interval is not lost.
+	seriesStaleTimeout = 5 * time.Minute
+)
+
+// Limits are the per-tenant limits configuring the streaming aggregation.
+type Limits interface {
+	StreamingAggregationRules(userID string) []*Rule
+}
+
+// PushFunc ingests the output series of a tenant.
+type PushFunc func(ctx context.Context, userID string, series []mimirpb.PreallocTimeseries) error
+
+// Aggregator aggregates the samples of the input series of the tenants' streaming aggregation rules, and
+// periodically pushes the aggregated output series. The input series are routed to the ingesters owning the
+// output series, so that each of them receives all the samples of the output series it owns. The samples are
+// aggregated over the rule intervals their timestamps belong to, so that the replicas of an output series
+// compute the same values. A nil Aggregator is valid, and aggregates nothing.
+type Aggregator struct {
+	services.Service
+
+	rules  *Rules
+	push   PushFunc
+	logger log.Logger
+
+	mtx     sync.Mutex
+	tenants map[string]*tenantState
+
+	inputSamples     *prometheus.CounterVec
+	discardedSamples *prometheus.CounterVec
+	outputSamples    *prometheus.CounterVec
+	pushFailures     *prometheus.CounterVec
+	trackedSeries    *prometheus.GaugeVec
+}
+
+type tenantState struct {
+	mtx   sync.Mutex
+	rules map[string]*ruleState
+}
+
+type ruleState struct {
+	rule *rule
+
+	// End of the last flushed interval, in milliseconds. The samples of the flushed intervals are discarded.
+	flushedUntil int64
+
+	series map[string]*seriesState
+
+	// The aggregations of the finished input series, by interval end and output series.
+	pending map[int64]map[string]*outputGroup
+}
+
+// seriesState is the state of an input series of a rule.
+type seriesState struct {
+	output    labels.Labels
+	outputKey string
+
+	lastSeen time.Time
+	lastTs   int64
+	last     float64
+	hasLast  bool
+
+	// Aggregated over the interval ending at intervalEnd, 0 if the series has no sample in an unfinished interval.
+	intervalEnd int64
+	min, max    float64
+	increase    float64
+}
+
+type outputGroup struct {
+	labels labels.Labels
+	value  float64
+}
+
+// NewAggregator creates a new Aggregator.
+func NewAggregator(rules *Rules, push PushFunc, logger log.Logger, reg prometheus.Registerer) *Aggregator {
+	a := &Aggregator{
+		rules:   rules,
+		push:    push,
+		logger:  logger,
+		tenants: map[string]*tenantState{},
+
+		inputSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_streaming_aggregation_input_samples_total",
+			Help: "The total number of samples aggregated by the streaming aggregation rules.",
+		}, []string{"user"}),
+		discardedSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_streaming_aggregation_discarded_samples_total",
+			Help: "The total number of input samples discarded by the streaming aggregation rules, because they were duplicated, out of order or received after their interval was flushed.",
+		}, []string{"user"}),
+		outputSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_streaming_aggregation_output_samples_total",
+			Help: "The total number of aggregated samples pushed by the streaming aggregation rules.",
+		}, []string{"user"}),
+		pushFailures: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_streaming_aggregation_push_failures_total",
+			Help: "The total number of failed pushes of aggregated samples.",
+		}, []string{"user"}),
+		trackedSeries: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cortex_ingester_streaming_aggregation_tracked_series",
+			Help: "The number of input series tracked by the streaming aggregation rules.",
+		}, []string{"user"}),
+	}
+	a.Service = services.NewTimerService(flushCheckInterval, nil, a.iteration, nil).WithName("streaming aggregation")
+	return a
+}
+
+func (a *Aggregator) iteration(ctx context.Context) error {
+	a.Flush(ctx, time.Now())
+	return nil
+}
+
+// Aggregate aggregates the float samples of the input series of the tenant's rule identified by ruleKey.
+func (a *Aggregator) Aggregate(userID, ruleKey string, series []mimirpb.PreallocTimeseries, now time.Time) {
+	if a == nil || len(series) == 0 {
+		return
+	}
+
+	var input, discarded int
+	defer func() {
+		if input > 0 {
+			a.inputSamples.WithLabelValues(userID).Add(float64(input))
+		}
+		if discarded > 0 {
+			a.discardedSamples.WithLabelValues(userID).Add(float64(discarded))
+		}
+	}()
+
+	r := a.rules.rule(userID, ruleKey)
+	if r == nil {
+		// The rule has been removed since the distributor routed the series.
+		for _, ts := range series {
+			discarded += len(ts.Samples)
+		}
+		return
+	}
+
+	t := a.tenant(userID)
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	rs := t.rules[ruleKey]
+	if rs == nil {
+		rs = &ruleState{
+			rule:         r,
+			flushedUntil: r.flushUntil(now),
+			series:       map[string]*seriesState{},
+			pending:      map[int64]map[string]*outputGroup{},
+		}
+		t.rules[ruleKey] = rs
+	}
+
+	for _, ts := range series {
+		lbls := mimirpb.FromLabelAdaptersToLabels(ts.Labels)
+		key := string(lbls.Bytes(nil))
+
+		s := rs.series[key]
+		if s == nil {
+			// The labels are backed by the request buffer, so they're copied before being retained.
+			output := r.outputLabels(mimirpb.FromLabelAdaptersToLabelsWithCopy(ts.Labels))
+			s = &seriesState{output: output, outputKey: string(output.Bytes(nil))}
+			rs.series[key] = s
+		}
+
+		for _, sample := range ts.Samples {
+			if rs.add(s, sample.TimestampMs, sample.Value, now) {
+				input++
+			} else {
+				discarded++
+			}
+		}
+	}
+}
+
+func (a *Aggregator) tenant(userID string) *tenantState {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	t := a.tenants[userID]
+	if t == nil {
+		t = &tenantState{rules: map[string]*ruleState{}}
+		a.tenants[userID] = t
+	}
+	return t
+}
+
+// RemoveTenant removes the state of the tenant, and its metrics.
+func (a *Aggregator) RemoveTenant(userID string) {
+	if a == nil {
+		return
+	}
+
+	a.mtx.Lock()
+	delete(a.tenants, userID)
+	a.mtx.Unlock()
+
+	a.inputSamples.DeleteLabelValues(userID)
+	a.discardedSamples.DeleteLabelValues(userID)
+	a.outputSamples.DeleteLabelValues(userID)
+	a.pushFailures.DeleteLabelValues(userID)
+	a.trackedSeries.DeleteLabelValues(userID)
+}
+
+// Flush pushes the output series of the intervals which ended, including the flush delay, by now.
+// The partial aggregations of the unfinished intervals are kept.
+func (a *Aggregator) Flush(ctx context.Context, now time.Time) {
+	a.mtx.Lock()
+	userIDs := make([]string, 0, len(a.tenants))
+	tenants := make([]*tenantState, 0, len(a.tenants))
+	for userID, t := range a.tenants {
+		userIDs = append(userIDs, userID)
+		tenants = append(tenants, t)
+	}
+	a.mtx.Unlock()
+
+	for idx, t := range tenants {
+		userID := userIDs[idx]
+		if len(a.rules.limits.StreamingAggregationRules(userID)) == 0 {
+			// The streaming aggregation has been disabled for the tenant.
+			a.RemoveTenant(userID)
+			continue
+		}
+
+		output, tracked := t.flush(a.rules, userID, now)
+		a.trackedSeries.WithLabelValues(userID).Set(float64(tracked))
+		if len(output) == 0 {
+			continue
+		}
+
+		if err := a.push(ctx, userID, output); err != nil {
+			a.pushFailures.WithLabelValues(userID).Inc()
+			level.Warn(a.logger).Log("msg", "failed to push streaming aggregation output series", "user", userID, "series", len(output), "err", err)
+			continue
+		}
+		a.outputSamples.WithLabelValues(userID).Add(float64(len(output)))
+	}
+}
+
+// flush returns the output series of the intervals which ended by now, and the number of tracked input series.
+func (t *tenantState) flush(rules *Rules, userID string, now time.Time) ([]mimirpb.PreallocTimeseries, int) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	var (
+		output  []mimirpb.PreallocTimeseries
+		tracked int
+	)
+
+	for key, rs := range t.rules {
+		r := rules.rule(userID, key)
+		if r == nil {
+			// The rule has been removed.
+			delete(t.rules, key)
+			continue
+		}
+		rs.rule = r
+
+		output = append(output, rs.flush(now)...)
+		tracked += len(rs.series)
+	}
+	return output, tracked
+}
+
+// flush returns the output series of the intervals which ended by now, oldest first.
+func (rs *ruleState) flush(now time.Time) []mimirpb.PreallocTimeseries {
+	until := rs.rule.flushUntil(now)
+	if until <= rs.flushedUntil {
+		return nil
+	}
+
+	staleTimeout := seriesStaleTimeout
+	if 2*rs.rule.interval > staleTimeout {
+		staleTimeout = 2 * rs.rule.interval
+	}
+
+	for key, s := range rs.series {
+		if s.intervalEnd != 0 && s.intervalEnd <= until {
+			rs.finish(s)
+		}
+		if s.intervalEnd == 0 && now.Sub(s.lastSeen) > staleTimeout {
+			delete(rs.series, key)
+		}
+	}
+	rs.flushedUntil = until
+
+	ends := make([]int64, 0, len(rs.pending))
+	for end := range rs.pending {
+		if end <= until {
+			ends = append(ends, end)
+		}
+	}
+	sort.Slice(ends, func(i, j int) bool { return ends[i] < ends[j] })
+
+	var output []mimirpb.PreallocTimeseries
+	for _, end := range ends {
+		for _, g := range rs.pending[end] {
+			value := g.value
+			if rs.rule.output == OutputRate {
+				value /= rs.rule.interval.Seconds()
+			}
+			output = append(output, mimirpb.PreallocTimeseries{TimeSeries: &mimirpb.TimeSeries{
+				Labels:  mimirpb.FromLabelsToLabelAdapters(g.labels),
+				Samples: []mimirpb.Sample{{TimestampMs: end, Value: value}},
+			}})
+		}
+		delete(rs.pending, end)
+	}
+	return output
+}
+
+// add aggregates a sample of the input series into the interval its timestamp belongs to, and returns
+// whether the sample has been aggregated.
+func (rs *ruleState) add(s *seriesState, timestampMs int64, value float64, now time.Time) bool {
+	// The samples received again, for example when a write request is retried, and the out of order
+	// samples are discarded.
+	if s.hasLast && timestampMs <= s.lastTs {
+		return false
+	}
+
+	end := rs.rule.intervalEnd(timestampMs)
+	if s.intervalEnd != 0 && s.intervalEnd != end {
+		rs.finish(s)
+	}
+
+	// Counter resets are detected like in PromQL: the whole value after the reset is the increase.
+	increase := 0.0
+	if s.hasLast {
+		if value >= s.last {
+			increase = value - s.last
+		} else {
+			increase = value
+		}
+	}
+	s.lastTs, s.last, s.hasLast = timestampMs, value, true
+	s.lastSeen = now
+
+	if end <= rs.flushedUntil {
+		// The interval has already been flushed. The sample is still the base of the next increase.
+		return false
+	}
+
+	if s.intervalEnd == 0 {
+		s.intervalEnd = end
+		s.min, s.max, s.increase = value, value, 0
+	}
+	s.min = math.Min(s.min, value)
+	s.max = math.Max(s.max, value)
+	s.increase += increase
+	return true
+}
+
+// finish aggregates the interval of the input series into its output series.
+func (rs *ruleState) finish(s *seriesState) {
+	groups := rs.pending[s.intervalEnd]
+	if groups == nil {
+		groups = map[string]*outputGroup{}
+		rs.pending[s.intervalEnd] = groups
+	}
+
+	g := groups[s.outputKey]
+	if g == nil {
+		g = &outputGroup{labels: s.output}
+		switch rs.rule.output {
+		case OutputMin:
+			g.value = math.Inf(1)
+		case OutputMax:
+			g.value = math.Inf(-1)
+		}
+		groups[s.outputKey] = g
+	}
+
+	switch rs.rule.output {
+	case OutputSum:
+		g.value += s.last
+	case OutputCount:
+		g.value++
+	case OutputMin:
+		g.value = math.Min(g.value, s.min)
+	case OutputMax:
+		g.value = math.Max(g.value, s.max)
+	case OutputIncrease, OutputRate:
+		g.value += s.increase
+	}
+
+	s.intervalEnd = 0
+}
@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamaggr
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+type rulesLimits map[string][]*Rule
+
+func (l rulesLimits) StreamingAggregationRules(userID string) []*Rule {
+	return l[userID]
+}
+
+type outputSample struct {
+	labels    string
+	timestamp int64
+	value     float64
+}
+
+type pushRecorder struct {
+	pushed map[string][]outputSample
+}
+
+func (r *pushRecorder) push(_ context.Context, userID string, series []mimirpb.PreallocTimeseries) error {
+	for _, ts := range series {
+		for _, s := range ts.Samples {
+			r.pushed[userID] = append(r.pushed[userID], outputSample{
+				labels:    mimirpb.FromLabelAdaptersToLabels(ts.Labels).String(),
+				timestamp: s.TimestampMs,
+				value:     s.Value,
+			})
+		}
+	}
+	sort.Slice(r.pushed[userID], func(i, j int) bool {
+		return r.pushed[userID][i].labels < r.pushed[userID][j].labels
+	})
+	return nil
+}
+
+func makeSeries(timestamp time.Time, value float64, lbls ...string) []mimirpb.PreallocTimeseries {
+	return []mimirpb.PreallocTimeseries{{TimeSeries: &mimirpb.TimeSeries{
+		Labels:  mimirpb.FromLabelsToLabelAdapters(labels.FromStrings(lbls...)),
+		Samples: []mimirpb.Sample{{TimestampMs: timestamp.UnixMilli(), Value: value}},
+	}}}
+}
+
+// ruleKey returns the key of the tenant's rule at the index.
+func ruleKey(t *testing.T, limits Limits, userID string, idx int) string {
+	rules, err := parseRules(limits.StreamingAggregationRules(userID))
+	require.NoError(t, err)
+	return rules[idx].key
+}
+
+func TestAggregator(t *testing.T) {
+	var (
+		start = time.Unix(600, 0)
+		end   = start.Add(time.Minute)
+	)
+
+	tests := map[string]struct {
+		output   string
+		expected []outputSample
+	}{
+		OutputSum: {
+			output: OutputSum,
+			expected: []outputSample{
+				{labels: `{__name__="requests_total:out", job="api"}`, timestamp: end.UnixMilli(), value: 30 + 15},
+				{labels: `{__name__="requests_total:out", job="db"}`, timestamp: end.UnixMilli(), value: 100},
+			},
+		},
+		OutputCount: {
+			output: OutputCount,
+			expected: []outputSample{
+				{labels: `{__name__="requests_total:out", job="api"}`, timestamp: end.UnixMilli(), value: 2},
+				{labels: `{__name__="requests_total:out", job="db"}`, timestamp: end.UnixMilli(), value: 1},
+			},
+		},
+		OutputMin: {
+			output: OutputMin,
+			expected: []outputSample{
+				{labels: `{__name__="requests_total:out", job="api"}`, timestamp: end.UnixMilli(), value: 5},
+				{labels: `{__name__="requests_total:out", job="db"}`, timestamp: end.UnixMilli(), value: 100},
+			},
+		},
+		OutputMax: {
+			output: OutputMax,
+			expected: []outputSample{
+				{labels: `{__name__="requests_total:out", job="api"}`, timestamp: end.UnixMilli(), value: 30},
+				{labels: `{__name__="requests_total:out", job="db"}`, timestamp: end.UnixMilli(), value: 100},
+			},
+		},
+		OutputIncrease: {
+			output: OutputIncrease,
+			expected: []outputSample{
+				// The second series is reset from 20 to 5, and then increases to 15.
+				{labels: `{__name__="requests_total:out", job="api"}`, timestamp: end.UnixMilli(), value: 20 + 5 + 10},
+				{labels: `{__name__="requests_total:out", job="db"}`, timestamp: end.UnixMilli(), value: 0},
+			},
+		},
+		OutputRate: {
+			output: OutputRate,
+			expected: []outputSample{
+				{labels: `{__name__="requests_total:out", job="api"}`, timestamp: end.UnixMilli(), value: float64(20+5+10) / 60},
+				{labels: `{__name__="requests_total:out", job="db"}`, timestamp: end.UnixMilli(), value: 0},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			limits := rulesLimits{"user-1": {{Match: `requests_total{env="prod"}`, By: []string{"job"}, Output: tc.output, Suffix: ":out"}}}
+			recorder := &pushRecorder{pushed: map[string][]outputSample{}}
+			a := NewAggregator(NewRules(limits), recorder.push, log.NewNopLogger(), nil)
+			key := ruleKey(t, limits, "user-1", 0)
+
+			// The samples are aggregated by timestamp, whenever they're received.
+			for idx, series := range [][]mimirpb.PreallocTimeseries{
+				makeSeries(start, 10, model.MetricNameLabel, "requests_total", "env", "prod", "job", "api", "pod", "1"),
+				makeSeries(start, 20, model.MetricNameLabel, "requests_total", "env", "prod", "job", "api", "pod", "2"),
+				makeSeries(start, 100, model.MetricNameLabel, "requests_total", "env", "prod", "job", "db", "pod", "1"),
+				makeSeries(start.Add(10*time.Second), 30, model.MetricNameLabel, "requests_total", "env", "prod", "job", "api", "pod", "1"),
+				makeSeries(start.Add(10*time.Second), 5, model.MetricNameLabel, "requests_total", "env", "prod", "job", "api", "pod", "2"),
+				makeSeries(start.Add(20*time.Second), 15, model.MetricNameLabel, "requests_total", "env", "prod", "job", "api", "pod", "2"),
+			} {
+				a.Aggregate("user-1", key, series, start.Add(time.Duration(idx)*time.Second))
+			}
+
+			// Nothing is pushed until the interval ends, plus the flush delay.
+			a.Flush(context.Background(), end.Add(time.Minute-time.Second))
+			assert.Empty(t, recorder.pushed)
+
+			a.Flush(context.Background(), end.Add(time.Minute))
+			assert.Equal(t, tc.expected, recorder.pushed["user-1"])
+
+			// The series without samples in the next interval are not pushed.
+			recorder.pushed = map[string][]outputSample{}
+			a.Flush(context.Background(), end.Add(2*time.Minute))
+			assert.Empty(t, recorder.pushed)
+		})
+	}
+}
+
+func TestAggregator_IncreaseAcrossIntervals(t *testing.T) {
+	var (
+		start  = time.Unix(600, 0)
+		limits = rulesLimits{"user-1": {{Match: `requests_total`, Output: OutputIncrease, Suffix: ":increase"}}}
+	)
+
+	recorder := &pushRecorder{pushed: map[string][]outputSample{}}
+	a := NewAggregator(NewRules(limits), recorder.push, log.NewNopLogger(), nil)
+	key := ruleKey(t, limits, "user-1", 0)
+
+	// The samples of the next interval finish the previous one, and their increase is computed from its last sample.
+	a.Aggregate("user-1", key, makeSeries(start, 10, model.MetricNameLabel, "requests_total", "pod", "1"), start)
+	a.Aggregate("user-1", key, makeSeries(start.Add(time.Minute), 25, model.MetricNameLabel, "requests_total", "pod", "1"), start)
+	a.Flush(context.Background(), start.Add(3*time.Minute))
+
+	assert.Equal(t, []outputSample{
+		{labels: `{__name__="requests_total:increase"}`, timestamp: start.Add(time.Minute).UnixMilli(), value: 0},
+		{labels: `{__name__="requests_total:increase"}`, timestamp: start.Add(2 * time.Minute).UnixMilli(), value: 15},
+	}, recorder.pushed["user-1"])
+}
+
+func TestAggregator_DiscardedSamples(t *testing.T) {
+	var (
+		start  = time.Unix(600, 0)
+		limits = rulesLimits{"user-1": {{Match: `requests_total`, Output: OutputSum, Suffix: ":sum"}}}
+	)
+
+	recorder := &pushRecorder{pushed: map[string][]outputSample{}}
+	a := NewAggregator(NewRules(limits), recorder.push, log.NewNopLogger(), nil)
+	key := ruleKey(t, limits, "user-1", 0)
+
+	a.Aggregate("user-1", key, makeSeries(start, 10, model.MetricNameLabel, "requests_total", "pod", "1"), start)
+
+	// The samples received again, for example because a write request is retried, are discarded.
+	a.Aggregate("user-1", key, makeSeries(start, 10, model.MetricNameLabel, "requests_total", "pod", "1"), start)
+
+	// The samples of the rules which don't exist anymore are discarded.
+	a.Aggregate("user-1", "unknown", makeSeries(start, 10, model.MetricNameLabel, "requests_total", "pod", "1"), start)
+
+	a.Flush(context.Background(), start.Add(2*time.Minute))
+	assert.Equal(t, []outputSample{
+		{labels: `{__name__="requests_total:sum"}`, timestamp: start.Add(time.Minute).UnixMilli(), value: 10},
+	}, recorder.pushed["user-1"])
+
+	// The samples of the intervals already flushed are discarded.
+	a.Aggregate("user-1", key, makeSeries(start.Add(30*time.Second), 10, model.MetricNameLabel, "requests_total", "pod", "2"), start.Add(2*time.Minute))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(a.inputSamples.WithLabelValues("user-1")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(a.discardedSamples.WithLabelValues("user-1")))
+}
+
+func TestAggregator_RulesReload(t *testing.T) {
+	var (
+		start  = time.Unix(600, 0)
+		limits = rulesLimits{"user-1": {{Match: `requests_total`, Output: OutputCount, Suffix: ":count"}}}
+	)
+
+	recorder := &pushRecorder{pushed: map[string][]outputSample{}}
+	a := NewAggregator(NewRules(limits), recorder.push, log.NewNopLogger(), nil)
+	a.Aggregate("user-1", ruleKey(t, limits, "user-1", 0), makeSeries(start, 1, model.MetricNameLabel, "requests_total", "pod", "1"), start)
+
+	// A new rule doesn't reset the state of the unchanged rules.
+	limits["user-1"] = []*Rule{
+		{Match: `requests_total`, Output: OutputCount, Suffix: ":count"},
+		{Match: `requests_total`, Output: OutputMax, Suffix: ":max"},
+	}
+	a.Aggregate("user-1", ruleKey(t, limits, "user-1", 0), makeSeries(start, 2, model.MetricNameLabel, "requests_total", "pod", "2"), start)
+	a.Aggregate("user-1", ruleKey(t, limits, "user-1", 1), makeSeries(start, 2, model.MetricNameLabel, "requests_total", "pod", "2"), start)
+
+	a.Flush(context.Background(), start.Add(2*time.Minute))
+	assert.Equal(t, []outputSample{
+		{labels: `{__name__="requests_total:count"}`, timestamp: start.Add(time.Minute).UnixMilli(), value: 2},
+		{labels: `{__name__="requests_total:max"}`, timestamp: start.Add(time.Minute).UnixMilli(), value: 2},
+	}, recorder.pushed["user-1"])
+
+	// Once the rules are removed, the tenant's state is removed too.
+	delete(limits, "user-1")
+	a.Flush(context.Background(), start.Add(3*time.Minute))
+	require.Empty(t, a.tenants)
+}
@@ -0,0 +1,265 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamaggr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// The aggregations supported by the rules.
+const (
+	OutputSum      = "sum"
+	OutputCount    = "count"
+	OutputMin      = "min"
+	OutputMax      = "max"
+	OutputIncrease = "increase"
+	OutputRate     = "rate"
+)
+
+const (
+	defaultInterval = time.Minute
+	minInterval     = time.Second
+)
+
+// Rule configures the streaming aggregation of the series matching a selector. The float samples received for
+// the matching series during each interval are aggregated into one sample per output series, named after the
+// input metric name followed by the suffix, and grouped by the configured labels.
+type Rule struct {
+	// Match is the series selector of the input series, for example `http_requests_total{env="prod"}`.
+	Match string `yaml:"match" json:"match"`
+	// By are the labels the output series are grouped by. Mutually exclusive with Without. If both are empty,
+	// all the input series with the same metric name are aggregated into one output series.
+	By []string `yaml:"by,omitempty" json:"by,omitempty"`
+	// Without are the labels removed from the output series. Mutually exclusive with By.
+	Without []string `yaml:"without,omitempty" json:"without,omitempty"`
+	// Output is the aggregation: sum, count, min, max, increase or rate.
+	Output string `yaml:"output" json:"output"`
+	// Interval is the period the samples are aggregated over. Defaults to 1 minute.
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// Suffix is appended to the input metric name to build the output metric name.
+	Suffix string `yaml:"suffix" json:"suffix"`
+	// DropInput drops the input series once aggregated, so that they're not ingested.
+	DropInput bool `yaml:"drop_input,omitempty" json:"drop_input,omitempty"`
+}
+
+// rule is a parsed Rule.
+type rule struct {
+	// key identifies the rule across configuration reloads.
+	key string
+
+	matchers  []*labels.Matcher
+	by        []string
+	without   []string
+	output    string
+	interval  time.Duration
+	suffix    string
+	dropInput bool
+}
+
+// ValidateRules returns an error if any of the rules is invalid.
+func ValidateRules(rules []*Rule) error {
+	_, err := parseRules(rules)
+	return err
+}
+
+func parseRules(rules []*Rule) ([]*rule, error) {
+	parsed := make([]*rule, 0, len(rules))
+	for idx, r := range rules {
+		p, err := parseRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid streaming aggregation rule #%d: %w", idx, err)
+		}
+		parsed = append(parsed, p)
+	}
+	return parsed, nil
+}
+
+func parseRule(r *Rule) (*rule, error) {
+	if r == nil {
+		return nil, errors.New("empty rule")
+	}
+
+	matchers, err := parser.ParseMetricSelector(r.Match)
+	if err != nil {
+		return nil, fmt.Errorf("invalid match %q: %w", r.Match, err)
+	}
+
+	if len(r.By) > 0 && len(r.Without) > 0 {
+		return nil, errors.New("by and without are mutually exclusive")
+	}
+	for _, names := range [][]string{r.By, r.Without} {
+		for _, name := range names {
+			if !model.LabelName(name).IsValid() || name == model.MetricNameLabel {
+				return nil, fmt.Errorf("invalid label name %q", name)
+			}
+		}
+	}
+
+	switch r.Output {
+	case OutputSum, OutputCount, OutputMin, OutputMax, OutputIncrease, OutputRate:
+	default:
+		return nil, fmt.Errorf("unsupported output %q", r.Output)
+	}
+
+	interval := time.Duration(r.Interval)
+	if interval == 0 {
+		interval = defaultInterval
+	}
+	if interval < minInterval {
+		return nil, fmt.Errorf("the interval must be at least %s", minInterval)
+	}
+
+	if r.Suffix == "" || !model.IsValidMetricName(model.LabelValue("metric"+r.Suffix)) {
+		return nil, fmt.Errorf("invalid suffix %q", r.Suffix)
+	}
+
+	return &rule{
+		key:       fmt.Sprintf("%s|%v|%v|%s|%s|%s", r.Match, r.By, r.Without, r.Output, interval, r.Suffix),
+		matchers:  matchers,
+		by:        r.By,
+		without:   r.Without,
+		output:    r.Output,
+		interval:  interval,
+		suffix:    r.Suffix,
+		dropInput: r.DropInput,
+	}, nil
+}
+
+// matches returns whether the series is an input of the rule.
+func (r *rule) matches(series labels.Labels) bool {
+	for _, m := range r.matchers {
+		if !m.Matches(series.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// outputLabels returns the labels of the output series the input series is aggregated into.
+func (r *rule) outputLabels(series labels.Labels) labels.Labels {
+	b := labels.NewBuilder(labels.EmptyLabels())
+	if len(r.without) > 0 {
+		b.Reset(series)
+		b.Del(r.without...)
+	} else {
+		for _, name := range r.by {
+			b.Set(name, series.Get(name))
+		}
+	}
+
+	b.Set(model.MetricNameLabel, series.Get(model.MetricNameLabel)+r.suffix)
+	return b.Labels()
+}
+
+// intervalEnd returns the end of the interval the timestamp belongs to, in milliseconds. Intervals are aligned
+// to the Unix epoch, so that all the ingesters aggregating a series agree on them.
+func (r *rule) intervalEnd(timestampMs int64) int64 {
+	intervalMs := r.interval.Milliseconds()
+	start := timestampMs - timestampMs%intervalMs
+	if timestampMs < 0 && timestampMs%intervalMs != 0 {
+		start -= intervalMs
+	}
+	return start + intervalMs
+}
+
+// flushUntil returns the end of the last interval which can be flushed at now, in milliseconds.
+func (r *rule) flushUntil(now time.Time) int64 {
+	delay := maxFlushDelay
+	if r.interval < delay {
+		delay = r.interval
+	}
+	return r.intervalEnd(now.Add(-delay).UnixMilli()) - r.interval.Milliseconds()
+}
+
+// Rules are the parsed streaming aggregation rules of the tenants, re-parsed only when they change.
+type Rules struct {
+	limits Limits
+
+	mtx     sync.Mutex
+	tenants map[string]*tenantRules
+}
+
+type tenantRules struct {
+	raw   []*Rule
+	rules []*rule
+	byKey map[string]*rule
+}
+
+// NewRules returns the streaming aggregation rules of the tenants configured in limits.
+func NewRules(limits Limits) *Rules {
+	return &Rules{
+		limits:  limits,
+		tenants: map[string]*tenantRules{},
+	}
+}
+
+// Route calls f for each of the tenant's rules the series is an input of, with the key identifying the rule and
+// the labels of the output series it's aggregated into. The samples of an input series must be aggregated where
+// the output series is ingested, so that one place sees all the samples of each output series. Route returns
+// whether the series must not be ingested, because a matching rule drops its input series.
+func (r *Rules) Route(userID string, series labels.Labels, f func(ruleKey string, output labels.Labels)) (drop bool) {
+	t := r.tenant(userID)
+	if t == nil {
+		return false
+	}
+
+	for _, rl := range t.rules {
+		if !rl.matches(series) {
+			continue
+		}
+		f(rl.key, rl.outputLabels(series))
+		drop = drop || rl.dropInput
+	}
+	return drop
+}
+
+// rule returns the tenant's rule with the given key, or nil if the tenant has no such rule.
+func (r *Rules) rule(userID, key string) *rule {
+	t := r.tenant(userID)
+	if t == nil {
+		return nil
+	}
+	return t.byKey[key]
+}
+
+func (r *Rules) tenant(userID string) *tenantRules {
+	raw := r.limits.StreamingAggregationRules(userID)
+
+	r.mtx.Lock()
+	t := r.tenants[userID]
+	if len(raw) == 0 && t != nil {
+		delete(r.tenants, userID)
+	}
+	r.mtx.Unlock()
+
+	if len(raw) == 0 {
+		return nil
+	}
+	if t != nil && reflect.DeepEqual(t.raw, raw) {
+		return t
+	}
+
+	// The rules have been validated when loaded, so this should never happen.
+	parsed, err := parseRules(raw)
+	if err != nil {
+		return nil
+	}
+
+	t = &tenantRules{raw: raw, rules: parsed, byKey: make(map[string]*rule, len(parsed))}
+	for _, rl := range parsed {
+		t.byKey[rl.key] = rl
+	}
+
+	r.mtx.Lock()
+	r.tenants[userID] = t
+	r.mtx.Unlock()
+	return t
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamaggr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRules(t *testing.T) {
+	tests := map[string]struct {
+		rule        *Rule
+		expectedErr string
+	}{
+		"valid rule": {
+			rule: &Rule{Match: `http_requests_total{env="prod"}`, By: []string{"job"}, Output: OutputRate, Interval: model.Duration(time.Minute), Suffix: ":job:rate1m"},
+		},
+		"default interval": {
+			rule: &Rule{Match: `http_requests_total`, Without: []string{"pod"}, Output: OutputSum, Suffix: ":sum"},
+		},
+		"empty rule": {
+			expectedErr: "empty rule",
+		},
+		"invalid match": {
+			rule:        &Rule{Match: `http_requests_total{`, Output: OutputSum, Suffix: ":sum"},
+			expectedErr: "invalid match",
+		},
+		"by and without": {
+			rule:        &Rule{Match: `http_requests_total`, By: []string{"job"}, Without: []string{"pod"}, Output: OutputSum, Suffix: ":sum"},
+			expectedErr: "by and without are mutually exclusive",
+		},
+		"invalid label name": {
+			rule:        &Rule{Match: `http_requests_total`, By: []string{"job-name"}, Output: OutputSum, Suffix: ":sum"},
+			expectedErr: `invalid label name "job-name"`,
+		},
+		"metric name label": {
+			rule:        &Rule{Match: `http_requests_total`, Without: []string{model.MetricNameLabel}, Output: OutputSum, Suffix: ":sum"},
+			expectedErr: `invalid label name "__name__"`,
+		},
+		"unsupported output": {
+			rule:        &Rule{Match: `http_requests_total`, Output: "avg", Suffix: ":avg"},
+			expectedErr: `unsupported output "avg"`,
+		},
+		"interval too short": {
+			rule:        &Rule{Match: `http_requests_total`, Output: OutputSum, Interval: model.Duration(time.Millisecond), Suffix: ":sum"},
+			expectedErr: "the interval must be at least 1s",
+		},
+		"empty suffix": {
+			rule:        &Rule{Match: `http_requests_total`, Output: OutputSum},
+			expectedErr: `invalid suffix ""`,
+		},
+		"invalid suffix": {
+			rule:        &Rule{Match: `http_requests_total`, Output: OutputSum, Suffix: "-sum"},
+			expectedErr: `invalid suffix "-sum"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateRules([]*Rule{tc.rule})
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestRule_outputLabels(t *testing.T) {
+	series := labels.FromStrings(model.MetricNameLabel, "http_requests_total", "job", "api", "pod", "api-1", "status", "200")
+
+	tests := map[string]struct {
+		rule     *Rule
+		expected labels.Labels
+	}{
+		"by": {
+			rule:     &Rule{Match: "http_requests_total", By: []string{"job", "missing"}, Output: OutputRate, Suffix: ":job:rate1m"},
+			expected: labels.FromStrings(model.MetricNameLabel, "http_requests_total:job:rate1m", "job", "api"),
+		},
+		"without": {
+			rule:     &Rule{Match: "http_requests_total", Without: []string{"pod"}, Output: OutputRate, Suffix: ":rate1m"},
+			expected: labels.FromStrings(model.MetricNameLabel, "http_requests_total:rate1m", "job", "api", "status", "200"),
+		},
+		"no grouping": {
+			rule:     &Rule{Match: "http_requests_total", Output: OutputCount, Suffix: ":count"},
+			expected: labels.FromStrings(model.MetricNameLabel, "http_requests_total:count"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := parseRule(tc.rule)
+			require.NoError(t, err)
+			require.True(t, r.matches(series))
+			assert.Equal(t, tc.expected, r.outputLabels(series))
+		})
+	}
+}
+
+func TestRule_intervalEnd(t *testing.T) {
+	r, err := parseRule(&Rule{Match: "http_requests_total", Output: OutputCount, Interval: model.Duration(time.Minute), Suffix: ":count"})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(60_000), r.intervalEnd(0))
+	assert.Equal(t, int64(60_000), r.intervalEnd(59_999))
+	assert.Equal(t, int64(120_000), r.intervalEnd(60_000))
+	assert.Equal(t, int64(0), r.intervalEnd(-1))
+
+	// The intervals ending more than the flush delay ago can be flushed.
+	assert.Equal(t, int64(60_000), r.flushUntil(time.UnixMilli(179_999)))
+	assert.Equal(t, int64(120_000), r.flushUntil(time.UnixMilli(180_000)))
+}
+
+func TestRules_Route(t *testing.T) {
+	limits := rulesLimits{"user-1": {
+		{Match: `requests_total`, Output: OutputSum, Suffix: ":sum"},
+		{Match: `requests_total{job="api"}`, By: []string{"job"}, Output: OutputCount, Suffix: ":count", DropInput: true},
+	}}
+	rules := NewRules(limits)
+
+	route := func(userID string, series labels.Labels) (bool, []string) {
+		var outputs []string
+		drop := rules.Route(userID, series, func(ruleKey string, output labels.Labels) {
+			require.NotNil(t, rules.rule(userID, ruleKey))
+			outputs = append(outputs, output.String())
+		})
+		return drop, outputs
+	}
+
+	drop, outputs := route("user-1", labels.FromStrings(model.MetricNameLabel, "requests_total", "job", "api", "pod", "1"))
+	assert.True(t, drop)
+	assert.Equal(t, []string{`{__name__="requests_total:sum"}`, `{__name__="requests_total:count", job="api"}`}, outputs)
+
+	drop, outputs = route("user-1", labels.FromStrings(model.MetricNameLabel, "requests_total", "job", "db", "pod", "1"))
+	assert.False(t, drop)
+	assert.Equal(t, []string{`{__name__="requests_total:sum"}`}, outputs)
+
+	drop, outputs = route("user-2", labels.FromStrings(model.MetricNameLabel, "requests_total", "job", "api", "pod", "1"))
+	assert.False(t, drop)
+	assert.Empty(t, outputs)
+}
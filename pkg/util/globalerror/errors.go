@@ -14,24 +14,31 @@ type ID string
 const (
 	errPrefix = "err-mimir-"
 
-	MissingMetricName             ID = "missing-metric-name"
-	InvalidMetricName             ID = "metric-name-invalid"
-	MaxLabelNamesPerSeries        ID = "max-label-names-per-series"
-	MaxNativeHistogramBuckets     ID = "max-native-histogram-buckets"
-	SeriesInvalidLabel            ID = "label-invalid"
-	SeriesLabelNameTooLong        ID = "label-name-too-long"
-	SeriesLabelValueTooLong       ID = "label-value-too-long"
-	SeriesWithDuplicateLabelNames ID = "duplicate-label-names"
-	SeriesLabelsNotSorted         ID = "labels-not-sorted"
-	SampleTooFarInFuture          ID = "too-far-in-future"
-	MaxSeriesPerMetric            ID = "max-series-per-metric"
-	MaxMetadataPerMetric          ID = "max-metadata-per-metric"
-	MaxSeriesPerUser              ID = "max-series-per-user"
-	MaxMetadataPerUser            ID = "max-metadata-per-user"
-	MaxChunksPerQuery             ID = "max-chunks-per-query"
-	MaxSeriesPerQuery             ID = "max-series-per-query"
-	MaxChunkBytesPerQuery         ID = "max-chunks-bytes-per-query"
-	MaxEstimatedChunksPerQuery    ID = "max-estimated-chunks-per-query"
+	MissingMetricName                     ID = "missing-metric-name"
+	InvalidMetricName                     ID = "metric-name-invalid"
+	MaxLabelNamesPerSeries                ID = "max-label-names-per-series"
+	MaxNativeHistogramBuckets             ID = "max-native-histogram-buckets"
+	SeriesInvalidLabel                    ID = "label-invalid"
+	SeriesLabelNameTooLong                ID = "label-name-too-long"
+	SeriesLabelValueTooLong               ID = "label-value-too-long"
+	SeriesWithDuplicateLabelNames         ID = "duplicate-label-names"
+	SeriesLabelsNotSorted                 ID = "labels-not-sorted"
+	SampleTooFarInFuture                  ID = "too-far-in-future"
+	NativeHistogramCustomBuckets          ID = "native-histogram-custom-buckets-unsupported"
+	MaxSeriesPerMetric                    ID = "max-series-per-metric"
+	MaxMetadataPerMetric                  ID = "max-metadata-per-metric"
+	MaxSeriesPerUser                      ID = "max-series-per-user"
+	MaxMetadataPerUser                    ID = "max-metadata-per-user"
+	MaxEstimatedMemoryPerUser             ID = "max-estimated-memory-per-user"
+	MaxChunksPerQuery                     ID = "max-chunks-per-query"
+	MaxSeriesPerQuery                     ID = "max-series-per-query"
+	MaxChunkBytesPerQuery                 ID = "max-chunks-bytes-per-query"
+	MaxEstimatedChunksPerQuery            ID = "max-estimated-chunks-per-query"
+	MaxEstimatedMemoryConsumptionPerQuery ID = "max-estimated-memory-consumption-per-query"
+	MaxExemplarsBlocksPerQuery            ID = "max-exemplars-blocks-per-query"
+	MaxExemplarsBytesPerQuery             ID = "max-exemplars-bytes-per-query"
+	MaxMetadataBlocksPerQuery             ID = "max-metadata-blocks-per-query"
+	MaxMetadataBytesPerQuery              ID = "max-metadata-bytes-per-query"
 
 	DistributorMaxIngestionRate             ID = "distributor-max-ingestion-rate"
 	DistributorMaxInflightPushRequests      ID = "distributor-max-inflight-push-requests"
@@ -41,6 +48,7 @@ const (
 	IngesterMaxTenants              ID = "ingester-max-tenants"
 	IngesterMaxInMemorySeries       ID = "ingester-max-series"
 	IngesterMaxInflightPushRequests ID = "ingester-max-inflight-push-requests"
+	IngesterMaxEstimatedMemory      ID = "ingester-max-estimated-memory"
 
 	ExemplarLabelsMissing    ID = "exemplar-labels-missing"
 	ExemplarLabelsTooLong    ID = "exemplar-labels-too-long"
@@ -7,8 +7,10 @@ package util
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"html/template"
@@ -16,12 +18,14 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/grafana/dskit/flagext"
+	"github.com/klauspost/compress/zstd"
 	"github.com/opentracing/opentracing-go"
 	otlog "github.com/opentracing/opentracing-go/log"
 	"gopkg.in/yaml.v3"
@@ -138,8 +142,36 @@ type CompressionType int
 const (
 	NoCompression CompressionType = iota
 	RawSnappy
+	Gzip
+	Zstd
 )
 
+// String returns the Content-Encoding name of the compression type.
+func (c CompressionType) String() string {
+	switch c {
+	case NoCompression:
+		return "none"
+	case RawSnappy:
+		return "snappy"
+	case Gzip:
+		return "gzip"
+	case Zstd:
+		return "zstd"
+	}
+	return "unknown"
+}
+
+// ReadRequestBody reads and decompresses a request body from an io.Reader, enforcing the maxSize limit on
+// both the compressed and decompressed body.
+// You can pass in and receive back the decompression buffer for pooling, or pass in nil and ignore the return.
+func ReadRequestBody(ctx context.Context, reader io.Reader, expectedSize, maxSize int, dst []byte, compression CompressionType) ([]byte, error) {
+	sp := opentracing.SpanFromContext(ctx)
+	if sp != nil {
+		sp.LogFields(otlog.Event("util.ReadRequestBody[start reading]"))
+	}
+	return decompressRequest(dst, reader, expectedSize, maxSize, compression, sp)
+}
+
 // ParseProtoReader parses a compressed proto from an io.Reader.
 // You can pass in and receive back the decompression buffer for pooling, or pass in nil and ignore the return.
 func ParseProtoReader(ctx context.Context, reader io.Reader, expectedSize, maxSize int, dst []byte, req proto.Message, compression CompressionType) ([]byte, error) {
@@ -184,6 +216,10 @@ type MsgSizeTooLargeErr struct {
 }
 
 func (e MsgSizeTooLargeErr) Error() string {
+	// The actual size is unknown when the decompressed message is read in a streaming fashion.
+	if e.Actual < 0 {
+		return fmt.Sprintf("the request has been rejected because its size exceeds the limit of %d bytes", e.Limit)
+	}
 	return fmt.Sprintf("the request has been rejected because its size of %d bytes exceeds the limit of %d bytes", e.Actual, e.Limit)
 }
 
@@ -225,15 +261,12 @@ func decompressFromReader(dst []byte, reader io.Reader, expectedSize, maxSize in
 	// reader is over limit, the result will be bigger than max.
 	reader = io.LimitReader(reader, int64(maxSize)+1)
 	switch compression {
-	case NoCompression:
-		_, err = buf.ReadFrom(reader)
-		body = buf.Bytes()
-	case RawSnappy:
+	case NoCompression, RawSnappy, Gzip, Zstd:
 		_, err = buf.ReadFrom(reader)
 		if err != nil {
 			return nil, err
 		}
-		body, err = decompressFromBuffer(dst, &buf, maxSize, RawSnappy, sp)
+		body, err = decompressFromBuffer(dst, &buf, maxSize, compression, sp)
 	}
 	return body, err
 }
@@ -262,10 +295,88 @@ func decompressFromBuffer(dst []byte, buffer *bytes.Buffer, maxSize int, compres
 			return nil, err
 		}
 		return body, nil
+	case Gzip, Zstd:
+		return decompressStream(dst, bytes.NewReader(buffer.Bytes()), maxSize, compression, sp)
 	}
 	return nil, nil
 }
 
+// decompressStream decompresses a gzip or zstd stream. Since the decompressed size isn't known
+// upfront, the decompression stops as soon as the decompressed size exceeds maxSize.
+func decompressStream(dst []byte, reader io.Reader, maxSize int, compression CompressionType, sp opentracing.Span) ([]byte, error) {
+	if sp != nil {
+		sp.LogFields(otlog.Event("util.ParseProtoRequest[decompress]"), otlog.String("encoding", compression.String()))
+	}
+
+	var decompressed io.Reader
+	switch compression {
+	case Gzip:
+		gr, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		decompressed = gr
+	case Zstd:
+		zr, err := getZstdDecoder(maxSize)
+		if err != nil {
+			return nil, err
+		}
+		defer putZstdDecoder(maxSize, zr)
+		if err := zr.Reset(reader); err != nil {
+			return nil, err
+		}
+		decompressed = zr
+	default:
+		return nil, fmt.Errorf("unsupported streaming compression: %s", compression)
+	}
+
+	buf := bytes.NewBuffer(dst[:0])
+	if _, err := buf.ReadFrom(io.LimitReader(decompressed, int64(maxSize)+1)); err != nil {
+		if errors.Is(err, zstd.ErrDecoderSizeExceeded) || errors.Is(err, zstd.ErrWindowSizeExceeded) {
+			return nil, MsgSizeTooLargeErr{Actual: -1, Limit: maxSize}
+		}
+		return nil, err
+	}
+	if buf.Len() > maxSize {
+		return nil, MsgSizeTooLargeErr{Actual: -1, Limit: maxSize}
+	}
+	return buf.Bytes(), nil
+}
+
+// zstdDecoderPools stores a sync.Pool of zstd decoders for each max message size, because the memory
+// used by a decoder is capped when it's created.
+var zstdDecoderPools sync.Map
+
+// getZstdDecoder returns a zstd decoder whose memory and window size are capped to maxSize, so that
+// a stream can't make the decoder allocate more memory than the decompressed message is allowed to.
+// The decoder must be reset with the stream to decompress, and returned with putZstdDecoder.
+func getZstdDecoder(maxSize int) (*zstd.Decoder, error) {
+	pool, _ := zstdDecoderPools.LoadOrStore(maxSize, &sync.Pool{})
+	if zr, ok := pool.(*sync.Pool).Get().(*zstd.Decoder); ok {
+		return zr, nil
+	}
+
+	// The window size can't be lower than the zstd minimum, even if the message is smaller.
+	limit := uint64(max(maxSize, zstd.MinWindowSize))
+	return zstd.NewReader(nil,
+		zstd.WithDecoderConcurrency(1),
+		zstd.WithDecoderLowmem(true),
+		zstd.WithDecoderMaxMemory(limit),
+		zstd.WithDecoderMaxWindow(limit),
+	)
+}
+
+// putZstdDecoder releases the stream decompressed by the zstd decoder, and returns the decoder to the pool.
+func putZstdDecoder(maxSize int, zr *zstd.Decoder) {
+	if err := zr.Reset(nil); err != nil {
+		return
+	}
+	if pool, ok := zstdDecoderPools.Load(maxSize); ok {
+		pool.(*sync.Pool).Put(zr)
+	}
+}
+
 // tryBufferFromReader attempts to cast the reader to a `*bytes.Buffer` this is possible when using httpgrpc.
 // If it fails it will return nil and false.
 func tryBufferFromReader(reader io.Reader) (*bytes.Buffer, bool) {
@@ -7,6 +7,7 @@ package util_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"html/template"
 	"io"
@@ -18,6 +19,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -179,19 +182,25 @@ func TestParseProtoReader(t *testing.T) {
 		{"too big rawSnappy", util.RawSnappy, 10, true, false},
 		{"too big decoded rawSnappy", util.RawSnappy, 50, true, false},
 		{"too big noCompression", util.NoCompression, 10, true, false},
+		{"gzip", util.Gzip, 100, false, false},
+		{"zstd", util.Zstd, 100, false, false},
+		{"too big gzip", util.Gzip, 10, true, false},
+		{"too big zstd", util.Zstd, 10, true, false},
 
 		{"bytesbuffer rawSnappy", util.RawSnappy, 53, false, true},
 		{"bytesbuffer noCompression", util.NoCompression, 53, false, true},
 		{"bytesbuffer too big rawSnappy", util.RawSnappy, 10, true, true},
 		{"bytesbuffer too big decoded rawSnappy", util.RawSnappy, 50, true, true},
 		{"bytesbuffer too big noCompression", util.NoCompression, 10, true, true},
+		{"bytesbuffer gzip", util.Gzip, 100, false, true},
+		{"bytesbuffer zstd", util.Zstd, 100, false, true},
+		{"bytesbuffer too big gzip", util.Gzip, 10, true, true},
+		{"bytesbuffer too big zstd", util.Zstd, 10, true, true},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			w := httptest.NewRecorder()
-			assert.Nil(t, util.SerializeProtoResponse(w, req, tt.compression))
 			var fromWire mimirpb.PreallocWriteRequest
 
-			reader := w.Result().Body
+			reader := io.NopCloser(bytes.NewReader(compressProto(t, req, tt.compression)))
 			if tt.useBytesBuffer {
 				buf := bytes.Buffer{}
 				_, err := buf.ReadFrom(reader)
@@ -211,6 +220,35 @@ func TestParseProtoReader(t *testing.T) {
 	}
 }
 
+func compressProto(t *testing.T, msg proto.Message, compression util.CompressionType) []byte {
+	t.Helper()
+
+	switch compression {
+	case util.Gzip:
+		data, err := proto.Marshal(msg)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		_, err = gw.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+		return buf.Bytes()
+	case util.Zstd:
+		data, err := proto.Marshal(msg)
+		require.NoError(t, err)
+
+		zw, err := zstd.NewWriter(nil)
+		require.NoError(t, err)
+		defer zw.Close()
+		return zw.EncodeAll(data, nil)
+	}
+
+	w := httptest.NewRecorder()
+	require.NoError(t, util.SerializeProtoResponse(w, msg, compression))
+	return w.Body.Bytes()
+}
+
 type bytesBuffered struct {
 	*bytes.Buffer
 }
@@ -233,6 +271,61 @@ func TestNewMsgSizeTooLargeErr(t *testing.T) {
 	msg := `the request has been rejected because its size of 100 bytes exceeds the limit of 50 bytes`
 
 	assert.Equal(t, msg, err.Error())
+
+	err = util.MsgSizeTooLargeErr{Actual: -1, Limit: 50}
+	msg = `the request has been rejected because its size exceeds the limit of 50 bytes`
+
+	assert.Equal(t, msg, err.Error())
+}
+
+func TestReadRequestBody_ShouldEnforceMaxDecompressedSize(t *testing.T) {
+	for _, compression := range []util.CompressionType{util.Gzip, util.Zstd} {
+		t.Run(compression.String(), func(t *testing.T) {
+			// Compress a message whose decompressed size is much bigger than the compressed one.
+			msg := &mimirpb.MetricMetadata{Help: strings.Repeat("x", 20000)}
+			compressed := compressProto(t, msg, compression)
+
+			_, err := util.ReadRequestBody(context.Background(), bytes.NewReader(compressed), len(compressed), 1000, nil, compression)
+			require.Equal(t, util.MsgSizeTooLargeErr{Actual: -1, Limit: 1000}, err)
+
+			body, err := util.ReadRequestBody(context.Background(), bytes.NewReader(compressed), len(compressed), 100000, nil, compression)
+			require.NoError(t, err)
+			require.Len(t, body, msg.Size())
+		})
+	}
+}
+
+func TestReadRequestBody_ShouldReuseZstdDecoders(t *testing.T) {
+	// Decompress different messages with the same limit, so that the pooled decoders are reused.
+	for i := 0; i < 10; i++ {
+		msg := &mimirpb.MetricMetadata{Help: strings.Repeat(strconv.Itoa(i), 100*(i+1))}
+		compressed := compressProto(t, msg, util.Zstd)
+
+		body, err := util.ReadRequestBody(context.Background(), bytes.NewReader(compressed), len(compressed), 100000, nil, util.Zstd)
+		require.NoError(t, err)
+
+		var actual mimirpb.MetricMetadata
+		require.NoError(t, proto.Unmarshal(body, &actual))
+		require.Equal(t, msg, &actual)
+	}
+}
+
+func TestReadRequestBody_ShouldRejectZstdWindowBiggerThanMaxSize(t *testing.T) {
+	// Compress a stream declaring a window size bigger than the max message size, which would make the
+	// decoder allocate the whole window, even if the decompressed message is smaller than the limit.
+	var compressed bytes.Buffer
+	zw, err := zstd.NewWriter(&compressed, zstd.WithWindowSize(1<<20))
+	require.NoError(t, err)
+	_, err = zw.Write(bytes.Repeat([]byte("x"), 200000))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	_, err = util.ReadRequestBody(context.Background(), bytes.NewReader(compressed.Bytes()), compressed.Len(), 300000, nil, util.Zstd)
+	require.Equal(t, util.MsgSizeTooLargeErr{Actual: -1, Limit: 300000}, err)
+
+	body, err := util.ReadRequestBody(context.Background(), bytes.NewReader(compressed.Bytes()), compressed.Len(), 1<<20, nil, util.Zstd)
+	require.NoError(t, err)
+	require.Len(t, body, 200000)
 }
 
 func TestParseRequestFormWithoutConsumingBody(t *testing.T) {
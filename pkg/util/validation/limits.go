@@ -22,8 +22,10 @@ import (
 	"golang.org/x/time/rate"
 	"gopkg.in/yaml.v3"
 
+	"github.com/grafana/mimir/pkg/distributor/graphite"
 	"github.com/grafana/mimir/pkg/ingester/activeseries"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/streamaggr"
 )
 
 const (
@@ -31,6 +33,8 @@ const (
 	MaxMetadataPerMetricFlag                 = "ingester.max-global-metadata-per-metric"
 	MaxSeriesPerUserFlag                     = "ingester.max-global-series-per-user"
 	MaxMetadataPerUserFlag                   = "ingester.max-global-metadata-per-user"
+	MaxEstimatedMemoryPerUserFlag            = "ingester.max-global-estimated-memory-per-user"
+	SeriesEvictionIdleTimeoutFlag            = "ingester.series-eviction-idle-timeout"
 	MaxChunksPerQueryFlag                    = "querier.max-fetched-chunks-per-query"
 	MaxChunkBytesPerQueryFlag                = "querier.max-fetched-chunk-bytes-per-query"
 	MaxSeriesPerQueryFlag                    = "querier.max-fetched-series-per-query"
@@ -49,9 +53,12 @@ const (
 	IngestionRateFlag                        = "distributor.ingestion-rate-limit"
 	IngestionBurstSizeFlag                   = "distributor.ingestion-burst-size"
 	HATrackerMaxClustersFlag                 = "distributor.ha-tracker.max-clusters"
+	HASampleLevelDedupWindowFlag             = "distributor.ha-tracker.sample-level-dedup-window"
 	resultsCacheTTLFlag                      = "query-frontend.results-cache-ttl"
 	resultsCacheTTLForOutOfOrderWindowFlag   = "query-frontend.results-cache-ttl-for-out-of-order-time-window"
 	QueryIngestersWithinFlag                 = "querier.query-ingesters-within"
+	costAttributionLabelsFlag                = "validation.cost-attribution-labels"
+	influxFieldLabelFlag                     = "distributor.influx-field-label"
 
 	// MinCompactorPartialBlockDeletionDelay is the minimum partial blocks deletion delay that can be configured in Mimir.
 	MinCompactorPartialBlockDeletionDelay = 4 * time.Hour
@@ -68,29 +75,44 @@ func (e LimitError) Error() string {
 // limits via flags, or per-user limits via yaml config.
 type Limits struct {
 	// Distributor enforced limits.
-	RequestRate                                 float64             `yaml:"request_rate" json:"request_rate"`
-	RequestBurstSize                            int                 `yaml:"request_burst_size" json:"request_burst_size"`
-	IngestionRate                               float64             `yaml:"ingestion_rate" json:"ingestion_rate"`
-	IngestionBurstSize                          int                 `yaml:"ingestion_burst_size" json:"ingestion_burst_size"`
-	AcceptHASamples                             bool                `yaml:"accept_ha_samples" json:"accept_ha_samples"`
-	HAClusterLabel                              string              `yaml:"ha_cluster_label" json:"ha_cluster_label"`
-	HAReplicaLabel                              string              `yaml:"ha_replica_label" json:"ha_replica_label"`
-	HAMaxClusters                               int                 `yaml:"ha_max_clusters" json:"ha_max_clusters"`
-	DropLabels                                  flagext.StringSlice `yaml:"drop_labels" json:"drop_labels" category:"advanced"`
-	MaxLabelNameLength                          int                 `yaml:"max_label_name_length" json:"max_label_name_length"`
-	MaxLabelValueLength                         int                 `yaml:"max_label_value_length" json:"max_label_value_length"`
-	MaxLabelNamesPerSeries                      int                 `yaml:"max_label_names_per_series" json:"max_label_names_per_series"`
-	MaxMetadataLength                           int                 `yaml:"max_metadata_length" json:"max_metadata_length"`
-	MaxNativeHistogramBuckets                   int                 `yaml:"max_native_histogram_buckets" json:"max_native_histogram_buckets"`
-	CreationGracePeriod                         model.Duration      `yaml:"creation_grace_period" json:"creation_grace_period" category:"advanced"`
-	EnforceMetadataMetricName                   bool                `yaml:"enforce_metadata_metric_name" json:"enforce_metadata_metric_name" category:"advanced"`
-	IngestionTenantShardSize                    int                 `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
-	MetricRelabelConfigs                        []*relabel.Config   `yaml:"metric_relabel_configs,omitempty" json:"metric_relabel_configs,omitempty" doc:"nocli|description=List of metric relabel configurations. Note that in most situations, it is more effective to use metrics relabeling directly in the Prometheus server, e.g. remote_write.write_relabel_configs. Labels available during the relabeling phase and cleaned afterwards: __meta_tenant_id" category:"experimental"`
-	ServiceOverloadStatusCodeOnRateLimitEnabled bool                `yaml:"service_overload_status_code_on_rate_limit_enabled" json:"service_overload_status_code_on_rate_limit_enabled" category:"experimental"`
+	RequestRate                                 float64                `yaml:"request_rate" json:"request_rate"`
+	RequestBurstSize                            int                    `yaml:"request_burst_size" json:"request_burst_size"`
+	IngestionRate                               float64                `yaml:"ingestion_rate" json:"ingestion_rate"`
+	IngestionBurstSize                          int                    `yaml:"ingestion_burst_size" json:"ingestion_burst_size"`
+	AcceptHASamples                             bool                   `yaml:"accept_ha_samples" json:"accept_ha_samples"`
+	HAClusterLabel                              string                 `yaml:"ha_cluster_label" json:"ha_cluster_label"`
+	HAReplicaLabel                              string                 `yaml:"ha_replica_label" json:"ha_replica_label"`
+	HAMaxClusters                               int                    `yaml:"ha_max_clusters" json:"ha_max_clusters"`
+	HASampleLevelDedupWindow                    model.Duration         `yaml:"ha_sample_level_dedup_window" json:"ha_sample_level_dedup_window" category:"experimental"`
+	HASampleLevelDedupMaxSamplesPerSecond       float64                `yaml:"ha_sample_level_dedup_max_samples_per_second" json:"ha_sample_level_dedup_max_samples_per_second" category:"experimental"`
+	DropLabels                                  flagext.StringSlice    `yaml:"drop_labels" json:"drop_labels" category:"advanced"`
+	MaxLabelNameLength                          int                    `yaml:"max_label_name_length" json:"max_label_name_length"`
+	MaxLabelValueLength                         int                    `yaml:"max_label_value_length" json:"max_label_value_length"`
+	MaxLabelNamesPerSeries                      int                    `yaml:"max_label_names_per_series" json:"max_label_names_per_series"`
+	MaxMetadataLength                           int                    `yaml:"max_metadata_length" json:"max_metadata_length"`
+	MaxNativeHistogramBuckets                   int                    `yaml:"max_native_histogram_buckets" json:"max_native_histogram_buckets"`
+	ReduceNativeHistogramOverMaxBuckets         bool                   `yaml:"reduce_native_histogram_over_max_buckets" json:"reduce_native_histogram_over_max_buckets" category:"experimental"`
+	CreationGracePeriod                         model.Duration         `yaml:"creation_grace_period" json:"creation_grace_period" category:"advanced"`
+	EnforceMetadataMetricName                   bool                   `yaml:"enforce_metadata_metric_name" json:"enforce_metadata_metric_name" category:"advanced"`
+	IngestionTenantShardSize                    int                    `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
+	MetricRelabelConfigs                        []*relabel.Config      `yaml:"metric_relabel_configs,omitempty" json:"metric_relabel_configs,omitempty" doc:"nocli|description=List of metric relabel configurations. Note that in most situations, it is more effective to use metrics relabeling directly in the Prometheus server, e.g. remote_write.write_relabel_configs. Labels available during the relabeling phase and cleaned afterwards: __meta_tenant_id" category:"experimental"`
+	ServiceOverloadStatusCodeOnRateLimitEnabled bool                   `yaml:"service_overload_status_code_on_rate_limit_enabled" json:"service_overload_status_code_on_rate_limit_enabled" category:"experimental"`
+	OTelMetricSuffixesEnabled                   bool                   `yaml:"otel_metric_suffixes_enabled" json:"otel_metric_suffixes_enabled" category:"experimental"`
+	OTelTargetInfoEnabled                       bool                   `yaml:"otel_target_info_enabled" json:"otel_target_info_enabled" category:"experimental"`
+	PromoteOTelResourceAttributes               flagext.StringSliceCSV `yaml:"promote_otel_resource_attributes" json:"promote_otel_resource_attributes" category:"experimental"`
+	InfluxFieldLabel                            string                 `yaml:"influx_field_label" json:"influx_field_label" category:"experimental"`
+	InfluxTagLabelMapping                       map[string]string      `yaml:"influx_tag_label_mapping,omitempty" json:"influx_tag_label_mapping,omitempty" doc:"nocli|description=Map of InfluxDB tag keys to the names of the labels they're converted to. The tag keys not listed here are converted to label names by replacing the characters not allowed in label names with underscores." category:"experimental"`
+	StreamingAggregationRules                   []*streamaggr.Rule     `yaml:"streaming_aggregation_rules,omitempty" json:"streaming_aggregation_rules,omitempty" doc:"nocli|description=List of streaming aggregation rules. The float samples received for the series matching each rule are forwarded to the ingesters owning the output series, named after the input metric name followed by the rule suffix, which aggregate them over the rule interval their timestamp belongs to and ingest the output series. An interval is aggregated once the rule interval, up to 1 minute, has elapsed after its end. The samples received later are discarded." category:"experimental"`
+	GraphiteTemplates                           []string               `yaml:"graphite_templates,omitempty" json:"graphite_templates,omitempty" doc:"nocli|description=List of templates mapping Graphite metric paths to metric names and labels, in the \"[<filter>] <template> [<tags>]\" format, for example \"servers.* .host.measurement* region=eu\". The first template whose filter matches the metric path is applied. The metric paths not matching any template are converted to metric names by replacing dots with underscores." category:"experimental"`
 	// Ingester enforced limits.
 	// Series
 	MaxGlobalSeriesPerUser   int `yaml:"max_global_series_per_user" json:"max_global_series_per_user"`
 	MaxGlobalSeriesPerMetric int `yaml:"max_global_series_per_metric" json:"max_global_series_per_metric"`
+	// Series eviction
+	SeriesEvictionIdleTimeout model.Duration `yaml:"series_eviction_idle_timeout" json:"series_eviction_idle_timeout" category:"experimental"`
+	SeriesEvictionDryRun      bool           `yaml:"series_eviction_dry_run" json:"series_eviction_dry_run" category:"experimental"`
+	// Memory
+	MaxGlobalEstimatedMemoryPerUser int64 `yaml:"max_global_estimated_memory_per_user" json:"max_global_estimated_memory_per_user" category:"experimental"`
 	// Metadata
 	MaxGlobalMetricsWithMetadataPerUser int `yaml:"max_global_metadata_per_user" json:"max_global_metadata_per_user"`
 	MaxGlobalMetadataPerMetric          int `yaml:"max_global_metadata_per_metric" json:"max_global_metadata_per_metric"`
@@ -103,10 +125,16 @@ type Limits struct {
 	// Max allowed time window for out-of-order samples.
 	OutOfOrderTimeWindow                 model.Duration `yaml:"out_of_order_time_window" json:"out_of_order_time_window" category:"experimental"`
 	OutOfOrderBlocksExternalLabelEnabled bool           `yaml:"out_of_order_blocks_external_label_enabled" json:"out_of_order_blocks_external_label_enabled" category:"experimental"`
+	// Created timestamps
+	CreatedTimestampZeroIngestionEnabled bool `yaml:"created_timestamp_zero_ingestion_enabled" json:"created_timestamp_zero_ingestion_enabled" category:"experimental"`
 
 	// User defined label to give the option of subdividing specific metrics by another label
 	SeparateMetricsGroupLabel string `yaml:"separate_metrics_group_label" json:"separate_metrics_group_label" category:"experimental"`
 
+	// Cost attribution
+	CostAttributionLabels                flagext.StringSliceCSV `yaml:"cost_attribution_labels" json:"cost_attribution_labels" category:"experimental"`
+	MaxCostAttributionCardinalityPerUser int                    `yaml:"max_cost_attribution_cardinality_per_user" json:"max_cost_attribution_cardinality_per_user" category:"experimental"`
+
 	// Querier enforced limits.
 	MaxChunksPerQuery                    int            `yaml:"max_fetched_chunks_per_query" json:"max_fetched_chunks_per_query"`
 	MaxEstimatedChunksPerQueryMultiplier float64        `yaml:"max_estimated_fetched_chunks_per_query_multiplier" json:"max_estimated_fetched_chunks_per_query_multiplier" category:"experimental"`
@@ -196,19 +224,29 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.StringVar(&l.HAClusterLabel, "distributor.ha-tracker.cluster", "cluster", "Prometheus label to look for in samples to identify a Prometheus HA cluster.")
 	f.StringVar(&l.HAReplicaLabel, "distributor.ha-tracker.replica", "__replica__", "Prometheus label to look for in samples to identify a Prometheus HA replica.")
 	f.IntVar(&l.HAMaxClusters, HATrackerMaxClustersFlag, 100, "Maximum number of clusters that HA tracker will keep track of for a single tenant. 0 to disable the limit.")
+	f.Var(&l.HASampleLevelDedupWindow, HASampleLevelDedupWindowFlag, "If greater than 0, the samples received from a non-elected replica are forwarded to the ingesters, which append them to the existing series that haven't been received from the elected replica in this period, instead of being deduplicated. The ingesters must track the active series, and the value must not be greater than -ingester.active-series-metrics-idle-timeout. 0 to deduplicate all the samples of the non-elected replicas.")
+	f.Float64Var(&l.HASampleLevelDedupMaxSamplesPerSecond, "distributor.ha-tracker.sample-level-dedup-max-samples-per-second", 10000, "Maximum number of samples per second, across all distributors, received from the non-elected replicas of a tenant and forwarded to the ingesters for the sample-level deduplication. The samples of the non-elected replicas above this rate are deduplicated. 0 to disable the limit.")
 	f.Var(&l.DropLabels, "distributor.drop-label", "This flag can be used to specify label names that to drop during sample ingestion within the distributor and can be repeated in order to drop multiple labels.")
 	f.IntVar(&l.MaxLabelNameLength, MaxLabelNameLengthFlag, 1024, "Maximum length accepted for label names")
 	f.IntVar(&l.MaxLabelValueLength, MaxLabelValueLengthFlag, 2048, "Maximum length accepted for label value. This setting also applies to the metric name")
 	f.IntVar(&l.MaxLabelNamesPerSeries, MaxLabelNamesPerSeriesFlag, 30, "Maximum number of label names per series.")
 	f.IntVar(&l.MaxMetadataLength, MaxMetadataLengthFlag, 1024, "Maximum length accepted for metric metadata. Metadata refers to Metric Name, HELP and UNIT. Longer metadata is dropped except for HELP which is truncated.")
 	f.IntVar(&l.MaxNativeHistogramBuckets, maxNativeHistogramBucketsFlag, 0, "Maximum number of buckets per native histogram sample. 0 to disable the limit.")
+	f.BoolVar(&l.ReduceNativeHistogramOverMaxBuckets, "validation.reduce-native-histogram-over-max-buckets", true, "Whether to reduce or reject native histogram samples with more buckets than the configured limit. If enabled, the resolution of the histogram is reduced until the number of buckets fits the limit, and the sample is rejected only if the limit can't be met at the lowest resolution. This applies to native histograms received via remote write and to exponential histograms received via OTLP.")
 	_ = l.CreationGracePeriod.Set("10m")
 	f.Var(&l.CreationGracePeriod, CreationGracePeriodFlag, "Controls how far into the future incoming samples and exemplars are accepted compared to the wall clock. Any sample or exemplar will be rejected if its timestamp is greater than '(now + grace_period)'. This configuration is enforced in the distributor, ingester and query-frontend (to avoid querying too far into the future).")
 	f.BoolVar(&l.EnforceMetadataMetricName, "validation.enforce-metadata-metric-name", true, "Enforce every metadata has a metric name.")
 	f.BoolVar(&l.ServiceOverloadStatusCodeOnRateLimitEnabled, "distributor.service-overload-status-code-on-rate-limit-enabled", false, "If enabled, rate limit errors will be reported to the client with HTTP status code 529 (Service is overloaded). If disabled, status code 429 (Too Many Requests) is used.")
+	f.BoolVar(&l.OTelMetricSuffixesEnabled, "distributor.otel-metric-suffixes-enabled", false, "If enabled, the names of the metrics received via OTLP are normalized following the Prometheus naming conventions, adding the unit and type suffixes (for example _seconds or _total). If disabled, only the characters not allowed in Prometheus metric names are replaced.")
+	f.BoolVar(&l.OTelTargetInfoEnabled, "distributor.otel-target-info-enabled", true, "If enabled, a target_info series holding the resource attributes is generated for each OTLP resource.")
+	f.Var(&l.PromoteOTelResourceAttributes, "distributor.otel-promote-resource-attributes", "Comma-separated list of OTLP resource attributes to add as labels to every series of the resource. The attributes are converted to label names following the Prometheus conventions, and don't override the data point attributes with the same name.")
+	f.StringVar(&l.InfluxFieldLabel, influxFieldLabelFlag, "", "Name of the label holding the field key of the samples received via the InfluxDB line protocol, whose metric name is the measurement. If empty, the metric name is \"<measurement>_<field key>\", or just the measurement for the field \"value\".")
 
 	f.IntVar(&l.MaxGlobalSeriesPerUser, MaxSeriesPerUserFlag, 150000, "The maximum number of in-memory series per tenant, across the cluster before replication. 0 to disable.")
 	f.IntVar(&l.MaxGlobalSeriesPerMetric, MaxSeriesPerMetricFlag, 0, "The maximum number of in-memory series per metric name, across the cluster before replication. 0 to disable.")
+	f.Var(&l.SeriesEvictionIdleTimeout, SeriesEvictionIdleTimeoutFlag, "When a tenant reaches the per-tenant series limit, new series are admitted as long as there are in-memory series which haven't received samples for at least this period, and those idle series are evicted from memory by compacting the TSDB head. Each new series is charged against the idle series until they're evicted. Compacting the TSDB head cuts a block, which costs CPU, disk I/O and an upload to the object storage, so the idle series of a tenant are evicted at most once per this period. The value must not be greater than -ingester.active-series-metrics-idle-timeout. 0 to disable.")
+	f.BoolVar(&l.SeriesEvictionDryRun, "ingester.series-eviction-dry-run", false, "If enabled, the series eviction only reports the idle series which would have been evicted, without admitting new series over the per-tenant series limit nor evicting any series.")
+	f.Int64Var(&l.MaxGlobalEstimatedMemoryPerUser, MaxEstimatedMemoryPerUserFlag, 0, "The maximum estimated memory, in bytes, of the in-memory series of a tenant, across the cluster before replication, including the series labels, postings, head chunks and out-of-order chunks. Like the per-tenant series limit, each ingester enforces its share of the limit. Requests to create additional series are rejected. 0 to disable.")
 
 	f.IntVar(&l.MaxGlobalMetricsWithMetadataPerUser, MaxMetadataPerUserFlag, 0, "The maximum number of in-memory metrics with metadata per tenant, across the cluster. 0 to disable.")
 	f.IntVar(&l.MaxGlobalMetadataPerMetric, MaxMetadataPerMetricFlag, 0, "The maximum number of metadata per metric, across the cluster. 0 to disable.")
@@ -216,8 +254,11 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&l.ActiveSeriesCustomTrackersConfig, "ingester.active-series-custom-trackers", "Additional active series metrics, matching the provided matchers. Matchers should be in form <name>:<matcher>, like 'foobar:{foo=\"bar\"}'. Multiple matchers can be provided either providing the flag multiple times or providing multiple semicolon-separated values to a single flag.")
 	f.Var(&l.OutOfOrderTimeWindow, "ingester.out-of-order-time-window", fmt.Sprintf("Non-zero value enables out-of-order support for most recent samples that are within the time window in relation to the TSDB's maximum time, i.e., within [db.maxTime-timeWindow, db.maxTime]). The ingester will need more memory as a factor of rate of out-of-order samples being ingested and the number of series that are getting out-of-order samples. If query falls into this window, cached results will use value from -%s option to specify TTL for resulting cache entry.", resultsCacheTTLForOutOfOrderWindowFlag))
 	f.BoolVar(&l.NativeHistogramsIngestionEnabled, "ingester.native-histograms-ingestion-enabled", false, "Enable ingestion of native histogram samples. If false, native histogram samples are ignored without an error. To query native histograms with query-sharding enabled make sure to set -query-frontend.query-result-response-format to 'protobuf'.")
+	f.BoolVar(&l.CreatedTimestampZeroIngestionEnabled, "ingester.created-timestamp-zero-ingestion-enabled", false, "Enable the ingestion of a zero sample at the created timestamp of counters, histograms and summaries, received via OTLP or Remote-Write 2.0, so that the first increase of series which have just been created or reset is not lost. The zero sample is not ingested if it conflicts with existing samples.")
 	f.BoolVar(&l.OutOfOrderBlocksExternalLabelEnabled, "ingester.out-of-order-blocks-external-label-enabled", false, "Whether the shipper should label out-of-order blocks with an external label before uploading them. Setting this label will compact out-of-order blocks separately from non-out-of-order blocks")
 
+	f.Var(&l.CostAttributionLabels, costAttributionLabelsFlag, "Comma-separated list of labels used to break down the tenant's received and discarded samples in the distributor, and the tenant's active series in the ingester. Series without a label are accounted with an empty value for that label. The metrics are exported with the tenant ID in the 'user' label, so 'user' and 'reason' are not valid cost attribution labels.")
+	f.IntVar(&l.MaxCostAttributionCardinalityPerUser, "validation.max-cost-attribution-cardinality-per-user", 10000, "Maximum number of distinct combinations of cost attribution label values tracked per tenant. Once the limit is reached, the samples and series of new combinations are accounted with all cost attribution labels set to '__overflow__'. 0 to disable the limit.")
 	f.StringVar(&l.SeparateMetricsGroupLabel, "validation.separate-metrics-group-label", "", "Label used to define the group label for metrics separation. For each write request, the group is obtained from the first non-empty group label from the first timeseries in the incoming list of timeseries. Specific distributor and ingester metrics will be further separated adding a 'group' label with group label's value. Currently applies to the following metrics: cortex_discarded_samples_total")
 
 	f.IntVar(&l.MaxChunksPerQuery, MaxChunksPerQueryFlag, 2e6, "Maximum number of chunks that can be fetched in a single query from ingesters and long-term storage. This limit is enforced in the querier, ruler and store-gateway. 0 to disable.")
@@ -359,6 +400,30 @@ func (l *Limits) validate() error {
 		return errors.New("invalid value for -" + MaxEstimatedChunksPerQueryMultiplierFlag + ": must be 0 or greater than or equal to 1")
 	}
 
+	if l.InfluxFieldLabel != "" && (!model.LabelName(l.InfluxFieldLabel).IsValid() || l.InfluxFieldLabel == model.MetricNameLabel) {
+		return fmt.Errorf("invalid value for -%s: %q is not a valid label name", influxFieldLabelFlag, l.InfluxFieldLabel)
+	}
+
+	for tag, name := range l.InfluxTagLabelMapping {
+		if !model.LabelName(name).IsValid() || name == model.MetricNameLabel {
+			return fmt.Errorf("invalid influx_tag_label_mapping: tag %q is mapped to %q, which is not a valid label name", tag, name)
+		}
+	}
+
+	if err := streamaggr.ValidateRules(l.StreamingAggregationRules); err != nil {
+		return err
+	}
+
+	if _, err := graphite.ParseTemplates(l.GraphiteTemplates); err != nil {
+		return fmt.Errorf("invalid graphite_templates: %w", err)
+	}
+
+	for _, name := range l.CostAttributionLabels {
+		if !model.LabelName(name).IsValid() || name == "user" || name == "reason" {
+			return fmt.Errorf("invalid value for -%s: %q is not a valid cost attribution label", costAttributionLabelsFlag, name)
+		}
+	}
+
 	return nil
 }
 
@@ -465,6 +530,41 @@ func (o *Overrides) DropLabels(userID string) flagext.StringSlice {
 	return o.getOverridesForUser(userID).DropLabels
 }
 
+// OTelMetricSuffixesEnabled returns whether to add unit and type suffixes to the names of the metrics received via OTLP.
+func (o *Overrides) OTelMetricSuffixesEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).OTelMetricSuffixesEnabled
+}
+
+// OTelTargetInfoEnabled returns whether to generate a target_info series for each OTLP resource.
+func (o *Overrides) OTelTargetInfoEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).OTelTargetInfoEnabled
+}
+
+// PromoteOTelResourceAttributes returns the OTLP resource attributes to add as labels to every series of the resource.
+func (o *Overrides) PromoteOTelResourceAttributes(userID string) []string {
+	return o.getOverridesForUser(userID).PromoteOTelResourceAttributes
+}
+
+// InfluxFieldLabel returns the name of the label holding the field key of the samples received via the InfluxDB line protocol.
+func (o *Overrides) InfluxFieldLabel(userID string) string {
+	return o.getOverridesForUser(userID).InfluxFieldLabel
+}
+
+// InfluxTagLabelMapping returns the map of InfluxDB tag keys to label names.
+func (o *Overrides) InfluxTagLabelMapping(userID string) map[string]string {
+	return o.getOverridesForUser(userID).InfluxTagLabelMapping
+}
+
+// StreamingAggregationRules returns the rules aggregating the samples of the tenant's series on ingestion.
+func (o *Overrides) StreamingAggregationRules(userID string) []*streamaggr.Rule {
+	return o.getOverridesForUser(userID).StreamingAggregationRules
+}
+
+// GraphiteTemplates returns the templates mapping Graphite metric paths to metric names and labels.
+func (o *Overrides) GraphiteTemplates(userID string) []string {
+	return o.getOverridesForUser(userID).GraphiteTemplates
+}
+
 // MaxLabelNameLength returns maximum length a label name can be.
 func (o *Overrides) MaxLabelNameLength(userID string) int {
 	return o.getOverridesForUser(userID).MaxLabelNameLength
@@ -493,6 +593,12 @@ func (o *Overrides) MaxNativeHistogramBuckets(userID string) int {
 	return o.getOverridesForUser(userID).MaxNativeHistogramBuckets
 }
 
+// ReduceNativeHistogramOverMaxBuckets returns whether to reduce the resolution of native histogram
+// samples with more buckets than the configured limit, instead of rejecting them.
+func (o *Overrides) ReduceNativeHistogramOverMaxBuckets(userID string) bool {
+	return o.getOverridesForUser(userID).ReduceNativeHistogramOverMaxBuckets
+}
+
 // CreationGracePeriod is misnamed, and actually returns how far into the future
 // we should accept samples.
 func (o *Overrides) CreationGracePeriod(userID string) time.Duration {
@@ -504,6 +610,23 @@ func (o *Overrides) MaxGlobalSeriesPerUser(userID string) int {
 	return o.getOverridesForUser(userID).MaxGlobalSeriesPerUser
 }
 
+// SeriesEvictionIdleTimeout returns the period after which the in-memory series of a user which haven't received
+// samples can be evicted to make room for new series, once the user reached the series limit.
+func (o *Overrides) SeriesEvictionIdleTimeout(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).SeriesEvictionIdleTimeout)
+}
+
+// SeriesEvictionDryRun returns whether the series eviction only reports the series which would have been evicted.
+func (o *Overrides) SeriesEvictionDryRun(userID string) bool {
+	return o.getOverridesForUser(userID).SeriesEvictionDryRun
+}
+
+// MaxGlobalEstimatedMemoryPerUser returns the maximum estimated memory, in bytes, of the in-memory series of a user
+// across the cluster before replication.
+func (o *Overrides) MaxGlobalEstimatedMemoryPerUser(userID string) int64 {
+	return o.getOverridesForUser(userID).MaxGlobalEstimatedMemoryPerUser
+}
+
 // MaxGlobalSeriesPerMetric returns the maximum number of series allowed per metric across the cluster.
 func (o *Overrides) MaxGlobalSeriesPerMetric(userID string) int {
 	return o.getOverridesForUser(userID).MaxGlobalSeriesPerMetric
@@ -642,6 +765,17 @@ func (o *Overrides) OutOfOrderBlocksExternalLabelEnabled(userID string) bool {
 	return o.getOverridesForUser(userID).OutOfOrderBlocksExternalLabelEnabled
 }
 
+// CostAttributionLabels returns the labels used to break down the tenant's samples and active series.
+func (o *Overrides) CostAttributionLabels(userID string) []string {
+	return o.getOverridesForUser(userID).CostAttributionLabels
+}
+
+// MaxCostAttributionCardinalityPerUser returns the maximum number of distinct cost attribution
+// label values combinations tracked for the tenant.
+func (o *Overrides) MaxCostAttributionCardinalityPerUser(userID string) int {
+	return o.getOverridesForUser(userID).MaxCostAttributionCardinalityPerUser
+}
+
 // SeparateMetricsGroupLabel returns the custom label used to separate specific metrics
 func (o *Overrides) SeparateMetricsGroupLabel(userID string) string {
 	return o.getOverridesForUser(userID).SeparateMetricsGroupLabel
@@ -716,6 +850,11 @@ func (o *Overrides) MetricRelabelConfigs(userID string) []*relabel.Config {
 	return o.getOverridesForUser(userID).MetricRelabelConfigs
 }
 
+// CreatedTimestampZeroIngestionEnabled returns whether to ingest a zero sample at the created timestamp of series in the ingester.
+func (o *Overrides) CreatedTimestampZeroIngestionEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).CreatedTimestampZeroIngestionEnabled
+}
+
 // NativeHistogramsIngestionEnabled returns whether to ingest native histograms in the ingester
 func (o *Overrides) NativeHistogramsIngestionEnabled(userID string) bool {
 	return o.getOverridesForUser(userID).NativeHistogramsIngestionEnabled
@@ -761,6 +900,18 @@ func (o *Overrides) MaxHAClusters(user string) int {
 	return o.getOverridesForUser(user).HAMaxClusters
 }
 
+// HASampleLevelDedupWindow returns the period after which the ingesters append the samples of a series received from a
+// non-elected HA replica if the series hasn't been received from the elected replica. 0 if the sample-level deduplication is disabled.
+func (o *Overrides) HASampleLevelDedupWindow(user string) time.Duration {
+	return time.Duration(o.getOverridesForUser(user).HASampleLevelDedupWindow)
+}
+
+// HASampleLevelDedupMaxSamplesPerSecond returns the maximum rate of samples received from the non-elected HA replicas
+// and forwarded to the ingesters for the sample-level deduplication. 0 if unlimited.
+func (o *Overrides) HASampleLevelDedupMaxSamplesPerSecond(user string) float64 {
+	return o.getOverridesForUser(user).HASampleLevelDedupMaxSamplesPerSecond
+}
+
 // S3SSEType returns the per-tenant S3 SSE type.
 func (o *Overrides) S3SSEType(user string) string {
 	return o.getOverridesForUser(user).S3SSEType
@@ -675,6 +675,54 @@ func TestUnmarshalMaxEstimatedChunksPerQuery(t *testing.T) {
 	}
 }
 
+func TestUnmarshalCostAttributionLabels(t *testing.T) {
+	testCases := map[string]bool{
+		"team":          true,
+		"team,env":      true,
+		"user":          false,
+		"team,reason":   false,
+		"invalid-label": false,
+	}
+
+	for value, shouldBeValid := range testCases {
+		t.Run(value, func(t *testing.T) {
+			limits := Limits{}
+			cfg := "cost_attribution_labels: " + value
+			err := yaml.Unmarshal([]byte(cfg), &limits)
+
+			if shouldBeValid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, "is not a valid cost attribution label")
+			}
+		})
+	}
+}
+
+func TestUnmarshalInfluxAndGraphiteSettings(t *testing.T) {
+	testCases := map[string]string{
+		"influx_field_label: field":                                     "",
+		"influx_field_label: __name__":                                  `"__name__" is not a valid label name`,
+		"influx_tag_label_mapping: {host: instance}":                    "",
+		"influx_tag_label_mapping: {host: in-stance}":                   `"in-stance", which is not a valid label name`,
+		`graphite_templates: ["servers.* .host.measurement* env=prod"]`: "",
+		`graphite_templates: ["host.cpu"]`:                              "invalid graphite_templates",
+	}
+
+	for cfg, expectedErr := range testCases {
+		t.Run(cfg, func(t *testing.T) {
+			limits := Limits{}
+			err := yaml.Unmarshal([]byte(cfg), &limits)
+
+			if expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, expectedErr)
+			}
+		})
+	}
+}
+
 type structExtension struct {
 	Foo int `yaml:"foo" json:"foo"`
 }